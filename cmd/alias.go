@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/config"
+	"github.com/nacos-group/nacos-cli/internal/terminal"
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+// aliasConfigPath returns the config file aliases are read from and saved to:
+// the --config file, or the profile's file
+func aliasConfigPath(configFile, profile string) (string, error) {
+	if configFile != "" {
+		return util.ResolvePath(configFile)
+	}
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+	return config.GetProfileConfigPath(profile)
+}
+
+// loadAliases returns the aliases configured in the file at path. A missing or
+// invalid file has none; loading the config proper reports the problem.
+func loadAliases(path string) map[string]string {
+	if _, err := os.Stat(path); err != nil {
+		return nil
+	}
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		return nil
+	}
+	return cfg.Aliases
+}
+
+// expandAliasArgs expands an alias used as the subcommand, e.g.
+// "nacos-cli --profile dev cl" runs "config-list --group skill_*" with the dev profile.
+// It runs before cobra parses the arguments, so --config and --profile are
+// picked out of the global flags that precede the subcommand.
+func expandAliasArgs(args []string) ([]string, error) {
+	var cfgFile, profile string
+	flags := rootCmd.PersistentFlags()
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			return args, nil
+		}
+		if !strings.HasPrefix(arg, "-") || arg == "-" {
+			path, err := aliasConfigPath(cfgFile, profile)
+			if err != nil {
+				return args, nil
+			}
+			expanded, err := terminal.ExpandAliases(args[i:], terminal.MergeAliases(loadAliases(path)))
+			if err != nil {
+				return nil, err
+			}
+			return append(args[:i:i], expanded...), nil
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+		if name == "" {
+			continue
+		}
+		flag := flags.Lookup(name)
+		if !strings.HasPrefix(arg, "--") {
+			// -c file, -c=file or -cfile
+			flag = flags.ShorthandLookup(name[:1])
+			if len(name) > 1 && !hasValue {
+				value, hasValue = name[1:], true
+			}
+		}
+		if flag == nil || flag.NoOptDefVal != "" {
+			continue // unknown or boolean flag: takes no separate value
+		}
+		if !hasValue && i+1 < len(args) {
+			i++
+			value = args[i]
+		}
+		switch flag.Name {
+		case "config":
+			cfgFile = value
+		case "profile":
+			profile = value
+		}
+	}
+	return args, nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
+	"github.com/spf13/cobra"
+)
+
+var (
+	applyConfigGroup           string
+	applyConfigWatch           bool
+	applyConfigInterval        time.Duration
+	applyConfigOverwriteRemote bool
+)
+
+var applyConfigCmd = &cobra.Command{
+	Use:   "config-apply <dir>",
+	Short: "Publish a directory of config files",
+	Long:  help.ConfigApply.FormatForCLI("nacos-cli"),
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if applyConfigInterval < 0 {
+			checkError(fmt.Errorf("--interval must not be negative"))
+		}
+		if applyConfigInterval > 0 && !applyConfigWatch {
+			checkError(fmt.Errorf("--interval needs --watch"))
+		}
+		if applyConfigOverwriteRemote && applyConfigInterval == 0 {
+			checkError(fmt.Errorf("--overwrite-remote needs --interval"))
+		}
+		dir := mustResolvePath(args[0])
+		if info, err := os.Stat(dir); err != nil {
+			checkError(err)
+		} else if !info.IsDir() {
+			checkError(fmt.Errorf("%s is not a directory", dir))
+		}
+		group := applyConfigGroup
+		if group == "" {
+			group = defaultGroup
+		}
+
+		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
+		applier := skillsync.NewConfigApplier(nacosClient, dir, group, nil)
+		if !applyConfigWatch {
+			checkError(applier.Apply())
+			return
+		}
+		applier.SetInterval(applyConfigInterval)
+		applier.SetOverwriteRemote(applyConfigOverwriteRemote)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Fprintln(stderr, "Press Ctrl+C to stop watching")
+		checkError(applier.Run(ctx))
+	},
+}
+
+func init() {
+	applyConfigCmd.Flags().StringVar(&applyConfigGroup, "group", "", "Group of the files directly in <dir> (default: defaults.group from the config file)")
+	applyConfigCmd.Flags().BoolVar(&applyConfigWatch, "watch", false, "Keep running and publish each file shortly after it changes")
+	applyConfigCmd.Flags().DurationVar(&applyConfigInterval, "interval", 0, "With --watch, also compare every file with Nacos this often, to find configs changed there")
+	applyConfigCmd.Flags().BoolVar(&applyConfigOverwriteRemote, "overwrite-remote", false, "With --interval, publish a file again when its config was changed in Nacos, instead of warning")
+	rootCmd.AddCommand(applyConfigCmd)
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigApply(t *testing.T) {
+	s := newNacosStub(t)
+	dir := t.TempDir()
+	if err := os.Mkdir(filepath.Join(dir, "prod"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range map[string]string{"app.yaml": "a: 1", "prod/db.properties": "url=db"} {
+		if err := os.WriteFile(filepath.Join(dir, filepath.FromSlash(name)), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if out := runCommand(t, s.Addr, "config-apply", dir, "--group", "dev"); !strings.Contains(out, "exit status 0") {
+		t.Fatalf("config-apply:\n%s", out)
+	}
+	if got, _ := s.Config("app.yaml", "dev"); got != "a: 1" {
+		t.Errorf("app.yaml (dev) = %q, want %q", got, "a: 1")
+	}
+	if got, _ := s.Config("db.properties", "prod"); got != "url=db" {
+		t.Errorf("db.properties (prod) = %q, want %q", got, "url=db")
+	}
+
+	for _, args := range [][]string{{"--interval", "1m"}, {"--watch", "--overwrite-remote"}} {
+		out := runCommand(t, s.Addr, append([]string{"config-apply", dir}, args...)...)
+		if !strings.Contains(out, "exit status 1") {
+			t.Errorf("config-apply %v:\n%s", args, out)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/spf13/cobra"
+)
+
+var configEffectiveOutput string
+
+var configEffectiveCmd = &cobra.Command{
+	Use:   "config-effective",
+	Short: "Show the settings in effect and where each one came from",
+	Long:  help.ConfigEffective.FormatForCLI("nacos-cli"),
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if configEffectiveOutput != "table" && configEffectiveOutput != "json" {
+			checkError(fmt.Errorf("invalid --output %q: use table or json", configEffectiveOutput))
+		}
+		if configEffectiveOutput == "json" {
+			data, err := json.MarshalIndent(settings.List(), "", "  ")
+			checkError(err)
+			fmt.Fprintln(stdout, string(data))
+			return
+		}
+		settings.Print(stdout)
+	},
+}
+
+func init() {
+	configEffectiveCmd.Flags().StringVar(&configEffectiveOutput, "output", "table", "Output format: table or json")
+	rootCmd.AddCommand(configEffectiveCmd)
+}
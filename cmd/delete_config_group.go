@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	groupDeleteDataID string
+	groupDeleteDryRun bool
+)
+
+var deleteConfigGroupCmd = &cobra.Command{
+	Use:   "config-group-delete <group>",
+	Short: "Delete all configs of a group, or those matching --data-id",
+	Long:  help.ConfigGroupDelete.FormatForCLI("nacos-cli"),
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		group := args[0]
+		// An empty group, e.g. from an unset variable, would match every group
+		if strings.TrimSpace(group) == "" {
+			checkError(fmt.Errorf("the group is empty"))
+		}
+		// A wildcard would turn the blur search on for the group and delete
+		// from every group it matches
+		if strings.Contains(group, "*") {
+			checkError(fmt.Errorf("the group must be a name, not a pattern: %s", group))
+		}
+
+		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
+		configs, err := nacosClient.ExpandConfigs(groupDeleteDataID, group)
+		checkError(err)
+		if len(configs) == 0 {
+			fmt.Fprintf(stdout, "No configurations in group %s match\n", group)
+			return
+		}
+
+		ns := nacosClient.Namespace
+		if ns == "" {
+			ns = "public"
+		}
+		fmt.Fprintf(stdout, "%d configs in group %s (namespace %s):\n", len(configs), group, ns)
+		for _, cfg := range configs {
+			fmt.Fprintf(stdout, "  - %s (%s)\n", cfg.DataID, cfg.GroupName)
+		}
+		if groupDeleteDryRun {
+			fmt.Fprintln(stdout, "Dry run: nothing was deleted")
+			return
+		}
+		ok, err := ui.Confirm(fmt.Sprintf("Delete these %d configs?", len(configs)), true)
+		checkError(err)
+		if !ok {
+			fmt.Fprintln(stdout, "Cancelled, nothing was deleted")
+			return
+		}
+
+		// One failure must not leave the rest of the group in place
+		var failed []string
+		for i, cfg := range configs {
+			if err := nacosClient.DeleteConfig(cfg.DataID, cfg.GroupName); err != nil {
+				fmt.Fprintf(stderr, "[%d/%d] Failed to delete %s: %v\n", i+1, len(configs), cfg.DataID, err)
+				failed = append(failed, cfg.DataID)
+				continue
+			}
+			fmt.Fprintf(stdout, "[%d/%d] Deleted %s\n", i+1, len(configs), cfg.DataID)
+		}
+		fmt.Fprintf(stdout, "Deleted: %d | Failed: %d\n", len(configs)-len(failed), len(failed))
+		if len(failed) > 0 {
+			fmt.Fprintf(stderr, "Failed configs: %s\n", strings.Join(failed, ", "))
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	deleteConfigGroupCmd.Flags().StringVar(&groupDeleteDataID, "data-id", "", "Only delete configs whose data ID matches (supports wildcard *, e.g. 'exp-*')")
+	deleteConfigGroupCmd.Flags().BoolVar(&groupDeleteDryRun, "dry-run", false, "List the configs that would be deleted without deleting them")
+	rootCmd.AddCommand(deleteConfigGroupCmd)
+}
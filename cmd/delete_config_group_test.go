@@ -0,0 +1,21 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConfigGroupDeleteEmptyGroup(t *testing.T) {
+	s := newNacosStub(t)
+	for _, group := range []string{"", " "} {
+		out := runCommand(t, s.Addr, "config-group-delete", group, "--yes")
+		if !strings.Contains(out, "exit status 1") || !strings.Contains(out, "the group is empty") {
+			t.Errorf("config-group-delete %q:\n%s", group, out)
+		}
+	}
+	for _, c := range stubConfigs {
+		if _, ok := s.Config(c.dataID, c.group); !ok {
+			t.Errorf("%s (%s) was deleted", c.dataID, c.group)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/editor"
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/lint"
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/nacos-group/nacos-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	editConfigCreate bool
+	editConfigNoLint bool
+	editConfigDryRun bool
+)
+
+var editConfigCmd = &cobra.Command{
+	Use:   "config-edit [dataId] [group]",
+	Short: "Edit a configuration in $EDITOR, review the diff and publish it",
+	Long:  help.ConfigEdit.FormatForCLI("nacos-cli"),
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		runEditConfig(args[0], configGroup(args), configEdit{
+			create:     editConfigCreate,
+			noValidate: editConfigNoLint,
+			dryRun:     editConfigDryRun,
+		})
+	},
+}
+
+// configEdit is how runEditConfig edits a config
+type configEdit struct {
+	create     bool // create the config if it does not exist
+	noValidate bool // publish content that does not parse as its type
+	dryRun     bool // print the diff without publishing
+}
+
+// runEditConfig opens the current content of a config in $EDITOR, checks
+// that the edit parses as the config's type, shows a diff and publishes the
+// edit after confirmation. The publish fails if the config was published by
+// someone else since it was fetched; the edit is then saved to a file.
+func runEditConfig(dataID, group string, opts configEdit) {
+	nacosClient := mustNewNacosClient()
+	var current, md5, declared string
+	config, err := nacosClient.GetConfigDetail(dataID, group)
+	switch {
+	case errors.Is(err, client.ErrConfigNotFound):
+		if !opts.create {
+			checkError(fmt.Errorf("%w; pass --create to create it", err))
+		}
+		fmt.Fprintf(stderr, "%s (%s) does not exist yet; it is created when published\n", dataID, group)
+	case err != nil:
+		checkError(err)
+	default:
+		current, md5, declared = config.Content, config.MD5, config.Type
+	}
+	typ := lint.DetectType(dataID, declared)
+
+	content := current
+	for {
+		edited, err := editor.Edit(dataID, []byte(content))
+		checkError(err)
+		content = string(edited)
+
+		if content == current {
+			fmt.Fprintln(stdout, "No changes, nothing to publish")
+			return
+		}
+		if strings.TrimSpace(content) == "" {
+			checkError(fmt.Errorf("config content is empty, nothing published"))
+		}
+		if opts.noValidate || printLintProblems(dataID, lint.Check(typ, content)) {
+			break
+		}
+		invalid := fmt.Errorf("content is not valid %s, nothing published (pass --no-validate to publish it anyway)", typ)
+		if ui.AssumeYes {
+			keepEdit(dataID, content, invalid)
+		}
+		again, err := ui.Confirm("Edit again?", false)
+		if err != nil || !again {
+			keepEdit(dataID, content, invalid)
+		}
+	}
+
+	fmt.Fprint(stdout, util.UnifiedDiff(dataID+" (remote)", dataID+" (edited)", current, content))
+	if opts.dryRun {
+		return
+	}
+
+	fmt.Fprintln(stdout)
+	ok, err := ui.Confirm("Publish these changes?", true)
+	if err != nil {
+		keepEdit(dataID, content, err)
+	}
+	if !ok {
+		fmt.Fprintln(stdout, "Aborted, nothing published")
+		return
+	}
+
+	fmt.Fprintf(stdout, "Publishing config: %s (%s)...\n", dataID, group)
+	err = nacosClient.PublishConfigCAS(dataID, group, content, md5)
+	if errors.Is(err, client.ErrConfigChanged) {
+		err = fmt.Errorf("%w; nothing published, edit the new content to apply your change", err)
+	}
+	if err != nil {
+		keepEdit(dataID, content, err)
+	}
+	fmt.Fprintln(stdout, "Configuration published successfully")
+}
+
+// keepEdit saves an edit that is not published to a file, so it is not
+// lost, and exits with err
+func keepEdit(dataID, content string, err error) {
+	if path, keepErr := editor.Keep(dataID, []byte(content)); keepErr == nil {
+		fmt.Fprintf(stderr, "Your edit is saved in %s\n", path)
+	}
+	checkError(err)
+}
+
+func init() {
+	editConfigCmd.Flags().BoolVar(&editConfigCreate, "create", false, "Start from empty content if the config does not exist")
+	editConfigCmd.Flags().BoolVar(&editConfigNoLint, "no-validate", false, "Publish yaml, json, properties or xml content that does not parse (see config-lint)")
+	editConfigCmd.Flags().BoolVar(&editConfigDryRun, "dry-run", false, "Show the diff of the edit without publishing")
+	rootCmd.AddCommand(editConfigCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"testing"
+)
+
+// keptEdit matches the file an edit that was not published is saved in
+var keptEdit = regexp.MustCompile(`\S*nacos-cli-edit-\w+`)
+
+func TestConfigEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the editor")
+	}
+	// The editor replaces the file with $EDITED
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	if err := os.WriteFile(script, []byte("#!/bin/sh\nprintf '%s' \"$EDITED\" > \"$1\"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", script)
+	t.Setenv("TMPDIR", t.TempDir())
+
+	tests := []struct {
+		golden    string
+		edited    string
+		args      []string
+		published string // content of the config afterwards
+	}{
+		{"config_edit", "server:\n  port: 9090\n", []string{"config-edit", "app.yaml", "DEFAULT_GROUP", "--yes"}, "server:\n  port: 9090\n"},
+		{"config_edit_unchanged", "server:\n  port: 8080\n", []string{"config-edit", "app.yaml", "DEFAULT_GROUP", "--yes"}, "server:\n  port: 8080\n"},
+		{"config_edit_invalid", "server:\n  port: 9090\n  port: 9091\n", []string{"config-edit", "app.yaml", "DEFAULT_GROUP", "--yes"}, "server:\n  port: 8080\n"},
+		{"config_edit_missing", "a: 1\n", []string{"config-edit", "new.yaml", "DEFAULT_GROUP", "--yes"}, ""},
+		{"config_edit_create", "a: 1\n", []string{"config-edit", "new.yaml", "DEFAULT_GROUP", "--create", "--yes"}, "a: 1\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			s := newNacosStub(t)
+			t.Setenv("EDITED", tt.edited)
+			out := runCommand(t, s.Addr, tt.args...)
+			checkGolden(t, tt.golden, keptEdit.ReplaceAllString(out, "KEPT"))
+			if got, _ := s.Config(tt.args[1], "DEFAULT_GROUP"); got != tt.published {
+				t.Errorf("%s = %q after config-edit, want %q", tt.args[1], got, tt.published)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/spf13/cobra"
+)
+
+// Exit codes of config-exists besides 0 (exists), 1 (error) and
+// exitAuthFailed, so scripts can branch on them
+const (
+	exitConfigNotFound = 4
+	exitMD5Mismatch    = 5
+)
+
+var existsConfigMD5 string
+
+var existsConfigCmd = &cobra.Command{
+	Use:   "config-exists [dataId] [group]",
+	Short: "Exit 0 if a configuration exists, 4 if it does not",
+	Long:  help.ConfigExists.FormatForCLI("nacos-cli"),
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dataID := args[0]
+		group := configGroup(args)
+
+		nacosClient := mustNewNacosClient()
+		content, err := nacosClient.GetConfig(dataID, group)
+		if errors.Is(err, client.ErrConfigNotFound) {
+			exit(exitConfigNotFound)
+		}
+		checkError(err)
+
+		if existsConfigMD5 != "" && !strings.EqualFold(client.CalculateMD5(content), existsConfigMD5) {
+			exit(exitMD5Mismatch)
+		}
+	},
+}
+
+func init() {
+	existsConfigCmd.Flags().StringVar(&existsConfigMD5, "md5", "", "Exit 0 only if the content has this MD5, else 5")
+	rootCmd.AddCommand(existsConfigCmd)
+}
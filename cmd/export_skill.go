@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var (
+	exportSkillOutput      string
+	exportSkillAll         bool
+	exportSkillVersion     string
+	exportSkillLabel       string
+	exportSkillConcurrency int
+)
+
+var exportSkillCmd = &cobra.Command{
+	Use:   "skill-export [skillName]",
+	Short: "Export a skill from Nacos to a local zip without installing it",
+	Long:  help.SkillExport.FormatForCLI("nacos-cli"),
+	Args:  cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		if exportSkillAll == (len(args) == 1) {
+			checkError(fmt.Errorf("specify either a skill name or --all"))
+		}
+		if exportSkillAll && (exportSkillVersion != "" || exportSkillLabel != "") {
+			checkError(fmt.Errorf("--version and --label cannot be used with --all"))
+		}
+		skillService := skill.NewSkillService(mustNewNacosClient())
+		skillService.SetProgress(ui.NewProgress)
+		stamp := time.Now().Format("20060102")
+
+		if exportSkillAll {
+			dir := exportSkillOutput
+			if dir == "" {
+				dir = "skills-" + stamp
+			}
+			names, err := skillService.AllSkillNames()
+			checkError(err)
+			fmt.Fprintln(stdout, i18n.T("Exporting %d skills to %s...", len(names), dir))
+			m, err := skillService.ExportSkills(names, dir, exportSkillConcurrency)
+			checkError(err)
+			for _, exported := range m.Skills {
+				fmt.Fprintln(stdout, "  "+i18n.T("%s -> %s (%d files, %d bytes)", exported.Name, exported.File, exported.Files, exported.Bytes))
+			}
+			for _, failure := range m.Failed {
+				fmt.Fprintln(stderr, i18n.T("Error: failed to export skill '%s': %s", failure.Name, failure.Error))
+			}
+			fmt.Fprintln(stdout, i18n.T("Exported: %d | Failed: %d | Manifest: %s", len(m.Skills), len(m.Failed), filepath.Join(dir, skill.ExportManifestFile)))
+			if len(m.Failed) > 0 {
+				exit(1)
+			}
+			return
+		}
+
+		name := args[0]
+		zipPath := exportSkillOutput
+		if zipPath == "" {
+			zipPath = fmt.Sprintf("%s-%s.zip", name, stamp)
+		}
+		fmt.Fprintln(stdout, i18n.T("Exporting skill: %s...", name))
+		archive, err := skillService.DownloadSkill(name, exportSkillVersion, exportSkillLabel)
+		checkError(err)
+		exported, err := archive.Export(zipPath)
+		checkError(err)
+		fmt.Fprintln(stdout, i18n.T("Skill exported successfully!"))
+		fmt.Fprintln(stdout, "  "+i18n.T("File: %s", zipPath))
+		if exported.Version != "" {
+			fmt.Fprintln(stdout, "  "+i18n.T("Version: %s", exported.Version))
+		}
+		fmt.Fprintln(stdout, "  "+i18n.T("Written: %d files, %d bytes", exported.Files, exported.Bytes))
+		fmt.Fprintln(stdout, "  "+i18n.T("SHA-256: %s", exported.SHA256))
+		fmt.Fprintln(stdout, "  "+i18n.T("Tip: Use 'skill-publish %s' to import it into another cluster.", zipPath))
+	},
+}
+
+func init() {
+	exportSkillCmd.Flags().StringVarP(&exportSkillOutput, "output", "o", "", "Zip file to write (default: <name>-YYYYMMDD.zip); with --all, the directory (default: skills-YYYYMMDD)")
+	exportSkillCmd.Flags().BoolVar(&exportSkillAll, "all", false, "Export every skill as <name>.zip into one directory, with a manifest.json")
+	exportSkillCmd.Flags().StringVar(&exportSkillVersion, "version", "", "Specific version to export (e.g. v1, v2)")
+	exportSkillCmd.Flags().StringVar(&exportSkillLabel, "label", "", "Route label to resolve version (e.g. latest, stable)")
+	exportSkillCmd.Flags().IntVar(&exportSkillConcurrency, "concurrency", skill.DefaultDownloadConcurrency, "With --all, how many skills to download at once")
+	rootCmd.AddCommand(exportSkillCmd)
+}
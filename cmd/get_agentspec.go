@@ -8,7 +8,6 @@ import (
 
 	"github.com/nacos-group/nacos-cli/internal/agentspec"
 	"github.com/nacos-group/nacos-cli/internal/help"
-	"github.com/nacos-group/nacos-cli/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -32,14 +31,12 @@ var getAgentSpecCmd = &cobra.Command{
 			checkError(err)
 			getAgentSpecOutput = filepath.Join(homeDir, ".agentspecs")
 		} else {
-			// Expand ~ to home directory
-			expanded, err := util.ExpandTilde(getAgentSpecOutput)
-			checkError(err)
-			getAgentSpecOutput = expanded
+			getAgentSpecOutput = mustResolvePath(getAgentSpecOutput)
 		}
 
 		// Create Nacos client
 		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
 
 		// Create agentspec service
 		agentSpecService := agentspec.NewAgentSpecService(nacosClient)
@@ -51,34 +48,34 @@ var getAgentSpecCmd = &cobra.Command{
 		// Process each agent spec
 		for i, specName := range specNames {
 			if len(specNames) > 1 {
-				fmt.Printf("\n[%d/%d] ", i+1, len(specNames))
+				fmt.Fprintf(stdout, "\n[%d/%d] ", i+1, len(specNames))
 			}
-			fmt.Printf("Fetching agent spec: %s...\n", specName)
+			fmt.Fprintf(stdout, "Fetching agent spec: %s...\n", specName)
 			err := agentSpecService.GetAgentSpec(specName, getAgentSpecOutput, getAgentSpecVersion, getAgentSpecLabel)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to download agent spec '%s': %v\n", specName, err)
+				fmt.Fprintf(stderr, "Error: failed to download agent spec '%s': %v\n", specName, err)
 				failCount++
 				failedSpecs = append(failedSpecs, specName)
 			} else {
 				specPath := filepath.Join(getAgentSpecOutput, specName)
-				fmt.Printf("Agent spec downloaded successfully!\n")
-				fmt.Printf("  Location: %s\n", specPath)
+				fmt.Fprintf(stdout, "Agent spec downloaded successfully!\n")
+				fmt.Fprintf(stdout, "  Location: %s\n", specPath)
 				successCount++
 			}
 		}
 
 		// Summary
 		if len(specNames) > 1 {
-			fmt.Printf("\n========== Summary ==========\n")
-			fmt.Printf("Total: %d | Success: %d | Failed: %d\n", len(specNames), successCount, failCount)
+			fmt.Fprintf(stdout, "\n========== Summary ==========\n")
+			fmt.Fprintf(stdout, "Total: %d | Success: %d | Failed: %d\n", len(specNames), successCount, failCount)
 			if failCount > 0 {
-				fmt.Printf("Failed agent specs: %s\n", strings.Join(failedSpecs, ", "))
+				fmt.Fprintf(stdout, "Failed agent specs: %s\n", strings.Join(failedSpecs, ", "))
 			}
 		}
 
 		// Exit with error if any spec failed
 		if failCount > 0 {
-			os.Exit(1)
+			exit(1)
 		}
 	},
 }
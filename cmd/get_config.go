@@ -1,43 +1,361 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 
+	"github.com/nacos-group/nacos-cli/internal/client"
 	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/highlight"
+	"github.com/nacos-group/nacos-cli/internal/query"
+	"github.com/nacos-group/nacos-cli/internal/ui"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+var (
+	getConfigBatch       bool
+	getConfigOutputDir   string
+	getConfigConcurrency int
+	getConfigStrict      bool
+	getConfigCompact     bool
+	getConfigOutputFile  string
+	getConfigExpand      bool
+	getConfigExpandLimit int
+	getConfigQuery       string
+	getConfigOutput      string
 )
 
 var getConfigCmd = &cobra.Command{
 	Use:   "config-get [dataId] [group]",
 	Short: "Get a specific configuration",
 	Long:  help.ConfigGet.FormatForCLI("nacos-cli"),
-	Args:  cobra.ExactArgs(2),
+	Args: func(cmd *cobra.Command, args []string) error {
+		if getConfigBatch {
+			return cobra.MinimumNArgs(1)(cmd, args)
+		}
+		return cobra.RangeArgs(1, 2)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		if getConfigOutputFile != "" {
+			getConfigOutputFile = mustResolvePath(getConfigOutputFile)
+		}
+		if getConfigOutputDir != "" {
+			getConfigOutputDir = mustResolvePath(getConfigOutputDir)
+		}
+		if getConfigBatch && getConfigExpand {
+			checkError(fmt.Errorf("--batch and --expand cannot be used together"))
+		}
+		if (getConfigBatch || getConfigExpand) && getConfigOutputFile != "" {
+			checkError(fmt.Errorf("--output-file cannot be used with --batch or --expand (use --output-dir)"))
+		}
+		if getConfigOutput != "text" && getConfigOutput != "json" {
+			checkError(fmt.Errorf("invalid --output %q: use text or json", getConfigOutput))
+		}
+		extract := getConfigQuery != "" || getConfigOutput == "json"
+		if extract && (getConfigBatch || getConfigExpand || getConfigOutputFile != "") {
+			checkError(fmt.Errorf("--query and --output json cannot be used with --batch, --expand or --output-file"))
+		}
+		if getConfigBatch {
+			runBatchGetConfig(args)
+			return
+		}
+		if getConfigExpand {
+			runExpandGetConfig(args[0], configGroup(args))
+			return
+		}
+
 		dataID := args[0]
-		group := args[1]
+		group := configGroup(args)
 
 		// Create Nacos client
 		nacosClient := mustNewNacosClient()
 
-		// Get config
-		fmt.Printf("Fetching config: %s (%s)...\n\n", dataID, group)
-		content, err := nacosClient.GetConfig(dataID, group)
+		// Get config. Only the content goes to stdout, so it can be piped
+		// into jq or a file; progress and the header go to stderr.
+		fmt.Fprintf(stderr, "Fetching config: %s (%s)...\n\n", dataID, group)
+		config, err := nacosClient.GetConfigDetail(dataID, group)
 		checkError(err)
 
+		content := config.Content
 		if content == "" {
-			fmt.Println("Configuration not found")
+			fmt.Fprintln(stderr, "Configuration not found")
+			if extract {
+				exit(1)
+			}
 			return
 		}
 
+		if extract {
+			printConfigQuery(dataID, config, getConfigQuery)
+			return
+		}
+
+		if getConfigOutputFile != "" {
+			checkError(os.WriteFile(getConfigOutputFile, []byte(content), 0644))
+			fmt.Fprintf(stderr, "Saved to %s (%d bytes)\n", getConfigOutputFile, len(content))
+			return
+		}
+
+		// Colors, and a final newline before the shell prompt, are for display
+		// only; piped output keeps the original bytes
+		f, isFile := stdout.(*os.File)
+		if isFile && highlight.Enabled(f) {
+			content = highlight.Format(content, highlight.DetectType(dataID, config.Type, content), getConfigCompact)
+		}
+		if isFile && term.IsTerminal(int(f.Fd())) && !strings.HasSuffix(content, "\n") {
+			content += "\n"
+		}
+
 		// Display content
-		fmt.Println("═══════════════════════════════════════")
-		fmt.Printf("Data ID: %s\n", dataID)
-		fmt.Printf("Group: %s\n", group)
-		fmt.Println("═══════════════════════════════════════")
-		fmt.Println(content)
+		fmt.Fprintln(stderr, "═══════════════════════════════════════")
+		fmt.Fprintf(stderr, "Data ID: %s\n", dataID)
+		fmt.Fprintf(stderr, "Group: %s\n", group)
+		fmt.Fprintln(stderr, "═══════════════════════════════════════")
+		fmt.Fprint(stdout, content)
 	},
 }
 
+// printConfigQuery prints the value at path (the whole content if path is
+// empty) of a YAML or JSON config: with --output json as JSON, else scalars
+// bare and maps and lists in the format of the config. A path that is not
+// in the config is an error.
+func printConfigQuery(dataID string, config *client.Config, path string) {
+	if path == "" {
+		path = "."
+	}
+	typ := highlight.DetectType(dataID, config.Type, config.Content)
+	doc, err := query.Decode(config.Content, typ)
+	checkError(err)
+	value, err := query.Lookup(doc, path)
+	checkError(err)
+	var out string
+	if getConfigOutput == "json" {
+		out, err = query.JSON(value)
+	} else {
+		out, err = query.Text(value, typ)
+	}
+	checkError(err)
+	fmt.Fprintln(stdout, out)
+}
+
+// configRef identifies a config by dataId and group
+type configRef struct {
+	DataID string
+	Group  string
+}
+
+func (r configRef) String() string {
+	return r.DataID + ":" + r.Group
+}
+
+// batchGetResult holds the outcome of fetching one config in batch mode
+type batchGetResult struct {
+	ref     configRef
+	content string
+	err     error
+}
+
+// runBatchGetConfig fetches several configs with one client and a bounded worker pool.
+// Results go to stdout as a JSON map ("dataId:group" -> content) or to --output-dir;
+// the summary is written to stderr so stdout stays machine-readable.
+func runBatchGetConfig(args []string) {
+	refs, err := parseBatchRefs(args)
+	checkError(err)
+	if len(refs) == 0 {
+		checkError(fmt.Errorf("no configs specified for --batch"))
+	}
+
+	if getConfigOutputDir != "" {
+		checkError(os.MkdirAll(getConfigOutputDir, 0755))
+	}
+
+	nacosClient := mustNewNacosClient()
+	mustLogin(nacosClient)
+	reportConfigs(fetchConfigsConcurrently(nacosClient, refs, getConfigConcurrency), false)
+}
+
+// runExpandGetConfig fetches every config whose dataId and group match the
+// wildcard patterns. More than --expand-limit matches need a confirmation, so
+// a pattern such as '*' does not dump a whole namespace by accident.
+func runExpandGetConfig(dataIDPattern, groupPattern string) {
+	nacosClient := mustNewNacosClient()
+	mustLogin(nacosClient)
+	fmt.Fprintf(stderr, "Expanding %s (%s)...\n", dataIDPattern, groupPattern)
+	configs, err := nacosClient.ExpandConfigs(dataIDPattern, groupPattern)
+	checkError(err)
+	if len(configs) == 0 {
+		checkError(fmt.Errorf("no configurations match %s (%s)", dataIDPattern, groupPattern))
+	}
+	if len(configs) > getConfigExpandLimit {
+		fmt.Fprintf(stderr, "Warning: %d configurations match, more than --expand-limit %d\n", len(configs), getConfigExpandLimit)
+		ok, err := ui.Confirm(fmt.Sprintf("Fetch all %d of them?", len(configs)), true)
+		checkError(err)
+		if !ok {
+			checkError(fmt.Errorf("cancelled; narrow the patterns or raise --expand-limit"))
+		}
+	}
+
+	if getConfigOutputDir != "" {
+		checkError(os.MkdirAll(getConfigOutputDir, 0755))
+	}
+	refs := make([]configRef, len(configs))
+	for i, cfg := range configs {
+		refs[i] = configRef{DataID: cfg.DataID, Group: cfg.GroupName}
+	}
+	reportConfigs(fetchConfigsConcurrently(nacosClient, refs, getConfigConcurrency), true)
+}
+
+// reportConfigs writes the fetched configs to --output-dir or stdout and a
+// summary to stderr, and exits with 1 if any failed. On stdout the configs are
+// a JSON map, or with delimited, each config after a delimiter line (see
+// printDelimited).
+func reportConfigs(results []batchGetResult, delimited bool) {
+	var notFound, failed []batchGetResult
+	found := make(map[string]string)
+	written := make(map[string]configRef) // --output-dir file -> config saved there
+	for _, r := range results {
+		switch {
+		case errors.Is(r.err, client.ErrConfigNotFound) || (r.err == nil && r.content == ""):
+			notFound = append(notFound, r)
+		case r.err != nil:
+			failed = append(failed, r)
+		case getConfigOutputDir != "":
+			path := filepath.Join(getConfigOutputDir, r.ref.Group+"__"+r.ref.DataID)
+			// group__dataId is ambiguous when either contains "__", e.g.
+			// a__b:c and a:b__c; never let one config overwrite another
+			if other, ok := written[path]; ok {
+				r.err = fmt.Errorf("both %s and %s would be saved as %s; fetch one of them separately", other, r.ref, path)
+				failed = append(failed, r)
+				continue
+			}
+			written[path] = r.ref
+			if err := os.WriteFile(path, []byte(r.content), 0644); err != nil {
+				r.err = fmt.Errorf("write %s: %w", path, err)
+				failed = append(failed, r)
+				continue
+			}
+			found[r.ref.String()] = path
+		default:
+			if delimited {
+				printDelimited(r.ref, r.content, len(found) == 0)
+			}
+			found[r.ref.String()] = r.content
+		}
+	}
+
+	if getConfigOutputDir == "" && !delimited {
+		out, err := json.MarshalIndent(found, "", "  ")
+		checkError(err)
+		fmt.Fprintln(stdout, string(out))
+	}
+
+	fmt.Fprintf(stderr, "Fetched: %d | Not found: %d | Failed: %d\n", len(found), len(notFound), len(failed))
+	for _, r := range notFound {
+		fmt.Fprintf(stderr, "  not found: %s\n", r.ref)
+	}
+	for _, r := range failed {
+		fmt.Fprintf(stderr, "  failed: %s: %v\n", r.ref, r.err)
+	}
+
+	if len(failed) > 0 || (getConfigStrict && len(notFound) > 0) {
+		exit(1)
+	}
+}
+
+// printDelimited writes a config to stdout after a "==> dataId:group <==" line,
+// like head and tail print between files, and a blank line before the
+// delimiter of every config but the first. The content ends with a newline, so
+// every delimiter starts a line and the output can be split again.
+func printDelimited(ref configRef, content string, first bool) {
+	if !first {
+		fmt.Fprintln(stdout)
+	}
+	fmt.Fprintf(stdout, "==> %s <==\n", ref)
+	fmt.Fprint(stdout, content)
+	if !strings.HasSuffix(content, "\n") {
+		fmt.Fprintln(stdout)
+	}
+}
+
+// fetchConfigsConcurrently fetches refs with at most concurrency requests in flight.
+// Results are returned in the same order as refs.
+func fetchConfigsConcurrently(nacosClient *client.NacosClient, refs []configRef, concurrency int) []batchGetResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]batchGetResult, len(refs))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, ref configRef) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			content, err := nacosClient.GetConfig(ref.DataID, ref.Group)
+			results[i] = batchGetResult{ref: ref, content: content, err: err}
+		}(i, ref)
+	}
+	wg.Wait()
+	return results
+}
+
+// parseBatchRefs parses "dataId:group" arguments; a single "-" reads them from stdin,
+// one per line (blank lines and # comments are ignored).
+func parseBatchRefs(args []string) ([]configRef, error) {
+	specs := args
+	if len(args) == 1 && args[0] == "-" {
+		specs = nil
+		scanner := bufio.NewScanner(stdin)
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line == "" || strings.HasPrefix(line, "#") {
+				continue
+			}
+			specs = append(specs, strings.Fields(line)...)
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("read stdin: %w", err)
+		}
+	}
+
+	seen := make(map[configRef]bool)
+	var refs []configRef
+	for _, spec := range specs {
+		idx := strings.LastIndex(spec, ":")
+		if idx <= 0 || idx == len(spec)-1 {
+			return nil, fmt.Errorf("invalid batch entry %q (expected dataId:group)", spec)
+		}
+		ref := configRef{DataID: spec[:idx], Group: spec[idx+1:]}
+		if strings.ContainsAny(ref.DataID+ref.Group, `/\`) {
+			return nil, fmt.Errorf("invalid batch entry %q (path separators are not allowed)", spec)
+		}
+		if seen[ref] {
+			continue
+		}
+		seen[ref] = true
+		refs = append(refs, ref)
+	}
+	return refs, nil
+}
+
 func init() {
+	getConfigCmd.Flags().BoolVar(&getConfigBatch, "batch", false, "Fetch several configs given as dataId:group arguments ('-' reads them from stdin)")
+	getConfigCmd.Flags().StringVar(&getConfigOutputDir, "output-dir", "", "With --batch or --expand, write each config to <dir>/<group>__<dataId> instead of stdout")
+	getConfigCmd.Flags().IntVar(&getConfigConcurrency, "concurrency", 4, "With --batch or --expand, maximum number of concurrent requests")
+	getConfigCmd.Flags().BoolVar(&getConfigStrict, "strict", false, "With --batch or --expand, exit non-zero if any config is not found")
+	getConfigCmd.Flags().BoolVar(&getConfigCompact, "compact", false, "Show JSON content as stored instead of pretty-printed")
+	getConfigCmd.Flags().StringVar(&getConfigOutputFile, "output-file", "", "Write the config content to a file instead of stdout")
+	getConfigCmd.Flags().BoolVar(&getConfigExpand, "expand", false, "Treat * in dataId and group as wildcards and fetch every matching config, each after a '==> dataId:group <==' line")
+	getConfigCmd.Flags().IntVar(&getConfigExpandLimit, "expand-limit", 20, "With --expand, ask before fetching more configs than this (--yes skips the question)")
+	getConfigCmd.Flags().StringVar(&getConfigQuery, "query", "", "Print only the value at this path of YAML or JSON content, e.g. .database.host or .servers[0]")
+	getConfigCmd.Flags().StringVar(&getConfigOutput, "output", "text", "Output format: text or json (json prints the content, or the --query value, as JSON)")
 	rootCmd.AddCommand(getConfigCmd)
 }
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestConfigGet(t *testing.T) {
+	s := newNacosStub(t)
+	tests := []struct {
+		golden string
+		args   []string
+	}{
+		{"config_get", []string{"config-get", "app.yaml", "DEFAULT_GROUP"}},
+		{"config_get_not_found", []string{"config-get", "missing.yaml", "DEFAULT_GROUP"}},
+		{"config_get_no_group", []string{"config-get", "app.yaml"}},
+		{"config_get_batch", []string{"config-get", "--batch", "app.yaml:DEFAULT_GROUP", "feature-flags.json:team", "missing.yaml:DEFAULT_GROUP"}},
+		{"config_get_batch_strict", []string{"config-get", "--batch", "--strict", "app.yaml:DEFAULT_GROUP", "missing.yaml:DEFAULT_GROUP"}},
+		{"config_get_expand", []string{"config-get", "--expand", "*.yaml", "DEFAULT_GROUP"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			checkGolden(t, tt.golden, runCommand(t, s.Addr, tt.args...))
+		})
+	}
+
+	t.Run("config_get_batch_stdin", func(t *testing.T) {
+		stdin = strings.NewReader("# refs to fetch\napp.yaml:DEFAULT_GROUP\n\nfeature-flags.json:team missing.yaml:DEFAULT_GROUP\n")
+		checkGolden(t, "config_get_batch_stdin", runCommand(t, s.Addr, "config-get", "--batch", "-"))
+	})
+
+	t.Run("config_get_server_error", func(t *testing.T) {
+		s := newNacosStub(t)
+		s.Fail("/nacos/v3/client/cs/config", http.StatusInternalServerError)
+		checkGolden(t, "config_get_server_error", runCommand(t, s.Addr, "config-get", "app.yaml", "DEFAULT_GROUP"))
+	})
+}
+
+func TestConfigGetQuery(t *testing.T) {
+	s := newNacosStub(t)
+	tests := []struct {
+		golden string
+		args   []string
+	}{
+		{"config_get_query", []string{"config-get", "app.yaml", "DEFAULT_GROUP", "--query", ".server.port"}},
+		{"config_get_query_json", []string{"config-get", "app.yaml", "DEFAULT_GROUP", "--query", ".server", "--output", "json"}},
+		{"config_get_query_missing", []string{"config-get", "feature-flags.json", "team", "--query", ".beta.rollout"}},
+		{"config_get_query_properties", []string{"config-get", "db.properties", "DEFAULT_GROUP", "--query", ".url"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			checkGolden(t, tt.golden, runCommand(t, s.Addr, tt.args...))
+		})
+	}
+}
@@ -5,16 +5,26 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/i18n"
 	"github.com/nacos-group/nacos-cli/internal/skill"
+	"github.com/nacos-group/nacos-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	getSkillOutput  string
-	getSkillVersion string
-	getSkillLabel   string
+	getSkillOutput       string
+	getSkillVersion      string
+	getSkillLabel        string
+	getSkillForce        bool
+	getSkillPreserveExec bool
+	getSkillConcurrency  int
+	getSkillAllowPartial bool
+	getSkillRetries      int
+	getSkillRetryDelay   time.Duration
+	getSkillSkipUniform  bool
 )
 
 var getSkillCmd = &cobra.Command{
@@ -25,72 +35,126 @@ var getSkillCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		skillNames := args
 
-		// Default output directory
-		if getSkillOutput == "" {
-			homeDir, err := os.UserHomeDir()
-			checkError(err)
-			getSkillOutput = filepath.Join(homeDir, ".skills")
-		} else {
-			// Expand ~ to home directory
-			if strings.HasPrefix(getSkillOutput, "~/") {
-				homeDir, err := os.UserHomeDir()
-				checkError(err)
-				getSkillOutput = filepath.Join(homeDir, getSkillOutput[2:])
-			} else if getSkillOutput == "~" {
-				homeDir, err := os.UserHomeDir()
-				checkError(err)
-				getSkillOutput = homeDir
-			}
-		}
+		getSkillOutput = mustResolveSkillsDir(getSkillOutput)
 
 		// Create Nacos client
 		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
 
 		// Create skill service
 		skillService := skill.NewSkillService(nacosClient)
+		skillService.SetProgress(ui.NewProgress)
+		skillService.SetUniformCheck(skill.UniformCheck{Retries: getSkillRetries, Delay: getSkillRetryDelay, Skip: getSkillSkipUniform})
 
 		// Track results
-		var successCount, failCount int
+		var successCount, skipCount, failCount int
 		var failedSkills []string
 
-		// Process each skill
-		for i, skillName := range skillNames {
+		// Download concurrently, then confirm and extract in order
+		if len(skillNames) == 1 {
+			fmt.Fprintln(stdout, i18n.T("Fetching skill: %s...", skillNames[0]))
+		} else {
+			fmt.Fprintln(stdout, i18n.T("Fetching %d skills...", len(skillNames)))
+		}
+		downloads := skillService.DownloadSkills(skillNames, getSkillVersion, getSkillLabel, getSkillConcurrency)
+		for i, download := range downloads {
+			skillName, archive, err := download.Name, download.Archive, download.Err
 			if len(skillNames) > 1 {
-				fmt.Printf("\n[%d/%d] ", i+1, len(skillNames))
+				fmt.Fprintf(stdout, "\n[%d/%d] %s\n", i+1, len(skillNames), skillName)
+			}
+			proceed := false
+			if err == nil {
+				archive.PreserveExec = getSkillPreserveExec
+				archive.KeepUnchanged = !getSkillForce
+				archive.AllowPartial = getSkillAllowPartial
+				proceed, err = confirmSkillOverwrite(archive, getSkillOutput)
 			}
-			fmt.Printf("Fetching skill: %s...\n", skillName)
-			err := skillService.GetSkill(skillName, getSkillOutput, getSkillVersion, getSkillLabel)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: failed to download skill '%s': %v\n", skillName, err)
+			var result skill.ExtractResult
+			if err == nil && proceed {
+				result, err = archive.Extract(getSkillOutput)
+			}
+			switch {
+			case err != nil:
+				fmt.Fprintln(stderr, i18n.T("Error: failed to download skill '%s': %v", skillName, err))
 				failCount++
 				failedSkills = append(failedSkills, skillName)
-			} else {
+			case !proceed:
+				fmt.Fprintln(stdout, i18n.T("Skipped, nothing written"))
+				skipCount++
+			default:
 				skillPath := filepath.Join(getSkillOutput, skillName)
-				fmt.Printf("Skill downloaded successfully!\n")
-				fmt.Printf("  Location: %s\n", skillPath)
+				fmt.Fprintln(stdout, i18n.T("Skill downloaded successfully!"))
+				fmt.Fprintln(stdout, "  "+i18n.T("Location: %s", skillPath))
+				fmt.Fprintln(stdout, "  "+i18n.T("Written: %d files, %d bytes%s", result.Files, result.Bytes, unchangedNote(result)))
+				for _, s := range result.Skipped {
+					fmt.Fprintln(stdout, "  "+i18n.T("Skipped: %s (%s)", s.Name, s.Reason))
+				}
 				successCount++
 			}
 		}
 
 		// Summary
 		if len(skillNames) > 1 {
-			fmt.Printf("\n========== Summary ==========\n")
-			fmt.Printf("Total: %d | Success: %d | Failed: %d\n", len(skillNames), successCount, failCount)
+			fmt.Fprintln(stdout, "\n"+i18n.T("========== Summary =========="))
+			fmt.Fprintln(stdout, i18n.T("Total: %d | Success: %d | Skipped: %d | Failed: %d", len(skillNames), successCount, skipCount, failCount))
 			if failCount > 0 {
-				fmt.Printf("Failed skills: %s\n", strings.Join(failedSkills, ", "))
+				fmt.Fprintln(stdout, i18n.T("Failed skills: %s", strings.Join(failedSkills, ", ")))
 			}
 		}
 
 		// Exit with error if any skill failed
 		if failCount > 0 {
-			os.Exit(1)
+			exit(1)
 		}
 	},
 }
 
+// unchangedNote returns e.g. " (12 unchanged)" when files were kept from the
+// installed version, or ""
+func unchangedNote(result skill.ExtractResult) string {
+	if result.Unchanged == 0 {
+		return ""
+	}
+	return " " + i18n.T("(%d unchanged)", result.Unchanged)
+}
+
+// confirmSkillOverwrite reports how many files an existing skill directory would
+// lose and asks before continuing, unless --force or --yes is set
+func confirmSkillOverwrite(archive *skill.SkillArchive, outputDir string) (bool, error) {
+	skillPath := filepath.Join(outputDir, archive.Name)
+	if _, err := os.Stat(skillPath); os.IsNotExist(err) || getSkillForce {
+		return true, nil
+	}
+	existing, err := archive.Existing(outputDir)
+	if err != nil {
+		return false, err
+	}
+	stale, err := archive.Stale(outputDir)
+	if err != nil {
+		return false, err
+	}
+	fmt.Fprint(stdout, i18n.T("%s already exists: %d of %d files will be replaced", skillPath, len(existing), archive.FileCount()))
+	if len(stale) > 0 {
+		fmt.Fprint(stdout, i18n.T(", %d files not in the skill will be removed", len(stale)))
+	}
+	fmt.Fprintln(stdout)
+	ok, err := ui.Confirm(i18n.T("Overwrite?"), true)
+	if err != nil {
+		return false, fmt.Errorf("%s already exists, use --force to overwrite (%w)", skillPath, err)
+	}
+	return ok, nil
+}
+
 func init() {
-	getSkillCmd.Flags().StringVarP(&getSkillOutput, "output", "o", "", "Output directory (default: ~/.skills)")
+	getSkillCmd.Flags().StringVarP(&getSkillOutput, "output", "o", "", "Output directory (default: NACOS_SKILLS_DIR, else skillsDir from the config file, else ~/.skills)")
 	getSkillCmd.Flags().StringVar(&getSkillVersion, "version", "", "Specific version to download (e.g. v1, v2)")
 	getSkillCmd.Flags().StringVar(&getSkillLabel, "label", "", "Route label to resolve version (e.g. latest, stable)")
+	getSkillCmd.Flags().BoolVar(&getSkillForce, "force", false, "Overwrite an existing skill directory without asking, rewriting unchanged files too")
+	getSkillCmd.Flags().IntVar(&getSkillConcurrency, "concurrency", skill.DefaultDownloadConcurrency, "How many skills to download at once")
+	getSkillCmd.Flags().BoolVar(&getSkillAllowPartial, "allow-partial", false, "Install a skill even if some of its files had to be skipped, listing them")
+	getSkillCmd.Flags().IntVar(&getSkillRetries, "uniform-retries", skill.DefaultUniformCheck.Retries, "How often to re-read a skill published via config whose files disagree on the upload they belong to")
+	getSkillCmd.Flags().DurationVar(&getSkillRetryDelay, "uniform-retry-delay", skill.DefaultUniformCheck.Delay, "Wait before the first re-read, doubled for each next one")
+	getSkillCmd.Flags().BoolVar(&getSkillSkipUniform, "skip-uniform-check", false, "Install a skill published via config even if its files disagree on the upload they belong to")
+	getSkillCmd.Flags().BoolVar(&getSkillPreserveExec, "preserve-exec", true, "Make scripts executable: files uploaded as executable, with a shebang line, or .sh/.py under scripts/")
 	rootCmd.AddCommand(getSkillCmd)
 }
@@ -1,20 +1,68 @@
 package cmd
 
 import (
+	"io"
+	"os"
+
+	"github.com/chzyer/readline"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
 	"github.com/nacos-group/nacos-cli/internal/terminal"
 	"github.com/spf13/cobra"
 )
 
+var (
+	interactiveScript          string
+	interactiveContinueOnError bool
+)
+
 var interactiveCmd = &cobra.Command{
 	Use:   "interactive",
 	Short: "Start interactive terminal mode",
-	Long:  `Start an interactive terminal for managing Nacos configurations and skills`,
+	Long: `Start an interactive terminal for managing Nacos configurations and skills.
+
+With --script, or when commands are piped on stdin, terminal commands are run
+one per line instead. Each command is echoed before its output; blank lines and
+lines starting with # are skipped. Execution stops at the first failed command
+(exit code 1) unless --continue-on-error is set.
+
+Examples:
+  nacos-cli interactive --script setup.nacos
+  nacos-cli interactive --script setup.nacos --continue-on-error
+  cat setup.nacos | nacos-cli interactive`,
 	Run: func(cmd *cobra.Command, args []string) {
-		// Create Nacos client
+		scriptMode := interactiveScript != "" || !readline.IsTerminal(int(os.Stdin.Fd()))
+
+		// Create Nacos client; a script cannot log in again, so login failures are
+		// fatal there, while the terminal logs in at startup and can retry
 		nacosClient := mustNewNacosClient()
+		if scriptMode {
+			mustLogin(nacosClient)
+		}
 
 		// Create and start terminal
 		term := terminal.NewTerminal(nacosClient)
+		term.SetAliases(loadAliases(aliasFile), aliasFile)
+		term.SetDefaultGroup(defaultGroup)
+		term.SetMaxConfigSize(maxConfigSize(defaultMaxSize))
+		term.SetPollTimeout(pollTimeout)
+		term.SetSyncHooks(skillsync.Hooks{OnChange: onChangeHook, OnError: onErrorHook})
+		term.SetSyncStatusFile(syncStatusFile())
+
+		if scriptMode {
+			var script io.Reader = os.Stdin
+			name := "stdin"
+			if interactiveScript != "" && interactiveScript != "-" {
+				f, err := os.Open(interactiveScript)
+				checkError(err)
+				defer f.Close()
+				script, name = f, interactiveScript
+			}
+			checkError(term.RunScript(script, name, interactiveContinueOnError))
+			return
+		}
+
+		term.SetHistoryFile(historyFile)
+		term.SetSkillsDir(mustResolveSkillsDir(""))
 		if err := term.Start(); err != nil {
 			checkError(err)
 		}
@@ -22,5 +70,7 @@ var interactiveCmd = &cobra.Command{
 }
 
 func init() {
+	interactiveCmd.Flags().StringVar(&interactiveScript, "script", "", "Run terminal commands from a file (- for stdin)")
+	interactiveCmd.Flags().BoolVar(&interactiveContinueOnError, "continue-on-error", false, "Keep running the script after a command fails")
 	rootCmd.AddCommand(interactiveCmd)
 }
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/lint"
+	"github.com/spf13/cobra"
+)
+
+var (
+	lintConfigFile string
+	lintConfigType string
+)
+
+var lintConfigCmd = &cobra.Command{
+	Use:   "config-lint [-f file] [--type yaml|json|properties|xml]",
+	Short: "Check that config content parses as its type",
+	Long:  help.ConfigLint.FormatForCLI("nacos-cli"),
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		name := "stdin"
+		var data []byte
+		var err error
+		if lintConfigFile != "" {
+			name = lintConfigFile
+			path := mustResolvePath(lintConfigFile)
+			data, err = os.ReadFile(path)
+			if err != nil {
+				checkError(fmt.Errorf("read file %s: %w", path, err))
+			}
+		} else {
+			data, err = io.ReadAll(stdin)
+			if err != nil {
+				checkError(fmt.Errorf("read stdin: %w", err))
+			}
+		}
+
+		typ := configLintType(lintConfigFile, lintConfigType)
+		if typ == "" {
+			checkError(fmt.Errorf("cannot tell the type of %s from its name; pass --type %s", name, lintConfigTypes))
+		}
+		if !printLintProblems(name, lint.Check(typ, string(data))) {
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "%s: valid %s\n", name, typ)
+	},
+}
+
+// lintConfigTypes is the usage of --type
+const lintConfigTypes = "yaml|json|properties|xml"
+
+// configLintType returns the type content of name is checked as: --type if
+// given, else the extension of name; "" if neither names a type lint checks
+func configLintType(name, flagType string) string {
+	if flagType != "" {
+		typ, err := lint.ParseType(flagType)
+		checkError(err)
+		return typ
+	}
+	return lint.DetectType(name, "")
+}
+
+// printLintProblems prints the problems found in the content of name as
+// name:line:column: message, and reports whether there were none
+func printLintProblems(name string, problems []lint.Problem) bool {
+	for _, p := range problems {
+		fmt.Fprintln(stderr, p.In(name))
+	}
+	return len(problems) == 0
+}
+
+func init() {
+	lintConfigCmd.Flags().StringVarP(&lintConfigFile, "file", "f", "", "Config file to check (default: read from stdin)")
+	lintConfigCmd.Flags().StringVar(&lintConfigType, "type", "", "Type to check the content as: "+lintConfigTypes+" (default: from the file extension)")
+	rootCmd.AddCommand(lintConfigCmd)
+}
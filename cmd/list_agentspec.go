@@ -33,15 +33,16 @@ var listAgentSpecCmd = &cobra.Command{
 
 		// Display results
 		if len(specs) == 0 {
-			fmt.Println("No agent specs found")
+			fmt.Fprintln(stdout, "No agent specs found")
+			printNamespaceHint(nacosClient, nil)
 			return
 		}
 
 		asciiMode := os.Getenv("NO_UNICODE_OUTPUT") != ""
 		separator := util.SeparatorLine(79, asciiMode)
 
-		fmt.Printf("AgentSpec List (Total: %d)\n", totalCount)
-		fmt.Println(separator)
+		fmt.Fprintf(stdout, "AgentSpec List (Total: %d)\n", totalCount)
+		fmt.Fprintln(stdout, separator)
 		for i, spec := range specs {
 			enableStr := "enabled"
 			if !spec.Enable {
@@ -49,9 +50,9 @@ var listAgentSpecCmd = &cobra.Command{
 			}
 			if spec.Description != nil && *spec.Description != "" {
 				desc := truncateDesc(*spec.Description, defaultDescLimit)
-				fmt.Printf("%3d. %s - %s [%s, online:%d]\n", i+1, spec.Name, desc, enableStr, spec.OnlineCnt)
+				fmt.Fprintf(stdout, "%3d. %s - %s [%s, online:%d]\n", i+1, spec.Name, desc, enableStr, spec.OnlineCnt)
 			} else {
-				fmt.Printf("%3d. %s [%s, online:%d]\n", i+1, spec.Name, enableStr, spec.OnlineCnt)
+				fmt.Fprintf(stdout, "%3d. %s [%s, online:%d]\n", i+1, spec.Name, enableStr, spec.OnlineCnt)
 			}
 		}
 	},
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -12,6 +13,7 @@ var (
 	configListSize   int
 	configListDataID string
 	configListGroup  string
+	configListOutput string
 )
 
 var listConfigCmd = &cobra.Command{
@@ -19,6 +21,10 @@ var listConfigCmd = &cobra.Command{
 	Short: "List all configurations",
 	Long:  help.ConfigList.FormatForCLI("nacos-cli"),
 	Run: func(cmd *cobra.Command, args []string) {
+		if configListOutput != "table" && configListOutput != "csv" {
+			checkError(fmt.Errorf("invalid --output %q: use table or csv", configListOutput))
+		}
+
 		// Create Nacos client
 		nacosClient := mustNewNacosClient()
 
@@ -26,16 +32,34 @@ var listConfigCmd = &cobra.Command{
 		configs, err := nacosClient.ListConfigs(configListDataID, configListGroup, "", configListPage, configListSize)
 		checkError(err)
 
+		// The server's pagination wins: it may cap the page size
+		size := configs.PageSize(configListSize)
+		if size < configListSize {
+			fmt.Fprintf(stderr, "Warning: the server caps the page size at %d (requested %d)\n", size, configListSize)
+		}
+		page, totalPages := configs.Page(configListPage), configs.TotalPages(configListSize)
+
+		if configListOutput == "csv" {
+			header, rows := configs.CSV()
+			checkError(util.WriteCSV(stdout, header, rows))
+			return
+		}
+
 		// Display results
 		if len(configs.PageItems) == 0 {
-			fmt.Println("No configurations found")
+			if totalPages > 0 {
+				fmt.Fprintf(stdout, "Page %d is out of range (Total: %d items, Total pages: %d)\n", page, configs.TotalCount, totalPages)
+				return
+			}
+			fmt.Fprintln(stdout, "No configurations found")
+			printNamespaceHint(nacosClient, nil)
 			return
 		}
 
-		fmt.Printf("Configuration List (Total: %d)\n", configs.TotalCount)
-		fmt.Println("═══════════════════════════════════════════════════════════════")
-		fmt.Printf("%-5s %-30s %-20s %-10s\n", "No.", "Data ID", "Group", "Type")
-		fmt.Println("───────────────────────────────────────────────────────────────")
+		fmt.Fprintf(stdout, "Configuration List (Page: %d/%d, Size: %d, Total: %d)\n", page, totalPages, size, configs.TotalCount)
+		fmt.Fprintln(stdout, "═══════════════════════════════════════════════════════════════")
+		fmt.Fprintf(stdout, "%-5s %-30s %-20s %-10s\n", "No.", "Data ID", "Group", "Type")
+		fmt.Fprintln(stdout, "───────────────────────────────────────────────────────────────")
 
 		for i, config := range configs.PageItems {
 			groupName := config.GroupName
@@ -43,16 +67,11 @@ var listConfigCmd = &cobra.Command{
 				groupName = config.Group
 			}
 
-			dataID := config.DataID
-			if len(dataID) > 28 {
-				dataID = dataID[:25] + "..."
-			}
-
-			if len(groupName) > 18 {
-				groupName = groupName[:15] + "..."
-			}
+			// Widths are in terminal columns, so CJK names stay aligned
+			dataID := util.PadRight(util.Truncate(config.DataID, 28, "..."), 30)
+			groupName = util.PadRight(util.Truncate(groupName, 18, "..."), 20)
 
-			fmt.Printf("%-5d %-30s %-20s %-10s\n", i+1, dataID, groupName, config.Type)
+			fmt.Fprintf(stdout, "%-5d %s %s %-10s\n", i+1, dataID, groupName, config.Type)
 		}
 	},
 }
@@ -62,5 +81,6 @@ func init() {
 	listConfigCmd.Flags().IntVar(&configListSize, "size", 20, "Page size (default: 20)")
 	listConfigCmd.Flags().StringVar(&configListDataID, "data-id", "", "Filter by data ID (supports wildcard *, e.g. 'resource*')")
 	listConfigCmd.Flags().StringVar(&configListGroup, "group", "", "Filter by group (supports wildcard *, e.g. 'skill_*')")
+	listConfigCmd.Flags().StringVar(&configListOutput, "output", "table", "Output format: table or csv (csv keeps names in full)")
 	rootCmd.AddCommand(listConfigCmd)
 }
@@ -0,0 +1,22 @@
+package cmd
+
+import "testing"
+
+func TestConfigList(t *testing.T) {
+	s := newNacosStub(t)
+	tests := []struct {
+		golden string
+		args   []string
+	}{
+		{"config_list", []string{"config-list"}},
+		{"config_list_csv", []string{"config-list", "--output", "csv"}},
+		{"config_list_filtered", []string{"config-list", "--group", "team"}},
+		{"config_list_empty", []string{"config-list", "--data-id", "missing*"}},
+		{"config_list_bad_output", []string{"config-list", "--output", "xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			checkGolden(t, tt.golden, runCommand(t, s.Addr, tt.args...))
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var groupListOutput string
+
+var listGroupCmd = &cobra.Command{
+	Use:   "group-list",
+	Short: "List config groups with their number of configs",
+	Long:  help.GroupList.FormatForCLI("nacos-cli"),
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if groupListOutput != "table" && groupListOutput != "json" && groupListOutput != "csv" {
+			checkError(fmt.Errorf("invalid --output %q: use table, json or csv", groupListOutput))
+		}
+		nacosClient := mustNewNacosClient()
+		groups, err := nacosClient.ListGroups()
+		checkError(err)
+
+		if groupListOutput == "json" {
+			data, err := json.MarshalIndent(groups, "", "  ")
+			checkError(err)
+			fmt.Fprintln(stdout, string(data))
+			return
+		}
+		if groupListOutput == "csv" {
+			rows := make([][]string, len(groups))
+			for i, group := range groups {
+				rows[i] = []string{group.Group, strconv.Itoa(group.Configs)}
+			}
+			checkError(util.WriteCSV(stdout, []string{"group", "configs"}, rows))
+			return
+		}
+		if len(groups) == 0 {
+			fmt.Fprintln(stdout, "No configurations found")
+			printNamespaceHint(nacosClient, nil)
+			return
+		}
+
+		fmt.Fprintf(stdout, "Group List (Total: %d)\n", len(groups))
+		fmt.Fprintln(stdout, "═══════════════════════════════════════════════════════════════")
+		fmt.Fprintf(stdout, "%-5s %-40s %s\n", "No.", "Group", "Configs")
+		fmt.Fprintln(stdout, "───────────────────────────────────────────────────────────────")
+		for i, group := range groups {
+			fmt.Fprintf(stdout, "%-5d %s %d\n", i+1, util.PadRight(util.Truncate(group.Group, 38, "..."), 40), group.Configs)
+		}
+	},
+}
+
+func init() {
+	listGroupCmd.Flags().StringVar(&groupListOutput, "output", "table", "Output format: table, json or csv")
+	rootCmd.AddCommand(listGroupCmd)
+}
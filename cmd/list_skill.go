@@ -1,8 +1,10 @@
 package cmd
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"strconv"
 
 	"github.com/nacos-group/nacos-cli/internal/help"
 	"github.com/nacos-group/nacos-cli/internal/skill"
@@ -11,9 +13,12 @@ import (
 )
 
 var (
-	skillListPage int
-	skillListSize int
-	skillListName string
+	skillListPage   int
+	skillListSize   int
+	skillListName   string
+	skillListDetail bool
+	skillListTag    string
+	skillListOutput string
 )
 
 const defaultDescLimit = 200
@@ -29,44 +34,104 @@ var listSkillCmd = &cobra.Command{
 		// Create skill service
 		skillService := skill.NewSkillService(nacosClient)
 
+		if skillListOutput != "table" && skillListOutput != "json" && skillListOutput != "csv" {
+			checkError(fmt.Errorf("--output must be table, json or csv"))
+		}
+
 		// List skills
 		skills, totalCount, err := skillService.ListSkills(skillListName, skillListPage, skillListSize)
 		checkError(err)
 
+		// Version, tags and resources are only in each skill's files
+		var details []skill.SkillDetail
+		if skillListDetail || skillListTag != "" {
+			names := make([]string, len(skills))
+			for i, s := range skills {
+				names[i] = s.Name
+			}
+			details = skillService.SkillDetails(names)
+		}
+		if skillListTag != "" {
+			skills, details = skill.FilterByTag(skills, details, skillListTag)
+		}
+
+		if skillListOutput == "json" {
+			data, err := json.MarshalIndent(map[string]any{"totalCount": totalCount, "skills": skill.ListEntries(skills, details)}, "", "  ")
+			checkError(err)
+			fmt.Fprintln(stdout, string(data))
+			return
+		}
+		if skillListOutput == "csv" {
+			header, rows := skill.ListCSV(skill.ListEntries(skills, details), details != nil)
+			checkError(util.WriteCSV(stdout, header, rows))
+			return
+		}
+
 		// Display results
 		if len(skills) == 0 {
-			fmt.Println("No skills found")
+			if skillListTag != "" {
+				fmt.Fprintf(stdout, "No skills tagged %s on page %d\n", skillListTag, skillListPage)
+				return
+			}
+			fmt.Fprintln(stdout, "No skills found")
+			printNamespaceHint(nacosClient, nil)
 			return
 		}
 
 		asciiMode := os.Getenv("NO_UNICODE_OUTPUT") != ""
 		separator := util.SeparatorLine(79, asciiMode)
 
-		fmt.Printf("Skill List (Total: %d)\n", totalCount)
-		fmt.Println(separator)
+		fmt.Fprintf(stdout, "Skill List (Total: %d)\n", totalCount)
+		fmt.Fprintln(stdout, separator)
+		if skillListDetail {
+			fmt.Fprintf(stdout, "     %-28s %-13s %s\n", "NAME", "RESOURCES", "DESCRIPTION")
+		}
 		for i, skill := range skills {
+			if skillListDetail {
+				resources, desc := detailColumns(skill.Description, details[i])
+				fmt.Fprintf(stdout, "%3d. %s %-13s %s\n", i+1, util.PadRight(skill.Name, 28), resources, desc)
+				if details[i].Info != nil && details[i].Info.Details() != "" {
+					fmt.Fprintf(stdout, "     %s\n", details[i].Info.Details())
+				}
+				continue
+			}
 			if skill.Description != "" {
 				desc := truncateDesc(skill.Description, defaultDescLimit)
-				fmt.Printf("%3d. %s - %s\n", i+1, skill.Name, desc)
+				fmt.Fprintf(stdout, "%3d. %s - %s\n", i+1, skill.Name, desc)
 			} else {
-				fmt.Printf("%3d. %s\n", i+1, skill.Name)
+				fmt.Fprintf(stdout, "%3d. %s\n", i+1, skill.Name)
 			}
 		}
 	},
 }
 
+// detailDescLimit is where descriptions are cut in the --detail table; the
+// full text is in --output json
+const detailDescLimit = 60
+
+// detailColumns returns the resource count and description columns of a
+// skill in the --detail table
+func detailColumns(description string, detail skill.SkillDetail) (string, string) {
+	if detail.Err != nil {
+		return "(unavailable)", truncateDesc(description, detailDescLimit)
+	}
+	return strconv.Itoa(detail.Resources), truncateDesc(description, detailDescLimit)
+}
+
 func init() {
 	listSkillCmd.Flags().IntVar(&skillListPage, "page", 1, "Page number (default: 1)")
 	listSkillCmd.Flags().IntVar(&skillListSize, "size", 20, "Page size (default: 20)")
 	listSkillCmd.Flags().StringVar(&skillListName, "name", "", "Filter by skill name (supports wildcard *)")
+	listSkillCmd.Flags().BoolVar(&skillListDetail, "detail", false, "Show the version and tags from each skill's SKILL.md")
+	listSkillCmd.Flags().StringVar(&skillListOutput, "output", "table", "Output format: table, json or csv (json and csv keep descriptions in full)")
+	listSkillCmd.Flags().StringVar(&skillListTag, "tag", "", "Only show skills of the page with this tag in their SKILL.md")
 	rootCmd.AddCommand(listSkillCmd)
 }
 
-// truncateDesc truncates description to maxLen and appends ...... if needed
+// truncateDesc cuts description to maxLen terminal columns and appends ...... if needed
 func truncateDesc(desc string, maxLen int) string {
-	runes := []rune(desc)
-	if len(runes) <= maxLen {
+	if util.DisplayWidth(desc) <= maxLen {
 		return desc
 	}
-	return string(runes[:maxLen]) + "......"
+	return util.Truncate(desc, maxLen, "") + "......"
 }
@@ -0,0 +1,22 @@
+package cmd
+
+import "testing"
+
+func TestSkillList(t *testing.T) {
+	s := newSkillStub(t)
+	tests := []struct {
+		golden string
+		args   []string
+	}{
+		{"skill_list", []string{"skill-list"}},
+		{"skill_list_json", []string{"skill-list", "--output", "json"}},
+		{"skill_list_csv", []string{"skill-list", "--output", "csv"}},
+		{"skill_list_empty", []string{"skill-list", "--name", "missing*"}},
+		{"skill_list_bad_output", []string{"skill-list", "--output", "xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			checkGolden(t, tt.golden, runCommand(t, s.Addr, tt.args...))
+		})
+	}
+}
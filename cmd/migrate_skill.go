@@ -0,0 +1,170 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/config"
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/report"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+	"github.com/spf13/cobra"
+)
+
+var (
+	migrateFrom      string
+	migrateTo        string
+	migrateSkills    []string
+	migrateAll       bool
+	migrateOverwrite bool
+	migrateSkip      bool
+	migrateViaConfig bool
+	migrateState     string
+	migrateReport    string
+)
+
+var migrateSkillCmd = &cobra.Command{
+	Use:   "skill-migrate --from-profile <profile> --to-profile <profile> (--skills a,b | --all)",
+	Short: "Copy skills from one Nacos to another and verify them",
+	Long:  help.SkillMigrate.FormatForCLI("nacos-cli"),
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if migrateFrom == "" || migrateTo == "" {
+			checkError(fmt.Errorf("--from-profile and --to-profile are required"))
+		}
+		if migrateAll == (len(migrateSkills) > 0) {
+			checkError(fmt.Errorf("specify either --skills or --all"))
+		}
+		if migrateOverwrite && migrateSkip {
+			checkError(fmt.Errorf("--overwrite and --skip cannot be used together"))
+		}
+
+		fromClient := mustNewProfileClient(migrateFrom)
+		toClient := mustNewProfileClient(migrateTo)
+		mustLogin(fromClient)
+		mustLogin(toClient)
+		from, to := skill.NewSkillService(fromClient), skill.NewSkillService(toClient)
+		migrator := skill.NewMigrator(from, to)
+		migrator.Overwrite = migrateOverwrite
+		migrator.ViaConfig = migrateViaConfig
+
+		statePath := migrateState
+		if statePath == "" {
+			dir, err := config.GetConfigDir()
+			checkError(err)
+			checkError(os.MkdirAll(dir, 0755))
+			statePath = filepath.Join(dir, fmt.Sprintf("migrate-%s-%s.json", stateName(migrateFrom), stateName(migrateTo)))
+		}
+		state, err := skill.LoadMigrateState(statePath, migrateFrom, migrateTo)
+		checkError(err)
+
+		names := migrateSkills
+		if migrateAll {
+			names, err = from.AllSkillNames()
+			checkError(err)
+		}
+		fmt.Fprintf(stdout, "Migrating %d skills from %s (%s) to %s (%s)\n", len(names), migrateFrom, fromClient.ServerAddr, migrateTo, toClient.ServerAddr)
+		fmt.Fprintf(stdout, "State: %s\n\n", statePath)
+
+		counts := make(map[string]int)
+		var problems []skill.MigrateResult
+		rep := report.New("skill-migrate")
+		trail := &requestTrail{}
+		trail.follow(fromClient)
+		trail.follow(toClient)
+		for i, name := range names {
+			if previous, ok := state.Skills[name]; ok && previous.Done() {
+				fmt.Fprintf(stdout, "[%d/%d] %s: already %s in an earlier run\n", i+1, len(names), name, previous.Status)
+				counts[previous.Status]++
+				rep.Add(report.Item{Name: name, Status: previous.Status}, 0)
+				continue
+			}
+			start := time.Now()
+			trail.take() // requests sent before this skill belong to no item
+			result := migrator.Migrate(name)
+			rep.Add(migrateItem(result, trail.take()), time.Since(start))
+			state.Skills[name] = result
+			// Saved after every skill, so an interrupted run resumes here
+			checkError(state.Save(statePath))
+			counts[result.Status]++
+			if result.Error != "" {
+				fmt.Fprintf(stdout, "[%d/%d] %s: %s: %s\n", i+1, len(names), name, result.Status, result.Error)
+				problems = append(problems, result)
+			} else {
+				fmt.Fprintf(stdout, "[%d/%d] %s: %s\n", i+1, len(names), name, result.Status)
+			}
+		}
+
+		fmt.Fprintln(stdout, "\n========== Migration Report ==========")
+		fmt.Fprintf(stdout, "Migrated: %d | Identical: %d | Skipped: %d | Unverified: %d | Failed: %d\n",
+			counts[skill.MigrateMigrated], counts[skill.MigrateIdentical], counts[skill.MigrateSkipped],
+			counts[skill.MigrateUnverified], counts[skill.MigrateFailed])
+		for _, result := range problems {
+			fmt.Fprintf(stdout, "  %-10s %s: %s\n", result.Status, result.Name, result.Error)
+		}
+		writeReport(rep, migrateReport)
+		if counts[skill.MigrateFailed] > 0 || counts[skill.MigrateUnverified] > 0 {
+			fmt.Fprintln(stdout, "Run the same command again to retry the skills that are not done.")
+			exit(1)
+		}
+	},
+}
+
+// migrateItem is the --report entry of a migrated skill and the requests
+// migrating it sent to either server; failed and unverified skills count as
+// failures, as for the exit code
+func migrateItem(result skill.MigrateResult, requests []report.Request) report.Item {
+	failed := result.Status == skill.MigrateFailed || result.Status == skill.MigrateUnverified
+	return report.Item{Name: result.Name, Status: result.Status, Failed: failed, Error: result.Error, Bytes: result.Bytes, Requests: requests}
+}
+
+// mustNewProfileClient creates a client from a profile name, or from a config
+// file when the argument is a path, without prompting for missing settings
+func mustNewProfileClient(profile string) *client.NacosClient {
+	path := profile
+	if !strings.ContainsAny(profile, `/\`) && !strings.HasSuffix(profile, config.ConfigFileSuffix) {
+		var err error
+		path, err = config.GetProfileConfigPath(profile)
+		checkError(err)
+	}
+	cfg, err := config.LoadConfig(path)
+	checkError(err)
+	if cfg.GetServerAddr() == "" {
+		checkError(fmt.Errorf("profile %s has no host", profile))
+	}
+	defaults, err := cfg.GetDefaults()
+	checkError(err)
+	user, pass := cfg.Username, cfg.Password
+	if cfg.Token != "" {
+		user, pass = "", ""
+	}
+	c, err := client.NewNacosClient(cfg.GetServerAddr(), defaults.Namespace, cfg.AuthType, user, pass, cfg.AccessKey, cfg.SecretKey, cfg.Token)
+	checkError(err)
+	c.SetTimeout(timeout)
+	c.SetContentValidation(!noContentValidation)
+	c.SetMD5Verification(!noVerifyMD5)
+	c.SetCompression(!noCompress)
+	return c
+}
+
+// stateName turns a profile name or config file path into part of a file name
+func stateName(profile string) string {
+	return strings.TrimSuffix(filepath.Base(profile), config.ConfigFileSuffix)
+}
+
+func init() {
+	migrateSkillCmd.Flags().StringVar(&migrateFrom, "from-profile", "", "Profile (or config file) of the Nacos to copy skills from")
+	migrateSkillCmd.Flags().StringVar(&migrateTo, "to-profile", "", "Profile (or config file) of the Nacos to copy skills to")
+	migrateSkillCmd.Flags().StringSliceVar(&migrateSkills, "skills", nil, "Skills to migrate, comma-separated")
+	migrateSkillCmd.Flags().BoolVar(&migrateAll, "all", false, "Migrate every skill of the source")
+	migrateSkillCmd.Flags().BoolVar(&migrateOverwrite, "overwrite", false, "Replace skills the destination has with other files")
+	migrateSkillCmd.Flags().BoolVar(&migrateSkip, "skip", false, "Leave skills the destination has with other files alone (the default)")
+	migrateSkillCmd.Flags().BoolVar(&migrateViaConfig, "via-config", false, "Publish through the config API, for destinations without the skill upload API")
+	migrateSkillCmd.Flags().StringVar(&migrateReport, "report", "", reportUsage)
+	migrateSkillCmd.Flags().StringVar(&migrateState, "state", "", "State file recording finished skills (default: ~/.nacos-cli/migrate-<from>-<to>.json)")
+	rootCmd.AddCommand(migrateSkillCmd)
+}
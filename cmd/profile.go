@@ -1,14 +1,14 @@
 package cmd
 
 import (
-	"bufio"
 	"fmt"
 	"os"
-	"strings"
 
 	"github.com/nacos-group/nacos-cli/internal/client"
 	"github.com/nacos-group/nacos-cli/internal/config"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
 	"github.com/nacos-group/nacos-cli/internal/terminal"
+	"github.com/nacos-group/nacos-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
@@ -44,8 +44,8 @@ Examples:
 		// Get config path
 		configPath, err := config.GetProfileConfigPath(profileName)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			exit(1)
 		}
 
 		// Try to load existing config
@@ -53,7 +53,7 @@ Examples:
 		if _, err := os.Stat(configPath); err == nil {
 			cfg, err = config.LoadConfig(configPath)
 			if err != nil {
-				fmt.Printf("Warning: Failed to load existing config: %v\n", err)
+				fmt.Fprintf(stderr, "Warning: Failed to load existing config: %v\n", err)
 				cfg = &config.Config{}
 			}
 		} else {
@@ -61,39 +61,41 @@ Examples:
 		}
 
 		// Show current config and prompt for updates
-		fmt.Printf("Editing configuration for profile '%s'\n", profileName)
-		fmt.Printf("Config file: %s\n", configPath)
-		fmt.Println()
+		fmt.Fprintf(stdout, "Editing configuration for profile '%s'\n", profileName)
+		fmt.Fprintf(stdout, "Config file: %s\n", configPath)
+		fmt.Fprintln(stdout)
 
 		if err := cfg.PromptForUpdate(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			exit(1)
 		}
 
 		// Save the updated config
 		if err := cfg.SaveConfig(configPath); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to save config: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(stderr, "Error: Failed to save config: %v\n", err)
+			exit(1)
 		}
 
-		fmt.Printf("\nConfiguration saved to %s\n", configPath)
+		fmt.Fprintf(stdout, "\nConfiguration saved to %s\n", configPath)
 
-		// Ask user if they want to login
-		reader := bufio.NewReader(os.Stdin)
-		fmt.Print("\nLogin now? [Y/n] (Enter=Yes): ")
-		input, err := reader.ReadString('\n')
+		// Ask user if they want to login (Enter means yes)
+		fmt.Fprintln(stdout)
+		login, err := ui.Confirm("Login now?", false)
 		if err != nil {
 			return
 		}
-		input = strings.TrimSpace(strings.ToLower(input))
 
-		// Default to yes (empty input or 'y' or 'yes')
-		if input == "" || input == "y" || input == "yes" {
-			fmt.Println()
+		if login {
+			fmt.Fprintln(stdout)
+			defaults, err := cfg.GetDefaults()
+			if err != nil {
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				exit(1)
+			}
 			// Start interactive terminal with the edited config
 			nacosClient, err := client.NewNacosClient(
 				cfg.GetServerAddr(),
-				cfg.Namespace,
+				defaults.Namespace,
 				cfg.AuthType,
 				cfg.Username,
 				cfg.Password,
@@ -101,17 +103,24 @@ Examples:
 				cfg.SecretKey,
 				cfg.Token,
 			)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
-			}
+			checkError(err)
+			applyLang(cfg.Lang, "config file "+configPath)
 			term := terminal.NewTerminal(nacosClient)
+			term.SetHistoryFile(cfg.HistoryFile)
+			term.SetAliases(cfg.Aliases, configPath)
+			term.SetDefaultGroup(cfg.DefaultGroup)
+			term.SetSyncHooks(skillsync.Hooks{OnChange: cfg.OnChange, OnError: cfg.OnError})
+			if timeout, err := cfg.GetPollTimeout(); err == nil {
+				term.SetPollTimeout(timeout)
+			} else {
+				fmt.Fprintf(stderr, "Warning: %v\n", err)
+			}
 			if err := term.Start(); err != nil {
-				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-				os.Exit(1)
+				fmt.Fprintf(stderr, "Error: %v\n", err)
+				exit(1)
 			}
 		} else {
-			fmt.Printf("\nTo use this profile, run: nacos-cli --profile %s\n", profileName)
+			fmt.Fprintf(stdout, "\nTo use this profile, run: nacos-cli --profile %s\n", profileName)
 		}
 	},
 }
@@ -136,43 +145,61 @@ Examples:
 		// Get config path
 		configPath, err := config.GetProfileConfigPath(profileName)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			exit(1)
 		}
 
 		// Check if config exists
 		if _, err := os.Stat(configPath); os.IsNotExist(err) {
-			fmt.Printf("Profile '%s' does not exist.\n", profileName)
-			fmt.Printf("Config file: %s\n", configPath)
-			fmt.Println("\nRun 'nacos-cli profile edit " + profileName + "' to create it.")
+			fmt.Fprintf(stdout, "Profile '%s' does not exist.\n", profileName)
+			fmt.Fprintf(stdout, "Config file: %s\n", configPath)
+			fmt.Fprintln(stdout, "\nRun 'nacos-cli profile edit "+profileName+"' to create it.")
 			return
 		}
 
 		// Load config
 		cfg, err := config.LoadConfig(configPath)
 		if err != nil {
-			fmt.Fprintf(os.Stderr, "Error: Failed to load config: %v\n", err)
-			os.Exit(1)
+			fmt.Fprintf(stderr, "Error: Failed to load config: %v\n", err)
+			exit(1)
 		}
 
 		// Display config
-		fmt.Printf("Profile: %s\n", profileName)
-		fmt.Printf("Config file: %s\n", configPath)
-		fmt.Println("─────────────────────────────────────────")
-		fmt.Printf("%-15s %s\n", "host:", cfg.Host)
-		fmt.Printf("%-15s %d\n", "port:", cfg.Port)
-		fmt.Printf("%-15s %s\n", "auth-type:", cfg.AuthType)
+		fmt.Fprintf(stdout, "Profile: %s\n", profileName)
+		fmt.Fprintf(stdout, "Config file: %s\n", configPath)
+		fmt.Fprintln(stdout, "─────────────────────────────────────────")
+		fmt.Fprintf(stdout, "%-15s %s\n", "host:", cfg.Host)
+		fmt.Fprintf(stdout, "%-15s %d\n", "port:", cfg.Port)
+		fmt.Fprintf(stdout, "%-15s %s\n", "auth-type:", cfg.AuthType)
 		if cfg.AuthType == "aliyun" {
-			fmt.Printf("%-15s %s\n", "access-key:", cfg.AccessKey)
-			fmt.Printf("%-15s %s\n", "secret-key:", maskPassword(cfg.SecretKey))
+			fmt.Fprintf(stdout, "%-15s %s\n", "access-key:", cfg.AccessKey)
+			fmt.Fprintf(stdout, "%-15s %s\n", "secret-key:", maskPassword(cfg.SecretKey))
 		} else {
-			fmt.Printf("%-15s %s\n", "username:", cfg.Username)
-			fmt.Printf("%-15s %s\n", "password:", maskPassword(cfg.Password))
+			fmt.Fprintf(stdout, "%-15s %s\n", "username:", cfg.Username)
+			fmt.Fprintf(stdout, "%-15s %s\n", "password:", maskPassword(cfg.Password))
 		}
-		if cfg.Namespace != "" {
-			fmt.Printf("%-15s %s\n", "namespace:", cfg.Namespace)
+		defaults, err := cfg.GetDefaults()
+		if err != nil {
+			fmt.Fprintf(stderr, "Error: %v\n", err)
+			exit(1)
+		}
+		if cfg.KMSEndpoint != "" {
+			fmt.Fprintf(stdout, "%-15s %s\n", "kms-endpoint:", cfg.KMSEndpoint)
+			fmt.Fprintf(stdout, "%-15s %s\n", "kms-key-id:", cfg.KMSKeyID)
+		}
+		if defaults.Namespace != "" {
+			fmt.Fprintf(stdout, "%-15s %s\n", "namespace:", defaults.Namespace)
 		} else {
-			fmt.Printf("%-15s %s\n", "namespace:", "(public)")
+			fmt.Fprintf(stdout, "%-15s %s\n", "namespace:", "(public)")
+		}
+		if defaults.Group != "" {
+			fmt.Fprintf(stdout, "%-15s %s\n", "group:", defaults.Group)
+		}
+		if defaults.Output != "" {
+			fmt.Fprintf(stdout, "%-15s %s\n", "output:", defaults.Output)
+		}
+		if defaults.PageSize > 0 {
+			fmt.Fprintf(stdout, "%-15s %d\n", "page-size:", defaults.PageSize)
 		}
 	},
 }
@@ -8,7 +8,6 @@ import (
 
 	"github.com/nacos-group/nacos-cli/internal/agentspec"
 	"github.com/nacos-group/nacos-cli/internal/help"
-	"github.com/nacos-group/nacos-cli/internal/util"
 	"github.com/spf13/cobra"
 )
 
@@ -23,8 +22,8 @@ var publishAgentSpecCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
-			fmt.Fprintf(os.Stderr, "Error: agent spec path required\n")
-			os.Exit(1)
+			fmt.Fprintf(stderr, "Error: agent spec path required\n")
+			exit(1)
 		}
 		specPath := args[0]
 
@@ -41,27 +40,20 @@ var publishAgentSpecCmd = &cobra.Command{
 }
 
 func publishSingleAgentSpec(specPath string, agentSpecService *agentspec.AgentSpecService) {
-	expanded, err := util.ExpandTilde(specPath)
-	checkError(err)
-	specPath = expanded
-
-	absPath, err := filepath.Abs(specPath)
-	checkError(err)
+	absPath := mustResolvePath(specPath)
 
 	specName := filepath.Base(absPath)
-	fmt.Printf("Publishing agent spec: %s...\n", specName)
+	fmt.Fprintf(stdout, "Publishing agent spec: %s...\n", specName)
 
-	err = agentSpecService.UploadAgentSpec(absPath)
+	err := agentSpecService.UploadAgentSpec(absPath)
 	checkError(err)
 
-	fmt.Printf("Agent spec published successfully!\n")
-	fmt.Printf("  Tip: Use the Nacos console to review and go online, or use 'agentspec-list' to verify.\n")
+	fmt.Fprintf(stdout, "Agent spec published successfully!\n")
+	fmt.Fprintf(stdout, "  Tip: Use the Nacos console to review and go online, or use 'agentspec-list' to verify.\n")
 }
 
 func publishAllAgentSpecs(folderPath string, agentSpecService *agentspec.AgentSpecService) {
-	expanded, err := util.ExpandTilde(folderPath)
-	checkError(err)
-	folderPath = expanded
+	folderPath = mustResolvePath(folderPath)
 
 	entries, err := os.ReadDir(folderPath)
 	checkError(err)
@@ -79,46 +71,46 @@ func publishAllAgentSpecs(folderPath string, agentSpecService *agentspec.AgentSp
 	}
 
 	if len(specDirs) == 0 {
-		fmt.Println("No agent specs found (directories with manifest.json)")
+		fmt.Fprintln(stdout, "No agent specs found (directories with manifest.json)")
 		return
 	}
 
-	fmt.Printf("Found %d agent specs:\n", len(specDirs))
+	fmt.Fprintf(stdout, "Found %d agent specs:\n", len(specDirs))
 	for _, name := range specDirs {
-		fmt.Printf("  - %s\n", name)
+		fmt.Fprintf(stdout, "  - %s\n", name)
 	}
-	fmt.Println()
+	fmt.Fprintln(stdout)
 
 	successCount := 0
 	failedCount := 0
 
 	for i, specName := range specDirs {
-		fmt.Println(strings.Repeat("=", 80))
-		fmt.Printf("[%d/%d] Publishing agent spec: %s\n", i+1, len(specDirs), specName)
-		fmt.Println(strings.Repeat("=", 80))
+		fmt.Fprintln(stdout, strings.Repeat("=", 80))
+		fmt.Fprintf(stdout, "[%d/%d] Publishing agent spec: %s\n", i+1, len(specDirs), specName)
+		fmt.Fprintln(stdout, strings.Repeat("=", 80))
 
 		specPath := filepath.Join(folderPath, specName)
 		err := agentSpecService.UploadAgentSpec(specPath)
 		if err != nil {
-			fmt.Printf("Publish failed: %v\n", err)
+			fmt.Fprintf(stdout, "Publish failed: %v\n", err)
 			failedCount++
 		} else {
-			fmt.Printf("Publish successful!\n")
+			fmt.Fprintf(stdout, "Publish successful!\n")
 			successCount++
 		}
-		fmt.Println()
+		fmt.Fprintln(stdout)
 	}
 
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Println("Batch Publish Complete")
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Printf("Success: %d\n", successCount)
+	fmt.Fprintln(stdout, strings.Repeat("=", 80))
+	fmt.Fprintln(stdout, "Batch Publish Complete")
+	fmt.Fprintln(stdout, strings.Repeat("=", 80))
+	fmt.Fprintf(stdout, "Success: %d\n", successCount)
 	if failedCount > 0 {
-		fmt.Printf("Failed: %d\n", failedCount)
+		fmt.Fprintf(stdout, "Failed: %d\n", failedCount)
 	}
-	fmt.Printf("Total: %d\n", len(specDirs))
-	fmt.Println()
-	fmt.Println("Tip: Use the Nacos console to review and go online, or use 'agentspec-list' to verify.")
+	fmt.Fprintf(stdout, "Total: %d\n", len(specDirs))
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, "Tip: Use the Nacos console to review and go online, or use 'agentspec-list' to verify.")
 }
 
 func init() {
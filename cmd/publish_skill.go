@@ -5,14 +5,21 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+	"github.com/nacos-group/nacos-cli/internal/report"
 	"github.com/nacos-group/nacos-cli/internal/skill"
+	"github.com/nacos-group/nacos-cli/internal/ui"
 	"github.com/spf13/cobra"
 )
 
 var (
-	publishAll bool
+	publishAll     bool
+	publishVersion string
+	publishConfig  bool
+	publishReport  string
 )
 
 var publishSkillCmd = &cobra.Command{
@@ -22,57 +29,56 @@ var publishSkillCmd = &cobra.Command{
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
-			fmt.Fprintf(os.Stderr, "Error: skill path required\n")
-			os.Exit(1)
+			fmt.Fprintln(stderr, i18n.T("Error: skill path required"))
+			exit(1)
 		}
 		skillPath := args[0]
 
 		// Create Nacos client
 		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
 
 		// Create skill service
 		skillService := skill.NewSkillService(nacosClient)
+		skillService.SetProgress(ui.NewProgress)
 
 		// Handle batch publish
+		rep := report.New("skill-publish")
+		trail := &requestTrail{}
+		trail.follow(nacosClient)
 		if publishAll {
-			publishAllSkills(skillPath, skillService)
+			if publishVersion != "" {
+				checkError(fmt.Errorf("--version cannot be used with --all"))
+			}
+			publishAllSkills(skillPath, skillService, rep, trail)
 			return
 		}
 
 		// Single skill publish
-		publishSingleSkill(skillPath, skillService)
+		publishSingleSkill(skillPath, skillService, rep, trail)
 	},
 }
 
-func publishSingleSkill(skillPath string, skillService *skill.SkillService) {
-	// Expand ~ to home directory
-	if strings.HasPrefix(skillPath, "~") {
-		homeDir, err := os.UserHomeDir()
-		checkError(err)
-		skillPath = filepath.Join(homeDir, skillPath[1:])
-	}
-
-	// Expand path
-	absPath, err := filepath.Abs(skillPath)
-	checkError(err)
+func publishSingleSkill(skillPath string, skillService *skill.SkillService, rep *report.Report, trail *requestTrail) {
+	absPath := mustResolvePath(skillPath)
 
 	skillName := filepath.Base(absPath)
-	fmt.Printf("Publishing skill: %s...\n", skillName)
+	fmt.Fprintln(stdout, i18n.T("Publishing skill: %s...", skillName))
 
-	err = skillService.UploadSkill(absPath)
+	start := time.Now()
+	trail.take() // requests sent before this skill belong to no item
+	result, err := uploadSkill(skillService, absPath, publishVersion)
+	rep.Add(publishItem(skillName, result, err, trail.take()), time.Since(start))
+	writeReport(rep, publishReport)
 	checkError(err)
 
-	fmt.Printf("Skill published successfully!\n")
-	fmt.Printf("  Tip: Use the Nacos console to review and go online, or use 'skill-list' to verify.\n")
+	fmt.Fprintln(stdout, i18n.T("Skill published successfully!"))
+	printUploadResult(result)
+	fmt.Fprintln(stdout, "  "+i18n.T("Tip: Use the Nacos console to review and go online, or use 'skill-list' to verify."))
 }
 
-func publishAllSkills(folderPath string, skillService *skill.SkillService) {
-	// Expand ~ to home directory
-	if strings.HasPrefix(folderPath, "~") {
-		homeDir, err := os.UserHomeDir()
-		checkError(err)
-		folderPath = filepath.Join(homeDir, folderPath[1:])
-	}
+func publishAllSkills(folderPath string, skillService *skill.SkillService, rep *report.Report, trail *requestTrail) {
+	folderPath = mustResolvePath(folderPath)
 
 	// List subdirectories
 	entries, err := os.ReadDir(folderPath)
@@ -92,50 +98,88 @@ func publishAllSkills(folderPath string, skillService *skill.SkillService) {
 	}
 
 	if len(skillDirs) == 0 {
-		fmt.Println("No skills found (directories with SKILL.md)")
+		fmt.Fprintln(stdout, i18n.T("No skills found (directories with SKILL.md)"))
+		writeReport(rep, publishReport)
 		return
 	}
 
-	fmt.Printf("Found %d skills:\n", len(skillDirs))
+	fmt.Fprintln(stdout, i18n.T("Found %d skills:", len(skillDirs)))
 	for _, name := range skillDirs {
-		fmt.Printf("  - %s\n", name)
+		fmt.Fprintf(stdout, "  - %s\n", name)
 	}
-	fmt.Println()
+	fmt.Fprintln(stdout)
 
 	successCount := 0
 	failedCount := 0
 
 	for i, skillName := range skillDirs {
-		fmt.Println(strings.Repeat("=", 80))
-		fmt.Printf("[%d/%d] Publishing skill: %s\n", i+1, len(skillDirs), skillName)
-		fmt.Println(strings.Repeat("=", 80))
+		fmt.Fprintln(stdout, strings.Repeat("=", 80))
+		fmt.Fprintln(stdout, i18n.T("[%d/%d] Publishing skill: %s", i+1, len(skillDirs), skillName))
+		fmt.Fprintln(stdout, strings.Repeat("=", 80))
 
 		skillPath := filepath.Join(folderPath, skillName)
-		err := skillService.UploadSkill(skillPath)
+		start := time.Now()
+		trail.take() // requests sent before this skill belong to no item
+		result, err := uploadSkill(skillService, skillPath, "")
+		rep.Add(publishItem(skillName, result, err, trail.take()), time.Since(start))
 		if err != nil {
-			fmt.Printf("Publish failed: %v\n", err)
+			fmt.Fprintln(stdout, i18n.T("Publish failed: %v", err))
 			failedCount++
 		} else {
-			fmt.Printf("Publish successful!\n")
+			fmt.Fprintln(stdout, i18n.T("Publish successful!"))
+			printUploadResult(result)
 			successCount++
 		}
-		fmt.Println()
+		fmt.Fprintln(stdout)
 	}
 
 	// Summary
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Println("Batch Publish Complete")
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Printf("Success: %d\n", successCount)
+	fmt.Fprintln(stdout, strings.Repeat("=", 80))
+	fmt.Fprintln(stdout, i18n.T("Batch Publish Complete"))
+	fmt.Fprintln(stdout, strings.Repeat("=", 80))
+	fmt.Fprintln(stdout, i18n.T("Success: %d", successCount))
 	if failedCount > 0 {
-		fmt.Printf("Failed: %d\n", failedCount)
+		fmt.Fprintln(stdout, i18n.T("Failed: %d", failedCount))
+	}
+	fmt.Fprintln(stdout, i18n.T("Total: %d", len(skillDirs)))
+	writeReport(rep, publishReport)
+	fmt.Fprintln(stdout)
+	fmt.Fprintln(stdout, i18n.T("Tip: Use the Nacos console to review and go online, or use 'skill-list' to verify."))
+}
+
+// uploadSkill uploads a skill through the skill API, or through the config
+// API with --via-config
+func uploadSkill(skillService *skill.SkillService, skillPath, version string) (*skill.UploadResult, error) {
+	if publishConfig {
+		return skillService.UploadSkillViaConfig(skillPath, version)
+	}
+	return skillService.UploadSkillVersion(skillPath, version)
+}
+
+// publishItem is the --report entry of a published skill and the requests
+// publishing it sent
+func publishItem(name string, result *skill.UploadResult, err error, requests []report.Request) report.Item {
+	if err != nil {
+		return report.Item{Name: name, Status: "failed", Failed: true, Error: err.Error(), Requests: requests}
+	}
+	return report.Item{Name: name, Status: "published", Bytes: result.Bytes, Requests: requests}
+}
+
+// printUploadResult prints the uniformId the server assigned and the files it
+// did not accept
+func printUploadResult(result *skill.UploadResult) {
+	if result.UniformID != "" {
+		fmt.Fprintln(stdout, "  "+i18n.T("Uniform ID: %s", result.UniformID))
+	}
+	for _, warning := range result.Warnings {
+		fmt.Fprintln(stderr, "  "+i18n.T("Warning: %s", warning))
 	}
-	fmt.Printf("Total: %d\n", len(skillDirs))
-	fmt.Println()
-	fmt.Println("Tip: Use the Nacos console to review and go online, or use 'skill-list' to verify.")
 }
 
 func init() {
 	publishSkillCmd.Flags().BoolVar(&publishAll, "all", false, "Publish all skills in the directory")
+	publishSkillCmd.Flags().BoolVar(&publishConfig, "via-config", false, "Publish through the config API, for servers without the skill upload API")
+	publishSkillCmd.Flags().StringVar(&publishReport, "report", "", reportUsage)
+	publishSkillCmd.Flags().StringVar(&publishVersion, "version", "", "Record this version (e.g. 1.4.0) in the uploaded SKILL.md; the local file is not changed")
 	rootCmd.AddCommand(publishSkillCmd)
 }
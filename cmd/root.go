@@ -1,29 +1,65 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/nacos-group/nacos-cli/internal/client"
 	"github.com/nacos-group/nacos-cli/internal/config"
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+	"github.com/nacos-group/nacos-cli/internal/kms"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/report"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
 	"github.com/nacos-group/nacos-cli/internal/terminal"
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/nacos-group/nacos-cli/internal/util"
 	"github.com/spf13/cobra"
 )
 
 var (
-	serverAddr  string
-	host        string
-	port        int
-	namespace   string
-	authType    string
-	username    string
-	password    string
-	token       string
-	accessKey   string
-	secretKey   string
-	configFile  string
-	profileName string // Profile name for config file (default, dev, prod, etc.)
+	serverAddr          string
+	host                string
+	port                int
+	namespace           string
+	authType            string
+	username            string
+	password            string
+	token               string
+	accessKey           string
+	secretKey           string
+	kmsEndpoint         string // Aliyun KMS endpoint that decrypts and encrypts cipher- configs
+	kmsKeyID            string // KMS key cipher- configs are encrypted with
+	configFile          string
+	profileName         string // Profile name for config file (default, dev, prod, etc.)
+	timeout             time.Duration
+	noContentValidation bool   // Accept config responses without the Nacos config headers
+	noVerifyMD5         bool   // Accept downloads that do not match their Content-MD5 header
+	noCompress          bool   // Do not ask the server for gzip-compressed responses
+	verbose             bool   // Log at debug level (-v)
+	logLevel            string // Minimum level of log messages; overrides -v
+	langFlag            string // Language of messages: en or zh
+	historyFile         string // Terminal history file from the config file
+	skillsDir           string // Skills directory from NACOS_SKILLS_DIR or the config file
+	aliasFile           string // Config file terminal aliases are read from and saved to
+
+	defaultGroup    string // Group for config-get/config-set when omitted, from the config file
+	defaultOutput   string // Format of skill-list and group-list when --output is omitted, from the config file
+	defaultPageSize int    // --size of the list commands when omitted, from the config file
+	defaultMaxSize  string // --max-size of config-set when omitted, from the config file
+
+	settings     config.Settings // How each setting was resolved, shown by config-effective
+	pollTimeout  time.Duration   // How long each skill-sync poll may take, from the config file
+	onChangeHook string          // Command skill-sync runs after a skill changes, from the config file
+	onErrorHook  string          // Command skill-sync runs after a skill fails to sync, from the config file
 )
 
 var rootCmd = &cobra.Command{
@@ -41,18 +77,33 @@ Examples:
   nacos-cli profile show    # Show default config
   nacos-cli profile show dev   # Show dev config`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		stdout, stderr = cmd.OutOrStdout(), cmd.ErrOrStderr()
+		applyLogLevel()
+		applyLang("", "")
+
 		// Skip config loading for help, completion, and profile subcommands
 		skipCommands := map[string]bool{
 			"help": true, "completion": true,
 			"profile": true, "edit": true, "show": true,
+			// skill-sync status and stop only read local daemon files
+			"status": true, "stop": true,
+			// skill-migrate reads the two profiles it is given
+			"skill-migrate": true,
+			// skill-verify only reads installed skills
+			"skill-verify": true,
+			// config-lint only reads local content
+			"config-lint": true,
 		}
 		if skipCommands[cmd.Name()] {
 			return
 		}
+		// Resolved again below, now that the config file is known
+		settings = config.Settings{}
 
 		// Determine config loading strategy
 		// Priority: --config > env arg > default
 		var fileConfig *config.Config
+		var fileSource string // where settings from fileConfig came from
 		var err error
 
 		// Check if any connection parameters are provided via command line
@@ -60,94 +111,130 @@ Examples:
 
 		if configFile != "" {
 			// Explicit config file specified
+			configFile = mustResolvePath(configFile)
+			settings.Set("configFile", configFile, "flag --config")
+			fileSource = "config file " + configFile
 			fileConfig, err = config.LoadConfig(configFile)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Warning: Failed to load config file: %v\n", err)
+				fmt.Fprintln(stderr, i18n.T("Warning: Failed to load config file: %v", err))
 			}
 		} else if !hasCommandLineConfig {
 			// No command line config provided, use profile-based config
 			envName := config.DefaultProfile
+			source := config.SourceDefault
 			if profileName != "" {
 				envName = profileName
+				source = "flag --profile"
 			}
 			// This will load, prompt for missing fields, and save
-			fileConfig, _, err = config.LoadOrCreateConfig(envName)
+			var path string
+			fileConfig, path, err = config.LoadOrCreateConfig(envName)
 			if err != nil {
-				fmt.Fprintf(os.Stderr, "Error: Failed to load or create config: %v\n", err)
-				os.Exit(1)
+				fmt.Fprintln(stderr, i18n.T("Error: Failed to load or create config: %v", err))
+				exit(1)
 			}
+			settings.Set("configFile", path, source)
+			fileSource = "config file " + path
+		} else {
+			settings.Set("configFile", "", "not loaded: connection flags were given")
+		}
+		if fileConfig == nil {
+			fileConfig = &config.Config{}
 		}
 
 		// Apply configuration with priority: command line > config file > default
 		// Server address: --server has highest priority
-		if serverAddr == "" {
+		switch {
+		case serverAddr != "":
+			settings.Set("server", serverAddr, "flag --server")
+		case host != "":
 			// Try to build from --host and --port
-			if host != "" {
-				if port > 0 {
-					serverAddr = fmt.Sprintf("%s:%d", host, port)
-				} else if strings.Contains(host, ":") {
-					// Host already contains port
-					serverAddr = host
-				} else {
-					// Use default port 8848
-					serverAddr = fmt.Sprintf("%s:8848", host)
-				}
-			} else if port > 0 {
-				// Only port specified, use default host
-				serverAddr = fmt.Sprintf("127.0.0.1:%d", port)
-			} else if fileConfig != nil {
-				// Use from config file
-				serverAddr = fileConfig.GetServerAddr()
+			source := "flag --host"
+			if port > 0 {
+				serverAddr = fmt.Sprintf("%s:%d", host, port)
+				source = "flag --host and --port"
+			} else if strings.Contains(host, ":") {
+				// Host already contains port
+				serverAddr = host
+			} else {
+				// Use default port 8848
+				serverAddr = fmt.Sprintf("%s:8848", host)
 			}
+			settings.Set("server", serverAddr, source)
+		case port > 0:
+			// Only port specified, use default host
+			serverAddr = fmt.Sprintf("127.0.0.1:%d", port)
+			settings.Set("server", serverAddr, "flag --port")
+		case fileConfig.GetServerAddr() != "":
+			serverAddr = fileConfig.GetServerAddr()
+			settings.Set("server", serverAddr, fileSource)
+		default:
+			serverAddr = "127.0.0.1:8848"
+			settings.Set("server", serverAddr, config.SourceDefault)
 		}
 
-		// Namespace: command line > config file > default (empty)
-		if namespace == "" && fileConfig != nil && fileConfig.Namespace != "" {
-			namespace = fileConfig.Namespace
-		}
-
-		// AuthType: command line > config file > auto-detect by NewNacosClient
-		if authType == "" && fileConfig != nil && fileConfig.AuthType != "" {
-			authType = fileConfig.AuthType
-		}
-
-		// Username: command line > config file
-		if username == "" && fileConfig != nil && fileConfig.Username != "" {
-			username = fileConfig.Username
-		}
-
-		// Password: command line > config file
-		if password == "" && fileConfig != nil && fileConfig.Password != "" {
-			password = fileConfig.Password
-		}
+		// Defaults of the config file fill in flags and arguments the command
+		// line leaves out
+		defaults, err := fileConfig.GetDefaults()
+		checkError(err)
 
-		// Token: command line > config file (token takes priority over username/password when set)
-		if token == "" && fileConfig != nil && fileConfig.Token != "" {
-			token = fileConfig.Token
-		}
+		// Namespace, auth type and credentials: command line > config file.
+		// Without an auth type NewNacosClient detects it from the credentials.
+		resolve("namespace", &namespace, "namespace", defaults.Namespace, fileSource)
+		resolve("authType", &authType, "auth-type", fileConfig.AuthType, fileSource)
+		resolve("username", &username, "username", fileConfig.Username, fileSource)
+		resolve("password", &password, "password", fileConfig.Password, fileSource)
+		// A token takes priority over username/password when set
+		resolve("token", &token, "token", fileConfig.Token, fileSource)
 		// If token is provided, clear username/password defaults to avoid unnecessary login attempts
 		if token != "" {
 			username = ""
 			password = ""
+			settings.Set("username", "", "ignored: a token is set")
+			settings.Set("password", "", "ignored: a token is set")
 		}
+		// AccessKey / SecretKey (aliyun auth)
+		resolve("accessKey", &accessKey, "access-key", fileConfig.AccessKey, fileSource)
+		resolve("secretKey", &secretKey, "secret-key", fileConfig.SecretKey, fileSource)
+		// KMS for cipher- configs, signed with the same AccessKey / SecretKey
+		resolve("kmsEndpoint", &kmsEndpoint, "kms-endpoint", fileConfig.KMSEndpoint, fileSource)
+		resolve("kmsKeyId", &kmsKeyID, "kms-key-id", fileConfig.KMSKeyID, fileSource)
 
-		// AccessKey / SecretKey: command line > config file（AuthType=aliyun 时使用）
-		if accessKey == "" && fileConfig != nil {
-			accessKey = fileConfig.AccessKey
-		}
-		if secretKey == "" && fileConfig != nil {
-			secretKey = fileConfig.SecretKey
+		applyLang(fileConfig.Lang, fileSource)
+		historyFile = fileConfig.HistoryFile
+		applySkillsDir(fileConfig.SkillsDir, fileSource)
+		pollTimeout, err = fileConfig.GetPollTimeout()
+		checkError(err)
+		onChangeHook = fileConfig.OnChange
+		onErrorHook = fileConfig.OnError
+		if aliasFile, err = aliasConfigPath(configFile, profileName); err != nil {
+			aliasFile = ""
 		}
 
-		// Set default server address if still empty
-		if serverAddr == "" {
-			serverAddr = "127.0.0.1:8848"
+		defaultGroup = defaults.Group
+		defaultOutput = defaults.Output
+		defaultPageSize = defaults.PageSize
+		defaultMaxSize = defaults.MaxSize
+		applyFlagDefaults(cmd)
+		recordSettings(cmd, fileConfig, fileSource)
+		for _, setting := range settings.List() {
+			logging.Stderr().Debug("Setting "+setting.Name, "value", setting.Value, "source", setting.Source)
 		}
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		// Default behavior: start interactive terminal
 		nacosClient := mustNewNacosClient()
 		term := terminal.NewTerminal(nacosClient)
+		term.SetHistoryFile(historyFile)
+		term.SetSkillsDir(mustResolveSkillsDir(""))
+		term.SetAliases(loadAliases(aliasFile), aliasFile)
+		term.SetDefaultGroup(defaultGroup)
+		term.SetListDefaults(defaultOutput, defaultPageSize)
+		term.SetMaxConfigSize(maxConfigSize(defaultMaxSize))
+		term.SetPollTimeout(pollTimeout)
+		term.SetSyncHooks(skillsync.Hooks{OnChange: onChangeHook, OnError: onErrorHook})
+		term.SetSyncStatusFile(syncStatusFile())
+		term.SetSettings(settings.List())
 		if err := term.Start(); err != nil {
 			checkError(err)
 		}
@@ -162,6 +249,13 @@ func SetVersionInfo(version, commit, date string) {
 
 // Execute runs the root command
 func Execute() error {
+	ui.SetupConsole()
+	args, err := expandAliasArgs(os.Args[1:])
+	if err != nil {
+		fmt.Fprintln(stderr, i18n.T("Error: %v", err))
+		return err
+	}
+	rootCmd.SetArgs(args)
 	return rootCmd.Execute()
 }
 
@@ -171,6 +265,16 @@ func init() {
 	rootCmd.PersistentFlags().IntVar(&port, "port", 0, "Nacos server port (e.g., 8848)")
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "", "Path to configuration file")
 	rootCmd.PersistentFlags().StringVar(&profileName, "profile", "", "Profile name (e.g., dev, prod). Loads ~/.nacos-cli/<profile>.conf")
+	rootCmd.PersistentFlags().DurationVar(&timeout, "timeout", 0, "Timeout for each HTTP request until its response is read, skill downloads and uploads included (e.g., 30s); 0 means no timeout")
+	rootCmd.PersistentFlags().BoolVar(&noContentValidation, "no-content-validation", false, "Accept config content without the Nacos response headers (e.g. an HTML page from a gateway)")
+	rootCmd.PersistentFlags().BoolVar(&noVerifyMD5, "no-verify-md5", false, "Accept downloads that do not match their Content-MD5 header (for servers that send a wrong one)")
+	rootCmd.PersistentFlags().BoolVar(&noCompress, "no-compress", false, "Do not ask the server to gzip responses (e.g. for a proxy that mangles them)")
+	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Log debug details to stderr: requests, login endpoint, retries, MD5 checks")
+	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "", "Minimum level of log messages: debug, info, warn or error (default: info, or debug with -v)")
+	rootCmd.PersistentFlags().StringVar(&langFlag, "lang", "", "Language of messages: en or zh (default: lang from the config file, else from LANG, else en)")
+	rootCmd.PersistentFlags().BoolVarP(&ui.AssumeYes, "yes", "y", false, "Answer yes to every confirmation prompt")
+	rootCmd.PersistentFlags().BoolVarP(&ui.Quiet, "quiet", "q", false, "Do not show the progress of uploads, downloads and zipping")
+	rootCmd.PersistentFlags().BoolVar(&ui.Interactive, "interactive", true, "Allow prompts; with --interactive=false any prompt is an error (for CI)")
 
 	// Global flags - legacy style (for backward compatibility)
 	rootCmd.PersistentFlags().StringVarP(&serverAddr, "server", "s", "", "Nacos server address (e.g., 127.0.0.1:8848)")
@@ -181,24 +285,277 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&token, "token", "", "Access token (skips username/password login)")
 	rootCmd.PersistentFlags().StringVar(&accessKey, "access-key", "", "AccessKey (aliyun auth)")
 	rootCmd.PersistentFlags().StringVar(&secretKey, "secret-key", "", "SecretKey (aliyun auth)")
+	rootCmd.PersistentFlags().StringVar(&kmsEndpoint, "kms-endpoint", "", "Aliyun KMS endpoint that decrypts cipher- configs (e.g., kms.cn-hangzhou.aliyuncs.com)")
+	rootCmd.PersistentFlags().StringVar(&kmsKeyID, "kms-key-id", "", "KMS key that config-set encrypts cipher- configs with")
 
 	// Mark legacy server flag as deprecated but still functional
 	rootCmd.PersistentFlags().MarkDeprecated("server", "use --host and --port instead")
 }
 
+// stdout and stderr are where commands print: the root command's output and
+// error writers, which tests replace to capture them. stdin is where commands
+// read piped input from; tests replace it to pipe input in. exit ends the
+// process; tests replace it to run commands that fail in process.
+var (
+	stdin  io.Reader = os.Stdin
+	stdout io.Writer = os.Stdout
+	stderr io.Writer = os.Stderr
+	exit             = os.Exit
+)
+
+// exitAuthFailed is the exit code of a failed login, so scripts can tell wrong
+// credentials from other errors
+const exitAuthFailed = 3
+
 func checkError(err error) {
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintln(stderr, i18n.T("Error: %v", err))
+		if commandClient != nil {
+			printNamespaceHint(commandClient, err)
+		}
+		if errors.Is(err, client.ErrLoginFailed) {
+			exit(exitAuthFailed)
+		}
+		exit(1)
 	}
 }
 
-// mustNewNacosClient creates a NacosClient and exits with a clear error message on failure (e.g. login failed).
+// mustResolvePath resolves a path given on the command line with
+// util.ResolvePath, logging the result at debug level, and exits on error
+func mustResolvePath(path string) string {
+	resolved, err := util.ResolvePath(path)
+	checkError(err)
+	logging.Stderr().Debug("Resolved path "+path, "path", resolved)
+	return resolved
+}
+
+// configGroup returns the group argument of config-get/config-set, falling back
+// to defaultGroup from the config file when only the dataId is given.
+func configGroup(args []string) string {
+	if len(args) > 1 {
+		return args[1]
+	}
+	if defaultGroup == "" {
+		checkError(fmt.Errorf("group is required (or set defaults.group in the config file)"))
+	}
+	return defaultGroup
+}
+
+// outputFormatCommands are the commands whose --output is a format rather than
+// a path, so defaults.output applies to them
+var outputFormatCommands = map[string]bool{"skill-list": true, "group-list": true}
+
+// applyFlagDefaults sets --size, --max-size and the format --output of cmd
+// from the defaults of the config file, unless they were given on the
+// command line
+func applyFlagDefaults(cmd *cobra.Command) {
+	if f := cmd.Flags().Lookup("size"); f != nil && !f.Changed && defaultPageSize > 0 {
+		checkError(f.Value.Set(strconv.Itoa(defaultPageSize)))
+	}
+	if f := cmd.Flags().Lookup("output"); f != nil && !f.Changed && defaultOutput != "" && outputFormatCommands[cmd.Name()] {
+		checkError(f.Value.Set(defaultOutput))
+	}
+	if f := cmd.Flags().Lookup("max-size"); f != nil && !f.Changed && defaultMaxSize != "" {
+		checkError(f.Value.Set(defaultMaxSize))
+	}
+}
+
+// applyLogLevel sets the level of every logger from --log-level or -v
+func applyLogLevel() {
+	level := slog.LevelInfo
+	if verbose {
+		level = slog.LevelDebug
+	}
+	if logLevel != "" {
+		var err error
+		level, err = logging.ParseLevel(logLevel)
+		checkError(err)
+	}
+	logging.SetLevel(level)
+}
+
+// applyLang sets the language of messages from --lang, else from the lang of
+// the config file, else from the locale in the environment
+func applyLang(fileLang, fileSource string) {
+	name, source := langFlag, "flag --lang"
+	if name == "" {
+		name, source = fileLang, fileSource
+	}
+	if name == "" {
+		i18n.Set(i18n.FromEnv())
+		settings.Set("lang", string(i18n.Current()), config.SourceEnv)
+		return
+	}
+	lang, err := i18n.Parse(name)
+	checkError(err)
+	i18n.Set(lang)
+	settings.Set("lang", string(lang), source)
+}
+
+// applySkillsDir sets skillsDir from NACOS_SKILLS_DIR, else from the skillsDir
+// of the config file, and records where it came from
+func applySkillsDir(fileDir, fileSource string) {
+	switch env := os.Getenv(config.EnvSkillsDir); {
+	case env != "":
+		skillsDir = env
+		settings.Set("skillsDir", env, config.SourceEnv+" "+config.EnvSkillsDir)
+	case fileDir != "":
+		skillsDir = fileDir
+		settings.Set("skillsDir", fileDir, fileSource)
+	default:
+		skillsDir = ""
+		settings.Set("skillsDir", config.DefaultSkillsDir, config.SourceDefault)
+	}
+}
+
+// resolve fills *value from the config file unless its flag was given, and
+// records where the value came from
+func resolve(name string, value *string, flag, fileValue, fileSource string) {
+	switch {
+	case *value != "":
+		settings.Set(name, *value, "flag --"+flag)
+	case fileValue != "":
+		*value = fileValue
+		settings.Set(name, fileValue, fileSource)
+	default:
+		settings.Set(name, "", config.SourceDefault)
+	}
+}
+
+// recordSettings records the settings that are used as they are resolved
+// elsewhere: the flags without a config file key and the config file keys
+// without a flag
+func recordSettings(cmd *cobra.Command, fileConfig *config.Config, fileSource string) {
+	fromFile := func(set bool) string {
+		if set {
+			return fileSource
+		}
+		return config.SourceDefault
+	}
+	fromFlag := func(name string) string {
+		if cmd.Flags().Changed(name) {
+			return "flag --" + name
+		}
+		return config.SourceDefault
+	}
+
+	settings.Set("timeout", timeout.String(), fromFlag("timeout"))
+	settings.Set("compression", onOff(!noCompress), fromFlag("no-compress"))
+	settings.Set("contentValidation", onOff(!noContentValidation), fromFlag("no-content-validation"))
+	settings.Set("md5Verification", onOff(!noVerifyMD5), fromFlag("no-verify-md5"))
+	effectivePoll := pollTimeout
+	if effectivePoll == 0 {
+		effectivePoll = listener.DefaultPollTimeout
+	}
+	settings.Set("pollTimeout", effectivePoll.String(), fromFile(pollTimeout != 0))
+	history := historyFile
+	if history == "" {
+		history = "~/.nacos-cli/history"
+	}
+	settings.Set("historyFile", history, fromFile(historyFile != ""))
+
+	settings.Set("defaults.group", defaultGroup, fromFile(defaultGroup != ""))
+	settings.Set("defaults.output", defaultOutput, fromFile(defaultOutput != ""))
+	pageSize := ""
+	if defaultPageSize > 0 {
+		pageSize = strconv.Itoa(defaultPageSize)
+	}
+	settings.Set("defaults.pageSize", pageSize, fromFile(defaultPageSize > 0))
+	maxSize := defaultMaxSize
+	if maxSize == "" {
+		maxSize = util.FormatBytes(util.DefaultMaxConfigSize)
+	}
+	settings.Set("defaults.maxSize", maxSize, fromFile(defaultMaxSize != ""))
+}
+
+// onOff shows a boolean setting
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+// commandClient is the client of this invocation, which checkError asks to
+// explain a not-found or forbidden error by the namespace
+var commandClient *client.NacosClient
+
+// printNamespaceHint prints to stderr why a result may be empty (err is nil)
+// or an error may be not found or forbidden because of the namespace: that
+// it does not exist or is not accessible. Other errors get no hint.
+func printNamespaceHint(c *client.NacosClient, err error) {
+	if err != nil && !errors.Is(err, client.ErrConfigNotFound) && !errors.Is(err, client.ErrForbidden) {
+		return
+	}
+	if hint := c.NamespaceHint(errors.Is(err, client.ErrForbidden)); hint != "" {
+		fmt.Fprintln(stderr, i18n.T("Hint: %s", hint))
+	}
+}
+
+// mustNewNacosClient creates a NacosClient and exits with a clear error message on failure.
+// It logs in lazily: the first request does, and checkError exits with
+// exitAuthFailed if that fails.
 func mustNewNacosClient() *client.NacosClient {
 	c, err := client.NewNacosClient(serverAddr, namespace, authType, username, password, accessKey, secretKey, token)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+	checkError(err)
+	c.SetTimeout(timeout)
+	c.SetContentValidation(!noContentValidation)
+	c.SetMD5Verification(!noVerifyMD5)
+	c.SetCompression(!noCompress)
+	if kmsEndpoint != "" {
+		cipher, err := kms.NewClient(kmsEndpoint, kmsKeyID, accessKey, secretKey)
+		checkError(err)
+		c.SetCipher(cipher)
 	}
+	commandClient = c
 	return c
 }
+
+// mustLogin logs in before a command sends many requests, so wrong credentials
+// are reported once, with exitAuthFailed, instead of by every request. Other
+// errors are left to the requests, which report or retry them.
+func mustLogin(c *client.NacosClient) {
+	if err := c.EnsureTokenValid(); errors.Is(err, client.ErrLoginFailed) {
+		checkError(err)
+	}
+}
+
+// reportUsage describes --report for the batch commands that take it
+const reportUsage = "Write a JSON report of each item (status, error, duration, bytes, request ids) and the totals to this file, for CI"
+
+// requestTrail collects the requests clients send, for the --report entry of
+// the item a batch command is working on
+type requestTrail struct {
+	mu       sync.Mutex
+	requests []report.Request
+}
+
+// follow makes the trail record the requests of c
+func (t *requestTrail) follow(c *client.NacosClient) {
+	c.SetRequestHandler(func(r client.Request) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.requests = append(t.requests, report.Request{Method: r.Method, Path: r.Path, Status: r.Status, ID: r.ID, ServerID: r.ServerID})
+	})
+}
+
+// take returns the requests recorded since the last take
+func (t *requestTrail) take() []report.Request {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	requests := t.requests
+	t.requests = nil
+	return requests
+}
+
+// writeReport saves the report of a batch command to path, given by
+// --report; without it nothing is written. Failing to write it is an error,
+// as CI would miss the results.
+func writeReport(r *report.Report, path string) {
+	if path == "" {
+		return
+	}
+	if err := r.Write(path); err != nil {
+		checkError(fmt.Errorf("write --report: %w", err))
+	}
+}
@@ -0,0 +1,153 @@
+package cmd
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/nacostest"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// stubConfigs are what newNacosStub serves, and stubSkills what
+// newSkillStub serves
+var (
+	stubConfigs = []struct{ dataID, group, typ, content string }{
+		{"app.yaml", "DEFAULT_GROUP", "yaml", "server:\n  port: 8080\n"},
+		{"db.properties", "DEFAULT_GROUP", "properties", "url=jdbc:mysql://db/app\n"},
+		{"feature-flags.json", "team", "json", `{"beta":true}`},
+	}
+	stubSkills = []struct{ name, description string }{
+		{"code-review", "Review a change for bugs and style"},
+		{"release-notes", "Draft release notes from merged pull requests"},
+	}
+)
+
+// newNacosStub starts a fake Nacos server that serves stubConfigs
+func newNacosStub(t *testing.T) *nacostest.Server {
+	t.Helper()
+	s := nacostest.NewServer(t)
+	for _, c := range stubConfigs {
+		s.SetConfig(c.dataID, c.group, c.content)
+		s.SetConfigType(c.dataID, c.group, c.typ)
+	}
+	return s
+}
+
+// newSkillStub starts a fake Nacos server that serves stubSkills. They are
+// kept off newNacosStub, whose config list would show their configs.
+func newSkillStub(t *testing.T) *nacostest.Server {
+	t.Helper()
+	s := nacostest.NewServer(t)
+	for _, sk := range stubSkills {
+		s.SetSkill(sk.name, map[string]string{
+			"SKILL.md": fmt.Sprintf("---\nname: %s\ndescription: %s\n---\n", sk.name, sk.description),
+		})
+	}
+	return s
+}
+
+// requestID matches the id the client sends with each request
+var requestID = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+
+// exitCode is what the exit of runCommand panics with
+type exitCode int
+
+// runCommand runs nacos-cli in process against the server at addr and
+// returns a transcript of what it printed and its exit code, with addr
+// replaced by NACOS and request ids by REQUEST-ID so the transcript does not
+// change between runs
+func runCommand(t *testing.T, addr string, args ...string) string {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("LC_ALL", "C")
+	t.Setenv("NO_COLOR", "1")
+	resetFlags(rootCmd)
+	commandClient = nil
+
+	var out, errOut bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetErr(&errOut)
+	rootCmd.SetArgs(append([]string{"--host", addr}, args...))
+	exit = func(code int) { panic(exitCode(code)) }
+	defer func() {
+		rootCmd.SetOut(nil)
+		rootCmd.SetErr(nil)
+		stdin, stdout, stderr, exit = os.Stdin, os.Stdout, os.Stderr, os.Exit
+	}()
+
+	code := func() (code exitCode) {
+		defer func() {
+			if r := recover(); r != nil {
+				var ok bool
+				if code, ok = r.(exitCode); !ok {
+					panic(r)
+				}
+			}
+		}()
+		if err := rootCmd.Execute(); err != nil {
+			return 1
+		}
+		return 0
+	}()
+
+	transcript := fmt.Sprintf("$ nacos-cli %s\nexit status %d\n-- stdout --\n%s-- stderr --\n%s",
+		strings.Join(args, " "), code, out.String(), errOut.String())
+	transcript = requestID.ReplaceAllString(transcript, "REQUEST-ID")
+	return strings.ReplaceAll(transcript, addr, "NACOS")
+}
+
+// resetFlags sets every flag of c and its subcommands back to its default,
+// as a new process would start with
+func resetFlags(c *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		if slice, ok := f.Value.(pflag.SliceValue); ok {
+			slice.Replace(nil)
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	c.PersistentFlags().VisitAll(reset)
+	c.Flags().VisitAll(reset)
+	for _, sub := range c.Commands() {
+		resetFlags(sub)
+	}
+}
+
+// checkGolden compares got with testdata/<name>.golden, or rewrites the
+// file with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	file := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.MkdirAll("testdata", 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("%v (run go test -update to create it)", err)
+	}
+	if got != string(want) {
+		t.Errorf("output differs from %s:\n--- got\n%s\n--- want\n%s", file, got, want)
+	}
+}
+
+func TestLoginFailure(t *testing.T) {
+	s := newNacosStub(t)
+	s.RequireLogin("nacos", "secret")
+	checkGolden(t, "login_failure", runCommand(t, s.Addr, "--username", "nacos", "--password", "wrong", "config-list"))
+}
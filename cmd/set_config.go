@@ -1,68 +1,295 @@
 package cmd
 
 import (
-	"bufio"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/clipboard"
 	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+	"github.com/nacos-group/nacos-cli/internal/lint"
+	"github.com/nacos-group/nacos-cli/internal/render"
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/nacos-group/nacos-cli/internal/util"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
-var setConfigFile string
+var (
+	setConfigFile    string
+	setConfigRender  bool
+	setConfigVars    []string
+	setConfigVarFile string
+	setConfigDryRun  bool
+	setConfigFromURL string
+	setConfigHeaders []string
+	setConfigSHA256  string
+	setConfigEdit    bool
+	setConfigClip    bool
+	setConfigMaxSize string
+	setConfigForce   bool
+	setConfigBinary  bool
+	setConfigShrink  bool
+	setConfigNoLint  bool
+	setConfigTimeout time.Duration
+)
 
 var setConfigCmd = &cobra.Command{
 	Use:   "config-set [dataId] [group]",
 	Short: "Publish a configuration to Nacos",
 	Long:  help.ConfigSet.FormatForCLI("nacos-cli"),
-	Args:  cobra.ExactArgs(2),
+	Args:  cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
 		dataID := args[0]
-		group := args[1]
+		group := configGroup(args)
+
+		if setConfigEdit {
+			runEditSetConfig(dataID, group)
+			return
+		}
 
 		content, err := readSetConfigContent()
 		checkError(err)
 
 		if content == "" {
-			fmt.Fprintf(os.Stderr, "Error: config content is empty (use --file or stdin)\n")
-			os.Exit(1)
+			fmt.Fprintf(stderr, "Error: config content is empty (use --file or stdin)\n")
+			exit(1)
+		}
+
+		if setConfigRender {
+			content, err = renderSetConfigContent(content)
+			checkError(err)
+		} else if len(setConfigVars) > 0 || setConfigVarFile != "" {
+			fmt.Fprintf(stderr, "Warning: --var/--var-file have no effect without --render\n")
+		}
+		validateSetConfigContent(dataID, setConfigFile, content)
+
+		if setConfigDryRun {
+			fmt.Fprint(stdout, content)
+			return
 		}
 
 		// Create Nacos client
 		nacosClient := mustNewNacosClient()
 
-		fmt.Printf("Publishing config: %s (%s)...\n", dataID, group)
+		warnings := checkSetConfigContent(nacosClient, dataID, group, content)
+		if (setConfigClip || len(warnings) > 0) && !confirmSetConfig(dataID, group, content, warnings) {
+			fmt.Fprintln(stdout, "Aborted, nothing published")
+			return
+		}
+
+		fmt.Fprintf(stdout, "Publishing config: %s (%s)...\n", dataID, group)
 		err = nacosClient.PublishConfig(dataID, group, content)
 		checkError(err)
 
-		fmt.Println("Configuration published successfully")
+		fmt.Fprintln(stdout, "Configuration published successfully")
 	},
 }
 
+// runEditSetConfig edits the remote content in $EDITOR like config-edit,
+// starting from empty content if the config does not exist.
+func runEditSetConfig(dataID, group string) {
+	if setConfigFile != "" || setConfigFromURL != "" || setConfigClip || setConfigRender {
+		checkError(fmt.Errorf("--edit cannot be combined with --file, --from-url, --from-clipboard or --render"))
+	}
+	runEditConfig(dataID, group, configEdit{create: true, noValidate: setConfigNoLint, dryRun: setConfigDryRun})
+}
+
 func readSetConfigContent() (string, error) {
+	if setConfigClip {
+		if setConfigFile != "" || setConfigFromURL != "" || setConfigSHA256 != "" || len(setConfigHeaders) > 0 {
+			return "", fmt.Errorf("--from-clipboard cannot be combined with --file, --from-url, --sha256 or --url-header")
+		}
+		return clipboard.Read()
+	}
+	if setConfigFromURL != "" {
+		if setConfigFile != "" {
+			return "", fmt.Errorf("--file and --from-url cannot be used together")
+		}
+		fmt.Fprintf(stderr, "Downloading config from %s...\n", redactURL(setConfigFromURL))
+		data, err := util.FetchURL(setConfigFromURL, setConfigHeaders, timeout, setConfigSHA256)
+		if err != nil {
+			return "", err
+		}
+		return string(data), nil
+	}
+	if setConfigSHA256 != "" || len(setConfigHeaders) > 0 {
+		return "", fmt.Errorf("--sha256 and --url-header require --from-url")
+	}
 	if setConfigFile != "" {
-		data, err := os.ReadFile(setConfigFile)
+		path := mustResolvePath(setConfigFile)
+		data, err := os.ReadFile(path)
 		if err != nil {
-			return "", fmt.Errorf("read file %s: %w", setConfigFile, err)
+			return "", fmt.Errorf("read file %s: %w", path, err)
 		}
 		return string(data), nil
 	}
-	// Read from stdin
-	var content string
-	scanner := bufio.NewScanner(os.Stdin)
-	for scanner.Scan() {
-		if content != "" {
-			content += "\n"
+	data, err := readStdin(maxConfigSize(setConfigMaxSize), setConfigTimeout)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// readStdin reads content from stdin as is, line endings and all. On a
+// terminal it says how to finish first, so that a forgotten -f does not look
+// like a hang. It fails once more than max bytes arrive (unless --force is
+// given), or if the input has not ended after timeout, if that is not 0.
+func readStdin(max int64, timeout time.Duration) ([]byte, error) {
+	if f, ok := stdin.(*os.File); ok && term.IsTerminal(int(f.Fd())) {
+		fmt.Fprintln(stderr, i18n.T("Reading from stdin — press Ctrl+D to finish (or use -f)"))
+	}
+	r := stdin
+	if !setConfigForce {
+		r = io.LimitReader(r, max+1)
+	}
+	type result struct {
+		data []byte
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		data, err := io.ReadAll(r)
+		done <- result{data, err}
+	}()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, fmt.Errorf("read stdin: %w", res.err)
+		}
+		if !setConfigForce && int64(len(res.data)) > max {
+			return nil, fmt.Errorf("stdin has more than the limit of %s: check what is piped in, or pass --force to publish it anyway (the limit is --max-size or defaults.maxSize)",
+				util.FormatBytes(max))
 		}
-		content += scanner.Text()
+		return res.data, nil
+	case <-expired:
+		// The read goes on in the background until the command exits
+		return nil, fmt.Errorf("stdin did not end within %s (--stdin-timeout): pipe the content in, or use -f", timeout)
 	}
-	if err := scanner.Err(); err != nil {
-		return "", fmt.Errorf("read stdin: %w", err)
+}
+
+// validateSetConfigContent exits with the problems found in content when it
+// does not parse as the type of dataId, or else of the file it was read from,
+// unless --no-validate is given. Content of other types is not checked.
+func validateSetConfigContent(dataID, file, content string) {
+	if setConfigNoLint {
+		return
+	}
+	typ := lint.DetectType(dataID, "")
+	if typ == "" {
+		typ = lint.DetectType(file, "")
+	}
+	if !printLintProblems(dataID, lint.Check(typ, content)) {
+		checkError(fmt.Errorf("content is not valid %s, nothing published (pass --no-validate to publish it anyway)", typ))
+	}
+}
+
+// checkSetConfigContent exits if content is over --max-size, unless --force
+// is given, and returns warnings about content that looks binary or is much
+// smaller than the current content (see util.PublishCheck)
+func checkSetConfigContent(c *client.NacosClient, dataID, group, content string) []string {
+	check := util.PublishCheck{
+		MaxSize:     maxConfigSize(setConfigMaxSize),
+		Force:       setConfigForce,
+		AllowBinary: setConfigBinary,
+		AllowShrink: setConfigShrink,
+	}
+	if _, err := check.Check(content, ""); err != nil {
+		checkError(err)
+	}
+	var previous string
+	if !setConfigShrink {
+		var err error
+		previous, err = c.GetConfig(dataID, group)
+		if err != nil && !errors.Is(err, client.ErrConfigNotFound) {
+			checkError(fmt.Errorf("cannot compare with the current content (pass --allow-shrink to publish without comparing): %w", err))
+		}
+	}
+	warnings, err := check.Check(content, previous)
+	checkError(err)
+	return warnings
+}
+
+// maxConfigSize parses --max-size or defaults.maxSize; empty means
+// util.DefaultMaxConfigSize
+func maxConfigSize(size string) int64 {
+	if size == "" {
+		return util.DefaultMaxConfigSize
+	}
+	n, err := util.ParseSize(size)
+	checkError(err)
+	return n
+}
+
+// confirmSetConfig shows the first and last lines of content read from the
+// clipboard and the warnings about content, and asks whether to publish it
+func confirmSetConfig(dataID, group, content string, warnings []string) bool {
+	if setConfigClip {
+		fmt.Fprintln(stdout, "Clipboard content:")
+		for _, line := range clipboard.Preview(content) {
+			fmt.Fprintln(stdout, line)
+		}
+	}
+	for _, warning := range warnings {
+		fmt.Fprintln(stderr, i18n.T("Warning: %s", warning))
+	}
+	ok, err := ui.Confirm(fmt.Sprintf("Publish this to %s (%s)?", dataID, group), true)
+	checkError(err)
+	return ok
+}
+
+// redactURL hides any userinfo password embedded in a URL before it is displayed.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "(invalid url)"
+	}
+	return u.Redacted()
+}
+
+// renderSetConfigContent renders content as a Go template using --var-file and --var values.
+func renderSetConfigContent(content string) (string, error) {
+	vars, err := render.LoadVars(setConfigVarFile, setConfigVars)
+	if err != nil {
+		return "", err
+	}
+	name := "stdin"
+	if setConfigFile != "" {
+		name = filepath.Base(setConfigFile)
+	} else if setConfigClip {
+		name = "clipboard"
 	}
-	return content, nil
+	return render.Render(name, content, vars)
 }
 
 func init() {
 	setConfigCmd.Flags().StringVarP(&setConfigFile, "file", "f", "", "Path to config file (default: read from stdin)")
+	setConfigCmd.Flags().BoolVar(&setConfigRender, "render", false, "Render content as a Go template before publishing")
+	setConfigCmd.Flags().StringArrayVar(&setConfigVars, "var", nil, "Template variable key=value (repeatable, overrides --var-file)")
+	setConfigCmd.Flags().StringVar(&setConfigVarFile, "var-file", "", "YAML file with template variables")
+	setConfigCmd.Flags().StringVar(&setConfigFromURL, "from-url", "", "Fetch config content from an http(s) URL")
+	setConfigCmd.Flags().BoolVar(&setConfigClip, "from-clipboard", false, "Read config content from the system clipboard and confirm it before publishing")
+	setConfigCmd.Flags().StringArrayVar(&setConfigHeaders, "url-header", nil, "Header for --from-url as 'Name: value' (repeatable)")
+	setConfigCmd.Flags().StringVar(&setConfigSHA256, "sha256", "", "Expected SHA-256 checksum (hex) of the --from-url content")
+	setConfigCmd.Flags().DurationVar(&setConfigTimeout, "stdin-timeout", 0, "Fail if stdin has not ended after this long, e.g. 30s (default: wait)")
+	setConfigCmd.Flags().StringVar(&setConfigMaxSize, "max-size", "", "Refuse content larger than this, e.g. 5MB (default: defaults.maxSize, else 1MB)")
+	setConfigCmd.Flags().BoolVar(&setConfigForce, "force", false, "Publish content larger than --max-size")
+	setConfigCmd.Flags().BoolVar(&setConfigBinary, "allow-binary", false, "Publish content that looks binary without asking")
+	setConfigCmd.Flags().BoolVar(&setConfigShrink, "allow-shrink", false, "Publish content much smaller than the current content without asking, and without fetching it")
+	setConfigCmd.Flags().BoolVar(&setConfigNoLint, "no-validate", false, "Publish yaml, json, properties or xml content that does not parse (see config-lint)")
+	setConfigCmd.Flags().BoolVar(&setConfigDryRun, "dry-run", false, "Print the content that would be published without publishing")
+	setConfigCmd.Flags().BoolVar(&setConfigEdit, "edit", false, "Edit the current remote content in $EDITOR, review the diff, then publish")
 	rootCmd.AddCommand(setConfigCmd)
 }
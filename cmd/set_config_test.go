@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestConfigSetValidation(t *testing.T) {
+	s := newNacosStub(t)
+	tests := []struct {
+		golden string
+		args   []string
+	}{
+		{"config_set_invalid", []string{"config-set", "app.yaml", "DEFAULT_GROUP", "-f", "testdata/duplicate_keys.yaml"}},
+		{"config_set_no_validate", []string{"config-set", "app.yaml", "DEFAULT_GROUP", "-f", "testdata/duplicate_keys.yaml", "--no-validate"}},
+		{"config_lint", []string{"config-lint", "-f", "testdata/duplicate_keys.yaml"}},
+		{"config_lint_json", []string{"config-lint", "-f", "testdata/duplicate_keys.yaml", "--type", "json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.golden, func(t *testing.T) {
+			checkGolden(t, tt.golden, runCommand(t, s.Addr, tt.args...))
+		})
+	}
+
+	t.Run("config_lint_stdin", func(t *testing.T) {
+		stdin = strings.NewReader("server:\n  port: 8080\n  port: 9090\n")
+		checkGolden(t, "config_lint_stdin", runCommand(t, s.Addr, "config-lint", "--type", "yaml"))
+	})
+}
+
+func TestConfigSetStdin(t *testing.T) {
+	s := newNacosStub(t)
+	longLine := `{"key":"` + strings.Repeat("x", 100<<10) + `"}`
+	for _, content := range []string{"a: 1\n", "a: 1", "a: 1\r\nb: 2\r\n", longLine} {
+		stdin = strings.NewReader(content)
+		if out := runCommand(t, s.Addr, "config-set", "app.json", "DEFAULT_GROUP", "--no-validate", "--allow-shrink"); !strings.Contains(out, "exit status 0") {
+			t.Fatalf("config-set failed:\n%s", out)
+		}
+		if got, _ := s.Config("app.json", "DEFAULT_GROUP"); got != content {
+			t.Errorf("published %.40q, want %.40q", got, content)
+		}
+	}
+
+	stdin = strings.NewReader(strings.Repeat("a", 2048))
+	out := runCommand(t, s.Addr, "config-set", "app.txt", "DEFAULT_GROUP", "--max-size", "1KB")
+	if !strings.Contains(out, "exit status 1") || !strings.Contains(out, "stdin has more than the limit of 1.0 KB") {
+		t.Errorf("config-set over --max-size:\n%s", out)
+	}
+
+	r, w := io.Pipe()
+	defer w.Close()
+	stdin = r
+	out = runCommand(t, s.Addr, "config-set", "app.txt", "DEFAULT_GROUP", "--stdin-timeout", "50ms")
+	if !strings.Contains(out, "exit status 1") || !strings.Contains(out, "stdin did not end within 50ms") {
+		t.Errorf("config-set with nothing piped in:\n%s", out)
+	}
+}
+
+func TestConfigRoundTrip(t *testing.T) {
+	s := newNacosStub(t)
+	for _, tt := range []struct{ name, content string }{
+		{"trailing newline", "a: 1\nb: 2\n"},
+		{"no trailing newline", "a: 1\nb: 2"},
+		{"crlf", "a: 1\r\nb: 2\r\n"},
+		{"bom", "\ufeffa: 1\nb: 2\n"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "app.yaml")
+			if err := os.WriteFile(file, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			for _, args := range [][]string{{"-f", file}, nil} {
+				s.DeleteConfig("app.yaml", "DEFAULT_GROUP")
+				stdin = strings.NewReader(tt.content)
+				runCommand(t, s.Addr, append([]string{"config-set", "app.yaml", "DEFAULT_GROUP"}, args...)...)
+				if got, _ := s.Config("app.yaml", "DEFAULT_GROUP"); got != tt.content {
+					t.Errorf("config-set %v published %q, want %q", args, got, tt.content)
+				}
+			}
+
+			out := runCommand(t, s.Addr, "config-get", "app.yaml", "DEFAULT_GROUP")
+			_, out, _ = strings.Cut(out, "-- stdout --\n")
+			if got, _, _ := strings.Cut(out, "-- stderr --\n"); got != tt.content {
+				t.Errorf("config-get printed %q, want %q", got, tt.content)
+			}
+			saved := filepath.Join(t.TempDir(), "saved.yaml")
+			runCommand(t, s.Addr, "config-get", "app.yaml", "DEFAULT_GROUP", "--output-file", saved)
+			if got, err := os.ReadFile(saved); err != nil || string(got) != tt.content {
+				t.Errorf("config-get --output-file saved %q, %v; want %q", got, err, tt.content)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
+	"github.com/nacos-group/nacos-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncConfigMaps          []string
+	syncConfigFile          string
+	syncConfigReload        string
+	syncConfigOnDelete      string
+	syncConfigPollTimeout   time.Duration
+	syncConfigInterval      time.Duration
+	syncConfigReloadTimeout time.Duration
+	syncConfigBatchSize     int
+	syncConfigLogFile       string
+	syncConfigLogFormat     string
+)
+
+var syncConfigCmd = &cobra.Command{
+	Use:   "config-sync --map dataId:group:path... | -f <mappingFile>",
+	Short: "Keep configs mirrored to local files",
+	Long:  help.ConfigSync.FormatForCLI("nacos-cli"),
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		mappings, err := configMappings(syncConfigMaps, syncConfigFile, syncConfigReload)
+		checkError(err)
+		if cmd.Flags().Changed("poll-timeout") {
+			checkError(validatePollTimeout(syncConfigPollTimeout))
+			pollTimeout = syncConfigPollTimeout
+		}
+		checkError(validatePollInterval(syncConfigInterval))
+		checkError(validateLogFormat(syncConfigLogFormat))
+		if syncConfigReloadTimeout <= 0 {
+			checkError(fmt.Errorf("--reload-timeout must be positive"))
+		}
+		if syncConfigBatchSize <= 0 {
+			checkError(fmt.Errorf("--batch-size must be positive"))
+		}
+
+		logger, closeLog := syncLogger(syncConfigLogFile, syncConfigLogFormat, false)
+		defer closeLog()
+
+		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
+		syncer := skillsync.NewConfigSyncer(nacosClient, mappings, logger)
+		checkError(syncer.SetOnDelete(syncConfigOnDelete))
+		syncer.SetPollTimeout(pollTimeout)
+		syncer.SetPollInterval(syncConfigInterval)
+		syncer.SetBatchSize(syncConfigBatchSize)
+		syncer.SetReloadTimeout(syncConfigReloadTimeout)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		fmt.Fprintln(stderr, "Press Ctrl+C to stop synchronization")
+		checkError(syncer.Run(ctx))
+	},
+}
+
+// configMappings collects the mappings of --map and the mapping file. An empty
+// group falls back to defaultGroup, an empty reload command to reload, and ~
+// in paths is expanded.
+func configMappings(maps []string, file, reload string) ([]skillsync.ConfigMapping, error) {
+	var mappings []skillsync.ConfigMapping
+	for _, s := range maps {
+		m, err := skillsync.ParseConfigMapping(s)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, m)
+	}
+	if file != "" {
+		path, err := util.ResolvePath(file)
+		if err != nil {
+			return nil, err
+		}
+		fromFile, err := skillsync.LoadConfigMappings(path)
+		if err != nil {
+			return nil, err
+		}
+		mappings = append(mappings, fromFile...)
+	}
+	if len(mappings) == 0 {
+		return nil, fmt.Errorf("specify configs with --map dataId:group:path or a mapping file with -f")
+	}
+
+	paths := make(map[string]bool)
+	for i := range mappings {
+		m := &mappings[i]
+		if m.Group == "" {
+			if defaultGroup == "" {
+				return nil, fmt.Errorf("group of %s is required (or set defaultGroup in the config file)", m.DataID)
+			}
+			m.Group = defaultGroup
+		}
+		if m.Reload == "" {
+			m.Reload = reload
+		}
+		path, err := util.ResolvePath(m.Path)
+		if err != nil {
+			return nil, err
+		}
+		m.Path = path
+		if paths[path] {
+			return nil, fmt.Errorf("%s is mapped more than once", path)
+		}
+		paths[path] = true
+	}
+	return mappings, nil
+}
+
+func init() {
+	syncConfigCmd.Flags().StringArrayVar(&syncConfigMaps, "map", nil, "Mirror a config to a file, as dataId:group:path (repeatable; an empty group means defaultGroup)")
+	syncConfigCmd.Flags().StringVarP(&syncConfigFile, "file", "f", "", "YAML file with a 'configs' list of dataId, group, path and reload")
+	syncConfigCmd.Flags().StringVar(&syncConfigReload, "reload", "", "Command to run after a file is rewritten or removed, for mappings without their own")
+	syncConfigCmd.Flags().StringVar(&syncConfigOnDelete, "on-delete", skillsync.OnDeleteKeep, "What to do with the file of a config deleted in Nacos: keep or delete")
+	syncConfigCmd.Flags().DurationVar(&syncConfigReloadTimeout, "reload-timeout", skillsync.DefaultHookTimeout, "Kill a reload command that runs longer than this")
+	syncConfigCmd.Flags().IntVar(&syncConfigBatchSize, "batch-size", listener.DefaultBatchSize, "How many configs each concurrent poller watches")
+	syncConfigCmd.Flags().StringVar(&syncConfigLogFile, "log-file", "", "Also write structured log entries to this file (rotated at 10MB, 3 old files kept)")
+	syncConfigCmd.Flags().StringVar(&syncConfigLogFormat, "log-format", logging.FormatText, "Format of --log-file: text or json")
+	syncConfigCmd.Flags().DurationVar(&syncConfigInterval, "poll-interval", listener.PollInterval, "How often each config is fetched to check for changes")
+	syncConfigCmd.Flags().DurationVar(&syncConfigPollTimeout, "poll-timeout", 0, "How long each poll for changes may take (default: pollTimeout from the config file, or 30s)")
+	rootCmd.AddCommand(syncConfigCmd)
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/config"
+	"github.com/nacos-group/nacos-cli/internal/daemon"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// daemonStopTimeout is how long skill-sync stop waits for a clean shutdown
+	daemonStopTimeout = 30 * time.Second
+	// defaultMaxStaleness is how old the sync heartbeat may get before
+	// skill-sync status fails; polls back off to 2 minutes apart while the
+	// server is down, which still counts as progress
+	defaultMaxStaleness = 5 * time.Minute
+)
+
+var syncSkillMaxStaleness time.Duration
+
+var syncSkillStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the skill-sync daemon and sync heartbeat of the current profile",
+	Long: `Show the skill-sync daemon of the current profile and the heartbeat that
+skill-sync writes after every poll cycle. Exits with status 1 when there is
+no heartbeat or it is older than --max-staleness, so it can back an alert.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if syncSkillMaxStaleness <= 0 {
+			checkError(fmt.Errorf("--max-staleness must be positive"))
+		}
+		printDaemonStatus()
+		fmt.Fprintln(stdout)
+
+		path := syncStatusFile()
+		status, err := skillsync.ReadStatus(path)
+		if err != nil {
+			fmt.Fprintf(stdout, "No sync heartbeat: %v\n", err)
+			exit(1)
+		}
+		fmt.Fprintf(stdout, "Heartbeat (%s):\n", path)
+		status.Print(stdout)
+		if age := time.Since(status.UpdatedAt); age > syncSkillMaxStaleness {
+			fmt.Fprintf(stdout, "\nHeartbeat is stale: last poll cycle %s ago, more than %s\n", age.Round(time.Second), syncSkillMaxStaleness)
+			exit(1)
+		}
+	},
+}
+
+// printDaemonStatus prints whether the daemon of the current profile runs and what it last did
+func printDaemonStatus() {
+	files := daemonFiles()
+	pid, running := files.Running()
+	state, err := daemon.ReadState(files)
+	if !running {
+		fmt.Fprintln(stdout, "skill-sync daemon is not running")
+		if err == nil {
+			fmt.Fprintf(stdout, "Last ran as pid %d, state updated %s\n", state.PID, state.UpdatedAt.Format(time.DateTime))
+		}
+		fmt.Fprintf(stdout, "Log: %s\n", files.Log)
+		return
+	}
+	if err != nil {
+		fmt.Fprintf(stdout, "skill-sync daemon is running (pid %d) but its state is unreadable: %v\n", pid, err)
+		return
+	}
+
+	fmt.Fprintf(stdout, "skill-sync daemon running (pid %d, up %s)\n", pid, time.Since(state.StartedAt).Round(time.Second))
+	fmt.Fprintf(stdout, "Command: nacos-cli %s\n", state.Command)
+	fmt.Fprintf(stdout, "State updated: %s ago\n", time.Since(state.UpdatedAt).Round(time.Second))
+	fmt.Fprintf(stdout, "Log: %s\n", files.Log)
+}
+
+// syncStatusFile returns the heartbeat file of the current profile:
+// ~/.nacos-cli/sync-status.json, or sync-status-<profile>.json for other profiles
+func syncStatusFile() string {
+	dir, err := config.GetConfigDir()
+	checkError(err)
+	if profileName == "" || profileName == config.DefaultProfile {
+		return filepath.Join(dir, "sync-status.json")
+	}
+	return filepath.Join(dir, "sync-status-"+profileName+".json")
+}
+
+var syncSkillStopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "Stop the skill-sync daemon of the current profile",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		pid, err := daemon.Stop(daemonFiles(), daemonStopTimeout)
+		checkError(err)
+		fmt.Fprintf(stdout, "Stopped skill-sync daemon (pid %d)\n", pid)
+	},
+}
+
+// daemonFiles returns the daemon files of the current profile under ~/.nacos-cli
+func daemonFiles() daemon.Files {
+	dir, err := config.GetConfigDir()
+	checkError(err)
+	profile := profileName
+	if profile == "" {
+		profile = config.DefaultProfile
+	}
+	return daemon.FilesFor(dir, profile)
+}
+
+// startSkillSyncDaemon starts skill-sync again as a background process with
+// the same arguments and returns once it is running
+func startSkillSyncDaemon() {
+	files := daemonFiles()
+	// The daemon cannot answer prompts
+	args := append(os.Args[1:], "--interactive=false")
+	pid, err := daemon.Start(files, args)
+	checkError(err)
+	fmt.Fprintf(stdout, "skill-sync daemon started (pid %d)\n", pid)
+	fmt.Fprintf(stdout, "  Log: %s\n", files.Log)
+	fmt.Fprintln(stdout, "  Use 'nacos-cli skill-sync status' and 'nacos-cli skill-sync stop' to manage it")
+}
+
+// recordDaemonState writes the pidfile and makes syncer keep the state file
+// current. The returned function removes the pidfile on shutdown.
+func recordDaemonState(syncer *skillsync.SkillSyncer, skillNames []string, logger *slog.Logger) func() {
+	files := daemonFiles()
+	recorder, err := daemon.NewRecorder(files, strings.Join(os.Args[1:], " "), skillNames)
+	checkError(err)
+	syncer.SetEventHandler(func(skill, event string) {
+		if err := recorder.Record(skill, event); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to update daemon state: %v", err), "error", err)
+		}
+	})
+	return func() {
+		if err := recorder.Close(); err != nil {
+			logger.Warn(fmt.Sprintf("Failed to remove pidfile: %v", err), "error", err)
+		}
+	}
+}
+
+func init() {
+	syncSkillStatusCmd.Flags().DurationVar(&syncSkillMaxStaleness, "max-staleness", defaultMaxStaleness, "Exit with status 1 when the last poll cycle is older than this")
+	syncSkillCmd.AddCommand(syncSkillStatusCmd)
+	syncSkillCmd.AddCommand(syncSkillStopCmd)
+}
@@ -0,0 +1,310 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/config"
+	"github.com/nacos-group/nacos-cli/internal/daemon"
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
+	"github.com/nacos-group/nacos-cli/internal/util"
+	"github.com/spf13/cobra"
+)
+
+var (
+	syncSkillAll         bool
+	syncSkillOutput      string
+	syncSkillPollTimeout time.Duration
+	syncSkillInterval    time.Duration
+	syncSkillPush        bool
+	syncSkillForceRemote bool
+	syncSkillForceSync   bool
+	syncSkillListenEarly bool
+	syncSkillExec        bool
+	syncSkillVerboseDiff bool
+	syncSkillDaemon      bool
+	syncSkillLogFile     string
+	syncSkillLogFormat   string
+	syncSkillOnChange    string
+	syncSkillOnError     string
+	syncSkillHookTimeout time.Duration
+	syncSkillBatchSize   int
+	syncSkillInitWorkers int
+	syncSkillBandwidth   string
+	syncSkillJitter      time.Duration
+	syncSkillPins        []string
+	syncSkillMaps        []string
+	syncSkillMapFile     string
+	syncSkillInclude     []string
+	syncSkillExclude     []string
+	syncSkillCatalog     string
+	syncSkillDebounce    time.Duration
+)
+
+var syncSkillCmd = &cobra.Command{
+	Use:   "skill-sync [skillName...] | --push <skillDir...>",
+	Short: "Keep local skills in sync with Nacos",
+	Long:  help.SkillSync.FormatForCLI("nacos-cli"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if syncSkillPush {
+			runSkillPush(cmd, args)
+			return
+		}
+		if len(args) == 0 && !syncSkillAll && syncSkillCatalog == "" {
+			fmt.Fprintf(stderr, "Error: specify skill names, --all or --catalog\n")
+			exit(1)
+		}
+		if syncSkillCatalog != "" && (len(args) > 0 || syncSkillAll) {
+			checkError(fmt.Errorf("--catalog cannot be used with skill names or --all"))
+		}
+
+		outputDir, err := resolveSkillsDir(syncSkillOutput)
+		checkError(err)
+		if cmd.Flags().Changed("poll-timeout") {
+			checkError(validatePollTimeout(syncSkillPollTimeout))
+			pollTimeout = syncSkillPollTimeout
+		}
+		checkError(validatePollInterval(syncSkillInterval))
+		checkError(validateLogFormat(syncSkillLogFormat))
+		if syncSkillHookTimeout <= 0 {
+			checkError(fmt.Errorf("--hook-timeout must be positive"))
+		}
+		if syncSkillBatchSize <= 0 {
+			checkError(fmt.Errorf("--batch-size must be positive"))
+		}
+		if syncSkillInitWorkers <= 0 {
+			checkError(fmt.Errorf("--init-concurrency must be positive"))
+		}
+		if syncSkillDebounce < 0 {
+			checkError(fmt.Errorf("--debounce must not be negative"))
+		}
+		if syncSkillJitter < 0 {
+			checkError(fmt.Errorf("--startup-jitter must not be negative"))
+		}
+		bandwidth, err := util.ParseSize(syncSkillBandwidth)
+		if err != nil {
+			checkError(fmt.Errorf("--max-bandwidth: %w", err))
+		}
+		pins, err := skillsync.ParsePins(syncSkillPins)
+		if err != nil {
+			checkError(fmt.Errorf("--pin: %w", err))
+		}
+		var mapFile string
+		if syncSkillMapFile != "" {
+			mapFile = mustResolvePath(syncSkillMapFile)
+		}
+		dirs, err := skillsync.LoadDirMap(mapFile, syncSkillMaps)
+		checkError(err)
+		checkError(dirs.Validate())
+		filter, err := skillsync.NewSkillFilter(syncSkillInclude, syncSkillExclude)
+		checkError(err)
+		if !filter.Empty() && !syncSkillAll && syncSkillCatalog == "" {
+			checkError(fmt.Errorf("--include and --exclude can only be used with --all or --catalog"))
+		}
+		var catalog skillsync.Catalog
+		if syncSkillCatalog != "" {
+			catalog, err = skillsync.ParseCatalogRef(syncSkillCatalog)
+			checkError(err)
+		}
+
+		inDaemon := syncSkillDaemon && daemon.IsDaemon()
+		if syncSkillDaemon && !inDaemon {
+			startSkillSyncDaemon()
+			return
+		}
+		logger, closeLog := syncLogger(syncSkillLogFile, syncSkillLogFormat, inDaemon)
+		defer closeLog()
+
+		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
+		syncer := skillsync.NewSkillSyncer(nacosClient, outputDir, logger)
+		syncer.SetPollTimeout(pollTimeout)
+		syncer.SetPollInterval(syncSkillInterval)
+		syncer.SetBatchSize(syncSkillBatchSize)
+		syncer.SetForceRemote(syncSkillForceRemote)
+		syncer.SetForceInitialSync(syncSkillForceSync)
+		syncer.SetInitConcurrency(syncSkillInitWorkers)
+		syncer.SetMaxBandwidth(bandwidth)
+		syncer.SetStartupJitter(syncSkillJitter)
+		syncer.SetListenEarly(syncSkillListenEarly)
+		syncer.SetPreserveExec(syncSkillExec)
+		syncer.SetVerboseDiff(syncSkillVerboseDiff)
+		syncer.SetDebounce(syncSkillDebounce)
+		syncer.SetPins(pins)
+		syncer.SetDirMap(dirs)
+		syncer.SetFilter(filter)
+		syncer.SetHooks(syncHooks(cmd))
+		syncer.SetStatusFile(syncStatusFile())
+
+		skillNames := args
+		switch {
+		case syncSkillAll:
+			skillNames, err = syncer.AllSkillNames()
+			checkError(err)
+		case syncSkillCatalog != "":
+			syncer.SetCatalog(catalog)
+			skillNames, err = syncer.CatalogSkillNames()
+			checkError(err)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if inDaemon {
+			cleanup := recordDaemonState(syncer, skillNames, logger)
+			err = syncer.Run(ctx, skillNames)
+			cleanup()
+			checkError(err)
+			return
+		}
+		fmt.Fprintln(stderr, "Press Ctrl+C to stop synchronization")
+		checkError(syncer.Run(ctx, skillNames))
+	},
+}
+
+// runSkillPush watches local skill directories and uploads them when they change
+func runSkillPush(cmd *cobra.Command, args []string) {
+	for _, flag := range []string{"output", "poll-timeout", "force-remote", "force-initial-sync", "daemon", "on-change", "on-error", "hook-timeout", "batch-size", "poll-interval", "init-concurrency", "max-bandwidth", "startup-jitter", "listen-early", "preserve-exec", "pin", "map", "map-file", "verbose-diff", "include", "exclude", "catalog", "debounce"} {
+		if cmd.Flags().Changed(flag) {
+			checkError(fmt.Errorf("--%s cannot be used with --push", flag))
+		}
+	}
+	if len(args) == 0 {
+		checkError(fmt.Errorf("specify skill directories, or a folder with --all"))
+	}
+	dirs, err := skillsync.PushDirs(args, syncSkillAll)
+	checkError(err)
+	checkError(validateLogFormat(syncSkillLogFormat))
+	logger, closeLog := syncLogger(syncSkillLogFile, syncSkillLogFormat, false)
+	defer closeLog()
+
+	nacosClient := mustNewNacosClient()
+	mustLogin(nacosClient)
+	pusher := skillsync.NewSkillPusher(nacosClient, logger)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintln(stderr, "Press Ctrl+C to stop pushing")
+	checkError(pusher.Run(ctx, dirs))
+}
+
+// validatePollTimeout rejects --poll-timeout values too short to be useful
+func validatePollTimeout(timeout time.Duration) error {
+	if timeout < time.Second {
+		return fmt.Errorf("--poll-timeout must be at least 1s")
+	}
+	return nil
+}
+
+// validatePollInterval rejects --poll-interval values that would hammer the server
+func validatePollInterval(interval time.Duration) error {
+	if interval < time.Second {
+		return fmt.Errorf("--poll-interval must be at least 1s")
+	}
+	return nil
+}
+
+// syncHooks returns the hooks to run: --on-change and --on-error, falling
+// back to onChange and onError from the config file
+func syncHooks(cmd *cobra.Command) skillsync.Hooks {
+	hooks := skillsync.Hooks{OnChange: onChangeHook, OnError: onErrorHook, Timeout: syncSkillHookTimeout}
+	if cmd.Flags().Changed("on-change") {
+		hooks.OnChange = syncSkillOnChange
+	}
+	if cmd.Flags().Changed("on-error") {
+		hooks.OnError = syncSkillOnError
+	}
+	return hooks
+}
+
+// validateLogFormat rejects --log-format values other than text and json
+func validateLogFormat(format string) error {
+	if _, err := logging.NewHandler(io.Discard, format); err != nil {
+		return fmt.Errorf("--log-format: %w", err)
+	}
+	return nil
+}
+
+// syncLogger returns the logger of skill-sync or config-sync and a function
+// closing it. It prints readable lines to stderr, or structured lines in the
+// daemon, whose stderr is the daemon log, and writes structured entries in
+// format to logFile if set.
+func syncLogger(logFile, format string, inDaemon bool) (*slog.Logger, func()) {
+	console := logging.Stderr().Handler()
+	if inDaemon {
+		handler, err := logging.NewHandler(stderr, format)
+		checkError(err)
+		console = handler
+	}
+	path, err := util.ExpandTilde(logFile)
+	checkError(err)
+	logger, closer, err := logging.Open(console, path, format)
+	checkError(err)
+	return logger, func() { closer.Close() }
+}
+
+// resolveSkillsDir returns the absolute path of dir, the directory given with
+// -o or -d, defaulting to NACOS_SKILLS_DIR, then skillsDir of the config file,
+// then ~/.skills. Commands that skip the config file still honor the
+// environment variable.
+func resolveSkillsDir(dir string) (string, error) {
+	if dir == "" {
+		dir = skillsDir
+	}
+	if dir == "" {
+		dir = os.Getenv(config.EnvSkillsDir)
+	}
+	if dir == "" {
+		dir = config.DefaultSkillsDir
+	}
+	return util.ResolvePath(dir)
+}
+
+// mustResolveSkillsDir is resolveSkillsDir that exits on error
+func mustResolveSkillsDir(dir string) string {
+	resolved, err := resolveSkillsDir(dir)
+	checkError(err)
+	logging.Stderr().Debug("Resolved skills directory "+dir, "path", resolved)
+	return resolved
+}
+
+func init() {
+	syncSkillCmd.Flags().BoolVar(&syncSkillAll, "all", false, "Sync all skills in the namespace (with --push: all skills in the folder)")
+	syncSkillCmd.Flags().BoolVar(&syncSkillForceRemote, "force-remote", false, "Overwrite local edits with remote changes instead of saving them as <skill>.remote")
+	syncSkillCmd.Flags().BoolVar(&syncSkillForceSync, "force-initial-sync", false, "Download every skill at startup, even those unchanged since the last run")
+	syncSkillCmd.Flags().IntVar(&syncSkillInitWorkers, "init-concurrency", skillsync.DefaultInitConcurrency, "How many skills the first sync downloads at once")
+	syncSkillCmd.Flags().StringVar(&syncSkillBandwidth, "max-bandwidth", "0", "Limit all downloads together to this many bytes per second, e.g. 2MB; 0 does not limit")
+	syncSkillCmd.Flags().DurationVar(&syncSkillJitter, "startup-jitter", 0, "Wait a random time up to this long before the first sync, e.g. 30s")
+	syncSkillCmd.Flags().DurationVar(&syncSkillDebounce, "debounce", skillsync.DefaultDebounce, "Wait this long after a skill changes for further changes, then sync it once; 0 syncs on every change")
+	syncSkillCmd.Flags().BoolVar(&syncSkillListenEarly, "listen-early", false, "Start watching for changes before the first sync has finished downloading")
+	syncSkillCmd.Flags().BoolVar(&syncSkillExec, "preserve-exec", true, "Make scripts executable, as for skill-get")
+	syncSkillCmd.Flags().BoolVar(&syncSkillVerboseDiff, "verbose-diff", false, "Log a unified diff of each changed text file when a skill is updated")
+	syncSkillCmd.Flags().StringArrayVar(&syncSkillPins, "pin", nil, "Hold a skill at a version instead of the latest, as skill=version (repeatable)")
+	syncSkillCmd.Flags().StringArrayVar(&syncSkillMaps, "map", nil, "Sync a skill into another directory than --output, as skill=/abs/dir (repeatable, wins over --map-file)")
+	syncSkillCmd.Flags().StringVar(&syncSkillMapFile, "map-file", "", "YAML file mapping skill names to the absolute directory each is synced into")
+	syncSkillCmd.Flags().StringArrayVar(&syncSkillInclude, "include", nil, "With --all or --catalog, sync only skills whose name matches this glob, e.g. team-* (repeatable)")
+	syncSkillCmd.Flags().StringArrayVar(&syncSkillExclude, "exclude", nil, "With --all or --catalog, skip skills whose name matches this glob; wins over --include (repeatable)")
+	syncSkillCmd.Flags().StringVar(&syncSkillCatalog, "catalog", "", "Sync the skills listed in this config, as dataId:group, following it as skills are added and removed")
+	syncSkillCmd.Flags().BoolVar(&syncSkillDaemon, "daemon", false, "Run in the background (see 'skill-sync status' and 'skill-sync stop')")
+	syncSkillCmd.Flags().BoolVar(&syncSkillPush, "push", false, "Upload local skill directories whenever their files change")
+	syncSkillCmd.Flags().StringVarP(&syncSkillOutput, "output", "o", "", "Output directory (default: NACOS_SKILLS_DIR, else skillsDir from the config file, else ~/.skills)")
+	syncSkillCmd.Flags().StringVar(&syncSkillLogFile, "log-file", "", "Also write structured log entries to this file (rotated at 10MB, 3 old files kept)")
+	syncSkillCmd.Flags().StringVar(&syncSkillLogFormat, "log-format", logging.FormatText, "Format of --log-file and of the daemon log: text or json")
+	syncSkillCmd.Flags().StringVar(&syncSkillOnChange, "on-change", "", "Command to run after a skill is updated or deleted (default: onChange from the config file)")
+	syncSkillCmd.Flags().StringVar(&syncSkillOnError, "on-error", "", "Command to run after a skill fails to sync (default: onError from the config file)")
+	syncSkillCmd.Flags().DurationVar(&syncSkillHookTimeout, "hook-timeout", skillsync.DefaultHookTimeout, "Kill a hook that runs longer than this")
+	syncSkillCmd.Flags().IntVar(&syncSkillBatchSize, "batch-size", listener.DefaultBatchSize, "How many configs each concurrent poller watches")
+	syncSkillCmd.Flags().DurationVar(&syncSkillInterval, "poll-interval", listener.PollInterval, "How often each watched config is fetched to check for changes")
+	syncSkillCmd.Flags().DurationVar(&syncSkillPollTimeout, "poll-timeout", 0, "How long each poll for changes may take (default: pollTimeout from the config file, or 30s)")
+	rootCmd.AddCommand(syncSkillCmd)
+}
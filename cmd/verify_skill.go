@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+	"github.com/spf13/cobra"
+)
+
+var (
+	verifySkillDir string
+	verifySkillAll bool
+)
+
+var verifySkillCmd = &cobra.Command{
+	Use:   "skill-verify [skillName...] | --all",
+	Short: "Check installed skills for files changed since they were downloaded",
+	Long:  help.SkillVerify.FormatForCLI("nacos-cli"),
+	Run: func(cmd *cobra.Command, args []string) {
+		if verifySkillAll == (len(args) > 0) {
+			checkError(fmt.Errorf("specify skill names or --all"))
+		}
+		dir, err := resolveSkillsDir(verifySkillDir)
+		checkError(err)
+
+		var skillDirs []string
+		if verifySkillAll {
+			skillDirs, err = skill.SkillDirs(dir)
+			checkError(err)
+			if len(skillDirs) == 0 {
+				fmt.Fprintf(stdout, "No skills found in %s\n", dir)
+				return
+			}
+		} else {
+			for _, name := range args {
+				skillDirs = append(skillDirs, filepath.Join(dir, name))
+			}
+		}
+
+		var intact, damaged, unverifiable int
+		for _, skillDir := range skillDirs {
+			name := filepath.Base(skillDir)
+			if _, err := os.Stat(skillDir); err != nil {
+				fmt.Fprintf(stdout, "%s: not installed in %s\n", name, dir)
+				unverifiable++
+				continue
+			}
+			result, err := skill.VerifySkill(skillDir)
+			if errors.Is(err, skill.ErrNoManifest) {
+				fmt.Fprintf(stdout, "%s: cannot verify, no manifest (install it again with skill-get)\n", name)
+				unverifiable++
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(stdout, "%s: %v\n", name, err)
+				unverifiable++
+				continue
+			}
+			if result.OK() {
+				fmt.Fprintf(stdout, "%s: ok (%d files)\n", name, len(result.Files))
+				intact++
+				continue
+			}
+			problems := result.Problems()
+			fmt.Fprintf(stdout, "%s: %d files changed since the download\n", name, len(problems))
+			for _, f := range problems {
+				fmt.Fprintf(stdout, "  %-9s %s\n", f.Status, f.Path)
+			}
+			damaged++
+		}
+
+		if len(skillDirs) > 1 {
+			fmt.Fprintf(stdout, "\nOK: %d | Changed: %d | Not verified: %d\n", intact, damaged, unverifiable)
+		}
+		if damaged > 0 || unverifiable > 0 {
+			exit(1)
+		}
+	},
+}
+
+func init() {
+	verifySkillCmd.Flags().StringVarP(&verifySkillDir, "dir", "d", "", "Directory the skills are installed in (default: NACOS_SKILLS_DIR, else ~/.skills)")
+	verifySkillCmd.Flags().BoolVar(&verifySkillAll, "all", false, "Verify every skill in the directory")
+	rootCmd.AddCommand(verifySkillCmd)
+}
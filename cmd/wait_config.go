@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/spf13/cobra"
+)
+
+// exitWaitTimeout is the exit code of config-wait at its deadline, as of timeout(1)
+const exitWaitTimeout = 124
+
+var (
+	waitConfigTimeout  time.Duration
+	waitConfigMD5      string
+	waitConfigPrint    bool
+	waitConfigInterval time.Duration
+)
+
+var waitConfigCmd = &cobra.Command{
+	Use:   "config-wait [dataId] [group]",
+	Short: "Wait until a configuration changes, then exit",
+	Long:  help.ConfigWait.FormatForCLI("nacos-cli"),
+	Args:  cobra.RangeArgs(1, 2),
+	Run: func(cmd *cobra.Command, args []string) {
+		dataID := args[0]
+		group := configGroup(args)
+		nacosClient := mustNewNacosClient()
+		mustLogin(nacosClient)
+
+		item := listener.ConfigItem{DataID: dataID, Group: group, Tenant: nacosClient.Namespace, MD5: waitConfigMD5}
+		if waitConfigMD5 == "" {
+			// Without a baseline, wait for the next change from now; a
+			// missing config is waited for until it is created
+			_, md5, err := nacosClient.GetConfigWithMD5(context.Background(), dataID, group, item.Tenant)
+			if err != nil && !errors.Is(err, client.ErrConfigNotFound) {
+				checkError(err)
+			}
+			item.MD5 = md5
+		}
+		if item.MD5 == "" {
+			fmt.Fprintf(stderr, "Waiting for %s (%s) to be created...\n", dataID, group)
+		} else {
+			fmt.Fprintf(stderr, "Waiting for %s (%s) to change from MD5 %s...\n", dataID, group, item.MD5)
+		}
+
+		ctx := context.Background()
+		if waitConfigTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, waitConfigTimeout)
+			defer cancel()
+		}
+		l := listener.NewConfigListener(nacosClient)
+		l.SetPollTimeout(pollTimeout)
+		l.SetPollInterval(waitConfigInterval)
+		newMD5, err := l.WaitForChange(ctx, item)
+		if errors.Is(err, context.DeadlineExceeded) {
+			fmt.Fprintf(stderr, "%s (%s) did not change within %s\n", dataID, group, waitConfigTimeout)
+			exit(exitWaitTimeout)
+		}
+		checkError(err)
+		if newMD5 == "" {
+			fmt.Fprintf(stderr, "%s (%s) was deleted\n", dataID, group)
+			exit(exitConfigNotFound)
+		}
+
+		// Fetched again so the MD5 and content printed belong together
+		content, md5, err := nacosClient.GetConfigWithMD5(context.Background(), dataID, group, item.Tenant)
+		if errors.Is(err, client.ErrConfigNotFound) {
+			fmt.Fprintf(stderr, "%s (%s) was deleted\n", dataID, group)
+			exit(exitConfigNotFound)
+		}
+		checkError(err)
+		fmt.Fprintln(stdout, md5)
+		if waitConfigPrint {
+			fmt.Fprint(stdout, content)
+		}
+	},
+}
+
+func init() {
+	waitConfigCmd.Flags().DurationVar(&waitConfigTimeout, "timeout", 0, "Give up after this long and exit with 124 (default: wait forever)")
+	waitConfigCmd.Flags().StringVar(&waitConfigMD5, "md5", "", "MD5 of the content to wait to change from (default: the current content)")
+	waitConfigCmd.Flags().BoolVar(&waitConfigPrint, "print", false, "Print the new content after its MD5")
+	waitConfigCmd.Flags().DurationVar(&waitConfigInterval, "poll-interval", listener.PollInterval, "How often the config is fetched to check for changes")
+	rootCmd.AddCommand(waitConfigCmd)
+}
@@ -104,12 +104,7 @@ func (s *AgentSpecService) ListAgentSpecs(agentSpecName string, search string, p
 		return nil, 0, err
 	}
 
-	if s.client.AccessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
-	}
-
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("list agentspecs failed: %w", err)
 	}
@@ -117,7 +112,7 @@ func (s *AgentSpecService) ListAgentSpecs(agentSpecName string, search string, p
 
 	if resp.StatusCode != 200 {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, 0, client.ParseHTTPError(resp.StatusCode, respBody, "list agentspecs")
+		return nil, 0, client.WithRequestID(client.ParseHTTPError(resp.StatusCode, respBody, "list agentspecs"), resp)
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
@@ -131,7 +126,7 @@ func (s *AgentSpecService) ListAgentSpecs(agentSpecName string, search string, p
 	}
 
 	if v3Resp.Code != 0 {
-		return nil, 0, fmt.Errorf("list agentspecs failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message)
+		return nil, 0, client.WithRequestID(fmt.Errorf("list agentspecs failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message), resp)
 	}
 
 	var listResp AgentSpecListResponse
@@ -180,12 +175,7 @@ func (s *AgentSpecService) GetAgentSpec(name, outputDir string, version, label s
 	if err != nil {
 		return fmt.Errorf("failed to build request: %w", err)
 	}
-	if s.client.AccessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
-	}
-
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to get agentspec: %w", err)
 	}
@@ -197,7 +187,7 @@ func (s *AgentSpecService) GetAgentSpec(name, outputDir string, version, label s
 	}
 
 	if resp.StatusCode != 200 {
-		return client.ParseHTTPError(resp.StatusCode, respBody, "get agentspec")
+		return client.WithRequestID(client.ParseHTTPError(resp.StatusCode, respBody, "get agentspec"), resp)
 	}
 
 	var v3Resp V3Response
@@ -205,7 +195,7 @@ func (s *AgentSpecService) GetAgentSpec(name, outputDir string, version, label s
 		return fmt.Errorf("failed to parse response: %w", err)
 	}
 	if v3Resp.Code != 0 {
-		return fmt.Errorf("get agentspec failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message)
+		return client.WithRequestID(fmt.Errorf("get agentspec failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message), resp)
 	}
 
 	var spec AgentSpec
@@ -351,12 +341,7 @@ func (s *AgentSpecService) UploadAgentSpec(agentSpecPath string) error {
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	if s.client.AccessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
-	}
-
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return fmt.Errorf("upload failed: %w", err)
 	}
@@ -364,7 +349,7 @@ func (s *AgentSpecService) UploadAgentSpec(agentSpecPath string) error {
 
 	if resp.StatusCode != 200 {
 		respBody, _ := io.ReadAll(resp.Body)
-		return client.ParseHTTPError(resp.StatusCode, respBody, "upload agentspec")
+		return client.WithRequestID(client.ParseHTTPError(resp.StatusCode, respBody, "upload agentspec"), resp)
 	}
 
 	return nil
@@ -0,0 +1,93 @@
+package client
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// minClockSkew is the smallest difference from the server's Date header that
+// is taken as clock skew. The header only has whole seconds and is sent
+// after the request was checked, so a smaller one tells nothing.
+const minClockSkew = 5 * time.Second
+
+// spasTime returns the time for the timeStamp header of a SPAS signature:
+// the local time corrected by the clock skew found so far (see adjustClock)
+func (c *NacosClient) spasTime() time.Time {
+	return c.clock().Add(time.Duration(c.clockOffset.Load()))
+}
+
+// clock returns the local time
+func (c *NacosClient) clock() time.Time {
+	if c.now != nil {
+		return c.now()
+	}
+	return time.Now()
+}
+
+// timestampRejected reports whether a response rejects a SPAS signature for
+// its timestamp, e.g. "timestamp expired" or "invalid time stamp", rather
+// than for the credentials
+func timestampRejected(status int, body []byte) bool {
+	if status < 400 || status >= 500 {
+		return false
+	}
+	msg := strings.NewReplacer(" ", "", "_", "", "-", "").Replace(strings.ToLower(string(body)))
+	return strings.Contains(msg, "timestamp") && (strings.Contains(msg, "expire") || strings.Contains(msg, "invalid"))
+}
+
+// adjustClock is told about every response to a SPAS-signed request. If the
+// server rejected the signature for its timestamp, the skew between the local
+// clock and the server's Date header is added to later timestamps, with a
+// warning to fix the clock. It reports whether the skew changed, i.e.
+// whether the request is worth sending again.
+func (c *NacosClient) adjustClock(status int, header http.Header, body []byte) bool {
+	if c.AuthType != AuthTypeAliyun || !timestampRejected(status, body) {
+		return false
+	}
+	date, err := http.ParseTime(header.Get("Date"))
+	if err != nil {
+		c.log.Debug("Signature timestamp was rejected, but the response has no Date header to correct the clock by", "status", status)
+		return false
+	}
+	// The header drops the fraction of its second; assume the middle of it
+	skew := date.Add(500 * time.Millisecond).Sub(c.clock())
+	if skew.Abs() < minClockSkew {
+		c.log.Debug("Signature timestamp was rejected, but the local clock is close to the server's", "status", status, "skew", skew.String())
+		return false
+	}
+	if old := time.Duration(c.clockOffset.Swap(int64(skew))); (skew - old).Abs() < minClockSkew {
+		// Another request rejected at the same time corrected it already
+		return true
+	}
+	c.log.Warn(fmt.Sprintf("The local clock is %s %s the Nacos server, so signatures were rejected; signing with the server's time from now on. Sync the clock with NTP to fix this.",
+		skew.Abs().Round(time.Second), aheadOrBehind(skew)), "skew", skew.Round(time.Second).String())
+	return true
+}
+
+// checkClock is adjustClock for a response to Do. The request was signed by
+// its caller (see AuthorizeRequest), so it is not sent again; the next one is
+// signed with the corrected clock. The body is left for the caller to read.
+func (c *NacosClient) checkClock(resp *http.Response) {
+	if c.AuthType != AuthTypeAliyun || resp.StatusCode < 400 || resp.StatusCode >= 500 {
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if err == nil {
+		c.adjustClock(resp.StatusCode, resp.Header, body)
+	}
+}
+
+// aheadOrBehind describes the local clock for a skew measured as server time
+// minus local time
+func aheadOrBehind(skew time.Duration) string {
+	if skew > 0 {
+		return "behind"
+	}
+	return "ahead of"
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+// newSpasServer returns a server that checks SPAS signatures as Aliyun does,
+// rejecting timestamps more than 30s off its own clock, and the number of
+// requests it rejected
+func newSpasServer(t *testing.T, sendDate bool) (*httptest.Server, *int32) {
+	var rejected int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !sendDate {
+			// net/http adds a Date header unless it is set to nil
+			w.Header()["Date"] = nil
+		}
+		ms, err := strconv.ParseInt(r.Header.Get("timeStamp"), 10, 64)
+		if err != nil || time.Since(time.UnixMilli(ms)).Abs() > 30*time.Second {
+			atomic.AddInt32(&rejected, 1)
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"code":403,"message":"signature timestamp expired"}`)
+			return
+		}
+		group := r.URL.Query().Get("groupName")
+		if r.Header.Get("Spas-Signature") != spasSign(getSignData("", group, r.Header.Get("timeStamp")), "sk") {
+			w.WriteHeader(http.StatusForbidden)
+			fmt.Fprint(w, `{"code":403,"message":"signature does not match"}`)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"data":{"content":"a: 1"}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &rejected
+}
+
+func TestClockSkew(t *testing.T) {
+	for _, off := range []time.Duration{5 * time.Minute, -5 * time.Minute} {
+		t.Run(off.String(), func(t *testing.T) {
+			server, rejected := newSpasServer(t, true)
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "ak", "sk", "")
+			c.now = func() time.Time { return time.Now().Add(off) }
+			var log strings.Builder
+			c.SetLogger(slog.New(logging.NewConsoleHandler(func(msg string) {
+				log.WriteString(msg + "\n")
+			})))
+
+			// Retried with the corrected clock
+			if _, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err != nil {
+				t.Fatalf("GetConfig() error = %v", err)
+			}
+			if _, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", ""); err != nil {
+				t.Fatalf("GetConfigWithMD5() error = %v", err)
+			}
+			if n := atomic.LoadInt32(rejected); n != 1 {
+				t.Errorf("server rejected %d request(s), want 1", n)
+			}
+			want := "The local clock is 5m0s ahead of the Nacos server"
+			if off < 0 {
+				want = "The local clock is 5m0s behind the Nacos server"
+			}
+			if !strings.Contains(log.String(), want) || !strings.Contains(log.String(), "NTP") {
+				t.Errorf("log lacks %q and a hint at NTP:\n%s", want, log.String())
+			}
+			if strings.Count(log.String(), "The local clock") != 1 {
+				t.Errorf("clock skew was warned about more than once:\n%s", log.String())
+			}
+		})
+	}
+}
+
+func TestClockSkewInDo(t *testing.T) {
+	server, rejected := newSpasServer(t, true)
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "ak", "sk", "")
+	c.now = func() time.Time { return time.Now().Add(5 * time.Minute) }
+	c.SetLogger(logging.Discard())
+
+	// The request signed by the caller fails; the next one is signed with the
+	// corrected clock
+	if _, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", ""); err == nil || !strings.Contains(err.Error(), "timestamp expired") {
+		t.Fatalf("GetConfigWithMD5() error = %v, want the rejection", err)
+	}
+	if _, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", ""); err != nil {
+		t.Fatalf("GetConfigWithMD5() after the rejection error = %v", err)
+	}
+	if n := atomic.LoadInt32(rejected); n != 1 {
+		t.Errorf("server rejected %d request(s), want 1", n)
+	}
+}
+
+func TestClockSkewWithoutDate(t *testing.T) {
+	server, rejected := newSpasServer(t, false)
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "ak", "sk", "")
+	c.now = func() time.Time { return time.Now().Add(5 * time.Minute) }
+	c.SetLogger(logging.Discard())
+
+	if _, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err == nil || !strings.Contains(err.Error(), "timestamp expired") {
+		t.Errorf("GetConfig() error = %v, want the rejection", err)
+	}
+	if n := atomic.LoadInt32(rejected); n != 1 {
+		t.Errorf("server rejected %d request(s), want 1 without retrying", n)
+	}
+}
+
+func TestTimestampRejected(t *testing.T) {
+	tests := []struct {
+		status int
+		body   string
+		want   bool
+	}{
+		{403, `{"code":403,"message":"signature timestamp expired"}`, true},
+		{400, "Invalid time stamp", true},
+		{401, "TIMESTAMP_EXPIRED", true},
+		{403, "signature does not match", false},
+		{403, "timestamp", false},
+		{500, "timestamp expired", false},
+	}
+	for _, tt := range tests {
+		if got := timestampRejected(tt.status, []byte(tt.body)); got != tt.want {
+			t.Errorf("timestampRejected(%d, %q) = %v, want %v", tt.status, tt.body, got, tt.want)
+		}
+	}
+}
@@ -1,17 +1,28 @@
 package client
 
 import (
+	"context"
 	"crypto/hmac"
+	"crypto/md5"
 	"crypto/sha1"
 	"encoding/base64"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
 	"net/url"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-resty/resty/v2"
+	"github.com/nacos-group/nacos-cli/internal/logging"
 )
 
 const (
@@ -21,6 +32,86 @@ const (
 	AuthTypeToken  = "token"  // Pre-issued access token (no login required)
 )
 
+// codeConfigNotFound is the v3 API error code for "config data not exist"
+const codeConfigNotFound = 20004
+
+// ErrConfigNotFound is returned (wrapped) by GetConfig when the config does not exist.
+// Use errors.Is(err, ErrConfigNotFound) to check for it.
+var ErrConfigNotFound = errors.New("config not found")
+
+// ErrLoginFailed is returned (wrapped) by requests, EnsureTokenValid and Login when
+// username/password login fails, e.g. because of wrong credentials.
+var ErrLoginFailed = errors.New("login failed")
+
+// ErrForbidden is returned (wrapped) for a request answered with 403 after
+// logging in again: the user lacks permission for it
+var ErrForbidden = errors.New("forbidden")
+
+// forbiddenError is an error about a 403 that errors.Is matches to ErrForbidden
+type forbiddenError struct{ error }
+
+func (e forbiddenError) Is(target error) bool { return target == ErrForbidden }
+
+// ErrConfigChanged is returned (wrapped) by PublishConfigCAS when the config
+// no longer has the MD5 the new content was based on: it was published or
+// deleted by someone else in between
+var ErrConfigChanged = errors.New("config changed since it was fetched")
+
+// ErrUnexpectedContent is returned (wrapped) when a config response is neither
+// a Nacos JSON response nor raw content sent with the Nacos config headers,
+// such as the HTML error page of a gateway in front of Nacos
+var ErrUnexpectedContent = errors.New("unexpected config response")
+
+// ErrContentCorrupted is returned (wrapped) when a download does not match
+// the Content-MD5 header sent with it, even when fetched a second time: a
+// proxy cut the response short or altered it
+var ErrContentCorrupted = errors.New("content corrupted in transit")
+
+// MD5Check is what checking a download against its Content-MD5 header found
+type MD5Check string
+
+const (
+	MD5Verified MD5Check = "verified" // the content matched the header
+	MD5NotSent  MD5Check = "not-sent" // the server sent no header to check against
+	MD5Skipped  MD5Check = "skipped"  // the check is off, see SetMD5Verification
+)
+
+// ErrNoCipher is returned (wrapped) by PublishConfig for an encrypted config
+// (see IsEncrypted) when no cipher is set to encrypt its content with
+var ErrNoCipher = errors.New("config must be encrypted")
+
+// CipherPrefix starts the dataId of a config whose content is encrypted with
+// KMS, as the ACM and MSE SDKs do
+const CipherPrefix = "cipher-"
+
+// envelopePrefix starts the dataId of a config encrypted with a data key
+// (cipher-kms-aes-128- and cipher-kms-aes-256-), which needs the encrypted
+// data key stored with the config to decrypt
+const envelopePrefix = "cipher-kms-aes-"
+
+// Cipher encrypts and decrypts the content of encrypted configs
+type Cipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// IsEncrypted reports whether the content of the config dataID is stored
+// encrypted
+func IsEncrypted(dataID string) bool {
+	return strings.HasPrefix(dataID, CipherPrefix)
+}
+
+// StatusError is returned by GetConfigWithMD5 for a request answered with an
+// unexpected HTTP status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("get config returned status %d: %s", e.StatusCode, e.Body)
+}
+
 // NacosClient represents a Nacos API client
 type NacosClient struct {
 	ServerAddr       string
@@ -34,6 +125,19 @@ type NacosClient struct {
 	TokenExpireAt    time.Time
 	authLoginVersion string // "v3" or "v1", determined by first successful login
 	httpClient       *resty.Client
+	authMu           sync.Mutex      // serializes token refresh for concurrent callers
+	noValidation     bool            // accept any config response body as content
+	noMD5Check       bool            // accept content that does not match its Content-MD5
+	transport        *http.Transport // shared by httpClient and Do
+	timeout          time.Duration   // of each request sent with Do, see SetTimeout
+	log              *slog.Logger    // debug entries on requests, logins and retries
+	cipher           Cipher          // decrypts and encrypts cipher- configs; nil without KMS
+	namespacesOnce   sync.Once       // fetches the namespace list once, see ListNamespaces
+	namespaces       []Namespace
+	namespacesErr    error
+	clockOffset      atomic.Int64     // added to the local time in SPAS timestamps, see adjustClock
+	now              func() time.Time // the local clock; nil for time.Now
+	onRequest        func(Request)    // see SetRequestHandler
 }
 
 // Config represents a Nacos configuration
@@ -43,6 +147,12 @@ type Config struct {
 	GroupName string `json:"groupName"`
 	Content   string `json:"content"`
 	Type      string `json:"type"`
+	// MD5 is the MD5 of the content as stored, that of the ciphertext for an
+	// encrypted config: as the server reports it, else computed
+	MD5 string `json:"md5"`
+	// MD5Check is what checking the content against the Content-MD5 header
+	// found when it was fetched with GetConfigDetail
+	MD5Check MD5Check `json:"-"`
 }
 
 // ConfigListResponse represents the response of list configs API
@@ -53,6 +163,62 @@ type ConfigListResponse struct {
 	PageItems      []Config `json:"pageItems"`
 }
 
+// Page returns the number of the page listed: as the server reports it, else
+// as requested
+func (r *ConfigListResponse) Page(requested int) int {
+	if r.PageNumber > 0 {
+		return r.PageNumber
+	}
+	return requested
+}
+
+// PageSize returns the page size the server listed with, which is smaller
+// than requested when the server caps the page size (Nacos clamps 500 to
+// 100, for example). The cap shows only on a page short of requested that
+// is not the last one; otherwise requested is returned.
+func (r *ConfigListResponse) PageSize(requested int) int {
+	n := len(r.PageItems)
+	if n == 0 || n >= requested {
+		return requested
+	}
+	notLast := r.PageNumber > 0 && r.PageNumber < r.PagesAvailable
+	if r.PagesAvailable == 0 {
+		// Without pagesAvailable only the first page tells
+		notLast = r.PageNumber <= 1 && n < r.TotalCount
+	}
+	if notLast {
+		return n
+	}
+	return requested
+}
+
+// TotalPages returns the number of pages: as the server reports it, else as
+// computed from TotalCount and the page size the server listed with
+func (r *ConfigListResponse) TotalPages(requested int) int {
+	if r.PagesAvailable > 0 {
+		return r.PagesAvailable
+	}
+	size := r.PageSize(requested)
+	if size <= 0 {
+		return 0
+	}
+	return (r.TotalCount + size - 1) / size
+}
+
+// CSV returns the header and rows of config-list --output csv: the data ID,
+// group and type of each config listed, as in the table
+func (r *ConfigListResponse) CSV() ([]string, [][]string) {
+	rows := make([][]string, len(r.PageItems))
+	for i, cfg := range r.PageItems {
+		group := cfg.GroupName
+		if group == "" {
+			group = cfg.Group
+		}
+		rows[i] = []string{cfg.DataID, group, cfg.Type}
+	}
+	return []string{"dataId", "group", "type"}, rows
+}
+
 // V3Response represents the v3 API response wrapper
 type V3Response struct {
 	Code    int             `json:"code"`
@@ -82,9 +248,9 @@ func ParseHTTPError(statusCode int, body []byte, operation string) error {
 	case 403:
 		hint := "access denied — token may be expired or you lack permission for this operation"
 		if serverMsg != "" {
-			return fmt.Errorf("%s failed (403 Forbidden): %s\nHint: %s", operation, serverMsg, hint)
+			return forbiddenError{fmt.Errorf("%s failed (403 Forbidden): %s\nHint: %s", operation, serverMsg, hint)}
 		}
-		return fmt.Errorf("%s failed (403 Forbidden): %s", operation, hint)
+		return forbiddenError{fmt.Errorf("%s failed (403 Forbidden): %s", operation, hint)}
 	case 404:
 		hint := "resource not found — check the name/namespace or whether it exists"
 		if serverMsg != "" {
@@ -113,9 +279,10 @@ func ParseHTTPError(statusCode int, body []byte, operation string) error {
 	}
 }
 
-// NewNacosClient creates a new Nacos client with automatic authentication.
-// If token is non-empty, it is used directly as the Bearer token and no login request is made.
-// Returns an error if login is required but fails (e.g. wrong credentials).
+// NewNacosClient creates a new Nacos client. It does not contact the server:
+// username/password login happens on the first request (see EnsureTokenValid),
+// and a failed login is returned by that request. If token is non-empty, it is
+// used directly as the Bearer token and no login request is made.
 func NewNacosClient(serverAddr, namespace, authType, username, password, accessKey, secretKey, token string) (*NacosClient, error) {
 	if namespace == "" {
 		namespace = "public"
@@ -132,6 +299,15 @@ func NewNacosClient(serverAddr, namespace, authType, username, password, accessK
 		}
 	}
 
+	// Go's transport asks for gzip and decompresses the response as long
+	// as no request sets Accept-Encoding itself; see SetCompression
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	httpClient := resty.New().SetTransport(transport)
+	// Every request, retries included, gets its own id
+	httpClient.OnBeforeRequest(func(_ *resty.Client, req *resty.Request) error {
+		req.SetHeader(RequestIDHeader, newRequestID())
+		return nil
+	})
 	c := &NacosClient{
 		ServerAddr:  serverAddr,
 		Namespace:   namespace,
@@ -141,71 +317,302 @@ func NewNacosClient(serverAddr, namespace, authType, username, password, accessK
 		AccessKey:   accessKey,
 		SecretKey:   secretKey,
 		AccessToken: token,
-		httpClient:  resty.New(),
+		httpClient:  httpClient,
+		transport:   transport,
+		log:         logging.Stderr(),
 	}
 
-	// If a token is provided directly, skip login entirely.
-	if token != "" {
-		return c, nil
+	return c, nil
+}
+
+// Login authenticates again with the current username and password. The
+// previous token is kept if login fails.
+func (c *NacosClient) Login() error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	return c.login()
+}
+
+// ReloginAfterForbidden logs in again after a 401 or 403, which usually means
+// the token expired or was revoked on the server, e.g. by a restart or a
+// password change. The cached token is dropped first, so after a failed login
+// the next request logs in again. It reports whether the request should be retried.
+func (c *NacosClient) ReloginAfterForbidden() bool {
+	return c.relogin(c.AccessToken)
+}
+
+// relogin is ReloginAfterForbidden for a request sent with the token used. If
+// another request has logged in since, its token is reused, so that requests
+// rejected together log in once.
+func (c *NacosClient) relogin(used string) bool {
+	if c.AuthType != AuthTypeNacos || c.Username == "" || c.Password == "" {
+		return false
 	}
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if c.AccessToken != "" && c.AccessToken != used {
+		c.log.Debug("Token was rejected but another request already logged in again; retrying with the new token")
+		return true
+	}
+	c.log.Debug("Token was rejected; logging in again and retrying once")
+	c.AccessToken = ""
+	c.TokenExpireAt = time.Time{}
+	if err := c.login(); err != nil {
+		c.log.Debug("Login after a rejected token failed; not retrying", "error", err)
+		return false
+	}
+	return true
+}
+
+// authRejected reports whether status is how Nacos rejects a token
+func authRejected(status int) bool {
+	return status == http.StatusUnauthorized || status == http.StatusForbidden
+}
 
-	if c.AuthType == AuthTypeNacos {
-		if err := c.login(); err != nil {
-			return nil, fmt.Errorf("login failed: %w", err)
+// send runs a request built by build; on a 401 or 403 it logs in again once
+// and rebuilds the request so the new token is used. If the retry is rejected
+// too, or the login fails, the rejection is returned.
+func (c *NacosClient) send(build func() *resty.Request, method, url string) (*resty.Response, error) {
+	used := c.AccessToken
+	resp, err := build().Execute(method, url)
+	if err == nil {
+		c.logResponse(resp.RawResponse, len(resp.Body()), resp.Time())
+	}
+	if err == nil && authRejected(resp.StatusCode()) && c.relogin(used) {
+		resp, err = build().Execute(method, url)
+		if err == nil {
+			c.logResponse(resp.RawResponse, len(resp.Body()), resp.Time())
 		}
 	}
-	return c, nil
+	// The rebuilt request is signed with the corrected clock
+	if err == nil && c.adjustClock(resp.StatusCode(), resp.Header(), resp.Body()) {
+		resp, err = build().Execute(method, url)
+		if err == nil {
+			c.logResponse(resp.RawResponse, len(resp.Body()), resp.Time())
+		}
+	}
+	return resp, err
+}
+
+// Do sends a request built outside the client, such as by the skill and agent
+// spec services, with the current access token, logging in first if there is
+// none yet. On a 401 or 403 it logs in again once and retries; requests with a body must support GetBody (see
+// http.NewRequest).
+func (c *NacosClient) Do(req *http.Request) (*http.Response, error) {
+	return c.do(req, c.timeout)
+}
+
+// do is Do with the given timeout (0 for none) instead of the client's
+func (c *NacosClient) do(req *http.Request, timeout time.Duration) (*http.Response, error) {
+	if err := c.EnsureTokenValid(); err != nil {
+		return nil, err
+	}
+	httpClient := &http.Client{Transport: c.transport, Timeout: timeout}
+	used := c.AccessToken
+	if used != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", used))
+	}
+	req.Header.Set(RequestIDHeader, newRequestID())
+	start := time.Now()
+	resp, err := httpClient.Do(req)
+	if err == nil {
+		c.logResponse(resp, int(resp.ContentLength), time.Since(start))
+		c.checkClock(resp)
+	}
+	if err != nil || !authRejected(resp.StatusCode) || !c.relogin(used) {
+		return resp, err
+	}
+
+	retry := req.Clone(req.Context())
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return resp, nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	resp.Body.Close()
+	retry.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+	retry.Header.Set(RequestIDHeader, newRequestID())
+	start = time.Now()
+	resp, err = httpClient.Do(retry)
+	if err == nil {
+		c.logResponse(resp, int(resp.ContentLength), time.Since(start))
+	}
+	return resp, err
+}
+
+// SetContentValidation turns the check of raw config responses on or off (see
+// ErrUnexpectedContent). It is on by default; turn it off for servers that
+// send raw content without the Nacos config headers.
+func (c *NacosClient) SetContentValidation(enabled bool) {
+	c.noValidation = !enabled
+}
+
+// SetMD5Verification turns the check of downloads against their Content-MD5
+// header on or off (see ErrContentCorrupted). It is on by default; turn it
+// off for servers that send a wrong header.
+func (c *NacosClient) SetMD5Verification(enabled bool) {
+	c.noMD5Check = !enabled
+}
+
+// SetLogger replaces the default logger, which prints to stderr. The client
+// logs at debug level: requests with their status and size, the login
+// endpoint chosen, and token refreshes and retries; and it warns when it
+// returns the ciphertext of an encrypted config, when it downloads content
+// again that did not match its Content-MD5, and when it corrects its clock
+// for SPAS signatures. Query strings, tokens and passwords are never logged.
+func (c *NacosClient) SetLogger(logger *slog.Logger) {
+	c.log = logger
+}
+
+// logResponse logs a request at debug level by its path, leaving out the
+// query string, which may carry an access token, and with its request ids.
+// A negative size (streamed or decompressed body) is left out. The handler
+// set with SetRequestHandler is told about it as well.
+func (c *NacosClient) logResponse(resp *http.Response, size int, elapsed time.Duration) {
+	req := Request{Method: resp.Request.Method, Path: resp.Request.URL.Path, Status: resp.StatusCode}
+	req.ID, req.ServerID = RequestIDs(resp)
+	attrs := []any{"status", req.Status, "duration", elapsed}
+	if size >= 0 {
+		attrs = append(attrs, "bytes", size)
+	}
+	attrs = append(attrs, "requestId", req.ID)
+	if req.ServerID != "" {
+		attrs = append(attrs, "serverRequestId", req.ServerID)
+	}
+	c.log.Debug(req.Method+" "+req.Path, attrs...)
+	if c.onRequest != nil {
+		c.onRequest(req)
+	}
+}
+
+// SetCompression turns gzip compression of responses on or off. It is on by
+// default, which shrinks large configs on slow links; responses are
+// decompressed before their content or MD5 is looked at. Call it before the
+// first request.
+func (c *NacosClient) SetCompression(enabled bool) {
+	c.transport.DisableCompression = !enabled
 }
 
-// isLocalAddr checks if the server address is localhost
-func (c *NacosClient) isLocalAddr() bool {
-	addr := strings.ToLower(c.ServerAddr)
-	return strings.HasPrefix(addr, "127.0.0.1") ||
-		strings.HasPrefix(addr, "localhost") ||
-		strings.HasPrefix(addr, "0.0.0.0")
+// SetCipher sets what decrypts the content of encrypted configs (see
+// IsEncrypted) when they are fetched and encrypts it when they are published.
+// Without one their content is returned as ciphertext, with a warning, and
+// publishing them fails.
+func (c *NacosClient) SetCipher(cipher Cipher) {
+	c.cipher = cipher
+}
+
+// decrypt returns the plaintext of the content of config dataID if it is
+// encrypted and can be decrypted, else the content as it is
+func (c *NacosClient) decrypt(dataID, content string) (string, error) {
+	if !IsEncrypted(dataID) || content == "" {
+		return content, nil
+	}
+	if strings.HasPrefix(dataID, envelopePrefix) {
+		c.log.Warn(fmt.Sprintf("%s is encrypted with a KMS data key, which nacos-cli cannot decrypt: its content is ciphertext", dataID))
+		return content, nil
+	}
+	if c.cipher == nil {
+		c.log.Warn(fmt.Sprintf("%s is encrypted and no KMS endpoint is configured: its content is ciphertext (set --kms-endpoint to decrypt it)", dataID))
+		return content, nil
+	}
+	plaintext, err := c.cipher.Decrypt(content)
+	if err != nil {
+		return "", fmt.Errorf("decrypt %s: %w", dataID, err)
+	}
+	return plaintext, nil
+}
+
+// encrypt returns the content to publish for config dataID: the ciphertext
+// of content if the config is encrypted
+func (c *NacosClient) encrypt(dataID, content string) (string, error) {
+	if !IsEncrypted(dataID) {
+		return content, nil
+	}
+	if strings.HasPrefix(dataID, envelopePrefix) {
+		return "", fmt.Errorf("%w: %s is encrypted with a KMS data key, which nacos-cli does not support", ErrNoCipher, dataID)
+	}
+	if c.cipher == nil {
+		return "", fmt.Errorf("%w: %s is encrypted with KMS; set --kms-endpoint and --kms-key-id to publish it", ErrNoCipher, dataID)
+	}
+	ciphertext, err := c.cipher.Encrypt(content)
+	if err != nil {
+		return "", fmt.Errorf("encrypt %s: %w", dataID, err)
+	}
+	return ciphertext, nil
+}
+
+// SetTimeout sets the timeout applied to each HTTP request, including those
+// sent with Do, until its response is read. Zero means no timeout.
+func (c *NacosClient) SetTimeout(timeout time.Duration) {
+	c.httpClient.SetTimeout(timeout)
+	c.timeout = timeout
 }
 
 // login attempts to authenticate with Nacos server using v3 API first, then falls back to v1.
 // For Nacos 3.x, v3 login succeeds but some legacy v1 APIs (like config list) may return 410 (Gone),
 // so once v3 login succeeds we MUST NOT override authLoginVersion with v1.
+// Nothing is printed; the error says why each attempt failed.
 func (c *NacosClient) login() error {
 	form := map[string]string{"username": c.Username, "password": c.Password}
-	isLocal := c.isLocalAddr()
+	var failures []string
 
 	// Prefer v3 login. If we've previously determined v1 only, skip v3.
 	tryV3 := c.authLoginVersion == "" || c.authLoginVersion == "v3"
 	if tryV3 {
+		c.log.Debug("Logging in with the v3 API", "username", c.Username, "endpoint", "v3")
 		u := fmt.Sprintf("http://%s/nacos/v3/auth/user/login", c.ServerAddr)
 		resp, err := c.httpClient.R().SetFormData(form).Post(u)
-		if err != nil {
-			if !isLocal {
-				fmt.Printf("v3 login failed: %v\n", err)
-			}
-		} else if resp != nil && resp.StatusCode() == 200 && c.applyLoginResponse(resp.Body()) {
+		if err == nil && resp.StatusCode() == 200 && c.applyLoginResponse(resp.Body()) {
 			c.authLoginVersion = "v3"
+			c.log.Debug("Logged in with the v3 API", "endpoint", "v3", "expires", c.TokenExpireAt.Format(time.RFC3339))
 			return nil
-		} else if !isLocal && resp != nil {
-			fmt.Printf("v3 login failed: status=%d, body=%s\n", resp.StatusCode(), string(resp.Body()))
 		}
+		failures = append(failures, "v3 "+loginFailure(resp, err))
+		c.log.Debug("v3 login failed; trying the v1 API", "reason", failures[0])
 	}
 
 	// Fallback to v1 login if v3 is unavailable (e.g., older Nacos versions).
+	c.log.Debug("Logging in with the v1 API", "username", c.Username, "endpoint", "v1")
 	u := fmt.Sprintf("http://%s/nacos/v1/auth/login", c.ServerAddr)
 	resp, err := c.httpClient.R().SetFormData(form).Post(u)
-	if err != nil {
-		if !isLocal {
-			fmt.Printf("v1 login failed: %v\n", err)
-		}
-		return err
-	}
-	if resp != nil && resp.StatusCode() == 200 && c.applyLoginResponse(resp.Body()) {
+	if err == nil && resp.StatusCode() == 200 && c.applyLoginResponse(resp.Body()) {
 		c.authLoginVersion = "v1"
+		c.log.Debug("Logged in with the v1 API; config listing will use the v1 API too", "endpoint", "v1", "expires", c.TokenExpireAt.Format(time.RFC3339))
 		return nil
 	}
-	if !isLocal && resp != nil {
-		fmt.Printf("v1 login failed: status=%d, body=%s\n", resp.StatusCode(), string(resp.Body()))
+	if err != nil {
+		// The server could not be reached, which is not an auth failure
+		return fmt.Errorf("login: %w", err)
+	}
+	failures = append(failures, "v1 "+loginFailure(resp, err))
+	return fmt.Errorf("%w: %s", ErrLoginFailed, strings.Join(failures, "; "))
+}
+
+// loginFailure describes a failed login attempt, e.g. "status=403: unknown
+// user (requestId=...)"
+func loginFailure(resp *resty.Response, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	body := strings.TrimSpace(string(resp.Body()))
+	if len(body) > 200 {
+		body = body[:200] + "..."
+	}
+	failure := fmt.Sprintf("status=%d", resp.StatusCode())
+	if body != "" {
+		failure += ": " + body
+	}
+	if id, serverID := RequestIDs(resp.RawResponse); serverID != "" {
+		failure += fmt.Sprintf(" (requestId=%s, serverRequestId=%s)", id, serverID)
+	} else if id != "" {
+		failure += fmt.Sprintf(" (requestId=%s)", id)
 	}
-	return fmt.Errorf("login failed: status=%d", resp.StatusCode())
+	return failure
 }
 
 // applyLoginResponse parses login response and extracts access token
@@ -243,8 +650,13 @@ func (c *NacosClient) applyLoginFromMap(m map[string]interface{}) bool {
 	return true
 }
 
-// ensureTokenValid ensures the access token is valid, refreshing if necessary
-func (c *NacosClient) ensureTokenValid() error {
+// EnsureTokenValid logs in if there is no access token yet or the token
+// expires within a few seconds. It does nothing unless username/password
+// authentication is used.
+func (c *NacosClient) EnsureTokenValid() error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
 	// Token auth: user-supplied token, no refresh
 	if c.AuthType == AuthTypeToken {
 		return nil
@@ -256,6 +668,7 @@ func (c *NacosClient) ensureTokenValid() error {
 		return c.login()
 	}
 	if !c.TokenExpireAt.IsZero() && time.Now().Add(5*time.Second).After(c.TokenExpireAt) {
+		c.log.Debug("Token expires within 5s; logging in again", "expires", c.TokenExpireAt.Format(time.RFC3339))
 		return c.login()
 	}
 	return nil
@@ -282,25 +695,45 @@ func spasSign(signData, secretKey string) string {
 	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
 }
 
-// setSpasHeaders sets Aliyun authentication headers for SPAS signature
-func (c *NacosClient) setSpasHeaders(req *resty.Request, tenant, group string) {
+// spasHeaders returns the Aliyun SPAS signature headers for a request on
+// tenant and group, or nil when the client does not use Aliyun authentication
+func (c *NacosClient) spasHeaders(tenant, group string) map[string]string {
 	if c.AuthType != AuthTypeAliyun || c.AccessKey == "" || c.SecretKey == "" {
-		return
+		return nil
 	}
-	ts := strconv.FormatInt(time.Now().UnixMilli(), 10)
-	req.SetHeader("timeStamp", ts)
-	req.SetHeader("Spas-AccessKey", c.AccessKey)
+	ts := strconv.FormatInt(c.spasTime().UnixMilli(), 10)
 	normalizedTenant := tenant
 	if normalizedTenant == "public" {
 		normalizedTenant = ""
 	}
 	signData := getSignData(normalizedTenant, group, ts)
-	req.SetHeader("Spas-Signature", spasSign(signData, c.SecretKey))
+	return map[string]string{
+		"timeStamp":      ts,
+		"Spas-AccessKey": c.AccessKey,
+		"Spas-Signature": spasSign(signData, c.SecretKey),
+	}
+}
+
+// setSpasHeaders sets Aliyun authentication headers for SPAS signature
+func (c *NacosClient) setSpasHeaders(req *resty.Request, tenant, group string) {
+	req.SetHeaders(c.spasHeaders(tenant, group))
+}
+
+// AuthorizeRequest adds the client's credentials to a request built outside
+// the client: the access token if there is one, and the SPAS signature for
+// tenant and group with Aliyun authentication.
+func (c *NacosClient) AuthorizeRequest(req *http.Request, tenant, group string) {
+	if c.AccessToken != "" {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+	}
+	for name, value := range c.spasHeaders(tenant, group) {
+		req.Header.Set(name, value)
+	}
 }
 
 // ListConfigs retrieves a list of configurations using v3 or v1 API based on login version
 func (c *NacosClient) ListConfigs(dataID, groupName, namespaceID string, pageNo, pageSize int) (*ConfigListResponse, error) {
-	if err := c.ensureTokenValid(); err != nil {
+	if err := c.EnsureTokenValid(); err != nil {
 		return nil, err
 	}
 	ns := namespaceID
@@ -309,6 +742,7 @@ func (c *NacosClient) ListConfigs(dataID, groupName, namespaceID string, pageNo,
 	}
 
 	if c.authLoginVersion == "v1" {
+		c.log.Debug("Listing configs with the v1 API, as the server only accepted a v1 login", "endpoint", "v1")
 		return c.listConfigsV1(dataID, groupName, ns, pageNo, pageSize)
 	}
 	params := url.Values{}
@@ -328,19 +762,21 @@ func (c *NacosClient) ListConfigs(dataID, groupName, namespaceID string, pageNo,
 	}
 
 	v3URL := fmt.Sprintf("http://%s/nacos/v3/admin/cs/config/list", c.ServerAddr)
-	req := c.httpClient.R().SetQueryString(params.Encode())
-	if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
-		req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-	}
-	c.setSpasHeaders(req, ns, groupName)
-	resp, err := req.Get(v3URL)
+	resp, err := c.send(func() *resty.Request {
+		req := c.httpClient.R().SetQueryString(params.Encode())
+		if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
+			req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+		}
+		c.setSpasHeaders(req, ns, groupName)
+		return req
+	}, resty.MethodGet, v3URL)
 
 	if err != nil {
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
 
 	if resp.StatusCode() != 200 {
-		return nil, ParseHTTPError(resp.StatusCode(), resp.Body(), "list configs")
+		return nil, responseError(resp, "list configs")
 	}
 
 	var v3Resp V3Response
@@ -348,7 +784,7 @@ func (c *NacosClient) ListConfigs(dataID, groupName, namespaceID string, pageNo,
 		return nil, err
 	}
 	if v3Resp.Code != 0 {
-		return nil, fmt.Errorf("list configs failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message)
+		return nil, WithRequestID(fmt.Errorf("list configs failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message), resp.RawResponse)
 	}
 	var configList ConfigListResponse
 	if err := json.Unmarshal(v3Resp.Data, &configList); err != nil {
@@ -359,7 +795,7 @@ func (c *NacosClient) ListConfigs(dataID, groupName, namespaceID string, pageNo,
 
 // listConfigsV1 retrieves configurations using Nacos v1 API
 func (c *NacosClient) listConfigsV1(dataID, groupName, namespace string, pageNo, pageSize int) (*ConfigListResponse, error) {
-	if err := c.ensureTokenValid(); err != nil {
+	if err := c.EnsureTokenValid(); err != nil {
 		return nil, err
 	}
 	params := url.Values{}
@@ -377,21 +813,22 @@ func (c *NacosClient) listConfigsV1(dataID, groupName, namespace string, pageNo,
 		params.Set("tenant", namespace)
 	}
 
-	if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
-		params.Set("accessToken", c.AccessToken)
-	}
-
 	v1URL := fmt.Sprintf("http://%s/nacos/v1/cs/configs", c.ServerAddr)
-	req := c.httpClient.R().SetQueryString(params.Encode())
-	c.setSpasHeaders(req, namespace, groupName)
-	resp, err := req.Get(v1URL)
+	resp, err := c.send(func() *resty.Request {
+		if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
+			params.Set("accessToken", c.AccessToken)
+		}
+		req := c.httpClient.R().SetQueryString(params.Encode())
+		c.setSpasHeaders(req, namespace, groupName)
+		return req
+	}, resty.MethodGet, v1URL)
 
 	if err != nil {
 		return nil, fmt.Errorf("v1 request failed: %w", err)
 	}
 
 	if resp.StatusCode() != 200 {
-		return nil, ParseHTTPError(resp.StatusCode(), resp.Body(), "list configs (v1)")
+		return nil, responseError(resp, "list configs (v1)")
 	}
 
 	var configList ConfigListResponse
@@ -402,11 +839,101 @@ func (c *NacosClient) listConfigsV1(dataID, groupName, namespace string, pageNo,
 	return &configList, nil
 }
 
-// GetConfig retrieves a specific configuration using v3 client API
+// expandPageSize is the page size ExpandConfigs lists configs with
+const expandPageSize = 100
+
+// ExpandConfigs returns every config of the current namespace whose dataId and
+// group match the patterns, in which * stands for any run of characters. It
+// lists them with the blur search, and drops any result the server matched
+// more loosely than the pattern. The contents are not fetched.
+func (c *NacosClient) ExpandConfigs(dataIDPattern, groupPattern string) ([]Config, error) {
+	var matched []Config
+	listed := 0
+	for page := 1; ; page++ {
+		resp, err := c.ListConfigs(dataIDPattern, groupPattern, "", page, expandPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, cfg := range resp.PageItems {
+			if cfg.GroupName == "" {
+				cfg.GroupName = cfg.Group
+			}
+			if wildcardMatch(dataIDPattern, cfg.DataID) && wildcardMatch(groupPattern, cfg.GroupName) {
+				matched = append(matched, cfg)
+			}
+		}
+		listed += len(resp.PageItems)
+		if len(resp.PageItems) == 0 || listed >= resp.TotalCount {
+			return matched, nil
+		}
+	}
+}
+
+// wildcardMatch reports whether s matches pattern, in which * stands for any
+// run of characters and everything else for itself. An empty pattern matches
+// everything, as it does in a config search.
+func wildcardMatch(pattern, s string) bool {
+	if pattern == "" {
+		return true
+	}
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return s == pattern
+	}
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(s, part)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(part):]
+	}
+	return strings.HasSuffix(s, last)
+}
+
+// GetConfig retrieves a specific configuration using v3 client API. The
+// content of an encrypted config is decrypted (see SetCipher).
 func (c *NacosClient) GetConfig(dataID, group string) (string, error) {
-	if err := c.ensureTokenValid(); err != nil {
+	config, err := c.GetConfigDetail(dataID, group)
+	if err != nil {
 		return "", err
 	}
+	return config.Content, nil
+}
+
+// GetConfigDetail retrieves a configuration together with its metadata. Type
+// is empty when the server does not report one. The content of an encrypted
+// config is decrypted (see SetCipher).
+func (c *NacosClient) GetConfigDetail(dataID, group string) (*Config, error) {
+	config, err := c.getConfigDetail(dataID, group)
+	if err != nil {
+		return nil, err
+	}
+	if config.Content, err = c.decrypt(dataID, config.Content); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// getConfigDetail is GetConfigDetail without the decryption
+func (c *NacosClient) getConfigDetail(dataID, group string) (*Config, error) {
+	var config *Config
+	err := c.RetryCorrupted(func() (err error) {
+		config, err = c.fetchConfigDetail(dataID, group)
+		return err
+	})
+	return config, err
+}
+
+// fetchConfigDetail requests a config once for getConfigDetail
+func (c *NacosClient) fetchConfigDetail(dataID, group string) (*Config, error) {
+	if err := c.EnsureTokenValid(); err != nil {
+		return nil, err
+	}
 
 	ns := c.Namespace
 	if ns == "public" {
@@ -421,29 +948,45 @@ func (c *NacosClient) GetConfig(dataID, group string) (string, error) {
 	}
 
 	apiURL := fmt.Sprintf("http://%s/nacos/v3/client/cs/config", c.ServerAddr)
-	req := c.httpClient.R().SetQueryString(params.Encode())
-	if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
-		req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-	}
-	c.setSpasHeaders(req, c.Namespace, group)
-	resp, err := req.Get(apiURL)
+	resp, err := c.send(func() *resty.Request {
+		req := c.httpClient.R().SetQueryString(params.Encode())
+		if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
+			req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+		}
+		c.setSpasHeaders(req, c.Namespace, group)
+		return req
+	}, resty.MethodGet, apiURL)
 
 	if err != nil {
-		return "", fmt.Errorf("get config failed: %w", err)
+		return nil, fmt.Errorf("get config failed: %w", err)
 	}
 
+	if resp.StatusCode() == 404 {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrConfigNotFound, dataID, group)
+	}
 	if resp.StatusCode() != 200 {
-		return "", ParseHTTPError(resp.StatusCode(), resp.Body(), "get config")
+		return nil, responseError(resp, "get config")
 	}
 
 	// Parse v3 response
 	var v3Resp V3Response
 	if err := json.Unmarshal(resp.Body(), &v3Resp); err != nil {
 		// If not JSON, return raw content (for backward compatibility)
-		return string(resp.Body()), nil
+		check, err := c.CheckContentMD5(resp.Header(), resp.Body(), fmt.Sprintf("config %s (%s)", dataID, group))
+		if err != nil {
+			return nil, err
+		}
+		if err := c.checkRawContent(resp.Header(), resp.Body()); err != nil {
+			return nil, fmt.Errorf("get config %s (%s): %w", dataID, group, err)
+		}
+		return &Config{DataID: dataID, Group: group, Content: string(resp.Body()), Type: resp.Header().Get("Config-Type"),
+			MD5: CalculateMD5(string(resp.Body())), MD5Check: check}, nil
+	}
+	if v3Resp.Code == codeConfigNotFound {
+		return nil, fmt.Errorf("%w: %s (%s)", ErrConfigNotFound, dataID, group)
 	}
 	if v3Resp.Code != 0 {
-		return "", fmt.Errorf("get config failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message)
+		return nil, WithRequestID(fmt.Errorf("get config failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message), resp.RawResponse)
 	}
 
 	// Parse config from data
@@ -452,17 +995,243 @@ func (c *NacosClient) GetConfig(dataID, group string) (string, error) {
 		// Try to return raw data as string
 		var rawContent string
 		if err := json.Unmarshal(v3Resp.Data, &rawContent); err != nil {
-			return string(v3Resp.Data), nil
+			rawContent = string(v3Resp.Data)
 		}
-		return rawContent, nil
+		return &Config{DataID: dataID, Group: group, Content: rawContent, MD5: CalculateMD5(rawContent), MD5Check: c.unchecked()}, nil
 	}
+	if config.Type == "" {
+		// The v3 client API reports the type as configType
+		var typed struct {
+			ConfigType string `json:"configType"`
+		}
+		json.Unmarshal(v3Resp.Data, &typed)
+		config.Type = typed.ConfigType
+	}
+	if config.DataID == "" {
+		config.DataID = dataID
+	}
+	if config.Group == "" {
+		config.Group = group
+	}
+	if config.MD5 == "" {
+		config.MD5 = CalculateMD5(config.Content)
+	}
+	// A JSON response carries no Content-MD5 for its content; one cut short
+	// fails to parse and is checked as raw content above
+	config.MD5Check = c.unchecked()
 
-	return config.Content, nil
+	return &config, nil
+}
+
+// GetConfigWithMD5 fetches a config the way the config listener polls it:
+// within ctx rather than the client timeout, in namespace tenant as given, and
+// signed for tenant and group. It returns the content and the MD5 reported by
+// the server, or the MD5 of the content when the server reports none. The
+// content of an encrypted config is decrypted (see SetCipher); the MD5 stays
+// that of the ciphertext, as stored. A config that does not exist returns
+// ErrConfigNotFound, another unexpected status a *StatusError.
+func (c *NacosClient) GetConfigWithMD5(ctx context.Context, dataID, group, tenant string) (string, string, error) {
+	content, contentMD5, err := c.getConfigWithMD5(ctx, dataID, group, tenant)
+	if err != nil {
+		return "", "", err
+	}
+	if content, err = c.decrypt(dataID, content); err != nil {
+		return "", "", err
+	}
+	return content, contentMD5, nil
+}
+
+// getConfigWithMD5 is GetConfigWithMD5 without the decryption
+func (c *NacosClient) getConfigWithMD5(ctx context.Context, dataID, group, tenant string) (content, contentMD5 string, err error) {
+	err = c.RetryCorrupted(func() (err error) {
+		content, contentMD5, err = c.fetchConfigWithMD5(ctx, dataID, group, tenant)
+		return err
+	})
+	return content, contentMD5, err
+}
+
+// fetchConfigWithMD5 requests a config once for getConfigWithMD5
+func (c *NacosClient) fetchConfigWithMD5(ctx context.Context, dataID, group, tenant string) (string, string, error) {
+	// Do refreshes the token before sending
+	params := url.Values{}
+	params.Set("dataId", dataID)
+	params.Set("groupName", group)
+	if tenant != "" {
+		params.Set("namespaceId", tenant)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("http://%s/nacos/v3/client/cs/config?%s", c.ServerAddr, params.Encode()), nil)
+	if err != nil {
+		return "", "", err
+	}
+	c.AuthorizeRequest(req, tenant, group)
+	// Bounded by ctx, as polls are, not by the client timeout
+	resp, err := c.do(req, 0)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", "", err
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return "", "", fmt.Errorf("%w: %s (%s)", ErrConfigNotFound, dataID, group)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", "", WithRequestID(&StatusError{StatusCode: resp.StatusCode, Body: string(body)}, resp)
+	}
+
+	var v3Resp struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+		Data    struct {
+			Content string `json:"content"`
+			Md5     string `json:"md5"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &v3Resp); err != nil {
+		// Not a v3 response: the body is the content
+		if _, err := c.CheckContentMD5(resp.Header, body, fmt.Sprintf("config %s (%s)", dataID, group)); err != nil {
+			return "", "", err
+		}
+		if err := c.checkRawContent(resp.Header, body); err != nil {
+			return "", "", fmt.Errorf("get config %s (%s): %w", dataID, group, err)
+		}
+		return string(body), CalculateMD5(string(body)), nil
+	}
+	if v3Resp.Code == codeConfigNotFound {
+		return "", "", fmt.Errorf("%w: %s (%s)", ErrConfigNotFound, dataID, group)
+	}
+	if v3Resp.Code != 0 {
+		return "", "", WithRequestID(fmt.Errorf("get config failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message), resp)
+	}
+	contentMD5 := v3Resp.Data.Md5
+	if contentMD5 == "" {
+		contentMD5 = CalculateMD5(v3Resp.Data.Content)
+	}
+	return v3Resp.Data.Content, contentMD5, nil
+}
+
+// checkRawContent checks a config response that is not a Nacos JSON response
+// before its body is taken as the content. Nacos sends raw content with a
+// Config-Type or Content-MD5 header; a body without either, HTML in
+// particular, is more likely an error page of a proxy than a config.
+func (c *NacosClient) checkRawContent(header http.Header, body []byte) error {
+	if c.noValidation || header.Get("Config-Type") != "" || header.Get("Content-MD5") != "" {
+		return nil
+	}
+	what := "a response without the Nacos config headers"
+	if looksLikeHTML(header.Get("Content-Type"), body) {
+		what = "an HTML page"
+	}
+	return fmt.Errorf("%w: the server sent %s (Content-Type %q): %q; check that the address points at Nacos rather than a gateway, or pass --no-content-validation",
+		ErrUnexpectedContent, what, header.Get("Content-Type"), snippet(body, 120))
+}
+
+// CheckContentMD5 checks a downloaded body against the Content-MD5 header of
+// its response, which Nacos sends in hex and RFC 1864 in base64. A mismatch
+// returns ErrContentCorrupted (wrapped), naming the download as what.
+func (c *NacosClient) CheckContentMD5(header http.Header, body []byte, what string) (MD5Check, error) {
+	want := strings.TrimSpace(header.Get("Content-MD5"))
+	if c.noMD5Check || want == "" {
+		return c.unchecked(), nil
+	}
+	sum := md5.Sum(body)
+	if strings.EqualFold(want, hex.EncodeToString(sum[:])) || want == base64.StdEncoding.EncodeToString(sum[:]) {
+		return MD5Verified, nil
+	}
+	return "", fmt.Errorf("%w: %s arrived as %d bytes with MD5 %x, but the server sent Content-MD5 %s; pass --no-verify-md5 if the server's header is wrong",
+		ErrContentCorrupted, what, len(body), sum, want)
+}
+
+// unchecked is the MD5Check of content that had no Content-MD5 to check
+// against, or was not checked
+func (c *NacosClient) unchecked() MD5Check {
+	if c.noMD5Check {
+		return MD5Skipped
+	}
+	return MD5NotSent
+}
+
+// RetryCorrupted runs fetch, and once more if it fails with
+// ErrContentCorrupted: a proxy that cut one response short may pass the next
+func (c *NacosClient) RetryCorrupted(fetch func() error) error {
+	err := fetch()
+	if errors.Is(err, ErrContentCorrupted) {
+		c.log.Warn(fmt.Sprintf("Downloading again: %v", err), "error", err)
+		err = fetch()
+	}
+	return err
 }
 
-// PublishConfig publishes a configuration
+// looksLikeHTML reports whether a response is an HTML page
+func looksLikeHTML(contentType string, body []byte) bool {
+	if strings.HasPrefix(strings.ToLower(contentType), "text/html") {
+		return true
+	}
+	start := strings.ToLower(strings.TrimSpace(string(body[:min(len(body), 512)])))
+	return strings.HasPrefix(start, "<!doctype html") || strings.HasPrefix(start, "<html")
+}
+
+// snippet returns the start of body on one line, for error messages
+func snippet(body []byte, limit int) string {
+	s := strings.Join(strings.Fields(string(body)), " ")
+	if len(s) > limit {
+		s = s[:limit] + "..."
+	}
+	return s
+}
+
+// CalculateMD5 returns the hex MD5 of content, as Nacos computes it for a config
+func CalculateMD5(content string) string {
+	return fmt.Sprintf("%x", md5.Sum([]byte(content)))
+}
+
+// PublishConfig publishes a configuration. The content of an encrypted
+// config is encrypted first (see SetCipher).
 func (c *NacosClient) PublishConfig(dataID, group, content string) error {
-	if err := c.ensureTokenValid(); err != nil {
+	return c.publishConfig(dataID, group, content, "")
+}
+
+// PublishConfigCAS publishes a configuration only if it still has casMD5,
+// the Config.MD5 the new content is based on, so that a publish by someone
+// else in between is not overwritten. An empty casMD5 requires that the
+// config does not exist yet. The server is asked to compare the MD5 itself
+// (casMd5), and since not every server does, the current MD5 is also
+// checked just before publishing. A mismatch returns ErrConfigChanged.
+func (c *NacosClient) PublishConfigCAS(dataID, group, content, casMD5 string) error {
+	current, err := c.getConfigDetail(dataID, group)
+	switch {
+	case errors.Is(err, ErrConfigNotFound):
+		if casMD5 != "" {
+			return fmt.Errorf("%w: %s (%s) was deleted", ErrConfigChanged, dataID, group)
+		}
+	case err != nil:
+		return err
+	case casMD5 == "":
+		return fmt.Errorf("%w: %s (%s) was created", ErrConfigChanged, dataID, group)
+	case !strings.EqualFold(current.MD5, casMD5):
+		return fmt.Errorf("%w: %s (%s) now has MD5 %s, not %s", ErrConfigChanged, dataID, group, current.MD5, casMD5)
+	}
+	return c.publishConfig(dataID, group, content, casMD5)
+}
+
+// casRejected reports whether a failed publish was rejected because the
+// config no longer had the casMd5 sent with it
+func casRejected(body []byte) bool {
+	text := strings.ToLower(string(body))
+	return strings.Contains(text, "cas publish fail") || strings.Contains(text, "md5 may have changed")
+}
+
+// publishConfig publishes a configuration, only if it has casMD5 unless
+// casMD5 is empty
+func (c *NacosClient) publishConfig(dataID, group, content, casMD5 string) error {
+	content, err := c.encrypt(dataID, content)
+	if err != nil {
+		return err
+	}
+	if err := c.EnsureTokenValid(); err != nil {
 		return err
 	}
 	params := map[string]string{
@@ -474,21 +1243,68 @@ func (c *NacosClient) PublishConfig(dataID, group, content string) error {
 	if c.Namespace != "" {
 		params["namespaceId"] = c.Namespace
 	}
+	if casMD5 != "" {
+		params["casMd5"] = casMD5
+	}
 
 	apiURL := fmt.Sprintf("http://%s/nacos/v3/admin/cs/config", c.ServerAddr)
-	req := c.httpClient.R().SetFormData(params)
-	if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
-		req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
-	}
-	c.setSpasHeaders(req, c.Namespace, group)
-	resp, err := req.Post(apiURL)
+	resp, err := c.send(func() *resty.Request {
+		req := c.httpClient.R().SetFormData(params)
+		if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
+			req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+		}
+		if casMD5 != "" {
+			// Nacos reads casMd5 from a header in some versions
+			req.SetHeader("casMd5", casMD5)
+		}
+		c.setSpasHeaders(req, c.Namespace, group)
+		return req
+	}, resty.MethodPost, apiURL)
 
 	if err != nil {
 		return fmt.Errorf("publish config failed: %w", err)
 	}
 
+	if casMD5 != "" && casRejected(resp.Body()) {
+		return WithRequestID(fmt.Errorf("%w: %s (%s) no longer has MD5 %s", ErrConfigChanged, dataID, group, casMD5), resp.RawResponse)
+	}
+	return WithRequestID(booleanResult(resp, "publish config"), resp.RawResponse)
+}
+
+// DeleteConfig deletes a configuration from the current namespace
+func (c *NacosClient) DeleteConfig(dataID, group string) error {
+	if err := c.EnsureTokenValid(); err != nil {
+		return err
+	}
+	params := url.Values{}
+	params.Set("dataId", dataID)
+	params.Set("groupName", group)
+	if c.Namespace != "" {
+		params.Set("namespaceId", c.Namespace)
+	}
+
+	apiURL := fmt.Sprintf("http://%s/nacos/v3/admin/cs/config", c.ServerAddr)
+	resp, err := c.send(func() *resty.Request {
+		req := c.httpClient.R().SetQueryString(params.Encode())
+		if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
+			req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+		}
+		c.setSpasHeaders(req, c.Namespace, group)
+		return req
+	}, resty.MethodDelete, apiURL)
+
+	if err != nil {
+		return fmt.Errorf("delete config failed: %w", err)
+	}
+
+	return WithRequestID(booleanResult(resp, "delete config"), resp.RawResponse)
+}
+
+// booleanResult checks the response to a publish or delete, which Nacos
+// answers with true, as is or as the data of a v3 response
+func booleanResult(resp *resty.Response, operation string) error {
 	if resp.StatusCode() != 200 {
-		return ParseHTTPError(resp.StatusCode(), resp.Body(), "publish config")
+		return ParseHTTPError(resp.StatusCode(), resp.Body(), operation)
 	}
 
 	var v3Resp V3Response
@@ -496,18 +1312,42 @@ func (c *NacosClient) PublishConfig(dataID, group, content string) error {
 		if string(resp.Body()) == "true" {
 			return nil
 		}
-		return fmt.Errorf("publish config failed: invalid response format: %s", string(resp.Body()))
+		return fmt.Errorf("%s failed: invalid response format: %s", operation, string(resp.Body()))
 	}
 	if v3Resp.Code != 0 {
-		return fmt.Errorf("publish config failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message)
+		return fmt.Errorf("%s failed: code=%d, message=%s", operation, v3Resp.Code, v3Resp.Message)
 	}
 	var result bool
 	if err := json.Unmarshal(v3Resp.Data, &result); err != nil {
-		return fmt.Errorf("publish config failed: invalid data format: %w", err)
+		return fmt.Errorf("%s failed: invalid data format: %w", operation, err)
 	}
 	if !result {
-		return fmt.Errorf("publish config failed: server returned false")
+		return fmt.Errorf("%s failed: server returned false", operation)
 	}
-
 	return nil
 }
+
+// GroupCount is a config group and how many configs it holds
+type GroupCount struct {
+	Group   string `json:"group"`
+	Configs int    `json:"configs"`
+}
+
+// ListGroups returns the groups of the current namespace with their number of
+// configs, sorted by name. Nacos has no group API, so it lists every config.
+func (c *NacosClient) ListGroups() ([]GroupCount, error) {
+	configs, err := c.ExpandConfigs("", "")
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, cfg := range configs {
+		counts[cfg.GroupName]++
+	}
+	groups := make([]GroupCount, 0, len(counts))
+	for group, n := range counts {
+		groups = append(groups, GroupCount{Group: group, Configs: n})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].Group < groups[j].Group })
+	return groups, nil
+}
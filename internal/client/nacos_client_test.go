@@ -0,0 +1,885 @@
+package client
+
+import (
+	"compress/gzip"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+// newAuthServer returns a server that issues token-1, token-2, ... on login and
+// accepts only the most recent token, as if earlier ones had expired
+func newAuthServer(t *testing.T) (*httptest.Server, *int32) {
+	return newAuthServerRejecting(t, http.StatusForbidden)
+}
+
+// newAuthServerRejecting is newAuthServer answering an outdated token with status
+func newAuthServerRejecting(t *testing.T, status int) (*httptest.Server, *int32) {
+	var logins int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nacos/v3/auth/user/login", func(w http.ResponseWriter, r *http.Request) {
+		if r.FormValue("password") != "secret" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		n := atomic.AddInt32(&logins, 1)
+		fmt.Fprintf(w, `{"accessToken":"token-%d","tokenTtl":18000}`, n)
+	})
+	authorized := func(r *http.Request) bool {
+		return r.Header.Get("Authorization") == fmt.Sprintf("Bearer token-%d", atomic.LoadInt32(&logins))
+	}
+	mux.HandleFunc("/nacos/v3/client/cs/config", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"data":{"content":"a: 1"}}`)
+	})
+	mux.HandleFunc("/nacos/v3/admin/cs/config", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"data":true}`)
+	})
+	mux.HandleFunc("/nacos/v3/admin/cs/config/list", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(status)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"data":{"totalCount":1,"pageItems":[{"dataId":"app.yaml","group":"DEFAULT_GROUP"}]}}`)
+	})
+	mux.HandleFunc("/upload", func(w http.ResponseWriter, r *http.Request) {
+		if !authorized(r) {
+			w.WriteHeader(status)
+			return
+		}
+		io.Copy(w, r.Body)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &logins
+}
+
+func TestReloginAfterForbidden(t *testing.T) {
+	server, logins := newAuthServer(t)
+	c, err := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "nacos", "secret", "", "", "")
+	if err != nil {
+		t.Fatalf("NewNacosClient() error = %v", err)
+	}
+
+	// Simulate the server revoking the token
+	atomic.AddInt32(logins, 1)
+	content, err := c.GetConfig("app.yaml", "DEFAULT_GROUP")
+	if err != nil {
+		t.Fatalf("GetConfig() error = %v", err)
+	}
+	if content != "a: 1" {
+		t.Errorf("GetConfig() = %q, want %q", content, "a: 1")
+	}
+
+	atomic.AddInt32(logins, 1)
+	req, _ := http.NewRequest("POST", server.URL+"/upload", strings.NewReader("payload"))
+	resp, err := c.Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || string(body) != "payload" {
+		t.Errorf("Do() = %d %q, want 200 %q", resp.StatusCode, body, "payload")
+	}
+}
+
+func TestReloginAfterRevokedToken(t *testing.T) {
+	requests := map[string]func(c *NacosClient) error{
+		"ListConfigs": func(c *NacosClient) error {
+			_, err := c.ListConfigs("", "", "", 1, 10)
+			return err
+		},
+		"GetConfig": func(c *NacosClient) error {
+			_, err := c.GetConfig("app.yaml", "DEFAULT_GROUP")
+			return err
+		},
+		"PublishConfig": func(c *NacosClient) error {
+			return c.PublishConfig("app.yaml", "DEFAULT_GROUP", "a: 2")
+		},
+		"GetConfigWithMD5": func(c *NacosClient) error {
+			_, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", "")
+			return err
+		},
+	}
+	for _, status := range []int{http.StatusUnauthorized, http.StatusForbidden} {
+		for name, request := range requests {
+			t.Run(fmt.Sprintf("%s after %d", name, status), func(t *testing.T) {
+				server, logins := newAuthServerRejecting(t, status)
+				c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "nacos", "secret", "", "", "")
+				if err := request(c); err != nil {
+					t.Fatalf("first request error = %v", err)
+				}
+
+				// Revoke the token mid-session; the request logs in again and is replayed once
+				atomic.AddInt32(logins, 1)
+				if err := request(c); err != nil {
+					t.Fatalf("request after revocation error = %v", err)
+				}
+				if got := atomic.LoadInt32(logins); got != 3 || c.AccessToken != "token-3" {
+					t.Errorf("logins = %d, token %q; want 3, token-3", got, c.AccessToken)
+				}
+			})
+		}
+	}
+
+	t.Run("concurrent rejections log in once", func(t *testing.T) {
+		server, logins := newAuthServer(t)
+		c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "nacos", "secret", "", "", "")
+		if err := c.Login(); err != nil {
+			t.Fatal(err)
+		}
+		atomic.AddInt32(logins, 1)
+		errs := make(chan error, 8)
+		for i := 0; i < cap(errs); i++ {
+			go func() {
+				_, err := c.GetConfig("app.yaml", "DEFAULT_GROUP")
+				errs <- err
+			}()
+		}
+		for i := 0; i < cap(errs); i++ {
+			if err := <-errs; err != nil {
+				t.Errorf("GetConfig() error = %v", err)
+			}
+		}
+		if got := atomic.LoadInt32(logins); got != 3 {
+			t.Errorf("logins = %d, want 3", got)
+		}
+	})
+
+	t.Run("failed login surfaces the rejection", func(t *testing.T) {
+		server, logins := newAuthServer(t)
+		c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "nacos", "secret", "", "", "")
+		if err := c.Login(); err != nil {
+			t.Fatal(err)
+		}
+		atomic.AddInt32(logins, 1)
+		c.Password = "rotated"
+		if err := c.PublishConfig("app.yaml", "DEFAULT_GROUP", "a: 2"); err == nil || !strings.Contains(err.Error(), "403") {
+			t.Errorf("PublishConfig() error = %v, want the 403", err)
+		}
+		if c.AccessToken != "" {
+			t.Errorf("token %q kept after the server rejected it", c.AccessToken)
+		}
+	})
+}
+
+func TestLazyLogin(t *testing.T) {
+	var requests int32
+	server, _ := newAuthServer(t)
+	counting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		server.Config.Handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(counting.Close)
+
+	c, err := NewNacosClient(strings.TrimPrefix(counting.URL, "http://"), "", "", "nacos", "wrong", "", "", "")
+	if err != nil {
+		t.Fatalf("NewNacosClient() error = %v", err)
+	}
+	if n := atomic.LoadInt32(&requests); n != 0 {
+		t.Errorf("NewNacosClient() sent %d requests, want none", n)
+	}
+
+	// The first request logs in and reports the failure
+	if _, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); !errors.Is(err, ErrLoginFailed) {
+		t.Errorf("GetConfig() error = %v, want ErrLoginFailed", err)
+	}
+	req, _ := http.NewRequest("GET", counting.URL+"/upload", nil)
+	if _, err := c.Do(req); !errors.Is(err, ErrLoginFailed) {
+		t.Errorf("Do() error = %v, want ErrLoginFailed", err)
+	}
+
+	c.Password = "secret"
+	if err := c.Login(); err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	if c.AccessToken == "" || c.TokenExpireAt.IsZero() {
+		t.Errorf("Login() did not store the token: %q, %v", c.AccessToken, c.TokenExpireAt)
+	}
+
+	// A server that cannot be reached is not an auth failure
+	unreachable, _ := NewNacosClient(strings.TrimPrefix(counting.URL, "http://"), "", "", "nacos", "secret", "", "", "")
+	counting.Close()
+	if err := unreachable.Login(); err == nil || errors.Is(err, ErrLoginFailed) {
+		t.Errorf("Login() to a closed server error = %v, want a connection error", err)
+	}
+}
+
+// captureStdout returns what f writes to stdout
+func captureStdout(t *testing.T, f func()) string {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+	f()
+	w.Close()
+	out, _ := io.ReadAll(r)
+	return string(out)
+}
+
+func TestLoginFailureKeepsStdoutClean(t *testing.T) {
+	server, _ := newAuthServer(t)
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "nacos", "wrong", "", "", "")
+	var err error
+	out := captureStdout(t, func() {
+		err = c.Login()
+	})
+	if out != "" {
+		t.Errorf("failed login wrote to stdout: %q", out)
+	}
+	// Both attempts are in the error instead
+	if !errors.Is(err, ErrLoginFailed) || !strings.Contains(err.Error(), "v3 status=403") || !strings.Contains(err.Error(), "v1 status=404") {
+		t.Errorf("Login() error = %v, want both login attempts", err)
+	}
+}
+
+func TestGetConfigWithMD5(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		body        string
+		wantContent string
+		wantMD5     string
+		wantErr     func(error) bool
+	}{
+		{"md5 reported by the server", 200, `{"code":0,"data":{"content":"a: 1","md5":"abc"}}`, "a: 1", "abc", nil},
+		{"md5 computed from the content", 200, `{"code":0,"data":{"content":"a: 1"}}`, "a: 1", CalculateMD5("a: 1"), nil},
+		{"raw content", 200, "a: 1", "a: 1", CalculateMD5("a: 1"), nil},
+		{"not found status", 404, "", "", "", func(err error) bool { return errors.Is(err, ErrConfigNotFound) }},
+		{"not found code", 200, `{"code":20004,"message":"config data not exist"}`, "", "", func(err error) bool { return errors.Is(err, ErrConfigNotFound) }},
+		{"bad request", 400, "dataId is invalid", "", "", func(err error) bool {
+			var statusErr *StatusError
+			return errors.As(err, &statusErr) && statusErr.StatusCode == 400 && statusErr.Body == "dataId is invalid"
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var query string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path != "/nacos/v3/client/cs/config" {
+					http.NotFound(w, r)
+					return
+				}
+				query = r.URL.RawQuery
+				w.Header().Set("Config-Type", "yaml")
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+			c, err := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			content, md5, err := c.GetConfigWithMD5(context.Background(), "skill.json", "skill_demo", "dev")
+			if query != "dataId=skill.json&groupName=skill_demo&namespaceId=dev" {
+				t.Errorf("query = %q", query)
+			}
+			if tt.wantErr != nil {
+				if !tt.wantErr(err) {
+					t.Errorf("GetConfigWithMD5() error = %v", err)
+				}
+				return
+			}
+			if err != nil || content != tt.wantContent || md5 != tt.wantMD5 {
+				t.Errorf("GetConfigWithMD5() = %q, %q, %v; want %q, %q", content, md5, err, tt.wantContent, tt.wantMD5)
+			}
+		})
+	}
+}
+
+func TestRawContentValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		contentType string
+		header      string
+		body        string
+		disabled    bool
+		wantErr     string
+	}{
+		{"config type header", "text/plain", "Config-Type", "a: 1", false, ""},
+		{"content md5 header", "text/plain", "Content-MD5", "a: 1", false, ""},
+		{"gateway error page", "text/html", "", "<html>\n  <body>502 Bad Gateway</body>\n</html>", false, "an HTML page"},
+		{"html without a content type", "", "", "<!DOCTYPE html><p>login</p>", false, "an HTML page"},
+		{"plain text without headers", "text/plain", "", "a: 1", false, "without the Nacos config headers"},
+		{"validation disabled", "text/html", "", "<html></html>", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				if tt.header != "" {
+					w.Header().Set(tt.header, CalculateMD5(tt.body))
+				}
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+			c.SetContentValidation(!tt.disabled)
+
+			content, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", "")
+			_, detailErr := c.GetConfigDetail("app.yaml", "DEFAULT_GROUP")
+			if tt.wantErr == "" {
+				if err != nil || detailErr != nil || content != tt.body {
+					t.Errorf("GetConfigWithMD5() = %q, %v; GetConfigDetail() error = %v", content, err, detailErr)
+				}
+				return
+			}
+			for _, err := range []error{err, detailErr} {
+				if !errors.Is(err, ErrUnexpectedContent) || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("error = %v, want ErrUnexpectedContent mentioning %q", err, tt.wantErr)
+				}
+			}
+			if strings.Contains(err.Error(), "\n") {
+				t.Errorf("error %q spans lines", err)
+			}
+		})
+	}
+}
+
+func TestContentMD5Verification(t *testing.T) {
+	const content = "server:\n  port: 8080\n"
+	sum := md5.Sum([]byte(content))
+	tests := []struct {
+		name         string
+		header       string
+		truncate     int // responses cut short before a complete one
+		disabled     bool
+		wantCheck    MD5Check
+		wantRequests int
+		wantErr      bool
+	}{
+		{"hex header", CalculateMD5(content), 0, false, MD5Verified, 1, false},
+		{"base64 header", base64.StdEncoding.EncodeToString(sum[:]), 0, false, MD5Verified, 1, false},
+		{"no header", "", 0, false, MD5NotSent, 1, false},
+		{"truncated once", CalculateMD5(content), 1, false, MD5Verified, 2, false},
+		{"truncated twice", CalculateMD5(content), 2, false, "", 2, true},
+		{"verification disabled", CalculateMD5(content), 2, true, MD5Skipped, 1, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				w.Header().Set("Config-Type", "yaml")
+				if tt.header != "" {
+					w.Header().Set("Content-MD5", tt.header)
+				}
+				body := content
+				if requests <= tt.truncate {
+					body = content[:10]
+				}
+				fmt.Fprint(w, body)
+			}))
+			defer server.Close()
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+			c.SetMD5Verification(!tt.disabled)
+			c.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+			config, err := c.GetConfigDetail("app.yaml", "DEFAULT_GROUP")
+			if requests != tt.wantRequests {
+				t.Errorf("%d requests, want %d", requests, tt.wantRequests)
+			}
+			if tt.wantErr {
+				if !errors.Is(err, ErrContentCorrupted) || !strings.Contains(err.Error(), "app.yaml") {
+					t.Errorf("GetConfigDetail() error = %v, want ErrContentCorrupted", err)
+				}
+				requests = 0
+				if _, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", ""); !errors.Is(err, ErrContentCorrupted) {
+					t.Errorf("GetConfigWithMD5() error = %v, want ErrContentCorrupted", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetConfigDetail() error = %v", err)
+			}
+			if config.MD5Check != tt.wantCheck || (!tt.disabled && config.Content != content) {
+				t.Errorf("GetConfigDetail() = %q, %q; want %q", config.Content, config.MD5Check, tt.wantCheck)
+			}
+		})
+	}
+}
+
+func TestCompression(t *testing.T) {
+	content := strings.Repeat(`{"key":"value","list":[1,2,3]},`, 2000)
+	var gzipped, plain int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body string
+		switch r.URL.Path {
+		case "/nacos/v3/admin/cs/config/list":
+			body = `{"code":0,"data":{"totalCount":1,"pageItems":[{"dataId":"big.json","content":` + fmt.Sprintf("%q", content) + `}]}}`
+		default:
+			body = `{"code":0,"data":{"content":` + fmt.Sprintf("%q", content) + `}}`
+		}
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), "gzip") {
+			atomic.AddInt32(&plain, 1)
+			fmt.Fprint(w, body)
+			return
+		}
+		atomic.AddInt32(&gzipped, 1)
+		w.Header().Set("Content-Encoding", "gzip")
+		zw := gzip.NewWriter(w)
+		fmt.Fprint(zw, body)
+		zw.Close()
+	}))
+	defer server.Close()
+
+	for _, enabled := range []bool{true, false} {
+		t.Run(fmt.Sprintf("compression %v", enabled), func(t *testing.T) {
+			atomic.StoreInt32(&gzipped, 0)
+			atomic.StoreInt32(&plain, 0)
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+			c.SetCompression(enabled)
+
+			got, err := c.GetConfig("big.json", "DEFAULT_GROUP")
+			if err != nil || got != content {
+				t.Errorf("GetConfig() = %d bytes, %v; want the %d decompressed bytes", len(got), err, len(content))
+			}
+			list, err := c.ListConfigs("", "", "", 1, 10)
+			if err != nil || len(list.PageItems) != 1 || list.PageItems[0].Content != content {
+				t.Errorf("ListConfigs() = %+v, %v", list, err)
+			}
+			// The listener's MD5 is that of the decompressed content
+			got, md5, err := c.GetConfigWithMD5(context.Background(), "big.json", "DEFAULT_GROUP", "")
+			if err != nil || got != content || md5 != CalculateMD5(content) {
+				t.Errorf("GetConfigWithMD5() = %d bytes, md5 %s, %v; want md5 %s", len(got), md5, err, CalculateMD5(content))
+			}
+
+			wantGzipped, wantPlain := int32(3), int32(0)
+			if !enabled {
+				wantGzipped, wantPlain = 0, 3
+			}
+			if gzipped != wantGzipped || plain != wantPlain {
+				t.Errorf("%d gzipped and %d plain responses, want %d and %d", gzipped, plain, wantGzipped, wantPlain)
+			}
+		})
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	tests := []struct {
+		pattern string
+		s       string
+		want    bool
+	}{
+		{"", "anything", true},
+		{"app.yaml", "app.yaml", true},
+		{"app.yaml", "app.yml", false},
+		{"app.*.yaml", "app.prod.yaml", true},
+		{"app.*.yaml", "app..yaml", true},
+		{"app.*.yaml", "app.yaml", false},
+		{"app.*.yaml", "app.prod.yaml.bak", false},
+		{"team_*", "team_a", true},
+		{"team_*", "my_team_a", false},
+		{"*", "", true},
+		{"*x*x", "xx", true},
+		{"*x*x", "x", false},
+		{"a*b*c", "abbc", true},
+		{"a*b*c", "acb", false},
+	}
+	for _, tt := range tests {
+		if got := wildcardMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("wildcardMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestExpandConfigs(t *testing.T) {
+	// The server matches loosely and pages its results; the client keeps what
+	// the patterns match, from every page
+	var items []string
+	for i := 0; i < 120; i++ {
+		items = append(items, fmt.Sprintf(`{"dataId":"app.%d.yaml","groupName":"team_a"}`, i))
+	}
+	items = append(items, `{"dataId":"app.yaml","groupName":"team_a"}`, `{"dataId":"app.1.yaml","groupName":"other"}`)
+	var searches []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		searches = append(searches, q.Get("search"))
+		page, _ := strconv.Atoi(q.Get("pageNo"))
+		size, _ := strconv.Atoi(q.Get("pageSize"))
+		from, to := min((page-1)*size, len(items)), min(page*size, len(items))
+		fmt.Fprintf(w, `{"code":0,"data":{"totalCount":%d,"pageItems":[%s]}}`, len(items), strings.Join(items[from:to], ","))
+	}))
+	defer server.Close()
+
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	configs, err := c.ExpandConfigs("app.*.yaml", "team_*")
+	if err != nil {
+		t.Fatalf("ExpandConfigs() error = %v", err)
+	}
+	if len(configs) != 120 || configs[119].DataID != "app.119.yaml" || configs[0].GroupName != "team_a" {
+		t.Errorf("ExpandConfigs() = %d configs, want app.0.yaml to app.119.yaml in team_a", len(configs))
+	}
+	if len(searches) != 2 || searches[0] != "blur" {
+		t.Errorf("searches = %v, want 2 pages of blur search", searches)
+	}
+}
+
+func TestDeleteConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"deleted", 200, `{"code":0,"data":true}`, ""},
+		{"v1 style answer", 200, `true`, ""},
+		{"server returned false", 200, `{"code":0,"data":false}`, "server returned false"},
+		{"error code", 200, `{"code":20004,"message":"config not exist"}`, "config not exist"},
+		{"forbidden", 403, `{"code":403,"message":"no permission"}`, "403"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got *http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				w.WriteHeader(tt.status)
+				fmt.Fprint(w, tt.body)
+			}))
+			defer server.Close()
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "dev", "", "", "", "", "", "")
+
+			err := c.DeleteConfig("app.yaml", "team_a")
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("DeleteConfig() error = %v, want %q", err, tt.wantErr)
+			}
+			q := got.URL.Query()
+			if got.Method != http.MethodDelete || got.URL.Path != "/nacos/v3/admin/cs/config" || q.Get("dataId") != "app.yaml" || q.Get("groupName") != "team_a" || q.Get("namespaceId") != "dev" {
+				t.Errorf("request = %s %s", got.Method, got.URL)
+			}
+		})
+	}
+}
+
+func TestPublishConfigCAS(t *testing.T) {
+	tests := []struct {
+		name      string
+		exists    bool
+		casMD5    string
+		reject    bool // the server fails the publish as Nacos does for a wrong casMd5
+		wantErr   string
+		published bool
+	}{
+		{"unchanged", true, "m1", false, "", true},
+		{"changed", true, "m0", false, "now has MD5 m1, not m0", false},
+		{"deleted", false, "m1", false, "was deleted", false},
+		{"created", true, "", false, "was created", false},
+		{"new config", false, "", false, "", true},
+		{"changed just before publishing", true, "m1", true, "no longer has MD5 m1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var publish *http.Request
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.URL.Path == "/nacos/v3/client/cs/config" && !tt.exists:
+					w.WriteHeader(http.StatusNotFound)
+					fmt.Fprint(w, `{"code":20004,"message":"config data not exist"}`)
+				case r.URL.Path == "/nacos/v3/client/cs/config":
+					fmt.Fprint(w, `{"code":0,"data":{"content":"a: 1","md5":"m1"}}`)
+				case tt.reject:
+					r.ParseForm()
+					publish = r
+					w.WriteHeader(http.StatusInternalServerError)
+					fmt.Fprint(w, `{"code":500,"message":"Cas publish fail, server md5 may have changed."}`)
+				default:
+					r.ParseForm()
+					publish = r
+					fmt.Fprint(w, `{"code":0,"data":true}`)
+				}
+			}))
+			defer server.Close()
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+
+			err := c.PublishConfigCAS("app.yaml", "DEFAULT_GROUP", "a: 2", tt.casMD5)
+			if tt.wantErr == "" && err != nil || tt.wantErr != "" && (!errors.Is(err, ErrConfigChanged) || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("PublishConfigCAS() error = %v, want ErrConfigChanged mentioning %q", err, tt.wantErr)
+			}
+			if (publish != nil) != tt.published {
+				t.Fatalf("published = %v, want %v", publish != nil, tt.published)
+			}
+			if publish != nil && (publish.Form.Get("casMd5") != tt.casMD5 || publish.Header.Get("casMd5") != tt.casMD5) {
+				t.Errorf("casMd5 = %q (header %q), want %q", publish.Form.Get("casMd5"), publish.Header.Get("casMd5"), tt.casMD5)
+			}
+		})
+	}
+}
+
+func TestListGroups(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// v1 answers carry the group in group, v3 answers in groupName
+		fmt.Fprint(w, `{"code":0,"data":{"totalCount":4,"pageItems":[
+			{"dataId":"a","groupName":"team_b"},{"dataId":"b","groupName":"team_a"},
+			{"dataId":"c","group":"team_b"},{"dataId":"d","groupName":"DEFAULT_GROUP"}]}}`)
+	}))
+	defer server.Close()
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+
+	groups, err := c.ListGroups()
+	want := []GroupCount{{"DEFAULT_GROUP", 1}, {"team_a", 1}, {"team_b", 2}}
+	if err != nil || !slices.Equal(groups, want) {
+		t.Errorf("ListGroups() = %v, %v; want %v", groups, err, want)
+	}
+}
+
+func TestDebugLogHidesSecrets(t *testing.T) {
+	logging.SetLevel(slog.LevelDebug)
+	t.Cleanup(func() { logging.SetLevel(slog.LevelInfo) })
+	server, logins := newAuthServer(t)
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "nacos", "secret", "", "", "")
+	var log strings.Builder
+	c.SetLogger(slog.New(logging.NewConsoleHandler(func(msg string) {
+		log.WriteString(msg + "\n")
+	})))
+
+	if _, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatal(err)
+	}
+	atomic.AddInt32(logins, 1)
+	if _, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.ListConfigs("", "", "", 1, 10); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{"Logging in with the v3 API", "Token was rejected", "GET /nacos/v3/client/cs/config status=403", "GET /nacos/v3/admin/cs/config/list status=200"} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("debug log lacks %q:\n%s", want, log.String())
+		}
+	}
+	for _, secret := range []string{"secret", "token-1", "token-2", "dataId="} {
+		if strings.Contains(log.String(), secret) {
+			t.Errorf("debug log contains %q:\n%s", secret, log.String())
+		}
+	}
+}
+
+// prefixCipher "encrypts" by prefixing enc:
+type prefixCipher struct{}
+
+func (prefixCipher) Encrypt(plaintext string) (string, error) { return "enc:" + plaintext, nil }
+
+func (prefixCipher) Decrypt(ciphertext string) (string, error) {
+	if !strings.HasPrefix(ciphertext, "enc:") {
+		return "", errors.New("invalid ciphertext")
+	}
+	return strings.TrimPrefix(ciphertext, "enc:"), nil
+}
+
+func TestEncryptedConfigs(t *testing.T) {
+	var published string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			published = r.FormValue("content")
+			fmt.Fprint(w, `{"code":0,"data":true}`)
+			return
+		}
+		content := "plain: 1"
+		if IsEncrypted(r.URL.Query().Get("dataId")) {
+			content = "enc:password: s3cret"
+		}
+		fmt.Fprintf(w, `{"code":0,"data":{"content":%q,"md5":"stored-md5"}}`, content)
+	}))
+	defer server.Close()
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	var log strings.Builder
+	c.SetLogger(slog.New(logging.NewConsoleHandler(func(msg string) {
+		log.WriteString(msg + "\n")
+	})))
+
+	// Without a cipher: ciphertext with a warning, and no publishing
+	if content, err := c.GetConfig("cipher-db.yaml", "DEFAULT_GROUP"); err != nil || content != "enc:password: s3cret" {
+		t.Errorf("GetConfig() without a cipher = %q, %v", content, err)
+	}
+	if !strings.Contains(log.String(), "cipher-db.yaml is encrypted") {
+		t.Errorf("no warning about the ciphertext:\n%s", log.String())
+	}
+	if err := c.PublishConfig("cipher-db.yaml", "DEFAULT_GROUP", "password: s3cret"); !errors.Is(err, ErrNoCipher) || published != "" {
+		t.Errorf("PublishConfig() without a cipher error = %v, published %q", err, published)
+	}
+
+	c.SetCipher(prefixCipher{})
+	log.Reset()
+	if content, err := c.GetConfig("cipher-db.yaml", "DEFAULT_GROUP"); err != nil || content != "password: s3cret" {
+		t.Errorf("GetConfig() = %q, %v", content, err)
+	}
+	if content, md5, err := c.GetConfigWithMD5(context.Background(), "cipher-db.yaml", "DEFAULT_GROUP", ""); err != nil || content != "password: s3cret" || md5 != "stored-md5" {
+		t.Errorf("GetConfigWithMD5() = %q, %q, %v", content, md5, err)
+	}
+	if content, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err != nil || content != "plain: 1" {
+		t.Errorf("GetConfig() of a plain config = %q, %v", content, err)
+	}
+	if log.Len() != 0 {
+		t.Errorf("unexpected warnings:\n%s", log.String())
+	}
+	if err := c.PublishConfig("cipher-db.yaml", "DEFAULT_GROUP", "password: new"); err != nil || published != "enc:password: new" {
+		t.Errorf("PublishConfig() error = %v, published %q", err, published)
+	}
+	if err := c.PublishConfig("app.yaml", "DEFAULT_GROUP", "plain: 2"); err != nil || published != "plain: 2" {
+		t.Errorf("PublishConfig() of a plain config error = %v, published %q", err, published)
+	}
+
+	// Envelope encryption needs the data key, which is not supported
+	if content, _ := c.GetConfig("cipher-kms-aes-256-db.yaml", "DEFAULT_GROUP"); content != "enc:password: s3cret" || !strings.Contains(log.String(), "data key") {
+		t.Errorf("GetConfig() of an envelope-encrypted config = %q; log:\n%s", content, log.String())
+	}
+	if err := c.PublishConfig("cipher-kms-aes-256-db.yaml", "DEFAULT_GROUP", "x"); !errors.Is(err, ErrNoCipher) {
+		t.Errorf("PublishConfig() of an envelope-encrypted config error = %v", err)
+	}
+}
+
+func TestListConfigsClampedPageSize(t *testing.T) {
+	// 250 configs on a server that caps the page size at 100
+	const total, maxSize = 250, 100
+	page := func(w http.ResponseWriter, pageNo, pageSize int, v3 bool) {
+		pageSize = min(pageSize, maxSize)
+		var items []string
+		for i := (pageNo - 1) * pageSize; i < min(pageNo*pageSize, total); i++ {
+			items = append(items, fmt.Sprintf(`{"dataId":"app-%d.yaml","group":"DEFAULT_GROUP"}`, i))
+		}
+		data := fmt.Sprintf(`{"totalCount":%d,"pageNumber":%d,"pagesAvailable":%d,"pageItems":[%s]}`,
+			total, pageNo, (total+pageSize-1)/pageSize, strings.Join(items, ","))
+		if v3 {
+			data = `{"code":0,"data":` + data + `}`
+		}
+		fmt.Fprint(w, data)
+	}
+	for _, api := range []string{"v3", "v1"} {
+		t.Run(api, func(t *testing.T) {
+			mux := http.NewServeMux()
+			mux.HandleFunc("/nacos/"+api+"/auth/"+map[string]string{"v3": "user/login", "v1": "login"}[api], func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, `{"accessToken":"token-1","tokenTtl":18000}`)
+			})
+			// Only the list API of the login API is served
+			listPath := map[string]string{"v3": "/nacos/v3/admin/cs/config/list", "v1": "/nacos/v1/cs/configs"}[api]
+			mux.HandleFunc(listPath, func(w http.ResponseWriter, r *http.Request) {
+				pageNo, _ := strconv.Atoi(r.FormValue("pageNo"))
+				pageSize, _ := strconv.Atoi(r.FormValue("pageSize"))
+				page(w, pageNo, pageSize, api == "v3")
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "nacos", "secret", "", "", "")
+
+			tests := []struct {
+				page, size                          int
+				wantItems, wantSize, wantTotalPages int
+			}{
+				{1, 500, 100, 100, 3},
+				{3, 500, 50, 500, 3}, // the last page does not show the cap
+				{3, 100, 50, 100, 3},
+				{2, 20, 20, 20, 13},
+				{4, 100, 0, 100, 3},
+			}
+			for _, tt := range tests {
+				resp, err := c.ListConfigs("", "", "", tt.page, tt.size)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if len(resp.PageItems) != tt.wantItems || resp.PageSize(tt.size) != tt.wantSize ||
+					resp.TotalPages(tt.size) != tt.wantTotalPages || resp.Page(tt.page) != tt.page {
+					t.Errorf("page %d of %d: %d items, PageSize() = %d, TotalPages() = %d, Page() = %d; want %d items, %d, %d",
+						tt.page, tt.size, len(resp.PageItems), resp.PageSize(tt.size), resp.TotalPages(tt.size), resp.Page(tt.page),
+						tt.wantItems, tt.wantSize, tt.wantTotalPages)
+				}
+			}
+		})
+	}
+}
+
+func TestConfigListResponseCSV(t *testing.T) {
+	resp := ConfigListResponse{PageItems: []Config{
+		{DataID: "app.yaml", GroupName: "DEFAULT_GROUP", Type: "yaml"},
+		{DataID: "legacy.properties", Group: "LEGACY", Type: "properties"},
+	}}
+	header, rows := resp.CSV()
+	got := fmt.Sprint(header, rows)
+	if want := "[dataId group type] [[app.yaml DEFAULT_GROUP yaml] [legacy.properties LEGACY properties]]"; got != want {
+		t.Errorf("CSV() = %s, want %s", got, want)
+	}
+}
+
+func TestConfigListResponseWithoutPagination(t *testing.T) {
+	// A server that reports only the total count
+	items := make([]Config, 100)
+	tests := []struct {
+		name           string
+		resp           ConfigListResponse
+		requested      int
+		wantSize       int
+		wantTotalPages int
+	}{
+		{"capped first page", ConfigListResponse{TotalCount: 250, PageItems: items}, 500, 100, 3},
+		{"full page", ConfigListResponse{TotalCount: 250, PageItems: items}, 100, 100, 3},
+		{"everything on one page", ConfigListResponse{TotalCount: 100, PageItems: items}, 500, 500, 1},
+		{"empty", ConfigListResponse{}, 20, 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.resp.PageSize(tt.requested); got != tt.wantSize {
+				t.Errorf("PageSize() = %d, want %d", got, tt.wantSize)
+			}
+			if got := tt.resp.TotalPages(tt.requested); got != tt.wantTotalPages {
+				t.Errorf("TotalPages() = %d, want %d", got, tt.wantTotalPages)
+			}
+		})
+	}
+}
+
+func TestDoTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	c.SetTimeout(50 * time.Millisecond)
+
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/nacos/v3/admin/ai/skills/list", nil)
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Do(req)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err == nil {
+			t.Error("Do() succeeded past the client timeout")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Do() ignored the client timeout")
+	}
+}
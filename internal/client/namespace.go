@@ -0,0 +1,137 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+// Namespace is a namespace as the namespace list API reports it
+type Namespace struct {
+	ID          string `json:"namespace"`
+	Name        string `json:"namespaceShowName"`
+	Description string `json:"namespaceDesc"`
+	ConfigCount int    `json:"configCount"`
+}
+
+// ListNamespaces returns the namespaces of the server. The list is fetched
+// once per client, as it is only needed to explain an empty or forbidden
+// result (see NamespaceHint).
+func (c *NacosClient) ListNamespaces() ([]Namespace, error) {
+	c.namespacesOnce.Do(func() {
+		c.namespaces, c.namespacesErr = c.listNamespaces()
+	})
+	return c.namespaces, c.namespacesErr
+}
+
+// listNamespaces fetches the namespace list with the v3 API, or the v1
+// console API on servers without it
+func (c *NacosClient) listNamespaces() ([]Namespace, error) {
+	if err := c.EnsureTokenValid(); err != nil {
+		return nil, err
+	}
+	if c.authLoginVersion != "v1" {
+		resp, err := c.send(func() *resty.Request {
+			req := c.httpClient.R()
+			if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
+				req.SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.AccessToken))
+			}
+			c.setSpasHeaders(req, "", "")
+			return req
+		}, resty.MethodGet, fmt.Sprintf("http://%s/nacos/v3/admin/core/namespace/list", c.ServerAddr))
+		if err != nil {
+			return nil, fmt.Errorf("list namespaces failed: %w", err)
+		}
+		if resp.StatusCode() != http.StatusNotFound && resp.StatusCode() != http.StatusGone {
+			return parseNamespaces(resp, 0)
+		}
+		c.log.Debug("The v3 namespace API is not available; trying the v1 API", "status", resp.StatusCode())
+	}
+
+	resp, err := c.send(func() *resty.Request {
+		params := url.Values{}
+		if c.AuthType == AuthTypeNacos && c.AccessToken != "" {
+			params.Set("accessToken", c.AccessToken)
+		}
+		req := c.httpClient.R().SetQueryString(params.Encode())
+		c.setSpasHeaders(req, "", "")
+		return req
+	}, resty.MethodGet, fmt.Sprintf("http://%s/nacos/v1/console/namespaces", c.ServerAddr))
+	if err != nil {
+		return nil, fmt.Errorf("list namespaces failed: %w", err)
+	}
+	return parseNamespaces(resp, http.StatusOK)
+}
+
+// parseNamespaces reads a namespace list response, whose code is okCode on
+// success: 0 for the v3 API, 200 for the v1 API
+func parseNamespaces(resp *resty.Response, okCode int) ([]Namespace, error) {
+	if resp.StatusCode() != http.StatusOK {
+		return nil, responseError(resp, "list namespaces")
+	}
+	var body struct {
+		Code    int         `json:"code"`
+		Message string      `json:"message"`
+		Data    []Namespace `json:"data"`
+	}
+	if err := json.Unmarshal(resp.Body(), &body); err != nil {
+		return nil, fmt.Errorf("list namespaces failed: invalid response: %w", err)
+	}
+	if body.Code != okCode {
+		return nil, WithRequestID(fmt.Errorf("list namespaces failed: code=%d, message=%s", body.Code, body.Message), resp.RawResponse)
+	}
+	return body.Data, nil
+}
+
+// NamespaceHint explains an empty or, with forbidden, a 403 result by the
+// namespace of the client: that it does not exist, with the nearest one, or
+// that the user lacks permission on it. It returns "" for the public
+// namespace, when the namespaces cannot be listed, and when the namespace
+// exists and the result is not forbidden.
+func (c *NacosClient) NamespaceHint(forbidden bool) string {
+	ns := c.Namespace
+	if ns == "" || ns == "public" {
+		return ""
+	}
+	namespaces, err := c.ListNamespaces()
+	if err != nil {
+		c.log.Debug("Cannot list namespaces to check the namespace", "error", err)
+		return ""
+	}
+
+	var candidates []string
+	byCandidate := map[string]Namespace{}
+	for _, n := range namespaces {
+		if n.ID == ns {
+			if forbidden {
+				return fmt.Sprintf("you lack permission on namespace '%s' (id %s)", n.Name, n.ID)
+			}
+			return ""
+		}
+		if n.ID == "" {
+			continue // public, which --namespace selects when left out
+		}
+		candidates = append(candidates, n.ID, n.Name)
+		byCandidate[n.ID], byCandidate[n.Name] = n, n
+	}
+	if n, ok := byCandidate[ns]; ok && n.Name == ns {
+		return fmt.Sprintf("'%s' is the name of namespace id %s; pass the id: --namespace %s", ns, n.ID, n.ID)
+	}
+	if match := util.Closest(ns, candidates); match != "" {
+		n := byCandidate[match]
+		return fmt.Sprintf("namespace '%s' not found — did you mean '%s' (id %s)?", ns, n.Name, n.ID)
+	}
+	if len(candidates) == 0 {
+		return fmt.Sprintf("namespace '%s' not found; the server has only the public namespace", ns)
+	}
+	var names []string
+	for i := 0; i < len(candidates); i += 2 {
+		names = append(names, fmt.Sprintf("%s (id %s)", candidates[i+1], candidates[i]))
+	}
+	return fmt.Sprintf("namespace '%s' not found; the namespaces are: %s", ns, strings.Join(names, ", "))
+}
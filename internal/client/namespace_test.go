@@ -0,0 +1,90 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+func TestNamespaceHint(t *testing.T) {
+	namespaces := `[{"namespace":"","namespaceShowName":"public"},
+		{"namespace":"7df1c2a4-prod","namespaceShowName":"prod"},
+		{"namespace":"dev","namespaceShowName":"dev"}]`
+	tests := []struct {
+		name      string
+		namespace string
+		forbidden bool
+		v1Only    bool
+		want      string
+	}{
+		{"existing namespace", "dev", false, false, ""},
+		{"public namespace", "public", true, false, ""},
+		{"typo of a name", "prdo", false, false, "namespace 'prdo' not found — did you mean 'prod' (id 7df1c2a4-prod)?"},
+		{"name instead of id", "prod", false, false, "'prod' is the name of namespace id 7df1c2a4-prod; pass the id: --namespace 7df1c2a4-prod"},
+		{"no permission", "7df1c2a4-prod", true, false, "you lack permission on namespace 'prod' (id 7df1c2a4-prod)"},
+		{"nothing close", "staging-eu-west", false, false, "namespace 'staging-eu-west' not found; the namespaces are: prod (id 7df1c2a4-prod), dev (id dev)"},
+		{"v1 server", "prdo", false, true, "namespace 'prdo' not found — did you mean 'prod' (id 7df1c2a4-prod)?"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lists int32
+			mux := http.NewServeMux()
+			mux.HandleFunc("/nacos/v3/admin/core/namespace/list", func(w http.ResponseWriter, r *http.Request) {
+				if tt.v1Only {
+					http.NotFound(w, r)
+					return
+				}
+				atomic.AddInt32(&lists, 1)
+				fmt.Fprintf(w, `{"code":0,"data":%s}`, namespaces)
+			})
+			mux.HandleFunc("/nacos/v1/console/namespaces", func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&lists, 1)
+				fmt.Fprintf(w, `{"code":200,"data":%s}`, namespaces)
+			})
+			server := httptest.NewServer(mux)
+			defer server.Close()
+			c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), tt.namespace, "", "", "", "", "", "")
+
+			if got := c.NamespaceHint(tt.forbidden); got != tt.want {
+				t.Errorf("NamespaceHint() = %q, want %q", got, tt.want)
+			}
+			c.NamespaceHint(tt.forbidden)
+			if tt.namespace != "public" && lists != 1 {
+				t.Errorf("namespaces listed %d times, want once", lists)
+			}
+		})
+	}
+}
+
+func TestNamespaceHintWithoutNamespaceList(t *testing.T) {
+	// A user without permission on the namespace list gets no hint
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "prod", "", "", "", "", "", "")
+	if got := c.NamespaceHint(true); got != "" {
+		t.Errorf("NamespaceHint() = %q, want no hint", got)
+	}
+}
+
+func TestErrForbidden(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprint(w, `{"code":403,"message":"no permission"}`)
+	}))
+	defer server.Close()
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "prod", "", "", "", "", "", "")
+
+	_, err := c.GetConfig("app.yaml", "DEFAULT_GROUP")
+	if !errors.Is(err, ErrForbidden) || !strings.Contains(err.Error(), "no permission") {
+		t.Errorf("GetConfig() error = %v, want ErrForbidden with the server message", err)
+	}
+	if _, err := c.ListConfigs("", "", "", 1, 10); !errors.Is(err, ErrForbidden) {
+		t.Errorf("ListConfigs() error = %v, want ErrForbidden", err)
+	}
+}
@@ -0,0 +1,100 @@
+package client
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+)
+
+// RequestIDHeader carries the id the client gives each request, so the
+// request can be found in the logs of the server and of proxies in front of it
+const RequestIDHeader = "X-Request-Id"
+
+// serverIDHeaders are the response headers a server, gateway or tracing agent
+// identifies a request by, in the order they are looked for
+var serverIDHeaders = []string{RequestIDHeader, "X-Trace-Id", "Trace-Id", "X-B3-TraceId", "EagleEye-TraceId", "X-Acs-Request-Id", "Request-Id"}
+
+// Request is a request sent to Nacos, as told to the handler set with
+// SetRequestHandler
+type Request struct {
+	Method   string
+	Path     string // without the query string, which may carry an access token
+	Status   int
+	ID       string // sent as X-Request-Id
+	ServerID string // the request or trace id the server sent back, if any
+}
+
+// RequestError is an error the server answered a request with. Its message
+// ends with the ids of the request, for the server's admins to look it up.
+type RequestError struct {
+	Err      error
+	ID       string // sent as X-Request-Id
+	ServerID string // the request or trace id the server sent back, if any
+}
+
+func (e *RequestError) Error() string {
+	ids := fmt.Sprintf(" (requestId=%s)", e.ID)
+	if e.ServerID != "" {
+		ids = fmt.Sprintf(" (requestId=%s, serverRequestId=%s)", e.ID, e.ServerID)
+	}
+	// The ids go on the first line, before a hint on the next
+	msg, rest, found := strings.Cut(e.Err.Error(), "\n")
+	if found {
+		return msg + ids + "\n" + rest
+	}
+	return msg + ids
+}
+
+func (e *RequestError) Unwrap() error { return e.Err }
+
+// WithRequestID returns err as a *RequestError with the ids of the request
+// resp answers, or err itself if it is nil or the request had no id
+func WithRequestID(err error, resp *http.Response) error {
+	id, serverID := RequestIDs(resp)
+	if err == nil || id == "" {
+		return err
+	}
+	return &RequestError{Err: err, ID: id, ServerID: serverID}
+}
+
+// responseError is ParseHTTPError for a response to the client, with the ids
+// of its request
+func responseError(resp *resty.Response, operation string) error {
+	return WithRequestID(ParseHTTPError(resp.StatusCode(), resp.Body(), operation), resp.RawResponse)
+}
+
+// RequestIDs returns the id the client sent with the request resp answers,
+// and the request or trace id the server sent back. A server id that merely
+// echoes the client's is left out.
+func RequestIDs(resp *http.Response) (id, serverID string) {
+	if resp == nil {
+		return "", ""
+	}
+	if resp.Request != nil {
+		id = resp.Request.Header.Get(RequestIDHeader)
+	}
+	for _, name := range serverIDHeaders {
+		if v := resp.Header.Get(name); v != "" && v != id {
+			return id, v
+		}
+	}
+	return id, ""
+}
+
+// newRequestID returns a random (version 4) UUID
+func newRequestID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// SetRequestHandler sets a function told about every request answered by the
+// server, e.g. to list the requests of each item in a batch report
+func (c *NacosClient) SetRequestHandler(handler func(Request)) {
+	c.onRequest = handler
+}
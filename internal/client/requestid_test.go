@@ -0,0 +1,121 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+var uuidPattern = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestIDs(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = append(sent, r.Header.Get(RequestIDHeader))
+		switch r.URL.Query().Get("dataId") {
+		case "echo.yaml":
+			// A proxy that echoes the client's id and adds a trace id
+			w.Header().Set(RequestIDHeader, r.Header.Get(RequestIDHeader))
+			w.Header().Set("EagleEye-TraceId", "0bc3b4a516")
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"code":500,"message":"boom"}`)
+		case "denied.yaml":
+			w.Header().Set("X-Trace-Id", "trace-ab12")
+			fmt.Fprint(w, `{"code":403,"message":"no permission"}`)
+		default:
+			fmt.Fprint(w, `{"code":0,"data":{"content":"a: 1"}}`)
+		}
+	}))
+	defer server.Close()
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	var requests []Request
+	c.SetRequestHandler(func(r Request) { requests = append(requests, r) })
+
+	if _, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := c.GetConfigWithMD5(context.Background(), "app.yaml", "DEFAULT_GROUP", ""); err != nil {
+		t.Fatal(err)
+	}
+	_, err := c.GetConfig("denied.yaml", "DEFAULT_GROUP")
+	var reqErr *RequestError
+	if !errors.As(err, &reqErr) || reqErr.ID != sent[2] || reqErr.ServerID != "trace-ab12" {
+		t.Fatalf("GetConfig() error = %v, want a RequestError with ids %s and trace-ab12", err, sent[2])
+	}
+	if want := fmt.Sprintf("get config failed: code=403, message=no permission (requestId=%s, serverRequestId=trace-ab12)", sent[2]); err.Error() != want {
+		t.Errorf("GetConfig() error = %q, want %q", err, want)
+	}
+
+	_, err = c.GetConfig("echo.yaml", "DEFAULT_GROUP")
+	if !errors.As(err, &reqErr) || reqErr.ServerID != "0bc3b4a516" {
+		t.Fatalf("GetConfig() error = %v, want the trace id rather than the echoed id", err)
+	}
+	// The ids go before the hint, on the line of the error
+	if first, _, _ := strings.Cut(err.Error(), "\n"); !strings.HasSuffix(first, fmt.Sprintf("(requestId=%s, serverRequestId=0bc3b4a516)", sent[3])) {
+		t.Errorf("GetConfig() error = %q, want the ids at the end of its first line", err)
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range sent {
+		if !uuidPattern.MatchString(id) || seen[id] {
+			t.Errorf("request ids = %q, want a new UUID for each request", sent)
+			break
+		}
+		seen[id] = true
+	}
+	if len(requests) != len(sent) {
+		t.Fatalf("handler was told about %d request(s), want %d", len(requests), len(sent))
+	}
+	if r := requests[2]; r.Method != http.MethodGet || r.Path != "/nacos/v3/client/cs/config" || r.Status != 200 || r.ID != sent[2] || r.ServerID != "trace-ab12" {
+		t.Errorf("handler was told %+v", r)
+	}
+}
+
+func TestRequestIDsLogged(t *testing.T) {
+	logging.SetLevel(slog.LevelDebug)
+	t.Cleanup(func() { logging.SetLevel(slog.LevelInfo) })
+	var sent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		sent = r.Header.Get(RequestIDHeader)
+		w.Header().Set("X-Trace-Id", "trace-ab12")
+		fmt.Fprint(w, `{"code":0,"data":true}`)
+	}))
+	defer server.Close()
+	c, _ := NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	var log strings.Builder
+	c.SetLogger(slog.New(logging.NewConsoleHandler(func(msg string) {
+		log.WriteString(msg + "\n")
+	})))
+
+	if err := c.DeleteConfig("app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{"DELETE /nacos/v3/admin/cs/config", "requestId=" + sent, "serverRequestId=trace-ab12"} {
+		if !strings.Contains(log.String(), want) {
+			t.Errorf("debug log lacks %q:\n%s", want, log.String())
+		}
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	if WithRequestID(nil, &http.Response{}) != nil {
+		t.Error("WithRequestID(nil) is not nil")
+	}
+	err := errors.New("boom")
+	if got := WithRequestID(err, nil); got != err {
+		t.Errorf("WithRequestID() without a response = %v, want the error as is", got)
+	}
+	resp := &http.Response{Header: http.Header{}, Request: &http.Request{Header: http.Header{RequestIDHeader: {"id-1"}}}}
+	got := WithRequestID(fmt.Errorf("%w: app.yaml", ErrConfigChanged), resp)
+	if !errors.Is(got, ErrConfigChanged) || got.Error() != "config changed since it was fetched: app.yaml (requestId=id-1)" {
+		t.Errorf("WithRequestID() = %v", got)
+	}
+}
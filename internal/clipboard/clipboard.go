@@ -0,0 +1,108 @@
+package clipboard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+// ErrEmpty is returned by Read when the clipboard holds no text
+var ErrEmpty = errors.New("the clipboard is empty")
+
+// previewLines is how many lines Preview shows at each end of the content
+const previewLines = 3
+
+// previewWidth is the width Preview cuts long lines to
+const previewWidth = 100
+
+// utility is a command that prints the text on the clipboard
+type utility struct {
+	args    []string // the command and its arguments
+	install string   // what to install to get it
+}
+
+// utilities returns the commands that may print the clipboard on goos, in
+// the order they are tried
+func utilities(goos string, getenv func(string) string) []utility {
+	switch goos {
+	case "darwin":
+		return []utility{{[]string{"pbpaste"}, "pbpaste, which comes with macOS"}}
+	case "windows":
+		return []utility{{[]string{"powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"}, "Windows PowerShell"}}
+	}
+	var list []utility
+	if getenv("WAYLAND_DISPLAY") != "" {
+		list = append(list, utility{[]string{"wl-paste", "--no-newline"}, "wl-clipboard (wl-paste)"})
+	}
+	return append(list, utility{[]string{"xclip", "-selection", "clipboard", "-o"}, "xclip"})
+}
+
+// Read returns the text on the system clipboard, read with pbpaste on macOS,
+// PowerShell's Get-Clipboard on Windows, and wl-paste (on Wayland) or xclip
+// elsewhere. It fails with the utility to install when none is found, and
+// with ErrEmpty when there is no text, so empty content is never used.
+func Read() (string, error) {
+	return read(runtime.GOOS, os.Getenv, exec.LookPath, func(args []string) ([]byte, error) {
+		cmd := exec.Command(args[0], args[1:]...)
+		var stderr bytes.Buffer
+		cmd.Stderr = &stderr
+		out, err := cmd.Output()
+		if msg := strings.TrimSpace(stderr.String()); err != nil && msg != "" {
+			err = fmt.Errorf("%w: %s", err, msg)
+		}
+		return out, err
+	})
+}
+
+func read(goos string, getenv func(string) string, lookPath func(string) (string, error), run func([]string) ([]byte, error)) (string, error) {
+	var missing []string
+	for _, u := range utilities(goos, getenv) {
+		if _, err := lookPath(u.args[0]); err != nil {
+			missing = append(missing, u.install)
+			continue
+		}
+		out, err := run(u.args)
+		if err != nil {
+			return "", fmt.Errorf("read the clipboard with %s: %w", u.args[0], err)
+		}
+		text := string(out)
+		if goos == "windows" {
+			text = strings.ReplaceAll(text, "\r\n", "\n")
+		}
+		if strings.TrimSpace(text) == "" {
+			return "", ErrEmpty
+		}
+		return text, nil
+	}
+	if goos != "darwin" && goos != "windows" && getenv("WAYLAND_DISPLAY") == "" && getenv("DISPLAY") == "" {
+		return "", fmt.Errorf("cannot read the clipboard: no graphical session (DISPLAY and WAYLAND_DISPLAY are not set), and %s is not installed", strings.Join(missing, " or "))
+	}
+	return "", fmt.Errorf("cannot read the clipboard: install %s", strings.Join(missing, " or "))
+}
+
+// Preview describes content for a confirmation before it is used: its size
+// and its first and last lines, cut to a readable width, with the number of
+// lines left out between them
+func Preview(content string) []string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	preview := []string{fmt.Sprintf("%d bytes, %d lines", len(content), len(lines))}
+	show := func(lines []string) {
+		for _, line := range lines {
+			preview = append(preview, "  "+util.Truncate(line, previewWidth, "..."))
+		}
+	}
+	if len(lines) <= 2*previewLines+1 {
+		show(lines)
+		return preview
+	}
+	show(lines[:previewLines])
+	preview = append(preview, fmt.Sprintf("  ... %d more lines ...", len(lines)-2*previewLines))
+	show(lines[len(lines)-previewLines:])
+	return preview
+}
@@ -0,0 +1,80 @@
+package clipboard
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		name      string
+		goos      string
+		env       map[string]string
+		installed []string
+		output    string
+		runErr    error
+		want      string
+		wantRun   string
+		wantErr   string
+	}{
+		{"macOS", "darwin", nil, []string{"pbpaste"}, "a: 1\n", nil, "a: 1\n", "pbpaste", ""},
+		{"windows line endings", "windows", nil, []string{"powershell"}, "a: 1\r\nb: 2\r\n", nil, "a: 1\nb: 2\n", "powershell -NoProfile -Command Get-Clipboard -Raw", ""},
+		{"wayland", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, []string{"wl-paste", "xclip"}, "a: 1", nil, "a: 1", "wl-paste --no-newline", ""},
+		{"wayland without wl-paste", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0", "DISPLAY": ":0"}, []string{"xclip"}, "a: 1", nil, "a: 1", "xclip -selection clipboard -o", ""},
+		{"x11", "linux", map[string]string{"DISPLAY": ":0"}, []string{"xclip"}, "a: 1", nil, "a: 1", "xclip -selection clipboard -o", ""},
+		{"nothing installed", "linux", map[string]string{"WAYLAND_DISPLAY": "wayland-0"}, nil, "", nil, "", "", "install wl-clipboard (wl-paste) or xclip"},
+		{"no graphical session", "linux", nil, nil, "", nil, "", "", "no graphical session"},
+		{"empty clipboard", "darwin", nil, []string{"pbpaste"}, " \n", nil, "", "pbpaste", "the clipboard is empty"},
+		{"utility fails", "linux", map[string]string{"DISPLAY": ":0"}, []string{"xclip"}, "", errors.New("exit status 1: Error: target STRING not available"), "", "xclip -selection clipboard -o", "read the clipboard with xclip: exit status 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ran string
+			got, err := read(tt.goos, func(key string) string { return tt.env[key] }, func(name string) (string, error) {
+				for _, installed := range tt.installed {
+					if installed == name {
+						return "/usr/bin/" + name, nil
+					}
+				}
+				return "", fmt.Errorf("%s not found", name)
+			}, func(args []string) ([]byte, error) {
+				ran = strings.Join(args, " ")
+				return []byte(tt.output), tt.runErr
+			})
+			if ran != tt.wantRun {
+				t.Errorf("ran %q, want %q", ran, tt.wantRun)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("read() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("read() = %q, %v; want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPreview(t *testing.T) {
+	if got := Preview("a: 1\nb: 2\n"); strings.Join(got, "|") != "10 bytes, 2 lines|  a: 1|  b: 2" {
+		t.Errorf("Preview() of a short config = %q", got)
+	}
+
+	var lines []string
+	for i := 1; i <= 10; i++ {
+		lines = append(lines, fmt.Sprintf("key%d: %d", i, i))
+	}
+	lines[9] = strings.Repeat("x", 150)
+	got := Preview(strings.Join(lines, "\n"))
+	want := []string{"222 bytes, 10 lines", "  key1: 1", "  key2: 2", "  key3: 3", "  ... 4 more lines ...", "  key8: 8", "  key9: 9"}
+	if len(got) != len(want)+1 || strings.Join(got[:len(want)], "|") != strings.Join(want, "|") {
+		t.Fatalf("Preview() of a long config = %q", got)
+	}
+	if last := got[len(got)-1]; len(last) != 2+previewWidth || !strings.HasSuffix(last, "...") {
+		t.Errorf("long line previewed as %q", last)
+	}
+}
@@ -7,7 +7,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/nacos-group/nacos-cli/internal/util"
 	"golang.org/x/term"
 	"gopkg.in/yaml.v3"
 )
@@ -18,6 +21,13 @@ const (
 	ConfigFileSuffix  = ".conf"
 )
 
+// DefaultSkillsDir is where skill-get and skill-sync install skills when
+// neither -o, NACOS_SKILLS_DIR nor skillsDir of the config file says where
+const DefaultSkillsDir = "~/.skills"
+
+// EnvSkillsDir overrides skillsDir of the config file
+const EnvSkillsDir = "NACOS_SKILLS_DIR"
+
 // Config represents the Nacos CLI configuration
 type Config struct {
 	Host      string `yaml:"host"`
@@ -26,9 +36,32 @@ type Config struct {
 	Username  string `yaml:"username"`
 	Password  string `yaml:"password"`
 	Token     string `yaml:"token"`     // Pre-issued access token (skips username/password login)
-	AccessKey string `yaml:"accessKey"` // Aliyun AK（AuthType=aliyun 时使用）
+	AccessKey string `yaml:"accessKey"` // Aliyun AK (used when AuthType=aliyun)
 	SecretKey string `yaml:"secretKey"` // Aliyun SK
 	Namespace string `yaml:"namespace"`
+
+	KMSEndpoint string `yaml:"kmsEndpoint,omitempty"` // Aliyun KMS endpoint that decrypts cipher- configs, e.g. kms.cn-hangzhou.aliyuncs.com
+	KMSKeyID    string `yaml:"kmsKeyId,omitempty"`    // KMS key config-set encrypts cipher- configs with
+
+	HistoryFile  string            `yaml:"historyFile,omitempty"`  // Terminal history file (default: ~/.nacos-cli/history)
+	SkillsDir    string            `yaml:"skillsDir,omitempty"`    // Where skill-get and skill-sync install skills (default: ~/.skills)
+	Aliases      map[string]string `yaml:"aliases,omitempty"`      // Command aliases, e.g. cl: config-list --group skill_*
+	DefaultGroup string            `yaml:"defaultGroup,omitempty"` // Group for config-get/config-set when only a dataId is given
+	PollTimeout  string            `yaml:"pollTimeout,omitempty"`  // How long each skill-sync poll may take, e.g. 20s (default: 30s)
+	OnChange     string            `yaml:"onChange,omitempty"`     // Command skill-sync runs after a skill is updated or deleted
+	OnError      string            `yaml:"onError,omitempty"`      // Command skill-sync runs after a skill fails to sync
+	Lang         string            `yaml:"lang,omitempty"`         // Language of messages: en or zh (default: from LANG, else en)
+	Defaults     Defaults          `yaml:"defaults,omitempty"`     // Values commands use when a flag or argument is omitted
+}
+
+// Defaults are the values commands use when the corresponding flag or
+// argument is not given on the command line
+type Defaults struct {
+	Group     string `yaml:"group,omitempty"`     // Group of config-get/config-set when only a dataId is given
+	Namespace string `yaml:"namespace,omitempty"` // Namespace when --namespace is not given
+	Output    string `yaml:"output,omitempty"`    // Format of skill-list and group-list: table, json or csv
+	PageSize  int    `yaml:"pageSize,omitempty"`  // --size of the list commands
+	MaxSize   string `yaml:"maxSize,omitempty"`   // --max-size of config-set, e.g. 5MB
 }
 
 // LoadConfig loads configuration from a file
@@ -89,6 +122,57 @@ func (c *Config) GetServerAddr() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
+// GetPollTimeout parses PollTimeout, returning 0 when it is not set
+func (c *Config) GetPollTimeout() (time.Duration, error) {
+	if c.PollTimeout == "" {
+		return 0, nil
+	}
+	timeout, err := time.ParseDuration(c.PollTimeout)
+	if err != nil || timeout <= 0 {
+		return 0, fmt.Errorf("invalid pollTimeout %q in config file: use a duration such as 20s", c.PollTimeout)
+	}
+	return timeout, nil
+}
+
+// GetDefaults returns the defaults of the config file, validated. The
+// top-level defaultGroup and namespace keys are the same settings as
+// defaults.group and defaults.namespace: either may be used, and when both are
+// set they have to agree.
+func (c *Config) GetDefaults() (Defaults, error) {
+	d := c.Defaults
+	var err error
+	if d.Group, err = merge("defaultGroup", c.DefaultGroup, "defaults.group", d.Group); err != nil {
+		return Defaults{}, err
+	}
+	if d.Namespace, err = merge("namespace", c.Namespace, "defaults.namespace", d.Namespace); err != nil {
+		return Defaults{}, err
+	}
+	if d.Output != "" && d.Output != "table" && d.Output != "json" && d.Output != "csv" {
+		return Defaults{}, fmt.Errorf("invalid defaults.output %q in config file: use table, json or csv", d.Output)
+	}
+	if d.PageSize < 0 {
+		return Defaults{}, fmt.Errorf("invalid defaults.pageSize %d in config file: use a positive number", d.PageSize)
+	}
+	if d.MaxSize != "" {
+		if _, err := util.ParseSize(d.MaxSize); err != nil {
+			return Defaults{}, fmt.Errorf("invalid defaults.maxSize in config file: %w", err)
+		}
+	}
+	return d, nil
+}
+
+// merge returns whichever of two keys for the same setting is set, and an
+// error when both are set to different values
+func merge(key, value, otherKey, other string) (string, error) {
+	if value != "" && other != "" && value != other {
+		return "", fmt.Errorf("%s %q and %s %q in config file disagree: keep one of them", key, value, otherKey, other)
+	}
+	if value != "" {
+		return value, nil
+	}
+	return other, nil
+}
+
 // GetConfigDir returns the default config directory path (~/.nacos-cli)
 func GetConfigDir() (string, error) {
 	homeDir, err := os.UserHomeDir()
@@ -226,11 +310,14 @@ func (c *Config) SaveConfig(configPath string) error {
 
 // PromptForMissingFields interactively prompts the user to input missing configuration fields
 func (c *Config) PromptForMissingFields() error {
+	if err := ui.RequireInteractive(); err != nil {
+		return err
+	}
 	reader := bufio.NewReader(os.Stdin)
 
 	// Prompt for host if missing
 	if c.Host == "" {
-		fmt.Print("Enter Nacos host [127.0.0.1]: ")
+		fmt.Fprint(os.Stderr, "Enter Nacos host [127.0.0.1]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read host: %w", err)
@@ -245,7 +332,7 @@ func (c *Config) PromptForMissingFields() error {
 
 	// Prompt for port if not set
 	if c.Port == 0 {
-		fmt.Print("Enter Nacos port [8848]: ")
+		fmt.Fprint(os.Stderr, "Enter Nacos port [8848]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read port: %w", err)
@@ -264,7 +351,7 @@ func (c *Config) PromptForMissingFields() error {
 
 	// Prompt for auth type if not set
 	if c.AuthType == "" {
-		fmt.Print("Enter auth type (none/nacos/aliyun) [none]: ")
+		fmt.Fprint(os.Stderr, "Enter auth type (none/nacos/aliyun) [none]: ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read auth type: %w", err)
@@ -282,7 +369,7 @@ func (c *Config) PromptForMissingFields() error {
 	// Prompt for credentials based on auth type
 	if c.AuthType == "aliyun" {
 		if c.AccessKey == "" {
-			fmt.Print("Enter AccessKey: ")
+			fmt.Fprint(os.Stderr, "Enter AccessKey: ")
 			input, err := reader.ReadString('\n')
 			if err != nil {
 				return fmt.Errorf("failed to read access key: %w", err)
@@ -293,7 +380,7 @@ func (c *Config) PromptForMissingFields() error {
 			}
 		}
 		if c.SecretKey == "" {
-			fmt.Print("Enter SecretKey: ")
+			fmt.Fprint(os.Stderr, "Enter SecretKey: ")
 			c.SecretKey = readPassword(reader)
 			if c.SecretKey == "" {
 				return fmt.Errorf("secret key is required for aliyun auth")
@@ -302,7 +389,7 @@ func (c *Config) PromptForMissingFields() error {
 	} else if c.AuthType == "nacos" {
 		// Nacos auth
 		if c.Username == "" {
-			fmt.Print("Enter username: ")
+			fmt.Fprint(os.Stderr, "Enter username: ")
 			input, err := reader.ReadString('\n')
 			if err != nil {
 				return fmt.Errorf("failed to read username: %w", err)
@@ -313,7 +400,7 @@ func (c *Config) PromptForMissingFields() error {
 			}
 		}
 		if c.Password == "" {
-			fmt.Print("Enter password: ")
+			fmt.Fprint(os.Stderr, "Enter password: ")
 			password := readPassword(reader)
 			if password == "" {
 				return fmt.Errorf("password is required")
@@ -325,7 +412,7 @@ func (c *Config) PromptForMissingFields() error {
 
 	// Optionally prompt for namespace
 	if c.Namespace == "" {
-		fmt.Print("Enter namespace (leave empty for public): ")
+		fmt.Fprint(os.Stderr, "Enter namespace (leave empty for public): ")
 		input, err := reader.ReadString('\n')
 		if err != nil {
 			return fmt.Errorf("failed to read namespace: %w", err)
@@ -341,7 +428,7 @@ func readPassword(reader *bufio.Reader) string {
 	// Check if stdin is a terminal
 	if term.IsTerminal(int(os.Stdin.Fd())) {
 		bytePassword, err := term.ReadPassword(int(os.Stdin.Fd()))
-		fmt.Println() // New line after password input
+		fmt.Fprintln(os.Stderr) // New line after password input
 		if err != nil {
 			return ""
 		}
@@ -369,7 +456,7 @@ func LoadOrCreateConfig(profile string) (*Config, string, error) {
 	if _, err := os.Stat(configPath); err == nil {
 		cfg, err = LoadConfig(configPath)
 		if err != nil {
-			fmt.Printf("Warning: Failed to load config from %s: %v\n", configPath, err)
+			fmt.Fprintf(os.Stderr, "Warning: Failed to load config from %s: %v\n", configPath, err)
 			cfg = &Config{}
 		}
 	} else {
@@ -380,9 +467,9 @@ func LoadOrCreateConfig(profile string) (*Config, string, error) {
 	if !cfg.IsComplete() {
 		missing := cfg.GetMissingFields()
 		if len(missing) > 0 {
-			fmt.Printf("Configuration incomplete (missing: %s)\n", strings.Join(missing, ", "))
+			fmt.Fprintf(os.Stderr, "Configuration incomplete (missing: %s)\n", strings.Join(missing, ", "))
 		}
-		fmt.Printf("Please enter the required configuration for profile '%s':\n", profile)
+		fmt.Fprintf(os.Stderr, "Please enter the required configuration for profile '%s':\n", profile)
 
 		// Prompt for missing fields
 		if err := cfg.PromptForMissingFields(); err != nil {
@@ -391,9 +478,9 @@ func LoadOrCreateConfig(profile string) (*Config, string, error) {
 
 		// Save the completed config
 		if err := cfg.SaveConfig(configPath); err != nil {
-			fmt.Printf("Warning: Failed to save config to %s: %v\n", configPath, err)
+			fmt.Fprintf(os.Stderr, "Warning: Failed to save config to %s: %v\n", configPath, err)
 		} else {
-			fmt.Printf("Configuration saved to %s\n", configPath)
+			fmt.Fprintf(os.Stderr, "Configuration saved to %s\n", configPath)
 		}
 	}
 
@@ -419,9 +506,9 @@ func (c *Config) PromptForUpdate() error {
 	// Host
 	currentHost := formatCurrent(c.Host, false)
 	if currentHost != "" {
-		fmt.Printf("Enter Nacos host [%s]: ", currentHost)
+		fmt.Fprintf(os.Stderr, "Enter Nacos host [%s]: ", currentHost)
 	} else {
-		fmt.Print("Enter Nacos host [127.0.0.1]: ")
+		fmt.Fprint(os.Stderr, "Enter Nacos host [127.0.0.1]: ")
 	}
 	input, err := reader.ReadString('\n')
 	if err != nil {
@@ -439,7 +526,7 @@ func (c *Config) PromptForUpdate() error {
 	if c.Port > 0 {
 		currentPort = strconv.Itoa(c.Port)
 	}
-	fmt.Printf("Enter Nacos port [%s]: ", currentPort)
+	fmt.Fprintf(os.Stderr, "Enter Nacos port [%s]: ", currentPort)
 	input, err = reader.ReadString('\n')
 	if err != nil {
 		return fmt.Errorf("failed to read port: %w", err)
@@ -460,7 +547,7 @@ func (c *Config) PromptForUpdate() error {
 	if currentAuthType == "" {
 		currentAuthType = "none"
 	}
-	fmt.Printf("Enter auth type (none/nacos/aliyun) [%s]: ", currentAuthType)
+	fmt.Fprintf(os.Stderr, "Enter auth type (none/nacos/aliyun) [%s]: ", currentAuthType)
 	input, err = reader.ReadString('\n')
 	if err != nil {
 		return fmt.Errorf("failed to read auth type: %w", err)
@@ -480,9 +567,9 @@ func (c *Config) PromptForUpdate() error {
 		// AccessKey
 		currentAK := formatCurrent(c.AccessKey, false)
 		if currentAK != "" {
-			fmt.Printf("Enter AccessKey [%s]: ", currentAK)
+			fmt.Fprintf(os.Stderr, "Enter AccessKey [%s]: ", currentAK)
 		} else {
-			fmt.Print("Enter AccessKey: ")
+			fmt.Fprint(os.Stderr, "Enter AccessKey: ")
 		}
 		input, err = reader.ReadString('\n')
 		if err != nil {
@@ -498,9 +585,9 @@ func (c *Config) PromptForUpdate() error {
 
 		// SecretKey
 		if c.SecretKey != "" {
-			fmt.Print("Enter SecretKey [******] (press Enter to keep current): ")
+			fmt.Fprint(os.Stderr, "Enter SecretKey [******] (press Enter to keep current): ")
 		} else {
-			fmt.Print("Enter SecretKey: ")
+			fmt.Fprint(os.Stderr, "Enter SecretKey: ")
 		}
 		newSK := readPassword(reader)
 		if newSK != "" {
@@ -513,9 +600,9 @@ func (c *Config) PromptForUpdate() error {
 		// Nacos auth - Username
 		currentUser := formatCurrent(c.Username, false)
 		if currentUser != "" {
-			fmt.Printf("Enter username [%s]: ", currentUser)
+			fmt.Fprintf(os.Stderr, "Enter username [%s]: ", currentUser)
 		} else {
-			fmt.Print("Enter username: ")
+			fmt.Fprint(os.Stderr, "Enter username: ")
 		}
 		input, err = reader.ReadString('\n')
 		if err != nil {
@@ -531,9 +618,9 @@ func (c *Config) PromptForUpdate() error {
 
 		// Password
 		if c.Password != "" {
-			fmt.Print("Enter password [******] (press Enter to keep current): ")
+			fmt.Fprint(os.Stderr, "Enter password [******] (press Enter to keep current): ")
 		} else {
-			fmt.Print("Enter password: ")
+			fmt.Fprint(os.Stderr, "Enter password: ")
 		}
 		newPwd := readPassword(reader)
 		if newPwd != "" {
@@ -548,9 +635,9 @@ func (c *Config) PromptForUpdate() error {
 	// Namespace
 	currentNS := c.Namespace
 	if currentNS != "" {
-		fmt.Printf("Enter namespace [%s]: ", currentNS)
+		fmt.Fprintf(os.Stderr, "Enter namespace [%s]: ", currentNS)
 	} else {
-		fmt.Print("Enter namespace (leave empty for public): ")
+		fmt.Fprint(os.Stderr, "Enter namespace (leave empty for public): ")
 	}
 	input, err = reader.ReadString('\n')
 	if err != nil {
@@ -0,0 +1,137 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetDefaults(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		want    Defaults
+		wantErr string
+	}{
+		{name: "none", cfg: Config{}, want: Defaults{}},
+		{
+			name: "defaults section",
+			cfg:  Config{Defaults: Defaults{Group: "TEAM_A", Namespace: "dev", Output: "json", PageSize: 50}},
+			want: Defaults{Group: "TEAM_A", Namespace: "dev", Output: "json", PageSize: 50},
+		},
+		{
+			name: "top-level keys",
+			cfg:  Config{DefaultGroup: "TEAM_A", Namespace: "dev"},
+			want: Defaults{Group: "TEAM_A", Namespace: "dev"},
+		},
+		{
+			name: "both agree",
+			cfg:  Config{DefaultGroup: "TEAM_A", Namespace: "dev", Defaults: Defaults{Group: "TEAM_A", Namespace: "dev"}},
+			want: Defaults{Group: "TEAM_A", Namespace: "dev"},
+		},
+		{
+			name:    "groups disagree",
+			cfg:     Config{DefaultGroup: "TEAM_A", Defaults: Defaults{Group: "TEAM_B"}},
+			wantErr: "defaultGroup",
+		},
+		{
+			name:    "namespaces disagree",
+			cfg:     Config{Namespace: "dev", Defaults: Defaults{Namespace: "prod"}},
+			wantErr: "defaults.namespace",
+		},
+		{name: "bad output", cfg: Config{Defaults: Defaults{Output: "yaml"}}, wantErr: "defaults.output"},
+		{name: "negative page size", cfg: Config{Defaults: Defaults{PageSize: -1}}, wantErr: "defaults.pageSize"},
+		{name: "max size", cfg: Config{Defaults: Defaults{MaxSize: "5MB"}}, want: Defaults{MaxSize: "5MB"}},
+		{name: "bad max size", cfg: Config{Defaults: Defaults{MaxSize: "5 megs"}}, wantErr: "defaults.maxSize"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.cfg.GetDefaults()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("GetDefaults() error = %v, want one mentioning %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetDefaults() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("GetDefaults() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// Each profile has its own defaults: switching profiles switches them, and a
+// profile without a defaults section does not inherit another's
+func TestProfileDefaults(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	profiles := map[string]string{
+		"team-a": "host: 127.0.0.1\ndefaults:\n  group: TEAM_A\n  namespace: team-a\n  output: json\n  pageSize: 100\n",
+		"legacy": "host: 127.0.0.1\nnamespace: legacy\ndefaultGroup: LEGACY\n",
+		"plain":  "host: 127.0.0.1\n",
+	}
+	for name, content := range profiles {
+		path, err := GetProfileConfigPath(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want := map[string]Defaults{
+		"team-a": {Group: "TEAM_A", Namespace: "team-a", Output: "json", PageSize: 100},
+		"legacy": {Group: "LEGACY", Namespace: "legacy"},
+		"plain":  {},
+	}
+	for name, wantDefaults := range want {
+		path, _ := GetProfileConfigPath(name)
+		cfg, err := LoadConfig(path)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		got, err := cfg.GetDefaults()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if got != wantDefaults {
+			t.Errorf("%s: GetDefaults() = %+v, want %+v", name, got, wantDefaults)
+		}
+	}
+}
+
+func TestSaveConfigOmitsEmptyDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "test.conf")
+	cfg := &Config{Host: "127.0.0.1"}
+	if err := cfg.SaveConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(data), "defaults") {
+		t.Errorf("saved config has a defaults section:\n%s", data)
+	}
+
+	cfg.Defaults.PageSize = 50
+	if err := cfg.SaveConfig(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Defaults.PageSize != 50 {
+		t.Errorf("defaults.pageSize after a round trip = %d, want 50", loaded.Defaults.PageSize)
+	}
+}
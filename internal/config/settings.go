@@ -0,0 +1,84 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// Sources of a setting besides a flag ("flag --host") and a config file
+// ("config file /path/to/file.conf")
+const (
+	SourceDefault = "default"
+	SourceEnv     = "environment"
+)
+
+// secretSettings are the settings whose values are never kept, only whether
+// they are set
+var secretSettings = map[string]bool{"password": true, "token": true, "secretKey": true}
+
+// Setting is a resolved setting and where its value came from
+type Setting struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// Settings records how each setting was resolved, in the order the settings
+// were first set
+type Settings struct {
+	list []Setting
+}
+
+// Set records the value and source of a setting, replacing an earlier record
+// of it. The value of a secret setting is masked.
+func (s *Settings) Set(name, value, source string) {
+	if secretSettings[name] && value != "" {
+		value = "******"
+	}
+	for i := range s.list {
+		if s.list[i].Name == name {
+			s.list[i] = Setting{Name: name, Value: value, Source: source}
+			return
+		}
+	}
+	s.list = append(s.list, Setting{Name: name, Value: value, Source: source})
+}
+
+// Get returns the record of a setting
+func (s *Settings) Get(name string) (Setting, bool) {
+	for _, setting := range s.list {
+		if setting.Name == name {
+			return setting, true
+		}
+	}
+	return Setting{}, false
+}
+
+// List returns a copy of every record
+func (s *Settings) List() []Setting {
+	return append([]Setting(nil), s.list...)
+}
+
+// Print writes the settings as a table of name, value and source
+func (s *Settings) Print(w io.Writer) {
+	nameWidth, valueWidth := len("Setting"), len("Value")
+	for _, setting := range s.list {
+		nameWidth = max(nameWidth, len(setting.Name))
+		valueWidth = max(valueWidth, utf8.RuneCountInString(displayValue(setting.Value)))
+	}
+	fmt.Fprintf(w, "%-*s  %-*s  %s\n", nameWidth, "Setting", valueWidth, "Value", "Source")
+	fmt.Fprintln(w, strings.Repeat("─", nameWidth+valueWidth+len("Source")+4))
+	for _, setting := range s.list {
+		fmt.Fprintf(w, "%-*s  %-*s  %s\n", nameWidth, setting.Name, valueWidth, displayValue(setting.Value), setting.Source)
+	}
+}
+
+// displayValue shows an empty value as (not set)
+func displayValue(value string) string {
+	if value == "" {
+		return "(not set)"
+	}
+	return value
+}
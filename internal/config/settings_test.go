@@ -0,0 +1,46 @@
+package config
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSettings(t *testing.T) {
+	var s Settings
+	s.Set("server", "127.0.0.1:8848", SourceDefault)
+	s.Set("password", "nacos", "config file /tmp/a.conf")
+	s.Set("token", "", SourceDefault)
+	s.Set("server", "10.0.0.1:8848", "flag --host")
+
+	want := []Setting{
+		{Name: "server", Value: "10.0.0.1:8848", Source: "flag --host"},
+		{Name: "password", Value: "******", Source: "config file /tmp/a.conf"},
+		{Name: "token", Value: "", Source: SourceDefault},
+	}
+	if got := s.List(); !reflect.DeepEqual(got, want) {
+		t.Errorf("List() = %+v, want %+v", got, want)
+	}
+	if setting, ok := s.Get("server"); !ok || setting.Source != "flag --host" {
+		t.Errorf("Get(server) = %+v, %v", setting, ok)
+	}
+	if _, ok := s.Get("namespace"); ok {
+		t.Error("Get(namespace) found a setting that was never set")
+	}
+
+	var buf bytes.Buffer
+	s.Print(&buf)
+	out := buf.String()
+	if strings.Contains(out, "nacos\n") || strings.Contains(out, " nacos ") {
+		t.Errorf("Print() shows the password:\n%s", out)
+	}
+	for _, line := range []string{
+		"server    10.0.0.1:8848  flag --host",
+		"token     (not set)      default",
+	} {
+		if !strings.Contains(out, line) {
+			t.Errorf("Print() has no line %q:\n%s", line, out)
+		}
+	}
+}
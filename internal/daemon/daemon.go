@@ -0,0 +1,194 @@
+// Package daemon runs skill-sync in the background: it starts the detached
+// process and keeps the pidfile and state file that status and stop read.
+package daemon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvDaemon is set in the environment of the background process so it knows
+// it is the daemon and not the command that starts it
+const EnvDaemon = "NACOS_CLI_SKILL_SYNC_DAEMON"
+
+// Files are where a daemon keeps its pid, state and log
+type Files struct {
+	PID   string
+	State string
+	Log   string
+}
+
+// FilesFor returns the files of the skill-sync daemon of a profile under
+// dir, e.g. ~/.nacos-cli/skill-sync-default.pid
+func FilesFor(dir, profile string) Files {
+	base := filepath.Join(dir, "skill-sync-"+profile)
+	return Files{PID: base + ".pid", State: base + ".state.json", Log: base + ".log"}
+}
+
+// IsDaemon reports whether this process was started by Start
+func IsDaemon() bool {
+	return os.Getenv(EnvDaemon) == "1"
+}
+
+// Running returns the pid of the daemon if it is running
+func (f Files) Running() (int, bool) {
+	data, err := os.ReadFile(f.PID)
+	if err != nil {
+		return 0, false
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(data)))
+	if err != nil || !processAlive(pid) {
+		return 0, false
+	}
+	return pid, true
+}
+
+// Start runs the current executable again with args as a detached daemon
+// whose output goes to the log file. It waits briefly so a daemon that fails
+// on startup is reported here rather than only in the log.
+func Start(f Files, args []string) (int, error) {
+	if pid, ok := f.Running(); ok {
+		return 0, fmt.Errorf("skill-sync daemon already running (pid %d)", pid)
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return 0, err
+	}
+	if err := os.MkdirAll(filepath.Dir(f.Log), 0755); err != nil {
+		return 0, err
+	}
+	logFile, err := os.OpenFile(f.Log, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return 0, err
+	}
+	defer logFile.Close()
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), EnvDaemon+"=1")
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detachAttrs()
+	if err := cmd.Start(); err != nil {
+		return 0, err
+	}
+
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+	select {
+	case <-exited:
+		return 0, fmt.Errorf("skill-sync daemon exited on startup; see %s", f.Log)
+	case <-time.After(2 * time.Second):
+		return cmd.Process.Pid, nil
+	}
+}
+
+// Stop signals the daemon to shut down and waits up to timeout for it to exit
+func Stop(f Files, timeout time.Duration) (int, error) {
+	pid, ok := f.Running()
+	if !ok {
+		return 0, fmt.Errorf("skill-sync daemon is not running")
+	}
+	if err := terminate(pid); err != nil {
+		return pid, err
+	}
+	deadline := time.Now().Add(timeout)
+	for processAlive(pid) {
+		if time.Now().After(deadline) {
+			return pid, fmt.Errorf("skill-sync daemon (pid %d) did not stop within %s", pid, timeout)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	return pid, nil
+}
+
+// SkillEvent is the last thing that happened to a skill
+type SkillEvent struct {
+	Event string    `json:"event"`
+	At    time.Time `json:"at"`
+}
+
+// State is what the daemon reports to status. It is rewritten on every event,
+// so status works without talking to the daemon.
+type State struct {
+	PID       int                   `json:"pid"`
+	StartedAt time.Time             `json:"startedAt"`
+	Command   string                `json:"command"`
+	Skills    map[string]SkillEvent `json:"skills"`
+	UpdatedAt time.Time             `json:"updatedAt"`
+}
+
+// ReadState reads the state file
+func ReadState(f Files) (*State, error) {
+	data, err := os.ReadFile(f.State)
+	if err != nil {
+		return nil, err
+	}
+	var state State
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("read daemon state: %w", err)
+	}
+	return &state, nil
+}
+
+// Recorder keeps the state file of the running daemon up to date
+type Recorder struct {
+	files Files
+	mu    sync.Mutex
+	state State
+}
+
+// NewRecorder writes the pidfile and an initial state for this process
+func NewRecorder(f Files, command string, skills []string) (*Recorder, error) {
+	r := &Recorder{files: f, state: State{
+		PID:       os.Getpid(),
+		StartedAt: time.Now(),
+		Command:   command,
+		Skills:    make(map[string]SkillEvent),
+	}}
+	for _, name := range skills {
+		r.state.Skills[name] = SkillEvent{Event: "watching", At: r.state.StartedAt}
+	}
+	if err := os.WriteFile(f.PID, []byte(strconv.Itoa(r.state.PID)+"\n"), 0644); err != nil {
+		return nil, err
+	}
+	return r, r.save()
+}
+
+// Record notes an event for a skill and rewrites the state file
+func (r *Recorder) Record(skill, event string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state.Skills[skill] = SkillEvent{Event: event, At: time.Now()}
+	return r.save()
+}
+
+// Close removes the pidfile; the state file is kept for post-mortems
+func (r *Recorder) Close() error {
+	err := os.Remove(r.files.PID)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// save writes the state atomically so status never reads half a file
+func (r *Recorder) save() error {
+	r.state.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(r.state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := r.files.State + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, r.files.State)
+}
@@ -0,0 +1,69 @@
+package daemon
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestRecorder(t *testing.T) {
+	files := FilesFor(t.TempDir(), "dev")
+	if filepath.Base(files.PID) != "skill-sync-dev.pid" {
+		t.Errorf("PID file = %s, want skill-sync-dev.pid", files.PID)
+	}
+	if _, ok := files.Running(); ok {
+		t.Fatal("Running() = true before the daemon started")
+	}
+
+	r, err := NewRecorder(files, "skill-sync --all --daemon", []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("NewRecorder() error = %v", err)
+	}
+	// The recording process is alive, so it counts as the running daemon
+	if pid, ok := files.Running(); !ok || pid != os.Getpid() {
+		t.Errorf("Running() = %d, %v; want %d, true", pid, ok, os.Getpid())
+	}
+
+	if err := r.Record("a", "synced"); err != nil {
+		t.Fatalf("Record() error = %v", err)
+	}
+	state, err := ReadState(files)
+	if err != nil {
+		t.Fatalf("ReadState() error = %v", err)
+	}
+	if state.PID != os.Getpid() || state.Command != "skill-sync --all --daemon" {
+		t.Errorf("state = %+v", state)
+	}
+	if got := state.Skills["a"].Event; got != "synced" {
+		t.Errorf("skill a event = %q, want synced", got)
+	}
+	if got := state.Skills["b"].Event; got != "watching" {
+		t.Errorf("skill b event = %q, want watching", got)
+	}
+	if time.Since(state.UpdatedAt) > time.Minute {
+		t.Errorf("UpdatedAt = %s, want now", state.UpdatedAt)
+	}
+
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, ok := files.Running(); ok {
+		t.Error("Running() = true after Close()")
+	}
+	if _, err := ReadState(files); err != nil {
+		t.Errorf("state file removed by Close(): %v", err)
+	}
+}
+
+func TestStalePIDFile(t *testing.T) {
+	files := FilesFor(t.TempDir(), "default")
+	// A pid that cannot belong to a live process
+	os.WriteFile(files.PID, []byte("999999999\n"), 0644)
+	if _, ok := files.Running(); ok {
+		t.Error("Running() = true for a stale pidfile")
+	}
+	if _, err := Stop(files, time.Second); err == nil {
+		t.Error("Stop() succeeded with no daemon running")
+	}
+}
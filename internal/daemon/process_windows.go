@@ -0,0 +1,36 @@
+//go:build windows
+
+package daemon
+
+import (
+	"os"
+	"syscall"
+)
+
+// detachAttrs starts the daemon without a console window
+func detachAttrs() *syscall.SysProcAttr {
+	const createNewProcessGroup, detachedProcess = 0x00000200, 0x00000008
+	return &syscall.SysProcAttr{CreationFlags: createNewProcessGroup | detachedProcess}
+}
+
+func processAlive(pid int) bool {
+	const processQueryLimitedInformation = 0x1000
+	h, err := syscall.OpenProcess(processQueryLimitedInformation, false, uint32(pid))
+	if err != nil {
+		return false
+	}
+	defer syscall.CloseHandle(h)
+	var code uint32
+	const stillActive = 259
+	return syscall.GetExitCodeProcess(h, &code) == nil && code == stillActive
+}
+
+// terminate stops the daemon. Windows has no SIGTERM for a detached
+// process, so it is killed without running its shutdown.
+func terminate(pid int) error {
+	process, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return process.Kill()
+}
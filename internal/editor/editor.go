@@ -0,0 +1,77 @@
+package editor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Command returns the user's editor from $VISUAL or $EDITOR, falling back to
+// vi (notepad on Windows). The value may include arguments, e.g. "code --wait".
+func Command() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// Edit writes content to a temporary file, opens it in the user's editor and
+// returns the saved content. The file keeps the extension of name so editors
+// can pick syntax highlighting. An error is returned if the editor exits non-zero.
+func Edit(name string, content []byte) ([]byte, error) {
+	f, err := os.CreateTemp("", "nacos-cli-*"+filepath.Ext(name))
+	if err != nil {
+		return nil, fmt.Errorf("create temp file: %w", err)
+	}
+	path := f.Name()
+	defer os.Remove(path)
+
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("write temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return nil, fmt.Errorf("write temp file: %w", err)
+	}
+
+	command := Command()
+	cmd := exec.Command(command[0], append(command[1:], path)...)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s: %w", command[0], err)
+	}
+
+	edited, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read temp file: %w", err)
+	}
+	return edited, nil
+}
+
+// Keep saves content that was edited but could not be used, such as an edit
+// of a config that changed in the meantime, to a new file in the temporary
+// directory and returns its path, so that the edit is not lost
+func Keep(name string, content []byte) (string, error) {
+	f, err := os.CreateTemp("", "nacos-cli-edit-*"+filepath.Ext(name))
+	if err != nil {
+		return "", fmt.Errorf("create file: %w", err)
+	}
+	if _, err := f.Write(content); err != nil {
+		f.Close()
+		return "", fmt.Errorf("write %s: %w", f.Name(), err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("write %s: %w", f.Name(), err)
+	}
+	return f.Name(), nil
+}
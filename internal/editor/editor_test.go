@@ -0,0 +1,61 @@
+package editor
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+func TestCommand(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	if got := Command(); len(got) != 2 || got[0] != "code" || got[1] != "--wait" {
+		t.Errorf("Command() = %q, want [code --wait]", got)
+	}
+
+	t.Setenv("VISUAL", "nano")
+	if got := Command(); len(got) != 1 || got[0] != "nano" {
+		t.Errorf("Command() = %q, want VISUAL to win", got)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses shell scripts as editors")
+	}
+	t.Setenv("VISUAL", "")
+
+	script := filepath.Join(t.TempDir(), "editor.sh")
+	body := "#!/bin/sh\nsed s/old/new/ \"$1\" > \"$1.tmp\" && mv \"$1.tmp\" \"$1\"\n"
+	if err := os.WriteFile(script, []byte(body), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("EDITOR", script)
+	got, err := Edit("app.yaml", []byte("key: old\n"))
+	if err != nil {
+		t.Fatalf("Edit() unexpected error: %v", err)
+	}
+	if string(got) != "key: new\n" {
+		t.Errorf("Edit() = %q, want %q", got, "key: new\n")
+	}
+
+	t.Setenv("EDITOR", "false")
+	if _, err := Edit("app.yaml", []byte("x")); err == nil {
+		t.Error("Edit() expected error when the editor exits non-zero")
+	}
+}
+
+func TestKeep(t *testing.T) {
+	t.Setenv("TMPDIR", t.TempDir())
+	path, err := Keep("app.yaml", []byte("key: new\n"))
+	if err != nil {
+		t.Fatalf("Keep() error = %v", err)
+	}
+	if filepath.Ext(path) != ".yaml" {
+		t.Errorf("Keep() = %s, want the extension of the config", path)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "key: new\n" {
+		t.Errorf("kept content = %q, %v", got, err)
+	}
+}
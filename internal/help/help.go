@@ -3,6 +3,8 @@ package help
 import (
 	"fmt"
 	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/i18n"
 )
 
 // CommandHelp defines the help information for a command
@@ -22,11 +24,22 @@ var (
 			"--name string   Filter by skill name (supports wildcard *)",
 			"--page int      Page number (default: 1)",
 			"--size int      Page size (default: 20)",
+			"--detail        Show a table with each skill's resource count, and its version and tags from SKILL.md",
+			"--tag string    Only show skills of the page with this tag in their SKILL.md",
+			"--output string Output format: table, json or csv (json and csv keep descriptions in full; default: table)",
 		},
 		Examples: []string{
 			"# List all skills",
 			"skill-list",
 			"",
+			"# Show versions and tags, or only skills tagged search",
+			"skill-list --detail",
+			"skill-list --tag search",
+			"skill-list --detail --output json",
+			"",
+			"# Export an inventory for a spreadsheet",
+			"skill-list --detail --size 100 --output csv > skills.csv",
+			"",
 			"# Search by name",
 			"skill-list --name \"creator\"",
 			"",
@@ -40,9 +53,16 @@ var (
 		Description: "Download a skill from Nacos to local directory via the Client Skill API.",
 		Parameters: []string{
 			"skillName...    Required. One or more skill names to download",
-			"-o, --output    Output directory (default: ~/.skills)",
+			"-o, --output    Output directory (default: NACOS_SKILLS_DIR, else skillsDir from the config file, else ~/.skills)",
 			"--version       Specific version to download (e.g. v1, v2)",
 			"--label         Route label to resolve version (e.g. latest, stable)",
+			"--force         Overwrite an existing skill directory without asking, rewriting unchanged files too",
+			"--preserve-exec Make scripts executable: files uploaded as executable, with a shebang line, or .sh/.py under scripts/ (default: true; --preserve-exec=false to turn off)",
+			"--concurrency   How many skills to download at once (default: 8)",
+			"--allow-partial Install a skill even if some of its files had to be skipped, listing them",
+			"--uniform-retries     How often to re-read a skill published via config while it is being republished (default: 3)",
+			"--uniform-retry-delay Wait before the first re-read, doubled for each next one (default: 1s)",
+			"--skip-uniform-check  Install a skill published via config even if its files are from different uploads",
 		},
 		Examples: []string{
 			"# Download the latest version of a skill",
@@ -57,6 +77,9 @@ var (
 			"# Download to a custom directory",
 			"skill-get skill-creator -o ~/my-skills",
 			"",
+			"# Replace a skill that was downloaded before",
+			"skill-get skill-creator --force",
+			"",
 			"# Download multiple skills",
 			"skill-get skill-creator skill-analyzer",
 		},
@@ -66,8 +89,11 @@ var (
 		Command:     "skill-publish",
 		Description: "Publish a skill to Nacos by uploading it as a ZIP file (creates a draft version).\nReview and go-online operations should be done via the Nacos console.",
 		Parameters: []string{
-			"skillPath       Required. Path to the skill directory",
+			"skillPath       Required. Path to the skill directory, or a .zip such as one written by skill-export",
 			"--all           Publish all skills in the specified directory",
+			"--version       Record this version (e.g. 1.4.0) in the uploaded SKILL.md; the local file is not changed",
+			"--via-config    Publish through the config API, for servers without the skill upload API",
+			"--report        Write a JSON report of each skill and the totals to this file, for CI",
 		},
 		Examples: []string{
 			"# Publish a single skill",
@@ -76,9 +102,95 @@ var (
 			"# Publish all skills in a directory",
 			"skill-publish --all ./skills-folder",
 			"",
+			"# Publish a release, shown by 'skill-list --detail'",
+			"skill-publish ./my-skill --version 1.4.0",
+			"",
+			"# Keep a report for CI, and fail the build if any skill failed",
+			"skill-publish --all ./skills-folder --report publish.json",
+			"jq -e '.failed == 0' publish.json",
+			"",
 			"Note:",
 			"  - Skill directory must contain SKILL.md",
 			"  - After publishing, use the Nacos console to review and go online",
+			"  - Report fields: version (1), command, startedAt, durationMs, total, failed, counts (by status), bytes, items",
+			"  - Each item has name, status (published or failed), failed, error, durationMs and bytes (uploaded)",
+		},
+	}
+
+	SkillExport = CommandHelp{
+		Command:     "skill-export",
+		Description: "Export a skill from Nacos to a local zip without installing it.\nThe zip can be published to another cluster with skill-publish.",
+		Parameters: []string{
+			"skillName       Required unless --all. The skill to export",
+			"-o, --output    Zip file to write (default: <name>-YYYYMMDD.zip); with --all, the directory (default: skills-YYYYMMDD)",
+			"--all           Export every skill as <name>.zip into one directory, with a manifest.json",
+			"--version       Specific version to export (e.g. v1, v2)",
+			"--label         Route label to resolve version (e.g. latest, stable)",
+			"--concurrency   With --all, how many skills to download at once (default: 8)",
+		},
+		Examples: []string{
+			"# Snapshot a skill",
+			"skill-export skill-creator",
+			"",
+			"# Snapshot a released version to a chosen file",
+			"skill-export skill-creator --label stable -o skill-creator-stable.zip",
+			"",
+			"# Back up every skill, with a manifest.json",
+			"skill-export --all -o backup",
+			"",
+			"# Import an export into another cluster",
+			"skill-publish skill-creator-20260101.zip",
+		},
+	}
+
+	SkillVerify = CommandHelp{
+		Command:     "skill-verify",
+		Description: "Check installed skills against the hashes recorded when they were downloaded, to find files\nthat were changed, removed or added since, e.g. by local edits or a damaged disk.",
+		Parameters: []string{
+			"skillName       Skills to verify, unless --all",
+			"--all           Verify every skill in the directory",
+			"-d, --dir       Directory the skills are installed in (default: NACOS_SKILLS_DIR, else ~/.skills)",
+		},
+		Examples: []string{
+			"# Check one skill",
+			"skill-verify skill-creator",
+			"",
+			"# Check every installed skill",
+			"skill-verify --all",
+			"",
+			"Note:",
+			"  - Skills installed before skill-verify existed have no hashes; install them again with skill-get",
+			"  - Exits non-zero when any file differs or a skill cannot be verified",
+		},
+	}
+
+	SkillMigrate = CommandHelp{
+		Command:     "skill-migrate",
+		Description: "Copy skills from one Nacos to another, e.g. between environments, and check each one arrived.\nThe servers come from two profiles. A state file records finished skills, so an interrupted\nmigration resumes where it stopped.",
+		Parameters: []string{
+			"--from-profile  Required. Profile of the source, or the path of a config file",
+			"--to-profile    Required. Profile of the destination, or the path of a config file",
+			"--skills        Skills to migrate, comma-separated",
+			"--all           Migrate every skill of the source instead",
+			"--overwrite     Replace skills the destination has with other files",
+			"--skip          Leave such skills alone and report them (the default)",
+			"--via-config    Publish through the config API, for destinations without the skill upload API",
+			"--state         State file (default: ~/.nacos-cli/migrate-<from>-<to>.json)",
+			"--report        Write a JSON report of each skill and the totals to this file, for CI",
+		},
+		Examples: []string{
+			"# Promote two skills from staging to production",
+			"skill-migrate --from-profile staging --to-profile prod --skills skill-creator,pdf",
+			"",
+			"# Copy every skill, replacing those that differ",
+			"skill-migrate --from-profile staging --to-profile prod --all --overwrite",
+			"",
+			"Note:",
+			"  - Each skill is downloaded again from the destination and compared with the source",
+			"  - Skills uploaded through the skill API are drafts until they are put online in the console",
+			"  - Run the same command again to retry the skills that failed or could not be verified",
+			"  - Report fields: version (1), command, startedAt, durationMs, total, failed, counts (by status), bytes, items",
+			"  - Each item has name, status, failed (for failed and unverified skills), error, durationMs and bytes (uploaded)",
 		},
 	}
 
@@ -90,6 +202,7 @@ var (
 			"--group string     Filter by group (supports wildcard *)",
 			"--page int         Page number (default: 1)",
 			"--size int         Page size (default: 20)",
+			"--output string    Output format: table or csv (csv keeps names in full; default: table)",
 		},
 		Examples: []string{
 			"# List all configurations",
@@ -103,6 +216,9 @@ var (
 			"",
 			"# Combine filters with pagination",
 			"config-list --data-id *config* --group DEFAULT_GROUP --page 1 --size 50",
+			"",
+			"# Export an inventory for a spreadsheet",
+			"config-list --size 500 --output csv > configs.csv",
 		},
 	}
 
@@ -111,7 +227,17 @@ var (
 		Description: "Get a specific configuration from Nacos.",
 		Parameters: []string{
 			"dataId          Required. Configuration data ID",
-			"group           Required. Configuration group name",
+			"group           Configuration group name (default: defaults.group from the config file, or 'use group' in the terminal)",
+			"--batch         Fetch several configs given as dataId:group ('-' reads them from stdin)",
+			"--expand        Treat * in dataId and group as wildcards and fetch every matching config, each after a '==> dataId:group <==' line",
+			"--expand-limit  With --expand, ask before fetching more configs than this; --yes skips the question (default: 20)",
+			"--output-dir    With --batch or --expand, write files named <group>__<dataId> instead of stdout (two configs mapping to the same name fail)",
+			"--concurrency   With --batch or --expand, maximum concurrent requests (default: 4)",
+			"--strict        With --batch or --expand, exit non-zero if any config is not found",
+			"--output-file   Write the content unchanged to a file (CLI only; use > in the terminal)",
+			"--compact       Show JSON as stored; by default it is pretty-printed on a terminal",
+			"--query         Print only the value at a path of YAML or JSON content: .a.b, .list[0], .list[-1], .[\"key.with.dots\"]",
+			"--output        text or json: json prints the content, or the --query value, as JSON (default: text)",
 		},
 		Examples: []string{
 			"# Get a configuration",
@@ -119,6 +245,25 @@ var (
 			"",
 			"# Get a skill configuration",
 			"config-get skill.json skill_skill-creator",
+			"",
+			"# Save the content to a file exactly as stored",
+			"config-get application.yaml DEFAULT_GROUP --output-file ./application.yaml",
+			"",
+			"# Fetch several configs at once as a JSON map",
+			"config-get --batch app.yaml:DEFAULT_GROUP db.yaml:DEFAULT_GROUP",
+			"",
+			"# Read dataId:group pairs from stdin and write them to files",
+			" cat configs.txt | nacos-cli config-get --batch - --output-dir ./configs",
+			"",
+			"# Fetch every config matching wildcards, one after another",
+			"config-get 'app.*.yaml' 'team_*' --expand",
+			"",
+			"# Print one value for a script, or a section as JSON",
+			"config-get application.yaml DEFAULT_GROUP --query '.database.host'",
+			"config-get application.yaml DEFAULT_GROUP --query '.database' --output json",
+			"",
+			"Note:",
+			"  - --query exits 1 when the path is not in the content; strings are printed without quotes",
 		},
 	}
 
@@ -127,8 +272,23 @@ var (
 		Description: "Publish a configuration to Nacos (create or update).",
 		Parameters: []string{
 			"dataId          Required. Configuration data ID",
-			"group           Required. Configuration group name",
-			"--file, -f      Path to config file (default: read from stdin)",
+			"group           Configuration group name (default: defaults.group from the config file, or 'use group' in the terminal)",
+			"--file, -f      Path to config file (default: read from stdin, kept byte for byte)",
+			"--stdin-timeout Fail if stdin has not ended after this long, e.g. 30s (default: wait)",
+			"--from-url      Fetch content from an http(s) URL (redirects are followed)",
+			"--from-clipboard  Read content from the system clipboard; shows its first and last lines and asks before publishing",
+			"--url-header    Header for --from-url as 'Name: value' (repeatable, never printed)",
+			"--sha256        Expected SHA-256 checksum (hex) of the downloaded content",
+			"--render        Render content as a Go template (missing variables are errors)",
+			"--var k=v       Template variable (repeatable, overrides --var-file)",
+			"--var-file      YAML file with template variables",
+			"--dry-run       Print the content that would be published without publishing",
+			"--edit          Edit the current content in $VISUAL/$EDITOR (default: vi), review the diff, then publish",
+			"--max-size      Refuse content larger than this, e.g. 5MB (default: defaults.maxSize, else 1MB)",
+			"--force         Publish content larger than --max-size",
+			"--allow-binary  Publish content that looks binary (NUL bytes, invalid UTF-8) without asking",
+			"--allow-shrink  Publish content under a tenth of the current content without asking",
+			"--no-validate   Publish yaml, json, properties or xml content that does not parse (types come from the dataId, else the -f file)",
 		},
 		Examples: []string{
 			"# Publish from file",
@@ -137,16 +297,320 @@ var (
 			"# Publish from stdin",
 			" echo 'key: value' | nacos-cli config-set app.yaml DEFAULT_GROUP",
 			"",
+			"# In CI, fail instead of waiting when nothing is piped in",
+			" render-config | nacos-cli config-set app.yaml DEFAULT_GROUP --stdin-timeout 30s",
+			"",
 			"# Publish JSON config",
 			"config-set skill.json skill_my-skill -f ./skill.json",
+			"",
+			"# Publish from an artifact store, verifying the checksum",
+			"config-set app.yaml DEFAULT_GROUP --from-url https://artifacts.example.com/app.yaml --url-header 'Authorization: Bearer $TOKEN' --sha256 <hex>",
+			"",
+			"# Render a template (use {{ .key }} and {{ env \"NAME\" }}) and preview it",
+			"config-set app.yaml DEFAULT_GROUP -f app.yaml.tmpl --render --var-file prod.yaml --var replicas=3 --dry-run",
+			"",
+			"# Edit the remote content in your editor and publish after reviewing the diff",
+			"config-set app.yaml DEFAULT_GROUP --edit",
+			"",
+			"# Publish what you copied, after checking its first and last lines",
+			"config-set app.yaml DEFAULT_GROUP --from-clipboard",
+			"",
+			"# In automation, publish a large binary certificate bundle on purpose",
+			"config-set certs.p12 DEFAULT_GROUP -f certs.p12 --max-size 5MB --allow-binary --yes",
+		},
+	}
+
+	ConfigEdit = CommandHelp{
+		Command:     "config-edit",
+		Description: "Edit a configuration in $VISUAL/$EDITOR (default: vi), review the diff and publish it.\nThe publish fails if someone else published the config since it was opened; your edit is then saved to a file.",
+		Parameters: []string{
+			"dataId          Required. Configuration data ID",
+			"group           Configuration group name (default: defaults.group from the config file, or 'use group' in the terminal)",
+			"--create        Start from empty content if the config does not exist",
+			"--no-validate   Publish yaml, json, properties or xml content that does not parse (see config-lint)",
+			"--dry-run       Show the diff of the edit without publishing",
+		},
+		Examples: []string{
+			"# Edit a config and publish after reviewing the diff",
+			"config-edit app.yaml DEFAULT_GROUP",
+			"",
+			"# Create a config in the editor",
+			"config-edit new-service.yaml DEFAULT_GROUP --create",
+			"",
+			"Note:",
+			"  - Content that does not parse as the config's type (from its type, else its dataId) can be edited again",
+			"  - --yes publishes without asking; invalid content then fails instead of reopening the editor",
+		},
+	}
+
+	ConfigLint = CommandHelp{
+		Command:     "config-lint",
+		Description: "Check that config content parses as YAML, JSON, properties or XML, and report errors by line and column.\nDuplicate keys in YAML maps and properties files are errors too: parsers silently keep the last one.",
+		Parameters: []string{
+			"--file, -f      Config file to check (default: read from stdin)",
+			"--type          yaml, json, properties or xml (default: from the file extension)",
+		},
+		Examples: []string{
+			"# Check a file before publishing it",
+			"config-lint -f application.yaml",
+			"",
+			"# Check the live content of a config",
+			" nacos-cli config-get app.conf DEFAULT_GROUP | nacos-cli config-lint --type yaml",
+			"",
+			"Note:",
+			"  - Exits 1 when the content has errors, printed as file:line:column: message",
+			"  - config-set runs the same check for dataIds ending in .yaml, .yml, .json, .properties or .xml",
+		},
+	}
+
+	ConfigExists = CommandHelp{
+		Command:     "config-exists",
+		Description: "Check whether a configuration exists, for scripts: prints nothing and answers with the exit code.\nWith --md5 it also checks that the content is exactly what is about to be published.",
+		Parameters: []string{
+			"dataId          Configuration data ID",
+			"group           Configuration group name (default: defaults.group from the config file)",
+			"--md5           Exit 0 only when the content has this MD5",
+		},
+		Examples: []string{
+			"# Create a config only if it is missing",
+			"config-exists app.yaml DEFAULT_GROUP || config-set app.yaml DEFAULT_GROUP -f app.yaml",
+			"",
+			"# Skip a publish when the live config is already the file",
+			"config-exists app.yaml DEFAULT_GROUP --md5 \"$(md5sum < app.yaml | cut -d' ' -f1)\" || config-set app.yaml DEFAULT_GROUP -f app.yaml",
+			"",
+			"Note:",
+			"  - Exit codes: 0 exists (and matches --md5), 4 not found, 5 MD5 differs, 3 login failed, 1 other errors",
+		},
+	}
+
+	ConfigWait = CommandHelp{
+		Command:     "config-wait",
+		Description: "Wait until a configuration changes, then print its new MD5 and exit: the step between a one-off\nconfig-get and config-sync, e.g. for a CI job waiting for an operator to flip a config.",
+		Parameters: []string{
+			"dataId          Configuration data ID",
+			"group           Configuration group name (default: defaults.group from the config file)",
+			"--timeout       Give up after this long and exit with 124 (default: wait forever)",
+			"--md5           MD5 to wait to change from (default: the current content)",
+			"--print         Print the new content after its MD5",
+			"--poll-interval How often the config is fetched (default: 15s)",
+		},
+		Examples: []string{
+			"# Block a deploy until the release flag is flipped, for at most 5 minutes",
+			"config-wait release.yaml DEFAULT_GROUP --timeout 300s --print",
+			"",
+			"# Wait for a change since a known version, even if it already happened",
+			"config-wait app.yaml DEFAULT_GROUP --md5 e0ccdaf42c593022e15b18cc0e96d201",
+			"",
+			"Note:",
+			"  - Exit codes: 0 changed, 4 deleted, 124 timed out, 3 login failed, 1 other errors",
+			"  - --timeout here is the overall deadline, not the timeout of each request",
+		},
+	}
+
+	GroupList = CommandHelp{
+		Command:     "group-list",
+		Description: "List the config groups of the namespace with how many configs each holds.\nNacos has no group API, so every config of the namespace is listed to find them.",
+		Parameters: []string{
+			"--output string Output format: table, json or csv (default: table)",
+		},
+		Examples: []string{
+			"# List all groups",
+			"group-list",
+			"",
+			"# As JSON, for scripts",
+			"group-list --output json",
+		},
+	}
+
+	ConfigGroupDelete = CommandHelp{
+		Command:     "config-group-delete",
+		Description: "Delete every config of a group, or only those whose data ID matches --data-id.\nThe configs are listed and the deletion has to be confirmed; a failed config does not stop the others.",
+		Parameters: []string{
+			"group           Required. Group name (wildcards are not allowed)",
+			"--data-id       Only delete configs whose data ID matches (supports wildcard *)",
+			"--dry-run       List the configs that would be deleted without deleting them",
+			"--yes           Delete without asking (global flag)",
+		},
+		Examples: []string{
+			"# See what would be deleted",
+			"config-group-delete experiment-42 --dry-run",
+			"",
+			"# Delete a whole group after confirming",
+			"config-group-delete experiment-42",
+			"",
+			"# Delete only some configs of a group, without asking",
+			"config-group-delete team_a --data-id 'tmp-*' --yes",
+			"",
+			"Note:",
+			"  - Deleted configs cannot be restored from the CLI; use --dry-run first",
+		},
+	}
+
+	ConfigEffective = CommandHelp{
+		Command:     "config-effective",
+		Description: "Show every setting in effect (server, namespace, credentials, timeouts, defaults) with where\nits value came from: a flag, the config file, the environment or the built-in default.\nSecrets are masked.",
+		Parameters: []string{
+			"--output        Output format: table or json (default: table)",
+		},
+		Examples: []string{
+			"# Which server and namespace would the dev profile use, and why",
+			"config-effective --profile dev",
+			"",
+			"# Check that a flag overrides the config file",
+			"config-effective -c ./local.conf --host 10.0.0.1",
+			"",
+			"Note:",
+			"  - In the terminal, 'settings' shows the same, with the namespace and group of the session",
 		},
 	}
 
 	SkillSync = CommandHelp{
 		Command:     "skill-sync",
-		Description: "(Removed) Skill sync is no longer supported.",
-		Parameters:  []string{},
-		Examples:    []string{},
+		Description: "Download skills and keep them in sync: a skill is re-downloaded whenever it changes in Nacos.\nWith --push the direction is reversed: local skill directories are uploaded whenever their files change.\nIn the interactive terminal the sync runs as a background job (see 'jobs', 'logs' and 'stop').",
+		Parameters: []string{
+			"skillName...    Skill names to sync (with --push: skill directories)",
+			"--all           Sync all skills in the namespace (with --push: all skills in the folder)",
+			"--push          Upload local skill directories whenever their files change",
+			"--force-remote  Overwrite local edits with remote changes instead of saving them as <skill>.remote",
+			"--force-initial-sync  Download every skill at startup, even those unchanged since the last run",
+			"--init-concurrency    How many skills the first sync downloads at once (default: 5)",
+			"--max-bandwidth size  Limit all downloads together to this many bytes per second, e.g. 2MB (default: 0, no limit)",
+			"--startup-jitter      Wait a random time up to this long before the first sync, e.g. 30s (default: 0)",
+			"--debounce            Wait this long after a skill changes for further changes, then sync it once (default: 2s; 0 syncs on every change)",
+			"--listen-early        Start watching for changes before the first sync has finished downloading",
+			"--preserve-exec       Make scripts executable, as for skill-get (default: true)",
+			"--pin skill=version   Hold a skill at a version (as for skill-get --version) while others track the latest (repeatable)",
+			"--map skill=/dir      Sync a skill into this absolute directory instead of --output (repeatable, wins over --map-file)",
+			"--map-file file       YAML file of skill: /dir entries, as for --map",
+			"--verbose-diff        Log a unified diff of each changed text file when a skill is updated",
+			"--include glob        With --all or --catalog, sync only skills whose name matches, e.g. team-* (repeatable)",
+			"--exclude glob        With --all or --catalog, skip skills whose name matches; wins over --include (repeatable)",
+			"--catalog dataId:group  Sync the skills a config lists (JSON array or one name per line), following its changes",
+			"--daemon        Run in the background (CLI mode only); manage it with 'skill-sync status' and 'skill-sync stop'",
+			"-o, --output    Output directory (default: NACOS_SKILLS_DIR, else skillsDir from the config file, else ~/.skills)",
+			"--poll-timeout  How long each poll for changes may take (default: pollTimeout from the config file, or 30s)",
+			"--poll-interval How often each watched config is fetched to check for changes (default: 15s)",
+			"--log-file      Also write structured log entries to this file (rotated at 10MB, 3 old files kept)",
+			"--log-format    Format of --log-file and of the daemon log: text (default) or json",
+			"--on-change     Command to run after a skill is updated or deleted (default: onChange from the config file)",
+			"--on-error      Command to run after a skill fails to sync (default: onError from the config file)",
+			"--hook-timeout  Kill a hook that runs longer than this (default: 1m)",
+			"--batch-size    How many configs each concurrent poller watches (default: 100)",
+		},
+		Examples: []string{
+			"# Sync a single skill",
+			"skill-sync skill-creator",
+			"",
+			"# Sync all skills to a custom directory",
+			"skill-sync --all -o ~/my-skills",
+			"",
+			"# Sync the team's skills except the experimental ones",
+			"skill-sync --all --include 'team-*' --exclude '*-beta' --exclude '*-experimental'",
+			"",
+			"# Sync the skills a catalog config lists, picking up skills added to it",
+			"skill-sync --catalog skills.index:DEFAULT_GROUP",
+			"",
+			"# Put one skill under the agent's plugin directory, the others in ~/.skills",
+			"skill-sync --all --map code-review=/opt/agent/plugins",
+			"",
+			"# Behind a gateway that closes idle connections after 20s",
+			"skill-sync --all --poll-timeout 15s",
+			"",
+			"# Restart a fleet without flooding Nacos: start within a minute, at most 2MB/s per host",
+			"skill-sync --all --startup-jitter 1m --max-bandwidth 2MB",
+			"",
+			"# Push local edits to Nacos while authoring a skill",
+			"skill-sync --push ./skills/my-skill",
+			"",
+			"# Push every skill under a folder",
+			"skill-sync --push --all ./skills",
+			"",
+			"# Tell the local agent to reload its skills after each change",
+			"skill-sync --all --on-change 'curl -s -X POST localhost:8080/skills/reload'",
+			"",
+			"# Keep a JSON log for post-mortems",
+			"skill-sync --all --log-file ~/.skills/sync.log --log-format json",
+			"",
+			"# Keep all skills in sync in the background",
+			"skill-sync --all --daemon",
+			"skill-sync status",
+			"skill-sync stop",
+			"",
+			"# Fail a health check when syncing stopped making progress",
+			"skill-sync status --max-staleness 2m",
+			"",
+			"Note:",
+			"  - CLI mode runs until Ctrl+C",
+			"  - Terminal mode starts a background job and returns to the prompt",
+			"  - A skill edited locally is not overwritten; a remote change is saved as <skill>.remote",
+			"  - Hooks get NACOS_SKILL_NAME, NACOS_EVENT (updated, deleted or error) and NACOS_SKILL_PATH",
+			"  - An update is logged with the files it added, modified and removed; hooks get the same in NACOS_CHANGES",
+			"  - --include and --exclude follow path.Match: * matches any characters, ? one, [a-z] a class",
+			"  - A skill taken off the --catalog is removed locally, as one deleted in Nacos; a deleted catalog changes nothing",
+			"  - A heartbeat is written to ~/.nacos-cli/sync-status.json after every poll cycle",
+			"  - A mapped directory must be absolute and writable; 'skill-sync status' shows where each skill is",
+			"  - The first sync downloads the smallest skills first, by their size when last synced",
+		},
+	}
+
+	ConfigSync = CommandHelp{
+		Command:     "config-sync",
+		Description: "Mirror configs to local files, for applications that only read files.\nEach config is written once, then its file is rewritten whenever the config changes in Nacos.\nFiles are replaced atomically (written to a temporary file and renamed), so readers never see a partial file.",
+		Parameters: []string{
+			"--map             Mirror a config to a file, as dataId:group:path (repeatable; an empty group means defaults.group)",
+			"-f, --file        YAML file with a 'configs' list of dataId, group, path and reload",
+			"--reload          Command to run after a file is rewritten or removed, for mappings without their own",
+			"--on-delete       What to do with the file of a config deleted in Nacos: keep (default, with a warning) or delete",
+			"--reload-timeout  Kill a reload command that runs longer than this (default: 1m)",
+			"--poll-timeout    How long each poll for changes may take (default: pollTimeout from the config file, or 30s)",
+			"--poll-interval   How often each config is fetched to check for changes (default: 15s)",
+			"--batch-size      How many configs each concurrent poller watches (default: 100)",
+			"--log-file        Also write structured log entries to this file (rotated at 10MB, 3 old files kept)",
+			"--log-format      Format of --log-file: text (default) or json",
+		},
+		Examples: []string{
+			"# Mirror one config and reload the app after each change",
+			"config-sync --map app.yaml:prod:/etc/app/app.yaml --reload 'systemctl reload app'",
+			"",
+			"# Mirror the configs listed in a mapping file",
+			"config-sync -f ~/app-configs.yaml",
+			"",
+			"# Mapping file",
+			"configs:",
+			"  - dataId: app.yaml",
+			"    group: prod",
+			"    path: /etc/app/app.yaml",
+			"    reload: systemctl reload app",
+			"",
+			"Note:",
+			"  - Runs until Ctrl+C",
+			"  - Relative paths in a mapping file are relative to the file",
+			"  - Reload commands get NACOS_DATA_ID, NACOS_GROUP, NACOS_CONFIG_PATH and NACOS_EVENT (updated or deleted)",
+		},
+	}
+
+	ConfigApply = CommandHelp{
+		Command:     "config-apply",
+		Description: "Publish a directory of config files: <dir>/<dataId> goes to the default group and\n<dir>/<group>/<dataId> to that group. Only files that differ from their config in Nacos are published.\nWith --watch it keeps running and publishes each file shortly after it changes.",
+		Parameters: []string{
+			"dir                Directory of config files",
+			"--group            Group of the files directly in <dir> (default: defaults.group from the config file)",
+			"--watch            Keep running and publish each file shortly after it changes",
+			"--interval         With --watch, also compare every file with Nacos this often, to find configs changed there",
+			"--overwrite-remote With --interval, publish a file again when its config was changed in Nacos, instead of warning",
+		},
+		Examples: []string{
+			"# Publish the configs of a repository checkout",
+			"config-apply ./configs",
+			"",
+			"# Keep publishing local edits, and undo edits made in Nacos every 5 minutes",
+			"config-apply ./configs --watch --interval 5m --overwrite-remote",
+			"",
+			"Note:",
+			"  - Hidden files and editor swap or backup files are skipped",
+			"  - Removing a file leaves its config in Nacos",
+			"  - With --watch, runs until Ctrl+C",
+		},
 	}
 
 	AgentSpecList = CommandHelp{
@@ -222,11 +686,11 @@ var (
 
 // FormatForCLI formats help content for CLI mode (Cobra Long description)
 func (h *CommandHelp) FormatForCLI(cliPrefix string) string {
-	result := h.Description + "\n\nParameters:\n"
+	result := i18n.T(h.Description) + "\n\n" + i18n.T("Parameters:") + "\n"
 	for _, param := range h.Parameters {
 		result += "  " + param + "\n"
 	}
-	result += "\nExamples:\n"
+	result += "\n" + i18n.T("Examples:") + "\n"
 	for _, example := range h.Examples {
 		if example == "" {
 			result += "\n"
@@ -235,7 +699,7 @@ func (h *CommandHelp) FormatForCLI(cliPrefix string) string {
 			if example[0] != '#' && example[0] != ' ' && example != "Note:" {
 				result += "  " + cliPrefix + " " + example + "\n"
 			} else {
-				result += "  " + example + "\n"
+				result += "  " + localize(example) + "\n"
 			}
 		}
 	}
@@ -244,21 +708,35 @@ func (h *CommandHelp) FormatForCLI(cliPrefix string) string {
 
 // FormatForTerminal formats help content for terminal mode with colors
 func (h *CommandHelp) FormatForTerminal() {
-	fmt.Printf("\033[1;36mCommand: %s\033[0m\n", h.Command)
-	fmt.Printf("\n%s\n\n", h.Description)
-	fmt.Println("\033[33mParameters:\033[0m")
+	fmt.Printf("\033[1;36m%s\033[0m\n", i18n.T("Command: %s", h.Command))
+	fmt.Printf("\n%s\n\n", i18n.T(h.Description))
+	fmt.Printf("\033[33m%s\033[0m\n", i18n.T("Parameters:"))
 	for _, param := range h.Parameters {
 		fmt.Printf("  %s\n", param)
 	}
 	fmt.Println()
-	fmt.Println("\033[33mExamples:\033[0m")
+	fmt.Printf("\033[33m%s\033[0m\n", i18n.T("Examples:"))
 	for _, example := range h.Examples {
 		if example == "" {
 			fmt.Println()
 		} else if strings.HasPrefix(example, "Note:") || strings.HasPrefix(example, "  -") {
-			fmt.Printf("\033[33m%s\033[0m\n", example)
+			fmt.Printf("\033[33m%s\033[0m\n", localize(example))
 		} else {
-			fmt.Printf("  %s\n", example)
+			fmt.Printf("  %s\n", localize(example))
+		}
+	}
+}
+
+// localize translates the comments and notes among the example lines; the
+// commands themselves are left as they are
+func localize(line string) string {
+	if line == "Note:" {
+		return i18n.T(line)
+	}
+	for _, prefix := range []string{"# ", "  - "} {
+		if rest, ok := strings.CutPrefix(line, prefix); ok {
+			return prefix + i18n.T(rest)
 		}
 	}
+	return line
 }
@@ -0,0 +1,52 @@
+package help
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+)
+
+var all = []CommandHelp{
+	SkillList, SkillGet, SkillPublish, SkillExport, SkillVerify, SkillMigrate, ConfigList, ConfigGet, ConfigSet, ConfigEdit, ConfigLint,
+	SkillSync, ConfigSync, ConfigApply, ConfigExists, ConfigWait, GroupList, ConfigGroupDelete, ConfigEffective, AgentSpecList, AgentSpecGet, AgentSpecPublish,
+}
+
+func TestDescriptionsTranslated(t *testing.T) {
+	i18n.Set(i18n.Chinese)
+	defer i18n.Set(i18n.English)
+	for _, h := range all {
+		if i18n.T(h.Description) == h.Description {
+			t.Errorf("%s: description has no Chinese translation", h.Command)
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	i18n.Set(i18n.Chinese)
+	defer i18n.Set(i18n.English)
+	tests := []struct {
+		line string
+		want string
+	}{
+		{"# Search by name", "# 按名称搜索"},
+		{"Note:", "注意："},
+		{"  - Runs until Ctrl+C", "  - 持续运行，直到按下 Ctrl+C"},
+		{"skill-list --name \"creator\"", "skill-list --name \"creator\""},
+		{"# No translation yet", "# No translation yet"},
+	}
+	for _, tt := range tests {
+		if got := localize(tt.line); got != tt.want {
+			t.Errorf("localize(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestFormatForCLI(t *testing.T) {
+	got := SkillList.FormatForCLI("nacos-cli")
+	for _, want := range []string{"Parameters:\n", "  # List all skills\n", "  nacos-cli skill-list\n"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("FormatForCLI() does not contain %q:\n%s", want, got)
+		}
+	}
+}
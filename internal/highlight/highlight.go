@@ -0,0 +1,294 @@
+// Package highlight colorizes config content for display on a terminal. It is
+// only used for display: callers write the original content unchanged when
+// output is redirected or saved to a file.
+package highlight
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// Content types understood by Format
+const (
+	TypeJSON       = "json"
+	TypeYAML       = "yaml"
+	TypeProperties = "properties"
+	TypeText       = "text"
+)
+
+const (
+	colorKey     = "\033[36m"
+	colorString  = "\033[32m"
+	colorNumber  = "\033[33m"
+	colorLiteral = "\033[35m"
+	colorComment = "\033[90m"
+	colorReset   = "\033[0m"
+)
+
+// Enabled reports whether output written to f should be colorized: f is a
+// terminal, NO_COLOR is not set and TERM is not "dumb"
+func Enabled(f *os.File) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// DetectType returns the content type of a config: the type reported by the
+// server, else the dataId extension, else a guess from the content. Nacos
+// reports "text" for configs published without a type, so it is not trusted.
+func DetectType(dataID, declared, content string) string {
+	if typ := knownType(declared); typ != "" {
+		return typ
+	}
+	if typ := knownType(strings.TrimPrefix(filepath.Ext(dataID), ".")); typ != "" {
+		return typ
+	}
+	return sniffType(content)
+}
+
+func knownType(name string) string {
+	switch strings.ToLower(name) {
+	case "json":
+		return TypeJSON
+	case "yaml", "yml":
+		return TypeYAML
+	case "properties":
+		return TypeProperties
+	case "xml", "html":
+		return TypeText
+	}
+	return ""
+}
+
+var (
+	propertiesLine = regexp.MustCompile(`^[\w.\-]+\s*=`)
+	yamlLine       = regexp.MustCompile(`^(-\s|[\w.\-"']+\s*:(\s|$))`)
+)
+
+// sniffType guesses the type from the first line that is not blank or a comment
+func sniffType(content string) string {
+	trimmed := strings.TrimSpace(content)
+	if (strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[")) && json.Valid([]byte(trimmed)) {
+		return TypeJSON
+	}
+	for _, line := range strings.Split(trimmed, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || line == "---" {
+			continue
+		}
+		switch {
+		case propertiesLine.MatchString(line):
+			return TypeProperties
+		case yamlLine.MatchString(line):
+			return TypeYAML
+		}
+		break
+	}
+	return TypeText
+}
+
+// Format colorizes content of the given type. JSON is pretty-printed unless
+// compact is set, in which case the original bytes are kept. Other types only
+// gain color codes; content of an unknown type is returned unchanged.
+func Format(content, typ string, compact bool) string {
+	switch typ {
+	case TypeJSON:
+		if !compact {
+			var buf bytes.Buffer
+			if err := json.Indent(&buf, []byte(content), "", "  "); err == nil {
+				content = buf.String()
+			}
+		}
+		return highlightJSON(content)
+	case TypeYAML:
+		return highlightLines(content, highlightYAMLLine)
+	case TypeProperties:
+		return highlightProperties(content)
+	}
+	return content
+}
+
+func highlightLines(content string, fn func(string) string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		lines[i] = fn(line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func highlightJSON(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == '"':
+			end := jsonStringEnd(s, i)
+			color := colorString
+			if followedByColon(s[end:]) {
+				color = colorKey
+			}
+			b.WriteString(color + s[i:end] + colorReset)
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(s) && strings.IndexByte("0123456789.eE+-", s[end]) >= 0 {
+				end++
+			}
+			b.WriteString(colorNumber + s[i:end] + colorReset)
+			i = end
+		default:
+			literal := jsonLiteral(s[i:])
+			if literal == "" {
+				b.WriteByte(c)
+				i++
+				continue
+			}
+			b.WriteString(colorLiteral + literal + colorReset)
+			i += len(literal)
+		}
+	}
+	return b.String()
+}
+
+// jsonStringEnd returns the index just past the string starting at s[start]
+func jsonStringEnd(s string, start int) int {
+	for i := start + 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(s)
+}
+
+func followedByColon(s string) bool {
+	return strings.HasPrefix(strings.TrimLeft(s, " \t\r\n"), ":")
+}
+
+func jsonLiteral(s string) string {
+	for _, literal := range []string{"true", "false", "null"} {
+		if strings.HasPrefix(s, literal) {
+			return literal
+		}
+	}
+	return ""
+}
+
+var yamlKey = regexp.MustCompile(`^(\s*(?:-\s+)?)("[^"]*"|'[^']*'|[^\s#'"\-][^#]*?|-[^\s#][^#]*?)(\s*:)(\s|$)`)
+
+func highlightYAMLLine(line string) string {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || trimmed == "---" || trimmed == "..." {
+		return line
+	}
+	if strings.HasPrefix(trimmed, "#") {
+		return colorComment + line + colorReset
+	}
+	if m := yamlKey.FindStringSubmatchIndex(line); m != nil {
+		return line[:m[3]] + colorKey + line[m[4]:m[5]] + colorReset + line[m[6]:m[7]] + highlightValue(line[m[7]:])
+	}
+	if indent := len(line) - len(strings.TrimLeft(line, " \t")); strings.HasPrefix(trimmed, "- ") {
+		return line[:indent+2] + highlightValue(line[indent+2:])
+	}
+	return line
+}
+
+func highlightProperties(content string) string {
+	continued := false
+	return highlightLines(content, func(line string) string {
+		trimmed := strings.TrimSpace(line)
+		wasContinued := continued
+		continued = strings.HasSuffix(line, `\`) && !strings.HasSuffix(line, `\\`)
+		switch {
+		case trimmed == "":
+			return line
+		case wasContinued:
+			return colorString + line + colorReset
+		case strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "!"):
+			continued = false
+			return colorComment + line + colorReset
+		}
+
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+		end := indent
+		for end < len(line) && strings.IndexByte("=: \t", line[end]) < 0 {
+			if line[end] == '\\' {
+				end++
+			}
+			end++
+		}
+		if end > len(line) {
+			end = len(line)
+		}
+		sep := end
+		for sep < len(line) && (line[sep] == ' ' || line[sep] == '\t') {
+			sep++
+		}
+		if sep < len(line) && (line[sep] == '=' || line[sep] == ':') {
+			sep++
+		}
+		return line[:indent] + colorKey + line[indent:end] + colorReset + line[end:sep] + scalar(line[sep:])
+	})
+}
+
+// highlightValue colors a YAML value and its trailing comment
+func highlightValue(v string) string {
+	value, comment := splitComment(v)
+	if comment != "" {
+		comment = colorComment + comment + colorReset
+	}
+	return scalar(value) + comment
+}
+
+// splitComment splits a trailing " # comment" that is not inside quotes
+func splitComment(v string) (string, string) {
+	var quote byte
+	for i := 0; i < len(v); i++ {
+		switch c := v[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || v[i-1] == ' ' || v[i-1] == '\t'):
+			return v[:i], v[i:]
+		}
+	}
+	return v, ""
+}
+
+// scalar colors a value, keeping the whitespace around it
+func scalar(v string) string {
+	core := strings.TrimSpace(v)
+	color := scalarColor(core)
+	if color == "" {
+		return v
+	}
+	start := strings.Index(v, core)
+	return v[:start] + color + core + colorReset + v[start+len(core):]
+}
+
+func scalarColor(v string) string {
+	if v == "" || strings.IndexByte("|>{[&*!", v[0]) >= 0 {
+		return ""
+	}
+	switch strings.ToLower(v) {
+	case "true", "false", "null", "~", "yes", "no", "on", "off":
+		return colorLiteral
+	}
+	if _, err := strconv.ParseFloat(v, 64); err == nil {
+		return colorNumber
+	}
+	return colorString
+}
@@ -0,0 +1,89 @@
+package highlight
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var ansi = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		dataID, declared, content string
+		want                      string
+	}{
+		{"app", "json", "a: 1", TypeJSON},
+		{"app.yml", "text", "{}", TypeYAML},
+		{"app.properties", "", "a: 1", TypeProperties},
+		{"app", "", `{"a": 1}`, TypeJSON},
+		{"app", "text", "# comment\n\nserver:\n  port: 8080", TypeYAML},
+		{"app", "", "- one\n- two", TypeYAML},
+		{"app", "", "# db\ndb.url=jdbc:mysql://localhost", TypeProperties},
+		{"app", "", "hello world", TypeText},
+		{"app", "", "{not json", TypeText},
+		{"page.html", "", "a: 1", TypeText},
+	}
+	for _, tt := range tests {
+		if got := DetectType(tt.dataID, tt.declared, tt.content); got != tt.want {
+			t.Errorf("DetectType(%q, %q, %q) = %q, want %q", tt.dataID, tt.declared, tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestFormatOnlyAddsColor(t *testing.T) {
+	tests := []struct {
+		typ, content string
+	}{
+		{TypeYAML, "# settings\nserver:\n  port: 8080 # http\n  url: \"http://a:80/#x\"\n  tags:\n    - a\n    - name: b\n  text: |\n    line: one\n"},
+		{TypeProperties, "# db\n! legacy\ndb.url=jdbc:mysql://h:3306\nkey\\ with\\ space : value\nlong = a,\\\n    b\nflag\n"},
+		{TypeJSON, `{"a": [1, -2.5e3, true, null], "b": "x\"y"}`},
+		{TypeText, "<xml a=\"1\"/>"},
+	}
+	for _, tt := range tests {
+		got := Format(tt.content, tt.typ, true)
+		if stripped := ansi.ReplaceAllString(got, ""); stripped != tt.content {
+			t.Errorf("Format(%s) changed content:\n got %q\nwant %q", tt.typ, stripped, tt.content)
+		}
+	}
+}
+
+func TestFormatColors(t *testing.T) {
+	yaml := Format("port: 8080 # http\nname: app\nenabled: true", TypeYAML, false)
+	for _, want := range []string{
+		colorKey + "port" + colorReset,
+		colorNumber + "8080" + colorReset,
+		colorComment + "# http" + colorReset,
+		colorString + "app" + colorReset,
+		colorLiteral + "true" + colorReset,
+	} {
+		if !strings.Contains(yaml, want) {
+			t.Errorf("yaml output %q does not contain %q", yaml, want)
+		}
+	}
+
+	props := Format("db.port=3306", TypeProperties, false)
+	if want := colorKey + "db.port" + colorReset + "=" + colorNumber + "3306" + colorReset; props != want {
+		t.Errorf("properties output = %q, want %q", props, want)
+	}
+}
+
+func TestFormatJSON(t *testing.T) {
+	content := `{"a":1,"b":["x"]}`
+
+	pretty := ansi.ReplaceAllString(Format(content, TypeJSON, false), "")
+	want := "{\n  \"a\": 1,\n  \"b\": [\n    \"x\"\n  ]\n}"
+	if pretty != want {
+		t.Errorf("pretty JSON = %q, want %q", pretty, want)
+	}
+
+	colored := Format(content, TypeJSON, true)
+	if !strings.Contains(colored, colorKey+`"a"`+colorReset) || !strings.Contains(colored, colorString+`"x"`+colorReset) {
+		t.Errorf("compact JSON = %q, want keys and strings colored", colored)
+	}
+
+	invalid := `{"a": `
+	if got := ansi.ReplaceAllString(Format(invalid, TypeJSON, false), ""); got != invalid {
+		t.Errorf("invalid JSON = %q, want it unchanged", got)
+	}
+}
@@ -0,0 +1,82 @@
+// Package i18n translates the messages nacos-cli shows to users. Messages are
+// written in English in the code and passed through T, which looks them up in
+// the catalog of the current language and falls back to the English text.
+package i18n
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+// Lang is a language messages can be shown in
+type Lang string
+
+const (
+	English Lang = "en"
+	Chinese Lang = "zh"
+)
+
+// catalogs maps a language to its translations, keyed by the English message
+var catalogs = map[Lang]map[string]string{
+	Chinese: zh,
+}
+
+var current atomic.Value // Lang
+
+func init() {
+	current.Store(English)
+}
+
+// Set changes the language of every message shown from now on
+func Set(lang Lang) {
+	current.Store(lang)
+}
+
+// Current returns the language messages are shown in
+func Current() Lang {
+	return current.Load().(Lang)
+}
+
+// Parse returns the language named by s: en or zh, or a locale such as
+// zh_CN.UTF-8 or en-US
+func Parse(s string) (Lang, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if i := strings.IndexAny(name, "_-.@"); i >= 0 {
+		name = name[:i]
+	}
+	switch name {
+	case "en", "c", "posix":
+		return English, nil
+	case "zh":
+		return Chinese, nil
+	}
+	return "", fmt.Errorf("unsupported language %q: use en or zh", s)
+}
+
+// FromEnv returns the language of the locale in LC_ALL, LC_MESSAGES or LANG,
+// the first one set, and English for any locale without a catalog
+func FromEnv() Lang {
+	for _, key := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if value := os.Getenv(key); value != "" {
+			if lang, err := Parse(value); err == nil {
+				return lang
+			}
+			return English
+		}
+	}
+	return English
+}
+
+// T returns msg in the current language, formatted with args like
+// fmt.Sprintf when any are given
+func T(msg string, args ...any) string {
+	if translated, ok := catalogs[Current()][msg]; ok {
+		msg = translated
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
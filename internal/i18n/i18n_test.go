@@ -0,0 +1,90 @@
+package i18n
+
+import (
+	"regexp"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Lang
+		wantErr bool
+	}{
+		{"en", English, false},
+		{"zh", Chinese, false},
+		{"ZH", Chinese, false},
+		{"zh_CN.UTF-8", Chinese, false},
+		{"zh-TW", Chinese, false},
+		{"en_US.UTF-8", English, false},
+		{"C", English, false},
+		{"POSIX", English, false},
+		{"fr_FR.UTF-8", "", true},
+		{"", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := Parse(tt.in)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Parse(%q) = %q, %v; want %q, error %v", tt.in, got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	tests := []struct {
+		name               string
+		lcAll, lcMsg, lang string
+		want               Lang
+	}{
+		{"nothing set", "", "", "", English},
+		{"LANG", "", "", "zh_CN.UTF-8", Chinese},
+		{"LC_MESSAGES over LANG", "", "en_US.UTF-8", "zh_CN.UTF-8", English},
+		{"LC_ALL over the others", "zh_CN.UTF-8", "en_US.UTF-8", "en_US.UTF-8", Chinese},
+		{"locale without a catalog", "", "", "fr_FR.UTF-8", English},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("LC_ALL", tt.lcAll)
+			t.Setenv("LC_MESSAGES", tt.lcMsg)
+			t.Setenv("LANG", tt.lang)
+			if got := FromEnv(); got != tt.want {
+				t.Errorf("FromEnv() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestT(t *testing.T) {
+	defer Set(English)
+
+	if got := T("Fetching %d skills...", 3); got != "Fetching 3 skills..." {
+		t.Errorf("T() in English = %q", got)
+	}
+	Set(Chinese)
+	if got := T("Fetching %d skills...", 3); got != "正在获取 3 个技能..." {
+		t.Errorf("T() in Chinese = %q", got)
+	}
+	if got := T("Overwrite?"); got != "是否覆盖？" {
+		t.Errorf("T() without args in Chinese = %q", got)
+	}
+	// Messages without a translation are shown in English
+	if got := T("no translation for %s", "this"); got != "no translation for this" {
+		t.Errorf("T() of an untranslated message = %q", got)
+	}
+}
+
+// verbs matches the fmt verbs of a message
+var verbs = regexp.MustCompile(`%[-+# 0]*[0-9]*(\.[0-9]+)?[a-zA-Z%]`)
+
+func TestTranslationsKeepVerbs(t *testing.T) {
+	for lang, catalog := range catalogs {
+		for msg, translated := range catalog {
+			if want, got := verbs.FindAllString(msg, -1), verbs.FindAllString(translated, -1); !slices.Equal(want, got) {
+				t.Errorf("%s translation of %q has verbs %v, want %v", lang, msg, got, want)
+			}
+		}
+	}
+}
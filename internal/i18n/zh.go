@@ -0,0 +1,284 @@
+package i18n
+
+// zh holds the Chinese translations, keyed by the English message
+var zh = map[string]string{
+	// Errors and warnings
+	"Error:":                     "错误：",
+	"Error: %v":                  "错误：%v",
+	"Warning: %s":                "警告：%s",
+	"Hint: %s":                   "提示：%s",
+	"Usage:":                     "用法：",
+	"Overwrite?":                 "是否覆盖？",
+	"Error: skill path required": "错误：需要指定技能路径",
+	"Error: Failed to load or create config: %v": "错误：无法加载或创建配置：%v",
+	"Warning: Failed to load config file: %v":    "警告：无法加载配置文件：%v",
+
+	// skill-get
+	"Fetching skill: %s...":                              "正在获取技能：%s...",
+	"Fetching %d skills...":                              "正在获取 %d 个技能...",
+	"Error: failed to download skill '%s': %v":           "错误：下载技能 '%s' 失败：%v",
+	"Skipped, nothing written":                           "已跳过，未写入任何文件",
+	"Skill downloaded successfully!":                     "技能下载成功！",
+	"Location: %s":                                       "位置：%s",
+	"Written: %d files, %d bytes%s":                      "已写入：%d 个文件，%d 字节%s",
+	"(%d unchanged)":                                     "（%d 个未变化）",
+	"Skipped: %s (%s)":                                   "已跳过：%s（%s）",
+	"========== Summary ==========":                      "========== 汇总 ==========",
+	"Total: %d | Success: %d | Skipped: %d | Failed: %d": "总计：%d | 成功：%d | 跳过：%d | 失败：%d",
+	"Failed skills: %s":                                  "失败的技能：%s",
+	"%s already exists: %d of %d files will be replaced": "%s 已存在：%d/%d 个文件将被替换",
+	", %d files not in the skill will be removed":        "，%d 个不属于该技能的文件将被删除",
+
+	// skill-publish
+	"Publishing skill: %s...":                     "正在发布技能：%s...",
+	"Skill published successfully!":               "技能发布成功！",
+	"No skills found (directories with SKILL.md)": "未找到技能（包含 SKILL.md 的目录）",
+	"Found %d skills:":                            "找到 %d 个技能：",
+	"[%d/%d] Publishing skill: %s":                "[%d/%d] 正在发布技能：%s",
+	"Publish failed: %v":                          "发布失败：%v",
+	"Publish successful!":                         "发布成功！",
+	"Batch Publish Complete":                      "批量发布完成",
+	"Success: %d":                                 "成功：%d",
+	"Failed: %d":                                  "失败：%d",
+	"Total: %d":                                   "总计：%d",
+	"Uniform ID: %s":                              "Uniform ID：%s",
+	"Tip: Use the Nacos console to review and go online, or use 'skill-list' to verify.": "提示：请在 Nacos 控制台审核并上线，或使用 'skill-list' 确认。",
+
+	// skill-export
+	"Exporting %d skills to %s...":             "正在导出 %d 个技能到 %s...",
+	"%s -> %s (%d files, %d bytes)":            "%s -> %s（%d 个文件，%d 字节）",
+	"Error: failed to export skill '%s': %s":   "错误：导出技能 '%s' 失败：%s",
+	"Exported: %d | Failed: %d | Manifest: %s": "已导出：%d | 失败：%d | 清单：%s",
+	"Exporting skill: %s...":                   "正在导出技能：%s...",
+	"Skill exported successfully!":             "技能导出成功！",
+	"File: %s":                                 "文件：%s",
+	"Version: %s":                              "版本：%s",
+	"Written: %d files, %d bytes":              "已写入：%d 个文件，%d 字节",
+	"SHA-256: %s":                              "SHA-256：%s",
+	"Tip: Use 'skill-publish %s' to import it into another cluster.": "提示：使用 'skill-publish %s' 将其导入另一个集群。",
+
+	// config-set
+	"Reading from stdin — press Ctrl+D to finish (or use -f)": "正在从标准输入读取——按 Ctrl+D 结束（或使用 -f）",
+
+	// Sync status
+	"Last poll cycle:":    "最近轮询：",
+	"%s (%s ago, pid %d)": "%s（%s 前，pid %d）",
+	"Last error:":         "最近错误：",
+	"%s%s (at %s)":        "%s%s（于 %s）",
+	"SKILL":               "技能",
+	"LAST EVENT":          "最近事件",
+	"LAST SYNCED":         "最近同步",
+	"DIR":                 "目录",
+	"never":               "从未",
+
+	// Terminal
+	"Server:":                    "服务器：",
+	"Namespace:":                 "命名空间：",
+	"Skills:":                    "技能目录：",
+	"User:":                      "用户：",
+	"%s (username/password)":     "%s（用户名/密码）",
+	"Auth:":                      "认证：",
+	"(use 'login' to retry)":     "（使用 'login' 重试）",
+	"logged in, token %s":        "已登录，令牌%s",
+	"Goodbye! Have a great day!": "再见！祝你愉快！",
+	"Server Information:":        "服务器信息：",
+	"Username:":                  "用户名：",
+	"Auth Type:":                 "认证方式：",
+	"Token:":                     "令牌：",
+	"Sync:":                      "同步：",
+	"No heartbeat (start skill-sync to write one)": "没有心跳（启动 skill-sync 后写入）",
+
+	// Terminal help table
+	"Available Commands:":             "可用命令：",
+	"Command":                         "命令",
+	"Description":                     "说明",
+	"Usage":                           "用法",
+	"Skill Management":                "技能管理",
+	"AgentSpec Management":            "AgentSpec 管理",
+	"Configuration Management":        "配置管理",
+	"Background Jobs":                 "后台任务",
+	"System":                          "系统",
+	"List all skills":                 "列出所有技能",
+	"Options: --name, --page, --size": "选项：--name, --page, --size",
+	"Download a skill into the skills directory":       "下载技能到技能目录",
+	"Save a skill to a zip without installing":         "将技能保存为 zip，不安装",
+	"Back up all skills with a manifest":               "备份所有技能，附带清单",
+	"Publish a skill from local":                       "从本地发布技能",
+	"Publish all skills in directory":                  "发布目录中的所有技能",
+	"Keep skills in sync (background job)":             "保持技能同步（后台任务）",
+	"List all agent specs":                             "列出所有 agent spec",
+	"Download an agent spec to ~/.agentspecs":          "下载 agent spec 到 ~/.agentspecs",
+	"Publish an agent spec from local":                 "从本地发布 agent spec",
+	"Publish all agent specs in directory":             "发布目录中的所有 agent spec",
+	"List all configurations":                          "列出所有配置",
+	"Options: --data-id, --group, --page, --size":      "选项：--data-id, --group, --page, --size",
+	"Get configuration content":                        "获取配置内容",
+	"Publish config (-f file or type content)":         "发布配置（-f 文件或直接输入内容）",
+	"Edit in $EDITOR, review diff, publish":            "在 $EDITOR 中编辑，确认差异后发布",
+	"Publish the clipboard after a preview":            "预览后发布剪贴板内容",
+	"Edit config in $EDITOR and publish":               "在 $EDITOR 中编辑配置并发布",
+	"List background jobs":                             "列出后台任务",
+	"Show recent output of a job":                      "显示任务的最近输出",
+	"Stop a background job":                            "停止后台任务",
+	"Show settings and where each came from":           "显示设置及其来源",
+	"Show server information":                          "显示服务器信息",
+	"Log in again (e.g. after the token expired)":      "重新登录（如令牌过期后）",
+	"Show current namespace":                           "显示当前命名空间",
+	"Switch to different namespace":                    "切换命名空间",
+	"Default group for config-get/set":                 "config-get/set 的默认分组",
+	"Re-fetch skill/config names for Tab":              "重新获取 Tab 补全用的技能/配置名",
+	"Show or change terminal settings":                 "查看或修改终端设置",
+	"Re-run a read-only command periodically":          "定期重新运行只读命令",
+	"List recent commands (!N re-runs one)":            "列出最近的命令（!N 重新运行）",
+	"List, add or remove command aliases":              "列出、添加或删除命令别名",
+	"Clear screen":                                     "清屏",
+	"Show this help message":                           "显示此帮助",
+	"Exit terminal":                                    "退出终端",
+	"Tip: Use Tab for auto-completion, ↑↓ for history": "提示：Tab 自动补全，↑↓ 浏览历史",
+	"Tip: Append '> file', '>> file' or '| command' to redirect a command's output": "提示：在命令后加 '> 文件'、'>> 文件' 或 '| 命令' 重定向输出",
+
+	// Command help
+	"Command: %s": "命令：%s",
+	"Parameters:": "参数：",
+	"Examples:":   "示例：",
+	"Note:":       "注意：",
+	"List all skills from Nacos configuration center.":                         "列出 Nacos 配置中心的所有技能。",
+	"Download a skill from Nacos to local directory via the Client Skill API.": "通过 Client Skill API 从 Nacos 下载技能到本地目录。",
+	"Publish a skill to Nacos by uploading it as a ZIP file (creates a draft version).\nReview and go-online operations should be done via the Nacos console.":                                                                        "以 ZIP 文件上传的方式将技能发布到 Nacos（创建草稿版本）。\n审核和上线请在 Nacos 控制台操作。",
+	"Export a skill from Nacos to a local zip without installing it.\nThe zip can be published to another cluster with skill-publish.":                                                                                                "将 Nacos 中的技能导出为本地 zip，不安装。\n该 zip 可用 skill-publish 发布到另一个集群。",
+	"Copy skills from one Nacos to another, e.g. between environments, and check each one arrived.\nThe servers come from two profiles. A state file records finished skills, so an interrupted\nmigration resumes where it stopped.": "将技能从一个 Nacos 复制到另一个（例如在环境之间），并检查每个技能是否完整到达。\n服务器取自两个 profile。状态文件记录已完成的技能，中断的迁移会从中断处继续。",
+	"Check installed skills against the hashes recorded when they were downloaded, to find files\nthat were changed, removed or added since, e.g. by local edits or a damaged disk.":                                                  "根据下载时记录的哈希检查已安装的技能，找出此后被修改、删除或新增的文件，\n例如本地编辑或磁盘损坏造成的变化。",
+	"Show every setting in effect (server, namespace, credentials, timeouts, defaults) with where\nits value came from: a flag, the config file, the environment or the built-in default.\nSecrets are masked.":                       "显示当前生效的所有设置（服务器、命名空间、凭据、超时、默认值）及其来源：\n命令行参数、配置文件、环境变量或内置默认值。敏感信息会被隐藏。",
+	"Check whether a configuration exists, for scripts: prints nothing and answers with the exit code.\nWith --md5 it also checks that the content is exactly what is about to be published.":                                         "检查配置是否存在，供脚本使用：不输出任何内容，以退出码作答。\n使用 --md5 时还会检查内容是否正是将要发布的内容。",
+	"Wait until a configuration changes, then print its new MD5 and exit: the step between a one-off\nconfig-get and config-sync, e.g. for a CI job waiting for an operator to flip a config.":                                        "等待配置变更，然后输出新的 MD5 并退出：介于一次性的 config-get 与 config-sync 之间，\n例如用于 CI 任务等待运维人员切换配置。",
+	"List all configurations from Nacos configuration center.": "列出 Nacos 配置中心的所有配置。",
+	"Get a specific configuration from Nacos.":                 "从 Nacos 获取指定配置。",
+	"Publish a configuration to Nacos (create or update).":     "发布配置到 Nacos（创建或更新）。",
+	"Check that config content parses as YAML, JSON, properties or XML, and report errors by line and column.\nDuplicate keys in YAML maps and properties files are errors too: parsers silently keep the last one.":                                                                                          "检查配置内容能否按 YAML、JSON、properties 或 XML 解析，并按行列报告错误。\nYAML 映射和 properties 文件中的重复键也视为错误：解析器会静默保留最后一个。",
+	"Edit a configuration in $VISUAL/$EDITOR (default: vi), review the diff and publish it.\nThe publish fails if someone else published the config since it was opened; your edit is then saved to a file.":                                                                                                  "在 $VISUAL/$EDITOR（默认 vi）中编辑配置，确认差异后发布。\n如果打开后配置已被他人发布，发布会失败，你的编辑会保存到文件中。",
+	"Download skills and keep them in sync: a skill is re-downloaded whenever it changes in Nacos.\nWith --push the direction is reversed: local skill directories are uploaded whenever their files change.\nIn the interactive terminal the sync runs as a background job (see 'jobs', 'logs' and 'stop').": "下载技能并保持同步：技能在 Nacos 中变更后会重新下载。\n使用 --push 时方向相反：本地技能目录的文件变更后会上传。\n在交互式终端中，同步作为后台任务运行（参见 'jobs'、'logs' 和 'stop'）。",
+	"Mirror configs to local files, for applications that only read files.\nEach config is written once, then its file is rewritten whenever the config changes in Nacos.\nFiles are replaced atomically (written to a temporary file and renamed), so readers never see a partial file.":                     "将配置镜像到本地文件，供只读取文件的应用使用。\n每个配置先写入一次，之后在 Nacos 中变更时重写对应文件。\n文件以原子方式替换（先写临时文件再重命名），读取方不会看到不完整的文件。",
+	"Publish a directory of config files: <dir>/<dataId> goes to the default group and\n<dir>/<group>/<dataId> to that group. Only files that differ from their config in Nacos are published.\nWith --watch it keeps running and publishes each file shortly after it changes.":                              "发布一个目录中的配置文件：<dir>/<dataId> 发布到默认分组，\n<dir>/<group>/<dataId> 发布到对应分组。只发布与 Nacos 中配置不同的文件。\n使用 --watch 时持续运行，文件变更后很快发布。",
+	"List the config groups of the namespace with how many configs each holds.\nNacos has no group API, so every config of the namespace is listed to find them.":                                                                                                                                             "列出命名空间中的配置分组及每个分组的配置数。\nNacos 没有分组 API，因此会列出命名空间的所有配置来统计。",
+	"Delete every config of a group, or only those whose data ID matches --data-id.\nThe configs are listed and the deletion has to be confirmed; a failed config does not stop the others.":                                                                                                                  "删除分组中的所有配置，或仅删除 data ID 匹配 --data-id 的配置。\n会先列出这些配置并要求确认；某个配置删除失败不会中止其余配置。",
+	"List all agent specs from Nacos configuration center.":                                                                                                          "列出 Nacos 配置中心的所有 agent spec。",
+	"Download an agent spec from Nacos to local directory via the Client AgentSpec API.":                                                                             "通过 Client AgentSpec API 从 Nacos 下载 agent spec 到本地目录。",
+	"Publish an agent spec to Nacos by uploading it as a ZIP file (creates a draft version).\nReview and go-online operations should be done via the Nacos console.": "以 ZIP 文件上传的方式将 agent spec 发布到 Nacos（创建草稿版本）。\n审核和上线请在 Nacos 控制台操作。",
+
+	// Comments and notes among the examples of the command help
+	"--include and --exclude follow path.Match: * matches any characters, ? one, [a-z] a class":                      "--include 和 --exclude 遵循 path.Match：* 匹配任意字符，? 匹配一个字符，[a-z] 匹配字符类",
+	"--query exits 1 when the path is not in the content; strings are printed without quotes":                        "路径不在内容中时 --query 以 1 退出；字符串输出时不带引号",
+	"--timeout here is the overall deadline, not the timeout of each request":                                        "此处的 --timeout 是总等待时限，而非每个请求的超时",
+	"A heartbeat is written to ~/.nacos-cli/sync-status.json after every poll cycle":                                 "每个轮询周期后向 ~/.nacos-cli/sync-status.json 写入心跳",
+	"A skill edited locally is not overwritten; a remote change is saved as <skill>.remote":                          "本地修改过的技能不会被覆盖；远程变更保存为 <skill>.remote",
+	"A skill taken off the --catalog is removed locally, as one deleted in Nacos; a deleted catalog changes nothing": "从 --catalog 中移除的技能会像在 Nacos 中被删除一样在本地删除；删除目录配置本身不会有任何影响",
+	"A mapped directory must be absolute and writable; 'skill-sync status' shows where each skill is":                "映射目录必须是可写的绝对路径；'skill-sync status' 会显示每个技能所在的位置",
+	"Put one skill under the agent's plugin directory, the others in ~/.skills":                                      "将一个技能放到 agent 的插件目录下，其余放在 ~/.skills 中",
+	"Restart a fleet without flooding Nacos: start within a minute, at most 2MB/s per host":                          "重启大量主机而不压垮 Nacos：在一分钟内错开启动，每台主机最多 2MB/s",
+	"The first sync downloads the smallest skills first, by their size when last synced":                             "首次同步按上次同步时的大小先下载最小的技能",
+	"After publishing, use the Nacos console to review and go online":                                                "发布后请在 Nacos 控制台审核并上线",
+	"Agent spec directory must contain manifest.json":                                                                "agent spec 目录必须包含 manifest.json",
+	"An update is logged with the files it added, modified and removed; hooks get the same in NACOS_CHANGES":         "更新会连同新增、修改和删除的文件一起记录到日志；钩子通过 NACOS_CHANGES 获得相同内容",
+	"As JSON, for scripts":                                                             "以 JSON 输出，供脚本使用",
+	"Back up every skill, with a manifest.json":                                        "备份所有技能，附带 manifest.json",
+	"Behind a gateway that closes idle connections after 20s":                          "位于 20 秒后关闭空闲连接的网关之后",
+	"Block a deploy until the release flag is flipped, for at most 5 minutes":          "在发布开关切换前阻塞部署，最多 5 分钟",
+	"CLI mode runs until Ctrl+C":                                                       "CLI 模式持续运行，直到按下 Ctrl+C",
+	"Check every installed skill":                                                      "检查所有已安装的技能",
+	"Check one skill":                                                                  "检查一个技能",
+	"Check a file before publishing it":                                                "发布前检查文件",
+	"Check the live content of a config":                                               "检查配置的线上内容",
+	"Check that a flag overrides the config file":                                      "确认命令行参数覆盖了配置文件",
+	"Combine filters with pagination":                                                  "组合过滤条件与分页",
+	"Copy every skill, replacing those that differ":                                    "复制所有技能，替换内容不同的技能",
+	"Create a config only if it is missing":                                            "仅在配置不存在时创建",
+	"Create a config in the editor":                                                    "在编辑器中创建配置",
+	"Delete a whole group after confirming":                                            "确认后删除整个分组",
+	"Delete only some configs of a group, without asking":                              "不询问，只删除分组中的部分配置",
+	"Deleted configs cannot be restored from the CLI; use --dry-run first":             "删除的配置无法通过 CLI 恢复；请先使用 --dry-run",
+	"Download a specific version":                                                      "下载指定版本",
+	"Download multiple agent specs":                                                    "下载多个 agent spec",
+	"Download multiple skills":                                                         "下载多个技能",
+	"Download the latest version of a skill":                                           "下载技能的最新版本",
+	"Download the latest version of an agent spec":                                     "下载 agent spec 的最新版本",
+	"Download to a custom directory":                                                   "下载到自定义目录",
+	"Download via label":                                                               "按标签下载",
+	"Each skill is downloaded again from the destination and compared with the source": "每个技能都会从目标端重新下载并与源端比较",
+	"Edit the remote content in your editor and publish after reviewing the diff":      "在编辑器中编辑远程内容，确认差异后发布",
+	"Edit a config and publish after reviewing the diff":                               "编辑配置，确认差异后发布",
+	"Export an inventory for a spreadsheet":                                            "导出清单以导入电子表格",
+	"Each item has name, status (published or failed), failed, error, durationMs and bytes (uploaded)":              "每一项包含 name、status（published 或 failed）、failed、error、durationMs 和 bytes（上传字节数）",
+	"Each item has name, status, failed (for failed and unverified skills), error, durationMs and bytes (uploaded)": "每一项包含 name、status、failed（失败或未验证的技能）、error、durationMs 和 bytes（上传字节数）",
+	"Exit codes: 0 changed, 4 deleted, 124 timed out, 3 login failed, 1 other errors":                               "退出码：0 已变更，4 已删除，124 超时，3 登录失败，1 其他错误",
+	"Exit codes: 0 exists (and matches --md5), 4 not found, 5 MD5 differs, 3 login failed, 1 other errors":          "退出码：0 存在（且与 --md5 一致），4 不存在，5 MD5 不一致，3 登录失败，1 其他错误",
+	"Exits 1 when the content has errors, printed as file:line:column: message":                                     "内容有错误时以 1 退出，错误以 文件:行:列: 消息 的形式输出",
+	"config-set runs the same check for dataIds ending in .yaml, .yml, .json, .properties or .xml":                  "config-set 对以 .yaml、.yml、.json、.properties 或 .xml 结尾的 dataId 执行相同检查",
+	"Content that does not parse as the config's type (from its type, else its dataId) can be edited again":         "内容无法按配置类型（取自配置的类型，否则取自 dataId）解析时可以重新编辑",
+	"--yes publishes without asking; invalid content then fails instead of reopening the editor":                    "--yes 不询问直接发布；此时无效内容会直接失败，而不会重新打开编辑器",
+	"Exits non-zero when any file differs or a skill cannot be verified":                                            "任一文件不一致或技能无法校验时以非零状态退出",
+	"Fail a health check when syncing stopped making progress":                                                      "同步停滞时让健康检查失败",
+	"Fetch every config matching wildcards, one after another":                                                      "获取所有匹配通配符的配置，逐个输出",
+	"Fetch several configs at once as a JSON map":                                                                   "一次获取多个配置，输出为 JSON 映射",
+	"Filter by data ID":         "按 data ID 过滤",
+	"Filter by group":           "按分组过滤",
+	"Get a configuration":       "获取配置",
+	"Get a skill configuration": "获取技能配置",
+	"Hidden files and editor swap or backup files are skipped":                                 "跳过隐藏文件以及编辑器的交换文件和备份文件",
+	"Hooks get NACOS_SKILL_NAME, NACOS_EVENT (updated, deleted or error) and NACOS_SKILL_PATH": "钩子可获得 NACOS_SKILL_NAME、NACOS_EVENT（updated、deleted 或 error）和 NACOS_SKILL_PATH",
+	"Import an export into another cluster":                                                    "将导出的技能导入另一个集群",
+	"In automation, publish a large binary certificate bundle on purpose":                      "在自动化流程中有意发布较大的二进制证书包",
+	"In CI, fail instead of waiting when nothing is piped in":                                  "在 CI 中，没有管道输入时报错而不是一直等待",
+	"In the terminal, 'settings' shows the same, with the namespace and group of the session":  "在终端中，'settings' 显示相同内容，并包含当前会话的命名空间和分组",
+	"Keep a JSON log for post-mortems":                                                         "保留 JSON 日志以便事后排查",
+	"Keep a report for CI, and fail the build if any skill failed":                             "为 CI 保留报告，有技能失败时让构建失败",
+	"Keep all skills in sync in the background":                                                "在后台保持所有技能同步",
+	"Keep publishing local edits, and undo edits made in Nacos every 5 minutes":                "持续发布本地修改，并每 5 分钟撤销在 Nacos 中所做的修改",
+	"List all groups": "列出所有分组",
+	"Mapping file":    "映射文件",
+	"Mirror one config and reload the app after each change":           "镜像一个配置，每次变更后重新加载应用",
+	"Mirror the configs listed in a mapping file":                      "镜像映射文件中列出的配置",
+	"Print one value for a script, or a section as JSON":               "为脚本输出单个值，或以 JSON 输出一个片段",
+	"Promote two skills from staging to production":                    "将两个技能从预发环境推广到生产环境",
+	"Publish JSON config":                                              "发布 JSON 配置",
+	"Publish a pre-built zip file":                                     "发布预先打好的 zip 文件",
+	"Publish a release, shown by 'skill-list --detail'":                "发布正式版本，可在 'skill-list --detail' 中看到",
+	"Publish a single agent spec":                                      "发布单个 agent spec",
+	"Publish a single skill":                                           "发布单个技能",
+	"Publish all agent specs in a directory":                           "发布目录中的所有 agent spec",
+	"Publish all skills in a directory":                                "发布目录中的所有技能",
+	"Publish from an artifact store, verifying the checksum":           "从制品库发布并校验校验和",
+	"Publish from file":                                                "从文件发布",
+	"Publish from stdin":                                               "从标准输入发布",
+	"Publish the configs of a repository checkout":                     "发布代码仓库检出目录中的配置",
+	"Publish what you copied, after checking its first and last lines": "核对首尾几行后发布剪贴板中复制的内容",
+	"Push every skill under a folder":                                  "推送目录下的所有技能",
+	"Push local edits to Nacos while authoring a skill":                "编写技能时将本地修改推送到 Nacos",
+	"Read dataId:group pairs from stdin and write them to files":       "从标准输入读取 dataId:group 并写入文件",
+	"Relative paths in a mapping file are relative to the file":        "映射文件中的相对路径相对于该文件",
+	"Reload commands get NACOS_DATA_ID, NACOS_GROUP, NACOS_CONFIG_PATH and NACOS_EVENT (updated or deleted)":      "重新加载命令可获得 NACOS_DATA_ID、NACOS_GROUP、NACOS_CONFIG_PATH 和 NACOS_EVENT（updated 或 deleted）",
+	"Removing a file leaves its config in Nacos":                                                                  "删除文件不会删除 Nacos 中的配置",
+	"Replace a skill that was downloaded before":                                                                  "替换之前下载的技能",
+	"Report fields: version (1), command, startedAt, durationMs, total, failed, counts (by status), bytes, items": "报告字段：version（1）、command、startedAt、durationMs、total、failed、counts（按状态计数）、bytes、items",
+	"Run the same command again to retry the skills that failed or could not be verified":                         "再次运行相同命令即可重试失败或未能校验的技能",
+	"Runs until Ctrl+C":                                    "持续运行，直到按下 Ctrl+C",
+	"Save the content to a file exactly as stored":         "按原样将内容保存到文件",
+	"Search by name":                                       "按名称搜索",
+	"See what would be deleted":                            "查看将被删除的配置",
+	"Show versions and tags, or only skills tagged search": "显示版本和标签，或只显示带 search 标签的技能",
+	"Skill directory must contain SKILL.md":                "技能目录必须包含 SKILL.md",
+	"Skills installed before skill-verify existed have no hashes; install them again with skill-get": "skill-verify 出现之前安装的技能没有哈希记录，请用 skill-get 重新安装",
+	"Skills uploaded through the skill API are drafts until they are put online in the console":      "通过技能 API 上传的技能在控制台上线前为草稿",
+	"Skip a publish when the live config is already the file":                                        "线上配置已与文件一致时跳过发布",
+	"Snapshot a released version to a chosen file":                                                   "将已发布版本快照到指定文件",
+	"Snapshot a skill":                      "快照一个技能",
+	"Sync a single skill":                   "同步单个技能",
+	"Sync all skills to a custom directory": "将所有技能同步到自定义目录",
+	"Sync the skills a catalog config lists, picking up skills added to it": "同步目录配置中列出的技能，并跟进新加入的技能",
+	"Sync the team's skills except the experimental ones":                   "同步团队的技能，实验性技能除外",
+	"Tell the local agent to reload its skills after each change":           "每次变更后通知本地 agent 重新加载技能",
+	"Terminal mode starts a background job and returns to the prompt":       "终端模式启动后台任务并返回提示符",
+	"Wait for a change since a known version, even if it already happened":  "等待自某个已知版本以来的变更，即使变更已经发生",
+	"Which server and namespace would the dev profile use, and why":         "dev profile 会使用哪个服务器和命名空间，以及原因",
+	"With --watch, runs until Ctrl+C":                                       "使用 --watch 时持续运行，直到按下 Ctrl+C",
+	"With pagination":                                                       "分页",
+}
@@ -0,0 +1,114 @@
+package kms
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// The example of the Aliyun RPC signature documentation
+	params := url.Values{
+		"AccessKeyId":      {"testid"},
+		"Action":           {"DescribeRegions"},
+		"Format":           {"XML"},
+		"SignatureMethod":  {"HMAC-SHA1"},
+		"SignatureNonce":   {"3ee8c1b8-83d3-44af-a94f-4e0ad82fd6cf"},
+		"SignatureVersion": {"1.0"},
+		"Timestamp":        {"2016-02-23T12:46:24Z"},
+		"Version":          {"2014-05-26"},
+	}
+	if got, want := sign(http.MethodGet, params, "testsecret"), "OLeaidS1JvxuMvnyHOwuJ+uX5qY="; got != want {
+		t.Errorf("sign() = %q, want %q", got, want)
+	}
+}
+
+func TestPercentEncode(t *testing.T) {
+	tests := map[string]string{
+		"a b":     "a%20b",
+		"a*b":     "a%2Ab",
+		"a~b":     "a~b",
+		"k=v&x/y": "k%3Dv%26x%2Fy",
+	}
+	for in, want := range tests {
+		if got := percentEncode(in); got != want {
+			t.Errorf("percentEncode(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+// newKMSServer returns a fake KMS that "encrypts" by prefixing enc: and checks
+// the signature of every request
+func newKMSServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("Signature") != sign(http.MethodPost, r.PostForm, "sk") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"Code":"SignatureDoesNotMatch","Message":"bad signature"}`)
+			return
+		}
+		switch r.PostForm.Get("Action") {
+		case "Encrypt":
+			fmt.Fprintf(w, `{"CiphertextBlob":%q,"KeyId":%q}`, "enc:"+r.PostForm.Get("Plaintext"), r.PostForm.Get("KeyId"))
+		case "Decrypt":
+			blob := r.PostForm.Get("CiphertextBlob")
+			if !strings.HasPrefix(blob, "enc:") {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `{"Code":"InvalidCiphertext","Message":"the ciphertext is invalid"}`)
+				return
+			}
+			fmt.Fprintf(w, `{"Plaintext":%q}`, strings.TrimPrefix(blob, "enc:"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	server := newKMSServer(t)
+	c, err := NewClient(server.URL, "key-1", "ak", "sk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.now = func() time.Time { return time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC) }
+
+	ciphertext, err := c.Encrypt("password: s3cret & more")
+	if err != nil || ciphertext != "enc:password: s3cret & more" {
+		t.Fatalf("Encrypt() = %q, %v", ciphertext, err)
+	}
+	plaintext, err := c.Decrypt(ciphertext)
+	if err != nil || plaintext != "password: s3cret & more" {
+		t.Errorf("Decrypt() = %q, %v", plaintext, err)
+	}
+
+	_, err = c.Decrypt("not a blob")
+	if err == nil || !strings.Contains(err.Error(), "InvalidCiphertext") {
+		t.Errorf("Decrypt() of an invalid blob error = %v, want the KMS error code", err)
+	}
+
+	wrongKey, _ := NewClient(server.URL, "key-1", "ak", "wrong")
+	if _, err := wrongKey.Decrypt(ciphertext); err == nil || !strings.Contains(err.Error(), "SignatureDoesNotMatch") {
+		t.Errorf("Decrypt() with a wrong secret error = %v", err)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	c, err := NewClient("kms.cn-hangzhou.aliyuncs.com", "", "ak", "sk")
+	if err != nil || c.endpoint != "https://kms.cn-hangzhou.aliyuncs.com" {
+		t.Errorf("NewClient() endpoint = %q, %v", c.endpoint, err)
+	}
+	if _, err := c.Encrypt("x"); err == nil || !strings.Contains(err.Error(), "no KMS key ID") {
+		t.Errorf("Encrypt() without a key ID error = %v", err)
+	}
+	if _, err := NewClient("kms.cn-hangzhou.aliyuncs.com", "", "", ""); err == nil {
+		t.Error("NewClient() without credentials succeeded")
+	}
+}
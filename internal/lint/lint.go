@@ -0,0 +1,330 @@
+// Package lint checks that config content parses as its type before it is
+// published, since invalid YAML or JSON in Nacos only fails once consumers
+// read it. Beyond syntax it reports duplicate keys in YAML maps and
+// properties files, which parsers accept and silently resolve to the last one.
+package lint
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Types Check understands
+const (
+	TypeJSON       = "json"
+	TypeYAML       = "yaml"
+	TypeProperties = "properties"
+	TypeXML        = "xml"
+)
+
+// Types lists the values --type accepts, for messages
+const Types = "yaml, json, properties or xml"
+
+// Problem is an error in content at a 1-based line and column; 0 means the
+// position is not known
+type Problem struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (p Problem) String() string {
+	switch {
+	case p.Line > 0 && p.Column > 0:
+		return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+	case p.Line > 0:
+		return fmt.Sprintf("%d: %s", p.Line, p.Message)
+	}
+	return p.Message
+}
+
+// In formats p as name:line:column: message, the way compilers report errors
+func (p Problem) In(name string) string {
+	if p.Line == 0 {
+		return name + ": " + p.Message
+	}
+	return name + ":" + p.String()
+}
+
+// ParseType returns the type named by --type, accepting yml for yaml
+func ParseType(name string) (string, error) {
+	if typ := knownType(name); typ != "" {
+		return typ, nil
+	}
+	return "", fmt.Errorf("unknown type %q: expected %s", name, Types)
+}
+
+// DetectType returns the type content is checked as: declared if it names a
+// type, else the extension of name (a dataId or file name). It returns ""
+// for content of other types, which is not checked.
+func DetectType(name, declared string) string {
+	if typ := knownType(declared); typ != "" {
+		return typ
+	}
+	return knownType(strings.TrimPrefix(filepath.Ext(name), "."))
+}
+
+func knownType(name string) string {
+	switch strings.ToLower(name) {
+	case "json":
+		return TypeJSON
+	case "yaml", "yml":
+		return TypeYAML
+	case "properties":
+		return TypeProperties
+	case "xml":
+		return TypeXML
+	}
+	return ""
+}
+
+// Check parses content as typ and returns the problems found, in the order
+// of their position. Content of an unknown type has none.
+func Check(typ, content string) []Problem {
+	switch typ {
+	case TypeJSON:
+		return checkJSON(content)
+	case TypeYAML:
+		return checkYAML(content)
+	case TypeProperties:
+		return checkProperties(content)
+	case TypeXML:
+		return checkXML(content)
+	}
+	return nil
+}
+
+func checkJSON(content string) []Problem {
+	var v any
+	err := json.Unmarshal([]byte(content), &v)
+	if err == nil {
+		return nil
+	}
+	var syntax *json.SyntaxError
+	if errors.As(err, &syntax) {
+		// Offset counts the bytes read, including the one that failed
+		line, column := position(content, int(syntax.Offset)-1)
+		return []Problem{{Line: line, Column: column, Message: syntax.Error()}}
+	}
+	return []Problem{{Message: err.Error()}}
+}
+
+// yamlLine finds the line in the errors of the YAML parser, such as
+// "yaml: line 3: mapping values are not allowed in this context"
+var yamlLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+func checkYAML(content string) []Problem {
+	var problems []Problem
+	dec := yaml.NewDecoder(strings.NewReader(content))
+	for {
+		var doc yaml.Node
+		err := dec.Decode(&doc)
+		if err == io.EOF {
+			return problems
+		}
+		if err != nil {
+			p := Problem{Message: strings.TrimPrefix(err.Error(), "yaml: ")}
+			if m := yamlLine.FindStringSubmatch(err.Error()); m != nil {
+				p.Line, _ = strconv.Atoi(m[1])
+				p.Message = m[2]
+			}
+			// The parser cannot go on after an error
+			return append(problems, p)
+		}
+		problems = append(problems, duplicateYAMLKeys(&doc)...)
+	}
+}
+
+// duplicateYAMLKeys returns a problem for each key of a map in n that an
+// earlier key of the same map already defines. Aliases are not followed, so
+// their keys are reported where the anchor is.
+func duplicateYAMLKeys(n *yaml.Node) []Problem {
+	var problems []Problem
+	if n.Kind == yaml.MappingNode {
+		seen := make(map[string]*yaml.Node)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			if key.Kind != yaml.ScalarNode || key.ShortTag() == "!!merge" {
+				continue
+			}
+			// 1 and "1" are different keys
+			id := key.ShortTag() + " " + key.Value
+			if first, ok := seen[id]; ok {
+				problems = append(problems, Problem{
+					Line: key.Line, Column: key.Column,
+					Message: fmt.Sprintf("duplicate key %q, first defined at line %d", key.Value, first.Line),
+				})
+				continue
+			}
+			seen[id] = key
+		}
+	}
+	for _, child := range n.Content {
+		problems = append(problems, duplicateYAMLKeys(child)...)
+	}
+	return problems
+}
+
+// checkProperties reads content the way java.util.Properties does: a line
+// ending in an odd number of backslashes continues on the next one, # and !
+// start comments, and the key ends at the first unescaped =, : or blank.
+func checkProperties(content string) []Problem {
+	var problems []Problem
+	firstLine := make(map[string]int)
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		start := i + 1
+		line := strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+		indent := len(strings.TrimSuffix(lines[i], "\r")) - len(line)
+		for continues(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(strings.TrimSuffix(lines[i], "\r"), " \t\f")
+		}
+		rawKey, rawValue := splitProperty(line)
+		key, err := unescapeProperty(rawKey)
+		if err == nil {
+			_, err = unescapeProperty(rawValue)
+		}
+		if err != nil {
+			problems = append(problems, Problem{Line: start, Message: err.Error()})
+			continue
+		}
+		if first, ok := firstLine[key]; ok {
+			problems = append(problems, Problem{
+				Line: start, Column: indent + 1,
+				Message: fmt.Sprintf("duplicate key %q, first defined at line %d", key, first),
+			})
+			continue
+		}
+		firstLine[key] = start
+	}
+	return problems
+}
+
+// continues reports whether a properties line ends in an odd number of
+// backslashes, so that it continues on the next line
+func continues(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// splitProperty splits a logical properties line into its key and value, both
+// still escaped
+func splitProperty(line string) (string, string) {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '=', ':':
+			return line[:i], strings.TrimLeft(line[i+1:], " \t\f")
+		case ' ', '\t', '\f':
+			rest := strings.TrimLeft(line[i:], " \t\f")
+			if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+				rest = strings.TrimLeft(rest[1:], " \t\f")
+			}
+			return line[:i], rest
+		}
+	}
+	return line, ""
+}
+
+// unescapeProperty resolves the escapes of a properties key or value; a
+// \u not followed by four hex digits is an error, as in Java
+func unescapeProperty(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", fmt.Errorf(`malformed \uxxxx escape %q`, s[i-1:])
+			}
+			r, err := strconv.ParseUint(s[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf(`malformed \uxxxx escape %q`, s[i-1:i+5])
+			}
+			b.WriteRune(rune(r))
+			i += 4
+		default:
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String(), nil
+}
+
+func checkXML(content string) []Problem {
+	dec := xml.NewDecoder(strings.NewReader(content))
+	depth, roots := 0, 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var syntax *xml.SyntaxError
+			if errors.As(err, &syntax) {
+				return []Problem{{Line: syntax.Line, Message: syntax.Msg}}
+			}
+			return []Problem{{Message: err.Error()}}
+		}
+		switch tok.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				roots++
+				if roots == 2 {
+					line, column := dec.InputPos()
+					return []Problem{{Line: line, Column: column, Message: "more than one root element"}}
+				}
+			}
+			depth++
+		case xml.EndElement:
+			depth--
+		}
+	}
+	if roots == 0 {
+		return []Problem{{Message: "no root element"}}
+	}
+	return nil
+}
+
+// position returns the 1-based line and column (in characters) of the byte at
+// offset in content
+func position(content string, offset int) (int, int) {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > len(content) {
+		offset = len(content)
+	}
+	before := content[:offset]
+	lineStart := strings.LastIndexByte(before, '\n') + 1
+	return strings.Count(before, "\n") + 1, utf8.RuneCountInString(before[lineStart:]) + 1
+}
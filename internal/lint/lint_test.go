@@ -0,0 +1,97 @@
+package lint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		name, declared, want string
+	}{
+		{"app.yaml", "", TypeYAML},
+		{"app.yml", "", TypeYAML},
+		{"app.JSON", "", TypeJSON},
+		{"db.properties", "", TypeProperties},
+		{"beans.xml", "", TypeXML},
+		{"app", "", ""},
+		{"notes.txt", "", ""},
+		{"app", "yml", TypeYAML},
+		{"app.yaml", "json", TypeJSON},
+		{"app.yaml", "text", TypeYAML}, // what Nacos reports without a type
+	}
+	for _, tt := range tests {
+		if got := DetectType(tt.name, tt.declared); got != tt.want {
+			t.Errorf("DetectType(%q, %q) = %q, want %q", tt.name, tt.declared, got, tt.want)
+		}
+	}
+}
+
+func TestParseType(t *testing.T) {
+	if typ, err := ParseType("YML"); err != nil || typ != TypeYAML {
+		t.Errorf("ParseType(YML) = %q, %v", typ, err)
+	}
+	if _, err := ParseType("toml"); err == nil || !strings.Contains(err.Error(), Types) {
+		t.Errorf("ParseType(toml) error = %v, want the accepted types", err)
+	}
+}
+
+func TestProblemIn(t *testing.T) {
+	tests := []struct {
+		problem Problem
+		want    string
+	}{
+		{Problem{Line: 3, Column: 5, Message: "bad"}, "app.yaml:3:5: bad"},
+		{Problem{Line: 3, Message: "bad"}, "app.yaml:3: bad"},
+		{Problem{Message: "bad"}, "app.yaml: bad"},
+	}
+	for _, tt := range tests {
+		if got := tt.problem.In("app.yaml"); got != tt.want {
+			t.Errorf("In() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		typ     string
+		content string
+		want    []string
+	}{
+		{"valid json", TypeJSON, `{"a": [1, 2], "b": {"c": null}}`, nil},
+		{"json missing comma", TypeJSON, "{\n  \"a\": 1\n  \"b\": 2\n}", []string{"3:3: invalid character '\"' after object key:value pair"}},
+		{"truncated json", TypeJSON, "{\"a\": [1,", []string{"1:9: unexpected end of JSON input"}},
+		{"json with trailing content", TypeJSON, "{}\n}", []string{"2:1: invalid character '}' after top-level value"}},
+		{"valid yaml", TypeYAML, "a: 1\nb:\n  - c: 2\n    d: 3\n", nil},
+		{"yaml value with a colon", TypeYAML, "a: 1\nb: c: d\n", []string{"2: mapping values are not allowed in this context"}},
+		{"yaml duplicate key", TypeYAML, "server:\n  port: 80\n  host: a\n  port: 8080\n", []string{`4:3: duplicate key "port", first defined at line 2`}},
+		{"yaml duplicate key in a later document", TypeYAML, "a: 1\n---\nb: 1\nb: 2\n", []string{`4:1: duplicate key "b", first defined at line 3`}},
+		{"yaml keys of different types", TypeYAML, "1: a\n\"1\": b\n", nil},
+		{"yaml merge keys", TypeYAML, "base: &b {x: 1}\na:\n  <<: *b\n  <<: *b\n  y: 2\n", nil},
+		{"valid properties", TypeProperties, "# comment\na=1\nb : 2\nc 3\nlong=x\\\n  y\n", nil},
+		{"properties duplicate key", TypeProperties, "a=1\nb=2\n  a = 3\n", []string{`3:3: duplicate key "a", first defined at line 1`}},
+		{"properties escaped keys", TypeProperties, "a\\=b=1\na=2\n\\u0061=3\n", []string{`3:1: duplicate key "a", first defined at line 2`}},
+		{"properties continuation is not a key", TypeProperties, "a=1,\\\n  a=2\n", nil},
+		{"properties with CRLF", TypeProperties, "a=1\r\nb=2\r\na=3\r\n", []string{`3:1: duplicate key "a", first defined at line 1`}},
+		{"properties malformed unicode", TypeProperties, "a=\\u00zz\n", []string{`1: malformed \uxxxx escape "\\u00zz"`}},
+		{"valid xml", TypeXML, "<?xml version=\"1.0\"?>\n<beans>\n  <bean id=\"a\"/>\n</beans>\n", nil},
+		{"xml unclosed element", TypeXML, "<beans>\n  <bean>\n</beans>\n", []string{"3: element <bean> closed by </beans>"}},
+		{"xml two roots", TypeXML, "<a/>\n<b/>\n", []string{"2:5: more than one root element"}},
+		{"xml without root", TypeXML, "<!-- nothing -->\n", []string{"no root element"}},
+		{"unknown type", "toml", "a = = 1", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := Check(tt.typ, tt.content)
+			if len(problems) != len(tt.want) {
+				t.Fatalf("Check() = %q, want %q", problems, tt.want)
+			}
+			for i, want := range tt.want {
+				if got := problems[i].String(); got != want {
+					t.Errorf("problem %d = %q, want %q", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+package listener
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+)
+
+const (
+	// MaxBackoff caps the wait between polls while the server keeps failing
+	MaxBackoff = 2 * time.Minute
+	// failureSummaryInterval is how often a repeated error is summarized
+	failureSummaryInterval = 10 * time.Minute
+)
+
+// isFatal reports whether retrying err cannot succeed, such as a 400 for a
+// request the server will never accept
+func isFatal(err error) bool {
+	var statusErr *client.StatusError
+	return errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest
+}
+
+// Backoff computes the wait after consecutive failures: Base doubled per
+// failure, capped at Max, with up to 20% jitter either way so many clients
+// recovering from the same outage do not retry in step
+type Backoff struct {
+	Base     time.Duration
+	Max      time.Duration
+	failures int
+}
+
+// Next records a failure and returns how long to wait before trying again
+func (b *Backoff) Next() time.Duration {
+	delay := b.Base
+	for i := 0; i < b.failures && delay < b.Max; i++ {
+		delay *= 2
+	}
+	b.failures++
+	if delay > b.Max {
+		delay = b.Max
+	}
+	jittered := time.Duration(float64(delay) * (0.8 + 0.4*rand.Float64()))
+	if jittered > b.Max {
+		return b.Max
+	}
+	return jittered
+}
+
+// Reset starts over after a success
+func (b *Backoff) Reset() {
+	b.failures = 0
+}
+
+// failure tracks an error that keeps repeating for one config
+type failure struct {
+	message string
+	count   int
+	logged  time.Time
+}
+
+// record notes one occurrence of message at now and returns the line to log,
+// or "" when it only repeats an error that was reported recently
+func (f *failure) record(message string, now time.Time) string {
+	if message != f.message {
+		f.message, f.count, f.logged = message, 1, now
+		return message
+	}
+	f.count++
+	if now.Sub(f.logged) < failureSummaryInterval {
+		return ""
+	}
+	f.logged = now
+	return fmt.Sprintf("still failing (x%d, last: %s)", f.count, message)
+}
@@ -0,0 +1,76 @@
+package listener
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+func TestBackoff(t *testing.T) {
+	b := Backoff{Base: 15 * time.Second, Max: 2 * time.Minute}
+	for i, want := range []time.Duration{15 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute, 2 * time.Minute} {
+		got := b.Next()
+		low := time.Duration(float64(want) * 0.8)
+		if got < low || got > want*6/5 || got > b.Max {
+			t.Errorf("Next() #%d = %s, want %s ±20%% capped at %s", i+1, got, want, b.Max)
+		}
+	}
+
+	b.Reset()
+	if got := b.Next(); got > 18*time.Second {
+		t.Errorf("Next() after reset = %s, want about 15s", got)
+	}
+}
+
+func TestFailureRecord(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	var f failure
+
+	steps := []struct {
+		message string
+		after   time.Duration
+		want    string
+	}{
+		{"connection refused", 0, "connection refused"},
+		{"connection refused", time.Minute, ""},
+		{"connection refused", 2 * time.Minute, ""},
+		{"connection refused", 10 * time.Minute, "still failing (x4, last: connection refused)"},
+		{"connection refused", 11 * time.Minute, ""},
+		{"status 500", 12 * time.Minute, "status 500"},
+	}
+	for _, step := range steps {
+		if got := f.record(step.message, start.Add(step.after)); got != step.want {
+			t.Errorf("record(%q) at +%s = %q, want %q", step.message, step.after, got, step.want)
+		}
+	}
+}
+
+func TestBadRequestStopsListening(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("dataId is invalid"))
+	}))
+	defer server.Close()
+
+	l := NewConfigListener(newClient(t, server.URL))
+	l.SetLogger(logging.Discard())
+
+	done := make(chan error, 1)
+	go func() {
+		items := []ConfigItem{{DataID: "bad id", Group: "skill_demo"}}
+		done <- l.StartListening(items, func(string, string, string, string) error { return nil }, make(chan struct{}))
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), "will not be retried") || !strings.Contains(err.Error(), "dataId is invalid") {
+			t.Errorf("StartListening() error = %v, want a fatal 400 error", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("StartListening() kept retrying a 400")
+	}
+}
@@ -2,21 +2,42 @@ package listener
 
 import (
 	"context"
-	"crypto/md5"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
+	"net"
 	"net/http"
-	"net/url"
+	"sort"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
 )
 
 const (
-	// PollInterval is the interval between polling requests
+	// PollInterval is the default interval between polls of a config. The v3
+	// API has no long-poll listener, so every watched config is fetched this often.
 	PollInterval = 15 * time.Second
+	// DefaultPollTimeout is how long the server may take to answer a poll
+	DefaultPollTimeout = 30 * time.Second
+	// pollTimeoutMargin is added to the poll timeout to get the deadline of
+	// each request, so a server answering at the poll timeout is not cut off
+	pollTimeoutMargin = 5 * time.Second
+	// DefaultBatchSize is how many configs one poller watches; larger watch
+	// sets are split across pollers running concurrently
+	DefaultBatchSize = 100
+	// lostAfterPolls is how many polls in a row a batch must fail to fetch
+	// any of its configs before the connection to Nacos is reported lost
+	lostAfterPolls = 3
 )
 
+// errWindowTimeout reports a poll that timed out near the end of the poll
+// window, typically a gateway closing an idle connection. It means "no change".
+var errWindowTimeout = errors.New("poll timed out at the end of the poll window")
+
 // ConfigItem represents a configuration item being monitored
 type ConfigItem struct {
 	DataID string
@@ -25,77 +46,199 @@ type ConfigItem struct {
 	MD5    string
 }
 
-// ChangeHandler is called when a config change is detected
-type ChangeHandler func(dataID, group, tenant string) error
+// Changes a ChangeHandler is told about
+const (
+	ConfigModified = "modified" // the content of a config changed
+	ConfigDeleted  = "deleted"  // a config was deleted
+	ConfigCreated  = "created"  // a config missing so far, or deleted before, exists now
+)
+
+// ChangeHandler is called when a config change is detected, with the
+// change: ConfigModified, ConfigDeleted or ConfigCreated. When it returns an
+// error the change is reported again on the next poll.
+type ChangeHandler func(dataID, group, tenant, change string) error
+
+// ErrorHandler is told when a config cannot be fetched. It is called as often
+// as the failure is logged, so an error repeating on every poll is reported
+// once and then in periodic summaries.
+type ErrorHandler func(dataID, group, tenant string, err error)
+
+// CycleHandler is called after each poll of a batch of configs, whether or
+// not it found changes, e.g. to write a heartbeat
+type CycleHandler func()
+
+// ReconnectHandler is called once Nacos can be reached again after the
+// connection was reported lost, with how long it was down, e.g. to look for
+// changes missed during the outage
+type ReconnectHandler func(downtime time.Duration)
+
+// ConfigFetcher fetches a config with its MD5 within ctx; see
+// client.NacosClient.GetConfigWithMD5, which implements it. The listener thus
+// shares the client's token or Aliyun keys, and a token refreshed by one is
+// seen by the other.
+type ConfigFetcher interface {
+	GetConfigWithMD5(ctx context.Context, dataID, group, tenant string) (content, md5 string, err error)
+}
 
 // ConfigListener listens for configuration changes from Nacos
 type ConfigListener struct {
-	serverAddr  string
-	username    string
-	password    string
-	accessToken string
-	httpClient  *http.Client
+	fetcher     ConfigFetcher
+	pollTimeout time.Duration
+	interval    time.Duration // see SetPollInterval
+	batchSize   int
+	log         *slog.Logger
+	onError     ErrorHandler
+	onCycle     CycleHandler
+	onReconnect ReconnectHandler
+
+	handlerMu sync.Mutex // batches call the ChangeHandler one at a time
+
+	failMu   sync.Mutex
+	failures map[string]*failure // repeating errors by config, see logFailure
+
+	connMu    sync.Mutex
+	lostSince time.Time // when polls started failing; zero while connected
+
+	mu      sync.Mutex
+	pending []groupUpdate // WatchGroup calls not yet applied
+	updated chan struct{} // signalled by WatchGroup
+}
+
+// groupUpdate is the new set of items to watch in one tenant/group
+type groupUpdate struct {
+	tenant string
+	group  string
+	items  []ConfigItem
 }
 
-// NewConfigListener creates a new configuration listener
-func NewConfigListener(serverAddr, username, password string) *ConfigListener {
+// NewConfigListener creates a new configuration listener that fetches
+// configs with fetcher, usually a *client.NacosClient
+func NewConfigListener(fetcher ConfigFetcher) *ConfigListener {
 	return &ConfigListener{
-		serverAddr: serverAddr,
-		username:   username,
-		password:   password,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+		fetcher:     fetcher,
+		pollTimeout: DefaultPollTimeout,
+		interval:    PollInterval,
+		batchSize:   DefaultBatchSize,
+		updated:     make(chan struct{}, 1),
+		log:         logging.Stderr(),
 	}
 }
 
-// Login gets access token for authentication using v3 API
-func (l *ConfigListener) Login() error {
-	loginURL := fmt.Sprintf("http://%s/nacos/v3/auth/user/login", l.serverAddr)
+// SetLogger replaces the default logger, which prints to stderr. Entries
+// carry the dataId and group of the config and an event of "deleted" or "error".
+func (l *ConfigListener) SetLogger(logger *slog.Logger) {
+	l.log = logger
+}
 
-	data := url.Values{}
-	data.Set("username", l.username)
-	data.Set("password", l.password)
+// SetErrorHandler sets a function told about configs that cannot be fetched
+func (l *ConfigListener) SetErrorHandler(handler ErrorHandler) {
+	l.onError = handler
+}
 
-	resp, err := l.httpClient.PostForm(loginURL, data)
-	if err != nil {
-		return fmt.Errorf("login failed: %w", err)
+// SetCycleHandler sets a function called after each poll of a batch. Batches
+// poll concurrently, so it must be safe to call from several goroutines.
+func (l *ConfigListener) SetCycleHandler(handler CycleHandler) {
+	l.onCycle = handler
+}
+
+// SetReconnectHandler sets a function called after the connection to Nacos
+// was lost and polls succeed again
+func (l *ConfigListener) SetReconnectHandler(handler ReconnectHandler) {
+	l.onReconnect = handler
+}
+
+// SetPollInterval sets how often each config is polled; zero keeps PollInterval
+func (l *ConfigListener) SetPollInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = PollInterval
 	}
-	defer resp.Body.Close()
+	l.interval = interval
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("login failed with status: %d", resp.StatusCode)
+// SetPollTimeout sets how long each poll may take. Set it below the idle
+// timeout of any gateway in front of Nacos; zero keeps DefaultPollTimeout.
+func (l *ConfigListener) SetPollTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultPollTimeout
 	}
+	l.pollTimeout = timeout
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return fmt.Errorf("read login response failed: %w", err)
+// SetBatchSize sets how many configs one poller watches; zero keeps
+// DefaultBatchSize
+func (l *ConfigListener) SetBatchSize(size int) {
+	if size <= 0 {
+		size = DefaultBatchSize
 	}
+	l.batchSize = size
+}
 
-	// Parse accessToken from response
-	token := extractAccessToken(string(body))
-	if token == "" {
-		return fmt.Errorf("failed to extract access token from response")
+// WatchGroup replaces the configs watched in tenant/group with items, for
+// example when a skill gains or loses resources. Configs already watched keep
+// their MD5; new ones should be primed. It may be called from a ChangeHandler;
+// the batches are rebalanced once the handler returns.
+func (l *ConfigListener) WatchGroup(tenant, group string, items []ConfigItem) {
+	l.mu.Lock()
+	l.pending = append(l.pending, groupUpdate{tenant: tenant, group: group, items: items})
+	l.mu.Unlock()
+	select {
+	case l.updated <- struct{}{}:
+	default:
 	}
+}
 
-	l.accessToken = token
-	return nil
+// applyUpdates applies pending WatchGroup calls to currentItems
+func (l *ConfigListener) applyUpdates(currentItems map[string]*ConfigItem) {
+	l.mu.Lock()
+	updates := l.pending
+	l.pending = nil
+	l.mu.Unlock()
+
+	for _, update := range updates {
+		keep := make(map[string]bool)
+		for i := range update.items {
+			key := itemKey(update.items[i])
+			keep[key] = true
+			if _, ok := currentItems[key]; !ok {
+				item := update.items[i]
+				currentItems[key] = &item
+			}
+		}
+		for key, item := range currentItems {
+			if item.Tenant == update.tenant && item.Group == update.group && !keep[key] {
+				delete(currentItems, key)
+				l.failMu.Lock()
+				delete(l.failures, key)
+				l.failMu.Unlock()
+			}
+		}
+	}
 }
 
-// StartListening starts polling for configuration changes (v3 API doesn't support long-polling)
-func (l *ConfigListener) StartListening(items []ConfigItem, handler ChangeHandler, stopCh <-chan struct{}) error {
-	// Login first to get access token
-	if l.username != "" && l.password != "" {
-		if err := l.Login(); err != nil {
-			fmt.Printf("Warning: Login failed: %v\n", err)
+func itemKey(item ConfigItem) string {
+	return fmt.Sprintf("%s_%s_%s", item.DataID, item.Group, item.Tenant)
+}
+
+// Prime fills in the current MD5 of each item so that the first poll only
+// reports configs that changed after this call. Missing configs keep an empty MD5.
+func (l *ConfigListener) Prime(items []ConfigItem) {
+	for i := range items {
+		if _, md5, err := l.getConfig(context.Background(), items[i].DataID, items[i].Group, items[i].Tenant); err == nil {
+			items[i].MD5 = md5
 		}
 	}
+}
 
+// StartListening starts polling for configuration changes (v3 API doesn't support long-polling).
+// The items are split into batches of the batch size, each polled by its own
+// goroutine with its own backoff. When WatchGroup changes the watched configs
+// the batches are stopped and rebuilt; an item keeps its MD5 across batches,
+// so each change is still handled once.
+func (l *ConfigListener) StartListening(items []ConfigItem, handler ChangeHandler, stopCh <-chan struct{}) error {
 	// Keep a map of current items and their MD5
 	currentItems := make(map[string]*ConfigItem)
 	for i := range items {
-		key := fmt.Sprintf("%s_%s_%s", items[i].DataID, items[i].Group, items[i].Tenant)
-		currentItems[key] = &items[i]
+		currentItems[itemKey(items[i])] = &items[i]
 	}
 
 	// Create a context that cancels when stopCh is closed
@@ -107,157 +250,320 @@ func (l *ConfigListener) StartListening(items []ConfigItem, handler ChangeHandle
 		cancel()
 	}()
 
-	ticker := time.NewTicker(PollInterval)
-	defer ticker.Stop()
+	// The first round checks immediately; after a rebalance the items were
+	// just polled or primed, so the batches wait a poll interval first
+	var initialDelay time.Duration
+	for {
+		l.applyUpdates(currentItems)
+		batchCtx, stopBatches := context.WithCancel(ctx)
+		errCh := make(chan error, 1)
+		var wg sync.WaitGroup
+		for _, batch := range splitBatches(currentItems, l.batchSize) {
+			wg.Add(1)
+			go func(batch map[string]*ConfigItem) {
+				defer wg.Done()
+				if err := l.pollBatch(batchCtx, batch, handler, initialDelay); err != nil {
+					select {
+					case errCh <- err:
+					default:
+					}
+				}
+			}(batch)
+		}
+
+		var err error
+		select {
+		case <-ctx.Done():
+		case <-l.updated:
+		case err = <-errCh:
+		}
+		stopBatches()
+		wg.Wait()
+		if err != nil {
+			return err
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		initialDelay = l.interval
+	}
+}
+
+// WaitForChange polls one config until its MD5 differs from item.MD5 and
+// returns the new MD5, which is empty when the config was deleted. An item
+// with an empty MD5 waits for the config to be created. When ctx ends first
+// it returns ctx.Err().
+func (l *ConfigListener) WaitForChange(ctx context.Context, item ConfigItem) (string, error) {
+	stop := make(chan struct{})
+	var once sync.Once
+	stopOnce := func() { once.Do(func() { close(stop) }) }
+	go func() {
+		select {
+		case <-ctx.Done():
+			stopOnce()
+		case <-stop:
+		}
+	}()
+
+	changed := false
+	items := []ConfigItem{item}
+	err := l.StartListening(items, func(dataID, group, tenant, change string) error {
+		changed = true
+		stopOnce()
+		return nil
+	}, stop)
+	stopOnce()
+	if err != nil {
+		return "", err
+	}
+	if !changed {
+		return "", ctx.Err()
+	}
+	// StartListening records the new MD5 once the handler returned
+	return items[0].MD5, nil
+}
+
+// splitBatches splits items into batches of at most size, in key order so
+// batches change little when items are added or removed
+func splitBatches(items map[string]*ConfigItem, size int) []map[string]*ConfigItem {
+	keys := make([]string, 0, len(items))
+	for key := range items {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
 
-	// Do an initial check immediately
-	l.pollConfigs(ctx, currentItems, handler)
+	var batches []map[string]*ConfigItem
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+		batch := make(map[string]*ConfigItem, end-start)
+		for _, key := range keys[start:end] {
+			batch[key] = items[key]
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
 
+// pollBatch polls a batch every poll interval until ctx is cancelled, backing
+// off while none of its configs can be fetched (usually the server is down).
+// Items keep their MD5 throughout, so once the server is back only configs
+// that really changed are handled.
+func (l *ConfigListener) pollBatch(ctx context.Context, batch map[string]*ConfigItem, handler ChangeHandler, delay time.Duration) error {
+	retry := Backoff{Base: l.interval, Max: MaxBackoff}
+	var failedPolls int
+	var failingSince time.Time
 	for {
 		select {
 		case <-ctx.Done():
 			return nil
-		case <-ticker.C:
-			l.pollConfigs(ctx, currentItems, handler)
+		case <-time.After(delay):
+		}
+
+		failed, err := l.pollConfigs(ctx, batch, handler)
+		if err != nil {
+			return err
+		}
+		if l.onCycle != nil && ctx.Err() == nil {
+			l.onCycle()
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		delay = l.interval
+		if failed > 0 && failed == len(batch) {
+			delay = retry.Next()
+			if failedPolls == 0 {
+				failingSince = time.Now()
+			}
+			failedPolls++
+			if failedPolls == lostAfterPolls {
+				l.connectionLost(failingSince, len(batch))
+			}
+		} else {
+			retry.Reset()
+			failedPolls = 0
+			l.reconnected()
 		}
 	}
 }
 
-// pollConfigs polls all configurations and checks for changes
-func (l *ConfigListener) pollConfigs(ctx context.Context, currentItems map[string]*ConfigItem, handler ChangeHandler) {
+// connectionLost reports that a batch of n configs has not been fetched
+// since the given time. Batches fail together when the server is down, so
+// only the first report is logged.
+func (l *ConfigListener) connectionLost(since time.Time, n int) {
+	l.connMu.Lock()
+	defer l.connMu.Unlock()
+	if !l.lostSince.IsZero() {
+		return
+	}
+	l.lostSince = since
+	l.log.Warn(fmt.Sprintf("Connection to Nacos lost: none of %d config(s) could be fetched in %d polls", n, lostAfterPolls), "event", "connection-lost")
+}
+
+// reconnected reports a successful poll; after a lost connection it logs the
+// outage and calls the ReconnectHandler
+func (l *ConfigListener) reconnected() {
+	l.connMu.Lock()
+	since := l.lostSince
+	l.lostSince = time.Time{}
+	l.connMu.Unlock()
+	if since.IsZero() {
+		return
+	}
+	downtime := time.Since(since).Round(time.Second)
+	l.log.Info(fmt.Sprintf("Reconnected to Nacos after %s", downtime), "event", "reconnected", "downtime", downtime)
+	if l.onReconnect != nil {
+		l.onReconnect(downtime)
+	}
+}
+
+// pollConfigs polls all configurations and checks for changes. It returns how
+// many configs could not be fetched, or an error when polling cannot succeed
+// and listening should stop.
+func (l *ConfigListener) pollConfigs(ctx context.Context, currentItems map[string]*ConfigItem, handler ChangeHandler) (int, error) {
+	failed := 0
 	for key, item := range currentItems {
 		select {
 		case <-ctx.Done():
-			return
+			return failed, nil
 		default:
 		}
 
 		// Fetch latest config
-		content, newMD5, err := l.getConfig(item.DataID, item.Group, item.Tenant)
+		content, newMD5, err := l.getConfig(ctx, item.DataID, item.Group, item.Tenant)
+		if ctx.Err() != nil {
+			// Stopped or rebalanced mid-request; the item is polled again later
+			return failed, nil
+		}
+		if errors.Is(err, errWindowTimeout) {
+			l.clearFailure(key, item)
+			continue
+		}
+		if isFatal(err) {
+			return failed, fmt.Errorf("polling %s/%s failed and will not be retried: %w", item.DataID, item.Group, err)
+		}
 		if err != nil {
-			// Check if it's a 404 error (config deleted)
-			if strings.Contains(err.Error(), "404") || strings.Contains(err.Error(), "not exist") || strings.Contains(err.Error(), "config data not exist") {
+			// The config was deleted
+			if errors.Is(err, client.ErrConfigNotFound) || strings.Contains(err.Error(), "not exist") {
 				// Check if MD5 is already empty (already processed deletion)
 				if item.MD5 == "" {
 					// Already deleted and MD5 reset, skip
 					continue
 				}
 				// First time seeing deletion, process it
-				if err := handler(item.DataID, item.Group, item.Tenant); err != nil {
-					fmt.Printf("Handler failed for %s/%s: %v\n", item.DataID, item.Group, err)
+				l.itemLog(item).Info(fmt.Sprintf("Config %s/%s deleted", item.DataID, item.Group), "event", ConfigDeleted)
+				if err := l.handle(handler, item, ConfigDeleted); err != nil {
+					l.itemLog(item).Error(fmt.Sprintf("Handler failed for %s/%s: %v", item.DataID, item.Group, err), "event", "error", "error", err)
+					continue
 				}
-				// Reset MD5 to empty so we can detect if skill is recreated
+				// Reset MD5 to empty so that the config being created again
+				// is reported as ConfigCreated
 				item.MD5 = ""
 				continue
 			}
-			fmt.Printf("Failed to fetch config %s/%s: %v\n", item.DataID, item.Group, err)
+			failed++
+			l.logFailure(key, item, err)
 			continue
 		}
+		l.clearFailure(key, item)
 
 		// Check if MD5 actually changed
+		l.itemLog(item).Debug(fmt.Sprintf("Polled %s/%s: %d bytes, changed=%v", item.DataID, item.Group, len(content), item.MD5 != newMD5),
+			"bytes", len(content), "md5", newMD5, "previousMd5", item.MD5)
 		if item.MD5 == newMD5 {
 			// MD5 hasn't changed, skip
 			continue
 		}
 
 		// Call handler
-		if err := handler(item.DataID, item.Group, item.Tenant); err != nil {
-			fmt.Printf("Handler failed for %s/%s: %v\n", item.DataID, item.Group, err)
+		change := ConfigModified
+		if item.MD5 == "" {
+			change = ConfigCreated
+			l.itemLog(item).Info(fmt.Sprintf("Config %s/%s created", item.DataID, item.Group), "event", ConfigCreated)
+		}
+		if err := l.handle(handler, item, change); err != nil {
+			l.itemLog(item).Error(fmt.Sprintf("Handler failed for %s/%s: %v", item.DataID, item.Group, err), "event", "error", "error", err)
 			continue
 		}
 
 		// Update MD5 only if handler succeeds
 		item.MD5 = newMD5
-
-		_ = content // Suppress unused warning
-		_ = key     // Suppress unused warning
 	}
+	return failed, nil
 }
 
-// getConfig fetches the latest configuration content using v3 client API
-func (l *ConfigListener) getConfig(dataID, group, tenant string) (string, string, error) {
-	params := url.Values{}
-	params.Set("dataId", dataID)
-	params.Set("groupName", group)
-	if tenant != "" {
-		params.Set("namespaceId", tenant)
-	}
-
-	configURL := fmt.Sprintf("http://%s/nacos/v3/client/cs/config?%s", l.serverAddr, params.Encode())
-
-	req, err := http.NewRequest("GET", configURL, nil)
-	if err != nil {
-		return "", "", err
-	}
-	if l.accessToken != "" {
-		req.Header.Set("Authorization", "Bearer "+l.accessToken)
-	}
-
-	resp, err := l.httpClient.Do(req)
-	if err != nil {
-		return "", "", err
-	}
-	defer resp.Body.Close()
+// handle calls the handler for a change of item; batches take turns
+func (l *ConfigListener) handle(handler ChangeHandler, item *ConfigItem, change string) error {
+	l.handlerMu.Lock()
+	defer l.handlerMu.Unlock()
+	return handler(item.DataID, item.Group, item.Tenant, change)
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", "", err
-	}
+// itemLog returns the logger with the attributes identifying item
+func (l *ConfigListener) itemLog(item *ConfigItem) *slog.Logger {
+	return l.log.With("dataId", item.DataID, "group", item.Group)
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return "", "", fmt.Errorf("get config returned status %d: %s", resp.StatusCode, string(body))
+// logFailure logs a failed fetch, collapsing an error that repeats on every
+// poll into a periodic "still failing" summary
+func (l *ConfigListener) logFailure(key string, item *ConfigItem, err error) {
+	l.failMu.Lock()
+	defer l.failMu.Unlock()
+	if l.failures == nil {
+		l.failures = make(map[string]*failure)
 	}
-
-	// Parse v3 response
-	var v3Resp struct {
-		Code    int    `json:"code"`
-		Message string `json:"message"`
-		Data    struct {
-			Content string `json:"content"`
-			Md5     string `json:"md5"`
-		} `json:"data"`
+	f, ok := l.failures[key]
+	if !ok {
+		f = &failure{}
+		l.failures[key] = f
 	}
-	if err := json.Unmarshal(body, &v3Resp); err != nil {
-		// Fallback: treat as raw content
-		contentMD5 := calculateMD5(string(body))
-		return string(body), contentMD5, nil
+	if line := f.record(err.Error(), time.Now()); line != "" {
+		l.itemLog(item).Warn(fmt.Sprintf("Failed to fetch config %s/%s: %s", item.DataID, item.Group, line), "event", "error", "error", err, "failures", f.count)
+		if l.onError != nil {
+			l.onError(item.DataID, item.Group, item.Tenant, err)
+		}
 	}
+}
 
-	if v3Resp.Code != 0 {
-		return "", "", fmt.Errorf("get config failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message)
+// clearFailure forgets the errors of a config that was fetched again
+func (l *ConfigListener) clearFailure(key string, item *ConfigItem) {
+	l.failMu.Lock()
+	defer l.failMu.Unlock()
+	f, ok := l.failures[key]
+	if !ok {
+		return
 	}
-
-	content := v3Resp.Data.Content
-	contentMD5 := v3Resp.Data.Md5
-	if contentMD5 == "" {
-		contentMD5 = calculateMD5(content)
+	delete(l.failures, key)
+	if f.count > 1 {
+		l.itemLog(item).Info(fmt.Sprintf("Fetching config %s/%s recovered after %d failures", item.DataID, item.Group, f.count), "failures", f.count)
 	}
-
-	return content, contentMD5, nil
 }
 
-// CalculateMD5 calculates MD5 hash of content (exported for reuse)
-func CalculateMD5(content string) string {
-	hash := md5.Sum([]byte(content))
-	return fmt.Sprintf("%x", hash)
+// getConfig fetches the latest content and MD5 of a config. A request that
+// times out near the end of the poll window returns errWindowTimeout.
+func (l *ConfigListener) getConfig(ctx context.Context, dataID, group, tenant string) (string, string, error) {
+	reqCtx, cancel := context.WithTimeout(ctx, l.pollTimeout+pollTimeoutMargin)
+	defer cancel()
+	start := time.Now()
+	content, md5, err := l.fetcher.GetConfigWithMD5(reqCtx, dataID, group, tenant)
+	var statusErr *client.StatusError
+	timedOut := isTimeout(err) || (errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusGatewayTimeout)
+	if timedOut && ctx.Err() == nil && l.nearWindowEnd(time.Since(start)) {
+		return "", "", errWindowTimeout
+	}
+	return content, md5, err
 }
 
-// calculateMD5 is the internal version
-func calculateMD5(content string) string {
-	return CalculateMD5(content)
+// nearWindowEnd reports whether a request that failed after elapsed ran for
+// most of the poll window, as opposed to failing early
+func (l *ConfigListener) nearWindowEnd(elapsed time.Duration) bool {
+	return elapsed >= l.pollTimeout*3/4
 }
 
-// extractAccessToken extracts access token from JSON response
-func extractAccessToken(body string) string {
-	// Simple JSON parsing for {"accessToken":"xxx",...} or {"data":{"accessToken":"xxx",...}}
-	start := strings.Index(body, `"accessToken":"`)
-	if start == -1 {
-		return ""
-	}
-	start += len(`"accessToken":"`)
-	end := strings.Index(body[start:], `"`)
-	if end == -1 {
-		return ""
-	}
-	return body[start : start+end]
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return (errors.As(err, &netErr) && netErr.Timeout()) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
 }
@@ -0,0 +1,523 @@
+package listener
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+// newSpasServer returns a config server that answers only requests carrying a
+// valid SPAS signature for accessKey/secretKey, and the signed data it checked
+func newSpasServer(t *testing.T, accessKey, secretKey string) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var signed []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ts := r.Header.Get("timeStamp")
+		if r.Header.Get("Spas-AccessKey") != accessKey || ts == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		// The public namespace is signed as an empty tenant
+		tenant := r.URL.Query().Get("namespaceId")
+		if tenant == "public" {
+			tenant = ""
+		}
+		var parts []string
+		for _, part := range []string{tenant, r.URL.Query().Get("groupName"), ts} {
+			if part != "" {
+				parts = append(parts, part)
+			}
+		}
+		data := strings.Join(parts, "+")
+		mac := hmac.New(sha1.New, []byte(secretKey))
+		mac.Write([]byte(data))
+		if r.Header.Get("Spas-Signature") != base64.StdEncoding.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		mu.Lock()
+		signed = append(signed, strings.TrimSuffix(data, ts))
+		mu.Unlock()
+		fmt.Fprint(w, `{"code":0,"data":{"content":"{}","md5":"abc"}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), signed...)
+	}
+}
+
+func TestListenerSignsRequestsWithSpas(t *testing.T) {
+	tests := []struct {
+		name   string
+		tenant string
+		want   string
+	}{
+		{"public namespace", "public", "skill_demo+"},
+		{"named namespace", "dev", "dev+skill_demo+"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, signed := newSpasServer(t, "ak", "sk")
+			addr := strings.TrimPrefix(server.URL, "http://")
+			c, err := client.NewNacosClient(addr, tt.tenant, client.AuthTypeAliyun, "", "", "ak", "sk", "")
+			if err != nil {
+				t.Fatalf("NewNacosClient() error = %v", err)
+			}
+			l := NewConfigListener(c)
+			l.SetLogger(slog.New(logging.NewConsoleHandler(func(msg string) {
+				t.Errorf("unexpected log: %s", msg)
+			})))
+
+			items := []ConfigItem{{DataID: "skill.json", Group: "skill_demo", Tenant: tt.tenant}}
+			l.Prime(items)
+			if items[0].MD5 != "abc" {
+				t.Fatalf("Prime() MD5 = %q, want %q (request rejected?)", items[0].MD5, "abc")
+			}
+
+			changed := 0
+			l.pollConfigs(context.Background(), map[string]*ConfigItem{"k": &items[0]}, func(dataID, group, tenant, change string) error {
+				changed++
+				return nil
+			})
+			if changed != 0 {
+				t.Errorf("pollConfigs() reported %d changes, want 0", changed)
+			}
+
+			got := signed()
+			if len(got) != 2 || got[0] != tt.want || got[1] != tt.want {
+				t.Errorf("signed data = %q, want two requests signed as %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// newTokenServer returns a config server that issues token-1, token-2, ... on
+// login with the given tokenTtl and accepts only the most recent token
+func newTokenServer(t *testing.T, ttl int) (*httptest.Server, *int32) {
+	var logins int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nacos/v3/auth/user/login", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&logins, 1)
+		fmt.Fprintf(w, `{"accessToken":"token-%d","tokenTtl":%d}`, n, ttl)
+	})
+	mux.HandleFunc("/nacos/v3/client/cs/config", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", atomic.LoadInt32(&logins)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"data":{"content":"{}","md5":"abc"}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, &logins
+}
+
+func TestListenerKeepsTokenValid(t *testing.T) {
+	tests := []struct {
+		name       string
+		ttl        int
+		revoke     bool
+		wantLogins int32
+	}{
+		// A token about to expire is refreshed before the request
+		{"refresh before expiry", 1, false, 2},
+		// A token the server no longer accepts is replaced after the 403
+		{"relogin after forbidden", 18000, true, 3},
+		{"valid token reused", 18000, false, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, logins := newTokenServer(t, tt.ttl)
+			addr := strings.TrimPrefix(server.URL, "http://")
+			c, err := client.NewNacosClient(addr, "", "", "nacos", "secret", "", "", "")
+			if err != nil {
+				t.Fatalf("NewNacosClient() error = %v", err)
+			}
+			if err := c.Login(); err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			l := NewConfigListener(c)
+			l.SetLogger(slog.New(logging.NewConsoleHandler(func(msg string) {
+				t.Errorf("unexpected log: %s", msg)
+			})))
+
+			if tt.revoke {
+				atomic.AddInt32(logins, 1)
+			}
+			if _, md5, err := l.getConfig(context.Background(), "skill.json", "skill_demo", ""); err != nil || md5 != "abc" {
+				t.Fatalf("getConfig() = %q, %v; want abc", md5, err)
+			}
+			if got := atomic.LoadInt32(logins); got != tt.wantLogins {
+				t.Errorf("logins = %d, want %d", got, tt.wantLogins)
+			}
+		})
+	}
+}
+
+// newClient returns a client without authentication for the server at addr
+func newClient(t *testing.T, addr string) *client.NacosClient {
+	t.Helper()
+	c, err := client.NewNacosClient(strings.TrimPrefix(addr, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatalf("NewNacosClient() error = %v", err)
+	}
+	return c
+}
+
+func TestGatewayTimeoutAtWindowEndIsNoChange(t *testing.T) {
+	tests := []struct {
+		name  string
+		delay time.Duration
+		want  error
+	}{
+		{"near the end of the window", 90 * time.Millisecond, errWindowTimeout},
+		{"early in the window", 0, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				time.Sleep(tt.delay)
+				w.WriteHeader(http.StatusGatewayTimeout)
+			}))
+			defer server.Close()
+
+			l := NewConfigListener(newClient(t, server.URL))
+			l.SetPollTimeout(100 * time.Millisecond)
+			_, _, err := l.getConfig(context.Background(), "skill.json", "skill_demo", "")
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("getConfig() error = %v, want %v", err, tt.want)
+			}
+			if tt.want == nil && (err == nil || errors.Is(err, errWindowTimeout)) {
+				t.Errorf("getConfig() error = %v, want a gateway timeout error", err)
+			}
+		})
+	}
+}
+
+func TestWatchGroup(t *testing.T) {
+	l := NewConfigListener(newClient(t, "127.0.0.1:0"))
+	current := make(map[string]*ConfigItem)
+	for _, item := range []ConfigItem{
+		{DataID: "skill.json", Group: "skill_a", MD5: "1"},
+		{DataID: "resource_old.json", Group: "skill_a", MD5: "2"},
+		{DataID: "resource_x.json", Group: "skill_b", MD5: "3"},
+	} {
+		item := item
+		current[itemKey(item)] = &item
+	}
+
+	l.WatchGroup("", "skill_a", []ConfigItem{
+		{DataID: "skill.json", Group: "skill_a", MD5: "new"},
+		{DataID: "resource_new.json", Group: "skill_a", MD5: "4"},
+	})
+	l.applyUpdates(current)
+
+	got := make(map[string]string)
+	for _, item := range current {
+		got[item.Group+"/"+item.DataID] = item.MD5
+	}
+	want := map[string]string{
+		"skill_a/skill.json":        "1", // already watched, MD5 kept
+		"skill_a/resource_new.json": "4",
+		"skill_b/resource_x.json":   "3",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("watched = %v, want %v", got, want)
+	}
+	for key, md5 := range want {
+		if got[key] != md5 {
+			t.Errorf("watched[%s] = %q, want %q (all: %v)", key, got[key], md5, got)
+		}
+	}
+}
+
+// fakeFetcher serves one config whose MD5 the test sets; "" means deleted
+type fakeFetcher struct {
+	md5 string
+}
+
+func (f *fakeFetcher) GetConfigWithMD5(ctx context.Context, dataID, group, tenant string) (string, string, error) {
+	if f.md5 == "" {
+		return "", "", fmt.Errorf("%w: %s (%s)", client.ErrConfigNotFound, dataID, group)
+	}
+	return "content " + f.md5, f.md5, nil
+}
+
+func TestPollReportsDeletionAndCreation(t *testing.T) {
+	fetcher := &fakeFetcher{md5: "v1"}
+	l := NewConfigListener(fetcher)
+	l.SetLogger(logging.Discard())
+	item := &ConfigItem{DataID: "skill.json", Group: "skill_demo", MD5: "v1"}
+	var changes []string
+	fail := false
+	handler := func(dataID, group, tenant, change string) error {
+		changes = append(changes, change)
+		if fail {
+			return errors.New("handler failed")
+		}
+		return nil
+	}
+	poll := func() {
+		l.pollConfigs(context.Background(), map[string]*ConfigItem{"k": item}, handler)
+	}
+
+	poll() // unchanged
+	fetcher.md5 = "v2"
+	poll()
+	fetcher.md5 = ""
+	fail = true
+	poll() // a failed deletion is reported again
+	fail = false
+	poll()
+	poll() // deletion handled; still deleted
+	fetcher.md5 = "v2"
+	poll() // recreated with the content it had before
+	poll()
+
+	want := []string{ConfigModified, ConfigDeleted, ConfigDeleted, ConfigCreated}
+	if strings.Join(changes, ",") != strings.Join(want, ",") {
+		t.Errorf("changes = %v, want %v", changes, want)
+	}
+	if item.MD5 != "v2" {
+		t.Errorf("MD5 = %q after recreation, want v2", item.MD5)
+	}
+}
+
+func TestSplitBatches(t *testing.T) {
+	items := make(map[string]*ConfigItem)
+	for i := 0; i < 5; i++ {
+		item := ConfigItem{DataID: fmt.Sprintf("resource_%d", i), Group: "skill_a"}
+		items[itemKey(item)] = &item
+	}
+	batches := splitBatches(items, 2)
+	if len(batches) != 3 {
+		t.Fatalf("splitBatches() = %d batches, want 3", len(batches))
+	}
+	seen := make(map[string]bool)
+	for i, batch := range batches {
+		if len(batch) > 2 {
+			t.Errorf("batch %d has %d items, want at most 2", i, len(batch))
+		}
+		for key := range batch {
+			if seen[key] {
+				t.Errorf("%s is in more than one batch", key)
+			}
+			seen[key] = true
+		}
+	}
+	if len(seen) != len(items) {
+		t.Errorf("batches hold %d items, want %d", len(seen), len(items))
+	}
+}
+
+// newMD5Server serves the MD5 of each dataId from md5s, which set changes
+func newMD5Server(t *testing.T, md5s map[string]string) (string, func(dataID, md5 string), func(dataID string) int) {
+	var mu sync.Mutex
+	requests := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		dataID := r.URL.Query().Get("dataId")
+		requests[dataID]++
+		fmt.Fprintf(w, `{"code":0,"data":{"content":"x","md5":%q}}`, md5s[dataID])
+	}))
+	t.Cleanup(server.Close)
+	set := func(dataID, md5 string) {
+		mu.Lock()
+		md5s[dataID] = md5
+		mu.Unlock()
+	}
+	count := func(dataID string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return requests[dataID]
+	}
+	return strings.TrimPrefix(server.URL, "http://"), set, count
+}
+
+func TestBatchesHandleEachChangeOnce(t *testing.T) {
+	md5s := map[string]string{}
+	var items []ConfigItem
+	for i := 0; i < 5; i++ {
+		dataID := fmt.Sprintf("resource_%d", i)
+		md5s[dataID] = "v1"
+		items = append(items, ConfigItem{DataID: dataID, Group: "skill_a"})
+	}
+	addr, setMD5, requests := newMD5Server(t, md5s)
+	l := NewConfigListener(newClient(t, addr))
+	l.SetLogger(logging.Discard())
+	l.SetBatchSize(2)
+	l.SetPollInterval(10 * time.Millisecond)
+	l.Prime(items)
+	var cycles atomic.Int32
+	l.SetCycleHandler(func() { cycles.Add(1) })
+
+	var mu sync.Mutex
+	changes := make(map[string]int)
+	handler := func(dataID, group, tenant, change string) error {
+		mu.Lock()
+		changes[dataID]++
+		mu.Unlock()
+		if dataID == "resource_2" {
+			// Adding a config rebalances the batches
+			added := append(append([]ConfigItem{}, items...), ConfigItem{DataID: "resource_new", Group: "skill_a"})
+			l.Prime(added)
+			l.WatchGroup("", "skill_a", added)
+		}
+		return nil
+	}
+
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	go func() { done <- l.StartListening(items, handler, stop) }()
+	setMD5("resource_2", "v2")
+	time.Sleep(300 * time.Millisecond)
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatalf("StartListening() error = %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(changes) != 1 || changes["resource_2"] != 1 {
+		t.Errorf("changes = %v, want resource_2 handled once", changes)
+	}
+	if requests("resource_new") < 2 {
+		t.Errorf("resource_new polled %d times, want it watched after the rebalance", requests("resource_new"))
+	}
+	if cycles.Load() < 3 {
+		t.Errorf("cycle handler called %d times, want it called after every poll of each batch", cycles.Load())
+	}
+}
+
+func TestReconnectAfterServerRestart(t *testing.T) {
+	var down atomic.Bool
+	var logins int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nacos/v3/auth/user/login", func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&logins, 1)
+		fmt.Fprintf(w, `{"accessToken":"token-%d","tokenTtl":18000}`, n)
+	})
+	mux.HandleFunc("/nacos/v3/client/cs/config", func(w http.ResponseWriter, r *http.Request) {
+		if down.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("Authorization") != fmt.Sprintf("Bearer token-%d", atomic.LoadInt32(&logins)) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"code":0,"data":{"content":"{}","md5":"abc"}}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	addr := strings.TrimPrefix(server.URL, "http://")
+	c, err := client.NewNacosClient(addr, "", "", "nacos", "secret", "", "", "")
+	if err != nil {
+		t.Fatalf("NewNacosClient() error = %v", err)
+	}
+
+	var mu sync.Mutex
+	var logs []string
+	lost := make(chan struct{}, 1)
+	l := NewConfigListener(c)
+	l.SetLogger(slog.New(logging.NewConsoleHandler(func(msg string) {
+		mu.Lock()
+		defer mu.Unlock()
+		logs = append(logs, msg)
+		if strings.Contains(msg, "Connection to Nacos lost") {
+			select {
+			case lost <- struct{}{}:
+			default:
+			}
+		}
+	})))
+	l.SetPollInterval(5 * time.Millisecond)
+	reconnects := make(chan time.Duration, 1)
+	l.SetReconnectHandler(func(downtime time.Duration) { reconnects <- downtime })
+
+	// The server goes down and comes back with the old token invalidated
+	down.Store(true)
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	var changes int32
+	go func() {
+		done <- l.StartListening([]ConfigItem{{DataID: "skill.json", Group: "skill_demo", MD5: "abc"}}, func(string, string, string, string) error {
+			atomic.AddInt32(&changes, 1)
+			return nil
+		}, stop)
+	}()
+	select {
+	case <-lost:
+	case <-time.After(5 * time.Second):
+		t.Fatal("connection loss was not reported")
+	}
+	atomic.AddInt32(&logins, 1)
+	down.Store(false)
+
+	select {
+	case <-reconnects:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reconnect handler was not called")
+	}
+	close(stop)
+	if err := <-done; err != nil {
+		t.Errorf("StartListening() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&logins); got != 3 {
+		t.Errorf("logins = %d, want 3 (initial, revoked, relogin)", got)
+	}
+	if n := atomic.LoadInt32(&changes); n != 0 {
+		t.Errorf("handler called %d times for an unchanged config", n)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	all := strings.Join(logs, "\n")
+	if strings.Count(all, "Connection to Nacos lost") != 1 || !strings.Contains(all, "Reconnected to Nacos after") {
+		t.Errorf("logs = %q, want one lost and one reconnected entry", all)
+	}
+}
+
+func TestWaitForChange(t *testing.T) {
+	addr, setMD5, _ := newMD5Server(t, map[string]string{"app.yaml": "v1"})
+	l := NewConfigListener(newClient(t, addr))
+	l.SetLogger(logging.Discard())
+	l.SetPollInterval(10 * time.Millisecond)
+	item := ConfigItem{DataID: "app.yaml", Group: "DEFAULT_GROUP", MD5: "v1"}
+
+	// Nothing changes before the deadline
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if md5, err := l.WaitForChange(ctx, item); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("WaitForChange() = %q, %v; want a deadline error", md5, err)
+	}
+
+	time.AfterFunc(30*time.Millisecond, func() { setMD5("app.yaml", "v2") })
+	md5, err := l.WaitForChange(context.Background(), item)
+	if err != nil || md5 != "v2" {
+		t.Errorf("WaitForChange() = %q, %v; want v2", md5, err)
+	}
+
+	// A baseline that is already out of date returns at the first poll
+	md5, err = l.WaitForChange(context.Background(), ConfigItem{DataID: "app.yaml", Group: "DEFAULT_GROUP", MD5: "old"})
+	if err != nil || md5 != "v2" {
+		t.Errorf("WaitForChange() with a stale MD5 = %q, %v; want v2", md5, err)
+	}
+}
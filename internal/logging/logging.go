@@ -0,0 +1,204 @@
+// Package logging builds the slog loggers used across the CLI. The console
+// shows plain messages, plus the attributes of debug entries; a log file gets
+// structured entries with every attribute (skill, dataId, event, duration,
+// ...) and is rotated by size. Attributes holding secrets are redacted
+// everywhere.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+)
+
+// level is the minimum level every logger built here writes; see SetLevel
+var level = new(slog.LevelVar)
+
+// SetLevel sets the minimum level of every logger built by this package,
+// including ones built earlier. The default is info.
+func SetLevel(l slog.Level) {
+	level.Set(l)
+}
+
+// ParseLevel parses a --log-level value: debug, info, warn or error
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", s)
+	}
+	return l, nil
+}
+
+// redactedKeys are attribute key fragments whose values are never logged
+var redactedKeys = []string{"password", "secret", "token", "authorization"}
+
+// Redact replaces the value of an attribute whose key names a secret, such
+// as password or accessToken
+func Redact(a slog.Attr) slog.Attr {
+	key := strings.ToLower(a.Key)
+	for _, fragment := range redactedKeys {
+		if strings.Contains(key, fragment) {
+			return slog.String(a.Key, "[REDACTED]")
+		}
+	}
+	return a
+}
+
+// Log file formats accepted by NewHandler
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// consoleHandler prints the message of each record; debug records also get
+// their attributes
+type consoleHandler struct {
+	print func(msg string)
+	attrs []slog.Attr
+}
+
+// NewConsoleHandler returns a handler that passes the message of every record
+// at the level set by SetLevel or higher to print, for human-friendly output.
+// Attributes are left out except on debug records, which end in key=value
+// pairs.
+func NewConsoleHandler(print func(msg string)) slog.Handler {
+	return &consoleHandler{print: print}
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, l slog.Level) bool {
+	return l >= level.Level()
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	if r.Level >= slog.LevelInfo {
+		h.print(r.Message)
+		return nil
+	}
+	var b strings.Builder
+	b.WriteString(r.Message)
+	write := func(a slog.Attr) bool {
+		a = Redact(a)
+		fmt.Fprintf(&b, " %s=%v", a.Key, a.Value)
+		return true
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(write)
+	h.print(b.String())
+	return nil
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &consoleHandler{print: h.print, attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...)}
+}
+
+func (h *consoleHandler) WithGroup(string) slog.Handler { return h }
+
+// Stderr returns a logger printing "[15:04:05] message" lines to stderr,
+// keeping stdout for command output
+func Stderr() *slog.Logger {
+	return slog.New(NewConsoleHandler(func(msg string) {
+		fmt.Fprintf(os.Stderr, "[%s] %s\n", time.Now().Format("15:04:05"), msg)
+	}))
+}
+
+// Printf returns a logger that passes each message to a printf-style function
+func Printf(logf func(format string, args ...interface{})) *slog.Logger {
+	return slog.New(NewConsoleHandler(func(msg string) {
+		logf("%s", msg)
+	}))
+}
+
+// Discard returns a logger that drops everything
+func Discard() *slog.Logger {
+	return slog.New(NewConsoleHandler(func(string) {}))
+}
+
+// NewHandler returns a structured handler writing format ("text" or "json") to w
+func NewHandler(w io.Writer, format string) (slog.Handler, error) {
+	// Durations read "1.5s" rather than a count of nanoseconds
+	opts := &slog.HandlerOptions{Level: level, ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+		if a.Value.Kind() == slog.KindDuration {
+			a.Value = slog.StringValue(a.Value.Duration().String())
+		}
+		return Redact(a)
+	}}
+	switch format {
+	case "", FormatText:
+		return slog.NewTextHandler(w, opts), nil
+	case FormatJSON:
+		return slog.NewJSONHandler(w, opts), nil
+	default:
+		return nil, fmt.Errorf("unknown log format %q (use text or json)", format)
+	}
+}
+
+// Open returns a logger that sends each record to console and, if path is
+// set, in format to a log file rotated by size. The returned closer closes
+// the file.
+func Open(console slog.Handler, path, format string) (*slog.Logger, io.Closer, error) {
+	if path == "" {
+		return slog.New(console), io.NopCloser(nil), nil
+	}
+	file, err := OpenRotatingFile(path, DefaultMaxSize, DefaultMaxFiles)
+	if err != nil {
+		return nil, nil, err
+	}
+	handler, err := NewHandler(file, format)
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+	return slog.New(Tee(console, handler)), file, nil
+}
+
+// teeHandler sends every record to several handlers
+type teeHandler []slog.Handler
+
+// Tee returns a handler that sends every record to each of handlers
+func Tee(handlers ...slog.Handler) slog.Handler {
+	return teeHandler(handlers)
+}
+
+func (t teeHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range t {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (t teeHandler) Handle(ctx context.Context, r slog.Record) error {
+	var firstErr error
+	for _, h := range t {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (t teeHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return handlers
+}
+
+func (t teeHandler) WithGroup(name string) slog.Handler {
+	handlers := make(teeHandler, len(t))
+	for i, h := range t {
+		handlers[i] = h.WithGroup(name)
+	}
+	return handlers
+}
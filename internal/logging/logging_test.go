@@ -0,0 +1,158 @@
+package logging
+
+import (
+	"encoding/json"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestOpenWritesConsoleAndFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.log")
+	var console []string
+	logger, closer, err := Open(NewConsoleHandler(func(msg string) {
+		console = append(console, msg)
+	}), path, FormatJSON)
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	logger.With("skill", "demo").Info("Skill demo synced", "event", "synced")
+	logger.Debug("poll details")
+	closer.Close()
+
+	if len(console) != 1 || console[0] != "Skill demo synced" {
+		t.Errorf("console = %q, want just the message", console)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		t.Fatalf("log file is not one JSON entry: %v\n%s", err, data)
+	}
+	if entry["skill"] != "demo" || entry["event"] != "synced" || entry["msg"] != "Skill demo synced" {
+		t.Errorf("entry = %v", entry)
+	}
+}
+
+func TestSetLevel(t *testing.T) {
+	t.Cleanup(func() { SetLevel(slog.LevelInfo) })
+	var console []string
+	logger := slog.New(NewConsoleHandler(func(msg string) {
+		console = append(console, msg)
+	})).With("endpoint", "v3")
+
+	logger.Debug("hidden by default")
+	SetLevel(slog.LevelDebug)
+	logger.Debug("Logging in", "username", "nacos", "password", "secret", "accessToken", "token-1")
+	logger.Info("Logged in", "accessToken", "token-1")
+	SetLevel(slog.LevelWarn)
+	logger.Info("hidden at warn")
+
+	want := []string{"Logging in endpoint=v3 username=nacos password=[REDACTED] accessToken=[REDACTED]", "Logged in"}
+	if strings.Join(console, "\n") != strings.Join(want, "\n") {
+		t.Errorf("console = %q, want %q", console, want)
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    slog.Level
+		wantErr bool
+	}{
+		{"debug", slog.LevelDebug, false},
+		{"INFO", slog.LevelInfo, false},
+		{"warn", slog.LevelWarn, false},
+		{"error", slog.LevelError, false},
+		{"verbose", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseLevel(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseLevel(%q) = %v, %v", tt.input, got, err)
+		}
+	}
+}
+
+func TestFileRedactsSecrets(t *testing.T) {
+	var buf strings.Builder
+	handler, err := NewHandler(&buf, FormatText)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slog.New(handler).Info("Login", "Password", "secret", "secretKey", "sk", "user", "nacos")
+	if strings.Contains(buf.String(), "=secret") || strings.Contains(buf.String(), "=sk") || !strings.Contains(buf.String(), "user=nacos") {
+		t.Errorf("log entry = %q, want secrets redacted", buf.String())
+	}
+}
+
+func TestStderrKeepsStdoutClean(t *testing.T) {
+	stdout, stderr := os.Stdout, os.Stderr
+	outR, outW, _ := os.Pipe()
+	errR, errW, _ := os.Pipe()
+	os.Stdout, os.Stderr = outW, errW
+	Stderr().Info("Skill demo synced")
+	os.Stdout, os.Stderr = stdout, stderr
+	outW.Close()
+	errW.Close()
+
+	out, _ := io.ReadAll(outR)
+	logged, _ := io.ReadAll(errR)
+	if len(out) != 0 {
+		t.Errorf("stdout = %q, want nothing", out)
+	}
+	if !strings.HasSuffix(string(logged), "] Skill demo synced\n") {
+		t.Errorf("stderr = %q, want the message", logged)
+	}
+}
+
+func TestOpenRejectsUnknownFormat(t *testing.T) {
+	if _, _, err := Open(NewConsoleHandler(func(string) {}), filepath.Join(t.TempDir(), "sync.log"), "xml"); err == nil {
+		t.Error("Open() with format xml succeeded")
+	}
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "sync.log")
+	f, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+	}
+	f.Close()
+
+	// Each line fills a file, so the oldest one has been dropped
+	want := map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"}
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != content {
+			t.Errorf("%s = %q, want %q", filepath.Base(name), data, content)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("%s.3 exists, want at most 2 old files", filepath.Base(path))
+	}
+
+	// Reopening appends to the current file
+	f, err = OpenRotatingFile(path, 100, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Write([]byte("fifth\n"))
+	f.Close()
+	if data, _ := os.ReadFile(path); !strings.HasSuffix(string(data), "fourth\nfifth\n") {
+		t.Errorf("after reopening = %q, want fifth appended", data)
+	}
+}
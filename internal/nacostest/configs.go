@@ -0,0 +1,328 @@
+package nacostest
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultLongPollTimeout is how long the listener holds a request that names
+// no Long-Pulling-Timeout
+const defaultLongPollTimeout = 30 * time.Second
+
+// configKey identifies a config; the public namespace is stored as ""
+type configKey struct {
+	tenant, group, dataID string
+}
+
+// config is a stored config
+type config struct {
+	content string
+	md5     string
+	typ     string
+}
+
+// namespace returns tenant as the store keeps it
+func namespace(tenant string) string {
+	if tenant == publicNamespace {
+		return ""
+	}
+	return tenant
+}
+
+// SetConfig stores a config in the public namespace as if it was published
+func (s *Server) SetConfig(dataID, group, content string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putConfig(configKey{"", group, dataID}, content, "")
+}
+
+// SetConfigType sets the type of a config in the public namespace, e.g. yaml,
+// as the config list and the client config API report it
+func (s *Server) SetConfigType(dataID, group, typ string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if c, ok := s.configs[configKey{"", group, dataID}]; ok {
+		c.typ = typ
+	}
+}
+
+// Config returns the content of a config in the public namespace, and
+// whether it exists
+func (s *Server) Config(dataID, group string) (string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	c, ok := s.configs[configKey{"", group, dataID}]
+	if !ok {
+		return "", false
+	}
+	return c.content, true
+}
+
+// DeleteConfig deletes a config in the public namespace
+func (s *Server) DeleteConfig(dataID, group string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.removeConfig(configKey{"", group, dataID})
+}
+
+// putConfig stores a config and wakes the listeners. typ is kept from the
+// stored config when empty. s.mu must be held.
+func (s *Server) putConfig(key configKey, content, typ string) {
+	if old, ok := s.configs[key]; ok && typ == "" {
+		typ = old.typ
+	}
+	sum := md5.Sum([]byte(content))
+	s.configs[key] = &config{content: content, md5: hex.EncodeToString(sum[:]), typ: typ}
+	s.notify()
+}
+
+// removeConfig deletes a config and wakes the listeners if it existed. s.mu
+// must be held.
+func (s *Server) removeConfig(key configKey) bool {
+	if _, ok := s.configs[key]; !ok {
+		return false
+	}
+	delete(s.configs, key)
+	s.notify()
+	return true
+}
+
+// notify wakes every long-poll request so it checks its configs again. s.mu
+// must be held.
+func (s *Server) notify() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+// configCount returns how many configs are stored
+func (s *Server) configCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.configs)
+}
+
+// getConfig serves the v3 client config API
+func (s *Server) getConfig(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	key := configKey{namespace(q.Get("namespaceId")), q.Get("groupName"), q.Get("dataId")}
+	s.mu.Lock()
+	c, ok := s.configs[key]
+	s.mu.Unlock()
+	if !ok {
+		reply(w, http.StatusNotFound, codeNotFound, "config data not exist", nil)
+		return
+	}
+	reply(w, http.StatusOK, codeOK, "success", map[string]string{
+		"dataId": key.dataID, "groupName": key.group, "namespaceId": key.tenant,
+		"content": c.content, "md5": c.md5, "configType": c.typ,
+	})
+}
+
+// adminConfig serves publishing (POST) and deleting (DELETE) with the v3
+// admin config API
+func (s *Server) adminConfig(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	key := configKey{namespace(r.Form.Get("namespaceId")), r.Form.Get("groupName"), r.Form.Get("dataId")}
+	if key.dataID == "" || key.group == "" {
+		reply(w, http.StatusBadRequest, codeBadRequest, "dataId and groupName are required", nil)
+		return
+	}
+	switch r.Method {
+	case http.MethodPost:
+		s.mu.Lock()
+		// Like Nacos, a casMd5 that is not the current MD5 fails the publish
+		if cas := r.Form.Get("casMd5"); cas != "" {
+			if c, ok := s.configs[key]; !ok || c.md5 != cas {
+				s.mu.Unlock()
+				reply(w, http.StatusInternalServerError, codeServerError, "Cas publish fail, server md5 may have changed.", nil)
+				return
+			}
+		}
+		s.putConfig(key, r.Form.Get("content"), r.Form.Get("type"))
+		s.mu.Unlock()
+		reply(w, http.StatusOK, codeOK, "success", true)
+	case http.MethodDelete:
+		s.mu.Lock()
+		s.removeConfig(key)
+		s.mu.Unlock()
+		reply(w, http.StatusOK, codeOK, "success", true)
+	default:
+		reply(w, http.StatusMethodNotAllowed, codeBadRequest, "unsupported method "+r.Method, nil)
+	}
+}
+
+// listConfigs serves the v3 config list
+func (s *Server) listConfigs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	items := s.searchConfigs(q.Get("namespaceId"), q.Get("dataId"), q.Get("groupName"), q.Get("search") == "blur")
+	pageNo, _ := strconv.Atoi(q.Get("pageNo"))
+	pageSize, _ := strconv.Atoi(q.Get("pageSize"))
+	reply(w, http.StatusOK, codeOK, "success", page(items, pageNo, pageSize))
+}
+
+// v1Configs serves the v1 config API: the list when search is given, else a
+// config's raw content
+func (s *Server) v1Configs(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if search := q.Get("search"); search != "" {
+		items := s.searchConfigs(q.Get("tenant"), q.Get("dataId"), q.Get("group"), search == "blur")
+		pageNo, _ := strconv.Atoi(q.Get("pageNo"))
+		pageSize, _ := strconv.Atoi(q.Get("pageSize"))
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(page(items, pageNo, pageSize))
+		return
+	}
+	s.mu.Lock()
+	c, ok := s.configs[configKey{namespace(q.Get("tenant")), q.Get("group"), q.Get("dataId")}]
+	s.mu.Unlock()
+	if !ok {
+		http.Error(w, "config data not exist", http.StatusNotFound)
+		return
+	}
+	w.Header().Set("Config-Type", c.typ)
+	w.Header().Set("Content-MD5", c.md5)
+	w.Write([]byte(c.content))
+}
+
+// searchConfigs returns the configs of tenant that match dataID and group,
+// sorted by group and dataId. An empty pattern matches everything; in a blur
+// search * matches any run of characters, else a pattern matches itself.
+func (s *Server) searchConfigs(tenant, dataID, group string, blur bool) []map[string]string {
+	match := func(pattern, value string) bool {
+		if pattern == "" {
+			return true
+		}
+		if !blur {
+			return pattern == value
+		}
+		return wildcardMatch(pattern, value)
+	}
+	s.mu.Lock()
+	var keys []configKey
+	for key := range s.configs {
+		if key.tenant == namespace(tenant) && match(dataID, key.dataID) && match(group, key.group) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].group != keys[j].group {
+			return keys[i].group < keys[j].group
+		}
+		return keys[i].dataID < keys[j].dataID
+	})
+	items := make([]map[string]string, 0, len(keys))
+	for _, key := range keys {
+		c := s.configs[key]
+		items = append(items, map[string]string{
+			"dataId": key.dataID, "group": key.group, "groupName": key.group,
+			"tenant": key.tenant, "type": c.typ, "md5": c.md5,
+		})
+	}
+	s.mu.Unlock()
+	return items
+}
+
+// wildcardMatch reports whether value matches pattern, in which * stands for
+// any run of characters
+func wildcardMatch(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return value == pattern
+	}
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+	for _, part := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, part)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(part):]
+	}
+	return strings.HasSuffix(value, parts[len(parts)-1])
+}
+
+// listenedConfig is a config a long-poll request watches, with the MD5 the
+// client has of it ("" if it has none)
+type listenedConfig struct {
+	key configKey
+	md5 string
+}
+
+// listen serves the v1 long-poll listener. The Listening-Configs form field
+// lists dataId^2group^2md5[^2tenant] entries separated by ^1. The request is
+// answered as soon as a config's MD5 differs from the client's, or empty
+// after Long-Pulling-Timeout milliseconds; the body names the changed
+// configs as dataId^2group[^2tenant]^1 entries, URL-encoded.
+func (s *Server) listen(w http.ResponseWriter, r *http.Request) {
+	r.ParseForm()
+	var listened []listenedConfig
+	for _, entry := range strings.Split(r.Form.Get("Listening-Configs"), "\x01") {
+		fields := strings.Split(entry, "\x02")
+		if len(fields) < 3 {
+			continue
+		}
+		l := listenedConfig{key: configKey{group: fields[1], dataID: fields[0]}, md5: fields[2]}
+		if len(fields) > 3 {
+			l.key.tenant = namespace(fields[3])
+		}
+		listened = append(listened, l)
+	}
+	if len(listened) == 0 {
+		http.Error(w, "invalid probeModify", http.StatusBadRequest)
+		return
+	}
+	timeout := defaultLongPollTimeout
+	if ms, err := strconv.Atoi(r.Header.Get("Long-Pulling-Timeout")); err == nil && ms > 0 {
+		timeout = time.Duration(ms) * time.Millisecond
+	}
+
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	for {
+		s.mu.Lock()
+		changed := s.changedConfigs(listened)
+		wake := s.changed
+		s.mu.Unlock()
+		if changed != "" {
+			w.Write([]byte(url.QueryEscape(changed)))
+			return
+		}
+		select {
+		case <-wake:
+		case <-deadline.C:
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// changedConfigs returns the listened configs whose MD5 differs from the
+// client's, in the listener's response format. s.mu must be held.
+func (s *Server) changedConfigs(listened []listenedConfig) string {
+	var changed strings.Builder
+	for _, l := range listened {
+		current := ""
+		if c, ok := s.configs[l.key]; ok {
+			current = c.md5
+		}
+		if current == l.md5 {
+			continue
+		}
+		changed.WriteString(l.key.dataID + "\x02" + l.key.group)
+		if l.key.tenant != "" {
+			changed.WriteString("\x02" + l.key.tenant)
+		}
+		changed.WriteString("\x01")
+	}
+	return changed.String()
+}
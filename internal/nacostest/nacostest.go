@@ -0,0 +1,204 @@
+// Package nacostest runs a fake Nacos server in process for tests. It serves
+// the v1 and v3 login, config CRUD with accurate and blur search, the v1
+// long-poll listener, and the skill list, download and upload APIs from an
+// in-memory store that tests seed and inspect directly. Skills are stored in
+// the config layout as well (skill.json and resource configs in group
+// skill_<name>), so a skill syncer watching them sees every change.
+package nacostest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Nacos result codes the fake replies with
+const (
+	codeOK           = 0
+	codeBadRequest   = 400
+	codeUnauthorized = 403
+	codeServerError  = 500
+	codeNotFound     = 20004
+)
+
+const (
+	tokenTTLSeconds = 18000
+	defaultPageSize = 20
+	publicNamespace = "public"
+
+	// The config layout of a skill, as skill.UploadSkillViaConfig writes it
+	skillGroupPrefix = "skill_"
+	skillDataID      = "skill.json"
+	resourcePrefix   = "resource_"
+)
+
+// Server is a fake Nacos server. Its methods may be called while clients
+// use it.
+type Server struct {
+	// Addr is the server's host:port, as client.NewNacosClient takes it
+	Addr string
+	// URL is the server's base URL, e.g. http://127.0.0.1:51234
+	URL string
+
+	mu       sync.Mutex
+	username string // the only user login accepts; empty serves everyone
+	password string
+	token    string
+	configs  map[configKey]*config
+	skills   map[string]*storedSkill
+	failures map[string]int // path -> status every request to it fails with
+	requests map[string]int // path -> number of requests
+	changed  chan struct{}  // closed on every config change, see notify
+}
+
+// NewServer starts a fake Nacos server that is closed when the test ends
+func NewServer(t testing.TB) *Server {
+	t.Helper()
+	s := &Server{
+		configs:  make(map[configKey]*config),
+		skills:   make(map[string]*storedSkill),
+		failures: make(map[string]int),
+		requests: make(map[string]int),
+		changed:  make(chan struct{}),
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/nacos/v1/auth/login", s.login)
+	mux.HandleFunc("/nacos/v3/auth/user/login", s.login)
+	mux.HandleFunc("/nacos/v3/client/cs/config", s.authorized(s.getConfig))
+	mux.HandleFunc("/nacos/v3/admin/cs/config", s.authorized(s.adminConfig))
+	mux.HandleFunc("/nacos/v3/admin/cs/config/list", s.authorized(s.listConfigs))
+	mux.HandleFunc("/nacos/v1/cs/configs", s.authorized(s.v1Configs))
+	mux.HandleFunc("/nacos/v1/cs/configs/listener", s.authorized(s.listen))
+	mux.HandleFunc("/nacos/v3/admin/core/namespace/list", s.authorized(s.listNamespaces))
+	mux.HandleFunc("/nacos/v3/admin/ai/skills/list", s.authorized(s.listSkills))
+	mux.HandleFunc("/nacos/v3/admin/ai/skills/upload", s.authorized(s.uploadSkill))
+	mux.HandleFunc("/nacos/v3/client/ai/skills", s.authorized(s.getSkill))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests[r.URL.Path]++
+		status := s.failures[r.URL.Path]
+		s.mu.Unlock()
+		if status != 0 {
+			reply(w, status, status, "injected failure", nil)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+	s.URL = server.URL
+	s.Addr = strings.TrimPrefix(server.URL, "http://")
+	return s
+}
+
+// RequireLogin makes every request but login need the access token that
+// logging in as username with password returns
+func (s *Server) RequireLogin(username, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.username, s.password = username, password
+	s.token = randomHex(16)
+}
+
+// Fail makes every request to path, e.g. /nacos/v3/client/cs/config, fail
+// with status and a Nacos error body whose message is "injected failure".
+// Status 0 serves path again.
+func (s *Server) Fail(path string, status int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if status == 0 {
+		delete(s.failures, path)
+	} else {
+		s.failures[path] = status
+	}
+}
+
+// Requests returns how many requests were sent to path
+func (s *Server) Requests(path string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests[path]
+}
+
+// login serves the v1 and v3 login, which take the same form
+func (s *Server) login(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		reply(w, http.StatusMethodNotAllowed, codeBadRequest, "login needs POST", nil)
+		return
+	}
+	r.ParseForm()
+	s.mu.Lock()
+	ok := s.username == "" || r.Form.Get("username") == s.username && r.Form.Get("password") == s.password
+	token := s.token
+	s.mu.Unlock()
+	if !ok {
+		reply(w, http.StatusForbidden, codeUnauthorized, "user not found!", nil)
+		return
+	}
+	if token == "" {
+		token = "anonymous"
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]any{"accessToken": token, "tokenTtl": tokenTTLSeconds, "globalAdmin": true})
+}
+
+// authorized wraps a handler so that it refuses requests without the access
+// token once RequireLogin was called. The v3 APIs send the token as a Bearer
+// header, the v1 APIs as the accessToken parameter.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		token := s.token
+		s.mu.Unlock()
+		if token != "" && r.Header.Get("Authorization") != "Bearer "+token && r.FormValue("accessToken") != token {
+			reply(w, http.StatusForbidden, codeUnauthorized, "user not found!", nil)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// listNamespaces serves the namespace list with the public namespace only
+func (s *Server) listNamespaces(w http.ResponseWriter, r *http.Request) {
+	reply(w, http.StatusOK, codeOK, "success", []map[string]any{
+		{"namespace": "", "namespaceShowName": publicNamespace, "configCount": s.configCount()},
+	})
+}
+
+// reply writes a v3 response: status, and a body with code, message and data
+func reply(w http.ResponseWriter, status, code int, message string, data any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{"code": code, "message": message, "data": data})
+}
+
+// page is a page of items as the list APIs return it
+func page[T any](items []T, pageNo, pageSize int) map[string]any {
+	if pageNo < 1 {
+		pageNo = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultPageSize
+	}
+	pages := (len(items) + pageSize - 1) / pageSize
+	start := min((pageNo-1)*pageSize, len(items))
+	end := min(start+pageSize, len(items))
+	return map[string]any{
+		"totalCount":     len(items),
+		"pageNumber":     pageNo,
+		"pagesAvailable": pages,
+		"pageItems":      items[start:end],
+	}
+}
+
+// randomHex returns n random bytes in hex
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
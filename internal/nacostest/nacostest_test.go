@@ -0,0 +1,207 @@
+package nacostest
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+)
+
+func newClient(t *testing.T, s *Server, username, password string) *client.NacosClient {
+	t.Helper()
+	c, err := client.NewNacosClient(s.Addr, "", "", username, password, "", "", "")
+	if err != nil {
+		t.Fatalf("NewNacosClient() error = %v", err)
+	}
+	return c
+}
+
+func TestLogin(t *testing.T) {
+	s := NewServer(t)
+	s.RequireLogin("nacos", "secret")
+	s.SetConfig("app.yaml", "DEFAULT_GROUP", "port: 8080")
+
+	if _, err := newClient(t, s, "nacos", "wrong").GetConfig("app.yaml", "DEFAULT_GROUP"); !errors.Is(err, client.ErrLoginFailed) {
+		t.Errorf("GetConfig() with a wrong password error = %v, want ErrLoginFailed", err)
+	}
+	if _, err := newClient(t, s, "", "").GetConfig("app.yaml", "DEFAULT_GROUP"); err == nil {
+		t.Error("GetConfig() without a login succeeded")
+	}
+	got, err := newClient(t, s, "nacos", "secret").GetConfig("app.yaml", "DEFAULT_GROUP")
+	if err != nil || got != "port: 8080" {
+		t.Errorf("GetConfig() = %q, %v; want the config", got, err)
+	}
+}
+
+func TestConfigs(t *testing.T) {
+	s := NewServer(t)
+	c := newClient(t, s, "", "")
+	for _, dataID := range []string{"app.yaml", "app-dev.yaml", "db.properties"} {
+		if err := c.PublishConfig(dataID, "DEFAULT_GROUP", "content of "+dataID); err != nil {
+			t.Fatalf("PublishConfig(%s) error = %v", dataID, err)
+		}
+	}
+
+	if got, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err != nil || got != "content of app.yaml" {
+		t.Errorf("GetConfig() = %q, %v", got, err)
+	}
+	if _, err := c.GetConfig("app.yaml", "other"); !errors.Is(err, client.ErrConfigNotFound) {
+		t.Errorf("GetConfig() of a missing config error = %v, want ErrConfigNotFound", err)
+	}
+
+	tests := []struct {
+		dataID       string
+		pageNo       int
+		pageSize     int
+		want         []string
+		total, pages int
+	}{
+		{"app.yaml", 1, 10, []string{"app.yaml"}, 1, 1},
+		{"app", 1, 10, nil, 0, 0},
+		{"app*", 1, 10, []string{"app-dev.yaml", "app.yaml"}, 2, 1},
+		{"", 1, 2, []string{"app-dev.yaml", "app.yaml"}, 3, 2},
+		{"", 2, 2, []string{"db.properties"}, 3, 2},
+		{"*.yaml", 3, 1, nil, 2, 2},
+	}
+	for _, tt := range tests {
+		resp, err := c.ListConfigs(tt.dataID, "", "", tt.pageNo, tt.pageSize)
+		if err != nil {
+			t.Fatalf("ListConfigs(%q) error = %v", tt.dataID, err)
+		}
+		var got []string
+		for _, item := range resp.PageItems {
+			got = append(got, item.DataID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") || resp.TotalCount != tt.total || resp.PagesAvailable != tt.pages {
+			t.Errorf("ListConfigs(%q, page %d of %d) = %q, %d total, %d pages; want %q, %d, %d",
+				tt.dataID, tt.pageNo, tt.pageSize, got, resp.TotalCount, resp.PagesAvailable, tt.want, tt.total, tt.pages)
+		}
+	}
+
+	if err := c.DeleteConfig("app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Fatalf("DeleteConfig() error = %v", err)
+	}
+	if _, ok := s.Config("app.yaml", "DEFAULT_GROUP"); ok {
+		t.Error("config still stored after DeleteConfig()")
+	}
+}
+
+func TestFail(t *testing.T) {
+	s := NewServer(t)
+	s.SetConfig("app.yaml", "DEFAULT_GROUP", "port: 8080")
+	c := newClient(t, s, "", "")
+
+	s.Fail("/nacos/v3/client/cs/config", http.StatusInternalServerError)
+	if _, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err == nil {
+		t.Error("GetConfig() succeeded while failing")
+	}
+	s.Fail("/nacos/v3/client/cs/config", 0)
+	if _, err := c.GetConfig("app.yaml", "DEFAULT_GROUP"); err != nil {
+		t.Errorf("GetConfig() error = %v after failing stopped", err)
+	}
+	if got := s.Requests("/nacos/v3/client/cs/config"); got != 2 {
+		t.Errorf("Requests() = %d, want 2", got)
+	}
+}
+
+// longPoll sends a listener request for app.yaml with md5 and returns the
+// response body, or the error
+func longPoll(s *Server, md5 string, timeout time.Duration) string {
+	form := url.Values{"Listening-Configs": {"app.yaml\x02DEFAULT_GROUP\x02" + md5 + "\x01"}}
+	req, _ := http.NewRequest(http.MethodPost, s.URL+"/nacos/v1/cs/configs/listener", strings.NewReader(form.Encode()))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Long-Pulling-Timeout", strconv.FormatInt(timeout.Milliseconds(), 10))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err.Error()
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	return string(body)
+}
+
+func TestListener(t *testing.T) {
+	s := NewServer(t)
+	s.SetConfig("app.yaml", "DEFAULT_GROUP", "v1")
+	current := client.CalculateMD5("v1")
+	changed := url.QueryEscape("app.yaml\x02DEFAULT_GROUP\x01")
+
+	if got := longPoll(s, "stale", time.Minute); got != changed {
+		t.Errorf("poll with a stale MD5 = %q, want %q", got, changed)
+	}
+	if got := longPoll(s, current, 50*time.Millisecond); got != "" {
+		t.Errorf("poll without a change = %q, want nothing after the timeout", got)
+	}
+
+	// A publish releases a waiting poll
+	done := make(chan string, 1)
+	start := time.Now()
+	go func() { done <- longPoll(s, current, time.Minute) }()
+	time.Sleep(50 * time.Millisecond)
+	s.SetConfig("app.yaml", "DEFAULT_GROUP", "v2")
+	select {
+	case got := <-done:
+		if got != changed {
+			t.Errorf("poll released by a publish = %q, want %q", got, changed)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("poll not released by a publish after %s", time.Since(start))
+	}
+}
+
+func TestSkills(t *testing.T) {
+	s := NewServer(t)
+	c := newClient(t, s, "", "")
+	service := skill.NewSkillService(c)
+	service.SetLogger(logging.Discard())
+
+	dir := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(filepath.Join(dir, "scripts"), 0755)
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: demo\ndescription: A demo\n---\nv1"), 0644)
+	os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("echo hi"), 0755)
+	if _, err := service.UploadSkill(dir); err != nil {
+		t.Fatalf("UploadSkill() error = %v", err)
+	}
+	if files, ok := s.Skill("demo"); !ok || files["scripts/run.sh"] != "echo hi" {
+		t.Errorf("Skill() = %q, %v after the upload", files, ok)
+	}
+
+	items, total, err := service.ListSkills("", 1, 10)
+	if err != nil || total != 1 || items[0].Name != "demo" || items[0].Description != "A demo" {
+		t.Errorf("ListSkills() = %+v, %d, %v", items, total, err)
+	}
+
+	out := t.TempDir()
+	if err := service.GetSkill("demo", out, "", ""); err != nil {
+		t.Fatalf("GetSkill() error = %v", err)
+	}
+	// Windows has no exec bit to keep
+	info, err := os.Stat(filepath.Join(out, "demo", "scripts", "run.sh"))
+	if err != nil || runtime.GOOS != "windows" && info.Mode().Perm()&0100 == 0 {
+		t.Errorf("downloaded run.sh = %v, %v; want it executable", info, err)
+	}
+
+	// The config layout is written too
+	if _, ok := s.Config("skill.json", "skill_demo"); !ok {
+		t.Error("no skill.json in group skill_demo")
+	}
+	if _, ok := s.Config("resource_scripts_run.sh.json", "skill_demo"); !ok {
+		t.Error("no resource config for scripts/run.sh")
+	}
+
+	s.DeleteSkill("demo")
+	if err := service.GetSkill("demo", out, "", ""); !errors.Is(err, skill.ErrSkillNotFound) {
+		t.Errorf("GetSkill() of a deleted skill error = %v, want ErrSkillNotFound", err)
+	}
+}
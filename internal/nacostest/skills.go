@@ -0,0 +1,314 @@
+package nacostest
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// storedSkill is a skill with every version uploaded, oldest first
+type storedSkill struct {
+	tenant      string
+	description string
+	uniformID   string
+	versions    []skillVersion
+}
+
+// skillVersion is an uploaded version of a skill: its files by path relative
+// to the skill directory, SKILL.md included
+type skillVersion struct {
+	version string // from the frontmatter of SKILL.md; may be empty
+	files   map[string]skillFile
+}
+
+// skillFile is a file of a skill
+type skillFile struct {
+	data []byte
+	mode fs.FileMode
+}
+
+// invalidDataIDChars matches what Nacos does not accept in a dataId
+var invalidDataIDChars = regexp.MustCompile(`[^A-Za-z0-9._:-]`)
+
+// SetSkill stores a skill in the public namespace as if it was uploaded.
+// files maps paths relative to the skill directory to their content and
+// should include SKILL.md. The version in the frontmatter of SKILL.md is
+// added as the latest, replacing a version stored before under the same
+// name (or without one).
+func (s *Server) SetSkill(name string, files map[string]string) {
+	stored := make(map[string]skillFile, len(files))
+	for p, content := range files {
+		stored[p] = skillFile{data: []byte(content), mode: 0644}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.putSkill("", name, stored)
+}
+
+// Skill returns the files of the latest version of a skill, and whether the
+// skill exists
+func (s *Server) Skill(name string) (map[string]string, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sk, ok := s.skills[name]
+	if !ok {
+		return nil, false
+	}
+	files := make(map[string]string)
+	for p, f := range sk.versions[len(sk.versions)-1].files {
+		files[p] = string(f.data)
+	}
+	return files, true
+}
+
+// DeleteSkill deletes a skill with all its versions and its configs
+func (s *Server) DeleteSkill(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sk, ok := s.skills[name]
+	if !ok {
+		return
+	}
+	delete(s.skills, name)
+	for key := range s.configs {
+		if key.tenant == sk.tenant && key.group == skillGroupPrefix+name {
+			s.removeConfig(key)
+		}
+	}
+}
+
+// putSkill stores a version of a skill and writes it in the config layout:
+// the resources first, then skill.json, as a publish via config does. s.mu
+// must be held.
+func (s *Server) putSkill(tenant, name string, files map[string]skillFile) {
+	description, version := frontmatter(files["SKILL.md"].data)
+	sk, ok := s.skills[name]
+	if !ok {
+		sk = &storedSkill{uniformID: randomHex(16)}
+		s.skills[name] = sk
+	}
+	sk.tenant, sk.description = tenant, description
+	for i := range sk.versions {
+		if sk.versions[i].version == version {
+			// Replaced, and moved to the end as the latest
+			sk.versions = append(sk.versions[:i], sk.versions[i+1:]...)
+			break
+		}
+	}
+	sk.versions = append(sk.versions, skillVersion{version: version, files: files})
+
+	group := skillGroupPrefix + name
+	revision := randomHex(8)
+	skillJSON := map[string]any{
+		"namespaceId": tenant, "name": name, "description": description,
+		"uniformId": sk.uniformID, "revision": revision, "content": string(files["SKILL.md"].data),
+	}
+	var resources []string
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		if p == "SKILL.md" {
+			continue
+		}
+		f := files[p]
+		res := map[string]any{"type": "file", "name": p, "content": string(f.data)}
+		metadata := map[string]any{"path": p, "uniformId": sk.uniformID, "revision": revision}
+		if dir, file, ok := strings.Cut(p, "/"); ok {
+			res["type"], res["name"] = dir, file
+		}
+		if !utf8.Valid(f.data) {
+			res["content"] = base64.StdEncoding.EncodeToString(f.data)
+			metadata["encoding"] = "base64"
+		}
+		if f.mode.Perm()&0111 != 0 {
+			metadata["mode"] = fmt.Sprintf("%#o", f.mode.Perm())
+		}
+		res["metadata"] = metadata
+		dataID := resourcePrefix + invalidDataIDChars.ReplaceAllString(res["type"].(string)+"_"+res["name"].(string), "_") + ".json"
+		data, _ := json.Marshal(res)
+		s.putConfig(configKey{tenant, group, dataID}, string(data), "json")
+		resources = append(resources, dataID)
+	}
+	skillJSON["resources"] = resources
+	for key := range s.configs {
+		if key.tenant == tenant && key.group == group && strings.HasPrefix(key.dataID, resourcePrefix) && !slices.Contains(resources, key.dataID) {
+			s.removeConfig(key)
+		}
+	}
+	data, _ := json.Marshal(skillJSON)
+	s.putConfig(configKey{tenant, group, skillDataID}, string(data), "json")
+}
+
+// listSkills serves the skill list; skillName matches like a blur search
+func (s *Server) listSkills(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	s.mu.Lock()
+	items := []map[string]string{}
+	for name, sk := range s.skills {
+		if sk.tenant == namespace(q.Get("namespaceId")) && (q.Get("skillName") == "" || wildcardMatch(q.Get("skillName"), name)) {
+			items = append(items, map[string]string{"name": name, "description": sk.description})
+		}
+	}
+	s.mu.Unlock()
+	sort.Slice(items, func(i, j int) bool { return items[i]["name"] < items[j]["name"] })
+	pageNo, _ := strconv.Atoi(q.Get("pageNo"))
+	pageSize, _ := strconv.Atoi(q.Get("pageSize"))
+	reply(w, http.StatusOK, codeOK, "success", page(items, pageNo, pageSize))
+}
+
+// getSkill serves the client skill API: a ZIP of the skill's latest version,
+// or of the version asked for, with its MD5 as Content-MD5. Labels are not
+// supported, so a label is never found.
+func (s *Server) getSkill(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	name, version := q.Get("name"), q.Get("version")
+	s.mu.Lock()
+	var files map[string]skillFile
+	if sk, ok := s.skills[name]; ok && sk.tenant == namespace(q.Get("namespaceId")) && q.Get("label") == "" {
+		for _, v := range sk.versions {
+			if version == "" || v.version == version {
+				files = v.files
+			}
+		}
+	}
+	s.mu.Unlock()
+	if files == nil {
+		reply(w, http.StatusNotFound, codeNotFound, "skill not found: "+name, nil)
+		return
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	paths := make([]string, 0, len(files))
+	for p := range files {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	for _, p := range paths {
+		header := &zip.FileHeader{Name: name + "/" + p, Method: zip.Deflate}
+		header.SetMode(files[p].mode)
+		fw, err := zw.CreateHeader(header)
+		if err != nil {
+			reply(w, http.StatusInternalServerError, codeServerError, err.Error(), nil)
+			return
+		}
+		fw.Write(files[p].data)
+	}
+	zw.Close()
+	sum := md5.Sum(buf.Bytes())
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-MD5", hex.EncodeToString(sum[:]))
+	w.Write(buf.Bytes())
+}
+
+// uploadSkill serves the skill upload API: a ZIP in the multipart field file
+// whose entries are all under the skill's directory
+func (s *Server) uploadSkill(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		reply(w, http.StatusMethodNotAllowed, codeBadRequest, "upload needs POST", nil)
+		return
+	}
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		reply(w, http.StatusBadRequest, codeBadRequest, "no file: "+err.Error(), nil)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		reply(w, http.StatusBadRequest, codeBadRequest, err.Error(), nil)
+		return
+	}
+	name, files, err := readSkillZip(data)
+	if err != nil {
+		reply(w, http.StatusBadRequest, codeBadRequest, err.Error(), nil)
+		return
+	}
+
+	s.mu.Lock()
+	s.putSkill(namespace(r.URL.Query().Get("namespaceId")), name, files)
+	uniformID := s.skills[name].uniformID
+	s.mu.Unlock()
+	reply(w, http.StatusOK, codeOK, "success", map[string]any{"success": true, "uniformId": uniformID})
+}
+
+// readSkillZip returns the skill directory name and files of an uploaded ZIP
+func readSkillZip(data []byte) (string, map[string]skillFile, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", nil, fmt.Errorf("invalid zip: %w", err)
+	}
+	name := ""
+	files := make(map[string]skillFile)
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		dir, rel, ok := strings.Cut(path.Clean(f.Name), "/")
+		if !ok || name != "" && dir != name {
+			return "", nil, fmt.Errorf("%s is not in the skill directory", f.Name)
+		}
+		name = dir
+		rc, err := f.Open()
+		if err != nil {
+			return "", nil, err
+		}
+		content, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return "", nil, err
+		}
+		mode := f.Mode().Perm()
+		if mode == 0 {
+			mode = 0644
+		}
+		files[rel] = skillFile{data: content, mode: mode}
+	}
+	if _, ok := files["SKILL.md"]; !ok {
+		return "", nil, fmt.Errorf("no SKILL.md in the skill directory")
+	}
+	return name, files, nil
+}
+
+// frontmatter returns the description and version in the frontmatter of a
+// SKILL.md
+func frontmatter(md []byte) (description, version string) {
+	lines := strings.Split(string(md), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != "---" {
+		return "", ""
+	}
+	for _, line := range lines[1:] {
+		if strings.TrimSpace(line) == "---" {
+			break
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.Trim(strings.TrimSpace(value), `"'`)
+		switch strings.TrimSpace(key) {
+		case "description":
+			description = value
+		case "version":
+			version = value
+		}
+	}
+	return description, version
+}
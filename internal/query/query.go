@@ -0,0 +1,280 @@
+// Package query extracts a value from YAML or JSON config content with a
+// path such as .database.hosts[0].name, a small subset of what jq and yq
+// accept, so that scripts do not need either of them installed.
+package query
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Content types Decode understands, as highlight.DetectType names them
+const (
+	TypeJSON = "json"
+	TypeYAML = "yaml"
+)
+
+// ErrNotFound is returned by Lookup for a path that is not in the document
+var ErrNotFound = errors.New("not found")
+
+// step is one element of a path: a map key, or an index into a list
+type step struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+func (s step) String() string {
+	if s.isIndex {
+		return "[" + strconv.Itoa(s.index) + "]"
+	}
+	if s.key == "" || strings.ContainsAny(s.key, ".[]\"' ") {
+		return "[" + strconv.Quote(s.key) + "]"
+	}
+	return "." + s.key
+}
+
+// parsePath parses a path made of .key, ["key"] and [index] steps; the
+// leading dot is optional, and "." alone is the whole document. A negative
+// index counts from the end of the list.
+func parsePath(path string) ([]step, error) {
+	rest := strings.TrimSpace(path)
+	if rest == "" {
+		return nil, fmt.Errorf("empty query: use . for the whole document")
+	}
+	if rest == "." {
+		return nil, nil
+	}
+	if !strings.HasPrefix(rest, ".") && !strings.HasPrefix(rest, "[") {
+		rest = "." + rest
+	}
+	var steps []step
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			if strings.HasPrefix(rest, "[") {
+				continue
+			}
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid query %q: empty key", path)
+			}
+			steps = append(steps, step{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := closingBracket(rest)
+			if end < 0 {
+				return nil, fmt.Errorf("invalid query %q: missing ]", path)
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			rest = rest[end+1:]
+			if strings.HasPrefix(inner, `"`) || strings.HasPrefix(inner, "'") {
+				key, err := unquote(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid query %q: %v", path, err)
+				}
+				steps = append(steps, step{key: key})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil {
+				return nil, fmt.Errorf("invalid query %q: %q is not an index (quote keys: [\"key\"])", path, inner)
+			}
+			steps = append(steps, step{index: index, isIndex: true})
+		default:
+			return nil, fmt.Errorf("invalid query %q: expected . or [ before %q", path, rest)
+		}
+	}
+	return steps, nil
+}
+
+// closingBracket returns the index of the ] that closes the [ at the start
+// of s, skipping a quoted key
+func closingBracket(s string) int {
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote != 0 && s[i] == '\\':
+			i++
+		case quote != 0:
+			if s[i] == quote {
+				quote = 0
+			}
+		case s[i] == '"' || s[i] == '\'':
+			quote = s[i]
+		case s[i] == ']':
+			return i
+		}
+	}
+	return -1
+}
+
+func unquote(s string) (string, error) {
+	if strings.HasPrefix(s, "'") {
+		if len(s) < 2 || !strings.HasSuffix(s, "'") {
+			return "", fmt.Errorf("unterminated key %s", s)
+		}
+		return s[1 : len(s)-1], nil
+	}
+	return strconv.Unquote(s)
+}
+
+// Decode parses content of type typ, TypeJSON or TypeYAML. Maps are decoded
+// as map[string]any and lists as []any; JSON numbers keep their text. Of YAML
+// with several documents, only the first is decoded.
+func Decode(content, typ string) (any, error) {
+	var doc any
+	switch typ {
+	case TypeJSON:
+		dec := json.NewDecoder(strings.NewReader(content))
+		dec.UseNumber()
+		if err := dec.Decode(&doc); err != nil {
+			return nil, fmt.Errorf("parse JSON: %w", err)
+		}
+	case TypeYAML:
+		if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+			return nil, fmt.Errorf("parse YAML: %w", err)
+		}
+		doc = normalize(doc)
+	default:
+		return nil, fmt.Errorf("cannot query %s content: only YAML and JSON can be queried", typ)
+	}
+	return doc, nil
+}
+
+// normalize turns the maps with non-string keys that YAML allows into
+// map[string]any, and timestamps back into the text they were written as,
+// so the value can be marshaled as JSON
+func normalize(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for k, item := range v {
+			v[k] = normalize(item)
+		}
+		return v
+	case map[any]any:
+		m := make(map[string]any, len(v))
+		for k, item := range v {
+			m[fmt.Sprint(k)] = normalize(item)
+		}
+		return m
+	case []any:
+		for i, item := range v {
+			v[i] = normalize(item)
+		}
+		return v
+	case time.Time:
+		return v.Format(time.RFC3339Nano)
+	}
+	return v
+}
+
+// Lookup returns the value at path in doc, as returned by Decode. A path
+// that is not in doc is an error wrapping ErrNotFound that names the part of
+// the path that was found.
+func Lookup(doc any, path string) (any, error) {
+	steps, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	current := doc
+	at := "."
+	for i, s := range steps {
+		next, err := lookupStep(current, s)
+		if err != nil {
+			return nil, fmt.Errorf("%s %w: %s %v", path, ErrNotFound, at, err)
+		}
+		current = next
+		at = joinSteps(steps[:i+1])
+	}
+	return current, nil
+}
+
+func lookupStep(v any, s step) (any, error) {
+	if s.isIndex {
+		list, ok := v.([]any)
+		if !ok {
+			return nil, fmt.Errorf("is %s, not a list", kind(v))
+		}
+		index := s.index
+		if index < 0 {
+			index += len(list)
+		}
+		if index < 0 || index >= len(list) {
+			return nil, fmt.Errorf("has %d items", len(list))
+		}
+		return list[index], nil
+	}
+	m, ok := v.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("is %s, not a map", kind(v))
+	}
+	item, ok := m[s.key]
+	if !ok {
+		return nil, fmt.Errorf("has no key %q", s.key)
+	}
+	return item, nil
+}
+
+func joinSteps(steps []step) string {
+	var b strings.Builder
+	for _, s := range steps {
+		b.WriteString(s.String())
+	}
+	return b.String()
+}
+
+func kind(v any) string {
+	switch v.(type) {
+	case nil:
+		return "null"
+	case map[string]any:
+		return "a map"
+	case []any:
+		return "a list"
+	case string:
+		return "a string"
+	case bool:
+		return "a boolean"
+	}
+	return "a number"
+}
+
+// Text formats a value for scripts: strings without quotes, other scalars as
+// JSON writes them, and maps and lists as YAML or indented JSON, following
+// typ, the type of the content they came from
+func Text(v any, typ string) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return v, nil
+	case map[string]any, []any:
+		if typ == TypeYAML {
+			out, err := yaml.Marshal(v)
+			return strings.TrimSuffix(string(out), "\n"), err
+		}
+	}
+	return JSON(v)
+}
+
+// JSON formats a value as indented JSON, with map keys sorted
+func JSON(v any) (string, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(buf.String(), "\n"), nil
+}
@@ -0,0 +1,130 @@
+package query
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+const yamlDoc = `database:
+  host: db.internal
+  port: 5432
+  options: {ssl: true, "pool.size": 10}
+  replicas:
+    - name: r1
+    - name: r2
+released: 2024-05-01
+nothing: null
+`
+
+const jsonDoc = `{"features": {"beta": true, "limits": [10, 20.5, 12345678901234567890]}, "name": "app"}`
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		typ, path, want string
+	}{
+		{TypeYAML, ".database.host", "db.internal"},
+		{TypeYAML, "database.port", "5432"},
+		{TypeYAML, ".database.replicas[1].name", "r2"},
+		{TypeYAML, ".database.replicas[-1].name", "r2"},
+		{TypeYAML, `.database.options["pool.size"]`, "10"},
+		{TypeYAML, ".database.options['pool.size']", "10"},
+		{TypeYAML, ".database.options.ssl", "true"},
+		{TypeYAML, ".released", "2024-05-01T00:00:00Z"},
+		{TypeYAML, ".nothing", "null"},
+		{TypeYAML, ".database.replicas", "- name: r1\n- name: r2"},
+		{TypeJSON, ".features.limits[2]", "12345678901234567890"},
+		{TypeJSON, ".features.limits[1]", "20.5"},
+		{TypeJSON, ".name", "app"},
+		{TypeJSON, ".features", "{\n  \"beta\": true,\n  \"limits\": [\n    10,\n    20.5,\n    12345678901234567890\n  ]\n}"},
+	}
+	docs := map[string]string{TypeYAML: yamlDoc, TypeJSON: jsonDoc}
+	for _, tt := range tests {
+		t.Run(tt.typ+tt.path, func(t *testing.T) {
+			doc, err := Decode(docs[tt.typ], tt.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			v, err := Lookup(doc, tt.path)
+			if err != nil {
+				t.Fatalf("Lookup(%q) error = %v", tt.path, err)
+			}
+			got, err := Text(v, tt.typ)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Lookup(%q) = %q, want %q", tt.path, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLookupNotFound(t *testing.T) {
+	doc, err := Decode(yamlDoc, TypeYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		path, want string
+	}{
+		{".database.user", `.database.user not found: .database has no key "user"`},
+		{".database.replicas[5]", ".database.replicas[5] not found: .database.replicas has 2 items"},
+		{".database.host.name", ".database.host.name not found: .database.host is a string, not a map"},
+		{".database[0]", ".database[0] not found: .database is a map, not a list"},
+		{".missing.key", `.missing.key not found: . has no key "missing"`},
+	}
+	for _, tt := range tests {
+		_, err := Lookup(doc, tt.path)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup(%q) error = %v, want ErrNotFound", tt.path, err)
+			continue
+		}
+		if err.Error() != tt.want {
+			t.Errorf("Lookup(%q) error = %q, want %q", tt.path, err, tt.want)
+		}
+	}
+}
+
+func TestLookupInvalidPath(t *testing.T) {
+	for _, path := range []string{"", ".a..b", ".a.", ".a[x]", ".a[1", `.a["b]`} {
+		if _, err := Lookup(map[string]any{}, path); err == nil || errors.Is(err, ErrNotFound) {
+			t.Errorf("Lookup(%q) error = %v, want an invalid query", path, err)
+		}
+	}
+}
+
+func TestLookupWholeDocument(t *testing.T) {
+	doc, err := Decode("b: 1\na: [x]\n", TypeYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := Lookup(doc, ".")
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := JSON(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "{\n  \"a\": [\n    \"x\"\n  ],\n  \"b\": 1\n}"; got != want {
+		t.Errorf("JSON() = %q, want %q", got, want)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	if _, err := Decode("a=1\n", "properties"); err == nil || !strings.Contains(err.Error(), "only YAML and JSON") {
+		t.Errorf("Decode(properties) error = %v", err)
+	}
+	if _, err := Decode("{\"a\": ", TypeJSON); err == nil || !strings.Contains(err.Error(), "parse JSON") {
+		t.Errorf("Decode(truncated JSON) error = %v", err)
+	}
+	// Keys YAML allows but JSON does not are turned into strings
+	doc, err := Decode("1: one\ntrue: yes\n", TypeYAML)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, err := Lookup(doc, `["1"]`); err != nil || v != "one" {
+		t.Errorf(`Lookup(["1"]) = %v, %v`, v, err)
+	}
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Render runs content through Go's text/template with the given variables.
+// Referencing a variable that is not defined is a hard error (instead of
+// rendering "<no value>"), so a half-rendered config never reaches the server.
+//
+// Besides the variables, templates can call:
+//   - env "NAME"            value of an environment variable (error if unset)
+//   - envOr "NAME" "dflt"   value of an environment variable, or dflt if unset
+func Render(name, content string, vars map[string]interface{}) (string, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(template.FuncMap{
+			"env":   lookupEnv,
+			"envOr": lookupEnvOr,
+		}).
+		Parse(content)
+	if err != nil {
+		return "", fmt.Errorf("parse template: %w", err)
+	}
+
+	if vars == nil {
+		vars = map[string]interface{}{}
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return "", fmt.Errorf("render template: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// LoadVars builds the template variables from an optional YAML vars file and
+// a list of key=value overrides. Overrides take precedence over the file.
+func LoadVars(varFile string, overrides []string) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+
+	if varFile != "" {
+		data, err := os.ReadFile(varFile)
+		if err != nil {
+			return nil, fmt.Errorf("read var file %s: %w", varFile, err)
+		}
+		if err := yaml.Unmarshal(data, &vars); err != nil {
+			return nil, fmt.Errorf("parse var file %s: %w", varFile, err)
+		}
+		if vars == nil {
+			vars = map[string]interface{}{}
+		}
+	}
+
+	for _, kv := range overrides {
+		key, value, ok := strings.Cut(kv, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid --var %q (expected key=value)", kv)
+		}
+		vars[key] = value
+	}
+
+	return vars, nil
+}
+
+func lookupEnv(name string) (string, error) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s is not set", name)
+	}
+	return value, nil
+}
+
+func lookupEnvOr(name, fallback string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	return fallback
+}
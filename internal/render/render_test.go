@@ -0,0 +1,102 @@
+package render
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	t.Setenv("NACOS_CLI_TEST_REGION", "cn-hangzhou")
+
+	tests := []struct {
+		name    string
+		content string
+		vars    map[string]interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "simple variable",
+			content: "host: {{ .host }}",
+			vars:    map[string]interface{}{"host": "db.prod"},
+			want:    "host: db.prod",
+		},
+		{
+			name:    "nested variable",
+			content: "port: {{ .db.port }}",
+			vars:    map[string]interface{}{"db": map[string]interface{}{"port": 3306}},
+			want:    "port: 3306",
+		},
+		{
+			name:    "env function",
+			content: "region: {{ env \"NACOS_CLI_TEST_REGION\" }}",
+			want:    "region: cn-hangzhou",
+		},
+		{
+			name:    "envOr fallback",
+			content: "zone: {{ envOr \"NACOS_CLI_TEST_UNSET\" \"a\" }}",
+			want:    "zone: a",
+		},
+		{
+			name:    "missing variable is an error",
+			content: "host: {{ .host }}",
+			wantErr: true,
+		},
+		{
+			name:    "unset env is an error",
+			content: "x: {{ env \"NACOS_CLI_TEST_UNSET\" }}",
+			wantErr: true,
+		},
+		{
+			name:    "invalid template",
+			content: "x: {{ .host ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render("test", tt.content, tt.vars)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Render() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Render() = %q, want %q", got, tt.want)
+			}
+			if tt.wantErr && strings.Contains(got, "<no value>") {
+				t.Errorf("Render() leaked <no value> into output: %q", got)
+			}
+		})
+	}
+}
+
+func TestLoadVars(t *testing.T) {
+	dir := t.TempDir()
+	varFile := filepath.Join(dir, "vars.yaml")
+	if err := os.WriteFile(varFile, []byte("host: db.dev\nport: 3306\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	vars, err := LoadVars(varFile, []string{"host=db.prod", "env=prod"})
+	if err != nil {
+		t.Fatalf("LoadVars() error = %v", err)
+	}
+	if vars["host"] != "db.prod" {
+		t.Errorf("override not applied: host = %v", vars["host"])
+	}
+	if vars["port"] != 3306 {
+		t.Errorf("var file value lost: port = %v", vars["port"])
+	}
+	if vars["env"] != "prod" {
+		t.Errorf("env = %v, want prod", vars["env"])
+	}
+
+	if _, err := LoadVars("", []string{"novalue"}); err == nil {
+		t.Error("expected error for --var without '='")
+	}
+	if _, err := LoadVars(filepath.Join(dir, "missing.yaml"), nil); err == nil {
+		t.Error("expected error for missing var file")
+	}
+}
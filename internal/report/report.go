@@ -0,0 +1,95 @@
+// Package report writes the JSON report a batch command leaves with
+// --report, so CI can fail a build on partial failures and chart durations
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// Version is the version of the report schema. Fields may be added without
+// changing it; it changes when a field is removed or changes meaning.
+const Version = 1
+
+// Report is what --report writes, e.g.
+//
+//	{
+//	  "version": 1,
+//	  "command": "skill-publish",
+//	  "startedAt": "2026-10-16T03:00:00Z",
+//	  "durationMs": 5230,
+//	  "total": 2,
+//	  "failed": 1,
+//	  "counts": {"failed": 1, "published": 1},
+//	  "bytes": 20480,
+//	  "items": [
+//	    {"name": "demo", "status": "published", "failed": false, "durationMs": 420, "bytes": 20480,
+//	     "requests": [{"method": "POST", "path": "/nacos/v3/admin/ai/skills/upload", "status": 200, "requestId": "..."}]},
+//	    {"name": "broken", "status": "failed", "failed": true, "error": "...", "durationMs": 35, "bytes": 0,
+//	     "requests": [{"method": "POST", "path": "/nacos/v3/admin/ai/skills/upload", "status": 500, "requestId": "...", "serverRequestId": "..."}]}
+//	  ]
+//	}
+type Report struct {
+	Version    int            `json:"version"`
+	Command    string         `json:"command"`
+	StartedAt  time.Time      `json:"startedAt"`
+	DurationMs int64          `json:"durationMs"`
+	Total      int            `json:"total"`
+	Failed     int            `json:"failed"` // items with Failed set
+	Counts     map[string]int `json:"counts"` // items by status
+	Bytes      int64          `json:"bytes"`  // transferred for all items
+	Items      []Item         `json:"items"`
+
+	now func() time.Time
+}
+
+// Item is the outcome of one item of a batch, such as one skill
+type Item struct {
+	Name       string    `json:"name"`
+	Status     string    `json:"status"` // as the command reports it, e.g. published or failed
+	Failed     bool      `json:"failed"` // whether the status counts as a failure, as for the exit code
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durationMs"`
+	Bytes      int64     `json:"bytes"`              // transferred, e.g. uploaded
+	Requests   []Request `json:"requests,omitempty"` // sent to Nacos for the item, in order
+}
+
+// Request is a request sent to Nacos for an item, with the ids its admins
+// look it up by in the server's logs
+type Request struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	Status   int    `json:"status"`
+	ID       string `json:"requestId"`                 // sent as X-Request-Id
+	ServerID string `json:"serverRequestId,omitempty"` // the request or trace id the server sent back
+}
+
+// New starts the report of command
+func New(command string) *Report {
+	r := &Report{Version: Version, Command: command, Counts: make(map[string]int), Items: []Item{}, now: time.Now}
+	r.StartedAt = r.now().UTC().Truncate(time.Millisecond)
+	return r
+}
+
+// Add records an item and took, how long it took
+func (r *Report) Add(item Item, took time.Duration) {
+	item.DurationMs = took.Milliseconds()
+	r.Items = append(r.Items, item)
+	r.Total++
+	if item.Failed {
+		r.Failed++
+	}
+	r.Counts[item.Status]++
+	r.Bytes += item.Bytes
+}
+
+// Write saves the report to path, with the duration up to now
+func (r *Report) Write(path string) error {
+	r.DurationMs = r.now().Sub(r.StartedAt).Milliseconds()
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
@@ -0,0 +1,78 @@
+package report
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	clock := time.Date(2026, 10, 16, 3, 0, 0, 0, time.UTC)
+	r := New("skill-publish")
+	r.now = func() time.Time { return clock }
+	r.StartedAt = clock
+
+	r.Add(Item{Name: "demo", Status: "published", Bytes: 2048}, 420*time.Millisecond)
+	r.Add(Item{Name: "broken", Status: "failed", Failed: true, Error: "upload failed: 500",
+		Requests: []Request{{Method: "POST", Path: "/nacos/v3/admin/ai/skills/upload", Status: 500, ID: "id-1", ServerID: "trace-1"}}}, 35*time.Millisecond)
+	r.Add(Item{Name: "other", Status: "published", Bytes: 1024}, time.Second)
+	clock = clock.Add(5230 * time.Millisecond)
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := r.Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("report is not JSON: %v\n%s", err, data)
+	}
+	want := map[string]any{
+		"version":    1.0,
+		"command":    "skill-publish",
+		"startedAt":  "2026-10-16T03:00:00Z",
+		"durationMs": 5230.0,
+		"total":      3.0,
+		"failed":     1.0,
+		"counts":     map[string]any{"published": 2.0, "failed": 1.0},
+		"bytes":      3072.0,
+	}
+	for key, value := range want {
+		if !reflect.DeepEqual(got[key], value) {
+			t.Errorf("%s = %v, want %v", key, got[key], value)
+		}
+	}
+	items := got["items"].([]any)
+	broken := items[1].(map[string]any)
+	if len(items) != 3 || broken["error"] != "upload failed: 500" || broken["failed"] != true || broken["durationMs"] != 35.0 {
+		t.Errorf("items = %v", items)
+	}
+	if _, ok := items[0].(map[string]any)["error"]; ok {
+		t.Errorf("item without an error has an error field: %v", items[0])
+	}
+	if _, ok := items[0].(map[string]any)["requests"]; ok {
+		t.Errorf("item without requests has a requests field: %v", items[0])
+	}
+	wantRequests := []any{map[string]any{"method": "POST", "path": "/nacos/v3/admin/ai/skills/upload", "status": 500.0, "requestId": "id-1", "serverRequestId": "trace-1"}}
+	if !reflect.DeepEqual(broken["requests"], wantRequests) {
+		t.Errorf("requests = %v, want %v", broken["requests"], wantRequests)
+	}
+}
+
+func TestEmptyReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := New("skill-migrate").Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	data, _ := os.ReadFile(path)
+	var got Report
+	if err := json.Unmarshal(data, &got); err != nil || got.Items == nil || got.Counts == nil {
+		t.Errorf("empty report = %s, want empty items and counts rather than null", data)
+	}
+}
@@ -0,0 +1,145 @@
+package skill
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+// FileRemoved is the status of a file the installed version of a skill has
+// and a new version no longer does
+const FileRemoved = "removed"
+
+const (
+	// maxDiffSize bounds the files Changes diffs; larger ones are only
+	// reported as modified
+	maxDiffSize = 256 << 10
+	// summaryNames is how many files of each kind SummarizeChanges names
+	summaryNames = 3
+)
+
+// FileChange is how one file differs between the installed version of a
+// skill and a new one
+type FileChange struct {
+	Path   string `json:"path"`           // relative to the skill directory, slash-separated
+	Status string `json:"status"`         // FileAdded, FileModified or FileRemoved
+	Diff   string `json:"diff,omitempty"` // unified diff of a modified text file, if asked for
+}
+
+// archiveFile is a file of the archive as Changes compares it
+type archiveFile struct {
+	sum     [sha256.Size]byte
+	content []byte // kept for diffing; nil when not diffing or too large
+}
+
+// Changes compares the archive with the installed version of the skill in
+// skillDir, file by file, skipping hidden files as Hash does. With diffs,
+// modified text files come with a unified diff. Every file is added when
+// skillDir does not exist.
+func (a *SkillArchive) Changes(skillDir string, diffs bool) ([]FileChange, error) {
+	files := make(map[string]archiveFile)
+	for _, f := range a.reader.File {
+		rel := skillRelPath(f.Name)
+		if f.FileInfo().IsDir() || hiddenPath(rel) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open zip entry %s: %w", f.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read zip entry %s: %w", f.Name, err)
+		}
+		file := archiveFile{sum: sha256.Sum256(data)}
+		if diffs && len(data) <= maxDiffSize {
+			file.content = data
+		}
+		files[rel] = file
+	}
+
+	var changes []FileChange
+	seen := make(map[string]bool)
+	err := filepath.WalkDir(skillDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(skillDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && hiddenPath(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		file, ok := files[rel]
+		if !ok {
+			changes = append(changes, FileChange{Path: rel, Status: FileRemoved})
+			return nil
+		}
+		seen[rel] = true
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if sha256.Sum256(data) == file.sum {
+			return nil
+		}
+		change := FileChange{Path: rel, Status: FileModified}
+		if file.content != nil && len(data) <= maxDiffSize && !util.LooksBinary(string(data)) && !util.LooksBinary(string(file.content)) {
+			change.Diff = util.UnifiedDiff("a/"+rel, "b/"+rel, string(data), string(file.content))
+		}
+		changes = append(changes, change)
+		return nil
+	})
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	for rel := range files {
+		if !seen[rel] {
+			changes = append(changes, FileChange{Path: rel, Status: FileAdded})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Path < changes[j].Path })
+	return changes, nil
+}
+
+// SummarizeChanges describes changes on one line, e.g.
+// "1 added (run.sh), 1 modified (SKILL.md)", naming a few files of each kind
+func SummarizeChanges(changes []FileChange) string {
+	if len(changes) == 0 {
+		return "no files changed"
+	}
+	var parts []string
+	for _, status := range []string{FileAdded, FileModified, FileRemoved} {
+		var names []string
+		for _, c := range changes {
+			if c.Status == status {
+				names = append(names, c.Path)
+			}
+		}
+		if len(names) == 0 {
+			continue
+		}
+		listed := strings.Join(names, ", ")
+		if len(names) > summaryNames {
+			listed = fmt.Sprintf("%s and %d more", strings.Join(names[:summaryNames], ", "), len(names)-summaryNames)
+		}
+		parts = append(parts, fmt.Sprintf("%d %s (%s)", len(names), status, listed))
+	}
+	return strings.Join(parts, ", ")
+}
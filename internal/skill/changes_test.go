@@ -0,0 +1,89 @@
+package skill
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArchiveChanges(t *testing.T) {
+	dir := t.TempDir()
+	v1 := newTestArchive(t, map[string]string{
+		"demo/SKILL.md":       "# Demo\nstep one\nstep two\n",
+		"demo/run.sh":         "echo hi",
+		"demo/logo.png":       "\x89PNG\x00v1",
+		"demo/docs/old.md":    "old",
+		"demo/docs/guide.md":  "guide",
+		"demo/.hidden/config": "v1",
+	})
+	if _, err := v1.Extract(dir); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	skillDir := filepath.Join(dir, "demo")
+
+	v2 := newTestArchive(t, map[string]string{
+		"demo/SKILL.md":       "# Demo\nstep one\nstep 2\n",
+		"demo/run.sh":         "echo hi",
+		"demo/logo.png":       "\x89PNG\x00v2",
+		"demo/docs/new.md":    "new",
+		"demo/docs/guide.md":  "guide",
+		"demo/.hidden/config": "v2",
+	})
+	changes, err := v2.Changes(skillDir, false)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	want := []FileChange{
+		{Path: "SKILL.md", Status: FileModified},
+		{Path: "docs/new.md", Status: FileAdded},
+		{Path: "docs/old.md", Status: FileRemoved},
+		{Path: "logo.png", Status: FileModified},
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Errorf("Changes() = %+v, want %+v", changes, want)
+	}
+
+	changes, err = v2.Changes(skillDir, true)
+	if err != nil {
+		t.Fatalf("Changes() error = %v", err)
+	}
+	if diff := changes[0].Diff; !strings.Contains(diff, "--- a/SKILL.md") || !strings.Contains(diff, "-step two\n+step 2\n") {
+		t.Errorf("diff of SKILL.md = %q", diff)
+	}
+	if changes[3].Diff != "" {
+		t.Errorf("binary logo.png diffed as %q", changes[3].Diff)
+	}
+
+	changes, err = v2.Changes(filepath.Join(dir, "missing"), false)
+	if err != nil || len(changes) != 5 || changes[0].Status != FileAdded {
+		t.Errorf("Changes() against a missing directory = %+v, %v; want 5 added files", changes, err)
+	}
+}
+
+func TestSummarizeChanges(t *testing.T) {
+	tests := []struct {
+		changes []FileChange
+		want    string
+	}{
+		{nil, "no files changed"},
+		{[]FileChange{{Path: "SKILL.md", Status: FileModified}}, "1 modified (SKILL.md)"},
+		{[]FileChange{
+			{Path: "a.md", Status: FileRemoved},
+			{Path: "b.md", Status: FileAdded},
+			{Path: "c.md", Status: FileModified},
+		}, "1 added (b.md), 1 modified (c.md), 1 removed (a.md)"},
+		{[]FileChange{
+			{Path: "1.md", Status: FileAdded},
+			{Path: "2.md", Status: FileAdded},
+			{Path: "3.md", Status: FileAdded},
+			{Path: "4.md", Status: FileAdded},
+			{Path: "5.md", Status: FileAdded},
+		}, "5 added (1.md, 2.md, 3.md and 2 more)"},
+	}
+	for _, tt := range tests {
+		if got := SummarizeChanges(tt.changes); got != tt.want {
+			t.Errorf("SummarizeChanges(%+v) = %q, want %q", tt.changes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package skill
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+)
+
+// In the config layout a skill is stored in group skill_<name> as a
+// skill.json plus one resource_<type>_<name>.json per file other than
+// SKILL.md. It lets skills be published to servers without the skill
+// upload API; DownloadSkill reads it back when the skill API is missing.
+const (
+	configGroupPrefix = "skill_"
+	configSkillDataID = "skill.json"
+)
+
+// skillConfig is the content of skill.json
+type skillConfig struct {
+	NamespaceID string `json:"namespaceId"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	UniformID   string `json:"uniformId"`
+	// Revision is new for every upload and recorded in the metadata of
+	// each resource, so a reader can tell resources of another upload
+	Revision string `json:"revision,omitempty"`
+	Content  string `json:"content"` // SKILL.md
+	// Resources lists the dataIds of the skill's resource configs; configs
+	// left over from an earlier upload are not listed and so are ignored
+	Resources []string `json:"resources,omitempty"`
+}
+
+// skillResource is the content of a resource config, shaped like the
+// resources of an agentspec. Type is the file's top-level directory and Name
+// the rest of its path; the metadata path holds the whole relative path.
+type skillResource struct {
+	Name     string         `json:"name"`
+	Type     string         `json:"type"`
+	Content  string         `json:"content"`
+	Metadata map[string]any `json:"metadata,omitempty"`
+}
+
+// UniformCheck controls how a skill read back from the config layout is
+// checked for resources of a different upload, as a reader sees while the
+// skill is being republished.
+type UniformCheck struct {
+	Retries int           // re-reads after a mismatch before giving up
+	Delay   time.Duration // before the first re-read, doubled for each next one
+	Skip    bool          // accept the skill as read despite a mismatch
+}
+
+// DefaultUniformCheck re-reads a skill three times, after 1s, 2s and 4s
+var DefaultUniformCheck = UniformCheck{Retries: 3, Delay: time.Second}
+
+// UniformMismatchError is returned by DownloadSkill when a resource of a skill
+// stored in the config layout still disagrees with its skill.json after all
+// retries. IDs are shown as uniformId/revision.
+type UniformMismatchError struct {
+	Skill      string
+	Resource   string // dataId
+	SkillID    string
+	ResourceID string
+}
+
+func (e *UniformMismatchError) Error() string {
+	return fmt.Sprintf("resource %s of skill %s is from upload %s but skill.json from upload %s; "+
+		"publish the skill again to repair it, or pass --skip-uniform-check to accept it as is",
+		e.Resource, e.Skill, e.ResourceID, e.SkillID)
+}
+
+// rootResourceType is the type of files directly in the skill directory
+const rootResourceType = "file"
+
+// invalidDataIDChars matches what Nacos does not accept in a dataId
+var invalidDataIDChars = regexp.MustCompile(`[^A-Za-z0-9._:-]`)
+
+// resourceDataID returns the dataId of a resource, e.g.
+// resource_scripts_lib_run.sh.json for scripts/lib/run.sh
+func resourceDataID(res skillResource) string {
+	return "resource_" + invalidDataIDChars.ReplaceAllString(res.Type+"_"+res.Name, "_") + ".json"
+}
+
+// UploadSkillViaConfig publishes a skill directory in the config layout
+// through the config API, for servers without the skill upload API. The
+// skill's uniformId is kept if it was published this way before. Resources
+// are published before skill.json, so a syncer woken by skill.json finds
+// them all.
+func (s *SkillService) UploadSkillViaConfig(skillPath, version string) (*UploadResult, error) {
+	if strings.HasSuffix(strings.ToLower(skillPath), ".zip") {
+		return nil, fmt.Errorf("a .zip cannot be published via config; publish its directory")
+	}
+	name := filepath.Base(skillPath)
+	group := configGroupPrefix + name
+	md, err := os.ReadFile(filepath.Join(skillPath, "SKILL.md"))
+	if err != nil {
+		return nil, err
+	}
+	if version != "" {
+		if md, err = SetFrontmatterVersion(md, version); err != nil {
+			return nil, err
+		}
+	}
+	info, err := ParseFrontmatter(md)
+	if err != nil {
+		return nil, err
+	}
+
+	resources, err := dirResources(skillPath)
+	if err != nil {
+		return nil, err
+	}
+	skillJSON := skillConfig{NamespaceID: s.client.Namespace, Name: name, Description: info.Description, Content: string(md)}
+	owners := make(map[string]string)
+	for _, res := range resources {
+		dataID := resourceDataID(res)
+		if other, ok := owners[dataID]; ok {
+			return nil, fmt.Errorf("%s and %s would both be stored as %s; rename one of them", other, path.Join(res.Type, res.Name), dataID)
+		}
+		owners[dataID] = path.Join(res.Type, res.Name)
+		skillJSON.Resources = append(skillJSON.Resources, dataID)
+	}
+
+	skillJSON.UniformID, err = s.uniformID(group)
+	if err != nil {
+		return nil, err
+	}
+	if skillJSON.Revision, err = randomHex(8); err != nil {
+		return nil, err
+	}
+	progress := s.progress("Publishing "+name, int64(len(resources)), "resources")
+	defer progress.Done()
+	var sent int64
+	for _, res := range resources {
+		progress.Add(1)
+		res.Metadata["uniformId"] = skillJSON.UniformID
+		res.Metadata["revision"] = skillJSON.Revision
+		data, err := json.Marshal(res)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.client.PublishConfig(resourceDataID(res), group, string(data)); err != nil {
+			return nil, fmt.Errorf("publish %s: %w", owners[resourceDataID(res)], err)
+		}
+		sent += int64(len(data))
+	}
+	data, err := json.Marshal(skillJSON)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.client.PublishConfig(configSkillDataID, group, string(data)); err != nil {
+		return nil, fmt.Errorf("publish %s: %w", configSkillDataID, err)
+	}
+	return &UploadResult{UniformID: skillJSON.UniformID, Bytes: sent + int64(len(data))}, nil
+}
+
+// uniformID returns the uniformId of a skill already published in the config
+// layout, or a new random one
+func (s *SkillService) uniformID(group string) (string, error) {
+	content, err := s.client.GetConfig(configSkillDataID, group)
+	if err == nil {
+		var existing skillConfig
+		if json.Unmarshal([]byte(content), &existing) == nil && existing.UniformID != "" {
+			return existing.UniformID, nil
+		}
+	} else if !errors.Is(err, client.ErrConfigNotFound) {
+		return "", err
+	}
+	return randomHex(16)
+}
+
+// randomHex returns n random bytes in hex
+func randomHex(n int) (string, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// dirResources returns a resource for every file of a skill directory but
+// SKILL.md and hidden files. Content that is not UTF-8 is base64-encoded,
+// and executable files record their mode.
+func dirResources(skillPath string) ([]skillResource, error) {
+	var resources []skillResource
+	err := filepath.WalkDir(skillPath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(skillPath, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && hiddenPath(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() || rel == "SKILL.md" {
+			return nil
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		res := skillResource{Type: rootResourceType, Name: rel, Content: string(data), Metadata: map[string]any{"path": rel}}
+		if dir, file, ok := strings.Cut(rel, "/"); ok {
+			res.Type, res.Name = dir, file
+		}
+		if !utf8.Valid(data) {
+			res.Content = base64.StdEncoding.EncodeToString(data)
+			res.Metadata["encoding"] = "base64"
+		}
+		if info.Mode().Perm()&0111 != 0 {
+			res.Metadata["mode"] = fmt.Sprintf("%#o", info.Mode().Perm())
+		}
+		resources = append(resources, res)
+		return nil
+	})
+	return resources, err
+}
+
+// downloadFromConfigs reads a skill stored in the config layout into an
+// archive, as if the skill API had served it. It returns
+// client.ErrConfigNotFound (wrapped) if the skill has no skill.json. A read
+// that mixes two uploads is retried as s.uniformCheck says.
+func (s *SkillService) downloadFromConfigs(name string) (*SkillArchive, error) {
+	check := s.uniformCheck
+	delay := check.Delay
+	for attempt := 0; ; attempt++ {
+		archive, err := s.readConfigLayout(name, check.Skip)
+		var mismatch *UniformMismatchError
+		if !errors.As(err, &mismatch) || attempt >= check.Retries {
+			return archive, err
+		}
+		s.log.Debug(fmt.Sprintf("Skill %s is being republished (%v); retrying in %s (%d/%d)", name, err, delay, attempt+1, check.Retries),
+			"skill", name, "attempt", attempt+1, "delay", delay)
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// readConfigLayout reads a skill stored in the config layout once. Unless
+// skipCheck is set, it fails with a *UniformMismatchError on a resource that
+// records another upload than skill.json; resources that record none, from
+// older uploads, are not checked.
+func (s *SkillService) readConfigLayout(name string, skipCheck bool) (*SkillArchive, error) {
+	group := configGroupPrefix + name
+	config, err := s.client.GetConfigDetail(configSkillDataID, group)
+	if err != nil {
+		return nil, err
+	}
+	s.limiter.Wait(len(config.Content))
+	check := config.MD5Check
+	var skillJSON skillConfig
+	if err := json.Unmarshal([]byte(config.Content), &skillJSON); err != nil {
+		return nil, fmt.Errorf("parse %s of skill %s: %w", configSkillDataID, name, err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	if err := addZipFile(zw, name+"/SKILL.md", []byte(skillJSON.Content), 0644); err != nil {
+		return nil, err
+	}
+	progress := s.progress("Downloading "+name, int64(len(skillJSON.Resources)), "resources")
+	defer progress.Done()
+	for _, dataID := range skillJSON.Resources {
+		progress.Add(1)
+		config, err := s.client.GetConfigDetail(dataID, group)
+		if err != nil {
+			return nil, fmt.Errorf("get resource %s of skill %s: %w", dataID, name, err)
+		}
+		s.limiter.Wait(len(config.Content))
+		if check == client.MD5Verified {
+			// Verified only if every config was
+			check = config.MD5Check
+		}
+		var res skillResource
+		if err := json.Unmarshal([]byte(config.Content), &res); err != nil {
+			return nil, fmt.Errorf("parse resource %s of skill %s: %w", dataID, name, err)
+		}
+		if !skipCheck {
+			if err := checkUniform(name, dataID, skillJSON, res); err != nil {
+				return nil, err
+			}
+		}
+		data := []byte(res.Content)
+		if res.Metadata["encoding"] == "base64" {
+			if data, err = base64.StdEncoding.DecodeString(res.Content); err != nil {
+				return nil, fmt.Errorf("decode resource %s of skill %s: %w", dataID, name, err)
+			}
+		}
+		mode := os.FileMode(0644)
+		if m, ok := res.Metadata["mode"].(string); ok {
+			if parsed, err := strconv.ParseUint(m, 0, 32); err == nil {
+				mode = os.FileMode(parsed).Perm()
+			}
+		}
+		rel, err := resourcePath(res)
+		if err != nil {
+			return nil, fmt.Errorf("resource %s of skill %s: %w", dataID, name, err)
+		}
+		if err := addZipFile(zw, name+"/"+rel, data, mode); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		return nil, err
+	}
+	return &SkillArchive{Name: name, PreserveExec: true, KeepUnchanged: true, MD5Check: check, reader: r}, nil
+}
+
+// resourcePath returns where a resource goes in the skill directory: its
+// metadata path, or type and name for resources uploaded without one. Paths
+// that would leave the skill directory or replace SKILL.md are refused.
+func resourcePath(res skillResource) (string, error) {
+	rel, _ := res.Metadata["path"].(string)
+	if rel == "" {
+		rel = res.Name
+		if res.Type != rootResourceType {
+			rel = res.Type + "/" + res.Name
+		}
+	}
+	clean := path.Clean(rel)
+	if strings.Contains(rel, "\\") || path.IsAbs(clean) || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || clean == "SKILL.md" {
+		return "", fmt.Errorf("invalid path %q", rel)
+	}
+	return clean, nil
+}
+
+// checkUniform returns a *UniformMismatchError if a resource records another
+// upload than skill.json
+func checkUniform(name, dataID string, skillJSON skillConfig, res skillResource) error {
+	uniformID, _ := res.Metadata["uniformId"].(string)
+	revision, _ := res.Metadata["revision"].(string)
+	if revision == "" || skillJSON.Revision == "" {
+		return nil
+	}
+	if uniformID == skillJSON.UniformID && revision == skillJSON.Revision {
+		return nil
+	}
+	return &UniformMismatchError{
+		Skill:      name,
+		Resource:   dataID,
+		SkillID:    skillJSON.UniformID + "/" + skillJSON.Revision,
+		ResourceID: uniformID + "/" + revision,
+	}
+}
+
+// addZipFile writes one file with its mode to an archive being built
+func addZipFile(zw *zip.Writer, name string, data []byte, mode os.FileMode) error {
+	header := &zip.FileHeader{Name: name, Method: zip.Deflate}
+	header.SetMode(mode)
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
@@ -0,0 +1,257 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+)
+
+// newConfigStore serves the config API from memory and nothing else, like a
+// server without the skill API. It returns the client and the stored configs
+// by group and dataId.
+func newConfigStore(t *testing.T) (*client.NacosClient, map[string]string) {
+	var mu gosync.Mutex
+	configs := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		reply := func(data any) {
+			raw, _ := json.Marshal(data)
+			json.NewEncoder(w).Encode(map[string]any{"code": 0, "message": "ok", "data": json.RawMessage(raw)})
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/nacos/v3/admin/cs/config":
+			r.ParseForm()
+			configs[r.Form.Get("groupName")+"/"+r.Form.Get("dataId")] = r.Form.Get("content")
+			reply(true)
+		case r.Method == http.MethodGet && r.URL.Path == "/nacos/v3/client/cs/config":
+			content, ok := configs[r.URL.Query().Get("groupName")+"/"+r.URL.Query().Get("dataId")]
+			if !ok {
+				http.Error(w, `{"code":20004,"message":"config data not exist"}`, http.StatusNotFound)
+				return
+			}
+			reply(map[string]string{"content": content})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	c, err := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, configs
+}
+
+func TestUploadViaConfigRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "demo")
+	files := map[string][]byte{
+		"SKILL.md":               []byte("---\nname: demo\ndescription: A demo\n---\n# Demo\n"),
+		"README.md":              []byte("read me\n"),
+		"scripts/run.sh":         []byte("#!/bin/sh\necho hi\n"),
+		"scripts/lib/helper.py":  []byte("print('hi')\n"),
+		"scripts/lib/util/io.py": []byte("from .. import helper\n"),
+		"assets/logo.png":        {0x89, 'P', 'N', 'G', 0xff, 0x00, 0xfe},
+	}
+	for name, data := range files {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		mode := os.FileMode(0644)
+		if name == "scripts/run.sh" {
+			mode = 0755
+		}
+		if err := os.WriteFile(path, data, mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.MkdirAll(filepath.Join(src, ".git"), 0755)
+	os.WriteFile(filepath.Join(src, ".git", "HEAD"), []byte("ref"), 0644)
+
+	c, configs := newConfigStore(t)
+	svc := NewSkillService(c)
+	result, err := svc.UploadSkillViaConfig(src, "1.4.0")
+	if err != nil {
+		t.Fatalf("UploadSkillViaConfig() error = %v", err)
+	}
+	var skillJSON skillConfig
+	if err := json.Unmarshal([]byte(configs["skill_demo/skill.json"]), &skillJSON); err != nil {
+		t.Fatal(err)
+	}
+	if skillJSON.Name != "demo" || skillJSON.Description != "A demo" || skillJSON.UniformID != result.UniformID || len(skillJSON.Resources) != 5 {
+		t.Errorf("skill.json = %+v, want demo with uniformId %q and 5 resources", skillJSON, result.UniformID)
+	}
+	var published int64
+	for _, content := range configs {
+		published += int64(len(content))
+	}
+	if result.Bytes != published {
+		t.Errorf("Bytes = %d, want the %d bytes of the configs published", result.Bytes, published)
+	}
+	var nested skillResource
+	if err := json.Unmarshal([]byte(configs["skill_demo/resource_scripts_lib_util_io.py.json"]), &nested); err != nil {
+		t.Fatalf("resource config for scripts/lib/util/io.py: %v", err)
+	}
+	if nested.Type != "scripts" || nested.Name != "lib/util/io.py" || nested.Metadata["path"] != "scripts/lib/util/io.py" {
+		t.Errorf("resource for scripts/lib/util/io.py = %s %s, path %v", nested.Type, nested.Name, nested.Metadata["path"])
+	}
+
+	// Publishing again keeps the uniformId
+	if _, err := svc.UploadSkillViaConfig(src, ""); err != nil {
+		t.Fatalf("UploadSkillViaConfig() again error = %v", err)
+	}
+	var again skillConfig
+	json.Unmarshal([]byte(configs["skill_demo/skill.json"]), &again)
+	if again.UniformID != skillJSON.UniformID {
+		t.Errorf("uniformId changed from %s to %s", skillJSON.UniformID, again.UniformID)
+	}
+
+	// The skill API is missing, so GetSkill reads the configs back
+	out := t.TempDir()
+	if err := svc.GetSkill("demo", out, "", ""); err != nil {
+		t.Fatalf("GetSkill() error = %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(out, "demo", filepath.FromSlash(name)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s = %q after the round trip, want %q", name, got, want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(out, "demo", ".git")); !os.IsNotExist(err) {
+		t.Errorf(".git was uploaded: %v", err)
+	}
+	if runtime.GOOS != "windows" {
+		if info, err := os.Stat(filepath.Join(out, "demo", "scripts", "run.sh")); err != nil || info.Mode().Perm() != execMode {
+			t.Errorf("scripts/run.sh mode = %v, %v; want %v", info.Mode().Perm(), err, execMode)
+		}
+	}
+
+	if _, err := svc.DownloadSkill("missing", "", ""); !errors.Is(err, ErrSkillNotFound) {
+		t.Errorf("DownloadSkill() of a missing skill error = %v, want %v", err, ErrSkillNotFound)
+	}
+}
+
+func TestUploadViaConfigRejectsCollidingNames(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "demo")
+	for _, name := range []string{"SKILL.md", "a/b c.txt", "a/b_c.txt"} {
+		path := filepath.Join(src, filepath.FromSlash(name))
+		os.MkdirAll(filepath.Dir(path), 0755)
+		os.WriteFile(path, []byte("---\nname: demo\ndescription: d\n---\n"), 0644)
+	}
+	c, configs := newConfigStore(t)
+	_, err := NewSkillService(c).UploadSkillViaConfig(src, "")
+	if err == nil || !strings.Contains(err.Error(), "resource_a_b_c.txt.json") {
+		t.Errorf("UploadSkillViaConfig() error = %v, want a collision on resource_a_b_c.txt.json", err)
+	}
+	if len(configs) != 0 {
+		t.Errorf("%d configs were published despite the collision", len(configs))
+	}
+}
+
+func TestConfigLayoutUniformCheck(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(filepath.Join(src, "scripts"), 0755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("---\nname: demo\ndescription: d\n---\n"), 0644)
+	os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("echo hi\n"), 0644)
+
+	c, configs := newConfigStore(t)
+	svc := NewSkillService(c)
+	if _, err := svc.UploadSkillViaConfig(src, ""); err != nil {
+		t.Fatal(err)
+	}
+	const key = "skill_demo/resource_scripts_run.sh.json"
+	current := configs[key]
+	// A resource left over from an earlier upload of the same skill
+	var res skillResource
+	json.Unmarshal([]byte(current), &res)
+	res.Metadata["revision"] = "0000"
+	stale, _ := json.Marshal(res)
+	configs[key] = string(stale)
+
+	svc.SetUniformCheck(UniformCheck{Retries: 2, Delay: time.Millisecond})
+	_, err := svc.DownloadSkill("demo", "", "")
+	var mismatch *UniformMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("DownloadSkill() error = %v, want a UniformMismatchError", err)
+	}
+	if mismatch.Resource != "resource_scripts_run.sh.json" || !strings.HasSuffix(mismatch.ResourceID, "/0000") || mismatch.SkillID == mismatch.ResourceID {
+		t.Errorf("mismatch = %+v", mismatch)
+	}
+
+	svc.SetUniformCheck(UniformCheck{Skip: true})
+	if _, err := svc.DownloadSkill("demo", "", ""); err != nil {
+		t.Errorf("DownloadSkill() skipping the check error = %v", err)
+	}
+
+	// The publish in progress finishes while the reader retries
+	svc.SetUniformCheck(UniformCheck{Retries: 10, Delay: 5 * time.Millisecond})
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		c.PublishConfig("resource_scripts_run.sh.json", "skill_demo", current)
+	}()
+	if _, err := svc.DownloadSkill("demo", "", ""); err != nil {
+		t.Errorf("DownloadSkill() while the publish finishes error = %v", err)
+	}
+
+	// Resources from uploads that recorded no revision are not checked
+	delete(res.Metadata, "revision")
+	old, _ := json.Marshal(res)
+	c.PublishConfig("resource_scripts_run.sh.json", "skill_demo", string(old))
+	svc.SetUniformCheck(UniformCheck{})
+	if _, err := svc.DownloadSkill("demo", "", ""); err != nil {
+		t.Errorf("DownloadSkill() of an older upload error = %v", err)
+	}
+}
+
+func TestResourcePath(t *testing.T) {
+	tests := []struct {
+		name    string
+		res     skillResource
+		want    string
+		wantErr bool
+	}{
+		{"metadata path", skillResource{Type: "scripts", Name: "util.py", Metadata: map[string]any{"path": "scripts/lib/util.py"}}, "scripts/lib/util.py", false},
+		{"type and name", skillResource{Type: "scripts", Name: "lib/util.py"}, "scripts/lib/util.py", false},
+		{"root file", skillResource{Type: rootResourceType, Name: "README.md"}, "README.md", false},
+		{"cleaned", skillResource{Metadata: map[string]any{"path": "scripts/./lib//util.py"}}, "scripts/lib/util.py", false},
+		{"parent", skillResource{Metadata: map[string]any{"path": "../../etc/passwd"}}, "", true},
+		{"parent inside", skillResource{Metadata: map[string]any{"path": "scripts/../../x"}}, "", true},
+		{"parent in name", skillResource{Type: "scripts", Name: "../../../x"}, "", true},
+		{"absolute", skillResource{Metadata: map[string]any{"path": "/etc/passwd"}}, "", true},
+		{"backslash", skillResource{Metadata: map[string]any{"path": "..\\..\\x"}}, "", true},
+		{"SKILL.md", skillResource{Type: rootResourceType, Name: "SKILL.md"}, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resourcePath(tt.res)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("resourcePath() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigLayoutRefusesTraversal(t *testing.T) {
+	c, _ := newConfigStore(t)
+	skillJSON, _ := json.Marshal(skillConfig{Name: "demo", Content: "---\nname: demo\n---\n", Resources: []string{"resource_evil.json"}})
+	evil, _ := json.Marshal(skillResource{Type: "scripts", Name: "x", Content: "root::0:0", Metadata: map[string]any{"path": "../../etc/passwd"}})
+	c.PublishConfig(configSkillDataID, "skill_demo", string(skillJSON))
+	c.PublishConfig("resource_evil.json", "skill_demo", string(evil))
+
+	if _, err := NewSkillService(c).readConfigLayout("demo", false); err == nil || !strings.Contains(err.Error(), "invalid path") {
+		t.Errorf("readConfigLayout() error = %v, want an invalid path", err)
+	}
+}
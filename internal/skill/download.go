@@ -0,0 +1,45 @@
+package skill
+
+import "sync"
+
+// DefaultDownloadConcurrency is how many skills DownloadSkills fetches at once
+const DefaultDownloadConcurrency = 8
+
+// DownloadResult is the outcome of downloading one skill with DownloadSkills
+type DownloadResult struct {
+	Name    string
+	Archive *SkillArchive
+	Err     error
+}
+
+// DownloadSkills downloads several skills with at most concurrency requests in
+// flight, without extracting them. Results are returned in the order of
+// names, so callers can report and extract them deterministically; a failed
+// download does not stop the others. Version resolution is as for GetSkill.
+func (s *SkillService) DownloadSkills(names []string, version, label string, concurrency int) []DownloadResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	svc := s
+	if concurrency > 1 && len(names) > 1 {
+		// Progress lines of downloads running together would overwrite each other
+		quiet := *s
+		quiet.newProgress = nil
+		svc = &quiet
+	}
+	results := make([]DownloadResult, len(names))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, name string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			archive, err := svc.DownloadSkill(name, version, label)
+			results[i] = DownloadResult{Name: name, Archive: archive, Err: err}
+		}(i, name)
+	}
+	wg.Wait()
+	return results
+}
@@ -0,0 +1,68 @@
+package skill
+
+import (
+	"archive/zip"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+)
+
+func TestDownloadSkillsConcurrently(t *testing.T) {
+	const latency = 40 * time.Millisecond
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(latency)
+		name := r.URL.Query().Get("name")
+		if name == "missing" {
+			http.Error(w, `{"code":20004,"message":"skill not found"}`, http.StatusNotFound)
+			return
+		}
+		zw := zip.NewWriter(w)
+		f, _ := zw.Create(name + "/SKILL.md")
+		f.Write([]byte(name))
+		zw.Close()
+	}))
+	defer server.Close()
+	c, err := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewSkillService(c)
+
+	var names []string
+	for i := 0; i < 16; i++ {
+		names = append(names, fmt.Sprintf("skill-%02d", i))
+	}
+	names[5] = "missing"
+
+	elapsed := make(map[int]time.Duration)
+	for _, concurrency := range []int{1, DefaultDownloadConcurrency} {
+		start := time.Now()
+		results := svc.DownloadSkills(names, "", "", concurrency)
+		elapsed[concurrency] = time.Since(start)
+
+		for i, r := range results {
+			if r.Name != names[i] {
+				t.Fatalf("result %d is %s, want %s", i, r.Name, names[i])
+			}
+			if r.Name == "missing" {
+				if !errors.Is(r.Err, ErrSkillNotFound) {
+					t.Errorf("missing: error = %v, want ErrSkillNotFound", r.Err)
+				}
+				continue
+			}
+			if r.Err != nil || r.Archive == nil || r.Archive.FileCount() != 1 {
+				t.Errorf("%s: archive = %v, error = %v", r.Name, r.Archive, r.Err)
+			}
+		}
+	}
+	t.Logf("%d skills at %s each: serial %s, %d at once %s", len(names), latency, elapsed[1], DefaultDownloadConcurrency, elapsed[DefaultDownloadConcurrency])
+	if elapsed[DefaultDownloadConcurrency] > elapsed[1]/3 {
+		t.Errorf("concurrent downloads took %s, want well under the serial %s", elapsed[DefaultDownloadConcurrency], elapsed[1])
+	}
+}
@@ -0,0 +1,179 @@
+package skill
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ExportManifestFile lists the skills ExportSkills wrote into a directory
+const ExportManifestFile = "manifest.json"
+
+// allPageSize is the page size AllSkillNames lists skills with
+const allPageSize = 100
+
+// ExportedSkill is a skill written to a zip by Export
+type ExportedSkill struct {
+	Name    string `json:"name"`
+	File    string `json:"file"`
+	Version string `json:"version,omitempty"`
+	Files   int    `json:"files"`
+	Bytes   int64  `json:"bytes"`
+	SHA256  string `json:"sha256"`
+}
+
+// ExportFailure is a skill ExportSkills could not write
+type ExportFailure struct {
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// ExportManifest is the content of the manifest.json ExportSkills writes
+type ExportManifest struct {
+	ExportedAt time.Time       `json:"exportedAt"`
+	Server     string          `json:"server"`
+	Namespace  string          `json:"namespace"`
+	Skills     []ExportedSkill `json:"skills"`
+	Failed     []ExportFailure `json:"failed,omitempty"`
+}
+
+// AllSkillNames returns the names of every skill in the current namespace
+func (s *SkillService) AllSkillNames() ([]string, error) {
+	var names []string
+	for page := 1; ; page++ {
+		skills, total, err := s.ListSkills("", page, allPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range skills {
+			names = append(names, item.Name)
+		}
+		if len(skills) == 0 || len(names) >= total {
+			return names, nil
+		}
+	}
+}
+
+// Export writes the skill to a zip file in the layout skill-publish accepts:
+// every file under <name>/, SKILL.md included. Entries outside the skill's
+// directory are left out. The file is replaced only once it is complete.
+func (a *SkillArchive) Export(zipPath string) (ExportedSkill, error) {
+	exported := ExportedSkill{Name: a.Name, File: filepath.Base(zipPath)}
+	if !a.hasSkillMD() {
+		return exported, fmt.Errorf("skill %s is incomplete: the archive has no %s/SKILL.md", a.Name, a.Name)
+	}
+	if info, err := a.Info(); err == nil {
+		exported.Version = info.Version
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(zipPath), "."+filepath.Base(zipPath)+".*")
+	if err != nil {
+		return exported, err
+	}
+	defer os.Remove(tmp.Name())
+	hash := sha256.New()
+	counter := &countingWriter{w: io.MultiWriter(tmp, hash)}
+	zw := zip.NewWriter(counter)
+	for _, f := range a.reader.File {
+		if !strings.HasPrefix(f.Name, a.Name+"/") {
+			continue
+		}
+		// Copy keeps the entry's compressed data, mode and checksum
+		if err := zw.Copy(f); err != nil {
+			tmp.Close()
+			return exported, fmt.Errorf("failed to write zip entry %s: %w", f.Name, err)
+		}
+		if !f.FileInfo().IsDir() {
+			exported.Files++
+		}
+	}
+	if err := zw.Close(); err != nil {
+		tmp.Close()
+		return exported, err
+	}
+	if err := tmp.Close(); err != nil {
+		return exported, err
+	}
+	if err := os.Rename(tmp.Name(), zipPath); err != nil {
+		return exported, err
+	}
+	exported.Bytes = counter.n
+	exported.SHA256 = hex.EncodeToString(hash.Sum(nil))
+	return exported, nil
+}
+
+// ExportSkills downloads the latest version of each skill and writes it as
+// <name>.zip into dir, followed by a manifest.json listing them. A skill that
+// fails is recorded in the manifest and does not stop the others; the error
+// is for dir and the manifest only.
+func (s *SkillService) ExportSkills(names []string, dir string, concurrency int) (*ExportManifest, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	m := &ExportManifest{
+		ExportedAt: time.Now().UTC().Truncate(time.Second),
+		Server:     s.client.ServerAddr,
+		Namespace:  s.client.Namespace,
+		Skills:     []ExportedSkill{},
+	}
+	for _, download := range s.DownloadSkills(names, "", "", concurrency) {
+		err := download.Err
+		if err == nil {
+			var exported ExportedSkill
+			exported, err = download.Archive.Export(filepath.Join(dir, download.Name+".zip"))
+			if err == nil {
+				m.Skills = append(m.Skills, exported)
+				continue
+			}
+		}
+		m.Failed = append(m.Failed, ExportFailure{Name: download.Name, Error: err.Error()})
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(dir, ExportManifestFile), append(data, '\n'), 0644); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// zipSkillName returns the directory every entry of a skill zip is under,
+// if it holds a SKILL.md, or ""
+func zipSkillName(data []byte) string {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil || len(r.File) == 0 {
+		return ""
+	}
+	name, _, _ := strings.Cut(r.File[0].Name, "/")
+	archive := &SkillArchive{Name: name, reader: r}
+	for _, f := range r.File {
+		if !strings.HasPrefix(f.Name, name+"/") {
+			return ""
+		}
+	}
+	if !archive.hasSkillMD() {
+		return ""
+	}
+	return name
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,108 @@
+package skill
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestArchiveExport(t *testing.T) {
+	archive := newTestArchive(t, map[string]string{
+		"demo/SKILL.md":           "---\nname: demo\nversion: \"1.2.0\"\n---\n# demo\n",
+		"demo/scripts/lib/run.sh": "echo hi\n",
+		"other/stray.txt":         "not part of demo\n",
+	})
+	zipPath := filepath.Join(t.TempDir(), "demo-20260101.zip")
+	exported, err := archive.Export(zipPath)
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if exported.Name != "demo" || exported.File != "demo-20260101.zip" || exported.Version != "1.2.0" || exported.Files != 2 {
+		t.Errorf("Export() = %+v, want demo 1.2.0 with 2 files", exported)
+	}
+
+	data, err := os.ReadFile(zipPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256(data)
+	if exported.SHA256 != hex.EncodeToString(sum[:]) || exported.Bytes != int64(len(data)) {
+		t.Errorf("Export() sha256 %s, %d bytes; the file has %x, %d bytes", exported.SHA256, exported.Bytes, sum, len(data))
+	}
+	// Published again, the zip keeps the skill's name despite the date
+	if name := zipSkillName(data); name != "demo" {
+		t.Errorf("zipSkillName() of the export = %q, want demo", name)
+	}
+	matches, _ := filepath.Glob(filepath.Join(filepath.Dir(zipPath), ".*"))
+	if len(matches) != 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+
+	incomplete := newTestArchive(t, map[string]string{"demo/README.md": "no SKILL.md\n"})
+	if _, err := incomplete.Export(filepath.Join(t.TempDir(), "demo.zip")); err == nil {
+		t.Error("Export() of a skill without SKILL.md succeeded")
+	}
+}
+
+func TestZipSkillName(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  string
+	}{
+		{"skill directory", map[string]string{"demo/SKILL.md": "x", "demo/a.txt": "x"}, "demo"},
+		{"files at the root", map[string]string{"SKILL.md": "x"}, ""},
+		{"two directories", map[string]string{"demo/SKILL.md": "x", "other/a.txt": "x"}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := zip.NewWriter(&buf)
+			for name, content := range tt.files {
+				f, _ := w.Create(name)
+				f.Write([]byte(content))
+			}
+			w.Close()
+			if got := zipSkillName(buf.Bytes()); got != tt.want {
+				t.Errorf("zipSkillName() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExportSkills(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(filepath.Join(src, "scripts"), 0755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("---\nname: demo\ndescription: d\n---\n"), 0644)
+	os.WriteFile(filepath.Join(src, "scripts", "run.sh"), []byte("echo hi\n"), 0644)
+	c, _ := newConfigStore(t)
+	svc := NewSkillService(c)
+	if _, err := svc.UploadSkillViaConfig(src, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "backup")
+	m, err := svc.ExportSkills([]string{"demo", "missing"}, dir, 2)
+	if err != nil {
+		t.Fatalf("ExportSkills() error = %v", err)
+	}
+	if len(m.Skills) != 1 || m.Skills[0].Name != "demo" || len(m.Failed) != 1 || m.Failed[0].Name != "missing" {
+		t.Errorf("ExportSkills() = %+v, want demo exported and missing failed", m)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "demo.zip")); err != nil {
+		t.Error(err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, ExportManifestFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var written ExportManifest
+	if err := json.Unmarshal(data, &written); err != nil || len(written.Skills) != 1 || written.Skills[0].SHA256 != m.Skills[0].SHA256 {
+		t.Errorf("manifest.json = %s, %v", data, err)
+	}
+}
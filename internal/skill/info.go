@@ -0,0 +1,171 @@
+package skill
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Info parses the frontmatter of the skill's SKILL.md in the archive
+func (a *SkillArchive) Info() (*SkillInfo, error) {
+	for _, f := range a.reader.File {
+		if f.Name != a.Name+"/SKILL.md" {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		content, err := io.ReadAll(rc)
+		if err != nil {
+			return nil, err
+		}
+		return ParseFrontmatter(content)
+	}
+	return nil, fmt.Errorf("skill %s has no SKILL.md", a.Name)
+}
+
+// SkillDetail is what a skill's files tell beyond the list API's name and
+// description
+type SkillDetail struct {
+	Info      *SkillInfo // frontmatter of its SKILL.md
+	Resources int        // number of files besides SKILL.md
+	Err       error      // why the details are unavailable; Info is nil then
+}
+
+// SkillDetails downloads each skill to read its details,
+// DefaultDownloadConcurrency at a time. A skill that cannot be downloaded or
+// parsed gets an entry with Err set; it does not affect the others.
+func (s *SkillService) SkillDetails(names []string) []SkillDetail {
+	details := make([]SkillDetail, len(names))
+	for i, download := range s.DownloadSkills(names, "", "", DefaultDownloadConcurrency) {
+		if download.Err != nil {
+			details[i].Err = download.Err
+			continue
+		}
+		info, err := download.Archive.Info()
+		if err != nil {
+			details[i].Err = err
+			continue
+		}
+		details[i] = SkillDetail{Info: info, Resources: download.Archive.FileCount() - 1}
+	}
+	return details
+}
+
+// FilterByTag keeps the skills whose details, as returned by SkillDetails,
+// have the tag
+func FilterByTag(skills []SkillListItem, details []SkillDetail, tag string) ([]SkillListItem, []SkillDetail) {
+	var keptSkills []SkillListItem
+	var keptDetails []SkillDetail
+	for i, detail := range details {
+		if detail.Info != nil && detail.Info.HasTag(tag) {
+			keptSkills = append(keptSkills, skills[i])
+			keptDetails = append(keptDetails, detail)
+		}
+	}
+	return keptSkills, keptDetails
+}
+
+// SetFrontmatterVersion returns the SKILL.md content with the version field
+// of its frontmatter set to version, replacing or adding the field
+func SetFrontmatterVersion(content []byte, version string) ([]byte, error) {
+	if version == "" || strings.ContainsAny(version, "\r\n") {
+		return nil, fmt.Errorf("invalid version %q", version)
+	}
+	lines := strings.Split(string(content), "\n")
+	if len(lines) < 3 || lines[0] != "---" {
+		return nil, fmt.Errorf("invalid SKILL.md format")
+	}
+	field := "version: " + strconv.Quote(version)
+	for i := 1; i < len(lines); i++ {
+		switch {
+		case lines[i] == "---":
+			lines = append(lines[:i], append([]string{field}, lines[i:]...)...)
+			return []byte(strings.Join(lines, "\n")), nil
+		case strings.HasPrefix(lines[i], "version:"):
+			lines[i] = field
+			return []byte(strings.Join(lines, "\n")), nil
+		}
+	}
+	return nil, fmt.Errorf("invalid SKILL.md format: no closing ---")
+}
+
+// ListEntry is a skill as skill-list --output json prints it
+type ListEntry struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Version     string   `json:"version,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	Resources   *int     `json:"resources,omitempty"`
+	Error       string   `json:"error,omitempty"` // why the details are unavailable
+}
+
+// ListEntries combines listed skills with their details, which may be nil
+// when they were not fetched
+func ListEntries(skills []SkillListItem, details []SkillDetail) []ListEntry {
+	entries := make([]ListEntry, len(skills))
+	for i, item := range skills {
+		entries[i] = ListEntry{Name: item.Name, Description: item.Description}
+		if details == nil {
+			continue
+		}
+		d := details[i]
+		if d.Err != nil {
+			entries[i].Error = d.Err.Error()
+			continue
+		}
+		entries[i].Version, entries[i].Tags = d.Info.Version, d.Info.Tags
+		resources := d.Resources
+		entries[i].Resources = &resources
+	}
+	return entries
+}
+
+// ListCSV returns the header and rows of skill-list --output csv: each
+// skill's name and description, and with details its version, tags,
+// resource count and why the details are unavailable
+func ListCSV(entries []ListEntry, details bool) ([]string, [][]string) {
+	header := []string{"name", "description"}
+	if details {
+		header = append(header, "version", "tags", "resources", "error")
+	}
+	rows := make([][]string, len(entries))
+	for i, e := range entries {
+		rows[i] = []string{e.Name, e.Description}
+		if !details {
+			continue
+		}
+		resources := ""
+		if e.Resources != nil {
+			resources = strconv.Itoa(*e.Resources)
+		}
+		rows[i] = append(rows[i], e.Version, strings.Join(e.Tags, ", "), resources, e.Error)
+	}
+	return header, rows
+}
+
+// HasTag reports whether the skill is tagged tag, ignoring case
+func (i *SkillInfo) HasTag(tag string) bool {
+	for _, t := range i.Tags {
+		if strings.EqualFold(t, tag) {
+			return true
+		}
+	}
+	return false
+}
+
+// Details returns e.g. "version 1.4.0, tags: search, web", or "" when the
+// skill has neither
+func (i *SkillInfo) Details() string {
+	var parts []string
+	if i.Version != "" {
+		parts = append(parts, "version "+i.Version)
+	}
+	if len(i.Tags) > 0 {
+		parts = append(parts, "tags: "+strings.Join(i.Tags, ", "))
+	}
+	return strings.Join(parts, ", ")
+}
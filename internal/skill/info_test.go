@@ -0,0 +1,133 @@
+package skill
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseFrontmatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    *SkillInfo
+		wantErr bool
+	}{
+		{
+			name:    "name and description",
+			content: "---\nname: demo\ndescription: A demo\n---\n# Demo\n",
+			want:    &SkillInfo{Name: "demo", Description: "A demo"},
+		},
+		{
+			name:    "version, tags and other fields",
+			content: "---\nname: demo\ndescription: A demo\nversion: 1.4.0\ntags: [search, web]\nowner: team-a\nlicense: MIT\n---\n",
+			want: &SkillInfo{Name: "demo", Description: "A demo", Version: "1.4.0", Tags: []string{"search", "web"},
+				Metadata: map[string]any{"owner": "team-a", "license": "MIT"}},
+		},
+		{
+			name:    "no frontmatter",
+			content: "# Demo\n\nText\n",
+			wantErr: true,
+		},
+		{
+			name:    "unclosed frontmatter",
+			content: "---\nname: demo\ndescription: A demo\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFrontmatter([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFrontmatter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseFrontmatter() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArchiveInfo(t *testing.T) {
+	archive := newTestArchive(t, map[string]string{
+		"demo/SKILL.md": "---\nname: demo\ndescription: A demo\nversion: 2.0.1\ntags:\n  - Search\n---\n",
+	})
+	info, err := archive.Info()
+	if err != nil {
+		t.Fatalf("Info() error = %v", err)
+	}
+	if got, want := info.Details(), "version 2.0.1, tags: Search"; got != want {
+		t.Errorf("Details() = %q, want %q", got, want)
+	}
+	if !info.HasTag("search") || info.HasTag("web") {
+		t.Errorf("HasTag() does not match tags %q", info.Tags)
+	}
+
+	if _, err := newTestArchive(t, map[string]string{"demo/run.sh": "echo"}).Info(); err == nil {
+		t.Error("Info() of an archive without SKILL.md succeeded")
+	}
+}
+
+func TestSetFrontmatterVersion(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+		wantErr bool
+	}{
+		{
+			name:    "adds the field",
+			content: "---\nname: demo\n---\nbody\n",
+			want:    "---\nname: demo\nversion: \"1.4.0\"\n---\nbody\n",
+		},
+		{
+			name:    "replaces the field",
+			content: "---\nname: demo\nversion: 1.3.2\ntags: [a]\n---\nbody\n",
+			want:    "---\nname: demo\nversion: \"1.4.0\"\ntags: [a]\n---\nbody\n",
+		},
+		{
+			name:    "no frontmatter",
+			content: "body\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SetFrontmatterVersion([]byte(tt.content), "1.4.0")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SetFrontmatterVersion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("SetFrontmatterVersion() = %q, want %q", got, tt.want)
+			}
+			if info, err := ParseFrontmatter(got); err != nil || info.Version != "1.4.0" {
+				t.Errorf("ParseFrontmatter() = %+v, %v; want version 1.4.0", info, err)
+			}
+		})
+	}
+}
+
+func TestListCSV(t *testing.T) {
+	skills := []SkillListItem{{Name: "search", Description: "Searches, then summarizes"}, {Name: "broken"}}
+	details := []SkillDetail{
+		{Info: &SkillInfo{Version: "1.2.0", Tags: []string{"web", "search"}}, Resources: 3},
+		{Err: errors.New("skill.json not found")},
+	}
+
+	header, rows := ListCSV(ListEntries(skills, nil), false)
+	if !reflect.DeepEqual(header, []string{"name", "description"}) || !reflect.DeepEqual(rows[0], []string{"search", "Searches, then summarizes"}) {
+		t.Errorf("ListCSV() without details = %q, %q", header, rows)
+	}
+
+	header, rows = ListCSV(ListEntries(skills, details), true)
+	want := [][]string{
+		{"search", "Searches, then summarizes", "1.2.0", "web, search", "3", ""},
+		{"broken", "", "", "", "", "skill.json not found"},
+	}
+	if len(header) != 6 || !reflect.DeepEqual(rows, want) {
+		t.Errorf("ListCSV() with details = %q, %q; want rows %q", header, rows, want)
+	}
+}
@@ -0,0 +1,204 @@
+package skill
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// tmpDirInfix and oldDirInfix name the hidden directories an install
+	// stages into, e.g. .my-skill.tmp-123 and .my-skill.old-456
+	tmpDirInfix = ".tmp-"
+	oldDirInfix = ".old-"
+	// renameRetries bounds retries of a rename Windows refuses while another
+	// process has a file in the directory open
+	renameRetries = 10
+)
+
+// install writes the archive to a staging directory under targetDir and then
+// swaps each top-level entry (normally the skill directory) into place. Until
+// the swap the previous version is untouched, so a failed or interrupted
+// install never leaves a half-written skill.
+func (a *SkillArchive) install(targetDir string) (ExtractResult, error) {
+	if err := os.MkdirAll(targetDir, 0755); err != nil {
+		return ExtractResult{}, fmt.Errorf("failed to create directory %s: %w", targetDir, err)
+	}
+	removeLeftovers(targetDir, a.Name)
+	if !a.hasSkillMD() {
+		return ExtractResult{}, fmt.Errorf("skill %s is incomplete: the archive has no %s/SKILL.md", a.Name, a.Name)
+	}
+
+	staging, err := os.MkdirTemp(targetDir, "."+a.Name+tmpDirInfix)
+	if err != nil {
+		return ExtractResult{}, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer os.RemoveAll(staging)
+
+	// Reading an entry verifies its checksum, and each written file is read
+	// back, so a file in the staging directory is complete
+	carry := newCarryOver(filepath.Join(targetDir, a.Name), a.KeepUnchanged)
+	result, err := a.extract(staging, func(name string) string { return name }, carry)
+	if err != nil {
+		return result, err
+	}
+	if want := a.FileCount(); result.Files+len(result.Skipped) != want {
+		return result, fmt.Errorf("skill %s is incomplete: wrote %d of %d files", a.Name, result.Files, want)
+	}
+	if len(result.Skipped) > 0 && !a.AllowPartial {
+		return result, fmt.Errorf("skill %s is incomplete: %s (nothing was written)", a.Name, DescribeSkipped(result.Skipped))
+	}
+	if info, err := os.Stat(filepath.Join(staging, a.Name)); err == nil && info.IsDir() {
+		// The manifest holds the hashes skill-verify checks the files against
+		carry.next.MD5Check = a.MD5Check
+		if err := carry.next.write(filepath.Join(staging, a.Name)); err != nil {
+			return result, fmt.Errorf("failed to write the manifest of %s: %w", a.Name, err)
+		}
+	}
+
+	entries, err := os.ReadDir(staging)
+	if err != nil {
+		return result, err
+	}
+	for _, entry := range entries {
+		if err := replace(filepath.Join(staging, entry.Name()), filepath.Join(targetDir, entry.Name())); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// hasSkillMD reports whether the archive holds the skill's SKILL.md
+func (a *SkillArchive) hasSkillMD() bool {
+	for _, f := range a.reader.File {
+		if f.Name == a.Name+"/SKILL.md" {
+			return true
+		}
+	}
+	return false
+}
+
+// DescribeSkipped lists skipped entries with their reasons, e.g.
+// "2 file(s) skipped: x/a.py (outside the skill directory demo/), ..."
+func DescribeSkipped(skipped []SkippedFile) string {
+	parts := make([]string, len(skipped))
+	for i, s := range skipped {
+		parts[i] = fmt.Sprintf("%s (%s)", s.Name, s.Reason)
+	}
+	return fmt.Sprintf("%d file(s) skipped: %s", len(skipped), strings.Join(parts, ", "))
+}
+
+// Stale returns the files of targetDir/skillName that are not in the archive
+// and would be removed by Extract. Hidden files are kept, so they are not listed.
+func (a *SkillArchive) Stale(targetDir string) ([]string, error) {
+	inArchive := make(map[string]bool)
+	for _, f := range a.reader.File {
+		inArchive[strings.TrimSuffix(f.Name, "/")] = true
+	}
+	skillDir := filepath.Join(targetDir, a.Name)
+	var stale []string
+	err := filepath.WalkDir(skillDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		rel, err := filepath.Rel(targetDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if strings.HasPrefix(d.Name(), ".") && path != skillDir {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() && !inArchive[rel] {
+			stale = append(stale, rel)
+		}
+		return nil
+	})
+	return stale, err
+}
+
+// replace moves src to dst. An existing dst is first moved aside, since a
+// directory cannot be renamed over another one, and restored if the move
+// fails. Hidden entries of the old directory, such as .git, are carried over.
+func replace(src, dst string) error {
+	if _, err := os.Lstat(dst); errors.Is(err, os.ErrNotExist) {
+		return rename(src, dst)
+	}
+
+	aside, err := os.MkdirTemp(filepath.Dir(dst), "."+filepath.Base(dst)+oldDirInfix)
+	if err != nil {
+		return fmt.Errorf("failed to replace %s: %w", dst, err)
+	}
+	old := filepath.Join(aside, filepath.Base(dst))
+	if err := rename(dst, old); err != nil {
+		os.Remove(aside)
+		return fmt.Errorf("failed to replace %s: %w", dst, err)
+	}
+	if err := rename(src, dst); err != nil {
+		if restoreErr := rename(old, dst); restoreErr != nil {
+			return fmt.Errorf("failed to replace %s: %w (the previous version is in %s)", dst, err, old)
+		}
+		os.Remove(aside)
+		return fmt.Errorf("failed to replace %s: %w", dst, err)
+	}
+
+	if err := keepHidden(old, dst); err != nil {
+		return fmt.Errorf("replaced %s but could not carry over %w; the previous version is in %s", dst, err, old)
+	}
+	return os.RemoveAll(aside)
+}
+
+// keepHidden moves hidden top-level entries of the old directory into the
+// new one, unless the new version has its own
+func keepHidden(oldDir, newDir string) error {
+	entries, err := os.ReadDir(oldDir)
+	if err != nil {
+		// oldDir was a file; there is nothing to carry over
+		return nil
+	}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		dst := filepath.Join(newDir, entry.Name())
+		if _, err := os.Lstat(dst); err == nil {
+			continue
+		}
+		if err := rename(filepath.Join(oldDir, entry.Name()), dst); err != nil {
+			return fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+	}
+	return nil
+}
+
+// removeLeftovers deletes staging and old directories of name left behind
+// in dir by an install that was killed
+func removeLeftovers(dir, name string) {
+	for _, infix := range []string{tmpDirInfix, oldDirInfix} {
+		matches, _ := filepath.Glob(filepath.Join(dir, "."+name+infix+"*"))
+		for _, path := range matches {
+			os.RemoveAll(path)
+		}
+	}
+}
+
+// rename is os.Rename, retried briefly on Windows where it fails while an
+// agent or virus scanner has a file in the directory open
+func rename(src, dst string) error {
+	err := os.Rename(src, dst)
+	for i := 0; err != nil && runtime.GOOS == "windows" && i < renameRetries; i++ {
+		time.Sleep(100 * time.Millisecond)
+		err = os.Rename(src, dst)
+	}
+	return err
+}
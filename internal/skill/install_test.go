@@ -0,0 +1,196 @@
+package skill
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestExtractReplacesSkill(t *testing.T) {
+	dir := t.TempDir()
+	v1 := newTestArchive(t, map[string]string{"demo/SKILL.md": "v1", "demo/old.sh": "old"})
+	if _, err := v1.Extract(dir); err != nil {
+		t.Fatalf("Extract() v1 error = %v", err)
+	}
+	os.MkdirAll(filepath.Join(dir, "demo", ".git"), 0755)
+	os.WriteFile(filepath.Join(dir, "demo", ".git", "HEAD"), []byte("ref"), 0644)
+
+	v2 := newTestArchive(t, map[string]string{"demo/SKILL.md": "v2"})
+	if stale, err := v2.Stale(dir); err != nil || len(stale) != 1 || stale[0] != "demo/old.sh" {
+		t.Errorf("Stale() = %q, %v; want [demo/old.sh]", stale, err)
+	}
+	if _, err := v2.Extract(dir); err != nil {
+		t.Fatalf("Extract() v2 error = %v", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "demo", "SKILL.md")); string(data) != "v2" {
+		t.Errorf("SKILL.md = %q, want v2", data)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "demo", "old.sh")); !os.IsNotExist(err) {
+		t.Errorf("old.sh removed from Nacos is still there: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "demo", ".git")); err != nil {
+		t.Errorf(".git was not carried over: %v", err)
+	}
+	// Only the skill is left; staging and old directories are gone
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("target directory holds %q, want only demo", names)
+	}
+}
+
+func TestExtractFailureKeepsPreviousVersion(t *testing.T) {
+	dir := t.TempDir()
+	v1 := newTestArchive(t, map[string]string{"demo/SKILL.md": "v1", "demo/run.sh": "one"})
+	if _, err := v1.Extract(dir); err != nil {
+		t.Fatalf("Extract() v1 error = %v", err)
+	}
+
+	// A damaged second entry fails its checksum after the first was written
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for _, f := range []struct{ name, content string }{{"demo/SKILL.md", "v2"}, {"demo/run.sh", "TWO-DAMAGED"}} {
+		fw, _ := w.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Store})
+		fw.Write([]byte(f.content))
+	}
+	w.Close()
+	data := bytes.Replace(buf.Bytes(), []byte("TWO-DAMAGED"), []byte("TWO-DAMAGEX"), 1)
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v2 := &SkillArchive{Name: "demo", reader: r}
+
+	if _, err := v2.Extract(dir); err == nil {
+		t.Fatal("Extract() of a damaged archive succeeded")
+	}
+	for name, want := range map[string]string{"SKILL.md": "v1", "run.sh": "one"} {
+		if got, _ := os.ReadFile(filepath.Join(dir, "demo", name)); string(got) != want {
+			t.Errorf("%s = %q after a failed extract, want %q", name, got, want)
+		}
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("target directory holds %d entries after a failed extract, want 1", len(entries))
+	}
+}
+
+func TestReplaceRollsBack(t *testing.T) {
+	dir := t.TempDir()
+	dst := filepath.Join(dir, "demo")
+	os.MkdirAll(filepath.Join(dst, ".git"), 0755)
+	os.WriteFile(filepath.Join(dst, "SKILL.md"), []byte("v1"), 0644)
+
+	// The new version cannot be moved into place: the old one comes back
+	if err := replace(filepath.Join(dir, "missing"), dst); err == nil {
+		t.Fatal("replace() of a missing source succeeded")
+	}
+	if got, _ := os.ReadFile(filepath.Join(dst, "SKILL.md")); string(got) != "v1" {
+		t.Errorf("SKILL.md = %q after a failed replace, want v1", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory holds %d entries after a failed replace, want 1", len(entries))
+	}
+
+	// Replacing an existing directory works where rename cannot overwrite it
+	src := filepath.Join(dir, "staged")
+	os.MkdirAll(src, 0755)
+	os.WriteFile(filepath.Join(src, "SKILL.md"), []byte("v2"), 0644)
+	if err := replace(src, dst); err != nil {
+		t.Fatalf("replace() error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dst, "SKILL.md")); string(got) != "v2" {
+		t.Errorf("SKILL.md = %q, want v2", got)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".git")); err != nil {
+		t.Errorf(".git was not carried over: %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("directory holds %d entries after replace, want 1", len(entries))
+	}
+}
+
+func TestExtractKeepsUnchangedFiles(t *testing.T) {
+	dir := t.TempDir()
+	extract := func(files map[string]string, keep bool) ExtractResult {
+		t.Helper()
+		a := newTestArchive(t, files)
+		a.KeepUnchanged = keep
+		result, err := a.Extract(dir)
+		if err != nil {
+			t.Fatalf("Extract() error = %v", err)
+		}
+		return result
+	}
+	stat := func(name string) os.FileInfo {
+		t.Helper()
+		info, err := os.Stat(filepath.Join(dir, "demo", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info
+	}
+
+	extract(map[string]string{"demo/SKILL.md": "v1", "demo/lib/a.py": "a", "demo/lib/b.py": "b"}, true)
+	a, b := stat("lib/a.py"), stat("lib/b.py")
+
+	// Only SKILL.md changed: the other files are kept as they are
+	result := extract(map[string]string{"demo/SKILL.md": "v2", "demo/lib/a.py": "a", "demo/lib/b.py": "b"}, true)
+	if result.Files != 3 || result.Unchanged != 2 {
+		t.Errorf("Extract() = %+v, want 3 files, 2 unchanged", result)
+	}
+	if !os.SameFile(a, stat("lib/a.py")) || !os.SameFile(b, stat("lib/b.py")) {
+		t.Error("unchanged files were rewritten")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "demo", "SKILL.md")); string(data) != "v2" {
+		t.Errorf("SKILL.md = %q, want v2", data)
+	}
+
+	// A file edited locally is rewritten even though the archive did not change it
+	os.WriteFile(filepath.Join(dir, "demo", "lib", "a.py"), []byte("edited"), 0644)
+	if result := extract(map[string]string{"demo/SKILL.md": "v2", "demo/lib/a.py": "a", "demo/lib/b.py": "b"}, true); result.Unchanged != 2 {
+		t.Errorf("Extract() = %+v, want SKILL.md and b.py unchanged", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "demo", "lib", "a.py")); string(data) != "a" {
+		t.Errorf("a.py = %q, want the edit replaced", data)
+	}
+
+	// Without KeepUnchanged (skill-get --force) every file is written
+	if result := extract(map[string]string{"demo/SKILL.md": "v2", "demo/lib/a.py": "a", "demo/lib/b.py": "b"}, false); result.Unchanged != 0 {
+		t.Errorf("Extract() = %+v, want every file written", result)
+	}
+}
+
+func TestExtractReportsSkippedFiles(t *testing.T) {
+	files := map[string]string{"demo/SKILL.md": "v1", "other/run.sh": "echo"}
+
+	dir := t.TempDir()
+	if _, err := newTestArchive(t, files).Extract(dir); err == nil || !strings.Contains(err.Error(), "other/run.sh (outside the skill directory demo/)") {
+		t.Errorf("Extract() error = %v, want other/run.sh reported as skipped", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("target directory holds %d entries after a partial download, want none", len(entries))
+	}
+
+	partial := newTestArchive(t, files)
+	partial.AllowPartial = true
+	result, err := partial.Extract(dir)
+	if err != nil {
+		t.Fatalf("Extract() with AllowPartial error = %v", err)
+	}
+	if result.Files != 1 || len(result.Skipped) != 1 || result.Skipped[0].Name != "other/run.sh" {
+		t.Errorf("Extract() = %+v, want 1 file written and other/run.sh skipped", result)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "other")); !os.IsNotExist(err) {
+		t.Errorf("a file outside the skill was written: %v", err)
+	}
+
+	noSkillMD := newTestArchive(t, map[string]string{"demo/run.sh": "echo"})
+	if _, err := noSkillMD.Extract(t.TempDir()); err == nil || !strings.Contains(err.Error(), "no demo/SKILL.md") {
+		t.Errorf("Extract() without SKILL.md error = %v, want it refused", err)
+	}
+}
@@ -0,0 +1,98 @@
+package skill
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+)
+
+// manifestFile records what an install wrote into a skill directory, so the
+// next install can keep the files that did not change and VerifySkill can
+// find files changed since. It is hidden, so it is not part of the skill's hash.
+const manifestFile = ".manifest.json"
+
+// manifest lists the files of an installed skill by their path relative to
+// the skill directory, and whether the download was checked against the
+// Content-MD5 the server sent
+type manifest struct {
+	Files    map[string]manifestEntry `json:"files"`
+	MD5Check client.MD5Check          `json:"md5Check,omitempty"`
+}
+
+// manifestEntry is what an installed file was written with
+type manifestEntry struct {
+	SHA256  string      `json:"sha256"`
+	Size    int64       `json:"size"`
+	ModTime time.Time   `json:"modTime"`
+	Mode    os.FileMode `json:"mode"`
+}
+
+// readManifest returns the manifest of skillDir, or nil if there is none
+func readManifest(skillDir string) *manifest {
+	data, err := os.ReadFile(filepath.Join(skillDir, manifestFile))
+	if err != nil {
+		return nil
+	}
+	var m manifest
+	if json.Unmarshal(data, &m) != nil || m.Files == nil {
+		return nil
+	}
+	return &m
+}
+
+// write saves the manifest into skillDir
+func (m *manifest) write(skillDir string) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(skillDir, manifestFile), data, 0644)
+}
+
+// carryOver keeps unchanged files of the installed version of a skill when
+// a new version is extracted next to it
+type carryOver struct {
+	oldDir string    // the installed skill directory
+	old    *manifest // its manifest; nil writes every file
+	next   manifest  // what the new version consists of
+}
+
+func newCarryOver(oldDir string, keepUnchanged bool) *carryOver {
+	c := &carryOver{oldDir: oldDir, next: manifest{Files: make(map[string]manifestEntry)}}
+	if keepUnchanged {
+		c.old = readManifest(oldDir)
+	}
+	return c
+}
+
+// keep links the installed copy of rel to destPath if it is still exactly
+// what the last install wrote and the new version has the same content and
+// mode. The installed directory is left as it is, so a failed install can
+// still fall back to it.
+func (c *carryOver) keep(rel, sum string, mode os.FileMode, destPath string) bool {
+	if c.old == nil {
+		return false
+	}
+	entry, ok := c.old.Files[rel]
+	if !ok || entry.SHA256 != sum || entry.Mode != mode {
+		return false
+	}
+	oldPath := filepath.Join(c.oldDir, filepath.FromSlash(rel))
+	info, err := os.Stat(oldPath)
+	if err != nil || !info.Mode().IsRegular() || info.Size() != entry.Size || !info.ModTime().Equal(entry.ModTime) {
+		return false
+	}
+	return os.Link(oldPath, destPath) == nil
+}
+
+// record adds a file of the new version, as written to destPath
+func (c *carryOver) record(rel, sum string, mode os.FileMode, destPath string) {
+	info, err := os.Stat(destPath)
+	if err != nil {
+		return
+	}
+	c.next.Files[rel] = manifestEntry{SHA256: sum, Size: info.Size(), ModTime: info.ModTime(), Mode: mode}
+}
@@ -0,0 +1,171 @@
+package skill
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Outcomes of migrating a skill
+const (
+	MigrateMigrated   = "migrated"   // uploaded, and the destination serves the same files
+	MigrateIdentical  = "identical"  // the destination already had the same files
+	MigrateSkipped    = "skipped"    // the destination has other files, and overwriting is off
+	MigrateUnverified = "unverified" // uploaded, but the destination serves other files
+	MigrateFailed     = "failed"
+)
+
+// MigrateResult is the outcome of migrating one skill
+type MigrateResult struct {
+	Name   string    `json:"name"`
+	Status string    `json:"status"`
+	Hash   string    `json:"hash,omitempty"` // of the source's files
+	Error  string    `json:"error,omitempty"`
+	Bytes  int64     `json:"bytes,omitempty"` // uploaded to the destination
+	At     time.Time `json:"at"`
+}
+
+// Done reports whether the skill needs no further migration
+func (r MigrateResult) Done() bool {
+	return r.Status == MigrateMigrated || r.Status == MigrateIdentical
+}
+
+// Migrator copies skills from one Nacos to another
+type Migrator struct {
+	from, to  *SkillService
+	Overwrite bool // replace a skill the destination has with other files
+	ViaConfig bool // publish through the config API instead of the skill upload API
+}
+
+// NewMigrator creates a Migrator copying skills from one service to another
+func NewMigrator(from, to *SkillService) *Migrator {
+	return &Migrator{from: from, to: to}
+}
+
+// Migrate copies the latest version of a skill. It reads the skill from the
+// source, compares it with what the destination has, uploads it unless that
+// is the same or overwriting is off, and downloads it again from the
+// destination to check that the files arrived unchanged.
+func (m *Migrator) Migrate(name string) MigrateResult {
+	result := MigrateResult{Name: name, At: time.Now().UTC().Truncate(time.Second)}
+	fail := func(err error) MigrateResult {
+		result.Status, result.Error = MigrateFailed, err.Error()
+		return result
+	}
+
+	archive, err := m.from.DownloadSkill(name, "", "")
+	if err != nil {
+		return fail(fmt.Errorf("read from source: %w", err))
+	}
+	if result.Hash, err = archive.Hash(); err != nil {
+		return fail(err)
+	}
+
+	existing, err := m.to.DownloadSkill(name, "", "")
+	switch {
+	case errors.Is(err, ErrSkillNotFound):
+	case err != nil:
+		return fail(fmt.Errorf("read from destination: %w", err))
+	default:
+		hash, err := existing.Hash()
+		if err != nil {
+			return fail(err)
+		}
+		if hash == result.Hash {
+			result.Status = MigrateIdentical
+			return result
+		}
+		if !m.Overwrite {
+			result.Status, result.Error = MigrateSkipped, "the destination has other files (use --overwrite to replace them)"
+			return result
+		}
+	}
+
+	// The uploads read a skill directory, so the skill is written to a
+	// temporary one first
+	tmp, err := os.MkdirTemp("", "nacos-skill-migrate-")
+	if err != nil {
+		return fail(err)
+	}
+	defer os.RemoveAll(tmp)
+	dir := filepath.Join(tmp, name)
+	if _, err := archive.ExtractInto(dir); err != nil {
+		return fail(err)
+	}
+	var uploadResult *UploadResult
+	if m.ViaConfig {
+		uploadResult, err = m.to.UploadSkillViaConfig(dir, "")
+	} else {
+		uploadResult, err = m.to.UploadSkill(dir)
+	}
+	if err != nil {
+		return fail(fmt.Errorf("upload to destination: %w", err))
+	}
+	result.Bytes = uploadResult.Bytes
+
+	uploaded, err := m.to.DownloadSkill(name, "", "")
+	if err != nil {
+		result.Status, result.Error = MigrateUnverified, fmt.Sprintf("uploaded, but downloading it again failed: %v", err)
+		return result
+	}
+	hash, err := uploaded.Hash()
+	if err != nil {
+		return fail(err)
+	}
+	if hash != result.Hash {
+		result.Status, result.Error = MigrateUnverified, "uploaded, but the destination serves other files"
+		if !m.ViaConfig {
+			result.Error += " (a skill uploaded through the skill API is a draft until it is put online in the console)"
+		}
+		return result
+	}
+	result.Status = MigrateMigrated
+	return result
+}
+
+// MigrateState is the state file of a migration, so an interrupted one can
+// be resumed without migrating the finished skills again
+type MigrateState struct {
+	From   string                   `json:"from"`
+	To     string                   `json:"to"`
+	Skills map[string]MigrateResult `json:"skills"`
+}
+
+// LoadMigrateState reads a state file, or returns an empty state for from and
+// to if there is none. A state file of another migration is an error.
+func LoadMigrateState(path, from, to string) (*MigrateState, error) {
+	state := &MigrateState{From: from, To: to, Skills: make(map[string]MigrateResult)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("read migration state %s: %w", path, err)
+	}
+	if state.From != from || state.To != to {
+		return nil, fmt.Errorf("%s is the state of migrating %s to %s; remove it or pass another --state", path, state.From, state.To)
+	}
+	if state.Skills == nil {
+		state.Skills = make(map[string]MigrateResult)
+	}
+	return state, nil
+}
+
+// Save writes the state file, replacing it only once it is complete
+func (s *MigrateState) Save(path string) error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
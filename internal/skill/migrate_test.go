@@ -0,0 +1,89 @@
+package skill
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// publishTestSkill publishes a skill named demo with the given SKILL.md body
+// through the config API of svc
+func publishTestSkill(t *testing.T, svc *SkillService, body string) {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), "demo")
+	os.MkdirAll(filepath.Join(dir, "scripts"), 0755)
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: demo\ndescription: d\n---\n"+body), 0644)
+	os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("#!/bin/sh\necho hi\n"), 0755)
+	if _, err := svc.UploadSkillViaConfig(dir, ""); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	fromClient, _ := newConfigStore(t)
+	toClient, _ := newConfigStore(t)
+	from, to := NewSkillService(fromClient), NewSkillService(toClient)
+	publishTestSkill(t, from, "v1\n")
+	m := NewMigrator(from, to)
+	m.ViaConfig = true
+
+	steps := []struct {
+		name       string
+		change     func()
+		overwrite  bool
+		skill      string
+		wantStatus string
+	}{
+		{"to an empty destination", nil, false, "demo", MigrateMigrated},
+		{"again", nil, false, "demo", MigrateIdentical},
+		{"changed at the source", func() { publishTestSkill(t, from, "v2\n") }, false, "demo", MigrateSkipped},
+		{"changed, with overwrite", nil, true, "demo", MigrateMigrated},
+		{"missing at the source", nil, false, "missing", MigrateFailed},
+	}
+	for _, step := range steps {
+		if step.change != nil {
+			step.change()
+		}
+		m.Overwrite = step.overwrite
+		result := m.Migrate(step.skill)
+		if result.Status != step.wantStatus {
+			t.Fatalf("%s: Migrate() = %+v, want status %s", step.name, result, step.wantStatus)
+		}
+		if result.Done() != (step.wantStatus == MigrateMigrated || step.wantStatus == MigrateIdentical) {
+			t.Errorf("%s: Done() = %v", step.name, result.Done())
+		}
+		if (result.Bytes > 0) != (step.wantStatus == MigrateMigrated) {
+			t.Errorf("%s: Bytes = %d, want bytes only for an upload", step.name, result.Bytes)
+		}
+	}
+
+	archive, err := to.DownloadSkill("demo", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, _ := archive.Info()
+	if hash, _ := archive.Hash(); info == nil || hash != m.Migrate("demo").Hash {
+		t.Errorf("destination has %+v, want the v2 files of the source", info)
+	}
+}
+
+func TestMigrateState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "migrate.json")
+	state, err := LoadMigrateState(path, "old", "new")
+	if err != nil || len(state.Skills) != 0 {
+		t.Fatalf("LoadMigrateState() of a missing file = %+v, %v", state, err)
+	}
+	state.Skills["demo"] = MigrateResult{Name: "demo", Status: MigrateMigrated}
+	if err := state.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded, err := LoadMigrateState(path, "old", "new")
+	if err != nil || !loaded.Skills["demo"].Done() {
+		t.Errorf("LoadMigrateState() = %+v, %v; want demo migrated", loaded, err)
+	}
+	if _, err := LoadMigrateState(path, "old", "other"); err == nil || !strings.Contains(err.Error(), "old to new") {
+		t.Errorf("LoadMigrateState() of another migration error = %v", err)
+	}
+}
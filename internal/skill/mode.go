@@ -0,0 +1,47 @@
+package skill
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path"
+	"runtime"
+	"strings"
+)
+
+const (
+	// fileMode is the mode of extracted files
+	fileMode os.FileMode = 0644
+	// execMode is the mode of extracted files that should be executable
+	execMode os.FileMode = 0755
+)
+
+// entryMode returns the mode to extract a ZIP entry with. Unless preserveExec
+// is false, an entry is executable when the uploader recorded an executable
+// mode for it, or, for archives that carry no modes, when it starts with a
+// shebang line or is a .sh or .py file under scripts/.
+func entryMode(f *zip.File, data []byte, preserveExec bool) os.FileMode {
+	if !preserveExec {
+		return fileMode
+	}
+	if f.Mode()&0111 != 0 || bytes.HasPrefix(data, []byte("#!")) {
+		return execMode
+	}
+	rel := skillRelPath(f.Name)
+	if ext := path.Ext(rel); strings.HasPrefix(rel, "scripts/") && (ext == ".sh" || ext == ".py") {
+		return execMode
+	}
+	return fileMode
+}
+
+// writeEntry writes an extracted file with mode. The mode is also applied to
+// a file that already existed; Windows has no executable bit, so it is left alone there.
+func writeEntry(destPath string, data []byte, mode os.FileMode) error {
+	if err := os.WriteFile(destPath, data, mode); err != nil {
+		return err
+	}
+	if runtime.GOOS == "windows" {
+		return nil
+	}
+	return os.Chmod(destPath, mode)
+}
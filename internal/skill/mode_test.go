@@ -0,0 +1,38 @@
+package skill
+
+import (
+	"archive/zip"
+	"os"
+	"testing"
+)
+
+func TestEntryMode(t *testing.T) {
+	tests := []struct {
+		name         string
+		entry        string
+		mode         os.FileMode // recorded by the uploader, 0 for none
+		data         string
+		preserveExec bool
+		want         os.FileMode
+	}{
+		{"recorded executable", "demo/tool", 0755, "\x7fELF", true, execMode},
+		{"recorded plain", "demo/SKILL.md", 0644, "# demo", true, fileMode},
+		{"shebang", "demo/bin/run", 0, "#!/bin/sh\necho hi", true, execMode},
+		{"script under scripts/", "demo/scripts/build.py", 0, "print(1)", true, execMode},
+		{"nested script under scripts/", "demo/scripts/ci/run.sh", 0, "echo hi", true, execMode},
+		{"script outside scripts/", "demo/lib/util.py", 0, "print(1)", true, fileMode},
+		{"other file under scripts/", "demo/scripts/README.md", 0, "# scripts", true, fileMode},
+		{"turned off", "demo/scripts/run.sh", 0755, "#!/bin/sh", false, fileMode},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := &zip.FileHeader{Name: tt.entry}
+			if tt.mode != 0 {
+				header.SetMode(tt.mode)
+			}
+			if got := entryMode(&zip.File{FileHeader: *header}, []byte(tt.data), tt.preserveExec); got != tt.want {
+				t.Errorf("entryMode() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
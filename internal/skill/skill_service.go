@@ -3,29 +3,49 @@ package skill
 import (
 	"archive/zip"
 	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 
 	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/nacos-group/nacos-cli/internal/util"
 	"gopkg.in/yaml.v3"
 )
 
+// ErrSkillNotFound is returned (wrapped) by DownloadSkill when the skill, or
+// the requested version of it, does not exist.
+var ErrSkillNotFound = errors.New("skill not found")
+
 // SkillService handles skill-related operations
 type SkillService struct {
-	client *client.NacosClient
+	client       *client.NacosClient
+	uniformCheck UniformCheck
+	log          *slog.Logger
+	newProgress  func(label string, total int64, unit string) *ui.Progress // see SetProgress
+	limiter      *util.Limiter                                              // see SetLimiter
 }
 
-// SkillInfo represents skill metadata
+// SkillInfo represents skill metadata from the SKILL.md frontmatter
 type SkillInfo struct {
-	Name        string `json:"name" yaml:"name"`
-	Description string `json:"description" yaml:"description"`
+	Name        string   `json:"name" yaml:"name"`
+	Description string   `json:"description" yaml:"description"`
+	Version     string   `json:"version,omitempty" yaml:"version,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	// Metadata holds every other frontmatter field, e.g. owner or license
+	Metadata map[string]any `json:"metadata,omitempty" yaml:",inline"`
 }
 
 // SkillListItem represents a skill item in the list with name and description
@@ -37,10 +57,46 @@ type SkillListItem struct {
 // NewSkillService creates a new skill service
 func NewSkillService(nacosClient *client.NacosClient) *SkillService {
 	return &SkillService{
-		client: nacosClient,
+		client:       nacosClient,
+		uniformCheck: DefaultUniformCheck,
+		log:          logging.Stderr(),
 	}
 }
 
+// SetLogger replaces the default logger, which prints to stderr. The service
+// logs at debug level only, e.g. where a skill was read from and retries.
+func (s *SkillService) SetLogger(logger *slog.Logger) {
+	s.log = logger
+}
+
+// SetProgress makes uploads, downloads and the zipping of a skill directory
+// report their progress through newProgress, e.g. ui.NewProgress. Without it
+// nothing is reported.
+func (s *SkillService) SetProgress(newProgress func(label string, total int64, unit string) *ui.Progress) {
+	s.newProgress = newProgress
+}
+
+// progress starts the progress of a step, or returns nil (which reports
+// nothing) without SetProgress
+func (s *SkillService) progress(label string, total int64, unit string) *ui.Progress {
+	if s.newProgress == nil {
+		return nil
+	}
+	return s.newProgress(label, total, unit)
+}
+
+// SetLimiter makes downloads share the bandwidth of limiter with every other
+// reader it limits; a nil limiter does not limit
+func (s *SkillService) SetLimiter(limiter *util.Limiter) {
+	s.limiter = limiter
+}
+
+// SetUniformCheck sets how skills read from the config layout are checked
+// for a publish in progress
+func (s *SkillService) SetUniformCheck(check UniformCheck) {
+	s.uniformCheck = check
+}
+
 // SkillListResponse represents the response from skill list API
 type SkillListResponse struct {
 	TotalCount     int             `json:"totalCount"`
@@ -58,10 +114,15 @@ type V3Response struct {
 
 // ListSkills lists all skills with name and description
 func (s *SkillService) ListSkills(skillName string, pageNo, pageSize int) ([]SkillListItem, int, error) {
+	return s.ListSkillsIn(s.client.Namespace, skillName, pageNo, pageSize)
+}
+
+// ListSkillsIn is ListSkills in namespace rather than the client's
+func (s *SkillService) ListSkillsIn(namespace, skillName string, pageNo, pageSize int) ([]SkillListItem, int, error) {
 	params := url.Values{}
 	params.Set("pageNo", fmt.Sprintf("%d", pageNo))
 	params.Set("pageSize", fmt.Sprintf("%d", pageSize))
-	params.Set("namespaceId", s.client.Namespace)
+	params.Set("namespaceId", namespace)
 
 	if skillName != "" {
 		params.Set("skillName", skillName)
@@ -75,12 +136,7 @@ func (s *SkillService) ListSkills(skillName string, pageNo, pageSize int) ([]Ski
 		return nil, 0, err
 	}
 
-	if s.client.AccessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
-	}
-
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, 0, fmt.Errorf("list skills failed: %w", err)
 	}
@@ -88,7 +144,7 @@ func (s *SkillService) ListSkills(skillName string, pageNo, pageSize int) ([]Ski
 
 	if resp.StatusCode != 200 {
 		respBody, _ := io.ReadAll(resp.Body)
-		return nil, 0, client.ParseHTTPError(resp.StatusCode, respBody, "list skills")
+		return nil, 0, client.WithRequestID(client.ParseHTTPError(resp.StatusCode, respBody, "list skills"), resp)
 	}
 
 	respBody, err := io.ReadAll(resp.Body)
@@ -102,7 +158,7 @@ func (s *SkillService) ListSkills(skillName string, pageNo, pageSize int) ([]Ski
 	}
 
 	if v3Resp.Code != 0 {
-		return nil, 0, fmt.Errorf("list skills failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message)
+		return nil, 0, client.WithRequestID(fmt.Errorf("list skills failed: code=%d, message=%s", v3Resp.Code, v3Resp.Message), resp)
 	}
 
 	var skillList SkillListResponse
@@ -113,10 +169,53 @@ func (s *SkillService) ListSkills(skillName string, pageNo, pageSize int) ([]Ski
 	return skillList.PageItems, skillList.TotalCount, nil
 }
 
+// SkillArchive is a downloaded skill ZIP that has not been extracted yet
+type SkillArchive struct {
+	Name string
+	// PreserveExec makes scripts executable when extracted (see entryMode);
+	// DownloadSkill turns it on
+	PreserveExec bool
+	// KeepUnchanged makes Extract keep files of the installed version that
+	// did not change instead of rewriting them; DownloadSkill turns it on
+	KeepUnchanged bool
+	// AllowPartial makes Extract install a skill even if some entries had
+	// to be skipped; they are listed in ExtractResult.Skipped
+	AllowPartial bool
+	// MD5Check is what checking the download against its Content-MD5 found;
+	// Extract records it in the manifest
+	MD5Check client.MD5Check
+	reader   *zip.Reader
+}
+
+// ExtractResult summarizes the files written when extracting a skill
+type ExtractResult struct {
+	Files     int
+	Bytes     int64
+	Unchanged int           // files kept from the installed version instead of being rewritten
+	Skipped   []SkippedFile // archive entries that were not written
+}
+
+// SkippedFile is an archive entry that was not written, and why
+type SkippedFile struct {
+	Name   string
+	Reason string
+}
+
 // GetSkill downloads a skill as ZIP via the Client Skill API and extracts it to local directory.
 // The server returns a ZIP binary stream containing skillName/SKILL.md and resource files.
 // Priority for version resolution: label > version > latest.
 func (s *SkillService) GetSkill(skillName, outputDir string, version, label string) error {
+	archive, err := s.DownloadSkill(skillName, version, label)
+	if err != nil {
+		return err
+	}
+	_, err = archive.Extract(outputDir)
+	return err
+}
+
+// DownloadSkill downloads a skill ZIP without extracting it, so callers can
+// check what it would overwrite first. Version resolution is as for GetSkill.
+func (s *SkillService) DownloadSkill(skillName, version, label string) (*SkillArchive, error) {
 	params := url.Values{}
 	params.Set("namespaceId", s.client.Namespace)
 	params.Set("name", skillName)
@@ -130,103 +229,334 @@ func (s *SkillService) GetSkill(skillName, outputDir string, version, label stri
 	apiURL := fmt.Sprintf("http://%s/nacos/v3/client/ai/skills?%s",
 		s.client.ServerAddr, params.Encode())
 
-	req, err := http.NewRequest("GET", apiURL, nil)
+	var status int
+	var zipBytes []byte
+	var check client.MD5Check
+	err := s.client.RetryCorrupted(func() (err error) {
+		status, zipBytes, check, err = s.fetchSkillZip(skillName, apiURL)
+		return err
+	})
 	if err != nil {
-		return fmt.Errorf("failed to build request: %w", err)
+		return nil, err
+	}
+
+	if status == http.StatusNotFound {
+		switch {
+		case version == "" && label == "":
+			// Servers without the skill API only have skills published
+			// with UploadSkillViaConfig
+			s.log.Debug(fmt.Sprintf("Skill API has no %s; reading the config layout", skillName), "skill", skillName)
+			archive, err := s.downloadFromConfigs(skillName)
+			if err == nil {
+				return archive, nil
+			}
+			var mismatch *UniformMismatchError
+			if errors.As(err, &mismatch) {
+				return nil, err
+			}
+		case version != "":
+			return nil, fmt.Errorf("%w: %s (version %s)", ErrSkillNotFound, skillName, version)
+		case label != "":
+			return nil, fmt.Errorf("%w: %s (label %s)", ErrSkillNotFound, skillName, label)
+		}
+		return nil, fmt.Errorf("%w: %s", ErrSkillNotFound, skillName)
 	}
-	if s.client.AccessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
+	zipReader, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zip: %w", err)
 	}
+	s.log.Debug(fmt.Sprintf("Downloaded %s from the skill API: %d bytes, %d entries", skillName, len(zipBytes), len(zipReader.File)),
+		"skill", skillName, "bytes", len(zipBytes), "version", version, "label", label)
+	return &SkillArchive{Name: skillName, PreserveExec: true, KeepUnchanged: true, MD5Check: check, reader: zipReader}, nil
+}
 
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+// fetchSkillZip requests a skill ZIP once for DownloadSkill and returns the
+// status and body of a 200 or 404 response; other statuses are an error. The
+// ZIP of a 200 response is checked against its Content-MD5 header.
+func (s *SkillService) fetchSkillZip(skillName, apiURL string) (int, []byte, client.MD5Check, error) {
+	req, err := http.NewRequest("GET", apiURL, nil)
 	if err != nil {
-		return fmt.Errorf("failed to get skill: %w", err)
+		return 0, nil, "", fmt.Errorf("failed to build request: %w", err)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, nil, "", fmt.Errorf("failed to get skill: %w", err)
 	}
 	defer resp.Body.Close()
 
-	zipBytes, err := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return 0, nil, "", fmt.Errorf("failed to read response: %w", err)
+		}
+		if resp.StatusCode != http.StatusNotFound {
+			return 0, nil, "", client.WithRequestID(client.ParseHTTPError(resp.StatusCode, body, "get skill"), resp)
+		}
+		return resp.StatusCode, body, "", nil
+	}
+	progress := s.progress("Downloading "+skillName, max(resp.ContentLength, 0), "")
+	zipBytes, err := io.ReadAll(progress.Reader(s.limiter.Reader(resp.Body)))
+	progress.Done()
 	if err != nil {
-		return fmt.Errorf("failed to read response: %w", err)
+		return 0, nil, "", fmt.Errorf("failed to read response: %w", err)
 	}
+	check, err := s.client.CheckContentMD5(resp.Header, zipBytes, "skill "+skillName)
+	return resp.StatusCode, zipBytes, check, err
+}
 
-	if resp.StatusCode != 200 {
-		return client.ParseHTTPError(resp.StatusCode, zipBytes, "get skill")
+// FileCount returns the number of files in the archive
+func (a *SkillArchive) FileCount() int {
+	count := 0
+	for _, f := range a.reader.File {
+		if !f.FileInfo().IsDir() {
+			count++
+		}
 	}
+	return count
+}
 
-	// Extract ZIP to output directory
-	return extractZip(zipBytes, outputDir)
+// Existing returns the archive entries that already exist as files under
+// targetDir and would be replaced by Extract
+func (a *SkillArchive) Existing(targetDir string) ([]string, error) {
+	var existing []string
+	for _, f := range a.reader.File {
+		destPath, err := entryPath(targetDir, f.Name)
+		if err != nil {
+			return nil, err
+		}
+		if f.FileInfo().IsDir() {
+			continue
+		}
+		if info, err := os.Stat(destPath); err == nil && !info.IsDir() {
+			existing = append(existing, f.Name)
+		}
+	}
+	return existing, nil
 }
 
-// extractZip extracts a ZIP byte array to the target directory.
+// Extract extracts the archive to the target directory.
 // ZIP entries like "skillName/SKILL.md" are extracted preserving their path structure.
-func extractZip(zipBytes []byte, targetDir string) error {
-	zipReader, err := zip.NewReader(bytes.NewReader(zipBytes), int64(len(zipBytes)))
+// The new version replaces targetDir/skillName as a whole, and only once every
+// file was written; on failure the previous version is left untouched.
+func (a *SkillArchive) Extract(targetDir string) (ExtractResult, error) {
+	return a.install(targetDir)
+}
+
+// ExtractInto extracts the skill's files directly into skillDir, e.g. the
+// entry "skillName/SKILL.md" becomes skillDir/SKILL.md
+func (a *SkillArchive) ExtractInto(skillDir string) (ExtractResult, error) {
+	return a.extract(skillDir, skillRelPath, nil)
+}
+
+// Hash returns a digest of the skill's files, comparable with HashDir of a
+// directory holding the same files
+func (a *SkillArchive) Hash() (string, error) {
+	sums := make(map[string][]byte)
+	for _, f := range a.reader.File {
+		if f.FileInfo().IsDir() || hiddenPath(skillRelPath(f.Name)) {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return "", fmt.Errorf("failed to open zip entry %s: %w", f.Name, err)
+		}
+		h := sha256.New()
+		_, err = io.Copy(h, rc)
+		rc.Close()
+		if err != nil {
+			return "", fmt.Errorf("failed to read zip entry %s: %w", f.Name, err)
+		}
+		sums[skillRelPath(f.Name)] = h.Sum(nil)
+	}
+	return hashFiles(sums), nil
+}
+
+// HashDir returns a digest of the files in a local skill directory, skipping
+// hidden files. It matches SkillArchive.Hash for the same files.
+func HashDir(skillDir string) (string, error) {
+	sums := make(map[string][]byte)
+	err := filepath.WalkDir(skillDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(skillDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel != "." && hiddenPath(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		sum := sha256.Sum256(data)
+		sums[rel] = sum[:]
+		return nil
+	})
 	if err != nil {
-		return fmt.Errorf("failed to read zip: %w", err)
+		return "", err
+	}
+	return hashFiles(sums), nil
+}
+
+// hashFiles combines per-file digests, keyed by slash-separated path, in path order
+func hashFiles(sums map[string][]byte) string {
+	names := make([]string, 0, len(sums))
+	for name := range sums {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	h := sha256.New()
+	for _, name := range names {
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(sums[name])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// skillRelPath strips the leading skill directory from a ZIP entry name
+func skillRelPath(name string) string {
+	if i := strings.Index(name, "/"); i >= 0 {
+		return name[i+1:]
 	}
+	return name
+}
 
-	for _, f := range zipReader.File {
-		// Security: reject path traversal
-		if strings.Contains(f.Name, "..") {
-			return fmt.Errorf("unsafe zip entry path: %s", f.Name)
+func hiddenPath(rel string) bool {
+	for _, part := range strings.Split(rel, "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
 		}
+	}
+	return false
+}
 
-		destPath := filepath.Join(targetDir, f.Name)
+// extract writes each entry to targetDir under the path returned by rename.
+// With carry, files of the skill directory that did not change are linked
+// from the installed version instead, and every file is recorded in its manifest.
+func (a *SkillArchive) extract(targetDir string, rename func(string) string, carry *carryOver) (ExtractResult, error) {
+	var result ExtractResult
+	for _, f := range a.reader.File {
+		destPath, err := entryPath(targetDir, rename(f.Name))
+		if err != nil {
+			return result, err
+		}
+		if !strings.HasPrefix(f.Name, a.Name+"/") {
+			// Written outside the skill directory it would not be part of
+			// the skill, and could clobber another skill
+			if !f.FileInfo().IsDir() {
+				result.Skipped = append(result.Skipped, SkippedFile{Name: f.Name, Reason: "outside the skill directory " + a.Name + "/"})
+			}
+			continue
+		}
 
 		if f.FileInfo().IsDir() {
 			if err := os.MkdirAll(destPath, 0755); err != nil {
-				return fmt.Errorf("failed to create directory %s: %w", destPath, err)
+				return result, fmt.Errorf("failed to create directory %s: %w", destPath, err)
 			}
 			continue
 		}
 
 		// Ensure parent directory exists
 		if err := os.MkdirAll(filepath.Dir(destPath), 0755); err != nil {
-			return fmt.Errorf("failed to create parent directory: %w", err)
+			return result, fmt.Errorf("failed to create parent directory: %w", err)
 		}
 
 		rc, err := f.Open()
 		if err != nil {
-			return fmt.Errorf("failed to open zip entry %s: %w", f.Name, err)
+			return result, fmt.Errorf("failed to open zip entry %s: %w", f.Name, err)
 		}
 
 		data, err := io.ReadAll(rc)
 		rc.Close()
 		if err != nil {
-			return fmt.Errorf("failed to read zip entry %s: %w", f.Name, err)
+			return result, fmt.Errorf("failed to read zip entry %s: %w", f.Name, err)
 		}
 
-		if err := os.WriteFile(destPath, data, 0644); err != nil {
-			return fmt.Errorf("failed to write file %s: %w", destPath, err)
+		mode := entryMode(f, data, a.PreserveExec)
+		digest := sha256.Sum256(data)
+		sum := hex.EncodeToString(digest[:])
+		var rel string
+		if carry != nil {
+			rel = skillRelPath(f.Name)
+		}
+		result.Files++
+		result.Bytes += int64(len(data))
+		if rel != "" && carry.keep(rel, sum, mode, destPath) {
+			result.Unchanged++
+		} else if err := writeEntry(destPath, data, mode); err != nil {
+			return result, fmt.Errorf("failed to write file %s: %w", destPath, err)
+		}
+		// A full disk can truncate a file without failing the write, so
+		// every file is read back before the skill counts as installed
+		if err := checkFile(destPath, sum); err != nil {
+			return result, err
+		}
+		if rel != "" {
+			carry.record(rel, sum, mode, destPath)
 		}
 	}
 
-	return nil
+	return result, nil
+}
+
+// entryPath returns where a ZIP entry is extracted under targetDir
+func entryPath(targetDir, name string) (string, error) {
+	// Security: reject path traversal
+	if strings.Contains(name, "..") {
+		return "", fmt.Errorf("unsafe zip entry path: %s", name)
+	}
+	return filepath.Join(targetDir, name), nil
 }
 
 // UploadSkill uploads a skill from local directory or a pre-built zip file.
 // If skillPath points to a .zip file it is uploaded directly; otherwise the
 // directory is packed into a zip on-the-fly (skillName/... structure).
-func (s *SkillService) UploadSkill(skillPath string) error {
+func (s *SkillService) UploadSkill(skillPath string) (*UploadResult, error) {
+	return s.UploadSkillVersion(skillPath, "")
+}
+
+// UploadSkillVersion uploads a skill like UploadSkill, recording version in
+// the frontmatter of the uploaded SKILL.md unless it is empty. The local
+// SKILL.md is not changed. A version cannot be set on a pre-built zip.
+func (s *SkillService) UploadSkillVersion(skillPath, version string) (*UploadResult, error) {
 	var zipBuffer *bytes.Buffer
 	var skillName string
 
 	if strings.HasSuffix(strings.ToLower(skillPath), ".zip") {
+		if version != "" {
+			return nil, fmt.Errorf("cannot set the version of a .zip upload; set it in the SKILL.md inside")
+		}
 		// Direct zip upload
 		data, err := os.ReadFile(skillPath)
 		if err != nil {
-			return fmt.Errorf("failed to read zip file: %w", err)
+			return nil, fmt.Errorf("failed to read zip file: %w", err)
 		}
 		zipBuffer = bytes.NewBuffer(data)
-		// Use the zip filename (without .zip) as the display name
-		base := filepath.Base(skillPath)
-		skillName = strings.TrimSuffix(base, filepath.Ext(base))
+		// Use the skill directory inside the zip as the name, so an
+		// exported demo-20260101.zip is published as demo; fall back to
+		// the zip filename (without .zip)
+		if skillName = zipSkillName(data); skillName == "" {
+			base := filepath.Base(skillPath)
+			skillName = strings.TrimSuffix(base, filepath.Ext(base))
+		}
 	} else {
 		// Pack directory into zip
 		skillName = filepath.Base(skillPath)
 		zipBuffer = new(bytes.Buffer)
 		zipWriter := zip.NewWriter(zipBuffer)
+		// Zipping a large skill takes a while before the upload starts
+		zipping := s.progress("Zipping "+skillName, 0, "files")
+		defer zipping.Done()
 
 		err := filepath.Walk(skillPath, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
@@ -239,11 +569,30 @@ func (s *SkillService) UploadSkill(skillPath string) error {
 			if err != nil {
 				return err
 			}
-			zipPath := filepath.Join(skillName, relPath)
-			writer, err := zipWriter.Create(zipPath)
+			// The header records the file mode, so executable scripts stay
+			// executable when the skill is downloaded
+			header, err := zip.FileInfoHeader(info)
+			if err != nil {
+				return err
+			}
+			header.Name = filepath.ToSlash(filepath.Join(skillName, relPath))
+			header.Method = zip.Deflate
+			writer, err := zipWriter.CreateHeader(header)
 			if err != nil {
 				return err
 			}
+			defer zipping.Add(1)
+			if version != "" && relPath == "SKILL.md" {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return err
+				}
+				if content, err = SetFrontmatterVersion(content, version); err != nil {
+					return err
+				}
+				_, err = writer.Write(content)
+				return err
+			}
 			file, err := os.Open(path)
 			if err != nil {
 				return err
@@ -253,11 +602,12 @@ func (s *SkillService) UploadSkill(skillPath string) error {
 			return err
 		})
 		if err != nil {
-			return fmt.Errorf("failed to create ZIP: %w", err)
+			return nil, fmt.Errorf("failed to create ZIP: %w", err)
 		}
 		if err := zipWriter.Close(); err != nil {
-			return err
+			return nil, err
 		}
+		zipping.Done()
 	}
 
 	// Upload ZIP via multipart form
@@ -266,44 +616,54 @@ func (s *SkillService) UploadSkill(skillPath string) error {
 
 	part, err := writer.CreateFormFile("file", fmt.Sprintf("%s.zip", skillName))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	if _, err := io.Copy(part, zipBuffer); err != nil {
-		return err
+		return nil, err
 	}
 
 	if err := writer.Close(); err != nil {
-		return err
+		return nil, err
 	}
 
 	// Send HTTP request
 	uploadURL := fmt.Sprintf("http://%s/nacos/v3/admin/ai/skills/upload?namespaceId=%s",
 		s.client.ServerAddr, s.client.Namespace)
-	req, err := http.NewRequest("POST", uploadURL, body)
+	req, err := http.NewRequest("POST", uploadURL, bytes.NewReader(body.Bytes()))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	req.Header.Set("Content-Type", writer.FormDataContentType())
+	// The body is counted as the transport sends it; GetBody still gives a
+	// retry after a new login the whole body again
+	progress := s.progress(fmt.Sprintf("Uploading %s.zip", skillName), int64(body.Len()), "")
+	req.Body = io.NopCloser(progress.Reader(req.Body))
 
-	if s.client.AccessToken != "" {
-		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", s.client.AccessToken))
-	}
-
-	httpClient := &http.Client{}
-	resp, err := httpClient.Do(req)
+	resp, err := s.client.Do(req)
+	progress.Done()
 	if err != nil {
-		return fmt.Errorf("upload failed: %w", err)
+		return nil, fmt.Errorf("upload failed: %w", err)
 	}
 	defer resp.Body.Close()
 
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, client.WithRequestID(fmt.Errorf("%w (this server may not have the skill upload API; try publishing with --via-config)", client.ParseHTTPError(resp.StatusCode, respBody, "upload skill")), resp)
+	}
 	if resp.StatusCode != 200 {
-		respBody, _ := io.ReadAll(resp.Body)
-		return client.ParseHTTPError(resp.StatusCode, respBody, "upload skill")
+		return nil, client.WithRequestID(client.ParseHTTPError(resp.StatusCode, respBody, "upload skill"), resp)
 	}
-
-	return nil
+	result, err := parseUploadResponse(skillName, respBody)
+	if err != nil {
+		return nil, client.WithRequestID(err, resp)
+	}
+	result.Bytes = int64(body.Len())
+	return result, nil
 }
 
 // ParseSkillMD parses SKILL.md file
@@ -312,7 +672,11 @@ func (s *SkillService) ParseSkillMD(mdPath string) (*SkillInfo, error) {
 	if err != nil {
 		return nil, err
 	}
+	return ParseFrontmatter(content)
+}
 
+// ParseFrontmatter parses the YAML frontmatter of a SKILL.md
+func ParseFrontmatter(content []byte) (*SkillInfo, error) {
 	lines := strings.Split(string(content), "\n")
 	if len(lines) < 3 || lines[0] != "---" {
 		return nil, fmt.Errorf("invalid SKILL.md format")
@@ -341,3 +705,22 @@ func (s *SkillService) ParseSkillMD(mdPath string) (*SkillInfo, error) {
 	return &skillInfo, nil
 }
 
+
+// SkillDirs returns the subdirectories of folder that contain a SKILL.md
+func SkillDirs(folder string) ([]string, error) {
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		return nil, err
+	}
+	var dirs []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		dir := filepath.Join(folder, entry.Name())
+		if _, err := os.Stat(filepath.Join(dir, "SKILL.md")); err == nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, nil
+}
@@ -0,0 +1,233 @@
+package skill
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/nacostest"
+)
+
+func newTestArchive(t *testing.T, files map[string]string) *SkillArchive {
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	w.Create("demo/")
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	r, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &SkillArchive{Name: "demo", reader: r}
+}
+
+func TestSkillArchiveExtract(t *testing.T) {
+	archive := newTestArchive(t, map[string]string{
+		"demo/SKILL.md":       "# demo\n",
+		"demo/scripts/run.sh": "echo hi\n",
+	})
+	dir := t.TempDir()
+
+	if n := archive.FileCount(); n != 2 {
+		t.Errorf("FileCount() = %d, want 2", n)
+	}
+	existing, err := archive.Existing(dir)
+	if err != nil || len(existing) != 0 {
+		t.Fatalf("Existing() before extract = %q, %v; want none", existing, err)
+	}
+
+	result, err := archive.Extract(dir)
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if result.Files != 2 || result.Bytes != 15 || result.Unchanged != 0 || len(result.Skipped) != 0 {
+		t.Errorf("Extract() = %+v, want 2 files and 15 bytes written", result)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "demo", "scripts", "run.sh"))
+	if string(data) != "echo hi\n" {
+		t.Errorf("run.sh = %q, want %q", data, "echo hi\n")
+	}
+
+	os.Remove(filepath.Join(dir, "demo", "scripts", "run.sh"))
+	existing, err = archive.Existing(dir)
+	if err != nil {
+		t.Fatalf("Existing() error = %v", err)
+	}
+	if want := []string{"demo/SKILL.md"}; !reflect.DeepEqual(existing, want) {
+		t.Errorf("Existing() = %q, want %q", existing, want)
+	}
+}
+
+func TestSkillArchiveRejectsTraversal(t *testing.T) {
+	archive := newTestArchive(t, map[string]string{"../evil": "x"})
+	dir := t.TempDir()
+	if _, err := archive.Existing(dir); err == nil {
+		t.Error("Existing() accepted a path traversal entry")
+	}
+	if _, err := archive.Extract(dir); err == nil {
+		t.Error("Extract() accepted a path traversal entry")
+	}
+}
+
+func TestSkillArchiveHashMatchesDir(t *testing.T) {
+	archive := newTestArchive(t, map[string]string{
+		"demo/SKILL.md":       "# demo\n",
+		"demo/scripts/run.sh": "echo hi\n",
+	})
+	want, err := archive.Hash()
+	if err != nil {
+		t.Fatalf("Hash() error = %v", err)
+	}
+
+	dir := filepath.Join(t.TempDir(), "demo.remote")
+	if _, err := archive.ExtractInto(dir); err != nil {
+		t.Fatalf("ExtractInto() error = %v", err)
+	}
+	// Hidden files such as editor state do not count as changes
+	os.WriteFile(filepath.Join(dir, ".DS_Store"), []byte("x"), 0644)
+	if got, err := HashDir(dir); err != nil || got != want {
+		t.Errorf("HashDir() = %q, %v; want %q", got, err, want)
+	}
+
+	os.WriteFile(filepath.Join(dir, "scripts", "run.sh"), []byte("echo edited\n"), 0644)
+	if got, _ := HashDir(dir); got == want {
+		t.Error("HashDir() did not change after a file was edited")
+	}
+}
+
+func TestBinaryResourcesRoundTrip(t *testing.T) {
+	var logo bytes.Buffer
+	img := image.NewRGBA(image.Rect(0, 0, 4, 4))
+	img.Set(1, 2, color.RGBA{R: 255, A: 255})
+	if err := png.Encode(&logo, img); err != nil {
+		t.Fatal(err)
+	}
+	blob := make([]byte, 4096)
+	rand.New(rand.NewSource(1)).Read(blob)
+	if utf8.Valid(blob) {
+		t.Fatal("random blob is valid UTF-8")
+	}
+	files := map[string][]byte{
+		"SKILL.md":        []byte("---\nname: demo\n---\n"),
+		"assets/logo.png": logo.Bytes(),
+		"bin/blob":        blob,
+		"tools/run":       []byte("echo hi"),
+	}
+	src := filepath.Join(t.TempDir(), "demo")
+	for name, data := range files {
+		path := filepath.Join(src, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(src, "tools/run"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	server := nacostest.NewServer(t)
+	c, err := client.NewNacosClient(server.Addr, "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := NewSkillService(c)
+
+	if _, err := svc.UploadSkill(src); err != nil {
+		t.Fatalf("UploadSkill() error = %v", err)
+	}
+	out := t.TempDir()
+	if err := svc.GetSkill("demo", out, "", ""); err != nil {
+		t.Fatalf("GetSkill() error = %v", err)
+	}
+	for name, want := range files {
+		got, err := os.ReadFile(filepath.Join(out, "demo", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, want) {
+			t.Errorf("%s differs after the round trip: %d bytes, want %d", name, len(got), len(want))
+		}
+	}
+
+	// The executable bit recorded at upload survives the download
+	if runtime.GOOS != "windows" {
+		for name, want := range map[string]os.FileMode{"tools/run": execMode, "bin/blob": fileMode} {
+			info, err := os.Stat(filepath.Join(out, "demo", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Mode().Perm() != want {
+				t.Errorf("%s has mode %v, want %v", name, info.Mode().Perm(), want)
+			}
+		}
+	}
+}
+
+func TestDownloadSkillVerifiesContentMD5(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	f, _ := zw.Create("demo/SKILL.md")
+	f.Write([]byte("---\nname: demo\n---\n"))
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	stored := buf.Bytes()
+
+	for _, truncated := range []int{1, 2} {
+		var requests int
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.Header().Set("Content-MD5", client.CalculateMD5(string(stored)))
+			if requests <= truncated {
+				// A proxy cut the response short
+				w.Write(stored[:len(stored)/2])
+				return
+			}
+			w.Write(stored)
+		}))
+		c, _ := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+		c.SetLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+		out := t.TempDir()
+		err := NewSkillService(c).GetSkill("demo", out, "", "")
+		server.Close()
+
+		if truncated == 2 {
+			if !errors.Is(err, client.ErrContentCorrupted) || requests != 2 {
+				t.Errorf("GetSkill() of a download truncated twice = %v after %d requests, want ErrContentCorrupted after 2", err, requests)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("GetSkill() of a download truncated once: %v", err)
+		}
+		if m := readManifest(filepath.Join(out, "demo")); m == nil || m.MD5Check != client.MD5Verified {
+			t.Errorf("manifest = %+v, want the download recorded as verified", m)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package skill
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// UploadResult is what the server reported about an uploaded skill. Servers
+// that reply with no details leave it empty.
+type UploadResult struct {
+	UniformID string
+	// Warnings describes files the server did not accept as uploaded,
+	// e.g. "scripts/run.sh: rejected (file type not allowed)"
+	Warnings []string
+	// Bytes is how much was sent: the upload request, or the configs published
+	Bytes int64
+}
+
+// uploadResponse is the data of an upload response with details
+type uploadResponse struct {
+	Success   *bool  `json:"success"`
+	UniformID string `json:"uniformId"`
+	Message   string `json:"message"`
+	Files     []struct {
+		Name    string `json:"name"`
+		Status  string `json:"status"`
+		Message string `json:"message"`
+	} `json:"files"`
+}
+
+// parseUploadResponse reads the body of a 200 response to a skill upload. It
+// fails when the server says the skill was not registered: a non-zero code,
+// data false, success false, or every file rejected.
+func parseUploadResponse(skillName string, body []byte) (*UploadResult, error) {
+	result := &UploadResult{}
+	var v3Resp V3Response
+	if len(bytes.TrimSpace(body)) == 0 || json.Unmarshal(body, &v3Resp) != nil {
+		// Older servers reply with nothing or plain text
+		return result, nil
+	}
+	if v3Resp.Code != 0 {
+		return nil, fmt.Errorf("upload skill %s failed: code=%d, message=%s", skillName, v3Resp.Code, v3Resp.Message)
+	}
+
+	data := bytes.TrimSpace(v3Resp.Data)
+	switch {
+	case bytes.Equal(data, []byte("false")):
+		return nil, fmt.Errorf("upload skill %s failed: the server did not register it", skillName)
+	case len(data) == 0 || data[0] != '{':
+		// true, a message or a name: accepted without details
+		return result, nil
+	}
+	var resp uploadResponse
+	if err := json.Unmarshal(data, &resp); err != nil {
+		return nil, fmt.Errorf("upload skill %s: invalid response: %w", skillName, err)
+	}
+	result.UniformID = resp.UniformID
+	for _, f := range resp.Files {
+		switch strings.ToLower(f.Status) {
+		case "", "ok", "success":
+			continue
+		}
+		warning := fmt.Sprintf("%s: %s", f.Name, f.Status)
+		if f.Message != "" {
+			warning += fmt.Sprintf(" (%s)", f.Message)
+		}
+		result.Warnings = append(result.Warnings, warning)
+	}
+
+	if resp.Success != nil && !*resp.Success || len(resp.Files) > 0 && len(result.Warnings) == len(resp.Files) {
+		msg := resp.Message
+		if msg == "" {
+			msg = "the server did not register it"
+		}
+		if len(result.Warnings) > 0 {
+			msg += ": " + strings.Join(result.Warnings, "; ")
+		}
+		return nil, fmt.Errorf("upload skill %s failed: %s", skillName, msg)
+	}
+	return result, nil
+}
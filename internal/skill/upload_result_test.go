@@ -0,0 +1,59 @@
+package skill
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseUploadResponse(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    *UploadResult
+		wantErr string
+	}{
+		{name: "empty body", body: "", want: &UploadResult{}},
+		{name: "plain text", body: "ok", want: &UploadResult{}},
+		{name: "data without details", body: `{"code":0,"message":"success","data":"demo"}`, want: &UploadResult{}},
+		{
+			name: "uniformId and all files accepted",
+			body: `{"code":0,"data":{"uniformId":"u-1","files":[{"name":"SKILL.md","status":"success"}]}}`,
+			want: &UploadResult{UniformID: "u-1"},
+		},
+		{
+			name: "some files rejected",
+			body: `{"code":0,"data":{"uniformId":"u-1","files":[{"name":"SKILL.md","status":"ok"},{"name":"bin/tool","status":"rejected","message":"binary files are not allowed"}]}}`,
+			want: &UploadResult{UniformID: "u-1", Warnings: []string{"bin/tool: rejected (binary files are not allowed)"}},
+		},
+		{
+			name:    "every file rejected",
+			body:    `{"code":0,"data":{"files":[{"name":"SKILL.md","status":"failed","message":"no name"}]}}`,
+			wantErr: "SKILL.md: failed (no name)",
+		},
+		{
+			name:    "not registered",
+			body:    `{"code":0,"data":{"success":false,"message":"skill name already taken"}}`,
+			wantErr: "skill name already taken",
+		},
+		{name: "data false", body: `{"code":0,"data":false}`, wantErr: "did not register"},
+		{name: "error code", body: `{"code":21000,"message":"parse zip failed"}`, wantErr: "code=21000, message=parse zip failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUploadResponse("demo", []byte(tt.body))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("parseUploadResponse() error = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseUploadResponse() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseUploadResponse() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
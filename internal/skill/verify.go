@@ -0,0 +1,144 @@
+package skill
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// ErrFileCorrupt is returned (wrapped) when a file does not read back with
+// the content it was written with
+var ErrFileCorrupt = errors.New("file is corrupt")
+
+// ErrNoManifest is returned (wrapped) by VerifySkill for a skill directory
+// without the manifest an install writes
+var ErrNoManifest = errors.New("no manifest")
+
+// Statuses of a file checked by VerifySkill
+const (
+	FileOK       = "ok"
+	FileModified = "modified" // the content differs from what was installed
+	FileMissing  = "missing"  // installed, but no longer there
+	FileAdded    = "added"    // there, but not installed
+)
+
+// FileCheck is the status of one file of an installed skill
+type FileCheck struct {
+	Path   string `json:"path"` // relative to the skill directory, slash-separated
+	Status string `json:"status"`
+}
+
+// VerifyResult is the outcome of checking an installed skill
+type VerifyResult struct {
+	Name  string      `json:"name"`
+	Files []FileCheck `json:"files"`
+}
+
+// OK reports whether every file is as installed
+func (r *VerifyResult) OK() bool {
+	for _, f := range r.Files {
+		if f.Status != FileOK {
+			return false
+		}
+	}
+	return true
+}
+
+// Problems returns the files that are not as installed
+func (r *VerifyResult) Problems() []FileCheck {
+	var problems []FileCheck
+	for _, f := range r.Files {
+		if f.Status != FileOK {
+			problems = append(problems, f)
+		}
+	}
+	return problems
+}
+
+// VerifySkill checks the files of an installed skill against the hashes its
+// install recorded in the manifest, to find files that were changed, removed
+// or added since. Hidden files not in the manifest, such as .git, are ignored.
+func VerifySkill(skillDir string) (*VerifyResult, error) {
+	m := readManifest(skillDir)
+	if m == nil {
+		return nil, fmt.Errorf("%w in %s: install the skill again with skill-get to record its files", ErrNoManifest, skillDir)
+	}
+	result := &VerifyResult{Name: filepath.Base(skillDir)}
+	for rel, entry := range m.Files {
+		status := FileOK
+		sum, err := fileSHA256(filepath.Join(skillDir, filepath.FromSlash(rel)))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			status = FileMissing
+		case err != nil:
+			return nil, err
+		case sum != entry.SHA256:
+			status = FileModified
+		}
+		result.Files = append(result.Files, FileCheck{Path: rel, Status: status})
+	}
+
+	err := filepath.WalkDir(skillDir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(skillDir, path)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if _, ok := m.Files[rel]; ok {
+			return nil
+		}
+		if hiddenPath(rel) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.IsDir() {
+			result.Files = append(result.Files, FileCheck{Path: rel, Status: FileAdded})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(result.Files, func(i, j int) bool { return result.Files[i].Path < result.Files[j].Path })
+	return result, nil
+}
+
+// checkFile reads a written file back and compares it with the SHA-256 of
+// the content it was written with
+func checkFile(path, sum string) error {
+	got, err := fileSHA256(path)
+	if err != nil {
+		return fmt.Errorf("failed to read back %s: %w", path, err)
+	}
+	if got != sum {
+		return fmt.Errorf("%w: %s does not read back as written (is the disk full?)", ErrFileCorrupt, path)
+	}
+	return nil
+}
+
+// fileSHA256 returns the hex SHA-256 of a file's content
+func fileSHA256(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
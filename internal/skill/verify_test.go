@@ -0,0 +1,82 @@
+package skill
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestVerifySkill(t *testing.T) {
+	dir := t.TempDir()
+	archive := newTestArchive(t, map[string]string{
+		"demo/SKILL.md":         "v1",
+		"demo/run.sh":           "echo hi",
+		"demo/docs/guide.md":    "guide",
+		"demo/docs/removed.txt": "soon gone",
+	})
+	if _, err := archive.Extract(dir); err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	skillDir := filepath.Join(dir, "demo")
+
+	result, err := VerifySkill(skillDir)
+	if err != nil {
+		t.Fatalf("VerifySkill() error = %v", err)
+	}
+	if !result.OK() || len(result.Files) != 4 {
+		t.Fatalf("VerifySkill() of a fresh install = %+v, want 4 ok files", result.Files)
+	}
+
+	os.WriteFile(filepath.Join(skillDir, "run.sh"), []byte("echo pwned"), 0755)
+	os.Remove(filepath.Join(skillDir, "docs", "removed.txt"))
+	os.WriteFile(filepath.Join(skillDir, "docs", "extra.md"), []byte("extra"), 0644)
+	os.MkdirAll(filepath.Join(skillDir, ".git"), 0755)
+	os.WriteFile(filepath.Join(skillDir, ".git", "HEAD"), []byte("ref"), 0644)
+
+	result, err = VerifySkill(skillDir)
+	if err != nil {
+		t.Fatalf("VerifySkill() error = %v", err)
+	}
+	want := []FileCheck{
+		{Path: "SKILL.md", Status: FileOK},
+		{Path: "docs/extra.md", Status: FileAdded},
+		{Path: "docs/guide.md", Status: FileOK},
+		{Path: "docs/removed.txt", Status: FileMissing},
+		{Path: "run.sh", Status: FileModified},
+	}
+	if !reflect.DeepEqual(result.Files, want) {
+		t.Errorf("VerifySkill() = %+v, want %+v", result.Files, want)
+	}
+	if result.OK() || len(result.Problems()) != 3 {
+		t.Errorf("OK() = %v, Problems() = %+v; want 3 problems", result.OK(), result.Problems())
+	}
+}
+
+func TestVerifySkillWithoutManifest(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("hand-made"), 0644)
+	if _, err := VerifySkill(dir); !errors.Is(err, ErrNoManifest) {
+		t.Errorf("VerifySkill() error = %v, want ErrNoManifest", err)
+	}
+}
+
+func TestCheckFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "SKILL.md")
+	content := []byte("complete content")
+	os.WriteFile(path, content, 0644)
+	sum, err := fileSHA256(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := checkFile(path, sum); err != nil {
+		t.Errorf("checkFile() of an intact file = %v", err)
+	}
+
+	// What a full disk leaves behind: the write succeeded, the file is short
+	os.WriteFile(path, content[:8], 0644)
+	if err := checkFile(path, sum); !errors.Is(err, ErrFileCorrupt) {
+		t.Errorf("checkFile() of a truncated file = %v, want ErrFileCorrupt", err)
+	}
+}
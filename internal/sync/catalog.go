@@ -0,0 +1,205 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+)
+
+// DefaultCatalogGroup is the group of a catalog given without one
+const DefaultCatalogGroup = "DEFAULT_GROUP"
+
+// Catalog is a config listing the skills to sync, e.g. skills.index in
+// DEFAULT_GROUP; see SetCatalog
+type Catalog struct {
+	DataID string
+	Group  string
+}
+
+// String returns the catalog as --catalog takes it
+func (c Catalog) String() string {
+	return c.DataID + ":" + c.Group
+}
+
+// ParseCatalogRef parses a --catalog value of the form dataId[:group]
+func ParseCatalogRef(value string) (Catalog, error) {
+	dataID, group, _ := strings.Cut(value, ":")
+	if dataID == "" {
+		return Catalog{}, fmt.Errorf("invalid catalog %q: expected dataId:group", value)
+	}
+	if group == "" {
+		group = DefaultCatalogGroup
+	}
+	return Catalog{DataID: dataID, Group: group}, nil
+}
+
+// ParseCatalog returns the skill names a catalog lists: a JSON array of
+// names, or one name per line. Blank lines and lines starting with # are
+// skipped, and a name listed twice is returned once.
+func ParseCatalog(content string) ([]string, error) {
+	var names []string
+	if trimmed := strings.TrimSpace(content); strings.HasPrefix(trimmed, "[") {
+		if err := json.Unmarshal([]byte(trimmed), &names); err != nil {
+			return nil, fmt.Errorf("invalid catalog: %w", err)
+		}
+	} else {
+		for _, line := range strings.Split(content, "\n") {
+			if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+				names = append(names, line)
+			}
+		}
+	}
+	var unique []string
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if strings.ContainsAny(name, "/\\") {
+			return nil, fmt.Errorf("invalid catalog: %q is not a skill name", name)
+		}
+		if name != "" && !slices.Contains(unique, name) {
+			unique = append(unique, name)
+		}
+	}
+	return unique, nil
+}
+
+// SetCatalog makes Run watch catalog and follow it: skills added to it are
+// synced and watched, and skills taken off it are removed locally as if they
+// had been deleted in Nacos
+func (s *SkillSyncer) SetCatalog(catalog Catalog) {
+	s.catalog = &catalog
+}
+
+// CatalogSkillNames returns the skills the catalog set with SetCatalog lists
+// that the filter matches, logging those it filters out
+func (s *SkillSyncer) CatalogSkillNames() ([]string, error) {
+	names, err := s.readCatalog()
+	if err != nil {
+		return nil, err
+	}
+	return s.filterNames(names), nil
+}
+
+// readCatalog fetches and parses the catalog
+func (s *SkillSyncer) readCatalog() ([]string, error) {
+	content, err := s.client.GetConfig(s.catalog.DataID, s.catalog.Group)
+	if err != nil {
+		return nil, fmt.Errorf("read catalog %s: %w", s.catalog, err)
+	}
+	return ParseCatalog(content)
+}
+
+// catalogItem is the listener item of the catalog
+func (s *SkillSyncer) catalogItem() listener.ConfigItem {
+	return listener.ConfigItem{DataID: s.catalog.DataID, Group: s.catalog.Group, Tenant: s.client.Namespace}
+}
+
+// isCatalog reports whether dataID and group are the catalog's
+func (s *SkillSyncer) isCatalog(dataID, group string) bool {
+	return s.catalog != nil && dataID == s.catalog.DataID && group == s.catalog.Group
+}
+
+// catalogChanged re-reads the catalog and syncs the skills added to it and
+// removes those taken off. A deleted catalog leaves the skills as they are,
+// so that losing the catalog does not wipe every skill.
+func (s *SkillSyncer) catalogChanged() error {
+	names, err := s.readCatalog()
+	if errors.Is(err, client.ErrConfigNotFound) {
+		s.log.Warn(fmt.Sprintf("Catalog %s was deleted; still watching the %d skill(s) it listed", s.catalog, len(s.skillNames())), "event", EventDeleted)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	kept, _ := s.filter.Apply(names)
+	s.applyCatalog(kept)
+	return nil
+}
+
+// applyCatalog makes names the skills that are synced
+func (s *SkillSyncer) applyCatalog(names []string) {
+	current := s.skillNames()
+	var added, removed []string
+	for _, name := range names {
+		if !slices.Contains(current, name) {
+			added = append(added, name)
+		}
+	}
+	for _, name := range current {
+		if !slices.Contains(names, name) {
+			removed = append(removed, name)
+		}
+	}
+	if len(added) == 0 && len(removed) == 0 {
+		s.log.Info(fmt.Sprintf("Catalog %s changed; the skills it lists are the same", s.catalog))
+		return
+	}
+	s.log.Info(fmt.Sprintf("Catalog %s changed: %d skill(s) added, %d removed", s.catalog, len(added), len(removed)),
+		"added", len(added), "removed", len(removed))
+	s.setSkillNames(names)
+
+	for _, name := range removed {
+		if s.listener != nil {
+			s.listener.WatchGroup(s.client.Namespace, skillGroupPrefix+name, nil)
+		}
+		s.drop(name)
+	}
+	for _, name := range added {
+		s.skillLog(name).Info(fmt.Sprintf("Skill %s was added to the catalog", name), "event", EventChanged)
+		s.download(name, "", nil)
+		if s.listener != nil {
+			items := s.watchItems(name)
+			s.listener.Prime(items)
+			s.status.md5(name, items[0].MD5)
+			s.listener.WatchGroup(s.client.Namespace, skillGroupPrefix+name, items)
+		}
+	}
+}
+
+// drop removes a skill taken off the catalog like one deleted in Nacos: the
+// local copy is deleted unless it was edited. A skill never synced is only
+// no longer watched.
+func (s *SkillSyncer) drop(name string) {
+	unlock := s.lock(name)
+	defer unlock()
+	log := s.skillLog(name)
+	event, err := s.remove(name, nil)
+	switch {
+	case err != nil:
+		s.event(name, EventError)
+		s.status.event(name, EventError, err)
+		s.hooks.run(name, HookError, s.skillDir(name), err, nil)
+		log.Error(fmt.Sprintf("Failed to remove skill %s taken off the catalog: %v", name, err), "event", EventError, "error", err)
+	case event == "":
+		log.Info(fmt.Sprintf("Skill %s was taken off the catalog", name))
+	default:
+		s.event(name, event)
+		s.status.event(name, event, nil)
+		s.hooks.run(name, HookDeleted, s.skillDir(name), nil, nil)
+		log = log.With("event", event)
+		if _, err := os.Stat(s.skillDir(name)); err == nil {
+			log.Warn(fmt.Sprintf("Skill %s was taken off the catalog; kept %s because it has local edits", name, s.skillDir(name)))
+		} else {
+			log.Info(fmt.Sprintf("Skill %s was taken off the catalog; removed %s", name, s.skillDir(name)))
+		}
+	}
+}
+
+// skillNames returns the skills being synced
+func (s *SkillSyncer) skillNames() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.names)
+}
+
+// setSkillNames replaces the skills being synced
+func (s *SkillSyncer) setSkillNames(names []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.names = slices.Clone(names)
+}
@@ -0,0 +1,96 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+func TestParseCatalogRef(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Catalog
+		wantErr bool
+	}{
+		{"skills.index:DEFAULT_GROUP", Catalog{"skills.index", "DEFAULT_GROUP"}, false},
+		{"skills.index:team", Catalog{"skills.index", "team"}, false},
+		{"skills.index", Catalog{"skills.index", DefaultCatalogGroup}, false},
+		{":team", Catalog{}, true},
+		{"", Catalog{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseCatalogRef(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseCatalogRef(%q) = %+v, %v; want %+v, error %v", tt.value, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestParseCatalog(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{"JSON array", `["a", "b"]`, []string{"a", "b"}, false},
+		{"JSON array with whitespace", "\n  [\"a\",\n \"b\"]\n", []string{"a", "b"}, false},
+		{"lines", "a\nb\n", []string{"a", "b"}, false},
+		{"CRLF, blanks and comments", "# skills\r\na\r\n\r\n  b  \r\n", []string{"a", "b"}, false},
+		{"duplicates", "a\nb\na", []string{"a", "b"}, false},
+		{"empty", "", nil, false},
+		{"empty JSON array", "[]", nil, false},
+		{"bad JSON", `["a",`, nil, true},
+		{"JSON of the wrong type", `[1, 2]`, nil, true},
+		{"path instead of a name", "a\n../etc", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCatalog(tt.content)
+			if (err != nil) != tt.wantErr || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseCatalog() = %q, %v; want %q, error %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestApplyCatalog(t *testing.T) {
+	c, _ := newSkillServer(t)
+	out := t.TempDir()
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetCatalog(Catalog{DataID: "skills.index", Group: DefaultCatalogGroup})
+	var events []string
+	syncer.SetEventHandler(func(skill, event string) { events = append(events, skill+":"+event) })
+	local := filepath.Join(out, "demo")
+
+	syncer.applyCatalog([]string{"demo"})
+	if got := readSkillMD(t, local); got != "v1" {
+		t.Errorf("SKILL.md of a skill added to the catalog = %q, want v1", got)
+	}
+
+	// Taking a skill off the catalog removes it like deleting it in Nacos
+	syncer.applyCatalog(nil)
+	if _, err := os.Stat(local); !os.IsNotExist(err) {
+		t.Errorf("local copy of a skill taken off the catalog still exists: %v", err)
+	}
+	if got := syncer.skillNames(); len(got) != 0 {
+		t.Errorf("skillNames() = %q after the catalog emptied", got)
+	}
+
+	// A skill edited locally is kept
+	syncer.applyCatalog([]string{"demo"})
+	os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("mine"), 0644)
+	syncer.applyCatalog(nil)
+	if got := readSkillMD(t, local); got != "mine" {
+		t.Errorf("SKILL.md = %q, want the local edit kept", got)
+	}
+
+	want := []string{"demo:" + EventSynced, "demo:" + EventDeleted, "demo:" + EventSynced, "demo:" + EventDeleted}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
@@ -0,0 +1,376 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+// EventDrifted is logged for a config changed in Nacos although its file did
+// not change since it was applied
+const EventDrifted = "drifted"
+
+// ConfigFile is a local file published as a config by a ConfigApplier
+type ConfigFile struct {
+	DataID string
+	Group  string
+	Path   string
+}
+
+// ConfigApplier publishes a directory of files as configs: <dir>/<dataId>
+// to its default group and <dir>/<group>/<dataId> to that group
+type ConfigApplier struct {
+	client          *client.NacosClient
+	dir             string
+	group           string
+	interval        time.Duration
+	overwriteRemote bool
+	applied         map[string]string // path -> MD5 of the content last published or found in Nacos
+	warned          map[string]string // path -> MD5 of the drifted config in Nacos last warned about
+	log             *slog.Logger
+}
+
+// NewConfigApplier creates an applier for dir whose top-level files go to
+// group. It reports progress through logger, or to stderr if logger is nil.
+func NewConfigApplier(nacosClient *client.NacosClient, dir, group string, logger *slog.Logger) *ConfigApplier {
+	if logger == nil {
+		logger = logging.Stderr()
+	}
+	return &ConfigApplier{
+		client:  nacosClient,
+		dir:     dir,
+		group:   group,
+		applied: make(map[string]string),
+		warned:  make(map[string]string),
+		log:     logger,
+	}
+}
+
+// SetInterval sets how often Run compares every file with its config in
+// Nacos; zero only reacts to local changes
+func (a *ConfigApplier) SetInterval(interval time.Duration) {
+	a.interval = interval
+}
+
+// SetOverwriteRemote sets whether a config changed in Nacos since its file
+// was applied is published again, instead of only warned about
+func (a *ConfigApplier) SetOverwriteRemote(overwrite bool) {
+	a.overwriteRemote = overwrite
+}
+
+// Files lists the configs of the directory, ordered by path. Hidden files and
+// editor swap or backup files are skipped.
+func (a *ConfigApplier) Files() ([]ConfigFile, error) {
+	var files []ConfigFile
+	err := filepath.WalkDir(a.dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(a.dir, path)
+		if err != nil || ignoredPath(rel) {
+			if d.IsDir() && rel != "." {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+		f, err := a.configFile(rel)
+		if err != nil {
+			return err
+		}
+		files = append(files, f)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, nil
+}
+
+// configFile maps the path rel, relative to the directory, to its config
+func (a *ConfigApplier) configFile(rel string) (ConfigFile, error) {
+	f := ConfigFile{Group: a.group, Path: filepath.Join(a.dir, rel)}
+	parts := strings.Split(filepath.ToSlash(rel), "/")
+	switch len(parts) {
+	case 1:
+		if a.group == "" {
+			return ConfigFile{}, fmt.Errorf("%s: the group of top-level files is required (use --group or set defaults.group in the config file)", f.Path)
+		}
+		f.DataID = parts[0]
+	case 2:
+		f.Group, f.DataID = parts[0], parts[1]
+	default:
+		return ConfigFile{}, fmt.Errorf("%s: configs are <dataId> or <group>/<dataId>, not nested deeper", f.Path)
+	}
+	return f, nil
+}
+
+// Apply publishes every file whose content differs from its config in Nacos,
+// creating configs that do not exist yet. A file that fails is reported and
+// the others are still applied; the error then says how many failed.
+func (a *ConfigApplier) Apply() error {
+	files, err := a.Files()
+	if err != nil {
+		return err
+	}
+	counts := make(map[string]int)
+	for _, f := range files {
+		event, err := a.reconcile(f, true)
+		if err != nil {
+			a.configLog(f).Error(fmt.Sprintf("Failed to apply %s to config %s (%s): %v", f.Path, f.DataID, f.Group, err), "event", EventError, "error", err)
+			event = EventError
+		}
+		counts[event]++
+	}
+	a.log.Info(fmt.Sprintf("Applied %d file(s): %d published, %d up to date, %d failed", len(files), counts[EventPushed], counts[EventUpToDate], counts[EventError]),
+		"files", len(files), "published", counts[EventPushed], "upToDate", counts[EventUpToDate], "failed", counts[EventError])
+	if n := counts[EventError]; n > 0 {
+		return fmt.Errorf("%d of %d file(s) failed", n, len(files))
+	}
+	return nil
+}
+
+// reconcile compares a file with its config in Nacos and publishes the file
+// when they differ, returning EventPushed, EventUpToDate or EventDrifted. A
+// config that differs from a file unchanged since it was applied was changed
+// in Nacos: unless initial or overwriteRemote, it is only warned about, and
+// only once for each version of the config.
+func (a *ConfigApplier) reconcile(f ConfigFile, initial bool) (string, error) {
+	data, err := os.ReadFile(f.Path)
+	if err != nil {
+		return "", err
+	}
+	content := string(data)
+	localMD5 := client.CalculateMD5(content)
+
+	remote, err := a.client.GetConfigDetail(f.DataID, f.Group)
+	if err != nil && !errors.Is(err, client.ErrConfigNotFound) {
+		return "", err
+	}
+	if remote != nil && remote.Content == content {
+		a.applied[f.Path] = localMD5
+		delete(a.warned, f.Path)
+		a.configLog(f).Debug(fmt.Sprintf("Config %s (%s) is up to date", f.DataID, f.Group), "event", EventUpToDate)
+		return EventUpToDate, nil
+	}
+
+	log := a.configLog(f)
+	if !initial && a.applied[f.Path] == localMD5 {
+		if !a.overwriteRemote {
+			// A config deleted in Nacos is recorded as ""
+			var remoteMD5 string
+			if remote != nil {
+				remoteMD5 = client.CalculateMD5(remote.Content)
+			}
+			if warned, ok := a.warned[f.Path]; ok && warned == remoteMD5 {
+				log.Debug(fmt.Sprintf("Config %s (%s) still differs from %s", f.DataID, f.Group, f.Path), "event", EventDrifted)
+				return EventDrifted, nil
+			}
+			a.warned[f.Path] = remoteMD5
+			log.Warn(fmt.Sprintf("Config %s (%s) was changed in Nacos and no longer matches %s (use --overwrite-remote to publish the file again)", f.DataID, f.Group, f.Path), "event", EventDrifted)
+			return EventDrifted, nil
+		}
+		log.Info(fmt.Sprintf("Config %s (%s) was changed in Nacos; publishing %s again", f.DataID, f.Group, f.Path), "event", EventDrifted)
+	}
+	if err := a.publish(f, content, localMD5); err != nil {
+		return "", err
+	}
+	return EventPushed, nil
+}
+
+// publish publishes content as the config of f and records it as applied
+func (a *ConfigApplier) publish(f ConfigFile, content, contentMD5 string) error {
+	start := time.Now()
+	if err := a.client.PublishConfig(f.DataID, f.Group, content); err != nil {
+		return err
+	}
+	a.applied[f.Path] = contentMD5
+	delete(a.warned, f.Path)
+	a.configLog(f).Info(fmt.Sprintf("Published %s to config %s (%s)", f.Path, f.DataID, f.Group), "event", EventPushed, "md5", contentMD5, "duration", time.Since(start))
+	return nil
+}
+
+// Run applies the directory once, then publishes each file shortly after it
+// changes, if its content differs from what was last applied. With an
+// interval every file is also compared with Nacos that often, to catch
+// configs changed there. Removing a file leaves its config in Nacos. A failed
+// publish is retried with backoff. Run blocks until ctx is cancelled.
+func (a *ConfigApplier) Run(ctx context.Context) error {
+	files, err := a.Files()
+	if err != nil {
+		return err
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch local files: %w", err)
+	}
+	defer watcher.Close()
+	if err := watchTree(watcher, a.dir); err != nil {
+		return fmt.Errorf("watch %s: %w", a.dir, err)
+	}
+
+	pending := make(map[string]*pendingPush)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	// Files that failed the first apply are retried like changed files
+	if err := a.Apply(); err != nil {
+		for _, f := range files {
+			if _, ok := a.applied[f.Path]; !ok {
+				if rel, err := filepath.Rel(a.dir, f.Path); err == nil {
+					a.enqueue(pending, filepath.ToSlash(rel))
+				}
+			}
+		}
+		resetTimer(timer, pending)
+	}
+	msg := fmt.Sprintf("Watching %s for changes", a.dir)
+	var reconcile <-chan time.Time
+	if a.interval > 0 {
+		ticker := time.NewTicker(a.interval)
+		defer ticker.Stop()
+		reconcile = ticker.C
+		msg += fmt.Sprintf(", comparing with Nacos every %s", a.interval)
+	}
+	a.log.Info(msg, "dir", a.dir, "interval", a.interval)
+
+	for {
+		select {
+		case <-ctx.Done():
+			a.log.Info("Apply stopped")
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			rel, err := filepath.Rel(a.dir, event.Name)
+			if err != nil || ignoredPath(rel) {
+				continue
+			}
+			info, err := os.Stat(event.Name)
+			isDir := err == nil && info.IsDir()
+			if isDir && !event.Has(fsnotify.Create) {
+				continue
+			}
+			if isDir {
+				// A new or moved-in group directory: watch it and apply what it holds
+				_ = watchTree(watcher, event.Name)
+				files := make(map[string]bool)
+				addFiles(files, a.dir, event.Name)
+				for file := range files {
+					a.enqueue(pending, file)
+				}
+			} else {
+				a.enqueue(pending, filepath.ToSlash(rel))
+			}
+			resetTimer(timer, pending)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			a.log.Warn(fmt.Sprintf("Watch error: %v", err), "error", err)
+
+		case <-timer.C:
+			now := time.Now()
+			for rel, change := range pending {
+				if change.due.After(now) {
+					continue
+				}
+				if a.applyChange(rel, change) {
+					delete(pending, rel)
+				}
+			}
+			resetTimer(timer, pending)
+
+		case <-reconcile:
+			a.reconcileAll(pending)
+			resetTimer(timer, pending)
+		}
+	}
+}
+
+// enqueue (re)starts the debounce window of the file rel, a slash-separated
+// path relative to the directory
+func (a *ConfigApplier) enqueue(pending map[string]*pendingPush, rel string) {
+	change, ok := pending[rel]
+	if !ok {
+		change = &pendingPush{files: map[string]bool{rel: true}, retry: listener.Backoff{Base: pushRetryBase, Max: listener.MaxBackoff}}
+		pending[rel] = change
+	}
+	change.due = time.Now().Add(pushDebounce)
+}
+
+// applyChange publishes a changed file unless its content is what was last
+// applied, and reports whether it is done. On failure the next attempt is
+// scheduled with backoff.
+func (a *ConfigApplier) applyChange(rel string, change *pendingPush) bool {
+	f, err := a.configFile(filepath.FromSlash(rel))
+	if err != nil {
+		a.log.Warn(fmt.Sprintf("Ignoring %v", err), "path", filepath.Join(a.dir, rel))
+		return true
+	}
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		if _, ok := a.applied[f.Path]; ok {
+			delete(a.applied, f.Path)
+			delete(a.warned, f.Path)
+			a.configLog(f).Warn(fmt.Sprintf("%s was removed; config %s (%s) is kept in Nacos", f.Path, f.DataID, f.Group), "event", EventDeleted)
+		}
+		return true
+	}
+	if err == nil {
+		content := string(data)
+		contentMD5 := client.CalculateMD5(content)
+		if a.applied[f.Path] == contentMD5 {
+			return true
+		}
+		if err = a.publish(f, content, contentMD5); err == nil {
+			return true
+		}
+	}
+	delay := change.retry.Next()
+	change.due = time.Now().Add(delay)
+	a.configLog(f).Error(fmt.Sprintf("Failed to apply %s to config %s (%s): %v (retrying in %s)", f.Path, f.DataID, f.Group, err, delay.Round(time.Second)), "event", EventError, "error", err, "retryIn", delay)
+	return false
+}
+
+// reconcileAll compares every file with its config in Nacos. Files waiting
+// for their debounce window are left to it.
+func (a *ConfigApplier) reconcileAll(pending map[string]*pendingPush) {
+	files, err := a.Files()
+	if err != nil {
+		a.log.Error(fmt.Sprintf("Failed to list %s: %v", a.dir, err), "event", EventError, "error", err)
+		return
+	}
+	for _, f := range files {
+		rel, err := filepath.Rel(a.dir, f.Path)
+		if err != nil || pending[filepath.ToSlash(rel)] != nil {
+			continue
+		}
+		if _, err := a.reconcile(f, false); err != nil {
+			a.configLog(f).Error(fmt.Sprintf("Failed to reconcile config %s (%s) with %s: %v", f.DataID, f.Group, f.Path, err), "event", EventError, "error", err)
+		}
+	}
+}
+
+// configLog returns the logger with the config attributes set
+func (a *ConfigApplier) configLog(f ConfigFile) *slog.Logger {
+	return a.log.With("dataId", f.DataID, "group", f.Group, "path", f.Path)
+}
@@ -0,0 +1,188 @@
+package sync
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/nacostest"
+)
+
+// newApplyDir creates a config directory holding the given files, keyed by
+// slash-separated paths
+func newApplyDir(t *testing.T, files map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func newApplyClient(t *testing.T, server *nacostest.Server) *client.NacosClient {
+	t.Helper()
+	c, err := client.NewNacosClient(server.Addr, "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestConfigApplierApply(t *testing.T) {
+	server := nacostest.NewServer(t)
+	server.SetConfig("app.yaml", "DEFAULT_GROUP", "a: 1")
+	server.SetConfig("db.properties", "prod", "url=old")
+	dir := newApplyDir(t, map[string]string{
+		"app.yaml":           "a: 1",
+		"prod/db.properties": "url=new",
+		"prod/new.json":      "{}",
+		".git/config":        "[core]",
+		"app.yaml.swp":       "swap",
+	})
+
+	quiet := slog.New(slog.NewTextHandler(io.Discard, nil))
+	applier := NewConfigApplier(newApplyClient(t, server), dir, "DEFAULT_GROUP", quiet)
+	files, err := applier.Files()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []ConfigFile{
+		{DataID: "app.yaml", Group: "DEFAULT_GROUP", Path: filepath.Join(dir, "app.yaml")},
+		{DataID: "db.properties", Group: "prod", Path: filepath.Join(dir, "prod", "db.properties")},
+		{DataID: "new.json", Group: "prod", Path: filepath.Join(dir, "prod", "new.json")},
+	}
+	if len(files) != len(want) {
+		t.Fatalf("Files() = %+v, want %+v", files, want)
+	}
+	for i := range want {
+		if files[i] != want[i] {
+			t.Errorf("Files()[%d] = %+v, want %+v", i, files[i], want[i])
+		}
+	}
+
+	if err := applier.Apply(); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	for _, c := range []struct{ dataID, group, want string }{
+		{"app.yaml", "DEFAULT_GROUP", "a: 1"},
+		{"db.properties", "prod", "url=new"},
+		{"new.json", "prod", "{}"},
+	} {
+		if got, ok := server.Config(c.dataID, c.group); !ok || got != c.want {
+			t.Errorf("config %s (%s) = %q, %v, want %q", c.dataID, c.group, got, ok, c.want)
+		}
+	}
+
+	// Only <dataId> and <group>/<dataId> map to configs
+	nested := newApplyDir(t, map[string]string{"prod/eu/app.yaml": "a: 1"})
+	if err := NewConfigApplier(newApplyClient(t, server), nested, "DEFAULT_GROUP", quiet).Apply(); err == nil {
+		t.Error("Apply() of a nested directory succeeded, want an error")
+	}
+}
+
+func TestConfigApplierWatch(t *testing.T) {
+	for _, overwrite := range []bool{false, true} {
+		server := nacostest.NewServer(t)
+		dir := newApplyDir(t, map[string]string{"app.yaml": "a: 1"})
+		path := filepath.Join(dir, "app.yaml")
+
+		logs := make(chan string, 100)
+		applier := NewConfigApplier(newApplyClient(t, server), dir, "DEFAULT_GROUP", slog.New(logging.NewConsoleHandler(func(msg string) {
+			logs <- msg
+		})))
+		applier.SetInterval(100 * time.Millisecond)
+		applier.SetOverwriteRemote(overwrite)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() { done <- applier.Run(ctx) }()
+
+		waitFor := func(prefix string) {
+			t.Helper()
+			for {
+				select {
+				case line := <-logs:
+					if strings.HasPrefix(line, prefix) {
+						return
+					}
+				case <-time.After(10 * time.Second):
+					t.Fatalf("overwrite %v: timed out waiting for %q", overwrite, prefix)
+				}
+			}
+		}
+		waitFor("Watching")
+		if got, _ := server.Config("app.yaml", "DEFAULT_GROUP"); got != "a: 1" {
+			t.Errorf("overwrite %v: config after the first apply = %q, want %q", overwrite, got, "a: 1")
+		}
+
+		// A local edit is published
+		if err := os.WriteFile(path, []byte("a: 2"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		waitFor("Published")
+		if got, _ := server.Config("app.yaml", "DEFAULT_GROUP"); got != "a: 2" {
+			t.Errorf("overwrite %v: config after a local edit = %q, want %q", overwrite, got, "a: 2")
+		}
+
+		// An edit in Nacos is found by the next reconcile
+		server.SetConfig("app.yaml", "DEFAULT_GROUP", "a: 3")
+		waitFor("Config app.yaml (DEFAULT_GROUP) was changed in Nacos")
+		want := "a: 3"
+		if overwrite {
+			waitFor("Published")
+			want = "a: 2"
+		}
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("overwrite %v: Run() error = %v", overwrite, err)
+		}
+		if got, _ := server.Config("app.yaml", "DEFAULT_GROUP"); got != want {
+			t.Errorf("overwrite %v: config after an edit in Nacos = %q, want %q", overwrite, got, want)
+		}
+	}
+}
+
+func TestConfigApplierWarnsOnceAboutDrift(t *testing.T) {
+	server := nacostest.NewServer(t)
+	dir := newApplyDir(t, map[string]string{"app.yaml": "a: 1"})
+
+	var warnings []string
+	applier := NewConfigApplier(newApplyClient(t, server), dir, "DEFAULT_GROUP", slog.New(logging.NewConsoleHandler(func(msg string) {
+		if strings.HasPrefix(msg, "Config app.yaml (DEFAULT_GROUP) was changed in Nacos") {
+			warnings = append(warnings, msg)
+		}
+	})))
+	if err := applier.Apply(); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+
+	// Each reconcile finds the same drift; it is warned about once
+	server.SetConfig("app.yaml", "DEFAULT_GROUP", "a: 2")
+	applier.reconcileAll(nil)
+	applier.reconcileAll(nil)
+	if len(warnings) != 1 {
+		t.Errorf("warnings after two reconciles of one drift = %q, want 1", warnings)
+	}
+
+	// Another edit in Nacos is warned about again
+	server.SetConfig("app.yaml", "DEFAULT_GROUP", "a: 3")
+	applier.reconcileAll(nil)
+	if len(warnings) != 2 {
+		t.Errorf("warnings after a second drift = %q, want 2", warnings)
+	}
+	if got, _ := server.Config("app.yaml", "DEFAULT_GROUP"); got != "a: 3" {
+		t.Errorf("config = %q, want the edit in Nacos kept", got)
+	}
+}
@@ -0,0 +1,304 @@
+package sync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"gopkg.in/yaml.v3"
+)
+
+// What a ConfigSyncer does with the file of a config deleted in Nacos
+const (
+	OnDeleteKeep   = "keep"
+	OnDeleteDelete = "delete"
+)
+
+// ConfigMapping mirrors one config to a local file
+type ConfigMapping struct {
+	DataID string `yaml:"dataId"`
+	Group  string `yaml:"group"`
+	Path   string `yaml:"path"`
+	Reload string `yaml:"reload"` // command run after the file is rewritten or removed
+}
+
+// mappingFile is the layout of a file read by LoadConfigMappings
+type mappingFile struct {
+	Configs []ConfigMapping `yaml:"configs"`
+}
+
+// ParseConfigMapping parses dataId:group:path. The path may itself contain
+// colons, such as a Windows drive letter; the group may be empty.
+func ParseConfigMapping(s string) (ConfigMapping, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) != 3 || parts[0] == "" || parts[2] == "" {
+		return ConfigMapping{}, fmt.Errorf("invalid mapping %q: expected dataId:group:path", s)
+	}
+	return ConfigMapping{DataID: parts[0], Group: parts[1], Path: parts[2]}, nil
+}
+
+// LoadConfigMappings reads mappings from a YAML file with a top-level
+// "configs" list. Relative paths are taken relative to the file's directory.
+func LoadConfigMappings(path string) ([]ConfigMapping, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var file mappingFile
+	if err := yaml.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	for i, m := range file.Configs {
+		if m.DataID == "" || m.Path == "" {
+			return nil, fmt.Errorf("%s: config %d needs a dataId and a path", path, i+1)
+		}
+		if !filepath.IsAbs(m.Path) && !strings.HasPrefix(m.Path, "~") {
+			file.Configs[i].Path = filepath.Join(filepath.Dir(path), m.Path)
+		}
+	}
+	return file.Configs, nil
+}
+
+// ConfigSyncer keeps local files up to date with configs in Nacos
+type ConfigSyncer struct {
+	client       *client.NacosClient
+	mappings     []ConfigMapping
+	onDelete     string
+	pollTimeout  time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	log          *slog.Logger
+	hooks        *hookRunner
+}
+
+// NewConfigSyncer creates a syncer for mappings that reports progress through
+// logger, or to stderr if logger is nil. Files of deleted configs are kept.
+func NewConfigSyncer(nacosClient *client.NacosClient, mappings []ConfigMapping, logger *slog.Logger) *ConfigSyncer {
+	if logger == nil {
+		logger = logging.Stderr()
+	}
+	return &ConfigSyncer{
+		client:   nacosClient,
+		mappings: mappings,
+		onDelete: OnDeleteKeep,
+		log:      logger,
+		hooks:    newHookRunner(Hooks{}, logger),
+	}
+}
+
+// SetOnDelete sets what happens to the file of a deleted config:
+// OnDeleteKeep or OnDeleteDelete
+func (s *ConfigSyncer) SetOnDelete(mode string) error {
+	if mode != OnDeleteKeep && mode != OnDeleteDelete {
+		return fmt.Errorf("invalid on-delete mode %q: expected %s or %s", mode, OnDeleteDelete, OnDeleteKeep)
+	}
+	s.onDelete = mode
+	return nil
+}
+
+// SetPollTimeout sets how long each poll for changes may take; zero keeps
+// listener.DefaultPollTimeout
+func (s *ConfigSyncer) SetPollTimeout(timeout time.Duration) {
+	s.pollTimeout = timeout
+}
+
+// SetPollInterval sets how often each config is polled; zero keeps
+// listener.PollInterval
+func (s *ConfigSyncer) SetPollInterval(interval time.Duration) {
+	s.pollInterval = interval
+}
+
+// SetBatchSize sets how many configs one poller watches; zero keeps
+// listener.DefaultBatchSize
+func (s *ConfigSyncer) SetBatchSize(size int) {
+	s.batchSize = size
+}
+
+// SetReloadTimeout sets how long a reload command may run; zero keeps DefaultHookTimeout
+func (s *ConfigSyncer) SetReloadTimeout(timeout time.Duration) {
+	s.hooks = newHookRunner(Hooks{Timeout: timeout}, s.log)
+}
+
+// Run writes each config to its file once and then rewrites the file
+// whenever the config changes in Nacos. It blocks until ctx is cancelled,
+// and returns once running reload commands have finished.
+func (s *ConfigSyncer) Run(ctx context.Context) error {
+	if len(s.mappings) == 0 {
+		return fmt.Errorf("no configs to sync")
+	}
+	defer s.hooks.wait()
+
+	var items []listener.ConfigItem
+	seen := make(map[string]bool)
+	for _, m := range s.mappings {
+		key := m.DataID + "\x00" + m.Group
+		if !seen[key] {
+			seen[key] = true
+			items = append(items, listener.ConfigItem{DataID: m.DataID, Group: m.Group, Tenant: s.client.Namespace})
+		}
+	}
+
+	l := listener.NewConfigListener(s.client)
+	l.SetLogger(s.log)
+	l.SetPollTimeout(s.pollTimeout)
+	l.SetPollInterval(s.pollInterval)
+	l.SetBatchSize(s.batchSize)
+	// Prime before the first download, so a change in between is picked up
+	// by the first poll instead of being missed
+	l.Prime(items)
+
+	s.log.Info(fmt.Sprintf("Syncing %d config(s) to local files", len(s.mappings)), "configs", len(s.mappings))
+	for _, item := range items {
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err := s.update(item.DataID, item.Group, true); err != nil {
+			s.configLog(item.DataID, item.Group).Error(fmt.Sprintf("Failed to sync config %s (%s): %v", item.DataID, item.Group, err), "event", EventError, "error", err)
+		}
+	}
+
+	s.log.Info(fmt.Sprintf("Watching %d config(s) for changes by polling each every %s", len(items), pollInterval(s.pollInterval)), "configs", len(items), "mode", "poll", "interval", pollInterval(s.pollInterval))
+	err := l.StartListening(items, s.handleChange, ctx.Done())
+	s.log.Info("Sync stopped")
+	return err
+}
+
+// handleChange rewrites the files of a config that changed or was deleted
+func (s *ConfigSyncer) handleChange(dataID, group, tenant, change string) error {
+	s.configLog(dataID, group).Info(fmt.Sprintf("Change detected for config %s (%s)", dataID, group), "change", change, "event", EventChanged)
+	return s.update(dataID, group, false)
+}
+
+// update fetches a config and writes it to every file it is mapped to. A
+// config missing at startup is only warned about, since its file may be
+// mapped by mistake; one deleted while syncing is handled per onDelete.
+func (s *ConfigSyncer) update(dataID, group string, initial bool) error {
+	config, err := s.client.GetConfigDetail(dataID, group)
+	if errors.Is(err, client.ErrConfigNotFound) {
+		if initial {
+			s.configLog(dataID, group).Warn(fmt.Sprintf("Config %s (%s) does not exist; its file is written once it is created", dataID, group), "event", EventError)
+			return nil
+		}
+		return s.remove(dataID, group)
+	}
+	if err != nil {
+		return err
+	}
+
+	var failed error
+	for _, m := range s.mappingsOf(dataID, group) {
+		start := time.Now()
+		log := s.configLog(dataID, group).With("path", m.Path)
+		changed, err := writeFileAtomic(m.Path, []byte(config.Content))
+		if err != nil {
+			failed = fmt.Errorf("write %s: %w", m.Path, err)
+			continue
+		}
+		if !changed {
+			log.Info(fmt.Sprintf("%s is up to date", m.Path), "event", EventUpToDate)
+			continue
+		}
+		log.Info(fmt.Sprintf("Config %s (%s) written to %s", dataID, group, m.Path), "event", EventSynced, "duration", time.Since(start))
+		s.reload(m, HookUpdated)
+	}
+	return failed
+}
+
+// remove removes or keeps the files of a deleted config, per onDelete
+func (s *ConfigSyncer) remove(dataID, group string) error {
+	for _, m := range s.mappingsOf(dataID, group) {
+		log := s.configLog(dataID, group).With("path", m.Path, "event", EventDeleted)
+		if s.onDelete == OnDeleteKeep {
+			log.Warn(fmt.Sprintf("Config %s (%s) was deleted in Nacos; kept %s", dataID, group, m.Path))
+			continue
+		}
+		if err := os.Remove(m.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		log.Info(fmt.Sprintf("Config %s (%s) was deleted in Nacos; removed %s", dataID, group, m.Path))
+		s.reload(m, HookDeleted)
+	}
+	return nil
+}
+
+// reload queues the reload command of a mapping, if it has one
+func (s *ConfigSyncer) reload(m ConfigMapping, event string) {
+	if m.Reload == "" {
+		return
+	}
+	env := []string{"NACOS_DATA_ID=" + m.DataID, "NACOS_GROUP=" + m.Group, "NACOS_CONFIG_PATH=" + m.Path, "NACOS_EVENT=" + event}
+	s.hooks.queue(m.Path, hookRun{
+		command: m.Reload,
+		env:     env,
+		subject: "config " + m.DataID,
+		attrs:   []any{"dataId", m.DataID, "group", m.Group},
+	})
+}
+
+// mappingsOf returns the mappings of a config; one config may be mirrored to several files
+func (s *ConfigSyncer) mappingsOf(dataID, group string) []ConfigMapping {
+	var mappings []ConfigMapping
+	for _, m := range s.mappings {
+		if m.DataID == dataID && m.Group == group {
+			mappings = append(mappings, m)
+		}
+	}
+	return mappings
+}
+
+// configLog returns the logger with the config attributes set
+func (s *ConfigSyncer) configLog(dataID, group string) *slog.Logger {
+	return s.log.With("dataId", dataID, "group", group)
+}
+
+// writeFileAtomic replaces path with data by writing a temporary file in the
+// same directory and renaming it over path, so readers never see a partial
+// file. It reports false without writing when path already holds data. An
+// existing file keeps its permissions.
+func writeFileAtomic(path string, data []byte) (bool, error) {
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		if current, err := os.ReadFile(path); err == nil && bytes.Equal(current, data) {
+			return false, nil
+		}
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return false, err
+	}
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return false, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return false, err
+	}
+	if err := tmp.Close(); err != nil {
+		return false, err
+	}
+	if err := os.Chmod(tmp.Name(), mode); err != nil {
+		return false, err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return false, err
+	}
+	return true, nil
+}
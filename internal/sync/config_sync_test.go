@@ -0,0 +1,201 @@
+package sync
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	gosync "sync"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+// newConfigServer serves one config whose content set can change; setting ""
+// deletes it
+func newConfigServer(t *testing.T) (*client.NacosClient, func(content string)) {
+	var mu gosync.Mutex
+	content := "a: 1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if content == "" {
+			http.Error(w, `{"code":20004,"message":"config data not exist"}`, http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Config-Type", "yaml")
+		w.Write([]byte(content))
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, func(s string) {
+		mu.Lock()
+		content = s
+		mu.Unlock()
+	}
+}
+
+func TestParseConfigMapping(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    ConfigMapping
+		wantErr bool
+	}{
+		{"app.yaml:DEFAULT_GROUP:/etc/app/app.yaml", ConfigMapping{DataID: "app.yaml", Group: "DEFAULT_GROUP", Path: "/etc/app/app.yaml"}, false},
+		{"app.yaml::/etc/app/app.yaml", ConfigMapping{DataID: "app.yaml", Path: "/etc/app/app.yaml"}, false},
+		{`app.yaml:prod:C:\app\app.yaml`, ConfigMapping{DataID: "app.yaml", Group: "prod", Path: `C:\app\app.yaml`}, false},
+		{"app.yaml:/etc/app/app.yaml", ConfigMapping{}, true},
+		{":DEFAULT_GROUP:/etc/app/app.yaml", ConfigMapping{}, true},
+		{"app.yaml:DEFAULT_GROUP:", ConfigMapping{}, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseConfigMapping(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseConfigMapping(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseConfigMapping(%q) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestLoadConfigMappings(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mappings.yaml")
+	// An absolute path of this OS: /etc/... has no drive on Windows
+	etc := filepath.ToSlash(filepath.Join(t.TempDir(), "db.properties"))
+	data := `configs:
+  - dataId: app.yaml
+    group: prod
+    path: app/app.yaml
+    reload: systemctl reload app
+  - dataId: db.properties
+    path: ` + etc + `
+`
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadConfigMappings(path)
+	if err != nil {
+		t.Fatalf("LoadConfigMappings() error = %v", err)
+	}
+	want := []ConfigMapping{
+		{DataID: "app.yaml", Group: "prod", Path: filepath.Join(dir, "app", "app.yaml"), Reload: "systemctl reload app"},
+		{DataID: "db.properties", Path: etc},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("LoadConfigMappings() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("mapping %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("configs:\n  - dataId: app.yaml\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadConfigMappings(path); err == nil {
+		t.Error("LoadConfigMappings() accepted a mapping without a path")
+	}
+}
+
+func TestConfigSyncerWritesAndDeletes(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("reload commands use sh")
+	}
+	tests := []struct {
+		onDelete string
+		wantFile bool
+	}{
+		{OnDeleteKeep, true},
+		{OnDeleteDelete, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.onDelete, func(t *testing.T) {
+			c, setRemote := newConfigServer(t)
+			dir := t.TempDir()
+			path := filepath.Join(dir, "etc", "app.yaml")
+			reloads := filepath.Join(dir, "reloads")
+			mapping := ConfigMapping{DataID: "app.yaml", Group: "DEFAULT_GROUP", Path: path, Reload: `echo "$NACOS_EVENT" >> ` + reloads}
+			syncer := NewConfigSyncer(c, []ConfigMapping{mapping}, logging.Discard())
+			if err := syncer.SetOnDelete(tt.onDelete); err != nil {
+				t.Fatal(err)
+			}
+
+			if err := syncer.update("app.yaml", "DEFAULT_GROUP", true); err != nil {
+				t.Fatalf("update() error = %v", err)
+			}
+			// Unchanged content is not rewritten and does not reload
+			if err := syncer.update("app.yaml", "DEFAULT_GROUP", false); err != nil {
+				t.Fatalf("update() error = %v", err)
+			}
+			setRemote("a: 2")
+			if err := syncer.update("app.yaml", "DEFAULT_GROUP", false); err != nil {
+				t.Fatalf("update() error = %v", err)
+			}
+			if data, _ := os.ReadFile(path); string(data) != "a: 2" {
+				t.Fatalf("file = %q, want %q", data, "a: 2")
+			}
+
+			setRemote("")
+			if err := syncer.update("app.yaml", "DEFAULT_GROUP", false); err != nil {
+				t.Fatalf("update() error = %v", err)
+			}
+			if _, err := os.Stat(path); (err == nil) != tt.wantFile {
+				t.Errorf("file exists = %v, want %v", err == nil, tt.wantFile)
+			}
+
+			syncer.hooks.wait()
+			want := "updated\nupdated\n"
+			if !tt.wantFile {
+				want += "deleted\n"
+			}
+			if data, _ := os.ReadFile(reloads); string(data) != want {
+				t.Errorf("reloads = %q, want %q", data, want)
+			}
+			if matches, _ := filepath.Glob(filepath.Join(dir, "etc", ".app.yaml.tmp-*")); len(matches) > 0 {
+				t.Errorf("temporary files left behind: %v", matches)
+			}
+		})
+	}
+}
+
+func TestConfigSyncerMissingAtStartKeepsFile(t *testing.T) {
+	c, setRemote := newConfigServer(t)
+	setRemote("")
+	path := filepath.Join(t.TempDir(), "app.yaml")
+	if err := os.WriteFile(path, []byte("local"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	syncer := NewConfigSyncer(c, []ConfigMapping{{DataID: "app.yaml", Path: path}}, logging.Discard())
+	syncer.SetOnDelete(OnDeleteDelete)
+
+	if err := syncer.update("app.yaml", "", true); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "local" {
+		t.Errorf("file = %q, want it untouched", data)
+	}
+
+	// A rewrite keeps the file's permissions
+	setRemote("a: 1")
+	if err := syncer.update("app.yaml", "", false); err != nil {
+		t.Fatalf("update() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if runtime.GOOS != "windows" && info.Mode().Perm() != 0600 {
+		t.Errorf("mode = %v, want 0600", info.Mode().Perm())
+	}
+}
@@ -0,0 +1,128 @@
+package sync
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+)
+
+// DefaultDebounce is how long the changes of a skill are collected before
+// it is synced once for all of them
+const DefaultDebounce = 2 * time.Second
+
+// pendingChange is what changed in a skill while its debounce window is open
+type pendingChange struct {
+	timer  *time.Timer
+	tenant string
+	dataID string    // skill.json once it changed, else the config that changed last
+	change string    // what happened to dataID, see listener.ChangeHandler
+	seen   time.Time // when the last change was reported
+	count  int       // how many changes are coalesced
+}
+
+// SetDebounce sets how long the changes of a skill are collected before it
+// is synced: a publish changes skill.json and each resource, and saving a
+// skill several times in a row changes them again, but the skill is
+// downloaded and its hooks run once, for the final state. Zero syncs on
+// every change.
+func (s *SkillSyncer) SetDebounce(window time.Duration) {
+	s.debounce = window
+}
+
+// enqueue records a change of a skill and (re)starts its debounce window of
+// delay, after which flush syncs the skill
+func (s *SkillSyncer) enqueue(name, tenant, dataID, change string, delay time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.stopped {
+		return
+	}
+	p, ok := s.pending[name]
+	if !ok {
+		p = &pendingChange{tenant: tenant}
+		s.pending[name] = p
+		p.timer = time.AfterFunc(delay, func() { s.flush(name) })
+	} else {
+		p.timer.Reset(delay)
+	}
+	if p.dataID != skillConfigDataID {
+		p.dataID, p.change = dataID, change
+	} else if dataID == skillConfigDataID {
+		p.change = change
+	}
+	p.seen = time.Now()
+	p.count++
+}
+
+// flush syncs a skill once its debounce window has passed. A failed sync is
+// tried again after a poll interval, as the listener retries a failed change.
+func (s *SkillSyncer) flush(name string) {
+	s.mu.Lock()
+	p := s.pending[name]
+	delete(s.pending, name)
+	if p == nil || s.stopped {
+		s.mu.Unlock()
+		return
+	}
+	s.flushing.Add(1)
+	s.mu.Unlock()
+	defer s.flushing.Done()
+
+	if err := s.applyChange(name, p.tenant, p.dataID, p.change, p.count, p.seen); err != nil {
+		s.enqueue(name, p.tenant, p.dataID, p.change, pollInterval(s.pollInterval))
+	}
+}
+
+// stopDebounce drops the changes still waiting out their window and waits
+// for the syncs already started, so that Run returns with nothing running
+func (s *SkillSyncer) stopDebounce() {
+	s.mu.Lock()
+	s.stopped = true
+	for name, p := range s.pending {
+		p.timer.Stop()
+		delete(s.pending, name)
+	}
+	s.mu.Unlock()
+	s.flushing.Wait()
+}
+
+// applyChange syncs a skill for count changes, the last reported at seen.
+// When skill.json changed the skill's resource list is refreshed as well.
+// A sync started after seen already picked the changes up, so the skill is
+// not downloaded again.
+func (s *SkillSyncer) applyChange(name, tenant, dataID, change string, count int, seen time.Time) error {
+	if s.lastSynced(name).After(seen) {
+		s.skillLog(name).Debug(fmt.Sprintf("Skill %s was synced since it changed", name), "dataId", dataID)
+	} else {
+		msg := fmt.Sprintf("Change detected for skill %s", name)
+		switch {
+		case change != listener.ConfigModified:
+			msg += fmt.Sprintf(" (%s %s)", dataID, change)
+		case dataID != skillConfigDataID:
+			msg += fmt.Sprintf(" (%s)", dataID)
+		}
+		if count > 1 {
+			msg += fmt.Sprintf(", %d changes coalesced", count)
+		}
+		s.skillLog(name).Info(msg, "dataId", dataID, "change", change, "changes", count, "event", EventChanged)
+		_, err := s.download(name, dataID, nil)
+		if change == listener.ConfigDeleted && errors.Is(err, skill.ErrSkillNotFound) {
+			// Never synced, so there is nothing to remove
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if dataID == skillConfigDataID && s.listener != nil {
+		items := s.watchItems(name)
+		s.listener.Prime(items)
+		s.status.md5(name, items[0].MD5)
+		s.listener.WatchGroup(tenant, skillGroupPrefix+name, items)
+	}
+	return nil
+}
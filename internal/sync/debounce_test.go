@@ -0,0 +1,100 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+func TestChangesAreDebounced(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetDebounce(50 * time.Millisecond)
+	var mu gosync.Mutex
+	var events []string
+	syncer.SetEventHandler(func(skill, event string) {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+	})
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+
+	// Saved three times in a row, changing two resources each time (the test
+	// server's skill.json never changes)
+	for _, content := range []string{"v2", "v3", "v4"} {
+		setRemote(content)
+		syncer.handleChange("resource_a.md", "skill_demo", "", listener.ConfigModified)
+		syncer.handleChange("resource_b.md", "skill_demo", "", listener.ConfigModified)
+		time.Sleep(10 * time.Millisecond)
+	}
+	time.Sleep(200 * time.Millisecond)
+	syncer.stopDebounce()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if strings.Join(events, ",") != EventSynced+","+EventSynced {
+		t.Errorf("events = %v, want the first sync and one for the three saves", events)
+	}
+	if got := readSkillMD(t, filepath.Join(out, "demo")); got != "v4" {
+		t.Errorf("SKILL.md = %q, want the final state v4", got)
+	}
+}
+
+func TestChangeWithUnchangedSkillJSON(t *testing.T) {
+	c, _ := newSkillServer(t)
+	out := t.TempDir()
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetDebounce(0)
+	var events []string
+	syncer.SetEventHandler(func(skill, event string) { events = append(events, event) })
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	state, err := loadState(out, "demo")
+	if err != nil || state == nil {
+		t.Fatalf("loadState() = %v, %v", state, err)
+	}
+
+	// A change reported for a skill.json whose MD5 matches the last sync is
+	// checked, not downloaded, and leaves the sync state as it was
+	if err := syncer.handleChange(skillConfigDataID, "skill_demo", "", listener.ConfigModified); err != nil {
+		t.Fatalf("handleChange() error = %v", err)
+	}
+	if strings.Join(events, ",") != EventSynced+","+EventUpToDate {
+		t.Errorf("events = %v, want the first sync and up-to-date", events)
+	}
+	after, err := loadState(out, "demo")
+	if err != nil || after == nil || *after != *state {
+		t.Errorf("sync state = %+v, want it unchanged from %+v", after, state)
+	}
+	if _, err := os.Stat(filepath.Join(out, "demo", "SKILL.md")); err != nil {
+		t.Errorf("SKILL.md: %v", err)
+	}
+}
+
+func TestStopDebounceDropsPendingChanges(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetDebounce(20 * time.Millisecond)
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+
+	setRemote("v2")
+	syncer.handleChange(skillConfigDataID, "skill_demo", "", listener.ConfigModified)
+	syncer.stopDebounce()
+	time.Sleep(50 * time.Millisecond)
+	if got := readSkillMD(t, filepath.Join(out, "demo")); got != "v1" {
+		t.Errorf("SKILL.md = %q, want a change pending at shutdown dropped", got)
+	}
+}
@@ -0,0 +1,92 @@
+package sync
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DirMap maps skill names to the directory each is synced into instead of
+// the output directory, e.g. to put some skills under an agent's plugin
+// directory and others under a shared tools directory. A skill lands in
+// <dir>/<skill> as it would in the output directory, with its sync state in
+// <dir>/.sync-state.
+type DirMap map[string]string
+
+// LoadDirMap builds the mapping from an optional YAML file of skill: /dir
+// entries and a list of --map skill=/dir values, which take precedence over
+// the file
+func LoadDirMap(mapFile string, values []string) (DirMap, error) {
+	dirs := DirMap{}
+	if mapFile != "" {
+		data, err := os.ReadFile(mapFile)
+		if err != nil {
+			return nil, fmt.Errorf("read map file %s: %w", mapFile, err)
+		}
+		if err := yaml.Unmarshal(data, &dirs); err != nil {
+			return nil, fmt.Errorf("parse map file %s: %w", mapFile, err)
+		}
+		if dirs == nil {
+			dirs = DirMap{}
+		}
+	}
+
+	flags := make(map[string]bool)
+	for _, v := range values {
+		name, dir, ok := strings.Cut(v, "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" || dir == "" {
+			return nil, fmt.Errorf("invalid --map %q: expected skill=/dir", v)
+		}
+		if flags[name] {
+			return nil, fmt.Errorf("skill %s is mapped more than once", name)
+		}
+		flags[name] = true
+		dirs[name] = dir
+	}
+	return dirs, nil
+}
+
+// Validate checks that every mapped directory is absolute and writable,
+// creating those that do not exist yet, so a bad mapping fails at startup
+// rather than on the first change of its skill
+func (m DirMap) Validate() error {
+	for _, name := range m.names() {
+		dir := m[name]
+		if !filepath.IsAbs(dir) {
+			return fmt.Errorf("skill %s is mapped to %s: the directory must be an absolute path", name, dir)
+		}
+		if err := writable(dir); err != nil {
+			return fmt.Errorf("skill %s is mapped to %s: %w", name, dir, err)
+		}
+		m[name] = filepath.Clean(dir)
+	}
+	return nil
+}
+
+// names returns the mapped skills in order
+func (m DirMap) names() []string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// writable creates dir if needed and checks that files can be created in it
+func writable(dir string) error {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(dir, ".nacos-cli-write-test-*")
+	if err != nil {
+		return fmt.Errorf("not writable: %w", err)
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
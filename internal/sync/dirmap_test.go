@@ -0,0 +1,64 @@
+package sync
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDirMap(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "skill-dirs.yaml")
+	if err := os.WriteFile(file, []byte("a: /opt/plugins\nb: /srv/tools\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dirs, err := LoadDirMap(file, []string{"b=/srv/other", "c=/srv/c"})
+	if err != nil {
+		t.Fatalf("LoadDirMap() error = %v", err)
+	}
+	want := DirMap{"a": "/opt/plugins", "b": "/srv/other", "c": "/srv/c"}
+	if len(dirs) != len(want) {
+		t.Fatalf("LoadDirMap() = %v, want %v", dirs, want)
+	}
+	for name, dir := range want {
+		if dirs[name] != dir {
+			t.Errorf("LoadDirMap()[%s] = %q, want %q", name, dirs[name], dir)
+		}
+	}
+
+	for _, bad := range [][]string{{"a"}, {"=/x"}, {"a="}, {"a=/x", "a=/y"}} {
+		if _, err := LoadDirMap("", bad); err == nil {
+			t.Errorf("LoadDirMap(%q) succeeded", bad)
+		}
+	}
+	if _, err := LoadDirMap(filepath.Join(t.TempDir(), "missing.yaml"), nil); err == nil {
+		t.Error("LoadDirMap() with a missing file succeeded")
+	}
+}
+
+func TestDirMapValidate(t *testing.T) {
+	root := t.TempDir()
+	dirs := DirMap{"a": filepath.Join(root, "new", "plugins") + "/"}
+	if err := dirs.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+	if info, err := os.Stat(dirs["a"]); err != nil || !info.IsDir() {
+		t.Errorf("Validate() did not create %s: %v", dirs["a"], err)
+	}
+	if dirs["a"] != filepath.Join(root, "new", "plugins") {
+		t.Errorf("Validate() kept %q, want it cleaned", dirs["a"])
+	}
+
+	if err := (DirMap{"a": "relative/dir"}).Validate(); err == nil || !strings.Contains(err.Error(), "absolute") {
+		t.Errorf("Validate() of a relative directory error = %v", err)
+	}
+
+	// A directory cannot be created under a file
+	file := filepath.Join(root, "file")
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := (DirMap{"a": filepath.Join(file, "dir")}).Validate(); err == nil || !strings.Contains(err.Error(), "skill a") {
+		t.Errorf("Validate() of an unusable directory error = %v", err)
+	}
+}
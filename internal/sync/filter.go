@@ -0,0 +1,61 @@
+package sync
+
+import (
+	"fmt"
+	"path"
+)
+
+// SkillFilter picks the skills skill-sync --all syncs by name: those matching
+// an Include pattern, or every skill without one, unless they match an
+// Exclude pattern. Patterns follow path.Match, so * matches any run of
+// characters wherever it appears, e.g. team-*-beta.
+type SkillFilter struct {
+	Include []string
+	Exclude []string
+}
+
+// NewSkillFilter checks the patterns of --include and --exclude
+func NewSkillFilter(include, exclude []string) (SkillFilter, error) {
+	for _, pattern := range append(append([]string(nil), include...), exclude...) {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return SkillFilter{}, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	return SkillFilter{Include: include, Exclude: exclude}, nil
+}
+
+// Empty reports whether the filter keeps every skill
+func (f SkillFilter) Empty() bool {
+	return len(f.Include) == 0 && len(f.Exclude) == 0
+}
+
+// Match reports whether the skill called name is synced
+func (f SkillFilter) Match(name string) bool {
+	if matchAny(f.Exclude, name) {
+		return false
+	}
+	return len(f.Include) == 0 || matchAny(f.Include, name)
+}
+
+// Apply splits names into the skills that are synced and those filtered out
+func (f SkillFilter) Apply(names []string) (kept, filtered []string) {
+	for _, name := range names {
+		if f.Match(name) {
+			kept = append(kept, name)
+		} else {
+			filtered = append(filtered, name)
+		}
+	}
+	return kept, filtered
+}
+
+// matchAny reports whether name matches one of patterns; NewSkillFilter has
+// checked them, so a malformed pattern cannot get here
+func matchAny(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,62 @@
+package sync
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSkillFilterMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		include []string
+		exclude []string
+		skill   string
+		want    bool
+	}{
+		{"no patterns", nil, nil, "anything", true},
+		{"include prefix", []string{"team-*"}, nil, "team-search", true},
+		{"include misses", []string{"team-*"}, nil, "other", false},
+		{"star in the middle", []string{"team-*-beta"}, nil, "team-search-beta", true},
+		{"star matches dashes", []string{"team-*-beta"}, nil, "team-a-b-beta", true},
+		{"star matches nothing", []string{"team-*-beta"}, nil, "team--beta", true},
+		{"star needs both dashes", []string{"team-*-beta"}, nil, "team-beta", false},
+		{"suffix after the pattern", []string{"team-*-beta"}, nil, "team-search-beta2", false},
+		{"star on both sides", []string{"*beta*"}, nil, "search-beta-v2", true},
+		{"question mark", []string{"skill-?"}, nil, "skill-a", true},
+		{"question mark is one character", []string{"skill-?"}, nil, "skill-ab", false},
+		{"character class", []string{"[ab]-*"}, nil, "b-tools", true},
+		{"escaped star", []string{`big\*`}, nil, "big*", true},
+		{"escaped star is literal", []string{`big\*`}, nil, "bigger", false},
+		{"exclude", nil, []string{"*-experimental"}, "nlp-experimental", false},
+		{"exclude wins over include", []string{"team-*"}, []string{"*-beta"}, "team-x-beta", false},
+		{"one of several includes", []string{"a-*", "b-*"}, nil, "b-1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewSkillFilter(tt.include, tt.exclude)
+			if err != nil {
+				t.Fatalf("NewSkillFilter() error = %v", err)
+			}
+			if got := f.Match(tt.skill); got != tt.want {
+				t.Errorf("Match(%q) = %v, want %v", tt.skill, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSkillFilterApply(t *testing.T) {
+	f, err := NewSkillFilter(nil, []string{"huge-*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kept, filtered := f.Apply([]string{"a", "huge-model", "b", "huge-data"})
+	if !reflect.DeepEqual(kept, []string{"a", "b"}) || !reflect.DeepEqual(filtered, []string{"huge-model", "huge-data"}) {
+		t.Errorf("Apply() = %q, %q", kept, filtered)
+	}
+}
+
+func TestNewSkillFilterRejectsBadPattern(t *testing.T) {
+	if _, err := NewSkillFilter([]string{"ok-*"}, []string{"[a-"}); err == nil {
+		t.Error("NewSkillFilter() accepted the unterminated class [a-")
+	}
+}
@@ -0,0 +1,178 @@
+package sync
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/skill"
+)
+
+const (
+	// DefaultHookTimeout is how long a hook may run before it is killed
+	DefaultHookTimeout = time.Minute
+	// hookOutputLimit bounds the hook output quoted in a failure message
+	hookOutputLimit = 200
+)
+
+// Values of NACOS_EVENT passed to hooks
+const (
+	HookUpdated = "updated"
+	HookDeleted = "deleted"
+	HookError   = "error"
+)
+
+// Hooks are shell commands run when a skill changes locally or fails to sync.
+// They get NACOS_SKILL_NAME, NACOS_EVENT and NACOS_SKILL_PATH in their
+// environment, NACOS_ERROR for OnError, and NACOS_CHANGES (a one-line
+// summary) and NACOS_CHANGES_JSON (the changed files) for an updated skill
+// that was installed before.
+type Hooks struct {
+	OnChange string        // after a skill is updated or deleted
+	OnError  string        // after a skill fails to sync
+	Timeout  time.Duration // zero means DefaultHookTimeout
+}
+
+// hookRun is one queued execution of a hook
+type hookRun struct {
+	command string
+	env     []string
+	subject string // what the hook is for in log messages, e.g. "skill demo"
+	attrs   []any  // log attributes identifying the subject
+}
+
+// hookRunner runs hooks in the background so a slow hook does not hold up
+// syncing. The hooks of one skill (or mirrored config) run one at a time, in order.
+type hookRunner struct {
+	hooks Hooks
+	log   *slog.Logger
+
+	mu      gosync.Mutex
+	pending map[string][]hookRun // queued runs by skill or config
+	wg      gosync.WaitGroup
+}
+
+func newHookRunner(hooks Hooks, log *slog.Logger) *hookRunner {
+	if hooks.Timeout <= 0 {
+		hooks.Timeout = DefaultHookTimeout
+	}
+	return &hookRunner{hooks: hooks, log: log, pending: make(map[string][]hookRun)}
+}
+
+// run queues the hook for event, if one is set. err is the sync error for
+// HookError; changes are the files an update changed, nil for a first install.
+func (h *hookRunner) run(name, event, path string, err error, changes []skill.FileChange) {
+	command := h.hooks.OnChange
+	if event == HookError {
+		command = h.hooks.OnError
+	}
+	if command == "" {
+		return
+	}
+	env := []string{"NACOS_SKILL_NAME=" + name, "NACOS_EVENT=" + event, "NACOS_SKILL_PATH=" + path}
+	if err != nil {
+		env = append(env, "NACOS_ERROR="+err.Error())
+	}
+	if changes != nil {
+		env = append(env, "NACOS_CHANGES="+skill.SummarizeChanges(changes), "NACOS_CHANGES_JSON="+changesJSON(changes))
+	}
+	h.queue(name, hookRun{command: command, env: env, subject: "skill " + name, attrs: []any{"skill", name}})
+}
+
+// changesJSON lists changed files for a hook, leaving out the diffs, which
+// could outgrow the environment
+func changesJSON(changes []skill.FileChange) string {
+	files := make([]skill.FileChange, len(changes))
+	for i, c := range changes {
+		files[i] = skill.FileChange{Path: c.Path, Status: c.Status}
+	}
+	data, _ := json.Marshal(files)
+	return string(data)
+}
+
+// queue adds a run behind the earlier runs with the same key
+func (h *hookRunner) queue(key string, run hookRun) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	queue, busy := h.pending[key]
+	h.pending[key] = append(queue, run)
+	if !busy {
+		h.wg.Add(1)
+		go h.drain(key)
+	}
+}
+
+// drain runs the queued hooks of a key until none are left
+func (h *hookRunner) drain(key string) {
+	defer h.wg.Done()
+	for {
+		h.mu.Lock()
+		queue := h.pending[key]
+		if len(queue) == 0 {
+			delete(h.pending, key)
+			h.mu.Unlock()
+			return
+		}
+		next := queue[0]
+		h.pending[key] = queue[1:]
+		h.mu.Unlock()
+		h.exec(next)
+	}
+}
+
+// exec runs one hook and logs how it went. Failures are only logged.
+func (h *hookRunner) exec(run hookRun) {
+	ctx, cancel := context.WithTimeout(context.Background(), h.hooks.Timeout)
+	defer cancel()
+
+	cmd := shellCommand(ctx, run.command)
+	cmd.Env = append(os.Environ(), run.env...)
+	// Don't wait forever for output from processes the hook left behind
+	cmd.WaitDelay = time.Second
+	start := time.Now()
+	out, err := cmd.CombinedOutput()
+	log := h.log.With(run.attrs...).With("hook", run.command, "duration", time.Since(start))
+
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		log.Warn(fmt.Sprintf("Hook for %s killed after %s", run.subject, h.hooks.Timeout), "event", EventError, "output", string(out))
+	case err != nil:
+		msg := fmt.Sprintf("Hook for %s failed: %v", run.subject, err)
+		if output := hookOutput(out); output != "" {
+			msg += ": " + output
+		}
+		log.Warn(msg, "event", EventError, "error", err, "output", string(out))
+	default:
+		log.Info(fmt.Sprintf("Ran hook for %s", run.subject), "output", string(out))
+	}
+}
+
+// wait blocks until every queued hook has run
+func (h *hookRunner) wait() {
+	h.wg.Wait()
+}
+
+// shellCommand runs command through the shell, so hooks can use pipes and variables
+func shellCommand(ctx context.Context, command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.CommandContext(ctx, "cmd", "/C", command)
+	}
+	return exec.CommandContext(ctx, "sh", "-c", command)
+}
+
+// hookOutput shortens hook output to quote it on one line
+func hookOutput(out []byte) string {
+	output := strings.Join(strings.Fields(string(out)), " ")
+	if len(output) > hookOutputLimit {
+		output = output[:hookOutputLimit] + "..."
+	}
+	return output
+}
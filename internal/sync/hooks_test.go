@@ -0,0 +1,94 @@
+package sync
+
+import (
+	"errors"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+)
+
+func TestHookRunner(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are sh scripts")
+	}
+	out := filepath.Join(t.TempDir(), "hooks.log")
+	// Each run appends its environment, then sleeps so overlapping runs would interleave
+	h := newHookRunner(Hooks{
+		OnChange: `echo "start $NACOS_SKILL_NAME $NACOS_EVENT $NACOS_SKILL_PATH" >> ` + out + `; sleep 0.1; echo "end $NACOS_SKILL_NAME" >> ` + out,
+		OnError:  `echo "error $NACOS_SKILL_NAME $NACOS_ERROR" >> ` + out,
+	}, logging.Discard())
+
+	h.run("demo", HookUpdated, "/skills/demo", nil, nil)
+	h.run("demo", HookDeleted, "/skills/demo", nil, nil)
+	h.run("demo", HookError, "/skills/demo", errors.New("boom"), nil)
+	h.wait()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"start demo updated /skills/demo",
+		"end demo",
+		"start demo deleted /skills/demo",
+		"end demo",
+		"error demo boom",
+	}
+	if got := strings.Split(strings.TrimSpace(string(data)), "\n"); strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Errorf("hook runs = %q, want %q", got, want)
+	}
+}
+
+func TestHookTimeout(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are sh scripts")
+	}
+	var mu gosync.Mutex
+	var logs []string
+	h := newHookRunner(Hooks{OnChange: "sleep 10", Timeout: 100 * time.Millisecond}, slog.New(logging.NewConsoleHandler(func(msg string) {
+		mu.Lock()
+		logs = append(logs, msg)
+		mu.Unlock()
+	})))
+
+	start := time.Now()
+	h.run("demo", HookUpdated, "/skills/demo", nil, nil)
+	h.wait()
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("hook ran for %s, want it killed after 100ms", elapsed)
+	}
+	if len(logs) != 1 || !strings.Contains(logs[0], "killed after 100ms") {
+		t.Errorf("logs = %q, want the hook reported as killed", logs)
+	}
+}
+
+func TestHookChanges(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("hooks in this test are sh scripts")
+	}
+	out := filepath.Join(t.TempDir(), "hooks.log")
+	h := newHookRunner(Hooks{OnChange: `echo "$NACOS_CHANGES|$NACOS_CHANGES_JSON" >> ` + out}, logging.Discard())
+	h.run("demo", HookUpdated, "/skills/demo", nil, []skill.FileChange{
+		{Path: "SKILL.md", Status: skill.FileModified, Diff: "--- a/SKILL.md\n+++ b/SKILL.md\n"},
+		{Path: "run.sh", Status: skill.FileAdded},
+	})
+	h.run("demo", HookUpdated, "/skills/demo", nil, nil)
+	h.wait()
+
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `1 added (run.sh), 1 modified (SKILL.md)|[{"path":"SKILL.md","status":"modified"},{"path":"run.sh","status":"added"}]` + "\n|\n"
+	if string(data) != want {
+		t.Errorf("hook output = %q, want %q", data, want)
+	}
+}
@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"math/rand"
+	"slices"
+	gosync "sync"
+	"time"
+)
+
+// DefaultInitConcurrency is how many skills the first sync downloads at once
+const DefaultInitConcurrency = 5
+
+// progress numbers the skills of the first sync as they finish
+type progress struct {
+	mu    gosync.Mutex
+	done  int
+	total int
+}
+
+// next counts one more finished skill and returns e.g. "[142/300]"
+func (p *progress) next() string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	return fmt.Sprintf("[%d/%d]", p.done, p.total)
+}
+
+// initialSync downloads every skill once, initWorkers at a time. A failed
+// skill does not stop the others; failures are listed again after the
+// summary so they do not scroll away. It returns early when ctx is cancelled.
+func (s *SkillSyncer) initialSync(ctx context.Context, skillNames []string) {
+	names := make(chan string)
+	go func() {
+		defer close(names)
+		for _, name := range s.smallestFirst(skillNames) {
+			select {
+			case names <- name:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	p := &progress{total: len(skillNames)}
+	var mu gosync.Mutex
+	counts := make(map[string]int)
+	failures := make(map[string]error)
+	var wg gosync.WaitGroup
+	for i := 0; i < s.initWorkers && i < len(skillNames); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range names {
+				event, err := s.download(name, "", p)
+				mu.Lock()
+				counts[event]++
+				if err != nil {
+					failures[name] = err
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() != nil {
+		return
+	}
+	s.logSummary(counts, skillNames, failures)
+}
+
+// smallestFirst orders skills by their size when last synced, so the first
+// sync makes the most skills usable early. Skills never synced go last, in
+// the order given.
+func (s *SkillSyncer) smallestFirst(skillNames []string) []string {
+	sizes := make(map[string]int64, len(skillNames))
+	for _, name := range skillNames {
+		if state, err := loadState(s.baseDir(name), name); err == nil && state != nil && state.Bytes > 0 {
+			sizes[name] = state.Bytes
+		}
+	}
+	ordered := slices.Clone(skillNames)
+	slices.SortStableFunc(ordered, func(a, b string) int {
+		sa, oka := sizes[a]
+		sb, okb := sizes[b]
+		switch {
+		case oka && okb:
+			return cmp.Compare(sa, sb)
+		case oka:
+			return -1
+		case okb:
+			return 1
+		}
+		return 0
+	})
+	return ordered
+}
+
+// startupJitter waits a random time up to the jitter set with
+// SetStartupJitter. It returns false if ctx was cancelled meanwhile.
+func (s *SkillSyncer) startupJitter(ctx context.Context) bool {
+	if s.jitter <= 0 {
+		return true
+	}
+	delay := time.Duration(rand.Int63n(int64(s.jitter)))
+	s.log.Info(fmt.Sprintf("Waiting %s before the first sync", delay.Round(time.Millisecond)), "jitter", delay.String())
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// logSummary logs how the first sync went, e.g. "Initial sync: 298 up to
+// date, 1 downloaded, 1 failed", followed by each failure in skill order
+func (s *SkillSyncer) logSummary(counts map[string]int, skillNames []string, failures map[string]error) {
+	msg := fmt.Sprintf("Initial sync: %d up to date, %d downloaded, %d failed", counts[EventUpToDate], counts[EventSynced], counts[EventError])
+	if n := counts[EventConflict]; n > 0 {
+		msg += fmt.Sprintf(", %d conflict(s)", n)
+	}
+	if n := counts[EventDeleted]; n > 0 {
+		msg += fmt.Sprintf(", %d deleted", n)
+	}
+	s.log.Info(msg, "upToDate", counts[EventUpToDate], "downloaded", counts[EventSynced], "failed", counts[EventError])
+	for _, name := range skillNames {
+		if err, ok := failures[name]; ok {
+			s.skillLog(name).Error(fmt.Sprintf("  %s: %v", name, err), "event", EventError, "error", err)
+		}
+	}
+}
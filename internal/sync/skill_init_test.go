@@ -0,0 +1,107 @@
+package sync
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+func TestInitialSyncRunsConcurrently(t *testing.T) {
+	var active, peak int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("name")
+		if strings.HasSuffix(r.URL.Path, "/cs/config") || name == "broken" {
+			http.NotFound(w, r)
+			return
+		}
+		n := atomic.AddInt32(&active, 1)
+		defer atomic.AddInt32(&active, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		zw := zip.NewWriter(w)
+		f, _ := zw.Create(name + "/SKILL.md")
+		f.Write([]byte(name))
+		zw.Close()
+	}))
+	t.Cleanup(server.Close)
+	c, err := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var names []string
+	for i := 0; i < 8; i++ {
+		names = append(names, fmt.Sprintf("skill-%d", i))
+	}
+	names = append(names, "broken")
+
+	out := t.TempDir()
+	var logs strings.Builder
+	syncer := NewSkillSyncer(c, out, logging.Printf(func(format string, args ...any) {
+		fmt.Fprintf(&logs, format+"\n", args...)
+	}))
+	syncer.SetInitConcurrency(3)
+	syncer.initialSync(context.Background(), names)
+
+	if peak != 3 {
+		t.Errorf("peak concurrent downloads = %d, want 3", peak)
+	}
+	for _, name := range names[:8] {
+		if _, err := os.Stat(filepath.Join(out, name, "SKILL.md")); err != nil {
+			t.Errorf("%s was not synced: %v", name, err)
+		}
+	}
+	if state, err := loadState(out, "skill-0"); err != nil || state == nil || state.Bytes != int64(len("skill-0")) {
+		t.Errorf("sync state of skill-0 = %+v, %v; want its size recorded", state, err)
+	}
+	for _, want := range []string{"[9/9]", "skill-0 ✓", "broken ✗", "Initial sync: 0 up to date, 8 downloaded, 1 failed", "  broken: "} {
+		if !strings.Contains(logs.String(), want) {
+			t.Errorf("log does not contain %q:\n%s", want, logs.String())
+		}
+	}
+}
+
+func TestInitialSyncSmallestFirst(t *testing.T) {
+	out := t.TempDir()
+	syncer := NewSkillSyncer(nil, out, logging.Discard())
+	for name, size := range map[string]int64{"large": 5 << 20, "small": 2048, "medium": 300 << 10} {
+		if err := saveState(out, name, syncState{Bytes: size}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	got := syncer.smallestFirst([]string{"new-b", "large", "new-a", "small", "medium"})
+	want := []string{"small", "medium", "large", "new-b", "new-a"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("smallestFirst() = %v, want %v", got, want)
+	}
+}
+
+func TestStartupJitter(t *testing.T) {
+	syncer := NewSkillSyncer(nil, t.TempDir(), logging.Discard())
+	if !syncer.startupJitter(context.Background()) {
+		t.Error("startupJitter() without a jitter returned false")
+	}
+
+	syncer.SetStartupJitter(time.Hour)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if syncer.startupJitter(ctx) {
+		t.Error("startupJitter() returned true after ctx was cancelled")
+	}
+}
@@ -0,0 +1,295 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+const (
+	// pushDebounce is how long a skill must be quiet after a change before it
+	// is pushed, so an editor saving several files causes one upload
+	pushDebounce = 500 * time.Millisecond
+	// pushRetryBase is the first wait after a failed push; it doubles up to
+	// listener.MaxBackoff
+	pushRetryBase = 2 * time.Second
+	// maxListedFiles limits the changed files printed for one push
+	maxListedFiles = 10
+)
+
+// SkillPusher uploads local skill directories to Nacos whenever their files change
+type SkillPusher struct {
+	skillService *skill.SkillService
+	log          *slog.Logger
+}
+
+// pendingPush is a skill, or a file of config-apply, with local changes that
+// have not been uploaded yet
+type pendingPush struct {
+	files map[string]bool // changed paths relative to the skill or config directory
+	due   time.Time
+	retry listener.Backoff
+}
+
+// NewSkillPusher creates a pusher that reports progress through logger, or to
+// stderr if logger is nil
+func NewSkillPusher(nacosClient *client.NacosClient, logger *slog.Logger) *SkillPusher {
+	if logger == nil {
+		logger = logging.Stderr()
+	}
+	return &SkillPusher{
+		skillService: skill.NewSkillService(nacosClient),
+		log:          logger,
+	}
+}
+
+// PushDirs resolves the skill-sync --push arguments to skill directories:
+// the paths themselves, or with all the skills in the single folder given
+func PushDirs(paths []string, all bool) ([]string, error) {
+	if all && len(paths) != 1 {
+		return nil, fmt.Errorf("--push --all takes exactly one folder")
+	}
+	var dirs []string
+	for _, path := range paths {
+		path, err := util.ExpandTilde(path)
+		if err != nil {
+			return nil, err
+		}
+		if path, err = filepath.Abs(path); err != nil {
+			return nil, err
+		}
+		if all {
+			found, err := skill.SkillDirs(path)
+			if err != nil {
+				return nil, err
+			}
+			// Skip remote versions saved by a conflicting pull
+			for _, dir := range found {
+				if !strings.HasSuffix(dir, remoteDirSuffix) {
+					dirs = append(dirs, dir)
+				}
+			}
+			if len(dirs) == 0 {
+				return nil, fmt.Errorf("no skills found in %s (directories with SKILL.md)", path)
+			}
+			return dirs, nil
+		}
+		if info, err := os.Stat(path); err != nil {
+			return nil, err
+		} else if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a skill directory", path)
+		}
+		dirs = append(dirs, path)
+	}
+	return dirs, nil
+}
+
+// Run watches each skill directory and uploads the skill shortly after its
+// files change. A failed upload is retried with backoff; it always packs the
+// directory as it is at that moment, so the latest edits are never lost.
+// Run blocks until ctx is cancelled.
+func (p *SkillPusher) Run(ctx context.Context, skillDirs []string) error {
+	if len(skillDirs) == 0 {
+		return fmt.Errorf("no skills to push")
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("watch local files: %w", err)
+	}
+	defer watcher.Close()
+	for _, dir := range skillDirs {
+		if err := watchTree(watcher, dir); err != nil {
+			return fmt.Errorf("watch %s: %w", dir, err)
+		}
+	}
+	p.log.Info(fmt.Sprintf("Watching %d local skill(s) for changes", len(skillDirs)), "skills", len(skillDirs))
+
+	pending := make(map[string]*pendingPush)
+	timer := time.NewTimer(time.Hour)
+	timer.Stop()
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			p.log.Info("Push stopped")
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			dir := owningDir(skillDirs, event.Name)
+			rel, err := filepath.Rel(dir, event.Name)
+			if dir == "" || err != nil || ignoredPath(rel) {
+				continue
+			}
+			info, err := os.Stat(event.Name)
+			isDir := err == nil && info.IsDir()
+			if isDir && !event.Has(fsnotify.Create) {
+				continue
+			}
+			push, ok := pending[dir]
+			if !ok {
+				push = &pendingPush{files: make(map[string]bool), retry: listener.Backoff{Base: pushRetryBase, Max: listener.MaxBackoff}}
+				pending[dir] = push
+			}
+			if isDir {
+				// A new or moved-in directory: watch it and push what it holds
+				_ = watchTree(watcher, event.Name)
+				addFiles(push.files, dir, event.Name)
+			} else {
+				push.files[filepath.ToSlash(rel)] = true
+			}
+			push.due = time.Now().Add(pushDebounce)
+			resetTimer(timer, pending)
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			p.log.Warn(fmt.Sprintf("Watch error: %v", err), "error", err)
+
+		case <-timer.C:
+			now := time.Now()
+			for dir, push := range pending {
+				if push.due.After(now) {
+					continue
+				}
+				if p.push(dir, push) {
+					delete(pending, dir)
+				}
+			}
+			resetTimer(timer, pending)
+		}
+	}
+}
+
+// push uploads one skill and reports whether it succeeded. On failure the
+// next attempt is scheduled with backoff.
+func (p *SkillPusher) push(dir string, push *pendingPush) bool {
+	name := filepath.Base(dir)
+	log := p.log.With("skill", name, "dir", dir)
+	start := time.Now()
+	result, err := p.skillService.UploadSkill(dir)
+	if err != nil {
+		delay := push.retry.Next()
+		push.due = time.Now().Add(delay)
+		log.Error(fmt.Sprintf("Failed to push skill %s: %v (retrying in %s)", name, err, delay.Round(time.Second)), "event", EventError, "error", err, "retryIn", delay)
+		return false
+	}
+	files := changedFiles(push.files)
+	log.Info(fmt.Sprintf("Pushed skill %s: %s", name, files), "event", EventPushed, "files", files, "duration", time.Since(start))
+	for _, warning := range result.Warnings {
+		log.Warn(fmt.Sprintf("Nacos did not accept all of skill %s: %s", name, warning), "event", EventPushed, "warning", warning)
+	}
+
+	// What was pushed is now the remote version, so a pull-mode skill-sync of
+	// the same folder does not mistake it for a conflicting edit
+	if hash, err := skill.HashDir(dir); err == nil {
+		state := syncState{RemoteHash: hash, LocalHash: hash, SyncedAt: time.Now()}
+		if err := saveState(filepath.Dir(dir), name, state); err != nil {
+			log.Warn(fmt.Sprintf("Failed to save sync state of %s: %v", name, err), "error", err)
+		}
+	}
+	return true
+}
+
+// resetTimer makes timer fire when the earliest pending push is due
+func resetTimer(timer *time.Timer, pending map[string]*pendingPush) {
+	if !timer.Stop() {
+		select {
+		case <-timer.C:
+		default:
+		}
+	}
+	var next time.Time
+	for _, push := range pending {
+		if next.IsZero() || push.due.Before(next) {
+			next = push.due
+		}
+	}
+	if !next.IsZero() {
+		timer.Reset(time.Until(next))
+	}
+}
+
+// watchTree watches dir and its subdirectories, skipping hidden ones such as .git
+func watchTree(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if path != dir && strings.HasPrefix(d.Name(), ".") {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// addFiles records the files under sub, relative to the skill directory dir
+func addFiles(files map[string]bool, dir, sub string) {
+	_ = filepath.WalkDir(sub, func(path string, d os.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if rel, err := filepath.Rel(dir, path); err == nil && !ignoredPath(rel) {
+			files[filepath.ToSlash(rel)] = true
+		}
+		return nil
+	})
+}
+
+// owningDir returns the skill directory containing path, or ""
+func owningDir(skillDirs []string, path string) string {
+	for _, dir := range skillDirs {
+		if path == dir || strings.HasPrefix(path, dir+string(filepath.Separator)) {
+			return dir
+		}
+	}
+	return ""
+}
+
+// ignoredPath reports whether a change to rel should not trigger a push:
+// the skill directory itself, hidden files and editor swap or backup files
+func ignoredPath(rel string) bool {
+	if rel == "." {
+		return true
+	}
+	for _, part := range strings.Split(filepath.ToSlash(rel), "/") {
+		if strings.HasPrefix(part, ".") {
+			return true
+		}
+	}
+	base := filepath.Base(rel)
+	return strings.HasSuffix(base, "~") || strings.HasSuffix(base, ".swp") || strings.HasSuffix(base, ".swx") || strings.HasSuffix(base, ".tmp")
+}
+
+// changedFiles lists files for a push message, e.g. "SKILL.md, scripts/a.py"
+func changedFiles(files map[string]bool) string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(names) > maxListedFiles {
+		return fmt.Sprintf("%s and %d more", strings.Join(names[:maxListedFiles], ", "), len(names)-maxListedFiles)
+	}
+	return strings.Join(names, ", ")
+}
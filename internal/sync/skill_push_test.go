@@ -0,0 +1,135 @@
+package sync
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+func TestIgnoredPath(t *testing.T) {
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"SKILL.md", false},
+		{"scripts/init.py", false},
+		{".", true},
+		{".git/index", true},
+		{"scripts/.init.py.swp", true},
+		{"SKILL.md~", true},
+		{"notes.tmp", true},
+	}
+	for _, tt := range tests {
+		if got := ignoredPath(tt.rel); got != tt.want {
+			t.Errorf("ignoredPath(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestChangedFiles(t *testing.T) {
+	files := map[string]bool{"b.md": true, "SKILL.md": true, "a/c.py": true}
+	if got, want := changedFiles(files), "SKILL.md, a/c.py, b.md"; got != want {
+		t.Errorf("changedFiles() = %q, want %q", got, want)
+	}
+
+	for i := 0; i < 12; i++ {
+		files[string(rune('d'+i))+".md"] = true
+	}
+	if got := changedFiles(files); !strings.HasSuffix(got, "and 5 more") {
+		t.Errorf("changedFiles() = %q, want the list cut after %d files", got, maxListedFiles)
+	}
+}
+
+func TestPushDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{"one", "two", "notes"} {
+		if err := os.Mkdir(filepath.Join(root, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, dir := range []string{"one", "two"} {
+		if err := os.WriteFile(filepath.Join(root, dir, "SKILL.md"), []byte("# skill"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dirs, err := PushDirs([]string{root}, true)
+	if err != nil || len(dirs) != 2 || filepath.Base(dirs[0]) != "one" || filepath.Base(dirs[1]) != "two" {
+		t.Errorf("PushDirs(--all) = %v, %v; want one and two", dirs, err)
+	}
+	if _, err := PushDirs([]string{root, root}, true); err == nil {
+		t.Error("PushDirs(--all with two folders) succeeded, want an error")
+	}
+	if _, err := PushDirs([]string{filepath.Join(root, "one", "SKILL.md")}, false); err == nil {
+		t.Error("PushDirs(file) succeeded, want an error")
+	}
+}
+
+func TestPusherRetriesFailedUpload(t *testing.T) {
+	var uploads int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// The first upload fails, as if the server were briefly unavailable
+		if atomic.AddInt32(&uploads, 1) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte(`{"code":0,"data":"ok"}`))
+	}))
+	defer server.Close()
+
+	c, err := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "my-skill")
+	if err := os.Mkdir(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	logs := make(chan string, 10)
+	pusher := NewSkillPusher(c, slog.New(logging.NewConsoleHandler(func(msg string) {
+		logs <- msg
+	})))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- pusher.Run(ctx, []string{dir}) }()
+
+	waitFor := func(prefix string) {
+		t.Helper()
+		for {
+			select {
+			case line := <-logs:
+				if strings.HasPrefix(line, prefix) {
+					return
+				}
+			case <-time.After(10 * time.Second):
+				t.Fatalf("timed out waiting for %q", prefix)
+			}
+		}
+	}
+	waitFor("Watching")
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("# my skill"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	waitFor("Failed to push")
+	waitFor("Pushed")
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Run() error = %v", err)
+	}
+	if got := atomic.LoadInt32(&uploads); got != 2 {
+		t.Errorf("uploads = %d, want 2", got)
+	}
+}
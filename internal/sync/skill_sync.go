@@ -0,0 +1,708 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	gosync "sync"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+const (
+	// skillConfigDataID is the config published alongside each skill; it changes on every skill update
+	skillConfigDataID = "skill.json"
+	// skillGroupPrefix prefixes the skill name to form the config group
+	skillGroupPrefix = "skill_"
+	// resourceDataIDPattern matches the configs holding a skill's resource files
+	resourceDataIDPattern = "resource_*"
+	// listPageSize is the page size used when listing resource configs
+	listPageSize = 100
+)
+
+// Events logged in the "event" attribute. All but changed and pushed are
+// also reported to an EventHandler.
+const (
+	EventChanged  = "changed"
+	EventSynced   = "synced"
+	EventUpToDate = "up-to-date"
+	EventConflict = "conflict"
+	EventDeleted  = "deleted"
+	EventPushed   = "pushed"
+	EventError    = "error"
+)
+
+// EventHandler is told what happened to a skill, e.g. to keep a status file current
+type EventHandler func(skill, event string)
+
+// SkillSyncer keeps local skill directories up to date with Nacos
+type SkillSyncer struct {
+	client       *client.NacosClient
+	skillService *skill.SkillService
+	outputDir    string
+	pollTimeout  time.Duration
+	pollInterval time.Duration
+	batchSize    int
+	forceRemote  bool
+	forceInitial bool
+	initWorkers  int
+	jitter       time.Duration // see SetStartupJitter
+	limiter      *util.Limiter // shared by every download; see SetMaxBandwidth
+	debounce     time.Duration // see SetDebounce
+	listenEarly  bool
+	preserveExec bool
+	verboseDiff  bool
+	pins         map[string]string // skill name to the version it is held at
+	dirs         DirMap            // skills synced outside outputDir
+	filter       SkillFilter       // which skills AllSkillNames returns
+	catalog      *Catalog          // config listing the skills to sync, if any
+	log          *slog.Logger
+	onEvent      EventHandler
+	hooks        *hookRunner
+	status       *statusFile
+
+	listener *listener.ConfigListener
+
+	mu     gosync.Mutex
+	names  []string                 // the skills being synced; a catalog changes them
+	synced map[string]time.Time     // when each skill was last downloaded
+	locks  map[string]*gosync.Mutex // serialize the syncs of each skill
+
+	pending  map[string]*pendingChange // changes waiting out the debounce window, by skill
+	stopped  bool                      // Run is returning; no more changes are synced
+	flushing gosync.WaitGroup          // syncs of debounced changes in progress
+}
+
+// NewSkillSyncer creates a syncer that downloads skills into outputDir and
+// reports progress through logger, or to stderr if logger is nil. Entries
+// about a skill carry its name and the event.
+func NewSkillSyncer(nacosClient *client.NacosClient, outputDir string, logger *slog.Logger) *SkillSyncer {
+	if logger == nil {
+		logger = logging.Stderr()
+	}
+	skillService := skill.NewSkillService(nacosClient)
+	skillService.SetLogger(logger)
+	return &SkillSyncer{
+		client:       nacosClient,
+		skillService: skillService,
+		outputDir:    outputDir,
+		log:          logger,
+		hooks:        newHookRunner(Hooks{}, logger),
+		initWorkers:  DefaultInitConcurrency,
+		debounce:     DefaultDebounce,
+		preserveExec: true,
+		synced:       make(map[string]time.Time),
+		locks:        make(map[string]*gosync.Mutex),
+		pending:      make(map[string]*pendingChange),
+	}
+}
+
+// SetPollTimeout sets how long each poll for changes may take; zero keeps
+// listener.DefaultPollTimeout
+func (s *SkillSyncer) SetPollTimeout(timeout time.Duration) {
+	s.pollTimeout = timeout
+}
+
+// SetPollInterval sets how often each watched config is polled; zero keeps
+// listener.PollInterval
+func (s *SkillSyncer) SetPollInterval(interval time.Duration) {
+	s.pollInterval = interval
+}
+
+// SetBatchSize sets how many configs one poller watches; zero keeps
+// listener.DefaultBatchSize
+func (s *SkillSyncer) SetBatchSize(size int) {
+	s.batchSize = size
+}
+
+// SetForceRemote makes remote changes overwrite local edits instead of being
+// saved alongside them as a conflict
+func (s *SkillSyncer) SetForceRemote(force bool) {
+	s.forceRemote = force
+}
+
+// SetForceInitialSync makes the first sync download every skill, even those
+// whose skill.json and local files are unchanged since the last run
+func (s *SkillSyncer) SetForceInitialSync(force bool) {
+	s.forceInitial = force
+}
+
+// SetInitConcurrency sets how many skills the first sync downloads at once;
+// zero keeps DefaultInitConcurrency
+func (s *SkillSyncer) SetInitConcurrency(n int) {
+	if n <= 0 {
+		n = DefaultInitConcurrency
+	}
+	s.initWorkers = n
+}
+
+// SetMaxBandwidth limits all downloads together to bytesPerSecond, however
+// many run at once; zero does not limit
+func (s *SkillSyncer) SetMaxBandwidth(bytesPerSecond int64) {
+	s.limiter = util.NewLimiter(bytesPerSecond)
+	s.skillService.SetLimiter(s.limiter)
+}
+
+// SetStartupJitter makes Run wait a random time up to jitter before the
+// first sync, so many hosts started together do not all download at once
+func (s *SkillSyncer) SetStartupJitter(jitter time.Duration) {
+	s.jitter = jitter
+}
+
+// SetListenEarly makes Run start watching for changes as soon as the current
+// MD5s are known, while the first sync is still downloading
+func (s *SkillSyncer) SetListenEarly(early bool) {
+	s.listenEarly = early
+}
+
+// SetPreserveExec sets whether synced scripts are made executable; see
+// skill.SkillArchive.PreserveExec. It is on by default.
+func (s *SkillSyncer) SetPreserveExec(preserve bool) {
+	s.preserveExec = preserve
+}
+
+// SetVerboseDiff makes the log of an updated skill include a unified diff of
+// each modified text file, not only the summary of which files changed
+func (s *SkillSyncer) SetVerboseDiff(verbose bool) {
+	s.verboseDiff = verbose
+}
+
+// SetPins holds skills at a version instead of the latest; pins maps skill
+// names to versions as accepted by skill-get --version
+func (s *SkillSyncer) SetPins(pins map[string]string) {
+	s.pins = pins
+}
+
+// ParsePins parses --pin values of the form name=version
+func ParsePins(values []string) (map[string]string, error) {
+	pins := make(map[string]string)
+	for _, v := range values {
+		name, version, ok := strings.Cut(v, "=")
+		if !ok || name == "" || version == "" {
+			return nil, fmt.Errorf("invalid pin %q: expected skill=version", v)
+		}
+		if _, dup := pins[name]; dup {
+			return nil, fmt.Errorf("skill %s is pinned more than once", name)
+		}
+		pins[name] = version
+	}
+	return pins, nil
+}
+
+// SetDirMap syncs the skills in dirs into their mapped directory instead of
+// the output directory; see DirMap. The mapping should be validated first.
+func (s *SkillSyncer) SetDirMap(dirs DirMap) {
+	s.dirs = dirs
+}
+
+// SetFilter limits the skills AllSkillNames returns to those filter matches
+func (s *SkillSyncer) SetFilter(filter SkillFilter) {
+	s.filter = filter
+}
+
+// SetEventHandler sets a function told about every sync of a skill
+func (s *SkillSyncer) SetEventHandler(handler EventHandler) {
+	s.onEvent = handler
+}
+
+// SetHooks sets the commands run when a skill is updated, deleted or fails to sync
+func (s *SkillSyncer) SetHooks(hooks Hooks) {
+	s.hooks = newHookRunner(hooks, s.log)
+}
+
+// SetStatusFile makes the syncer write a heartbeat to path after every poll
+// cycle; see Status
+func (s *SkillSyncer) SetStatusFile(path string) {
+	s.status = newStatusFile(path, s.log)
+}
+
+func (s *SkillSyncer) event(name, event string) {
+	if s.onEvent != nil {
+		s.onEvent(name, event)
+	}
+}
+
+// AllSkillNames returns the names of every skill in the current namespace
+// that the filter set with SetFilter matches, logging those it filters out
+func (s *SkillSyncer) AllSkillNames() ([]string, error) {
+	names, err := s.skillService.AllSkillNames()
+	if err != nil || s.filter.Empty() {
+		return names, err
+	}
+	kept := s.filterNames(names)
+	if len(kept) == 0 {
+		return nil, fmt.Errorf("all %d skill(s) were filtered out by --include/--exclude", len(names))
+	}
+	return kept, nil
+}
+
+// filterNames returns the names the filter matches, logging those it filters out
+func (s *SkillSyncer) filterNames(names []string) []string {
+	kept, filtered := s.filter.Apply(names)
+	if len(filtered) > 0 {
+		s.log.Info(fmt.Sprintf("Filtered out %d skill(s) by --include/--exclude: %s", len(filtered), strings.Join(filtered, ", ")), "skills", len(filtered))
+	}
+	return kept
+}
+
+// Run downloads each skill once and then re-downloads it whenever its
+// skill.json or one of its resource configs changes in Nacos. With a catalog
+// the skills follow it as well. It blocks until ctx is cancelled, and returns
+// once running hooks have finished.
+func (s *SkillSyncer) Run(ctx context.Context, skillNames []string) error {
+	if len(skillNames) == 0 && s.catalog == nil {
+		return fmt.Errorf("no skills to sync")
+	}
+	for name := range s.pins {
+		if !slices.Contains(skillNames, name) {
+			return fmt.Errorf("skill %s is pinned but not synced", name)
+		}
+	}
+	defer s.hooks.wait()
+	defer s.stopDebounce()
+	s.setSkillNames(skillNames)
+
+	s.log.Info(fmt.Sprintf("Syncing %d skill(s) to %s", len(skillNames), s.outputDir), "skills", len(skillNames), "dir", s.outputDir)
+	for _, name := range s.dirs.names() {
+		if slices.Contains(skillNames, name) || s.catalog != nil {
+			s.skillLog(name).Info(fmt.Sprintf("Skill %s is synced to %s", name, s.dirs[name]), "dir", s.dirs[name])
+		} else {
+			s.skillLog(name).Warn(fmt.Sprintf("Skill %s is mapped to %s but not synced", name, s.dirs[name]), "dir", s.dirs[name])
+		}
+	}
+	if s.catalog != nil {
+		s.log.Info(fmt.Sprintf("Following catalog %s for skills added and removed", s.catalog), "catalog", s.catalog.String())
+	}
+	if rate := s.limiter.Rate(); rate > 0 {
+		s.log.Info(fmt.Sprintf("Downloads are limited to %s/s in total", util.FormatBytes(rate)), "maxBandwidth", rate)
+	}
+	if !s.startupJitter(ctx) {
+		return nil
+	}
+	if !s.listenEarly {
+		s.initialSync(ctx, skillNames)
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	var items []listener.ConfigItem
+	if s.catalog != nil {
+		items = append(items, s.catalogItem())
+	}
+	for _, name := range skillNames {
+		items = append(items, s.watchItems(name)...)
+	}
+
+	l := listener.NewConfigListener(s.client)
+	l.SetLogger(s.log)
+	l.SetPollTimeout(s.pollTimeout)
+	l.SetPollInterval(s.pollInterval)
+	l.SetBatchSize(s.batchSize)
+	l.SetErrorHandler(func(dataID, group, tenant string, err error) {
+		if s.isCatalog(dataID, group) {
+			return
+		}
+		name := strings.TrimPrefix(group, skillGroupPrefix)
+		s.status.fetchFailed(name, err)
+		s.hooks.run(name, HookError, s.skillDir(name), err, nil)
+	})
+	l.SetCycleHandler(s.status.beat)
+	l.SetReconnectHandler(func(time.Duration) {
+		s.reconcile(ctx, s.skillNames())
+	})
+	l.Prime(items)
+	for _, item := range items {
+		if item.DataID == skillConfigDataID {
+			s.status.md5(strings.TrimPrefix(item.Group, skillGroupPrefix), item.MD5)
+		}
+	}
+	s.status.beat()
+	s.listener = l
+
+	msg := fmt.Sprintf("Watching %d config(s) for changes by polling each every %s", len(items), pollInterval(s.pollInterval))
+	if batches := s.batches(len(items)); batches > 1 {
+		msg = fmt.Sprintf("Watching %d config(s) for changes in %d batches by polling each every %s", len(items), batches, pollInterval(s.pollInterval))
+	}
+	s.log.Info(msg, "configs", len(items), "mode", "poll", "interval", pollInterval(s.pollInterval))
+
+	initialDone := make(chan struct{})
+	if s.listenEarly {
+		go func() {
+			defer close(initialDone)
+			s.initialSync(ctx, skillNames)
+		}()
+	} else {
+		close(initialDone)
+	}
+	err := l.StartListening(items, s.handleChange, ctx.Done())
+	<-initialDone
+	s.log.Info("Sync stopped")
+	return err
+}
+
+// reconcile re-syncs every skill whose skill.json MD5 no longer matches its
+// sync state, or whose local copy went missing, after the connection to Nacos
+// was lost. Polling picks up the changes as well; this catches them in one
+// pass right away.
+func (s *SkillSyncer) reconcile(ctx context.Context, skillNames []string) {
+	reconciled := 0
+	for _, name := range skillNames {
+		if ctx.Err() != nil {
+			return
+		}
+		state, err := loadState(s.baseDir(name), name)
+		if err == nil && state != nil && state.SkillMD5 != "" && s.skillMD5(name) == state.SkillMD5 && intact(s.skillDir(name), state) {
+			continue
+		}
+		s.skillLog(name).Info(fmt.Sprintf("Skill %s changed while disconnected", name), "event", EventChanged)
+		if _, err := s.download(name, skillConfigDataID, nil); err == nil {
+			reconciled++
+		}
+	}
+	s.log.Info(fmt.Sprintf("Reconnected, reconciled %d skill(s)", reconciled), "event", "reconciled", "skills", reconciled)
+}
+
+// batches returns how many pollers the listener starts for n configs
+func (s *SkillSyncer) batches(n int) int {
+	size := s.batchSize
+	if size <= 0 {
+		size = listener.DefaultBatchSize
+	}
+	return (n + size - 1) / size
+}
+
+// pollInterval returns interval, or listener.PollInterval when it is zero
+func pollInterval(interval time.Duration) time.Duration {
+	if interval <= 0 {
+		return listener.PollInterval
+	}
+	return interval
+}
+
+// watchItems returns the configs to watch for a skill: its skill.json and
+// every resource config in its group. If the resources cannot be listed only
+// skill.json is watched, and they are listed again when it changes.
+func (s *SkillSyncer) watchItems(name string) []listener.ConfigItem {
+	group := skillGroupPrefix + name
+	items := []listener.ConfigItem{{DataID: skillConfigDataID, Group: group, Tenant: s.client.Namespace}}
+	for page := 1; ; page++ {
+		resp, err := s.client.ListConfigs(resourceDataIDPattern, group, s.client.Namespace, page, listPageSize)
+		if err != nil {
+			s.skillLog(name).Warn(fmt.Sprintf("Failed to list resources of skill %s: %v", name, err), "error", err)
+			return items
+		}
+		for _, cfg := range resp.PageItems {
+			items = append(items, listener.ConfigItem{DataID: cfg.DataID, Group: group, Tenant: s.client.Namespace})
+		}
+		if len(resp.PageItems) == 0 || len(items)-1 >= resp.TotalCount {
+			return items
+		}
+	}
+}
+
+// handleChange re-downloads the skill that owns the changed config once
+// its changes settle; see SetDebounce
+func (s *SkillSyncer) handleChange(dataID, group, tenant, change string) error {
+	if s.isCatalog(dataID, group) {
+		return s.catalogChanged()
+	}
+	name := strings.TrimPrefix(group, skillGroupPrefix)
+	if s.debounce <= 0 {
+		return s.applyChange(name, tenant, dataID, change, 1, time.Now())
+	}
+	s.enqueue(name, tenant, dataID, change, s.debounce)
+	return nil
+}
+
+// download fetches the latest version of a skill into the output directory.
+// If the local copy was edited since the last sync it is kept, and a changed
+// remote version is saved next to it as <skill>.remote instead. A synced
+// skill deleted in Nacos is removed locally unless it was edited. dataID is
+// the config whose change triggered the download, or "" for the first sync,
+// which also passes its progress. It returns the event reported for the skill.
+// An update of a skill installed before is logged with the files it changed.
+func (s *SkillSyncer) download(name, dataID string, progress *progress) (string, error) {
+	unlock := s.lock(name)
+	defer unlock()
+	start := time.Now()
+	event, changes, err := s.sync(name, dataID)
+	if err != nil {
+		event = EventError
+	}
+	s.event(name, event)
+	s.status.dir(name, s.skillDir(name))
+	s.status.event(name, event, err)
+	switch event {
+	case EventSynced:
+		s.hooks.run(name, HookUpdated, s.skillDir(name), nil, changes)
+	case EventDeleted:
+		s.hooks.run(name, HookDeleted, s.skillDir(name), nil, nil)
+	case EventError:
+		s.hooks.run(name, HookError, s.skillDir(name), err, nil)
+	}
+
+	log := s.skillLog(name).With("event", event, "duration", time.Since(start))
+	var summary string
+	if changes != nil {
+		summary = skill.SummarizeChanges(changes)
+		log = log.With("changes", summary)
+		defer s.logDiffs(log, name, changes)
+	}
+	if progress != nil {
+		step := progress.next()
+		switch event {
+		case EventSynced:
+			if summary != "" {
+				log.Info(fmt.Sprintf("%s %s ✓ (%s)", step, name, summary))
+				return event, err
+			}
+			log.Info(fmt.Sprintf("%s %s ✓", step, name))
+			return event, err
+		case EventUpToDate:
+			log.Info(fmt.Sprintf("%s %s ✓ (up to date)", step, name))
+			return event, err
+		case EventError:
+			log.Error(fmt.Sprintf("%s %s ✗ %v", step, name, err), "error", err)
+			return event, err
+		}
+		log = log.With("progress", step)
+	}
+	switch event {
+	case EventSynced:
+		if summary != "" {
+			log.Info(fmt.Sprintf("Skill %s synced: %s", name, summary))
+			break
+		}
+		log.Info(fmt.Sprintf("Skill %s synced", name))
+	case EventUpToDate:
+		log.Info(fmt.Sprintf("Skill %s is up to date", name))
+	case EventConflict:
+		log.Warn(fmt.Sprintf("Conflict: skill %s changed both locally and in Nacos; kept the local files and saved the remote version to %s", name, s.remoteDir(name)))
+		log.Info("Merge by hand, or run skill-sync with --force-remote to take the remote version")
+	case EventDeleted:
+		if _, err := os.Stat(s.skillDir(name)); err == nil {
+			log.Warn(fmt.Sprintf("Skill %s was deleted in Nacos; kept %s because it has local edits", name, s.skillDir(name)))
+		} else {
+			log.Info(fmt.Sprintf("Skill %s was deleted in Nacos; removed %s", name, s.skillDir(name)))
+		}
+	}
+	return event, err
+}
+
+// logDiffs logs the diff of each modified text file with --verbose-diff
+func (s *SkillSyncer) logDiffs(log *slog.Logger, name string, changes []skill.FileChange) {
+	for _, c := range changes {
+		if c.Diff != "" {
+			log.Info(fmt.Sprintf("Diff of %s in skill %s:\n%s", c.Path, name, strings.TrimSuffix(c.Diff, "\n")), "file", c.Path)
+		}
+	}
+}
+
+// lock serializes the syncs of a skill, such as its first sync and a change
+// handled while it runs with --listen-early. It returns the unlock function.
+func (s *SkillSyncer) lock(name string) func() {
+	s.mu.Lock()
+	l, ok := s.locks[name]
+	if !ok {
+		l = &gosync.Mutex{}
+		s.locks[name] = l
+	}
+	s.mu.Unlock()
+	l.Lock()
+	return l.Unlock
+}
+
+// markSynced notes that a skill was just fetched from Nacos
+func (s *SkillSyncer) markSynced(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.synced[name] = time.Now()
+}
+
+// lastSynced returns when a skill was last fetched from Nacos
+func (s *SkillSyncer) lastSynced(name string) time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.synced[name]
+}
+
+// skillLog returns the logger with the skill attribute set
+func (s *SkillSyncer) skillLog(name string) *slog.Logger {
+	return s.log.With("skill", name)
+}
+
+// sync does the work of download and returns the event to report. Publishing
+// a skill with unchanged content is a no-op: when only skill.json changed, or
+// on the first sync after a restart, and its MD5 still matches the last sync
+// the skill is not downloaded at all, and a downloaded skill whose files
+// match the last sync is not installed again. A local copy that went missing
+// is restored. When a skill installed before is updated, it also returns the
+// files that changed.
+func (s *SkillSyncer) sync(name, dataID string) (string, []skill.FileChange, error) {
+	state, err := loadState(s.baseDir(name), name)
+	if err != nil {
+		return "", nil, err
+	}
+	skillMD5 := s.skillMD5(name)
+	skillDir := s.skillDir(name)
+	version := s.pins[name]
+	checkMD5 := dataID == skillConfigDataID || (dataID == "" && !s.forceInitial)
+	if state != nil {
+		s.skillLog(name).Debug(fmt.Sprintf("Skill %s: skill.json md5 %s, last synced %s", name, skillMD5, state.SkillMD5),
+			"md5", skillMD5, "syncedMd5", state.SkillMD5, "checkMd5", checkMD5, "version", version)
+	}
+	if checkMD5 && state != nil && !s.forceRemote && skillMD5 != "" && skillMD5 == state.SkillMD5 && version == state.Version && intact(skillDir, state) {
+		s.markSynced(name)
+		return EventUpToDate, nil, nil
+	}
+
+	// A pinned skill is still downloaded when Nacos changes, but as the
+	// pinned version, so it stays up to date unless the pin's content changed
+	archive, err := s.skillService.DownloadSkill(name, version, "")
+	if errors.Is(err, skill.ErrSkillNotFound) {
+		event, err := s.remove(name, err)
+		return event, nil, err
+	}
+	if err != nil {
+		return "", nil, err
+	}
+	archive.PreserveExec = s.preserveExec
+	remoteHash, err := archive.Hash()
+	if err != nil {
+		return "", nil, err
+	}
+	s.markSynced(name)
+
+	if state != nil && !s.forceRemote {
+		if _, err := os.Stat(skillDir); remoteHash == state.RemoteHash && err == nil {
+			if (skillMD5 != "" && skillMD5 != state.SkillMD5) || version != state.Version {
+				// Remember the republished skill.json, so the next no-op
+				// publish is recognized without downloading
+				state.SkillMD5 = skillMD5
+				state.Version = version
+				if err := saveState(s.baseDir(name), name, *state); err != nil {
+					return "", nil, fmt.Errorf("save sync state: %w", err)
+				}
+			}
+			return EventUpToDate, nil, nil
+		}
+		if localHash, err := skill.HashDir(skillDir); err == nil && localHash != state.LocalHash {
+			return EventConflict, nil, s.saveConflict(name, archive)
+		}
+	}
+
+	changes := s.changes(name, archive)
+	result, err := archive.Extract(s.baseDir(name))
+	if err != nil {
+		return "", nil, err
+	}
+	localHash, err := skill.HashDir(skillDir)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := saveState(s.baseDir(name), name, syncState{RemoteHash: remoteHash, LocalHash: localHash, SkillMD5: skillMD5, Version: version, Bytes: result.Bytes, SyncedAt: time.Now()}); err != nil {
+		return "", nil, fmt.Errorf("save sync state: %w", err)
+	}
+	return EventSynced, changes, nil
+}
+
+// changes compares the archive with the installed copy of a skill, or returns
+// nil if there is none. Failing to compare does not hold up the sync.
+func (s *SkillSyncer) changes(name string, archive *skill.SkillArchive) []skill.FileChange {
+	if _, err := os.Stat(s.skillDir(name)); err != nil {
+		return nil
+	}
+	changes, err := archive.Changes(s.skillDir(name), s.verboseDiff)
+	if err != nil {
+		s.skillLog(name).Warn(fmt.Sprintf("Failed to compare skill %s with its installed copy: %v", name, err), "error", err)
+		return nil
+	}
+	if changes == nil {
+		changes = []skill.FileChange{}
+	}
+	return changes
+}
+
+// skillMD5 returns the MD5 of a skill's skill.json in Nacos, or "" if it
+// cannot be fetched. It is read before the skill is downloaded, so a change
+// in between shows up as a difference on the next poll.
+func (s *SkillSyncer) skillMD5(name string) string {
+	content, err := s.client.GetConfig(skillConfigDataID, skillGroupPrefix+name)
+	if err != nil {
+		return ""
+	}
+	return client.CalculateMD5(content)
+}
+
+// intact reports whether the local copy of a skill is still exactly what was
+// last synced
+func intact(skillDir string, state *syncState) bool {
+	localHash, err := skill.HashDir(skillDir)
+	return err == nil && localHash == state.LocalHash
+}
+
+// remove handles a skill that no longer exists in Nacos. A skill synced
+// before is deleted locally, unless it was edited since; one never synced
+// is an error (notFound).
+func (s *SkillSyncer) remove(name string, notFound error) (string, error) {
+	state, err := loadState(s.baseDir(name), name)
+	if err != nil {
+		return "", err
+	}
+	if state == nil {
+		return "", notFound
+	}
+	s.markSynced(name)
+
+	skillDir := s.skillDir(name)
+	if localHash, err := skill.HashDir(skillDir); err == nil && localHash == state.LocalHash {
+		if err := os.RemoveAll(skillDir); err != nil {
+			return "", err
+		}
+	}
+	if err := removeState(s.baseDir(name), name); err != nil {
+		return "", fmt.Errorf("remove sync state: %w", err)
+	}
+	return EventDeleted, nil
+}
+
+// baseDir is the directory a skill is synced into: its mapped directory, or
+// the output directory
+func (s *SkillSyncer) baseDir(name string) string {
+	if dir, ok := s.dirs[name]; ok {
+		return dir
+	}
+	return s.outputDir
+}
+
+// skillDir is the local directory of a skill
+func (s *SkillSyncer) skillDir(name string) string {
+	return filepath.Join(s.baseDir(name), name)
+}
+
+// saveConflict writes the remote version of a locally edited skill to
+// <skill>.remote, replacing any earlier one, and leaves the local copy alone
+func (s *SkillSyncer) saveConflict(name string, archive *skill.SkillArchive) error {
+	remoteDir := s.remoteDir(name)
+	if err := os.RemoveAll(remoteDir); err != nil {
+		return err
+	}
+	_, err := archive.ExtractInto(remoteDir)
+	return err
+}
+
+// remoteDir is where the remote version of a conflicting skill is saved
+func (s *SkillSyncer) remoteDir(name string) string {
+	return filepath.Join(s.baseDir(name), name+remoteDirSuffix)
+}
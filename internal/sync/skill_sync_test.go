@@ -0,0 +1,523 @@
+package sync
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	gosync "sync"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	"github.com/nacos-group/nacos-cli/internal/nacostest"
+	"github.com/nacos-group/nacos-cli/internal/skill"
+)
+
+// newSkillServer serves the skill "demo" with a SKILL.md that set can change,
+// and a skill.json that never changes; setting "" deletes the skill
+func newSkillServer(t *testing.T) (*client.NacosClient, func(content string)) {
+	var mu gosync.Mutex
+	content := "v1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		if content == "" {
+			http.Error(w, `{"code":20004,"message":"skill not found"}`, http.StatusNotFound)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/cs/config") {
+			w.Header().Set("Config-Type", "yaml")
+			w.Write([]byte("name: demo"))
+			return
+		}
+		zw := zip.NewWriter(w)
+		f, _ := zw.Create("demo/SKILL.md")
+		f.Write([]byte(content))
+		zw.Close()
+	}))
+	t.Cleanup(server.Close)
+
+	c, err := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c, func(s string) {
+		mu.Lock()
+		content = s
+		mu.Unlock()
+	}
+}
+
+func readSkillMD(t *testing.T, dir string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, "SKILL.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestDownloadKeepsLocalEdits(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	local := filepath.Join(out, "demo")
+
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if _, err := os.Stat(statePath(out, "demo")); err != nil {
+		t.Fatalf("no sync state after download: %v", err)
+	}
+
+	// A remote change with no local edits is applied
+	setRemote("v2")
+	if _, err := syncer.download("demo", "resource_a", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "v2" {
+		t.Fatalf("SKILL.md = %q, want v2", got)
+	}
+
+	// A remote change after a local edit is saved alongside
+	os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("mine"), 0644)
+	setRemote("v3")
+	if _, err := syncer.download("demo", "resource_a", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "mine" {
+		t.Errorf("local SKILL.md = %q, want the local edit kept", got)
+	}
+	if got := readSkillMD(t, local+remoteDirSuffix); got != "v3" {
+		t.Errorf("remote SKILL.md = %q, want v3", got)
+	}
+
+	// --force-remote restores overwriting
+	syncer.SetForceRemote(true)
+	if _, err := syncer.download("demo", "resource_a", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "v3" {
+		t.Errorf("SKILL.md with --force-remote = %q, want v3", got)
+	}
+}
+
+func TestDownloadLogsChanges(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	var logs []string
+	syncer := NewSkillSyncer(c, out, slog.New(logging.NewConsoleHandler(func(msg string) { logs = append(logs, msg) })))
+	syncer.SetVerboseDiff(true)
+
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if strings.Join(logs, "\n") != "Skill demo synced" {
+		t.Errorf("first sync logged %q, want no changes", logs)
+	}
+
+	logs = nil
+	setRemote("v2")
+	if _, err := syncer.download("demo", "resource_a", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	want := []string{"Skill demo synced: 1 modified (SKILL.md)", "Diff of SKILL.md in skill demo:\n--- a/SKILL.md\n+++ b/SKILL.md\n@@ -1,1 +1,1 @@\n-v1\n+v2"}
+	if strings.Join(logs, "|") != strings.Join(want, "|") {
+		t.Errorf("update logged %q, want %q", logs, want)
+	}
+}
+
+func TestDownloadRemovesDeletedSkill(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	var events []string
+	syncer.SetEventHandler(func(skill, event string) { events = append(events, event) })
+	local := filepath.Join(out, "demo")
+
+	setRemote("")
+	if _, err := syncer.download("demo", "", nil); !errors.Is(err, skill.ErrSkillNotFound) {
+		t.Fatalf("download() of a skill never synced error = %v, want ErrSkillNotFound", err)
+	}
+
+	setRemote("v1")
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	setRemote("")
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() of a deleted skill error = %v", err)
+	}
+	if _, err := os.Stat(local); !os.IsNotExist(err) {
+		t.Errorf("local copy of a deleted skill still exists: %v", err)
+	}
+	if _, err := os.Stat(statePath(out, "demo")); !os.IsNotExist(err) {
+		t.Errorf("sync state of a deleted skill still exists: %v", err)
+	}
+
+	// A deleted skill that was edited locally is kept
+	setRemote("v2")
+	syncer.download("demo", "", nil)
+	os.WriteFile(filepath.Join(local, "SKILL.md"), []byte("mine"), 0644)
+	setRemote("")
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "mine" {
+		t.Errorf("SKILL.md = %q, want the local edit kept", got)
+	}
+
+	want := []string{EventError, EventSynced, EventDeleted, EventSynced, EventDeleted}
+	if strings.Join(events, ",") != strings.Join(want, ",") {
+		t.Errorf("events = %v, want %v", events, want)
+	}
+}
+
+func TestUnchangedSkillJSONSkipsDownload(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	local := filepath.Join(out, "demo")
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+
+	// skill.json was republished with the same MD5: nothing is downloaded
+	setRemote("v2")
+	if _, err := syncer.download("demo", skillConfigDataID, nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "v1" {
+		t.Fatalf("SKILL.md = %q, want v1 kept", got)
+	}
+
+	// A resource change is always downloaded
+	if _, err := syncer.download("demo", "resource_a", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "v2" {
+		t.Fatalf("SKILL.md = %q, want v2", got)
+	}
+
+	// A local copy that went missing is restored even though nothing changed
+	if err := os.RemoveAll(local); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := syncer.download("demo", skillConfigDataID, nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "v2" {
+		t.Fatalf("SKILL.md = %q, want v2 restored", got)
+	}
+}
+
+func TestRestartSkipsUnchangedSkills(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	local := filepath.Join(out, "demo")
+	if _, err := NewSkillSyncer(c, out, logging.Discard()).download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+
+	// After a restart a skill whose skill.json MD5 and files are unchanged is
+	// not downloaded
+	setRemote("v2")
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	event, err := syncer.download("demo", "", nil)
+	if err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if event != EventUpToDate || readSkillMD(t, local) != "v1" {
+		t.Fatalf("download() after restart = %s with SKILL.md %q, want up-to-date with v1", event, readSkillMD(t, local))
+	}
+
+	// --force-initial-sync downloads it anyway
+	syncer = NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetForceInitialSync(true)
+	if event, err = syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if event != EventSynced || readSkillMD(t, local) != "v2" {
+		t.Fatalf("download() with --force-initial-sync = %s with SKILL.md %q, want synced with v2", event, readSkillMD(t, local))
+	}
+}
+
+func TestPinnedSkillKeepsVersion(t *testing.T) {
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/cs/config") {
+			w.Header().Set("Config-Type", "yaml")
+			w.Write([]byte("name: demo"))
+			return
+		}
+		version := r.URL.Query().Get("version")
+		requested = append(requested, version)
+		zw := zip.NewWriter(w)
+		f, _ := zw.Create("demo/SKILL.md")
+		f.Write([]byte("content of " + version))
+		zw.Close()
+	}))
+	t.Cleanup(server.Close)
+	c, err := client.NewNacosClient(strings.TrimPrefix(server.URL, "http://"), "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	local := filepath.Join(out, "demo")
+
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetPins(map[string]string{"demo": "v1"})
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "content of v1" {
+		t.Fatalf("SKILL.md = %q, want the pinned v1", got)
+	}
+	// A change in Nacos fetches the pinned version again, which is unchanged
+	if event, err := syncer.download("demo", "resource_a", nil); err != nil || event != EventUpToDate {
+		t.Fatalf("download() after a change = %s, %v; want up-to-date", event, err)
+	}
+
+	// Moving the pin is picked up after a restart even though skill.json
+	// did not change
+	syncer = NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetPins(map[string]string{"demo": "v2"})
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "content of v2" {
+		t.Fatalf("SKILL.md = %q, want the new pin v2", got)
+	}
+
+	// Without a pin the latest version is fetched
+	syncer = NewSkillSyncer(c, out, logging.Discard())
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "content of " {
+		t.Fatalf("SKILL.md = %q, want the latest version", got)
+	}
+	if want := []string{"v1", "v1", "v2", ""}; strings.Join(requested, ",") != strings.Join(want, ",") {
+		t.Errorf("requested versions %q, want %q", requested, want)
+	}
+}
+
+func TestParsePins(t *testing.T) {
+	pins, err := ParsePins([]string{"a=1.3.2", "b=v2"})
+	if err != nil || pins["a"] != "1.3.2" || pins["b"] != "v2" {
+		t.Errorf("ParsePins() = %v, %v", pins, err)
+	}
+	for _, bad := range [][]string{{"a"}, {"=1"}, {"a="}, {"a=1", "a=2"}} {
+		if _, err := ParsePins(bad); err == nil {
+			t.Errorf("ParsePins(%q) succeeded", bad)
+		}
+	}
+}
+
+func TestMappedSkillDir(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out, mapped := t.TempDir(), t.TempDir()
+	statusPath := filepath.Join(t.TempDir(), "sync-status.json")
+	syncer := NewSkillSyncer(c, out, logging.Discard())
+	syncer.SetDirMap(DirMap{"demo": mapped})
+	syncer.SetStatusFile(statusPath)
+	local := filepath.Join(mapped, "demo")
+
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+	if got := readSkillMD(t, local); got != "v1" {
+		t.Errorf("SKILL.md = %q, want v1 in the mapped directory", got)
+	}
+	if _, err := os.Stat(statePath(mapped, "demo")); err != nil {
+		t.Errorf("no sync state in the mapped directory: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(out, "demo")); !os.IsNotExist(err) {
+		t.Errorf("skill was also synced to the output directory: %v", err)
+	}
+	syncer.status.beat()
+	status, err := ReadStatus(statusPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := status.Skills["demo"].Dir; got != local {
+		t.Errorf("status dir = %q, want %q", got, local)
+	}
+
+	setRemote("")
+	if event, err := syncer.download("demo", "", nil); err != nil || event != EventDeleted {
+		t.Fatalf("download() of a deleted skill = %s, %v; want deleted", event, err)
+	}
+	if _, err := os.Stat(local); !os.IsNotExist(err) {
+		t.Errorf("deleted skill still exists in the mapped directory: %v", err)
+	}
+	if _, err := os.Stat(statePath(mapped, "demo")); !os.IsNotExist(err) {
+		t.Errorf("sync state of a deleted skill still exists: %v", err)
+	}
+}
+
+func TestReconcileAfterReconnect(t *testing.T) {
+	c, _ := newSkillServer(t)
+	out := t.TempDir()
+	var logs strings.Builder
+	syncer := NewSkillSyncer(c, out, logging.Printf(func(format string, args ...any) {
+		fmt.Fprintf(&logs, format+"\n", args...)
+	}))
+	if _, err := syncer.download("demo", "", nil); err != nil {
+		t.Fatalf("download() error = %v", err)
+	}
+
+	// Nothing changed during the outage
+	syncer.reconcile(context.Background(), []string{"demo"})
+	if !strings.Contains(logs.String(), "reconciled 0 skill(s)") {
+		t.Fatalf("log = %q, want 0 skills reconciled", logs.String())
+	}
+
+	// A skill that no longer matches its sync state is synced again
+	local := filepath.Join(out, "demo")
+	if err := os.RemoveAll(local); err != nil {
+		t.Fatal(err)
+	}
+	syncer.reconcile(context.Background(), []string{"demo"})
+	if !strings.Contains(logs.String(), "reconciled 1 skill(s)") {
+		t.Errorf("log = %q, want 1 skill reconciled", logs.String())
+	}
+	if got := readSkillMD(t, local); got != "v1" {
+		t.Errorf("SKILL.md = %q, want v1 restored", got)
+	}
+}
+
+func TestRecreatedSkillIsSyncedAgain(t *testing.T) {
+	c, setRemote := newSkillServer(t)
+	out := t.TempDir()
+	var mu gosync.Mutex
+	var logs strings.Builder
+	syncer := NewSkillSyncer(c, out, logging.Printf(func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(&logs, format+"\n", args...)
+	}))
+	syncer.SetPollInterval(10 * time.Millisecond)
+	syncer.SetDebounce(10 * time.Millisecond)
+	local := filepath.Join(out, "demo")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- syncer.Run(ctx, []string{"demo"}) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); !cond(); {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	exists := func() bool {
+		_, err := os.Stat(local)
+		return err == nil
+	}
+	waitFor("the skill to be watched", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return strings.Contains(logs.String(), "Watching")
+	})
+
+	// Deleted and uploaded again within the resync window, with the same
+	// skill.json as before
+	setRemote("")
+	waitFor("the skill to be removed", func() bool { return !exists() })
+	setRemote("v2")
+	waitFor("the recreated skill", func() bool {
+		data, err := os.ReadFile(filepath.Join(local, "SKILL.md"))
+		return err == nil && string(data) == "v2"
+	})
+}
+
+func TestRemoteChangesAreSynced(t *testing.T) {
+	server := nacostest.NewServer(t)
+	server.SetSkill("demo", map[string]string{"SKILL.md": "---\nname: demo\n---\nv1"})
+	c, err := client.NewNacosClient(server.Addr, "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := t.TempDir()
+	var mu gosync.Mutex
+	var logs strings.Builder
+	syncer := NewSkillSyncer(c, out, logging.Printf(func(format string, args ...any) {
+		mu.Lock()
+		defer mu.Unlock()
+		fmt.Fprintf(&logs, format+"\n", args...)
+	}))
+	syncer.SetPollInterval(10 * time.Millisecond)
+	syncer.SetDebounce(10 * time.Millisecond)
+	local := filepath.Join(out, "demo")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- syncer.Run(ctx, []string{"demo"}) }()
+	defer func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Run() error = %v", err)
+		}
+	}()
+
+	waitFor := func(what string, cond func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(5 * time.Second); !cond(); {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	fileIs := func(name, want string) func() bool {
+		return func() bool {
+			data, err := os.ReadFile(filepath.Join(local, name))
+			return err == nil && string(data) == want
+		}
+	}
+	waitFor("the skill to be watched", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return strings.Contains(logs.String(), "Watching")
+	})
+	if got := readSkillMD(t, local); got != "---\nname: demo\n---\nv1" {
+		t.Fatalf("SKILL.md after the initial sync = %q", got)
+	}
+
+	// SKILL.md edited and a script added
+	server.SetSkill("demo", map[string]string{
+		"SKILL.md":       "---\nname: demo\n---\nv2",
+		"scripts/run.sh": "echo hi",
+	})
+	waitFor("the edited SKILL.md", fileIs("SKILL.md", "---\nname: demo\n---\nv2"))
+	waitFor("the added script", fileIs("scripts/run.sh", "echo hi"))
+
+	// The script changed on its own
+	server.SetSkill("demo", map[string]string{
+		"SKILL.md":       "---\nname: demo\n---\nv2",
+		"scripts/run.sh": "echo bye",
+	})
+	waitFor("the changed script", fileIs("scripts/run.sh", "echo bye"))
+
+	server.DeleteSkill("demo")
+	waitFor("the skill to be removed", func() bool {
+		_, err := os.Stat(local)
+		return os.IsNotExist(err)
+	})
+}
@@ -0,0 +1,70 @@
+package sync
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// stateDirName holds one state file per skill, next to the skill directories
+	stateDirName = ".sync-state"
+	// remoteDirSuffix names the directory a conflicting remote version is saved to
+	remoteDirSuffix = ".remote"
+)
+
+// syncState records what skill-sync last synced for a skill, so a later
+// remote change can tell whether the local copy was edited since
+type syncState struct {
+	RemoteHash string    `json:"remoteHash"`         // skill.SkillArchive.Hash of the synced version
+	LocalHash  string    `json:"localHash"`          // skill.HashDir of the local copy right after syncing
+	SkillMD5   string    `json:"skillMd5,omitempty"` // MD5 of skill.json in Nacos when last checked
+	Version    string    `json:"version,omitempty"`  // version the skill was pinned to, or "" for the latest
+	Bytes      int64     `json:"bytes,omitempty"`    // size of the extracted files, to order the next first sync
+	SyncedAt   time.Time `json:"syncedAt"`
+}
+
+// statePath returns e.g. ~/.skills/.sync-state/my-skill.json for a skill in ~/.skills
+func statePath(skillsDir, name string) string {
+	return filepath.Join(skillsDir, stateDirName, name+".json")
+}
+
+// loadState returns the state of a skill, or nil if it was never synced
+func loadState(skillsDir, name string) (*syncState, error) {
+	data, err := os.ReadFile(statePath(skillsDir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var state syncState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("read sync state of %s: %w", name, err)
+	}
+	return &state, nil
+}
+
+func saveState(skillsDir, name string, state syncState) error {
+	path := statePath(skillsDir, name)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// removeState forgets a skill, e.g. after it was deleted in Nacos
+func removeState(skillsDir, name string) error {
+	err := os.Remove(statePath(skillsDir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
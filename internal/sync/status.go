@@ -0,0 +1,167 @@
+package sync
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	gosync "sync"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+// Status is the heartbeat a SkillSyncer writes after every poll cycle, so
+// monitoring can tell a sync that is running but no longer making progress
+type Status struct {
+	PID       int                    `json:"pid"`
+	UpdatedAt time.Time              `json:"updatedAt"` // end of the last poll cycle
+	Skills    map[string]SkillStatus `json:"skills"`
+	LastError *StatusError           `json:"lastError,omitempty"`
+}
+
+// SkillStatus is what was last synced of a skill
+type SkillStatus struct {
+	Event      string     `json:"event"`                // last event, e.g. synced or error
+	LastSynced *time.Time `json:"lastSynced,omitempty"` // when it was last found up to date
+	MD5        string     `json:"md5,omitempty"`        // MD5 of its skill.json in Nacos
+	Dir        string     `json:"dir,omitempty"`        // local directory it is synced to
+}
+
+// StatusError is the most recent sync or fetch error
+type StatusError struct {
+	Skill   string    `json:"skill,omitempty"`
+	Message string    `json:"message"`
+	At      time.Time `json:"at"`
+}
+
+// ReadStatus reads a heartbeat file written by a SkillSyncer
+func ReadStatus(path string) (*Status, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var status Status
+	if err := json.Unmarshal(data, &status); err != nil {
+		return nil, fmt.Errorf("read sync status: %w", err)
+	}
+	return &status, nil
+}
+
+// Print pretty-prints the status, indented by two spaces
+func (s *Status) Print(w io.Writer) {
+	fmt.Fprintf(w, "  %s %s\n", util.PadRight(i18n.T("Last poll cycle:"), 16), i18n.T("%s (%s ago, pid %d)", s.UpdatedAt.Format(time.DateTime), time.Since(s.UpdatedAt).Round(time.Second), s.PID))
+	if e := s.LastError; e != nil {
+		subject := ""
+		if e.Skill != "" {
+			subject = e.Skill + ": "
+		}
+		fmt.Fprintf(w, "  %s %s\n", util.PadRight(i18n.T("Last error:"), 16), i18n.T("%s%s (at %s)", subject, e.Message, e.At.Format(time.DateTime)))
+	}
+
+	names := make([]string, 0, len(s.Skills))
+	for name := range s.Skills {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	fmt.Fprintf(w, "\n  %s %s %s %s %s\n", util.PadRight(i18n.T("SKILL"), 30), util.PadRight(i18n.T("LAST EVENT"), 12), util.PadRight(i18n.T("LAST SYNCED"), 20), util.PadRight("MD5", 32), i18n.T("DIR"))
+	for _, name := range names {
+		skill := s.Skills[name]
+		synced := i18n.T("never")
+		if skill.LastSynced != nil {
+			synced = skill.LastSynced.Format(time.DateTime)
+		}
+		fmt.Fprintf(w, "  %s %-12s %s %s %s\n", util.PadRight(name, 30), skill.Event, util.PadRight(synced, 20), util.PadRight(skill.MD5, 32), skill.Dir)
+	}
+}
+
+// statusFile keeps a heartbeat file up to date. A nil statusFile does nothing.
+type statusFile struct {
+	path    string
+	log     *slog.Logger
+	mu      gosync.Mutex
+	status  Status
+	failing bool // the last write failed; logged once until a write succeeds
+}
+
+func newStatusFile(path string, log *slog.Logger) *statusFile {
+	return &statusFile{path: path, log: log, status: Status{PID: os.Getpid(), Skills: make(map[string]SkillStatus)}}
+}
+
+// event records what happened to a skill
+func (f *statusFile) event(skill, event string, err error) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.status.Skills[skill]
+	s.Event = event
+	switch event {
+	case EventSynced, EventUpToDate:
+		now := time.Now()
+		s.LastSynced = &now
+	case EventError:
+		f.status.LastError = &StatusError{Skill: skill, Message: err.Error(), At: time.Now()}
+	}
+	f.status.Skills[skill] = s
+}
+
+// md5 records the MD5 of a skill's skill.json in Nacos
+func (f *statusFile) md5(skill, md5 string) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.status.Skills[skill]
+	s.MD5 = md5
+	f.status.Skills[skill] = s
+}
+
+// dir records the local directory of a skill
+func (f *statusFile) dir(skill, dir string) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	s := f.status.Skills[skill]
+	s.Dir = dir
+	f.status.Skills[skill] = s
+}
+
+// fetchFailed records an error fetching a config of a skill while polling
+func (f *statusFile) fetchFailed(skill string, err error) {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status.LastError = &StatusError{Skill: skill, Message: err.Error(), At: time.Now()}
+}
+
+// beat stamps the status with the current time and writes it atomically.
+// A failed write is logged once, not on every poll cycle.
+func (f *statusFile) beat() {
+	if f == nil {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.status.UpdatedAt = time.Now()
+	data, err := json.MarshalIndent(f.status, "", "  ")
+	if err == nil {
+		_, err = writeFileAtomic(f.path, data)
+	}
+	switch {
+	case err != nil && !f.failing:
+		f.log.Warn(fmt.Sprintf("Failed to write sync status to %s: %v", f.path, err), "error", err)
+	case err == nil && f.failing:
+		f.log.Info(fmt.Sprintf("Writing sync status to %s recovered", f.path))
+	}
+	f.failing = err != nil
+}
@@ -0,0 +1,73 @@
+package sync
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/logging"
+)
+
+func TestStatusFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nacos-cli", "sync-status.json")
+	f := newStatusFile(path, logging.Discard())
+
+	start := time.Now()
+	f.event("demo", EventSynced, nil)
+	f.md5("demo", "abc")
+	f.dir("demo", "/opt/plugins/demo")
+	f.event("broken", EventError, errors.New("boom"))
+	f.beat()
+
+	status, err := ReadStatus(path)
+	if err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if status.PID != os.Getpid() || status.UpdatedAt.Before(start) {
+		t.Errorf("status = pid %d at %s, want this process after %s", status.PID, status.UpdatedAt, start)
+	}
+	demo := status.Skills["demo"]
+	if demo.Event != EventSynced || demo.MD5 != "abc" || demo.LastSynced == nil || demo.LastSynced.Before(start) {
+		t.Errorf("demo = %+v, want synced with md5 abc", demo)
+	}
+	if broken := status.Skills["broken"]; broken.Event != EventError || broken.LastSynced != nil {
+		t.Errorf("broken = %+v, want an error and never synced", broken)
+	}
+	if status.LastError == nil || status.LastError.Skill != "broken" || status.LastError.Message != "boom" {
+		t.Errorf("LastError = %+v, want boom from broken", status.LastError)
+	}
+
+	// A later sync keeps the MD5 and the last error
+	f.event("demo", EventUpToDate, nil)
+	f.beat()
+	status, err = ReadStatus(path)
+	if err != nil {
+		t.Fatalf("ReadStatus() error = %v", err)
+	}
+	if demo := status.Skills["demo"]; demo.Event != EventUpToDate || demo.MD5 != "abc" {
+		t.Errorf("demo = %+v, want up-to-date with md5 abc", demo)
+	}
+	if status.LastError == nil {
+		t.Error("LastError was cleared")
+	}
+
+	var out strings.Builder
+	status.Print(&out)
+	for _, want := range []string{"Last error:      broken: boom", "demo", "abc", "/opt/plugins/demo", "never"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("Print() = %q, want it to contain %q", out.String(), want)
+		}
+	}
+}
+
+func TestNilStatusFile(t *testing.T) {
+	var f *statusFile
+	f.event("demo", EventSynced, nil)
+	f.md5("demo", "abc")
+	f.dir("demo", "/opt/plugins/demo")
+	f.fetchFailed("demo", errors.New("boom"))
+	f.beat()
+}
@@ -0,0 +1,166 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/config"
+)
+
+// DefaultAliases ship with the CLI. Entries under "aliases:" in the config
+// file override them; an empty definition disables a default.
+var DefaultAliases = map[string]string{
+	"cl": "config-list",
+	"cg": "config-get",
+	"cs": "config-set",
+	"sl": "skill-list",
+	"sg": "skill-get",
+}
+
+// MergeAliases returns the default aliases overridden by the configured ones
+func MergeAliases(configured map[string]string) map[string]string {
+	merged := make(map[string]string, len(DefaultAliases)+len(configured))
+	for name, definition := range DefaultAliases {
+		merged[name] = definition
+	}
+	for name, definition := range configured {
+		if definition == "" {
+			delete(merged, name)
+		} else {
+			merged[name] = definition
+		}
+	}
+	return merged
+}
+
+// ExpandAliases replaces a leading alias in args with its definition. The
+// result is expanded again so aliases can build on each other, but an alias is
+// never expanded twice: "config-list: config-list --size 100" refers to the
+// real command, and a cycle stops at the first repeated name.
+func ExpandAliases(args []string, aliases map[string]string) ([]string, error) {
+	seen := make(map[string]bool)
+	for len(args) > 0 {
+		name := args[0]
+		definition, ok := aliases[name]
+		if !ok || seen[name] {
+			break
+		}
+		seen[name] = true
+
+		words, err := tokenize(definition)
+		if err != nil {
+			return nil, fmt.Errorf("alias %s: %w", name, err)
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("alias %s is empty", name)
+		}
+		args = append(words, args[1:]...)
+	}
+	return args, nil
+}
+
+// SetAliases sets the aliases configured in the config file at path. Aliases
+// added or removed with the alias command are saved back to that file; an
+// empty path keeps changes for this session only.
+func (t *Terminal) SetAliases(configured map[string]string, path string) {
+	t.aliases = MergeAliases(configured)
+	t.aliasFile = path
+}
+
+// alias lists, adds or removes aliases
+func (t *Terminal) alias(args []string) {
+	if len(args) == 0 || args[0] == "list" {
+		t.listAliases()
+		return
+	}
+
+	switch {
+	case args[0] == "add" && len(args) >= 3:
+		name := args[1]
+		if name == "" || strings.ContainsAny(name, " \t'\"\\") || strings.HasPrefix(name, "-") || strings.HasPrefix(name, "!") {
+			t.errorf("invalid alias name %q", name)
+			return
+		}
+		definition := joinWords(args[2:])
+		if err := t.saveAlias(name, definition); err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		t.aliases[name] = definition
+		t.printf("\033[32mAlias added:\033[0m %s = %s\n", name, definition)
+	case args[0] == "remove" && len(args) == 2:
+		name := args[1]
+		if _, ok := t.aliases[name]; !ok {
+			t.errorf("no alias named %q", name)
+			return
+		}
+		if err := t.saveAlias(name, ""); err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		delete(t.aliases, name)
+		t.printf("\033[32mAlias removed:\033[0m %s\n", name)
+	default:
+		t.printUsage("alias [list] | alias add <name> <command...> | alias remove <name>")
+	}
+}
+
+func (t *Terminal) listAliases() {
+	t.setRows(len(t.aliases))
+	if len(t.aliases) == 0 {
+		t.println("\033[90mNo aliases defined\033[0m")
+		return
+	}
+	names := make([]string, 0, len(t.aliases))
+	for name := range t.aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		source := ""
+		if DefaultAliases[name] == t.aliases[name] {
+			source = " \033[90m(default)\033[0m"
+		}
+		t.printf("\033[32m%-12s\033[0m %s%s\n", name, t.aliases[name], source)
+	}
+}
+
+// saveAlias writes an alias to the config file. An empty definition removes it;
+// for a default alias it is kept as an empty entry so the default stays disabled.
+func (t *Terminal) saveAlias(name, definition string) error {
+	if t.aliasFile == "" {
+		return nil
+	}
+	cfg := &config.Config{}
+	if _, err := os.Stat(t.aliasFile); err == nil {
+		if cfg, err = config.LoadConfig(t.aliasFile); err != nil {
+			return err
+		}
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = make(map[string]string)
+	}
+	if _, isDefault := DefaultAliases[name]; definition == "" && !isDefault {
+		delete(cfg.Aliases, name)
+	} else {
+		cfg.Aliases[name] = definition
+	}
+	return cfg.SaveConfig(t.aliasFile)
+}
+
+// joinWords joins args back into a command line, quoting words that tokenize
+// would otherwise split or unescape
+func joinWords(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		if arg != "" && !strings.ContainsAny(arg, " \t\n'\"\\$`") {
+			quoted[i] = arg
+			continue
+		}
+		replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`, "`", "\\`")
+		quoted[i] = `"` + replacer.Replace(arg) + `"`
+	}
+	return strings.Join(quoted, " ")
+}
@@ -0,0 +1,90 @@
+package terminal
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/config"
+)
+
+func TestExpandAliases(t *testing.T) {
+	aliases := MergeAliases(map[string]string{
+		"skills":      "config-list --group 'skill_*'",
+		"mine":        "skills --size 100",
+		"config-list": "config-list --page 1",
+		"a":           "b",
+		"b":           "a",
+		"cg":          "",
+	})
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"sl", "--name", "x"}, []string{"skill-list", "--name", "x"}},
+		{[]string{"skills"}, []string{"config-list", "--page", "1", "--group", "skill_*"}},
+		{[]string{"mine", "--page", "2"}, []string{"config-list", "--page", "1", "--group", "skill_*", "--size", "100", "--page", "2"}},
+		{[]string{"a"}, []string{"a"}},
+		{[]string{"cg", "app.yaml"}, []string{"cg", "app.yaml"}},
+		{[]string{"server"}, []string{"server"}},
+	}
+	for _, tt := range tests {
+		got, err := ExpandAliases(tt.args, aliases)
+		if err != nil {
+			t.Errorf("ExpandAliases(%q) error = %v", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ExpandAliases(%q) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+
+	if _, err := ExpandAliases([]string{"bad"}, map[string]string{"bad": "config-get 'oops"}); err == nil {
+		t.Error("ExpandAliases accepted an unterminated quote")
+	}
+}
+
+func TestJoinWordsRoundTrip(t *testing.T) {
+	words := []string{"skill-list", "--name", "my skill", `say "hi"`, "a\\b", ""}
+	got, err := tokenize(joinWords(words))
+	if err != nil {
+		t.Fatalf("tokenize() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, words) {
+		t.Errorf("round trip = %q, want %q", got, words)
+	}
+}
+
+func TestSaveAlias(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "default.conf")
+	if err := (&config.Config{Host: "127.0.0.1"}).SaveConfig(path); err != nil {
+		t.Fatal(err)
+	}
+
+	term := &Terminal{}
+	term.SetAliases(nil, path)
+	term.alias([]string{"add", "mine", "config-list", "--group", "skill_*"})
+	term.alias([]string{"remove", "cg"})
+	if term.result.failed() {
+		t.Fatal("alias command failed")
+	}
+
+	cfg, err := config.LoadConfig(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"mine": "config-list --group skill_*", "cg": ""}
+	if !reflect.DeepEqual(cfg.Aliases, want) {
+		t.Errorf("saved aliases = %q, want %q", cfg.Aliases, want)
+	}
+	if cfg.Host != "127.0.0.1" {
+		t.Errorf("saving aliases lost host: %q", cfg.Host)
+	}
+
+	term.alias([]string{"remove", "mine"})
+	cfg, _ = config.LoadConfig(path)
+	if _, ok := cfg.Aliases["mine"]; ok {
+		t.Error("removed alias is still in the config file")
+	}
+}
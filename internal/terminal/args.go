@@ -0,0 +1,160 @@
+package terminal
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/util"
+	"github.com/spf13/pflag"
+)
+
+// tokenize splits a command line into words using shell-like rules:
+//   - whitespace separates words
+//   - 'single quotes' keep everything literally
+//   - "double quotes" keep whitespace; \" \\ \$ and \` are unescaped inside them
+//   - a backslash outside quotes escapes the next character
+//
+// Quotes may appear in the middle of a word (--name="my skill" yields --name=my skill).
+func tokenize(input string) ([]string, error) {
+	var tokens []string
+	var cur strings.Builder
+	inWord := false
+
+	runes := []rune(input)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inWord {
+				tokens = append(tokens, cur.String())
+				cur.Reset()
+				inWord = false
+			}
+		case r == '\'':
+			inWord = true
+			end := indexRune(runes, i+1, '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote")
+			}
+			cur.WriteString(string(runes[i+1 : end]))
+			i = end
+		case r == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(runes); i++ {
+				c := runes[i]
+				if c == '"' {
+					closed = true
+					break
+				}
+				if c == '\\' && i+1 < len(runes) && strings.ContainsRune("\"\\$`", runes[i+1]) {
+					i++
+					c = runes[i]
+				}
+				cur.WriteRune(c)
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote")
+			}
+		case r == '\\':
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				cur.WriteRune(runes[i])
+			} else {
+				cur.WriteRune(r)
+			}
+		default:
+			inWord = true
+			cur.WriteRune(r)
+		}
+	}
+	if inWord {
+		tokens = append(tokens, cur.String())
+	}
+	return tokens, nil
+}
+
+func indexRune(runes []rune, from int, target rune) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == target {
+			return i
+		}
+	}
+	return -1
+}
+
+// flagSet is a pflag.FlagSet that remembers the terminal command it belongs to.
+type flagSet struct {
+	*pflag.FlagSet
+	command string
+	maxArgs int // most positional arguments accepted, or -1 for any number
+}
+
+// newFlagSet creates a flag set for a terminal command. Errors are returned
+// rather than printed so the terminal can render them in its own style.
+func newFlagSet(command string) *flagSet {
+	fs := pflag.NewFlagSet(command, pflag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.SetInterspersed(true)
+	return &flagSet{FlagSet: fs, command: command, maxArgs: -1}
+}
+
+// parseFlags parses args into fs and returns the positional arguments. Unknown
+// flags and more than fs.maxArgs positional arguments are errors. On error it
+// prints a message, marks the command as failed and returns ok=false.
+func (t *Terminal) parseFlags(fs *flagSet, args []string) (positional []string, ok bool) {
+	if err := fs.Parse(args); err != nil {
+		t.errorf("%v%s", err, fs.flagSuggestion(err))
+		t.printf("\033[90mRun '\033[0m%s --help\033[90m' for usage\033[0m\n", fs.command)
+		return nil, false
+	}
+	positional = fs.Args()
+	if fs.maxArgs >= 0 && len(positional) > fs.maxArgs {
+		t.errorf("unexpected argument %q", positional[fs.maxArgs])
+		t.printf("\033[90mRun '\033[0m%s --help\033[90m' for usage\033[0m\n", fs.command)
+		return nil, false
+	}
+	return positional, true
+}
+
+// flagSuggestion returns " (did you mean --name?)" when err is about an
+// unknown long flag that is a likely typo of a known one
+func (fs *flagSet) flagSuggestion(err error) string {
+	name, ok := strings.CutPrefix(err.Error(), "unknown flag: --")
+	if !ok {
+		return ""
+	}
+	var names []string
+	fs.VisitAll(func(f *pflag.Flag) {
+		names = append(names, f.Name)
+	})
+	if match := util.Closest(name, names); match != "" {
+		return didYouMean("--" + match)
+	}
+	return ""
+}
+
+// checkArgs fails the command if it got more than max arguments. It is used by
+// commands that take no flags.
+func (t *Terminal) checkArgs(args []string, max int) bool {
+	if len(args) <= max {
+		return true
+	}
+	t.errorf("unexpected argument %q", args[max])
+	return false
+}
+
+// hasHelpFlag reports whether --help or -h appears anywhere in args (before "--").
+func hasHelpFlag(args []string) bool {
+	for _, arg := range args {
+		if arg == "--" {
+			return false
+		}
+		if arg == "--help" || arg == "-h" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,152 @@
+package terminal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "plain words",
+			input: "skill-get a b",
+			want:  []string{"skill-get", "a", "b"},
+		},
+		{
+			name:  "double quoted value with spaces",
+			input: `skill-list --name "my skill"`,
+			want:  []string{"skill-list", "--name", "my skill"},
+		},
+		{
+			name:  "single quotes keep backslashes",
+			input: `config-get 'a\b' G`,
+			want:  []string{"config-get", `a\b`, "G"},
+		},
+		{
+			name:  "equals form with quotes",
+			input: `skill-list --name="my skill"`,
+			want:  []string{"skill-list", "--name=my skill"},
+		},
+		{
+			name:  "escaped quote inside double quotes",
+			input: `config-get "say \"hi\"" G`,
+			want:  []string{"config-get", `say "hi"`, "G"},
+		},
+		{
+			name:  "backslash escapes space outside quotes",
+			input: `skill-publish ./my\ skill`,
+			want:  []string{"skill-publish", "./my skill"},
+		},
+		{
+			name:  "empty quoted argument is kept",
+			input: `config-list --group ""`,
+			want:  []string{"config-list", "--group", ""},
+		},
+		{
+			name:    "unterminated double quote",
+			input:   `skill-get "oops`,
+			wantErr: true,
+		},
+		{
+			name:    "unterminated single quote",
+			input:   `skill-get 'oops`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenize(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("tokenize(%q) expected error, got %q", tt.input, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("tokenize(%q) unexpected error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tokenize(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFlags(t *testing.T) {
+	var name string
+	var page int
+
+	fs := newFlagSet("skill-list")
+	fs.StringVar(&name, "name", "", "")
+	fs.IntVar(&page, "page", 1, "")
+
+	term := &Terminal{}
+	positional, ok := term.parseFlags(fs, []string{"extra", "--name=my skill", "--page", "3"})
+	if !ok {
+		t.Fatal("parseFlags returned ok=false")
+	}
+	if name != "my skill" || page != 3 {
+		t.Errorf("got name=%q page=%d, want name=%q page=3", name, page, "my skill")
+	}
+	if !reflect.DeepEqual(positional, []string{"extra"}) {
+		t.Errorf("positional = %q, want [extra]", positional)
+	}
+
+	if _, ok := term.parseFlags(newFlagSet("skill-list"), []string{"--unknown"}); ok {
+		t.Error("parseFlags accepted an unknown flag")
+	}
+	if !term.result.failed() {
+		t.Error("parseFlags did not mark the command as failed")
+	}
+}
+
+func TestParseFlagsRejectsExtraInput(t *testing.T) {
+	var name string
+	newSkillList := func() *flagSet {
+		fs := newFlagSet("skill-list")
+		fs.StringVar(&name, "name", "", "")
+		fs.maxArgs = 0
+		return fs
+	}
+
+	term := &Terminal{}
+	if _, ok := term.parseFlags(newSkillList(), []string{"--nmae", "foo"}); ok {
+		t.Fatal("parseFlags accepted --nmae")
+	}
+	if want := "unknown flag: --nmae (did you mean --name?)"; term.result.err == nil || term.result.err.Error() != want {
+		t.Errorf("error = %v, want %q", term.result.err, want)
+	}
+
+	term = &Terminal{}
+	if _, ok := term.parseFlags(newSkillList(), []string{"--name", "foo", "extra"}); ok {
+		t.Error("parseFlags accepted an extra positional argument")
+	}
+
+	term = &Terminal{}
+	fs := newFlagSet("config-get")
+	fs.maxArgs = 2
+	if _, ok := term.parseFlags(fs, []string{"app.yaml", "DEFAULT_GROUP", "extra-arg"}); ok {
+		t.Error("parseFlags accepted a third argument for config-get")
+	}
+}
+
+func TestRunCommandRejectsExtraInput(t *testing.T) {
+	for _, line := range []string{"jobs extra", "help me", "cofig-get app.yaml", "stop 1 2"} {
+		term := &Terminal{aliases: MergeAliases(nil)}
+		term.runCommand(line)
+		if !term.result.failed() {
+			t.Errorf("%q was accepted", line)
+		}
+	}
+
+	term := &Terminal{aliases: MergeAliases(nil)}
+	if got := term.closestCommand("cofig-get"); got != "config-get" {
+		t.Errorf("closestCommand(cofig-get) = %q, want config-get", got)
+	}
+}
@@ -0,0 +1,231 @@
+package terminal
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// completionTTL is how long cached skill and config names are used before re-fetching.
+	completionTTL = 30 * time.Second
+	// completionWait bounds how long a Tab press waits for the first fetch.
+	completionWait = 300 * time.Millisecond
+	// completionPageSize is the number of items fetched for completion.
+	completionPageSize = 200
+)
+
+// completionEntry is a completable name; Group is only set for configs.
+type completionEntry struct {
+	Name  string
+	Group string
+}
+
+// completionCache holds the result of a remote list call for tab completion.
+// Refreshes run in the background so a slow or unreachable server never blocks
+// the prompt; on errors the previous entries (or none) are returned.
+type completionCache struct {
+	// fetch lists the entries of a namespace. The namespace is read with
+	// namespace (nil means "") before the fetch starts, so that a background
+	// fetch never reads the client's while 'ns' switches it.
+	fetch     func(namespace string) ([]completionEntry, error)
+	namespace func() string
+
+	mu         sync.Mutex
+	entries    []completionEntry
+	fetchedAt  time.Time
+	generation int           // bumped by invalidate; fetches started before are discarded
+	refreshing chan struct{} // closed when the in-flight refresh finishes
+}
+
+func newCompletionCache(namespace func() string, fetch func(namespace string) ([]completionEntry, error)) *completionCache {
+	return &completionCache{fetch: fetch, namespace: namespace}
+}
+
+// currentNamespace returns the namespace to fetch in
+func (c *completionCache) currentNamespace() string {
+	if c.namespace == nil {
+		return ""
+	}
+	return c.namespace()
+}
+
+// get returns the cached entries, starting a background refresh when they are stale.
+// If nothing has been fetched yet it waits up to completionWait for the first result.
+func (c *completionCache) get() []completionEntry {
+	c.mu.Lock()
+	if time.Since(c.fetchedAt) < completionTTL {
+		entries := c.entries
+		c.mu.Unlock()
+		return entries
+	}
+	done := c.startRefreshLocked()
+	empty := c.fetchedAt.IsZero()
+	c.mu.Unlock()
+
+	if empty {
+		select {
+		case <-done:
+		case <-time.After(completionWait):
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.entries
+}
+
+// refresh re-fetches synchronously and returns the number of entries.
+func (c *completionCache) refresh() (int, error) {
+	c.mu.Lock()
+	generation := c.generation
+	c.mu.Unlock()
+	entries, err := c.fetch(c.currentNamespace())
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if err != nil {
+		return len(c.entries), err
+	}
+	c.storeLocked(generation, entries)
+	return len(entries), nil
+}
+
+// invalidate marks the cache stale so the next lookup re-fetches, e.g. after
+// switching namespaces. A fetch still running is discarded when it finishes.
+func (c *completionCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fetchedAt = time.Time{}
+	c.generation++
+	c.refreshing = nil
+}
+
+// storeLocked keeps the entries of a fetch started in generation, unless the
+// cache was invalidated since
+func (c *completionCache) storeLocked(generation int, entries []completionEntry) {
+	if generation != c.generation {
+		return
+	}
+	c.entries = entries
+	c.fetchedAt = time.Now()
+}
+
+func (c *completionCache) startRefreshLocked() chan struct{} {
+	if c.refreshing != nil {
+		return c.refreshing
+	}
+	done := make(chan struct{})
+	c.refreshing = done
+	generation, namespace := c.generation, c.currentNamespace()
+	go func() {
+		entries, err := c.fetch(namespace)
+		c.mu.Lock()
+		if err == nil {
+			c.storeLocked(generation, entries)
+		}
+		if c.refreshing == done {
+			c.refreshing = nil
+		}
+		c.mu.Unlock()
+		close(done)
+	}()
+	return done
+}
+
+// fetchSkillCompletions lists skill names in namespace for completion.
+func (t *Terminal) fetchSkillCompletions(namespace string) ([]completionEntry, error) {
+	skills, _, err := t.skillService.ListSkillsIn(namespace, "", 1, completionPageSize)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]completionEntry, 0, len(skills))
+	for _, s := range skills {
+		entries = append(entries, completionEntry{Name: s.Name})
+	}
+	return entries, nil
+}
+
+// fetchConfigCompletions lists dataId/group pairs in namespace for completion.
+func (t *Terminal) fetchConfigCompletions(namespace string) ([]completionEntry, error) {
+	// An empty namespace would make ListConfigs read the client's
+	if namespace == "" {
+		namespace = "public"
+	}
+	resp, err := t.client.ListConfigs("", "", namespace, 1, completionPageSize)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]completionEntry, 0, len(resp.PageItems))
+	for _, config := range resp.PageItems {
+		groupName := config.GroupName
+		if groupName == "" {
+			groupName = config.Group
+		}
+		entries = append(entries, completionEntry{Name: config.DataID, Group: groupName})
+	}
+	return entries, nil
+}
+
+// completeSkillNames returns cached skill names.
+func (t *Terminal) completeSkillNames(line string) []string {
+	var names []string
+	for _, e := range t.skillCache.get() {
+		names = append(names, e.Name)
+	}
+	return uniqueSorted(names)
+}
+
+// completeDataIDs returns cached config dataIds.
+func (t *Terminal) completeDataIDs(line string) []string {
+	var names []string
+	for _, e := range t.configCache.get() {
+		names = append(names, e.Name)
+	}
+	return uniqueSorted(names)
+}
+
+// completeGroups returns the groups of the dataId typed as the first argument.
+func (t *Terminal) completeGroups(line string) []string {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return nil
+	}
+	dataID := fields[1]
+	var groups []string
+	for _, e := range t.configCache.get() {
+		if e.Name == dataID {
+			groups = append(groups, e.Group)
+		}
+	}
+	return uniqueSorted(groups)
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v == "" || seen[v] {
+			continue
+		}
+		seen[v] = true
+		result = append(result, v)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// refreshCache forces the completion caches to be re-fetched.
+func (t *Terminal) refreshCache() {
+	t.print("\033[90mRefreshing completion cache...\033[0m\r")
+	skills, skillErr := t.skillCache.refresh()
+	configs, configErr := t.configCache.refresh()
+	t.print("\033[K")
+	if skillErr != nil {
+		t.errorf("refresh skills: %v", skillErr)
+	}
+	if configErr != nil {
+		t.errorf("refresh configs: %v", configErr)
+	}
+	t.printf("\033[32mCache refreshed:\033[0m %d skills, %d configs\n", skills, configs)
+}
@@ -0,0 +1,121 @@
+package terminal
+
+import (
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCompletionCacheCachesWithinTTL(t *testing.T) {
+	var calls int32
+	c := newCompletionCache(nil, func(string) ([]completionEntry, error) {
+		atomic.AddInt32(&calls, 1)
+		return []completionEntry{{Name: "skill-a"}}, nil
+	})
+
+	for i := 0; i < 3; i++ {
+		if got := c.get(); len(got) != 1 || got[0].Name != "skill-a" {
+			t.Fatalf("get() = %v, want [skill-a]", got)
+		}
+	}
+	if n := atomic.LoadInt32(&calls); n != 1 {
+		t.Errorf("fetch called %d times, want 1", n)
+	}
+
+	c.invalidate()
+	c.get()
+	if n := atomic.LoadInt32(&calls); n != 2 {
+		t.Errorf("fetch called %d times after invalidate, want 2", n)
+	}
+}
+
+func TestCompletionCacheDoesNotBlockOnSlowServer(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	c := newCompletionCache(nil, func(string) ([]completionEntry, error) {
+		<-release
+		return nil, nil
+	})
+
+	start := time.Now()
+	if got := c.get(); len(got) != 0 {
+		t.Errorf("get() = %v, want no suggestions", got)
+	}
+	if elapsed := time.Since(start); elapsed > completionWait+time.Second {
+		t.Errorf("get() blocked for %v", elapsed)
+	}
+}
+
+func TestCompletionCacheKeepsEntriesOnError(t *testing.T) {
+	fail := false
+	c := newCompletionCache(nil, func(string) ([]completionEntry, error) {
+		if fail {
+			return nil, errors.New("server down")
+		}
+		return []completionEntry{{Name: "app.yaml", Group: "DEFAULT_GROUP"}}, nil
+	})
+
+	if _, err := c.refresh(); err != nil {
+		t.Fatalf("refresh() unexpected error: %v", err)
+	}
+	fail = true
+	n, err := c.refresh()
+	if err == nil {
+		t.Fatal("refresh() expected error")
+	}
+	if n != 1 || len(c.get()) != 1 {
+		t.Errorf("entries were dropped after a failed refresh")
+	}
+}
+
+func TestCompletionCacheDiscardsFetchBeforeInvalidate(t *testing.T) {
+	namespace := "old"
+	started := make(chan string, 2)
+	release := make(chan struct{})
+	c := newCompletionCache(func() string { return namespace }, func(ns string) ([]completionEntry, error) {
+		started <- ns
+		<-release
+		return []completionEntry{{Name: ns + ".yaml"}}, nil
+	})
+
+	c.mu.Lock()
+	old := c.startRefreshLocked()
+	c.mu.Unlock()
+	if ns := <-started; ns != "old" {
+		t.Fatalf("fetched namespace %q, want old", ns)
+	}
+	// 'ns new' while the fetch of the old namespace is running
+	namespace = "new"
+	c.invalidate()
+	close(release)
+	<-old
+	if got := c.get(); !reflect.DeepEqual(got, []completionEntry{{Name: "new.yaml"}}) {
+		t.Errorf("get() = %v, want the entries of the new namespace", got)
+	}
+	if ns := <-started; ns != "new" {
+		t.Errorf("fetched namespace %q, want new", ns)
+	}
+}
+
+func TestCompleteGroups(t *testing.T) {
+	term := &Terminal{}
+	term.configCache = newCompletionCache(nil, func(string) ([]completionEntry, error) {
+		return []completionEntry{
+			{Name: "app.yaml", Group: "PROD"},
+			{Name: "app.yaml", Group: "DEFAULT_GROUP"},
+			{Name: "db.yaml", Group: "DEFAULT_GROUP"},
+		}, nil
+	})
+
+	if got, want := term.completeDataIDs("config-get "), []string{"app.yaml", "db.yaml"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completeDataIDs() = %v, want %v", got, want)
+	}
+	if got, want := term.completeGroups("config-get app.yaml "), []string{"DEFAULT_GROUP", "PROD"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("completeGroups() = %v, want %v", got, want)
+	}
+	if got := term.completeGroups("config-get"); got != nil {
+		t.Errorf("completeGroups() without dataId = %v, want nil", got)
+	}
+}
@@ -0,0 +1,201 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/nacos-group/nacos-cli/internal/config"
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+const (
+	// historyLimit is the number of entries kept in the history file
+	historyLimit = 500
+	// historyShowDefault is the number of entries listed by a bare "history"
+	historyShowDefault = 20
+)
+
+// secretFlags mark command lines that are never written to history
+var secretFlags = []string{"--password", "--secret-key", "--token", "--access-key"}
+
+// history is the persistent list of commands entered in the terminal
+type history struct {
+	path    string
+	entries []string
+}
+
+// defaultHistoryFile returns ~/.nacos-cli/history
+func defaultHistoryFile() (string, error) {
+	dir, err := config.GetConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history"), nil
+}
+
+// loadHistory reads the history file at path, creating its directory with
+// 0700 so other users on the host cannot read it. An empty path keeps history
+// in memory only.
+func loadHistory(path string) (*history, error) {
+	h := &history{}
+	if path == "" {
+		return h, nil
+	}
+	path, err := util.ExpandTilde(path)
+	if err != nil {
+		return h, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return h, fmt.Errorf("create history directory: %w", err)
+	}
+	h.path = path
+
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			h.entries = append(h.entries, line)
+		}
+	}
+	if len(h.entries) > historyLimit {
+		h.entries = h.entries[len(h.entries)-historyLimit:]
+		return h, h.rewrite()
+	}
+	return h, scanner.Err()
+}
+
+// add appends line to the history unless it repeats the previous entry or
+// contains a secret. It reports whether the line was recorded.
+func (h *history) add(line string) bool {
+	if isSecretLine(line) {
+		return false
+	}
+	if n := len(h.entries); n > 0 && h.entries[n-1] == line {
+		return false
+	}
+	h.entries = append(h.entries, line)
+	if h.path == "" {
+		return true
+	}
+	if len(h.entries) > historyLimit {
+		h.entries = h.entries[len(h.entries)-historyLimit:]
+		h.rewrite()
+		return true
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return true
+	}
+	defer f.Close()
+	fmt.Fprintln(f, line)
+	return true
+}
+
+// get returns entry n (1-based)
+func (h *history) get(n int) (string, bool) {
+	if n < 1 || n > len(h.entries) {
+		return "", false
+	}
+	return h.entries[n-1], true
+}
+
+// clear removes all entries, including the history file contents
+func (h *history) clear() error {
+	h.entries = nil
+	if h.path == "" {
+		return nil
+	}
+	return h.rewrite()
+}
+
+func (h *history) rewrite() error {
+	data := ""
+	if len(h.entries) > 0 {
+		data = strings.Join(h.entries, "\n") + "\n"
+	}
+	return os.WriteFile(h.path, []byte(data), 0600)
+}
+
+// isSecretLine reports whether line passes a credential on the command line
+func isSecretLine(line string) bool {
+	for _, word := range strings.Fields(line) {
+		if word == "-p" {
+			return true
+		}
+		for _, flag := range secretFlags {
+			if word == flag || strings.HasPrefix(word, flag+"=") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// expandHistory replaces a "!N" line with history entry N
+func (t *Terminal) expandHistory(line string) (string, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(line, "!"))
+	if err != nil {
+		return "", fmt.Errorf("invalid history reference %q (use !N)", line)
+	}
+	entry, ok := t.hist.get(n)
+	if !ok {
+		return "", fmt.Errorf("%s: event not found", line)
+	}
+	return entry, nil
+}
+
+// recordHistory saves line to the persistent history and readline's in-memory list
+func (t *Terminal) recordHistory(line string) {
+	if t.hist.add(line) && t.rl != nil {
+		t.rl.SaveHistory(line)
+	}
+}
+
+// showHistory lists recent entries or clears the history
+func (t *Terminal) showHistory(args []string) {
+	if len(args) == 1 && args[0] == "clear" {
+		if err := t.hist.clear(); err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		if t.rl != nil {
+			t.rl.ResetHistory()
+		}
+		t.println("\033[32mHistory cleared\033[0m")
+		return
+	}
+
+	count := historyShowDefault
+	if len(args) == 1 {
+		n, err := strconv.Atoi(args[0])
+		if err != nil || n < 1 {
+			t.printUsage("history [count] | history clear")
+			return
+		}
+		count = n
+	} else if len(args) > 1 {
+		t.printUsage("history [count] | history clear")
+		return
+	}
+
+	start := len(t.hist.entries) - count
+	if start < 0 {
+		start = 0
+	}
+	t.setRows(len(t.hist.entries) - start)
+	for i := start; i < len(t.hist.entries); i++ {
+		t.printf("\033[90m%5d\033[0m  %s\n", i+1, t.hist.entries[i])
+	}
+}
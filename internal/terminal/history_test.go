@@ -0,0 +1,111 @@
+package terminal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestIsSecretLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want bool
+	}{
+		{"config-get app.yaml", false},
+		{"profile add dev --password secret", true},
+		{"login --password=secret", true},
+		{"profile add dev -p secret", true},
+		{"set --secret-key abc", true},
+		{"config-get password-notes", false},
+		{"config-set --password-hint x", false},
+	}
+	for _, tt := range tests {
+		if got := isSecretLine(tt.line); got != tt.want {
+			t.Errorf("isSecretLine(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestHistoryPersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "history")
+
+	h, err := loadHistory(path)
+	if err != nil {
+		t.Fatalf("loadHistory() error = %v", err)
+	}
+	info, err := os.Stat(filepath.Dir(path))
+	if err != nil {
+		t.Fatalf("history directory not created: %v", err)
+	}
+	// Windows has no Unix permissions to check
+	if perm := info.Mode().Perm(); runtime.GOOS != "windows" && perm != 0700 {
+		t.Errorf("history directory mode = %o, want 700", perm)
+	}
+
+	h.add("skill-list")
+	h.add("skill-list")
+	h.add("login --password secret")
+	h.add("config-get app.yaml")
+
+	h, err = loadHistory(path)
+	if err != nil {
+		t.Fatalf("loadHistory() error = %v", err)
+	}
+	want := []string{"skill-list", "config-get app.yaml"}
+	if strings.Join(h.entries, "\n") != strings.Join(want, "\n") {
+		t.Errorf("entries = %q, want %q", h.entries, want)
+	}
+
+	if err := h.clear(); err != nil {
+		t.Fatalf("clear() error = %v", err)
+	}
+	h, _ = loadHistory(path)
+	if len(h.entries) != 0 {
+		t.Errorf("entries after clear = %q, want none", h.entries)
+	}
+}
+
+func TestHistoryLimit(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history")
+	h, _ := loadHistory(path)
+	for i := 0; i < historyLimit+10; i++ {
+		h.add(fmt.Sprintf("config-get %d", i))
+	}
+
+	h, _ = loadHistory(path)
+	if len(h.entries) != historyLimit {
+		t.Fatalf("len(entries) = %d, want %d", len(h.entries), historyLimit)
+	}
+	if h.entries[0] != "config-get 10" {
+		t.Errorf("oldest entry = %q, want %q", h.entries[0], "config-get 10")
+	}
+}
+
+func TestExpandHistory(t *testing.T) {
+	term := &Terminal{hist: &history{entries: []string{"skill-list", "config-get app.yaml"}}}
+
+	tests := []struct {
+		line    string
+		want    string
+		wantErr bool
+	}{
+		{"!1", "skill-list", false},
+		{"!2", "config-get app.yaml", false},
+		{"!3", "", true},
+		{"!0", "", true},
+		{"!abc", "", true},
+	}
+	for _, tt := range tests {
+		got, err := term.expandHistory(tt.line)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("expandHistory(%q) error = %v, wantErr %v", tt.line, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("expandHistory(%q) = %q, want %q", tt.line, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,494 @@
+package terminal
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/listener"
+	"github.com/nacos-group/nacos-cli/internal/logging"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+const (
+	// jobLogLines is the number of output lines kept per background job
+	jobLogLines = 200
+	// jobStopTimeout bounds how long stop and quit wait for a job to finish
+	jobStopTimeout = 5 * time.Second
+)
+
+// ringBuffer keeps the most recent lines written by a background job
+type ringBuffer struct {
+	lines []string
+	start int
+	count int
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{lines: make([]string, size)}
+}
+
+func (r *ringBuffer) add(line string) {
+	idx := (r.start + r.count) % len(r.lines)
+	r.lines[idx] = line
+	if r.count < len(r.lines) {
+		r.count++
+	} else {
+		r.start = (r.start + 1) % len(r.lines)
+	}
+}
+
+// last returns up to n of the most recent lines, oldest first
+func (r *ringBuffer) last(n int) []string {
+	if n <= 0 || n > r.count {
+		n = r.count
+	}
+	result := make([]string, 0, n)
+	for i := r.count - n; i < r.count; i++ {
+		result = append(result, r.lines[(r.start+i)%len(r.lines)])
+	}
+	return result
+}
+
+// job is a long-running command, such as skill-sync, running in the background
+type job struct {
+	id      int
+	command string
+	started time.Time
+	cancel  context.CancelFunc
+	done    chan struct{}
+
+	mu        sync.Mutex
+	logs      *ringBuffer
+	lastEvent time.Time
+	err       error
+}
+
+// logf records a timestamped line in the job's log
+func (j *job) logf(format string, args ...interface{}) {
+	now := time.Now()
+	line := fmt.Sprintf("[%s] %s", now.Format("15:04:05"), fmt.Sprintf(format, args...))
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.logs.add(line)
+	j.lastEvent = now
+}
+
+func (j *job) running() bool {
+	select {
+	case <-j.done:
+		return false
+	default:
+		return true
+	}
+}
+
+func (j *job) status() string {
+	if j.running() {
+		return "running"
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.err != nil {
+		return "failed"
+	}
+	return "stopped"
+}
+
+// stop cancels the job and waits up to timeout for it to finish
+func (j *job) stop(timeout time.Duration) bool {
+	j.cancel()
+	select {
+	case <-j.done:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+// jobManager tracks background jobs started from the terminal
+type jobManager struct {
+	mu     sync.Mutex
+	nextID int
+	jobs   []*job
+}
+
+// start runs fn in a goroutine as a new job; fn must return once ctx is
+// cancelled. Messages logged to log go to the job's log.
+func (m *jobManager) start(command string, fn func(ctx context.Context, log *slog.Logger) error) *job {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	m.mu.Lock()
+	m.nextID++
+	j := &job{
+		id:      m.nextID,
+		command: command,
+		started: time.Now(),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+		logs:    newRingBuffer(jobLogLines),
+	}
+	m.jobs = append(m.jobs, j)
+	m.mu.Unlock()
+
+	go func() {
+		defer close(j.done)
+		err := fn(ctx, logging.Printf(j.logf))
+		if err != nil {
+			j.logf("Error: %v", err)
+		}
+		j.mu.Lock()
+		j.err = err
+		j.mu.Unlock()
+	}()
+	return j
+}
+
+func (m *jobManager) get(id int) *job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, j := range m.jobs {
+		if j.id == id {
+			return j
+		}
+	}
+	return nil
+}
+
+func (m *jobManager) list() []*job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*job(nil), m.jobs...)
+}
+
+func (m *jobManager) running() []*job {
+	var result []*job
+	for _, j := range m.list() {
+		if j.running() {
+			result = append(result, j)
+		}
+	}
+	return result
+}
+
+// stopAll cancels every running job and waits for them to finish
+func (m *jobManager) stopAll(timeout time.Duration) {
+	running := m.running()
+	for _, j := range running {
+		j.cancel()
+	}
+	deadline := time.After(timeout)
+	for _, j := range running {
+		select {
+		case <-j.done:
+		case <-deadline:
+			return
+		}
+	}
+}
+
+// syncSkill starts skill-sync as a background job
+func (t *Terminal) syncSkill(args []string) {
+	var all, push, forceRemote, forceSync, listenEarly, preserveExec, verboseDiff bool
+	var outputDir, logFile, logFormat, onChange, onError, catalogRef, mapFile, bandwidth string
+	var pollTimeout, pollInterval, hookTimeout, debounce, jitter time.Duration
+	var batchSize, initWorkers int
+	var pinValues, mapValues, include, exclude []string
+
+	fs := newFlagSet("skill-sync")
+	fs.BoolVar(&all, "all", false, "Sync all skills in the namespace")
+	fs.StringVarP(&outputDir, "output", "o", "", "Output directory")
+	fs.DurationVar(&pollTimeout, "poll-timeout", t.pollTimeout, "How long each poll for changes may take")
+	fs.DurationVar(&pollInterval, "poll-interval", listener.PollInterval, "How often each watched config is fetched")
+	fs.BoolVar(&push, "push", false, "Upload local skill directories whenever their files change")
+	fs.BoolVar(&forceRemote, "force-remote", false, "Overwrite local edits with remote changes")
+	fs.BoolVar(&forceSync, "force-initial-sync", false, "Download every skill at startup, even those unchanged since the last run")
+	fs.IntVar(&initWorkers, "init-concurrency", skillsync.DefaultInitConcurrency, "How many skills the first sync downloads at once")
+	fs.StringVar(&bandwidth, "max-bandwidth", "0", "Limit all downloads together to this many bytes per second, e.g. 2MB")
+	fs.DurationVar(&jitter, "startup-jitter", 0, "Wait a random time up to this long before the first sync")
+	fs.DurationVar(&debounce, "debounce", skillsync.DefaultDebounce, "Wait this long after a skill changes for further changes, then sync it once")
+	fs.BoolVar(&listenEarly, "listen-early", false, "Start watching for changes before the first sync has finished downloading")
+	fs.BoolVar(&preserveExec, "preserve-exec", true, "Make scripts executable")
+	fs.BoolVar(&verboseDiff, "verbose-diff", false, "Log a unified diff of each changed text file")
+	fs.StringArrayVar(&pinValues, "pin", nil, "Hold a skill at a version, as skill=version (repeatable)")
+	fs.StringArrayVar(&mapValues, "map", nil, "Sync a skill into another directory than --output, as skill=/abs/dir (repeatable)")
+	fs.StringVar(&mapFile, "map-file", "", "YAML file mapping skill names to the absolute directory each is synced into")
+	fs.StringArrayVar(&include, "include", nil, "With --all or --catalog, sync only skills whose name matches this glob (repeatable)")
+	fs.StringArrayVar(&exclude, "exclude", nil, "With --all or --catalog, skip skills whose name matches this glob (repeatable)")
+	fs.StringVar(&catalogRef, "catalog", "", "Sync the skills listed in this config, as dataId:group, following its changes")
+	fs.StringVar(&logFile, "log-file", "", "Also write structured log entries to this file")
+	fs.StringVar(&logFormat, "log-format", logging.FormatText, "Format of --log-file: text or json")
+	fs.StringVar(&onChange, "on-change", t.syncHooks.OnChange, "Command to run after a skill is updated or deleted")
+	fs.StringVar(&onError, "on-error", t.syncHooks.OnError, "Command to run after a skill fails to sync")
+	fs.DurationVar(&hookTimeout, "hook-timeout", skillsync.DefaultHookTimeout, "Kill a hook that runs longer than this")
+	fs.IntVar(&batchSize, "batch-size", listener.DefaultBatchSize, "How many configs each concurrent poller watches")
+	skillNames, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	if _, err := logging.NewHandler(io.Discard, logFormat); err != nil {
+		t.errorf("--log-format: %v", err)
+		return
+	}
+	logFile, err := util.ExpandTilde(logFile)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	if push {
+		pullFlags := fs.Changed("output") || fs.Changed("poll-timeout") || forceRemote || forceSync || fs.Changed("on-change") || fs.Changed("on-error") || fs.Changed("hook-timeout") || fs.Changed("batch-size") || fs.Changed("poll-interval") || fs.Changed("init-concurrency") || fs.Changed("max-bandwidth") || fs.Changed("startup-jitter") || listenEarly || fs.Changed("preserve-exec") || verboseDiff || len(pinValues) > 0 || len(mapValues) > 0 || mapFile != "" || len(include) > 0 || len(exclude) > 0 || catalogRef != "" || fs.Changed("debounce")
+		t.pushSkills(args, skillNames, all, pullFlags, logFile, logFormat)
+		return
+	}
+	if hookTimeout <= 0 {
+		t.errorf("--hook-timeout must be positive")
+		return
+	}
+	if batchSize <= 0 {
+		t.errorf("--batch-size must be positive")
+		return
+	}
+	if initWorkers <= 0 {
+		t.errorf("--init-concurrency must be positive")
+		return
+	}
+	if debounce < 0 {
+		t.errorf("--debounce must not be negative")
+		return
+	}
+	if jitter < 0 {
+		t.errorf("--startup-jitter must not be negative")
+		return
+	}
+	maxBandwidth, err := util.ParseSize(bandwidth)
+	if err != nil {
+		t.errorf("--max-bandwidth: %v", err)
+		return
+	}
+	pins, err := skillsync.ParsePins(pinValues)
+	if err != nil {
+		t.errorf("--pin: %v", err)
+		return
+	}
+	if mapFile != "" {
+		if mapFile, err = t.resolvePath(mapFile); err != nil {
+			t.errorf("resolve path: %v", err)
+			return
+		}
+	}
+	dirs, err := skillsync.LoadDirMap(mapFile, mapValues)
+	if err == nil {
+		err = dirs.Validate()
+	}
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	filter, err := skillsync.NewSkillFilter(include, exclude)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	if !filter.Empty() && !all && catalogRef == "" {
+		t.errorf("--include and --exclude can only be used with --all or --catalog")
+		return
+	}
+	var catalog skillsync.Catalog
+	if catalogRef != "" {
+		if len(skillNames) > 0 || all {
+			t.errorf("--catalog cannot be used with skill names or --all")
+			return
+		}
+		if catalog, err = skillsync.ParseCatalogRef(catalogRef); err != nil {
+			t.errorf("%v", err)
+			return
+		}
+	}
+	if pollInterval < time.Second {
+		t.errorf("--poll-interval must be at least 1s")
+		return
+	}
+	if fs.Changed("poll-timeout") && pollTimeout < time.Second {
+		t.errorf("--poll-timeout must be at least 1s")
+		return
+	}
+	if len(skillNames) == 0 && !all && catalogRef == "" {
+		t.printUsage("skill-sync <skillName> [skillName2...] or skill-sync --all")
+		return
+	}
+
+	outputDir, err = t.resolveSkillsDir(outputDir)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+
+	command := strings.TrimSpace("skill-sync " + strings.Join(args, " "))
+	j := t.jobs.start(command, func(ctx context.Context, log *slog.Logger) error {
+		log, closer, err := logging.Open(log.Handler(), logFile, logFormat)
+		if err != nil {
+			return err
+		}
+		defer closer.Close()
+
+		syncer := skillsync.NewSkillSyncer(t.client, outputDir, log)
+		syncer.SetPollTimeout(pollTimeout)
+		syncer.SetPollInterval(pollInterval)
+		syncer.SetBatchSize(batchSize)
+		syncer.SetForceRemote(forceRemote)
+		syncer.SetForceInitialSync(forceSync)
+		syncer.SetInitConcurrency(initWorkers)
+		syncer.SetMaxBandwidth(maxBandwidth)
+		syncer.SetStartupJitter(jitter)
+		syncer.SetListenEarly(listenEarly)
+		syncer.SetPreserveExec(preserveExec)
+		syncer.SetVerboseDiff(verboseDiff)
+		syncer.SetDebounce(debounce)
+		syncer.SetPins(pins)
+		syncer.SetDirMap(dirs)
+		syncer.SetFilter(filter)
+		syncer.SetHooks(skillsync.Hooks{OnChange: onChange, OnError: onError, Timeout: hookTimeout})
+		names := skillNames
+		switch {
+		case all:
+			var err error
+			if names, err = syncer.AllSkillNames(); err != nil {
+				return err
+			}
+		case catalogRef != "":
+			syncer.SetCatalog(catalog)
+			var err error
+			if names, err = syncer.CatalogSkillNames(); err != nil {
+				return err
+			}
+		}
+		return syncer.Run(ctx, names)
+	})
+
+	t.printf("\033[32mStarted job %d:\033[0m %s\n", j.id, command)
+	t.println("\033[90mUse '\033[0mjobs\033[90m', '\033[0mlogs <id>\033[90m' and '\033[0mstop <id>\033[90m' to manage it\033[0m")
+}
+
+// pushSkills starts skill-sync --push as a background job
+func (t *Terminal) pushSkills(args, paths []string, all, pullFlags bool, logFile, logFormat string) {
+	if pullFlags {
+		t.errorf("only --all, --log-file and --log-format can be used with --push")
+		return
+	}
+	if len(paths) == 0 {
+		t.printUsage("skill-sync --push <skillDir> [skillDir2...] or skill-sync --push --all <folder>")
+		return
+	}
+	dirs, err := skillsync.PushDirs(paths, all)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+
+	command := "skill-sync " + strings.Join(args, " ")
+	j := t.jobs.start(command, func(ctx context.Context, log *slog.Logger) error {
+		log, closer, err := logging.Open(log.Handler(), logFile, logFormat)
+		if err != nil {
+			return err
+		}
+		defer closer.Close()
+		return skillsync.NewSkillPusher(t.client, log).Run(ctx, dirs)
+	})
+	t.printf("\033[32mStarted job %d:\033[0m %s\n", j.id, command)
+	t.println("\033[90mUse '\033[0mjobs\033[90m', '\033[0mlogs <id>\033[90m' and '\033[0mstop <id>\033[90m' to manage it\033[0m")
+}
+
+// listJobs shows background jobs and when each last reported progress
+func (t *Terminal) listJobs() {
+	jobs := t.jobs.list()
+	t.setRows(len(jobs))
+	if len(jobs) == 0 {
+		t.println("\033[90mNo background jobs\033[0m")
+		return
+	}
+	t.printf("\033[90m%-4s %-9s %-12s %s\033[0m\n", "ID", "STATUS", "LAST EVENT", "COMMAND")
+	for _, j := range jobs {
+		j.mu.Lock()
+		lastEvent := "-"
+		if !j.lastEvent.IsZero() {
+			lastEvent = j.lastEvent.Format("15:04:05")
+		}
+		j.mu.Unlock()
+		t.printf("%-4d %-9s %-12s %s\n", j.id, j.status(), lastEvent, j.command)
+	}
+}
+
+// showJobLogs prints the most recent output of a job
+func (t *Terminal) showJobLogs(args []string) {
+	var lines int
+
+	fs := newFlagSet("logs")
+	fs.maxArgs = 1
+	fs.IntVarP(&lines, "lines", "n", 20, "Number of lines to show")
+	positional, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	j := t.lookupJob(positional, "logs <jobId> [-n lines]")
+	if j == nil {
+		return
+	}
+
+	j.mu.Lock()
+	output := j.logs.last(lines)
+	j.mu.Unlock()
+	if len(output) == 0 {
+		t.println("\033[90mNo output yet\033[0m")
+		return
+	}
+	for _, line := range output {
+		t.println(line)
+	}
+}
+
+// stopJob cancels a background job
+func (t *Terminal) stopJob(args []string) {
+	fs := newFlagSet("stop")
+	fs.maxArgs = 1
+	positional, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	j := t.lookupJob(positional, "stop <jobId>")
+	if j == nil {
+		return
+	}
+	if !j.running() {
+		t.printf("\033[90mJob %d is not running\033[0m\n", j.id)
+		return
+	}
+	if j.stop(jobStopTimeout) {
+		t.printf("\033[32mStopped job %d\033[0m\n", j.id)
+	} else {
+		t.printf("\033[33mJob %d is still shutting down\033[0m\n", j.id)
+	}
+}
+
+// lookupJob resolves the single job ID argument, printing usage or an error on failure
+func (t *Terminal) lookupJob(args []string, usage string) *job {
+	if len(args) != 1 {
+		t.printUsage(usage)
+		return nil
+	}
+	id, err := strconv.Atoi(args[0])
+	if err != nil {
+		t.errorf("invalid job ID %q", args[0])
+		return nil
+	}
+	j := t.jobs.get(id)
+	if j == nil {
+		t.errorf("no job with ID %d", id)
+	}
+	return j
+}
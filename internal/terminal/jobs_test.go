@@ -0,0 +1,95 @@
+package terminal
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestRingBuffer(t *testing.T) {
+	r := newRingBuffer(3)
+	if got := r.last(10); len(got) != 0 {
+		t.Fatalf("empty buffer last() = %v", got)
+	}
+	for _, line := range []string{"a", "b", "c", "d", "e"} {
+		r.add(line)
+	}
+	if got, want := r.last(0), []string{"c", "d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("last(0) = %v, want %v", got, want)
+	}
+	if got, want := r.last(2), []string{"d", "e"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("last(2) = %v, want %v", got, want)
+	}
+}
+
+func TestJobManagerStartAndStop(t *testing.T) {
+	var m jobManager
+	j := m.start("skill-sync demo", func(ctx context.Context, log *slog.Logger) error {
+		log.Info("started")
+		<-ctx.Done()
+		return nil
+	})
+
+	if got := m.get(j.id); got != j {
+		t.Fatalf("get(%d) = %v, want job", j.id, got)
+	}
+	if len(m.running()) != 1 {
+		t.Fatalf("running() = %d jobs, want 1", len(m.running()))
+	}
+	if !j.stop(time.Second) {
+		t.Fatal("job did not stop after cancel")
+	}
+	if j.status() != "stopped" || len(m.running()) != 0 {
+		t.Errorf("status = %q, running = %d; want stopped, 0", j.status(), len(m.running()))
+	}
+	if logs := j.logs.last(0); len(logs) != 1 {
+		t.Errorf("logs = %v, want one line", logs)
+	}
+}
+
+func TestJobManagerRecordsFailure(t *testing.T) {
+	var m jobManager
+	j := m.start("skill-sync broken", func(ctx context.Context, log *slog.Logger) error {
+		return errors.New("boom")
+	})
+	<-j.done
+	if j.status() != "failed" {
+		t.Errorf("status = %q, want failed", j.status())
+	}
+	if m.get(j.id+1) != nil {
+		t.Error("get() returned a job for an unknown ID")
+	}
+}
+
+func TestExitWarning(t *testing.T) {
+	term := &Terminal{running: true}
+	if w := term.exitWarning(); w != "" {
+		t.Errorf("exitWarning() with nothing pending = %q, want empty", w)
+	}
+
+	term.pendingInput = "config-set input"
+	if want := "config-set input not finished — discard it and quit?"; term.exitWarning() != want {
+		t.Errorf("exitWarning() = %q, want %q", term.exitWarning(), want)
+	}
+
+	term.pendingInput = ""
+	for i := 0; i < 2; i++ {
+		term.jobs.start("skill-sync demo", func(ctx context.Context, log *slog.Logger) error {
+			<-ctx.Done()
+			return nil
+		})
+	}
+	defer term.jobs.stopAll(time.Second)
+	if want := "2 sync jobs running — stop them and quit?"; term.exitWarning() != want {
+		t.Errorf("exitWarning() = %q, want %q", term.exitWarning(), want)
+	}
+
+	// Scripts cannot be asked, and --force never asks
+	term.quit([]string{"--force"})
+	if term.running || term.result.failed() {
+		t.Errorf("quit --force: running = %v, failed = %v", term.running, term.result.failed())
+	}
+}
@@ -0,0 +1,139 @@
+package terminal
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/chzyer/readline"
+)
+
+// pickerPageSize is how many items the picker numbers at once; the rest are
+// reached by typing a filter
+const pickerPageSize = 30
+
+// pickItem is one choice of the picker
+type pickItem struct {
+	Label  string // what is shown and filtered on
+	Detail string // shown in gray after the label, and filtered on too
+	Value  string // what choosing the item returns
+}
+
+// picker narrows a list of items by typed filters until one is chosen by
+// its number. It only reads lines, so it works the same on a dumb terminal.
+type picker struct {
+	items  []pickItem
+	shown  []pickItem // the items matching filter, numbered from 1 as listed
+	filter string
+}
+
+func newPicker(items []pickItem) *picker {
+	return &picker{items: items, shown: items}
+}
+
+// answer handles a line typed at the picker: a number chooses the item
+// listed with it; an empty line chooses the only item listed or clears the
+// filter; other text filters the items. It returns the chosen item, or
+// ok false with the list changed or an error about the answer.
+func (p *picker) answer(line string) (item pickItem, ok bool, err error) {
+	line = strings.TrimSpace(line)
+	if n, convErr := strconv.Atoi(line); convErr == nil {
+		if n < 1 || n > min(len(p.shown), pickerPageSize) {
+			return pickItem{}, false, fmt.Errorf("no item %d; choose 1-%d", n, min(len(p.shown), pickerPageSize))
+		}
+		return p.shown[n-1], true, nil
+	}
+	if line == "" {
+		if len(p.shown) == 1 {
+			return p.shown[0], true, nil
+		}
+		p.filter, p.shown = "", p.items
+		return pickItem{}, false, nil
+	}
+
+	var shown []pickItem
+	for _, item := range p.items {
+		if fuzzyMatch(line, item.Label+" "+item.Detail) {
+			shown = append(shown, item)
+		}
+	}
+	if len(shown) == 0 {
+		return pickItem{}, false, fmt.Errorf("nothing matches %q", line)
+	}
+	p.filter, p.shown = line, shown
+	return pickItem{}, false, nil
+}
+
+// print lists the items matching the filter, numbered
+func (p *picker) print(w io.Writer) {
+	if p.filter != "" {
+		fmt.Fprintf(w, "\033[90mMatching %q: %d of %d\033[0m\n", p.filter, len(p.shown), len(p.items))
+	}
+	for i, item := range p.shown[:min(len(p.shown), pickerPageSize)] {
+		fmt.Fprintf(w, "\033[90m%3d.\033[0m \033[32m%s\033[0m", i+1, item.Label)
+		if item.Detail != "" {
+			fmt.Fprintf(w, " \033[90m%s\033[0m", item.Detail)
+		}
+		fmt.Fprintln(w)
+	}
+	if more := len(p.shown) - pickerPageSize; more > 0 {
+		fmt.Fprintf(w, "\033[90m... and %d more; type part of a name to narrow the list\033[0m\n", more)
+	}
+}
+
+// fuzzyMatch reports whether the characters of pattern appear in s in order,
+// ignoring case, so that "prd" matches "prod" and "appyml" "app.yaml"
+func fuzzyMatch(pattern, s string) bool {
+	s = strings.ToLower(s)
+	for _, r := range strings.ToLower(pattern) {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
+
+// errPickCancelled is returned by pick when the user cancels with Ctrl+C
+var errPickCancelled = errors.New("cancelled")
+
+// pick lets the user choose one of items by number, typing text to filter
+// the list first. Ctrl+C or Ctrl+D cancels and returns errPickCancelled. It
+// needs the line editor, so it fails in script mode and with redirection.
+func (t *Terminal) pick(title string, items []pickItem) (pickItem, error) {
+	if t.rl == nil || t.redirected != nil {
+		return pickItem{}, errors.New("--pick needs an interactive terminal")
+	}
+	if len(items) == 0 {
+		return pickItem{}, errors.New("nothing to pick from")
+	}
+	t.pendingInput = "the selection"
+	defer func() { t.pendingInput = "" }()
+
+	p := newPicker(items)
+	t.printf("\n\033[1;36m%s\033[0m\n", title)
+	for {
+		p.print(t.stdout())
+		t.rl.SetPrompt("\033[33mNumber, or text to filter (Ctrl+C to cancel): \033[0m")
+		line, err := t.rl.Readline()
+		t.rl.SetPrompt(t.getPrompt())
+		if err == readline.ErrInterrupt || err == io.EOF {
+			t.println("\033[33mCancelled\033[0m")
+			return pickItem{}, errPickCancelled
+		}
+		if err != nil {
+			return pickItem{}, err
+		}
+		item, ok, err := p.answer(line)
+		if err != nil {
+			t.printf("\033[31m%s\033[0m\n", err)
+			continue
+		}
+		if ok {
+			return item, nil
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package terminal
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFuzzyMatch(t *testing.T) {
+	tests := []struct {
+		pattern, s string
+		want       bool
+	}{
+		{"prd", "prod", true},
+		{"appyml", "app.yaml DEFAULT_GROUP", true},
+		{"PROD", "prod", true},
+		{"dorp", "prod", false},
+		{"", "anything", true},
+		{"开发", "开发环境", true},
+	}
+	for _, tt := range tests {
+		if got := fuzzyMatch(tt.pattern, tt.s); got != tt.want {
+			t.Errorf("fuzzyMatch(%q, %q) = %v, want %v", tt.pattern, tt.s, got, tt.want)
+		}
+	}
+}
+
+func TestPickerAnswer(t *testing.T) {
+	items := []pickItem{
+		{Label: "public", Detail: "(id public)", Value: "public"},
+		{Label: "prod", Detail: "(id 7df1-prod)", Value: "7df1-prod"},
+		{Label: "dev", Detail: "(id dev)", Value: "dev"},
+	}
+	p := newPicker(items)
+
+	if _, _, err := p.answer("4"); err == nil || !strings.Contains(err.Error(), "choose 1-3") {
+		t.Errorf("answer(4) error = %v", err)
+	}
+	if _, _, err := p.answer("xyz"); err == nil || len(p.shown) != 3 {
+		t.Errorf("answer(xyz) error = %v, %d shown; want an error and the list kept", err, len(p.shown))
+	}
+
+	// Filtering renumbers the list
+	if _, ok, err := p.answer("pr"); ok || err != nil || len(p.shown) != 1 {
+		t.Fatalf("answer(pr) = %v, %v; %d shown", ok, err, len(p.shown))
+	}
+	if item, ok, _ := p.answer("1"); !ok || item.Value != "7df1-prod" {
+		t.Errorf("answer(1) after filtering = %+v, %v", item, ok)
+	}
+	if item, ok, _ := p.answer(""); !ok || item.Value != "7df1-prod" {
+		t.Errorf("answer() with one item shown = %+v, %v", item, ok)
+	}
+
+	// The filter matches the detail too; an empty line clears it
+	p.answer("i")
+	if len(p.shown) != 3 {
+		t.Errorf("answer(i) shows %d items, want 3", len(p.shown))
+	}
+	p.answer("dev")
+	if _, ok, _ := p.answer(""); !ok {
+		t.Error("answer() with one item shown did not choose it")
+	}
+	p.answer("p")
+	if _, ok, _ := p.answer(""); ok || len(p.shown) != 3 {
+		t.Errorf("answer() with several items shown = %v, %d shown; want the filter cleared", ok, len(p.shown))
+	}
+}
+
+func TestPickerPrint(t *testing.T) {
+	var items []pickItem
+	for i := 0; i < pickerPageSize+5; i++ {
+		items = append(items, pickItem{Label: fmt.Sprintf("app-%d.yaml", i), Detail: "DEFAULT_GROUP"})
+	}
+	p := newPicker(items)
+	var buf bytes.Buffer
+	p.print(&buf)
+	out := buf.String()
+	if !strings.Contains(out, "30.") || strings.Contains(out, "31.") || !strings.Contains(out, "and 5 more") {
+		t.Errorf("print() of %d items:\n%s", len(items), out)
+	}
+	if _, _, err := p.answer("31"); err == nil {
+		t.Error("answer(31) chose an item that is not listed")
+	}
+}
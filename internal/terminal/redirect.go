@@ -0,0 +1,269 @@
+package terminal
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/nacos-group/nacos-cli/internal/util"
+)
+
+// Redirect operators supported after a terminal command
+const (
+	redirectWrite  = ">"
+	redirectAppend = ">>"
+	redirectPipe   = "|"
+)
+
+// redirect describes where a command's output goes instead of the terminal
+type redirect struct {
+	op     string // one of redirectWrite, redirectAppend, redirectPipe
+	target string // file path, or the shell command for a pipe
+}
+
+// splitRedirect splits input at the first unquoted >, >> or | operator.
+// It returns the command part and a nil redirect when no operator is present.
+func splitRedirect(input string) (string, *redirect, error) {
+	runes := []rune(input)
+	var quote rune
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else if r == '\\' && quote == '"' {
+				i++
+			}
+		case r == '\'' || r == '"':
+			quote = r
+		case r == '\\':
+			i++
+		case r == '|' || r == '>':
+			op := string(r)
+			rest := i + 1
+			if r == '>' && rest < len(runes) && runes[rest] == '>' {
+				op = redirectAppend
+				rest++
+			}
+			return parseRedirectTarget(string(runes[:i]), op, string(runes[rest:]))
+		}
+	}
+	return input, nil, nil
+}
+
+func parseRedirectTarget(command, op, target string) (string, *redirect, error) {
+	if op == redirectPipe {
+		target = strings.TrimSpace(target)
+		if target == "" {
+			return "", nil, fmt.Errorf("missing command after |")
+		}
+		return command, &redirect{op: op, target: target}, nil
+	}
+	words, err := tokenize(target)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(words) != 1 {
+		return "", nil, fmt.Errorf("expected a single file name after %s", op)
+	}
+	path, err := util.ExpandTilde(words[0])
+	if err != nil {
+		return "", nil, err
+	}
+	return command, &redirect{op: op, target: path}, nil
+}
+
+// runRedirected runs fn with stdout sent to r's target. Colors and progress
+// lines are stripped so only the payload is written; error and usage lines
+// (see Terminal.diagnose) are still shown on the terminal.
+func (t *Terminal) runRedirected(r *redirect, fn func()) error {
+	var dst io.WriteCloser
+	var cmd *exec.Cmd
+
+	switch r.op {
+	case redirectPipe:
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/C", r.target)
+		} else {
+			cmd = exec.Command("sh", "-c", r.target)
+		}
+		cmd.Stdout = t.stdout()
+		cmd.Stderr = os.Stderr
+		stdin, err := cmd.StdinPipe()
+		if err != nil {
+			return err
+		}
+		if err := cmd.Start(); err != nil {
+			return fmt.Errorf("start %q: %w", r.target, err)
+		}
+		dst = stdin
+	default:
+		flags := os.O_CREATE | os.O_WRONLY | os.O_TRUNC
+		if r.op == redirectAppend {
+			flags = os.O_CREATE | os.O_WRONLY | os.O_APPEND
+		}
+		f, err := os.OpenFile(r.target, flags, 0644)
+		if err != nil {
+			return err
+		}
+		dst = f
+	}
+
+	filter := &payloadWriter{dst: dst, diag: os.Stderr}
+	if err := t.filterStdout(filter, fn); err != nil {
+		dst.Close()
+		if cmd != nil {
+			cmd.Wait()
+		}
+		return err
+	}
+
+	closeErr := dst.Close()
+	if cmd != nil {
+		if err := cmd.Wait(); err != nil {
+			return fmt.Errorf("%s: %w", r.target, err)
+		}
+		return nil
+	}
+	if filter.err != nil {
+		return filter.err
+	}
+	return closeErr
+}
+
+// captureOutput runs fn and returns what it printed, decorated as for a
+// redirect: colors and progress lines are stripped, and error lines are kept
+// after the rest.
+func (t *Terminal) captureOutput(fn func()) (string, error) {
+	var buf, diag bytes.Buffer
+	if err := t.filterStdout(&payloadWriter{dst: &buf, diag: &diag}, fn); err != nil {
+		return "", err
+	}
+	return buf.String() + diag.String(), nil
+}
+
+// filterStdout runs fn with os.Stdout, and the writer set with SetOutput,
+// replaced by a pipe drained into filter
+func (t *Terminal) filterStdout(filter *payloadWriter, fn func()) error {
+	pr, pw, err := os.Pipe()
+	if err != nil {
+		return err
+	}
+
+	copied := make(chan struct{})
+	go func() {
+		io.Copy(filter, pr)
+		filter.flush()
+		pr.Close()
+		close(copied)
+	}()
+
+	stdout, out := os.Stdout, t.out
+	os.Stdout = pw
+	if out != nil {
+		t.out = pw
+	}
+	t.redirected = filter
+	func() {
+		defer func() {
+			os.Stdout, t.out = stdout, out
+			t.redirected = nil
+			pw.Close()
+		}()
+		fn()
+	}()
+	<-copied
+	return nil
+}
+
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;?]*[A-Za-z]`)
+
+// payloadWriter strips terminal decoration from command output line by line.
+// Error and usage lines are not written to it but passed to diagnose, which
+// sends them to diag unchanged.
+type payloadWriter struct {
+	mu   sync.Mutex // Write runs on the goroutine draining the pipe, diagnose on the command's
+	dst  io.Writer
+	diag io.Writer
+	buf  []byte
+	err  error // first write error on dst; later output is discarded
+}
+
+// diagnose writes an error or usage line to diag
+func (w *payloadWriter) diagnose(line []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.diag.Write(line)
+}
+
+// writeRaw writes p to dst as it is, e.g. config content that must keep its
+// bytes even where they look like terminal decoration
+func (w *payloadWriter) writeRaw(p []byte) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.writeLine(w.buf)
+		w.buf = nil
+	}
+	w.write(p)
+}
+
+func (w *payloadWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.buf = append(w.buf, p...)
+	for {
+		idx := bytes.IndexByte(w.buf, '\n')
+		if idx < 0 {
+			break
+		}
+		w.writeLine(w.buf[:idx+1])
+		w.buf = w.buf[idx+1:]
+	}
+	return len(p), nil
+}
+
+func (w *payloadWriter) flush() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if len(w.buf) > 0 {
+		w.writeLine(w.buf)
+		w.buf = nil
+	}
+}
+
+func (w *payloadWriter) writeLine(line []byte) {
+	// Keep a line ending (\n or \r\n) but drop text overwritten by a carriage return
+	body := bytes.TrimSuffix(line, []byte("\n"))
+	body = bytes.TrimSuffix(body, []byte("\r"))
+	ending := line[len(body):]
+	if idx := bytes.LastIndexByte(body, '\r'); idx >= 0 {
+		body = body[idx+1:]
+	}
+
+	w.write(append(ansiEscape.ReplaceAll(body, nil), ending...))
+}
+
+// write writes p to dst unless an earlier write failed
+func (w *payloadWriter) write(p []byte) {
+	if w.err != nil {
+		return
+	}
+	if _, err := w.dst.Write(p); err != nil {
+		if errors.Is(err, syscall.EPIPE) || errors.Is(err, os.ErrClosed) {
+			// The reader went away (e.g. "| head"); discard the rest quietly
+			w.dst = io.Discard
+			return
+		}
+		w.err = err
+	}
+}
@@ -0,0 +1,115 @@
+package terminal
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+)
+
+func TestSplitRedirect(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		wantCommand string
+		wantOp      string
+		wantTarget  string
+		wantErr     bool
+	}{
+		{name: "no operator", input: "config-list --group g", wantCommand: "config-list --group g"},
+		{name: "write", input: "config-get app.yaml G > /tmp/app.yaml", wantCommand: "config-get app.yaml G ", wantOp: ">", wantTarget: "/tmp/app.yaml"},
+		{name: "append without spaces", input: "config-get a G>>out.txt", wantCommand: "config-get a G", wantOp: ">>", wantTarget: "out.txt"},
+		{name: "pipe keeps raw command", input: "config-list | grep 'a b' | wc -l", wantCommand: "config-list ", wantOp: "|", wantTarget: "grep 'a b' | wc -l"},
+		{name: "quoted operator is literal", input: `skill-list --name "a|b>c"`, wantCommand: `skill-list --name "a|b>c"`},
+		{name: "escaped operator is literal", input: `skill-list --name a\|b`, wantCommand: `skill-list --name a\|b`},
+		{name: "quoted file name", input: `config-get a G > "my file.yaml"`, wantCommand: "config-get a G ", wantOp: ">", wantTarget: "my file.yaml"},
+		{name: "missing file", input: "config-get a G >", wantErr: true},
+		{name: "two files", input: "config-get a G > x y", wantErr: true},
+		{name: "missing pipe command", input: "config-list |  ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			command, r, err := splitRedirect(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitRedirect(%q) expected error", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitRedirect(%q) unexpected error: %v", tt.input, err)
+			}
+			if command != tt.wantCommand {
+				t.Errorf("command = %q, want %q", command, tt.wantCommand)
+			}
+			if tt.wantOp == "" {
+				if r != nil {
+					t.Errorf("redirect = %+v, want nil", r)
+				}
+				return
+			}
+			if r == nil || r.op != tt.wantOp || r.target != tt.wantTarget {
+				t.Errorf("redirect = %+v, want op=%q target=%q", r, tt.wantOp, tt.wantTarget)
+			}
+		})
+	}
+}
+
+func TestPayloadWriter(t *testing.T) {
+	var dst, diag bytes.Buffer
+	w := &payloadWriter{dst: &dst, diag: &diag}
+
+	w.Write([]byte("\033[90mFetching configurations...\033[0m\r"))
+	w.Write([]byte("\033[K\n\033[32mapp.yaml\033[0m DEFAULT_GROUP\nkey: value\r\n"))
+	w.diagnose([]byte("\033[31mError:\033[0m boom\n"))
+	w.Write([]byte("no newline"))
+	w.flush()
+
+	if got, want := dst.String(), "\napp.yaml DEFAULT_GROUP\nkey: value\r\nno newline"; got != want {
+		t.Errorf("payload = %q, want %q", got, want)
+	}
+	if got, want := diag.String(), "\033[31mError:\033[0m boom\n"; got != want {
+		t.Errorf("diagnostics = %q, want %q", got, want)
+	}
+}
+
+func TestRedirectKeepsErrorsOnTerminal(t *testing.T) {
+	i18n.Set(i18n.Chinese)
+	t.Cleanup(func() { i18n.Set(i18n.English) })
+	stderr := filepath.Join(t.TempDir(), "stderr")
+	f, err := os.Create(stderr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	osStderr := os.Stderr
+	os.Stderr = f
+	t.Cleanup(func() { os.Stderr = osStderr })
+
+	term := newScriptTerminal()
+	term.SetOutput(io.Discard)
+	file := filepath.Join(t.TempDir(), "out.txt")
+	err = term.runRedirected(&redirect{op: redirectWrite, target: file}, func() {
+		term.println("payload")
+		term.errorf("boom")
+		term.printUsage("config-get <data-id> <group>")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if data, _ := os.ReadFile(file); string(data) != "payload\n" {
+		t.Errorf("file = %q, want only the payload", data)
+	}
+	data, _ := os.ReadFile(stderr)
+	for _, want := range []string{i18n.T("Error:") + "\033[0m boom", i18n.T("Usage:") + "\033[0m config-get"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("stderr = %q, want it to contain %q", data, want)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package terminal
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxScriptLine bounds a single script line, including inline config content
+const maxScriptLine = 1024 * 1024
+
+// lineReader is where the terminal reads input beyond the command line itself,
+// such as config-set content and confirmation answers
+type lineReader interface {
+	Readline() (string, error)
+}
+
+// scriptReader reads commands and their input from a script
+type scriptReader struct {
+	scanner *bufio.Scanner
+	line    int       // number of the last line read
+	echo    io.Writer // where Readline echoes the lines it reads
+}
+
+func newScriptReader(r io.Reader) *scriptReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), maxScriptLine)
+	return &scriptReader{scanner: scanner, echo: os.Stdout}
+}
+
+// next returns the next line of the script, or io.EOF at the end
+func (r *scriptReader) next() (string, error) {
+	if !r.scanner.Scan() {
+		if err := r.scanner.Err(); err != nil {
+			return "", err
+		}
+		return "", io.EOF
+	}
+	r.line++
+	return r.scanner.Text(), nil
+}
+
+// Readline returns the next line as input to the running command. The line is
+// echoed so the output reads like an interactive session.
+func (r *scriptReader) Readline() (string, error) {
+	line, err := r.next()
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintln(r.echo, line)
+	return line, nil
+}
+
+// RunScript executes terminal commands read from r, one per line, echoing each
+// command before its output. Blank lines and lines starting with # are skipped.
+// It stops at the first failed command unless continueOnError is set, and
+// returns an error if any command failed. name identifies the script in errors.
+// Status lines are off unless the script turns them on with "set timing on".
+func (t *Terminal) RunScript(r io.Reader, name string, continueOnError bool) error {
+	script := newScriptReader(r)
+	script.echo = t.stdout()
+	t.input = script
+	t.timing = false
+	defer t.jobs.stopAll(jobStopTimeout)
+
+	var commands, failures int
+	for t.running {
+		line, err := script.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read %s: %w", name, err)
+		}
+
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		lineNo := script.line
+		t.printf("%s%s\n", t.getPrompt(), line)
+		commands++
+		t.handleCommand(line)
+		if !t.result.failed() {
+			continue
+		}
+		failures++
+		if !continueOnError {
+			return fmt.Errorf("%s:%d: command failed: %s", name, lineNo, line)
+		}
+	}
+
+	if failures > 0 {
+		return fmt.Errorf("%s: %d of %d commands failed", name, failures, commands)
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package terminal
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/nacostest"
+)
+
+func newScriptTerminal() *Terminal {
+	return &Terminal{client: &client.NacosClient{}, hist: &history{}, running: true}
+}
+
+func TestRunScript(t *testing.T) {
+	tests := []struct {
+		name            string
+		script          string
+		continueOnError bool
+		wantErr         string
+	}{
+		{"comments and blank lines", "# list jobs\n\n  jobs\n", false, ""},
+		{"stops at first error", "jobs\n# comment\nbogus\nunknown\n", false, "setup.nacos:3: command failed: bogus"},
+		{"continue on error", "jobs\nbogus\nstop\njobs\n", true, "setup.nacos: 2 of 4 commands failed"},
+		{"quit ends the script", "jobs\nquit\nbogus\n", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := newScriptTerminal().RunScript(strings.NewReader(tt.script), "setup.nacos", tt.continueOnError)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("RunScript() error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Fatalf("RunScript() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScriptReaderReadline(t *testing.T) {
+	r := newScriptReader(strings.NewReader("a: 1\n\n# not a comment here\n"))
+	var lines []string
+	for {
+		line, err := r.Readline()
+		if err != nil {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if got := strings.Join(lines, "|"); got != "a: 1||# not a comment here" {
+		t.Errorf("lines = %q", got)
+	}
+	if r.line != 3 {
+		t.Errorf("line = %d, want 3", r.line)
+	}
+}
+
+func TestRunScriptPrintsToOutput(t *testing.T) {
+	var out strings.Builder
+	term := newScriptTerminal()
+	term.SetOutput(&out)
+	file := filepath.Join(t.TempDir(), "jobs.txt")
+	// Backslashes escape in terminal commands; Windows takes forward slashes
+	script := "jobs\nbogus\njobs > " + filepath.ToSlash(file) + "\n"
+	if err := term.RunScript(strings.NewReader(script), "setup.nacos", true); err == nil {
+		t.Fatal("RunScript() reported no failure for an unknown command")
+	}
+	got := out.String()
+	for _, want := range []string{"jobs\n", "No background jobs", "Unknown command:"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("output %q does not contain %q", got, want)
+		}
+	}
+	// Redirected output goes to the file, not to the writer
+	data, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) == 0 || strings.Count(got, "No background jobs") != 1 {
+		t.Errorf("redirected output = %q; terminal output = %q", data, got)
+	}
+}
+
+func TestRunScriptConfigSet(t *testing.T) {
+	s := nacostest.NewServer(t)
+	c, err := client.NewNacosClient(s.Addr, "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	term := NewTerminal(c)
+	term.SetOutput(io.Discard)
+	script := "config-set app.yaml DEFAULT_GROUP\na: 1\nb: 2\n\n"
+	if err := term.RunScript(strings.NewReader(script), "setup.nacos", false); err != nil {
+		t.Fatalf("RunScript() error = %v", err)
+	}
+	// Each typed line ends with Enter, the last one too
+	if got, _ := s.Config("app.yaml", "DEFAULT_GROUP"); got != "a: 1\nb: 2\n" {
+		t.Errorf("published %q, want %q", got, "a: 1\nb: 2\n")
+	}
+}
+
+func TestRunScriptConfigRoundTrip(t *testing.T) {
+	// A bare CR, escape bytes and a line that looks like an error are content
+	// like any other
+	content := "a: 1\r\nprogress: 50%\rdone\n\033[31mError: not an error\n"
+	s := nacostest.NewServer(t)
+	s.SetConfig("app.yaml", "DEFAULT_GROUP", content)
+	c, err := client.NewNacosClient(s.Addr, "", "", "", "", "", "", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	term := NewTerminal(c)
+	term.SetOutput(io.Discard)
+	file := filepath.Join(t.TempDir(), "app.yaml")
+	script := "config-get app.yaml DEFAULT_GROUP > " + filepath.ToSlash(file) + "\nconfig-set copy.yaml DEFAULT_GROUP -f " + filepath.ToSlash(file) + " --no-validate\n"
+	if err := term.RunScript(strings.NewReader(script), "setup.nacos", false); err != nil {
+		t.Fatalf("RunScript() error = %v", err)
+	}
+	if data, _ := os.ReadFile(file); string(data) != content {
+		t.Errorf("config-get > file saved %q, want %q", data, content)
+	}
+	if got, _ := s.Config("copy.yaml", "DEFAULT_GROUP"); got != content {
+		t.Errorf("published %q, want %q", got, content)
+	}
+}
@@ -0,0 +1,65 @@
+package terminal
+
+import (
+	"sync"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/skill"
+)
+
+// detailCache keeps the skill details fetched by skill-list --detail for
+// completionTTL, so paging back and forth does not download the same skills
+// again. Failures are not kept, so they are retried on the next listing.
+type detailCache struct {
+	fetch func(names []string) []skill.SkillDetail
+
+	mu      sync.Mutex
+	entries map[string]cachedDetail
+}
+
+type cachedDetail struct {
+	detail    skill.SkillDetail
+	fetchedAt time.Time
+}
+
+func newDetailCache(fetch func(names []string) []skill.SkillDetail) *detailCache {
+	return &detailCache{fetch: fetch, entries: make(map[string]cachedDetail)}
+}
+
+// get returns the details of names in order, fetching only those not cached
+func (c *detailCache) get(names []string) []skill.SkillDetail {
+	details := make([]skill.SkillDetail, len(names))
+	var missing []string
+	var missingAt []int
+	c.mu.Lock()
+	for i, name := range names {
+		if e, ok := c.entries[name]; ok && time.Since(e.fetchedAt) < completionTTL {
+			details[i] = e.detail
+			continue
+		}
+		missing = append(missing, name)
+		missingAt = append(missingAt, i)
+	}
+	c.mu.Unlock()
+	if len(missing) == 0 {
+		return details
+	}
+
+	fetched := c.fetch(missing)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for j, detail := range fetched {
+		details[missingAt[j]] = detail
+		if detail.Err == nil {
+			c.entries[missing[j]] = cachedDetail{detail: detail, fetchedAt: time.Now()}
+		}
+	}
+	return details
+}
+
+// invalidate drops every cached detail, e.g. after a skill was published
+func (c *detailCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[string]cachedDetail)
+}
@@ -0,0 +1,42 @@
+package terminal
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/skill"
+)
+
+func TestDetailCache(t *testing.T) {
+	var fetched [][]string
+	c := newDetailCache(func(names []string) []skill.SkillDetail {
+		fetched = append(fetched, names)
+		details := make([]skill.SkillDetail, len(names))
+		for i, name := range names {
+			if name == "broken" {
+				details[i].Err = errors.New("boom")
+				continue
+			}
+			details[i] = skill.SkillDetail{Info: &skill.SkillInfo{Name: name}, Resources: len(name)}
+		}
+		return details
+	})
+
+	first := c.get([]string{"a", "broken", "ccc"})
+	if first[0].Resources != 1 || first[1].Err == nil || first[2].Resources != 3 {
+		t.Fatalf("get() = %+v, want details in order with broken failing", first)
+	}
+	// Cached skills are not fetched again; failures are retried
+	second := c.get([]string{"ccc", "broken", "dd"})
+	if second[0].Resources != 3 || second[2].Resources != 2 {
+		t.Errorf("get() = %+v, want ccc from the cache and dd fetched", second)
+	}
+	c.invalidate()
+	c.get([]string{"a"})
+
+	want := [][]string{{"a", "broken", "ccc"}, {"broken", "dd"}, {"a"}}
+	if !reflect.DeepEqual(fetched, want) {
+		t.Errorf("fetched %q, want %q", fetched, want)
+	}
+}
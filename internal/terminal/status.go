@@ -0,0 +1,98 @@
+package terminal
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// commandResult is what a command reported while it ran. Handlers print as
+// they go and record their outcome here through errorf, printUsage and setRows.
+type commandResult struct {
+	err  error // the first error the command printed
+	rows int   // items listed, or -1 if the command does not list items
+}
+
+func (r commandResult) failed() bool {
+	return r.err != nil
+}
+
+// setRows records how many items a listing command printed
+func (t *Terminal) setRows(n int) {
+	t.result.rows = n
+}
+
+// printStatus prints the gray status line shown after each command when
+// timing is on
+func (t *Terminal) printStatus(elapsed time.Duration) {
+	if !t.timing || !t.running {
+		return
+	}
+	t.println(formatStatus(t.result, elapsed, t.client.ServerAddr))
+}
+
+// formatStatus renders e.g. "✓ 37 items · 412ms · server 10.0.0.1:8848"
+func formatStatus(result commandResult, elapsed time.Duration, server string) string {
+	if result.failed() {
+		return fmt.Sprintf("\033[31m✗\033[90m error · %s\033[0m", formatElapsed(elapsed))
+	}
+	parts := []string{}
+	switch result.rows {
+	case -1:
+	case 1:
+		parts = append(parts, "1 item")
+	default:
+		parts = append(parts, fmt.Sprintf("%d items", result.rows))
+	}
+	parts = append(parts, formatElapsed(elapsed))
+	if server != "" {
+		parts = append(parts, "server "+server)
+	}
+	return fmt.Sprintf("\033[32m✓\033[90m %s\033[0m", strings.Join(parts, " · "))
+}
+
+func formatElapsed(d time.Duration) string {
+	if d < time.Second {
+		return fmt.Sprintf("%dms", d.Milliseconds())
+	}
+	return fmt.Sprintf("%.1fs", d.Seconds())
+}
+
+// settings are the on/off switches changed with the set command
+func (t *Terminal) settings() map[string]*bool {
+	return map[string]*bool{
+		"timing": &t.timing,
+	}
+}
+
+// set shows or changes terminal settings
+func (t *Terminal) set(args []string) {
+	settings := t.settings()
+	if len(args) == 0 {
+		names := make([]string, 0, len(settings))
+		for name := range settings {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			t.printf("\033[32m%-12s\033[0m %s\n", name, onOff(*settings[name]))
+		}
+		return
+	}
+
+	setting, ok := settings[args[0]]
+	if !ok || len(args) != 2 || (args[1] != "on" && args[1] != "off") {
+		t.printUsage("set [timing on|off]")
+		return
+	}
+	*setting = args[1] == "on"
+	t.printf("\033[32m%s:\033[0m %s\n", args[0], args[1])
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
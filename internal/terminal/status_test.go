@@ -0,0 +1,45 @@
+package terminal
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestFormatStatus(t *testing.T) {
+	tests := []struct {
+		result  commandResult
+		elapsed time.Duration
+		want    string
+	}{
+		{commandResult{rows: 37}, 412 * time.Millisecond, "\033[32m✓\033[90m 37 items · 412ms · server nacos:8848\033[0m"},
+		{commandResult{rows: 1}, 5 * time.Millisecond, "\033[32m✓\033[90m 1 item · 5ms · server nacos:8848\033[0m"},
+		{commandResult{rows: -1}, 1500 * time.Millisecond, "\033[32m✓\033[90m 1.5s · server nacos:8848\033[0m"},
+		{commandResult{rows: -1, err: errors.New("boom")}, 30200 * time.Millisecond, "\033[31m✗\033[90m error · 30.2s\033[0m"},
+	}
+	for _, tt := range tests {
+		if got := formatStatus(tt.result, tt.elapsed, "nacos:8848"); got != tt.want {
+			t.Errorf("formatStatus(%+v, %v) = %q, want %q", tt.result, tt.elapsed, got, tt.want)
+		}
+	}
+}
+
+func TestSetTiming(t *testing.T) {
+	term := &Terminal{timing: true}
+	term.set([]string{"timing", "off"})
+	if term.timing || term.result.failed() {
+		t.Errorf("set timing off: timing = %v, failed = %v", term.timing, term.result.failed())
+	}
+	term.set([]string{"timing", "on"})
+	if !term.timing {
+		t.Error("set timing on did not enable timing")
+	}
+
+	for _, args := range [][]string{{"timing"}, {"timing", "yes"}, {"colors", "on"}} {
+		term := &Terminal{}
+		term.set(args)
+		if !term.result.failed() {
+			t.Errorf("set %q was accepted", args)
+		}
+	}
+}
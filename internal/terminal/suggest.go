@@ -0,0 +1,11 @@
+package terminal
+
+import "fmt"
+
+// didYouMean formats a suggestion to append to an error message
+func didYouMean(suggestion string) string {
+	if suggestion == "" {
+		return ""
+	}
+	return fmt.Sprintf(" (did you mean %s?)", suggestion)
+}
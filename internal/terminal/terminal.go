@@ -1,47 +1,114 @@
 package terminal
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/chzyer/readline"
 	"github.com/nacos-group/nacos-cli/internal/agentspec"
 	"github.com/nacos-group/nacos-cli/internal/client"
+	"github.com/nacos-group/nacos-cli/internal/clipboard"
+	"github.com/nacos-group/nacos-cli/internal/config"
+	"github.com/nacos-group/nacos-cli/internal/editor"
 	"github.com/nacos-group/nacos-cli/internal/help"
+	"github.com/nacos-group/nacos-cli/internal/highlight"
+	"github.com/nacos-group/nacos-cli/internal/i18n"
+	"github.com/nacos-group/nacos-cli/internal/lint"
+	"github.com/nacos-group/nacos-cli/internal/logging"
 	"github.com/nacos-group/nacos-cli/internal/skill"
+	skillsync "github.com/nacos-group/nacos-cli/internal/sync"
+	"github.com/nacos-group/nacos-cli/internal/ui"
+	"github.com/nacos-group/nacos-cli/internal/util"
 )
 
 const defaultDescLimit = 200
 
+// detailDescLimit is where descriptions are cut in the skill-list --detail
+// table; the full text is in --output json
+const detailDescLimit = 60
+
 // Terminal represents an interactive terminal
 type Terminal struct {
 	client           *client.NacosClient
 	skillService     *skill.SkillService
 	agentSpecService *agentspec.AgentSpecService
 	rl               *readline.Instance
+	input            lineReader // where multi-line content is read from: rl, or the script
 	running          bool
+	pendingInput     string // input being typed that quitting would discard, e.g. "config-set input"
+	result           commandResult // outcome of the command being run
+	timing           bool          // print a status line after each command
+	jobs             jobManager // background jobs such as skill-sync
+	redirected       *payloadWriter // output goes to a file or pipe through it; print the payload only
+	out              io.Writer  // where commands print; nil means os.Stdout, see SetOutput
+	historyFile      string     // empty means ~/.nacos-cli/history
+	hist             *history
+	aliases          map[string]string // command aliases, see SetAliases
+	aliasFile        string            // config file aliases are saved to
+	defaultGroup     string            // group used by config-get/config-set when omitted
+	defaultOutput    string            // skill-list --output when omitted; empty means table
+	defaultPageSize  int               // --size of the list commands when omitted; 0 means 20
+	maxConfigSize    int64             // config-set refuses larger content without --force
+	startupSettings  []config.Setting  // how the settings were resolved at startup, see 'settings'
+	pollTimeout      time.Duration     // how long each skill-sync poll may take; 0 means the default
+	syncHooks        skillsync.Hooks   // skill-sync hooks from the config file
+	syncStatusFile   string            // heartbeat written by skill-sync, shown by 'server'
+	skillsDir        string            // where skill-get and skill-sync install skills without -o; empty means ~/.skills
+	authErr          error             // why the last login failed, shown in the banner
+	skillCache       *completionCache // skill names for tab completion
+	skillDetails     *detailCache     // what skill-list --detail fetched
+	configCache      *completionCache // dataId/group pairs for tab completion
 }
 
 // NewTerminal creates a new interactive terminal
 func NewTerminal(nacosClient *client.NacosClient) *Terminal {
-	return &Terminal{
+	t := &Terminal{
 		client:           nacosClient,
 		skillService:     skill.NewSkillService(nacosClient),
 		agentSpecService: agentspec.NewAgentSpecService(nacosClient),
+		maxConfigSize:    util.DefaultMaxConfigSize,
 		running:          true,
-	}
+		timing:           true,
+		hist:             &history{},
+		aliases:          MergeAliases(nil),
+	}
+	t.skillService.SetProgress(ui.NewProgress)
+	t.skillCache = newCompletionCache(t.currentNamespace, t.fetchSkillCompletions)
+	t.skillDetails = newDetailCache(t.skillService.SkillDetails)
+	t.configCache = newCompletionCache(t.currentNamespace, t.fetchConfigCompletions)
+	return t
+}
+
+// currentNamespace returns the namespace of the session. Completion reads it
+// when a lookup starts, while 'ns' cannot run.
+func (t *Terminal) currentNamespace() string {
+	return t.client.Namespace
 }
 
 // getPrompt returns the prompt string with user info
 func (t *Terminal) getPrompt() string {
+	// Show the namespace and group once a default group is in use
+	scope := ""
+	if t.defaultGroup != "" {
+		ns := t.client.Namespace
+		if ns == "" {
+			ns = "public"
+		}
+		scope = fmt.Sprintf("[%s/%s]", ns, t.defaultGroup)
+	}
+
 	// Show abbreviated user info in prompt
 	switch t.client.AuthType {
 	case client.AuthTypeNacos:
 		if t.client.Username != "" {
-			return fmt.Sprintf("\033[32m%s@nacos>\033[0m ", t.client.Username)
+			return fmt.Sprintf("\033[32m%s@nacos%s>\033[0m ", t.client.Username, scope)
 		}
 	case client.AuthTypeAliyun:
 		if t.client.AccessKey != "" {
@@ -50,31 +117,98 @@ func (t *Terminal) getPrompt() string {
 			if len(ak) > 8 {
 				ak = ak[:8]
 			}
-			return fmt.Sprintf("\033[32m%s@nacos>\033[0m ", ak)
+			return fmt.Sprintf("\033[32m%s@nacos%s>\033[0m ", ak, scope)
 		}
 	case client.AuthTypeToken:
-		return "\033[32m(token)nacos>\033[0m "
+		return fmt.Sprintf("\033[32m(token)nacos%s>\033[0m ", scope)
+	}
+	return fmt.Sprintf("\033[32mnacos%s>\033[0m ", scope)
+}
+
+// updatePrompt redraws the prompt after the namespace or default group changed
+func (t *Terminal) updatePrompt() {
+	if t.rl != nil {
+		t.rl.SetPrompt(t.getPrompt())
 	}
-	return "\033[32mnacos>\033[0m "
 }
 
-// completer provides command auto-completion
-func completer() *readline.PrefixCompleter {
+// completer provides command auto-completion, including live skill names and dataIds
+func (t *Terminal) completer() *readline.PrefixCompleter {
+	skillNames := readline.PcItemDynamic(t.completeSkillNames)
+	// skill-get accepts several names, so each name may be followed by another
+	skillNames.SetChildren([]readline.PrefixCompleterInterface{skillNames})
+
 	return readline.NewPrefixCompleter(
 		readline.PcItem("help"),
-		readline.PcItem("quit"),
+		readline.PcItem("quit",
+			readline.PcItem("--force"),
+		),
 		readline.PcItem("skill-list",
 			readline.PcItem("--help"),
 			readline.PcItem("-h"),
+			readline.PcItem("--detail"),
+			readline.PcItem("--tag"),
+			readline.PcItem("--output"),
 		),
 		readline.PcItem("skill-get",
 			readline.PcItem("--help"),
 			readline.PcItem("-h"),
+			readline.PcItem("--force"),
+			readline.PcItem("--preserve-exec"),
+			readline.PcItem("--allow-partial"),
+			readline.PcItem("--uniform-retries"),
+			readline.PcItem("--uniform-retry-delay"),
+			readline.PcItem("--skip-uniform-check"),
+			readline.PcItem("--concurrency"),
+			readline.PcItem("-o"),
+			skillNames,
+		),
+		readline.PcItem("skill-sync",
+			readline.PcItem("--help"),
+			readline.PcItem("-h"),
+			readline.PcItem("--all"),
+			readline.PcItem("--poll-timeout"),
+			readline.PcItem("--poll-interval"),
+			readline.PcItem("--push"),
+			readline.PcItem("--force-remote"),
+			readline.PcItem("--force-initial-sync"),
+			readline.PcItem("--init-concurrency"),
+			readline.PcItem("--max-bandwidth"),
+			readline.PcItem("--startup-jitter"),
+			readline.PcItem("--debounce"),
+			readline.PcItem("--listen-early"),
+			readline.PcItem("--preserve-exec"),
+			readline.PcItem("--verbose-diff"),
+			readline.PcItem("--pin"),
+			readline.PcItem("--map"),
+			readline.PcItem("--map-file"),
+			readline.PcItem("--include"),
+			readline.PcItem("--exclude"),
+			readline.PcItem("--catalog"),
+			readline.PcItem("--log-file"),
+			readline.PcItem("--log-format"),
+			readline.PcItem("--on-change"),
+			readline.PcItem("--on-error"),
+			readline.PcItem("--hook-timeout"),
+			readline.PcItem("--batch-size"),
+			skillNames,
+		),
+		readline.PcItem("skill-export",
+			readline.PcItem("--help"),
+			readline.PcItem("-h"),
+			readline.PcItem("--all"),
+			readline.PcItem("--version"),
+			readline.PcItem("--label"),
+			readline.PcItem("--concurrency"),
+			readline.PcItem("-o"),
+			skillNames,
 		),
 		readline.PcItem("skill-publish",
 			readline.PcItem("--help"),
 			readline.PcItem("-h"),
 			readline.PcItem("--all"),
+			readline.PcItem("--version"),
+			readline.PcItem("--via-config"),
 		),
 		readline.PcItem("agentspec-list",
 			readline.PcItem("--help"),
@@ -92,34 +226,139 @@ func completer() *readline.PrefixCompleter {
 		readline.PcItem("config-list",
 			readline.PcItem("--help"),
 			readline.PcItem("-h"),
+			readline.PcItem("--output"),
 		),
 		readline.PcItem("config-get",
 			readline.PcItem("--help"),
+			readline.PcItem("--compact"),
+			readline.PcItem("--pick"),
 			readline.PcItem("-h"),
+			readline.PcItemDynamic(t.completeDataIDs,
+				readline.PcItemDynamic(t.completeGroups),
+			),
 		),
 		readline.PcItem("config-set",
 			readline.PcItem("--help"),
 			readline.PcItem("-h"),
 			readline.PcItem("--file"),
 			readline.PcItem("-f"),
+			readline.PcItem("--edit"),
+			readline.PcItem("--from-clipboard"),
+			readline.PcItem("--force"),
+			readline.PcItem("--allow-binary"),
+			readline.PcItem("--allow-shrink"),
+			readline.PcItem("--no-validate"),
+			readline.PcItemDynamic(t.completeDataIDs,
+				readline.PcItemDynamic(t.completeGroups),
+			),
+		),
+		readline.PcItem("config-edit",
+			readline.PcItem("--help"),
+			readline.PcItem("-h"),
+			readline.PcItem("--create"),
+			readline.PcItem("--no-validate"),
+			readline.PcItemDynamic(t.completeDataIDs,
+				readline.PcItemDynamic(t.completeGroups),
+			),
 		),
+		readline.PcItem("jobs"),
+		readline.PcItem("logs"),
+		readline.PcItem("stop"),
+		readline.PcItem("history",
+			readline.PcItem("clear"),
+		),
+		readline.PcItem("alias",
+			readline.PcItem("list"),
+			readline.PcItem("add"),
+			readline.PcItem("remove"),
+		),
+		readline.PcItem("refresh-cache"),
 		readline.PcItem("clear"),
 		readline.PcItem("server"),
-		readline.PcItem("ns"),
+		readline.PcItem("settings"),
+		readline.PcItem("login"),
+		readline.PcItem("set",
+			readline.PcItem("timing",
+				readline.PcItem("on"),
+				readline.PcItem("off"),
+			),
+		),
+		readline.PcItem("watch",
+			readline.PcItem("-n"),
+			readline.PcItem("config-get"),
+			readline.PcItem("config-list"),
+			readline.PcItem("skill-list"),
+			readline.PcItem("agentspec-list"),
+			readline.PcItem("jobs"),
+			readline.PcItem("server"),
+		),
+		readline.PcItem("ns",
+			readline.PcItem("--pick"),
+		),
+		readline.PcItem("use",
+			readline.PcItem("group"),
+			readline.PcItem("namespace"),
+		),
 	)
 }
 
+// SetHistoryFile sets where command history is persisted (default: ~/.nacos-cli/history)
+func (t *Terminal) SetHistoryFile(path string) {
+	t.historyFile = path
+}
+
+// SetSkillsDir sets where skill-get and skill-sync install skills when -o is
+// not given (default: ~/.skills)
+func (t *Terminal) SetSkillsDir(dir string) {
+	t.skillsDir = dir
+}
+
+// resolveSkillsDir returns the absolute path of dir, an -o argument, or of the
+// skills directory when dir is empty
+func (t *Terminal) resolveSkillsDir(dir string) (string, error) {
+	if dir == "" {
+		dir = t.skillsDir
+	}
+	if dir == "" {
+		dir = config.DefaultSkillsDir
+	}
+	return t.resolvePath(dir)
+}
+
+// resolvePath resolves a path typed in the terminal with util.ResolvePath,
+// logging the result at debug level, so a relative path is shown against the
+// directory nacos-cli was started in
+func (t *Terminal) resolvePath(path string) (string, error) {
+	resolved, err := util.ResolvePath(path)
+	if err != nil {
+		return "", err
+	}
+	logging.Stderr().Debug("Resolved path "+path, "path", resolved)
+	return resolved, nil
+}
+
 // Start starts the interactive terminal
 func (t *Terminal) Start() error {
-	// Configure readline
-	historyFile := filepath.Join(os.TempDir(), ".nacos-cli-history")
+	historyFile := t.historyFile
+	if historyFile == "" {
+		if path, err := defaultHistoryFile(); err == nil {
+			historyFile = path
+		}
+	}
+	hist, err := loadHistory(historyFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: history: %v\n", err)
+	}
+	t.hist = hist
 
+	// History is persisted by t.hist so secrets can be kept out of the file;
+	// readline only keeps it in memory for the arrow keys
 	rl, err := readline.NewEx(&readline.Config{
-		Prompt:          t.getPrompt(),
-		HistoryFile:     historyFile,
-		AutoComplete:    completer(),
-		InterruptPrompt: "^C",
-		EOFPrompt:       "exit",
+		Prompt:                 t.getPrompt(),
+		AutoComplete:           t.completer(),
+		InterruptPrompt:        "^C",
+		EOFPrompt:              "exit",
+		DisableAutoSaveHistory: true,
 	})
 	if err != nil {
 		return err
@@ -127,19 +366,26 @@ func (t *Terminal) Start() error {
 	defer rl.Close()
 
 	t.rl = rl
+	t.input = rl
+	for _, entry := range t.hist.entries {
+		rl.SaveHistory(entry)
+	}
 
+	// The client logs in lazily; log in now so the banner shows the outcome
+	t.authErr = t.client.EnsureTokenValid()
 	t.printWelcome()
 
 	for t.running {
 		line, err := rl.Readline()
 		if err == readline.ErrInterrupt {
+			// Ctrl+C on an empty line quits, but still asks while work is pending
 			if len(line) == 0 {
-				break
-			} else {
-				continue
+				t.exit(false)
 			}
+			continue
 		} else if err == io.EOF {
-			break
+			t.exit(false)
+			continue
 		}
 
 		line = strings.TrimSpace(line)
@@ -147,241 +393,595 @@ func (t *Terminal) Start() error {
 			continue
 		}
 
+		if strings.HasPrefix(line, "!") {
+			expanded, err := t.expandHistory(line)
+			if err != nil {
+				t.errorf("%v", err)
+				t.println()
+				continue
+			}
+			t.println(expanded)
+			line = expanded
+		}
+
+		t.recordHistory(line)
 		t.handleCommand(line)
 	}
 
+	t.jobs.stopAll(jobStopTimeout)
 	return nil
 }
 
+// readAnswer asks a question on the prompt line and returns the lower-cased answer
+func (t *Terminal) readAnswer(prompt string) (string, error) {
+	if t.rl != nil {
+		t.rl.SetPrompt(prompt)
+		defer t.rl.SetPrompt(t.getPrompt())
+	} else {
+		t.print(prompt)
+	}
+	line, err := t.input.Readline()
+	if err != nil {
+		return "", err
+	}
+	return strings.ToLower(strings.TrimSpace(line)), nil
+}
+
+// confirm asks a yes/no question through the line editor. Like ui.Confirm it
+// honors --yes and --interactive=false, and fails closed in script mode.
+func (t *Terminal) confirm(prompt string, defaultNo bool) (bool, error) {
+	return ui.ConfirmWith(prompt, defaultNo, t.rl != nil, func(prompt string) (string, error) {
+		return t.readAnswer("\033[33m" + prompt + "\033[0m")
+	})
+}
+
 // printWelcome prints welcome message
 func (t *Terminal) printWelcome() {
-	fmt.Println("\033[36m╔════════════════════════════════════════════════════════╗\033[0m")
-	fmt.Println("\033[36m║\033[0m                  \033[1mNacos CLI Terminal\033[0m                   \033[36m║\033[0m")
-	fmt.Println("\033[36m╚════════════════════════════════════════════════════════╝\033[0m")
-	fmt.Printf("\033[33mServer:\033[0m %s\n", t.client.ServerAddr)
+	t.println("\033[36m╔════════════════════════════════════════════════════════╗\033[0m")
+	t.println("\033[36m║\033[0m                  \033[1mNacos CLI Terminal\033[0m                   \033[36m║\033[0m")
+	t.println("\033[36m╚════════════════════════════════════════════════════════╝\033[0m")
+	t.printf("\033[33m%s\033[0m %s\n", i18n.T("Server:"), t.client.ServerAddr)
 	if t.client.Namespace != "" {
-		fmt.Printf("\033[33mNamespace:\033[0m %s\n", t.client.Namespace)
+		t.printf("\033[33m%s\033[0m %s\n", i18n.T("Namespace:"), t.client.Namespace)
 	}
 	// Show user info based on auth type
 	switch t.client.AuthType {
 	case client.AuthTypeNacos:
 		if t.client.Username != "" {
-			fmt.Printf("\033[33mUser:\033[0m %s (username/password)\n", t.client.Username)
+			t.printf("\033[33m%s\033[0m %s\n", i18n.T("User:"), i18n.T("%s (username/password)", t.client.Username))
+		}
+		if t.authErr != nil {
+			t.printf("\033[31m%s\033[0m %v \033[90m%s\033[0m\n", i18n.T("Auth:"), t.authErr, i18n.T("(use 'login' to retry)"))
+		} else if t.client.AccessToken != "" {
+			t.printf("\033[33m%s\033[0m %s\n", i18n.T("Auth:"), i18n.T("logged in, token %s", t.tokenStatus()))
 		}
 	case client.AuthTypeAliyun:
 		if t.client.AccessKey != "" {
-			fmt.Printf("\033[33mUser:\033[0m %s (AccessKey)\n", t.client.AccessKey)
+			t.printf("\033[33mUser:\033[0m %s (AccessKey)\n", t.client.AccessKey)
 		}
 	case client.AuthTypeToken:
-		fmt.Printf("\033[33mAuth:\033[0m Token (authenticated)\n")
+		t.printf("\033[33mAuth:\033[0m Token (authenticated)\n")
 	case client.AuthTypeNone:
-		fmt.Printf("\033[33mAuth:\033[0m None (public access)\n")
-	}
-	fmt.Println()
-	fmt.Println("\033[90mType '\033[0mhelp\033[90m' for available commands\033[0m")
-	fmt.Println("\033[90mPress '\033[0mTab\033[90m' for auto-completion\033[0m")
-	fmt.Println("\033[90mPress '\033[0mCtrl+C\033[90m' or type '\033[0mquit\033[90m' to quit\033[0m")
-	fmt.Println()
-}
-
-// parseCommandArgs parses command line arguments, properly handling flags and their values
-// For example: "skill-get my-skill --label latest -o /path" should recognize:
-//   - skill name: my-skill
-//   - flags: --label latest, -o /path
-// This prevents flags and their values from being treated as additional skill names
-func parseCommandArgs(input string) (cmd string, args []string) {
-	parts := strings.Fields(input)
-	if len(parts) == 0 {
-		return "", nil
-	}
-
-	cmd = parts[0]
-	args = make([]string, 0, len(parts)-1)
-
-	// Parse remaining parts, handling flags properly
-	for i := 1; i < len(parts); i++ {
-		arg := parts[i]
-		
-		// Check if this is a flag
-		if strings.HasPrefix(arg, "-") {
-			args = append(args, arg)
-			// Check if flag takes a value (not a boolean flag like --help or --all)
-			// Flags that don't take values
-			booleanFlags := map[string]bool{
-				"--help": true, "-h": true,
-				"--all": true,
-			}
-			
-			// If it's a long flag (--flag), check if value is separate
-			if strings.HasPrefix(arg, "--") && !strings.Contains(arg, "=") {
-				if !booleanFlags[arg] && i+1 < len(parts) && !strings.HasPrefix(parts[i+1], "-") {
-					// Next arg is the value, skip it in main args (it will be handled by flag parser)
-					args = append(args, parts[i+1])
-					i++ // Skip next arg since we consumed it
-				}
-			} else if strings.HasPrefix(arg, "-") && !strings.Contains(arg, "=") {
-				// Short flag (-o), check if value is separate
-				if !booleanFlags[arg] && len(arg) == 2 && i+1 < len(parts) && !strings.HasPrefix(parts[i+1], "-") {
-					// Next arg is the value
-					args = append(args, parts[i+1])
-					i++ // Skip next arg
-				}
-			}
-		} else {
-			// Not a flag, treat as positional argument
-			args = append(args, arg)
-		}
+		t.printf("\033[33mAuth:\033[0m None (public access)\n")
+	}
+	if dir, err := t.resolveSkillsDir(""); err == nil {
+		t.printf("\033[33m%s\033[0m %s\n", i18n.T("Skills:"), dir)
 	}
+	t.println()
+	t.println("\033[90mType '\033[0mhelp\033[90m' for available commands\033[0m")
+	t.println("\033[90mPress '\033[0mTab\033[90m' for auto-completion\033[0m")
+	t.println("\033[90mPress '\033[0mCtrl+C\033[90m' or type '\033[0mquit\033[90m' to quit\033[0m")
+	t.println()
+}
 
-	return cmd, args
+// parseCommandArgs splits a command line into the command name and its arguments.
+// Quoting follows shell rules (see tokenize), so "skill-list --name 'my skill'"
+// yields the single argument "my skill" for --name. Flags are left in place and
+// parsed by each command's own flag set.
+func parseCommandArgs(input string) (cmd string, args []string, err error) {
+	tokens, err := tokenize(input)
+	if err != nil {
+		return "", nil, err
+	}
+	if len(tokens) == 0 {
+		return "", nil, nil
+	}
+	return tokens[0], tokens[1:], nil
 }
 
-// handleCommand handles user command
+// handleCommand handles user command and prints its status line. t.result
+// holds what the command reported.
 func (t *Terminal) handleCommand(input string) {
-	cmd, args := parseCommandArgs(input)
+	t.result = commandResult{rows: -1}
+	start := time.Now()
+	t.dispatch(input)
+	t.printStatus(time.Since(start))
+	t.println()
+}
+
+// dispatch runs a command line, applying any output redirection
+func (t *Terminal) dispatch(input string) {
+	command, redir, err := splitRedirect(input)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	if redir == nil {
+		t.runCommand(command)
+		return
+	}
+
+	if err := t.runRedirected(redir, func() { t.runCommand(command) }); err != nil {
+		t.errorf("%v", err)
+	}
+}
+
+// runCommand parses and executes a single command
+func (t *Terminal) runCommand(input string) {
+	cmd, args, err := parseCommandArgs(input)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	words, err := ExpandAliases(append([]string{cmd}, args...), t.aliases)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	cmd, args = words[0], words[1:]
 
 	switch cmd {
 	case "help":
-		t.showHelp()
+		if t.checkArgs(args, 0) {
+			t.showHelp()
+		}
 	case "quit":
-		t.exit()
+		t.quit(args)
 	case "skill-list":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showSkillListHelp()
 		} else {
 			t.listSkills(args)
 		}
 	case "skill-get":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showSkillGetHelp()
 		} else {
 			t.getSkill(args)
 		}
+	case "skill-export":
+		if hasHelpFlag(args) {
+			t.showSkillExportHelp()
+		} else {
+			t.exportSkill(args)
+		}
 	case "skill-publish":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showSkillPublishHelp()
 		} else {
 			t.uploadSkill(args)
 		}
 	case "skill-sync":
-		fmt.Println("\033[33mskill-sync has been removed.\033[0m")
-		fmt.Println("\033[90mUse 'skill-get' to download skills.\033[0m")
+		if hasHelpFlag(args) {
+			t.showSkillSyncHelp()
+		} else {
+			t.syncSkill(args)
+		}
+	case "jobs":
+		if t.checkArgs(args, 0) {
+			t.listJobs()
+		}
+	case "logs":
+		t.showJobLogs(args)
+	case "stop":
+		t.stopJob(args)
 	case "agentspec-list":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showAgentSpecListHelp()
 		} else {
 			t.listAgentSpecs(args)
 		}
 	case "agentspec-get":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showAgentSpecGetHelp()
 		} else {
 			t.getAgentSpec(args)
 		}
 	case "agentspec-publish":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showAgentSpecPublishHelp()
 		} else {
 			t.publishAgentSpec(args)
 		}
 	case "config-list":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showConfigListHelp()
 		} else {
 			t.listConfigs(args)
 		}
 	case "config-get":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showConfigGetHelp()
 		} else {
 			t.getConfig(args)
 		}
 	case "config-set":
-		if len(args) > 0 && (args[0] == "--help" || args[0] == "-h") {
+		if hasHelpFlag(args) {
 			t.showConfigSetHelp()
 		} else {
 			t.setConfig(args)
 		}
+	case "config-edit":
+		if hasHelpFlag(args) {
+			t.showConfigEditHelp()
+		} else {
+			t.editConfigCommand(args)
+		}
+	case "history":
+		t.showHistory(args)
+	case "alias":
+		t.alias(args)
+	case "use":
+		t.use(args)
+	case "login":
+		t.login(args)
+	case "watch":
+		t.watch(args)
+	case "set":
+		t.set(args)
+	case "refresh-cache":
+		if t.checkArgs(args, 0) {
+			t.refreshCache()
+		}
 	case "clear":
-		t.clear()
+		if t.checkArgs(args, 0) {
+			t.clear()
+		}
 	case "server":
-		t.showServerInfo()
+		if t.checkArgs(args, 0) {
+			t.showServerInfo()
+		}
+	case "settings":
+		if t.checkArgs(args, 0) {
+			t.showSettings()
+		}
 	case "ns":
-		t.namespace(args)
+		if t.checkArgs(args, 1) {
+			t.namespace(args)
+		}
 	default:
-		fmt.Printf("\033[31mUnknown command:\033[0m %s\n", cmd)
-		fmt.Println("\033[90mType '\033[0mhelp\033[90m' for available commands\033[0m")
+		suggestion := didYouMean(t.closestCommand(cmd))
+		t.fail(fmt.Errorf("unknown command: %s", cmd))
+		t.printf("\033[31mUnknown command:\033[0m %s%s\n", cmd, suggestion)
+		t.println("\033[90mType '\033[0mhelp\033[90m' for available commands\033[0m")
+	}
+}
+
+// commandNames lists the terminal commands, for suggestions after a typo
+var commandNames = []string{
+	"help", "quit", "skill-list", "skill-get", "skill-export", "skill-publish", "skill-sync",
+	"jobs", "logs", "stop", "agentspec-list", "agentspec-get", "agentspec-publish",
+	"config-list", "config-get", "config-set", "config-edit", "history", "alias", "use", "login",
+	"watch", "set", "refresh-cache", "clear", "server", "settings", "ns",
+}
+
+// closestCommand returns the command or alias name nearest to a mistyped one
+func (t *Terminal) closestCommand(name string) string {
+	candidates := append([]string{}, commandNames...)
+	for alias := range t.aliases {
+		candidates = append(candidates, alias)
+	}
+	sort.Strings(candidates)
+	return util.Closest(name, candidates)
+}
+
+// SetOutput sets where commands print, e.g. a buffer in tests; nil, the
+// default, is os.Stdout
+func (t *Terminal) SetOutput(w io.Writer) {
+	t.out = w
+}
+
+// stdout returns where commands print. os.Stdout is looked up on every call,
+// so output follows a redirect that replaces it.
+func (t *Terminal) stdout() io.Writer {
+	if t.out != nil {
+		return t.out
+	}
+	return os.Stdout
+}
+
+// printf, println and print are fmt's functions writing to stdout
+func (t *Terminal) printf(format string, args ...interface{}) {
+	fmt.Fprintf(t.stdout(), format, args...)
+}
+
+func (t *Terminal) println(args ...interface{}) {
+	fmt.Fprintln(t.stdout(), args...)
+}
+
+func (t *Terminal) print(args ...interface{}) {
+	fmt.Fprint(t.stdout(), args...)
+}
+
+// errorf prints a red error line and marks the current command as failed
+func (t *Terminal) errorf(format string, args ...interface{}) {
+	t.fail(fmt.Errorf(format, args...))
+	t.diagnose(fmt.Sprintf("\033[31m%s\033[0m %s\n", i18n.T("Error:"), i18n.T(format, args...)))
+}
+
+// printUsage prints a command's usage after it was called with bad arguments
+func (t *Terminal) printUsage(usage string) {
+	t.fail(fmt.Errorf("usage: %s", usage))
+	t.diagnose(fmt.Sprintf("\033[31m%s\033[0m %s\n", i18n.T("Usage:"), usage))
+}
+
+// diagnose prints an error or usage line. While output is redirected it goes
+// to the terminal rather than to the file or pipe.
+func (t *Terminal) diagnose(line string) {
+	if t.redirected != nil {
+		t.redirected.diagnose([]byte(line))
+		return
+	}
+	t.print(line)
+}
+
+// fail records err as the outcome of the current command unless an earlier
+// error was already recorded
+func (t *Terminal) fail(err error) {
+	if t.result.err == nil {
+		t.result.err = err
 	}
-	fmt.Println()
 }
 
 // showHelp shows available commands
 func (t *Terminal) showHelp() {
-	fmt.Println("\033[1;36mAvailable Commands:\033[0m")
-	fmt.Println("\033[90m─────────────────────────────────────────────────────────────────────────────────────────────────────────\033[0m")
-	fmt.Printf("\033[90m%-20s %-40s %-30s\033[0m\n", "Command", "Description", "Usage")
-	fmt.Println("\033[90m─────────────────────────────────────────────────────────────────────────────────────────────────────────\033[0m")
+	t.printf("\033[1;36m%s\033[0m\n", i18n.T("Available Commands:"))
+	t.println("\033[90m─────────────────────────────────────────────────────────────────────────────────────────────────────────\033[0m")
+	t.printf("\033[90m%s %s %s\033[0m\n", util.PadRight(i18n.T("Command"), 20), util.PadRight(i18n.T("Description"), 40), i18n.T("Usage"))
+	t.println("\033[90m─────────────────────────────────────────────────────────────────────────────────────────────────────────\033[0m")
 
 	// Skill Management
-	fmt.Println("\033[1;33mSkill Management\033[0m")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "skill-list", "List all skills", "skill-list [options]")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "", "Options: --name, --page, --size", "")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "skill-get", "Download a skill to ~/.skills", "skill-get <name> [--version v1] [--label stable]")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "skill-publish", "Publish a skill from local", "skill-publish <path>")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "", "Publish all skills in directory", "skill-publish --all <folder>")
-	fmt.Println()
+	t.printf("\033[1;33m%s\033[0m\n", i18n.T("Skill Management"))
+	t.helpRow("skill-list", "List all skills", "skill-list [options]")
+	t.helpRow("", "Options: --name, --page, --size", "")
+	t.helpRow("skill-get", "Download a skill into the skills directory", "skill-get <name> [-o dir] [--force]")
+	t.helpRow("skill-export", "Save a skill to a zip without installing", "skill-export <name> [-o file.zip]")
+	t.helpRow("", "Back up all skills with a manifest", "skill-export --all [-o dir]")
+	t.helpRow("skill-publish", "Publish a skill from local", "skill-publish <path>")
+	t.helpRow("", "Publish all skills in directory", "skill-publish --all <folder>")
+	t.helpRow("skill-sync", "Keep skills in sync (background job)", "skill-sync <name...> | --all | --push <dir>")
+	t.println()
 
 	// AgentSpec Management
-	fmt.Println("\033[1;33mAgentSpec Management\033[0m")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "agentspec-list", "List all agent specs", "agentspec-list [options]")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "", "Options: --name, --page, --size", "")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "agentspec-get", "Download an agent spec to ~/.agentspecs", "agentspec-get <name> [--version v1] [--label stable]")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "agentspec-publish", "Publish an agent spec from local", "agentspec-publish <path>")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "", "Publish all agent specs in directory", "agentspec-publish --all <folder>")
-	fmt.Println()
+	t.printf("\033[1;33m%s\033[0m\n", i18n.T("AgentSpec Management"))
+	t.helpRow("agentspec-list", "List all agent specs", "agentspec-list [options]")
+	t.helpRow("", "Options: --name, --page, --size", "")
+	t.helpRow("agentspec-get", "Download an agent spec to ~/.agentspecs", "agentspec-get <name> [--version v1] [--label stable]")
+	t.helpRow("agentspec-publish", "Publish an agent spec from local", "agentspec-publish <path>")
+	t.helpRow("", "Publish all agent specs in directory", "agentspec-publish --all <folder>")
+	t.println()
 
 	// Configuration Management
-	fmt.Println("\033[1;33mConfiguration Management\033[0m")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "config-list", "List all configurations", "config-list [options]")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "", "Options: --data-id, --group, --page, --size", "")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "config-get", "Get configuration content", "config-get <data-id> <group>")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "config-set", "Publish config (-f file or type content)", "config-set <data-id> <group> [-f <file>]")
-	fmt.Println()
+	t.printf("\033[1;33m%s\033[0m\n", i18n.T("Configuration Management"))
+	t.helpRow("config-list", "List all configurations", "config-list [options]")
+	t.helpRow("", "Options: --data-id, --group, --page, --size", "")
+	t.helpRow("config-get", "Get configuration content", "config-get <data-id> <group> [--compact] | --pick [data-id] [group]")
+	t.helpRow("config-set", "Publish config (-f file or type content)", "config-set <data-id> <group> [-f <file>]")
+	t.helpRow("", "Edit in $EDITOR, review diff, publish", "config-set <data-id> <group> --edit")
+	t.helpRow("", "Publish the clipboard after a preview", "config-set <data-id> <group> --from-clipboard")
+	t.helpRow("config-edit", "Edit config in $EDITOR and publish", "config-edit <data-id> <group> [--create]")
+	t.println()
+
+	// Background Jobs
+	t.printf("\033[1;33m%s\033[0m\n", i18n.T("Background Jobs"))
+	t.helpRow("jobs", "List background jobs", "jobs")
+	t.helpRow("logs", "Show recent output of a job", "logs <id> [-n lines]")
+	t.helpRow("stop", "Stop a background job", "stop <id>")
+	t.println()
 
 	// System
-	fmt.Println("\033[1;33mSystem\033[0m")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "server", "Show server information", "server")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "ns", "Show current namespace", "ns")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "ns <namespace>", "Switch to different namespace", "ns <namespace>")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "clear", "Clear screen", "clear")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "help", "Show this help message", "help")
-	fmt.Printf("\033[32m%-20s\033[0m %-40s %-30s\n", "quit", "Exit terminal", "quit")
+	t.printf("\033[1;33m%s\033[0m\n", i18n.T("System"))
+	t.helpRow("server", "Show server information", "server")
+	t.helpRow("settings", "Show settings and where each came from", "settings")
+	t.helpRow("login", "Log in again (e.g. after the token expired)", "login [username]")
+	t.helpRow("ns", "Show current namespace", "ns")
+	t.helpRow("ns <namespace>", "Switch to different namespace", "ns <namespace> | ns --pick")
+	t.helpRow("use group <name>", "Default group for config-get/set", "use group <name> | use group -")
+	t.helpRow("refresh-cache", "Re-fetch skill/config names for Tab", "refresh-cache")
+	t.helpRow("set", "Show or change terminal settings", "set [timing on|off]")
+	t.helpRow("watch", "Re-run a read-only command periodically", "watch [-n seconds] <command...>")
+	t.helpRow("history", "List recent commands (!N re-runs one)", "history [count] | history clear")
+	t.helpRow("alias", "List, add or remove command aliases", "alias add <name> <command...>")
+	t.helpRow("clear", "Clear screen", "clear")
+	t.helpRow("help", "Show this help message", "help")
+	t.helpRow("quit", "Exit terminal", "quit [--force]")
+
+	t.println("\033[90m─────────────────────────────────────────────────────────────────────────────────────────────────────────\033[0m")
+	t.printf("\033[90m%s\033[0m\n", i18n.T("Tip: Use Tab for auto-completion, ↑↓ for history"))
+	t.printf("\033[90m%s\033[0m\n", i18n.T("Tip: Append '> file', '>> file' or '| command' to redirect a command's output"))
+}
 
-	fmt.Println("\033[90m─────────────────────────────────────────────────────────────────────────────────────────────────────────\033[0m")
-	fmt.Println("\033[90mTip: Use Tab for auto-completion, ↑↓ for history\033[0m")
+// helpRow prints a row of the help table, with its description translated
+func (t *Terminal) helpRow(command, description, usage string) {
+	t.printf("\033[32m%s\033[0m %s %s\n", util.PadRight(command, 20), util.PadRight(i18n.T(description), 40), usage)
 }
 
-// exit exits the terminal
-func (t *Terminal) exit() {
-	fmt.Println("\033[36mGoodbye! Have a great day!\033[0m")
+// quit handles "quit [--force]"
+func (t *Terminal) quit(args []string) {
+	var force bool
+
+	fs := newFlagSet("quit")
+	fs.BoolVar(&force, "force", false, "Quit without asking, stopping background jobs")
+	fs.maxArgs = 0
+	if _, ok := t.parseFlags(fs, args); !ok {
+		return
+	}
+	t.exit(force)
+}
+
+// exit stops the terminal. Unless force is set, it asks first while background
+// jobs are running or input is pending, and reports whether it is quitting.
+// Running jobs are stopped when Start returns.
+func (t *Terminal) exit(force bool) bool {
+	if warning := t.exitWarning(); warning != "" && !force && t.rl != nil {
+		ok, err := t.confirm(warning, true)
+		if err != nil {
+			t.errorf("%v", err)
+			return false
+		}
+		if !ok {
+			return false
+		}
+	}
+	t.printf("\033[36m%s\033[0m\n", i18n.T("Goodbye! Have a great day!"))
 	t.running = false
+	return true
+}
+
+// exitWarning describes the work quitting would abandon, or returns "" if there is none
+func (t *Terminal) exitWarning() string {
+	jobs, them := "", "them"
+	switch n := len(t.jobs.running()); n {
+	case 0:
+	case 1:
+		jobs, them = "1 sync job running", "it"
+	default:
+		jobs = fmt.Sprintf("%d sync jobs running", n)
+	}
+
+	switch {
+	case jobs != "" && t.pendingInput != "":
+		return fmt.Sprintf("%s and %s not finished — stop %s, discard the input and quit?", jobs, t.pendingInput, them)
+	case jobs != "":
+		return fmt.Sprintf("%s — stop %s and quit?", jobs, them)
+	case t.pendingInput != "":
+		return t.pendingInput + " not finished — discard it and quit?"
+	}
+	return ""
 }
 
 // clear clears the screen
 func (t *Terminal) clear() {
-	fmt.Print("\033[H\033[2J")
+	ui.ClearScreen()
 	t.printWelcome()
 }
 
 // showServerInfo shows server information
 func (t *Terminal) showServerInfo() {
-	fmt.Println("Server Information:")
-	fmt.Println("─────────────────────────────────────────────────────────")
-	fmt.Printf("  Server:    %s\n", t.client.ServerAddr)
-	fmt.Printf("  Username:  %s\n", t.client.Username)
-	fmt.Printf("  Namespace: %s\n", t.client.Namespace)
-	fmt.Printf("  Auth Type: %s\n", t.getAuthTypeDisplay())
-	fmt.Println("─────────────────────────────────────────────────────────")
+	t.println(i18n.T("Server Information:"))
+	t.println("─────────────────────────────────────────────────────────")
+	t.infoRow("Server:", t.client.ServerAddr)
+	t.infoRow("Username:", t.client.Username)
+	t.infoRow("Namespace:", t.client.Namespace)
+	t.infoRow("Auth Type:", t.getAuthTypeDisplay())
+	if status := t.tokenStatus(); status != "" {
+		t.infoRow("Token:", status)
+	}
+	if t.syncStatusFile != "" {
+		t.println("─────────────────────────────────────────────────────────")
+		t.println(i18n.T("Sync:"))
+		if status, err := skillsync.ReadStatus(t.syncStatusFile); err != nil {
+			t.println("  " + i18n.T("No heartbeat (start skill-sync to write one)"))
+		} else {
+			status.Print(t.stdout())
+		}
+	}
+	t.println("─────────────────────────────────────────────────────────")
+}
+
+// SetSettings sets how the settings were resolved at startup, for 'settings'
+func (t *Terminal) SetSettings(settings []config.Setting) {
+	t.startupSettings = settings
+}
+
+// showSettings prints the settings in effect with their sources, including
+// the namespace and group changed in this session
+func (t *Terminal) showSettings() {
+	var current config.Settings
+	for _, setting := range t.startupSettings {
+		current.Set(setting.Name, setting.Value, setting.Source)
+	}
+	if setting, ok := current.Get("namespace"); !ok || setting.Value != t.client.Namespace {
+		current.Set("namespace", t.client.Namespace, "terminal: ns / use namespace")
+	}
+	if setting, ok := current.Get("defaults.group"); !ok || setting.Value != t.defaultGroup {
+		current.Set("defaults.group", t.defaultGroup, "terminal: use group")
+	}
+	current.Print(t.stdout())
+}
+
+// infoRow prints a line of the server information with its label translated
+func (t *Terminal) infoRow(label, value string) {
+	t.printf("  %s %s\n", util.PadRight(i18n.T(label), 10), value)
+}
+
+// tokenStatus describes the access token's remaining lifetime, or "" when the
+// auth type does not use one
+func (t *Terminal) tokenStatus() string {
+	if t.client.AuthType == client.AuthTypeToken {
+		return "pre-issued (expiry unknown)"
+	}
+	if t.client.AuthType != client.AuthTypeNacos {
+		return ""
+	}
+	if t.client.AccessToken == "" {
+		return "not logged in (use 'login')"
+	}
+	if t.client.TokenExpireAt.IsZero() {
+		return "valid (no expiry reported)"
+	}
+	ttl := time.Until(t.client.TokenExpireAt)
+	if ttl <= 0 {
+		return "expired (renewed on the next request)"
+	}
+	return fmt.Sprintf("expires in %s (at %s)", ttl.Round(time.Second), t.client.TokenExpireAt.Format("15:04:05"))
+}
+
+// login logs in again with username/password auth, e.g. after the token was
+// revoked. "login <username>" switches to another user.
+func (t *Terminal) login(args []string) {
+	if len(args) > 1 || (len(args) == 1 && strings.HasPrefix(args[0], "-")) {
+		t.printUsage("login [username]")
+		return
+	}
+	switch t.client.AuthType {
+	case client.AuthTypeToken, client.AuthTypeAliyun:
+		t.errorf("login is not used with %s auth", t.client.AuthType)
+		return
+	}
+	if len(args) == 1 && args[0] != t.client.Username {
+		t.client.Username = args[0]
+		t.client.Password = ""
+	}
+	if t.client.Username == "" {
+		t.printUsage("login <username>")
+		return
+	}
+
+	if t.client.Password == "" {
+		if t.rl == nil {
+			t.errorf("no password available; start nacos-cli with --password to log in from a script")
+			return
+		}
+		password, err := t.rl.ReadPassword(fmt.Sprintf("Password for %s: ", t.client.Username))
+		if err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		t.client.Password = string(password)
+	}
+
+	t.client.AuthType = client.AuthTypeNacos
+	t.printf("\033[90mLogging in as \033[33m%s\033[90m...\033[0m\n", t.client.Username)
+	if t.authErr = t.client.Login(); t.authErr != nil {
+		t.errorf("%v", t.authErr)
+		return
+	}
+	t.skillCache.invalidate()
+	t.skillDetails.invalidate()
+	t.configCache.invalidate()
+	t.updatePrompt()
+	t.printf("\033[32mLogged in\033[0m, token %s\n", t.tokenStatus())
 }
 
 // getAuthTypeDisplay returns a human-readable auth type description
@@ -417,86 +1017,302 @@ func min(a, b int) int {
 func (t *Terminal) namespace(args []string) {
 	if len(args) == 0 {
 		// Show current namespace
-		fmt.Printf("Current Namespace: %s\n", t.client.Namespace)
+		t.printf("Current Namespace: %s\n", t.client.Namespace)
 		return
 	}
 
+	if args[0] == "--pick" {
+		id, ok := t.pickNamespace()
+		if !ok {
+			return
+		}
+		args = []string{id}
+	}
+
 	// Switch namespace
 	oldNs := t.client.Namespace
 	t.client.Namespace = args[0]
+	t.skillCache.invalidate()
+	t.skillDetails.invalidate()
+	t.configCache.invalidate()
+
+	t.updatePrompt()
+
+	t.printf("Switched namespace from '%s' to '%s'\n", oldNs, t.client.Namespace)
+	t.printNamespaceHint(nil)
+}
+
+// pickNamespace lets the user choose a namespace from the server's list and
+// returns its id
+func (t *Terminal) pickNamespace() (string, bool) {
+	namespaces, err := t.client.ListNamespaces()
+	if err != nil {
+		t.errorf("%v", err)
+		return "", false
+	}
+	var items []pickItem
+	for _, n := range namespaces {
+		id := n.ID
+		if id == "" {
+			id = "public"
+		}
+		detail := fmt.Sprintf("(id %s, %d configs)", id, n.ConfigCount)
+		if id == t.client.Namespace {
+			detail += " current"
+		}
+		items = append(items, pickItem{Label: n.Name, Detail: detail, Value: id})
+	}
+	item, err := t.pick("Namespaces", items)
+	if err != nil {
+		if !errors.Is(err, errPickCancelled) {
+			t.errorf("%v", err)
+		}
+		return "", false
+	}
+	return item.Value, true
+}
+
+// pickConfig lets the user choose a config among those whose dataId and
+// group match the patterns
+func (t *Terminal) pickConfig(dataIDPattern, groupPattern string) (dataID, group string, ok bool) {
+	t.print("\033[90mFetching configurations...\033[0m\r")
+	configs, err := t.client.ExpandConfigs(dataIDPattern, groupPattern)
+	t.print("\033[K")
+	if err != nil {
+		t.errorf("%v", err)
+		t.printNamespaceHint(err)
+		return "", "", false
+	}
+	if len(configs) == 0 {
+		t.errorf("no config matches %s (%s)", dataIDPattern, groupPattern)
+		t.printNamespaceHint(nil)
+		return "", "", false
+	}
+	items := make([]pickItem, len(configs))
+	for i, c := range configs {
+		// A tab cannot be in a dataId or group, so it separates them
+		items[i] = pickItem{Label: c.DataID, Detail: c.GroupName, Value: c.DataID + "\t" + c.GroupName}
+	}
+	item, err := t.pick("Configurations", items)
+	if err != nil {
+		if !errors.Is(err, errPickCancelled) {
+			t.errorf("%v", err)
+		}
+		return "", "", false
+	}
+	dataID, group, _ = strings.Cut(item.Value, "\t")
+	return dataID, group, true
+}
+
+// printNamespaceHint explains an empty result (err is nil) or a not-found or
+// forbidden error by the current namespace, see client.NamespaceHint
+func (t *Terminal) printNamespaceHint(err error) {
+	if err != nil && !errors.Is(err, client.ErrConfigNotFound) && !errors.Is(err, client.ErrForbidden) {
+		return
+	}
+	if hint := t.client.NamespaceHint(errors.Is(err, client.ErrForbidden)); hint != "" {
+		t.printf("\033[33m%s\033[0m\n", i18n.T("Hint: %s", hint))
+	}
+}
+
+// SetPollTimeout sets how long each skill-sync poll may take unless the
+// command gives --poll-timeout
+func (t *Terminal) SetPollTimeout(timeout time.Duration) {
+	t.pollTimeout = timeout
+}
+
+// SetSyncHooks sets the commands skill-sync runs when a skill changes or
+// fails to sync, unless the command gives --on-change or --on-error
+func (t *Terminal) SetSyncHooks(hooks skillsync.Hooks) {
+	t.syncHooks = hooks
+}
+
+// SetSyncStatusFile sets the skill-sync heartbeat file shown by 'server'
+func (t *Terminal) SetSyncStatusFile(path string) {
+	t.syncStatusFile = path
+}
+
+// SetDefaultGroup sets the group config-get and config-set use when only a dataId is given
+func (t *Terminal) SetDefaultGroup(group string) {
+	t.defaultGroup = group
+}
+
+// SetListDefaults sets the --output of skill-list and the --size of the list
+// commands used when they are omitted; empty and 0 keep table and 20
+func (t *Terminal) SetListDefaults(output string, pageSize int) {
+	t.defaultOutput = output
+	t.defaultPageSize = pageSize
+}
+
+// SetMaxConfigSize sets the size config-set refuses to publish above without
+// --force (see util.PublishCheck)
+func (t *Terminal) SetMaxConfigSize(size int64) {
+	t.maxConfigSize = size
+}
+
+// listOutput returns the default --output of skill-list
+func (t *Terminal) listOutput() string {
+	if t.defaultOutput != "" {
+		return t.defaultOutput
+	}
+	return "table"
+}
+
+// listSize returns the default --size of the list commands
+func (t *Terminal) listSize() int {
+	if t.defaultPageSize > 0 {
+		return t.defaultPageSize
+	}
+	return 20
+}
+
+// use sets session defaults: "use group <name>" (or "-" to clear) and "use namespace <id>"
+func (t *Terminal) use(args []string) {
+	group := t.defaultGroup
+	if group == "" {
+		group = "(none)"
+	}
+	if len(args) == 0 {
+		t.printf("Current Namespace: %s\n", t.client.Namespace)
+		t.printf("Default Group:     %s\n", group)
+		return
+	}
 
-	fmt.Printf("Switched namespace from '%s' to '%s'\n", oldNs, t.client.Namespace)
+	switch {
+	case args[0] == "namespace" && len(args) <= 2:
+		t.namespace(args[1:])
+	case args[0] == "group" && len(args) == 1:
+		t.printf("Default Group: %s\n", group)
+	case args[0] == "group" && len(args) == 2:
+		if args[1] == "-" {
+			t.defaultGroup = ""
+			t.println("Default group cleared")
+		} else {
+			t.defaultGroup = args[1]
+			t.printf("Default group set to '%s'\n", t.defaultGroup)
+		}
+		t.updatePrompt()
+	default:
+		t.printUsage("use group <name> | use group - | use namespace <id>")
+	}
+}
+
+// configArgs returns the dataId and group from config-get/config-set positional
+// arguments, falling back to the default group when only the dataId is given
+func (t *Terminal) configArgs(positional []string) (dataID, group string, ok bool) {
+	switch {
+	case len(positional) == 2:
+		return positional[0], positional[1], true
+	case len(positional) == 1 && t.defaultGroup != "":
+		return positional[0], t.defaultGroup, true
+	}
+	return "", "", false
 }
 
 // listSkills lists all skills
 func (t *Terminal) listSkills(args []string) {
-	// Parse flags
-	var name string
-	var page, size int = 1, 20
-
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if strings.HasPrefix(arg, "--name=") {
-			name = strings.TrimPrefix(arg, "--name=")
-		} else if arg == "--name" && i+1 < len(args) {
-			i++
-			name = args[i]
-		} else if arg == "--name=" && i+1 < len(args) {
-			i++
-			name = args[i]
-		} else if strings.HasPrefix(arg, "--page=") {
-			value := strings.TrimPrefix(arg, "--page=")
-			if value != "" {
-				fmt.Sscanf(value, "%d", &page)
-			}
-		} else if arg == "--page" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &page)
-		} else if arg == "--page=" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &page)
-		} else if strings.HasPrefix(arg, "--size=") {
-			value := strings.TrimPrefix(arg, "--size=")
-			if value != "" {
-				fmt.Sscanf(value, "%d", &size)
-			}
-		} else if arg == "--size" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &size)
-		} else if arg == "--size=" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &size)
-		}
+	var name, tag, output string
+	var page, size int
+	var detail bool
+
+	fs := newFlagSet("skill-list")
+	fs.maxArgs = 0
+	fs.StringVar(&name, "name", "", "Filter by skill name")
+	fs.StringVar(&output, "output", t.listOutput(), "Output format: table, json or csv")
+	fs.BoolVar(&detail, "detail", false, "Show the version and tags from each skill's SKILL.md")
+	fs.StringVar(&tag, "tag", "", "Only show skills of the page with this tag")
+	fs.IntVar(&page, "page", 1, "Page number")
+	fs.IntVar(&size, "size", t.listSize(), "Page size")
+	if _, ok := t.parseFlags(fs, args); !ok {
+		return
+	}
+	if output != "table" && output != "json" && output != "csv" {
+		t.errorf("--output must be table, json or csv")
+		return
 	}
 
-	fmt.Print("\033[90mFetching skills...\033[0m\r")
+	t.print("\033[90mFetching skills...\033[0m\r")
 
 	skills, totalCount, err := t.skillService.ListSkills(name, page, size)
 	if err != nil {
-		fmt.Printf("\033[31mError:\033[0m %v\n", err)
+		t.errorf("%v", err)
+		return
+	}
+
+	// Version, tags and resources are only in each skill's files
+	var details []skill.SkillDetail
+	if detail || tag != "" {
+		names := make([]string, len(skills))
+		for i, s := range skills {
+			names[i] = s.Name
+		}
+		details = t.skillDetails.get(names)
+	}
+	if tag != "" {
+		skills, details = skill.FilterByTag(skills, details, tag)
+	}
+	if output == "json" {
+		t.print("\033[K")
+		data, err := json.MarshalIndent(map[string]any{"totalCount": totalCount, "skills": skill.ListEntries(skills, details)}, "", "  ")
+		if err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		t.println(string(data))
 		return
 	}
+	if output == "csv" {
+		t.print("\033[K")
+		header, rows := skill.ListCSV(skill.ListEntries(skills, details), details != nil)
+		if err := util.WriteCSV(t.stdout(), header, rows); err != nil {
+			t.errorf("%v", err)
+		}
+		return
+	}
+	if tag != "" {
+		if len(skills) == 0 {
+			t.print("\033[K")
+			t.printf("\033[33mNo skills tagged %s on page %d\033[0m\n", tag, page)
+			return
+		}
+	}
+	t.setRows(len(skills))
 
-	fmt.Print("\033[K") // Clear line
+	t.print("\033[K") // Clear line
 
 	if len(skills) == 0 {
 		totalPages := (totalCount + size - 1) / size
 		if totalPages == 0 {
-			fmt.Println("\033[33mNo skills found\033[0m")
+			t.println("\033[33mNo skills found\033[0m")
+			t.printNamespaceHint(nil)
 		} else {
-			fmt.Printf("\033[33mPage %d is out of range\033[0m \033[90m(Total: %d items, Total pages: %d)\033[0m\n", page, totalCount, totalPages)
+			t.printf("\033[33mPage %d is out of range\033[0m \033[90m(Total: %d items, Total pages: %d)\033[0m\n", page, totalCount, totalPages)
 		}
 		return
 	}
 
-	fmt.Printf("\n\033[1;36mSkill List\033[0m \033[90m(Page: %d/%d, Total: %d)\033[0m\n", page, (totalCount+size-1)/size, totalCount)
-	fmt.Println("\033[36m═══════════════════════════════════════════════════════════════════════════════\033[0m")
+	t.printf("\n\033[1;36mSkill List\033[0m \033[90m(Page: %d/%d, Total: %d)\033[0m\n", page, (totalCount+size-1)/size, totalCount)
+	t.println("\033[36m═══════════════════════════════════════════════════════════════════════════════\033[0m")
+	if detail {
+		t.printf("     \033[1m%-28s %-13s %s\033[0m\n", "NAME", "RESOURCES", "DESCRIPTION")
+	}
 	for i, skill := range skills {
+		if detail {
+			resources := fmt.Sprintf("%-13d", details[i].Resources)
+			if details[i].Err != nil {
+				resources = fmt.Sprintf("\033[33m%-13s\033[0m", "(unavailable)")
+			}
+			t.printf("\033[90m%3d.\033[0m \033[32m%s\033[0m %s \033[90m%s\033[0m\n", (page-1)*size+i+1, util.PadRight(skill.Name, 28), resources, truncateDesc(skill.Description, detailDescLimit))
+			if details[i].Info != nil && details[i].Info.Details() != "" {
+				t.printf("     \033[90m%s\033[0m\n", details[i].Info.Details())
+			}
+			continue
+		}
 		if skill.Description != "" {
 			desc := truncateDesc(skill.Description, defaultDescLimit)
-			fmt.Printf("\033[90m%3d.\033[0m \033[32m%s\033[0m \033[90m- %s\033[0m\n", (page-1)*size+i+1, skill.Name, desc)
+			t.printf("\033[90m%3d.\033[0m \033[32m%s\033[0m \033[90m- %s\033[0m\n", (page-1)*size+i+1, skill.Name, desc)
 		} else {
-			fmt.Printf("\033[90m%3d.\033[0m \033[32m%s\033[0m\n", (page-1)*size+i+1, skill.Name)
+			t.printf("\033[90m%3d.\033[0m \033[32m%s\033[0m\n", (page-1)*size+i+1, skill.Name)
 		}
 	}
 }
@@ -504,201 +1320,292 @@ func (t *Terminal) listSkills(args []string) {
 // getSkill downloads one or more skills
 func (t *Terminal) getSkill(args []string) {
 	if len(args) == 0 {
-		fmt.Println("\033[31mUsage:\033[0m skill-get <skillName> [skillName2...]")
+		t.printUsage("skill-get <skillName> [skillName2...]")
 		return
 	}
 
-	// Parse flags from args
-	var skillNames []string
 	var outputDir string
 	var version, label string
-
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		
-		if arg == "--version" && i+1 < len(args) {
-			i++
-			version = args[i]
-		} else if strings.HasPrefix(arg, "--version=") {
-			version = strings.TrimPrefix(arg, "--version=")
-		} else if arg == "-o" && i+1 < len(args) {
-			i++
-			outputDir = args[i]
-		} else if strings.HasPrefix(arg, "-o=") {
-			outputDir = strings.TrimPrefix(arg, "-o=")
-		} else if arg == "--label" && i+1 < len(args) {
-			i++
-			label = args[i]
-		} else if strings.HasPrefix(arg, "--label=") {
-			label = strings.TrimPrefix(arg, "--label=")
-		} else if strings.HasPrefix(arg, "-") {
-			// Unknown flag, skip
-			continue
-		} else {
-			// Positional argument (skill name)
-			skillNames = append(skillNames, arg)
-		}
+	var force, preserveExec, allowPartial bool
+	var concurrency int
+	var check skill.UniformCheck
+
+	fs := newFlagSet("skill-get")
+	fs.StringVarP(&outputDir, "output", "o", "", "Output directory")
+	fs.StringVar(&version, "version", "", "Specific version to download")
+	fs.StringVar(&label, "label", "", "Route label to resolve version")
+	fs.BoolVar(&force, "force", false, "Overwrite an existing skill directory without asking")
+	fs.BoolVar(&preserveExec, "preserve-exec", true, "Make scripts executable")
+	fs.BoolVar(&allowPartial, "allow-partial", false, "Install a skill even if some of its files had to be skipped")
+	fs.IntVar(&concurrency, "concurrency", skill.DefaultDownloadConcurrency, "How many skills to download at once")
+	fs.IntVar(&check.Retries, "uniform-retries", skill.DefaultUniformCheck.Retries, "How often to re-read a skill whose files disagree on their upload")
+	fs.DurationVar(&check.Delay, "uniform-retry-delay", skill.DefaultUniformCheck.Delay, "Wait before the first re-read, doubled for each next one")
+	fs.BoolVar(&check.Skip, "skip-uniform-check", false, "Install a skill even if its files disagree on their upload")
+	skillNames, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
 	}
 
 	if len(skillNames) == 0 {
-		fmt.Println("\033[31mError:\033[0m no skill names specified")
+		t.errorf("no skill names specified")
 		return
 	}
 
-	// Default output directory
-	if outputDir == "" {
-		homeDir, homeErr := os.UserHomeDir()
-		if homeErr != nil {
-			fmt.Printf("\033[31mError:\033[0m %v\n", homeErr)
-			return
-		}
-		outputDir = filepath.Join(homeDir, ".skills")
-	} else {
-		// Expand ~ to home directory
-		if strings.HasPrefix(outputDir, "~/") {
-			homeDir, homeErr := os.UserHomeDir()
-			if homeErr != nil {
-				fmt.Printf("\033[31mError:\033[0m %v\n", homeErr)
-				return
-			}
-			outputDir = filepath.Join(homeDir, outputDir[2:])
-		} else if outputDir == "~" {
-			homeDir, homeErr := os.UserHomeDir()
-			if homeErr != nil {
-				fmt.Printf("\033[31mError:\033[0m %v\n", homeErr)
-				return
-			}
-			outputDir = homeDir
-		}
+	outputDir, dirErr := t.resolveSkillsDir(outputDir)
+	if dirErr != nil {
+		t.errorf("%v", dirErr)
+		return
 	}
 
 	// Track results
-	var successCount, failCount int
+	var successCount, skipCount, failCount int
 	var failedSkills []string
-	var err error
 
-	// Process each skill
-	for i, skillName := range skillNames {
+	// Download concurrently, then confirm and extract in order
+	if len(skillNames) == 1 {
+		t.printf("\033[90mDownloading skill: \033[33m%s\033[90m...\033[0m\n", skillNames[0])
+	} else {
+		t.printf("\033[90mDownloading %d skills...\033[0m\n", len(skillNames))
+	}
+	t.skillService.SetUniformCheck(check)
+	defer t.skillService.SetUniformCheck(skill.DefaultUniformCheck)
+	downloads := t.skillService.DownloadSkills(skillNames, version, label, concurrency)
+	for i, download := range downloads {
+		skillName, archive, err := download.Name, download.Archive, download.Err
 		if len(skillNames) > 1 {
-			fmt.Printf("\n\033[90m[%d/%d] \033[0m", i+1, len(skillNames))
-		}
-		fmt.Printf("\033[90mDownloading skill: \033[33m%s\033[90m...\033[0m\n", skillName)
-
-		err = t.skillService.GetSkill(skillName, outputDir, version, label)
-		if err != nil {
-			fmt.Printf("\033[31mError:\033[0m failed to download skill '%s': %v\n", skillName, err)
+			t.printf("\n\033[90m[%d/%d] \033[33m%s\033[0m\n", i+1, len(skillNames), skillName)
+		}
+		proceed := false
+		if err == nil {
+			archive.PreserveExec = preserveExec
+			archive.KeepUnchanged = !force
+			archive.AllowPartial = allowPartial
+			proceed, err = t.confirmSkillOverwrite(archive, outputDir, force)
+		}
+		var result skill.ExtractResult
+		if err == nil && proceed {
+			result, err = archive.Extract(outputDir)
+		}
+		switch {
+		case err != nil:
+			t.errorf("failed to download skill '%s': %v", skillName, err)
 			failCount++
 			failedSkills = append(failedSkills, skillName)
-		} else {
-			fmt.Printf("\033[32mSkill downloaded successfully!\033[0m\n")
-			fmt.Printf("  \033[90mLocation:\033[0m %s/%s\n", outputDir, skillName)
+		case !proceed:
+			t.println("\033[90mSkipped, nothing written\033[0m")
+			skipCount++
+		default:
+			t.printf("\033[32mSkill downloaded successfully!\033[0m\n")
+			t.printf("  \033[90mLocation:\033[0m %s/%s\n", outputDir, skillName)
+			written := fmt.Sprintf("%d files, %d bytes", result.Files, result.Bytes)
+			if result.Unchanged > 0 {
+				written += fmt.Sprintf(" (%d unchanged)", result.Unchanged)
+			}
+			t.printf("  \033[90mWritten:\033[0m %s\n", written)
+			for _, s := range result.Skipped {
+				t.printf("  \033[33mSkipped:\033[0m %s (%s)\n", s.Name, s.Reason)
+			}
 			successCount++
 		}
 	}
 
 	// Summary for multiple skills
 	if len(skillNames) > 1 {
-		fmt.Println()
-		fmt.Println("\033[36m========== Summary ==========\033[0m")
-		fmt.Printf("Total: %d | \033[32mSuccess:\033[0m %d | \033[31mFailed:\033[0m %d\n", len(skillNames), successCount, failCount)
+		t.println()
+		t.println("\033[36m========== Summary ==========\033[0m")
+		t.printf("Total: %d | \033[32mSuccess:\033[0m %d | \033[33mSkipped:\033[0m %d | \033[31mFailed:\033[0m %d\n", len(skillNames), successCount, skipCount, failCount)
 		if failCount > 0 {
-			fmt.Printf("Failed skills: \033[31m%s\033[0m\n", strings.Join(failedSkills, ", "))
+			t.printf("Failed skills: \033[31m%s\033[0m\n", strings.Join(failedSkills, ", "))
 		}
 	}
 }
 
-// uploadSkill uploads a skill
-func (t *Terminal) uploadSkill(args []string) {
-	if len(args) == 0 {
-		fmt.Println("Usage: skill-upload <skillPath> or skill-upload --all <folder>")
+// confirmSkillOverwrite reports how many files an existing skill directory would
+// lose and asks before continuing, unless force is set
+func (t *Terminal) confirmSkillOverwrite(archive *skill.SkillArchive, outputDir string, force bool) (bool, error) {
+	skillPath := filepath.Join(outputDir, archive.Name)
+	if _, err := os.Stat(skillPath); os.IsNotExist(err) || force {
+		return true, nil
+	}
+	existing, err := archive.Existing(outputDir)
+	if err != nil {
+		return false, err
+	}
+	stale, err := archive.Stale(outputDir)
+	if err != nil {
+		return false, err
+	}
+	t.printf("\033[33m%s already exists:\033[0m %d of %d files will be replaced", skillPath, len(existing), archive.FileCount())
+	if len(stale) > 0 {
+		t.printf(", %d files not in the skill will be removed", len(stale))
+	}
+	t.println()
+	ok, err := t.confirm("Overwrite?", true)
+	if err != nil {
+		return false, fmt.Errorf("%s already exists, use --force to overwrite (%w)", skillPath, err)
+	}
+	return ok, nil
+}
+
+// exportSkill writes a skill to a zip, or every skill into a directory of zips
+// with --all
+func (t *Terminal) exportSkill(args []string) {
+	var output, version, label string
+	var all bool
+	var concurrency int
+
+	fs := newFlagSet("skill-export")
+	fs.maxArgs = 1
+	fs.StringVarP(&output, "output", "o", "", "Zip file, or directory with --all")
+	fs.BoolVar(&all, "all", false, "Export every skill into one directory, with a manifest.json")
+	fs.StringVar(&version, "version", "", "Specific version to export")
+	fs.StringVar(&label, "label", "", "Route label to resolve version")
+	fs.IntVar(&concurrency, "concurrency", skill.DefaultDownloadConcurrency, "With --all, how many skills to download at once")
+	names, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	if all == (len(names) == 1) {
+		t.printUsage("skill-export <skillName> [-o file.zip] or skill-export --all [-o dir]")
+		return
+	}
+	if all && (version != "" || label != "") {
+		t.errorf("--version and --label cannot be used with --all")
 		return
 	}
+	stamp := time.Now().Format("20060102")
 
-	// Check for --all flag in any position
-	allFlagIndex := -1
-	folderPath := ""
-	for i, arg := range args {
-		if arg == "--all" {
-			allFlagIndex = i
-			// Get folder path from next argument or previous argument
-			if i+1 < len(args) {
-				folderPath = args[i+1]
-			}
-			break
+	if all {
+		if output == "" {
+			output = "skills-" + stamp
 		}
+		allNames, err := t.skillService.AllSkillNames()
+		if err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		t.printf("\033[90mExporting %d skills to %s...\033[0m\n", len(allNames), output)
+		m, err := t.skillService.ExportSkills(allNames, output, concurrency)
+		if err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		for _, exported := range m.Skills {
+			t.printf("  \033[33m%s\033[0m -> %s \033[90m(%d files, %d bytes)\033[0m\n", exported.Name, exported.File, exported.Files, exported.Bytes)
+		}
+		for _, failure := range m.Failed {
+			t.errorf("failed to export skill '%s': %s", failure.Name, failure.Error)
+		}
+		t.printf("\033[32mExported:\033[0m %d | \033[31mFailed:\033[0m %d | \033[90mManifest:\033[0m %s\n", len(m.Skills), len(m.Failed), filepath.Join(output, skill.ExportManifestFile))
+		return
 	}
 
-	// If --all found but no folder after it, check if folder is before --all
-	if allFlagIndex >= 0 && folderPath == "" {
-		if allFlagIndex > 0 {
-			folderPath = args[allFlagIndex-1]
-		}
+	name := names[0]
+	if output == "" {
+		output = fmt.Sprintf("%s-%s.zip", name, stamp)
+	}
+	t.printf("\033[90mExporting skill: \033[33m%s\033[90m...\033[0m\n", name)
+	archive, err := t.skillService.DownloadSkill(name, version, label)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	exported, err := archive.Export(output)
+	if err != nil {
+		t.errorf("%v", err)
+		return
 	}
+	t.printf("\033[32mSkill exported successfully!\033[0m\n")
+	t.printf("  \033[90mFile:\033[0m %s\n", output)
+	if exported.Version != "" {
+		t.printf("  \033[90mVersion:\033[0m %s\n", exported.Version)
+	}
+	t.printf("  \033[90mWritten:\033[0m %d files, %d bytes\n", exported.Files, exported.Bytes)
+	t.printf("  \033[90mSHA-256:\033[0m %s\n", exported.SHA256)
+}
 
-	if allFlagIndex >= 0 {
-		if folderPath == "" {
-			fmt.Println("Error: folder path required for --all flag")
-			fmt.Println("Usage: skill-upload --all <folder> or skill-upload <folder> --all")
-			return
+// uploadSkill uploads a skill
+func (t *Terminal) uploadSkill(args []string) {
+	var all, viaConfig bool
+	var version string
+
+	fs := newFlagSet("skill-publish")
+	fs.maxArgs = 1
+	fs.BoolVar(&all, "all", false, "Publish all skills in the directory")
+	fs.BoolVar(&viaConfig, "via-config", false, "Publish through the config API, for servers without the skill upload API")
+	fs.StringVar(&version, "version", "", "Record this version in the uploaded SKILL.md")
+	paths, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	if all && version != "" {
+		t.errorf("--version cannot be used with --all")
+		return
+	}
+
+	if len(paths) == 0 {
+		if all {
+			t.errorf("folder path required for --all flag")
 		}
-		t.uploadAllSkills(folderPath)
+		t.printUsage("skill-publish <skillPath> or skill-publish --all <folder>")
+		return
+	}
+
+	if all {
+		t.uploadAllSkills(paths[0], viaConfig)
 		return
 	}
 
 	// Single skill upload
-	skillPath := args[0]
+	skillPath := paths[0]
 
-	// Expand ~ to home directory
-	if strings.HasPrefix(skillPath, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		skillPath = filepath.Join(homeDir, skillPath[2:])
-	} else if skillPath == "~" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		skillPath = homeDir
+	skillPath, err := t.resolvePath(skillPath)
+	if err != nil {
+		t.errorf("resolve path: %v", err)
+		return
 	}
 
-	fmt.Printf("Uploading skill: %s...\n", skillPath)
+	t.printf("Uploading skill: %s...\n", skillPath)
 
-	err := t.skillService.UploadSkill(skillPath)
+	upload := t.skillService.UploadSkillVersion
+	if viaConfig {
+		upload = t.skillService.UploadSkillViaConfig
+	}
+	result, err := upload(skillPath, version)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		t.errorf("%v", err)
 		return
 	}
 
-	fmt.Printf("Skill uploaded successfully!\n")
+	t.skillCache.invalidate()
+	t.skillDetails.invalidate()
+	t.printf("Skill uploaded successfully!\n")
+	t.printUploadResult(result)
+}
+
+// printUploadResult prints the uniformId the server assigned and the files it
+// did not accept
+func (t *Terminal) printUploadResult(result *skill.UploadResult) {
+	if result.UniformID != "" {
+		t.printf("  \033[90mUniform ID:\033[0m %s\n", result.UniformID)
+	}
+	for _, warning := range result.Warnings {
+		t.printf("  \033[33mWarning:\033[0m %s\n", warning)
+	}
 }
 
 // uploadAllSkills uploads all skills in a directory
-func (t *Terminal) uploadAllSkills(folderPath string) {
-	// Expand ~ to home directory
-	if strings.HasPrefix(folderPath, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		folderPath = filepath.Join(homeDir, folderPath[2:])
-	} else if folderPath == "~" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		folderPath = homeDir
+func (t *Terminal) uploadAllSkills(folderPath string, viaConfig bool) {
+	folderPath, err := t.resolvePath(folderPath)
+	if err != nil {
+		t.errorf("resolve path: %v", err)
+		return
 	}
 
 	// List subdirectories
 	entries, err := os.ReadDir(folderPath)
 	if err != nil {
-		fmt.Printf("Error reading directory: %v\n", err)
+		t.errorf("read directory: %v", err)
 		return
 	}
 
@@ -716,122 +1623,119 @@ func (t *Terminal) uploadAllSkills(folderPath string) {
 	}
 
 	if len(skillDirs) == 0 {
-		fmt.Println("No skills found (directories with SKILL.md)")
+		t.println("No skills found (directories with SKILL.md)")
 		return
 	}
 
-	fmt.Printf("Found %d skills:\n", len(skillDirs))
+	t.printf("Found %d skills:\n", len(skillDirs))
 	for _, name := range skillDirs {
-		fmt.Printf("  - %s\n", name)
+		t.printf("  - %s\n", name)
 	}
-	fmt.Println()
+	t.println()
 
 	successCount := 0
 	failedCount := 0
 
 	for i, skillName := range skillDirs {
-		fmt.Println(strings.Repeat("=", 80))
-		fmt.Printf("[%d/%d] Uploading skill: %s\n", i+1, len(skillDirs), skillName)
-		fmt.Println(strings.Repeat("=", 80))
+		t.println(strings.Repeat("=", 80))
+		t.printf("[%d/%d] Uploading skill: %s\n", i+1, len(skillDirs), skillName)
+		t.println(strings.Repeat("=", 80))
 
 		skillPath := filepath.Join(folderPath, skillName)
-		err := t.skillService.UploadSkill(skillPath)
+		upload := t.skillService.UploadSkillVersion
+		if viaConfig {
+			upload = t.skillService.UploadSkillViaConfig
+		}
+		result, err := upload(skillPath, "")
 		if err != nil {
-			fmt.Printf("Upload failed: %v\n", err)
+			t.printf("Upload failed: %v\n", err)
+			t.fail(err)
 			failedCount++
 		} else {
-			fmt.Printf("Upload successful!\n")
+			t.printf("Upload successful!\n")
+			t.printUploadResult(result)
 			successCount++
 		}
-		fmt.Println()
+		t.println()
+	}
+	if successCount > 0 {
+		t.skillCache.invalidate()
+		t.skillDetails.invalidate()
 	}
 
 	// Summary
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Println("Batch Upload Complete")
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Printf("Success: %d\n", successCount)
+	t.println(strings.Repeat("=", 80))
+	t.println("Batch Upload Complete")
+	t.println(strings.Repeat("=", 80))
+	t.printf("Success: %d\n", successCount)
 	if failedCount > 0 {
-		fmt.Printf("Failed: %d\n", failedCount)
+		t.printf("Failed: %d\n", failedCount)
 	}
-	fmt.Printf("Total: %d\n", len(skillDirs))
-	fmt.Println()
-	fmt.Println("Tip: Use 'skill-list' to view all uploaded skills")
+	t.printf("Total: %d\n", len(skillDirs))
+	t.println()
+	t.println("Tip: Use 'skill-list' to view all uploaded skills")
 }
 
 // listConfigs lists all configurations
 func (t *Terminal) listConfigs(args []string) {
-	// Parse flags
-	var dataID, group string
-	var page, size int = 1, 20
-
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if strings.HasPrefix(arg, "--data-id=") {
-			dataID = strings.TrimPrefix(arg, "--data-id=")
-		} else if arg == "--data-id" && i+1 < len(args) {
-			i++
-			dataID = args[i]
-		} else if arg == "--data-id=" && i+1 < len(args) {
-			i++
-			dataID = args[i]
-		} else if strings.HasPrefix(arg, "--group=") {
-			group = strings.TrimPrefix(arg, "--group=")
-		} else if arg == "--group" && i+1 < len(args) {
-			i++
-			group = args[i]
-		} else if arg == "--group=" && i+1 < len(args) {
-			i++
-			group = args[i]
-		} else if strings.HasPrefix(arg, "--page=") {
-			value := strings.TrimPrefix(arg, "--page=")
-			if value != "" {
-				fmt.Sscanf(value, "%d", &page)
-			}
-		} else if arg == "--page" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &page)
-		} else if arg == "--page=" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &page)
-		} else if strings.HasPrefix(arg, "--size=") {
-			value := strings.TrimPrefix(arg, "--size=")
-			if value != "" {
-				fmt.Sscanf(value, "%d", &size)
-			}
-		} else if arg == "--size" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &size)
-		} else if arg == "--size=" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &size)
-		}
+	var dataID, group, output string
+	var page, size int
+
+	fs := newFlagSet("config-list")
+	fs.maxArgs = 0
+	fs.StringVar(&dataID, "data-id", "", "Filter by data ID")
+	fs.StringVar(&group, "group", "", "Filter by group")
+	fs.IntVar(&page, "page", 1, "Page number")
+	fs.IntVar(&size, "size", t.listSize(), "Page size")
+	fs.StringVar(&output, "output", "table", "Output format: table or csv")
+	if _, ok := t.parseFlags(fs, args); !ok {
+		return
+	}
+	if output != "table" && output != "csv" {
+		t.errorf("--output must be table or csv")
+		return
 	}
 
-	fmt.Print("\033[90mFetching configurations...\033[0m\r")
+	t.print("\033[90mFetching configurations...\033[0m\r")
 
 	configs, err := t.client.ListConfigs(dataID, group, "", page, size)
 	if err != nil {
-		fmt.Printf("\033[31mError:\033[0m %v\n", err)
+		t.errorf("%v", err)
+		t.printNamespaceHint(err)
+		return
+	}
+	t.print("\033[K") // Clear line
+	if output == "csv" {
+		header, rows := configs.CSV()
+		if err := util.WriteCSV(t.stdout(), header, rows); err != nil {
+			t.errorf("%v", err)
+		}
 		return
 	}
+	t.setRows(len(configs.PageItems))
 
-	fmt.Print("\033[K") // Clear line
+	// The server's pagination wins: it may cap the page size
+	totalPages := configs.TotalPages(size)
+	if effective := configs.PageSize(size); effective < size {
+		t.printf("\033[33mThe server caps the page size at %d (requested %d)\033[0m\n", effective, size)
+		size = effective
+	}
+	page = configs.Page(page)
 
 	if len(configs.PageItems) == 0 {
-		totalPages := (configs.TotalCount + size - 1) / size
 		if totalPages == 0 {
-			fmt.Println("\033[33mNo configurations found\033[0m")
+			t.println("\033[33mNo configurations found\033[0m")
+			t.printNamespaceHint(nil)
 		} else {
-			fmt.Printf("\033[33mPage %d is out of range\033[0m \033[90m(Total: %d items, Total pages: %d)\033[0m\n", page, configs.TotalCount, totalPages)
+			t.printf("\033[33mPage %d is out of range\033[0m \033[90m(Total: %d items, Total pages: %d)\033[0m\n", page, configs.TotalCount, totalPages)
 		}
 		return
 	}
 
-	fmt.Printf("\n\033[1;36mConfiguration List\033[0m \033[90m(Page: %d/%d, Total: %d)\033[0m\n", page, (configs.TotalCount+size-1)/size, configs.TotalCount)
-	fmt.Println("\033[36m═══════════════════════════════════════════════════════════════\033[0m")
-	fmt.Printf("\033[90m%-5s %-30s %-20s %-10s\033[0m\n", "No.", "Data ID", "Group", "Type")
-	fmt.Println("\033[90m───────────────────────────────────────────────────────────────\033[0m")
+	t.printf("\n\033[1;36mConfiguration List\033[0m \033[90m(Page: %d/%d, Size: %d, Total: %d)\033[0m\n", page, totalPages, size, configs.TotalCount)
+	t.println("\033[36m═══════════════════════════════════════════════════════════════\033[0m")
+	t.printf("\033[90m%-5s %-30s %-20s %-10s\033[0m\n", "No.", "Data ID", "Group", "Type")
+	t.println("\033[90m───────────────────────────────────────────────────────────────\033[0m")
 
 	for i, config := range configs.PageItems {
 		groupName := config.GroupName
@@ -839,70 +1743,98 @@ func (t *Terminal) listConfigs(args []string) {
 			groupName = config.Group
 		}
 
-		dataID := config.DataID
-		if len(dataID) > 28 {
-			dataID = dataID[:25] + "..."
-		}
-
-		if len(groupName) > 18 {
-			groupName = groupName[:15] + "..."
-		}
+		// Widths are in terminal columns, so CJK names stay aligned
+		dataID := util.PadRight(util.Truncate(config.DataID, 28, "..."), 30)
+		groupName = util.PadRight(util.Truncate(groupName, 18, "..."), 20)
 
-		fmt.Printf("%-5d \033[32m%-30s\033[0m \033[33m%-20s\033[0m \033[90m%-10s\033[0m\n",
+		t.printf("%-5d \033[32m%s\033[0m \033[33m%s\033[0m \033[90m%-10s\033[0m\n",
 			(page-1)*size+i+1, dataID, groupName, config.Type)
 	}
 }
 
 // setConfig publishes a configuration (interactive mode: requires --file/-f)
 func (t *Terminal) setConfig(args []string) {
-	var dataID, group, filePath string
-
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if arg == "-f" || arg == "--file" {
-			if i+1 < len(args) {
-				i++
-				filePath = args[i]
-			}
-			continue
-		}
-		if dataID == "" {
-			dataID = arg
-		} else if group == "" {
-			group = arg
-		}
+	var filePath string
+	var edit, fromClipboard, noValidate bool
+	check := util.PublishCheck{MaxSize: t.maxConfigSize}
+
+	fs := newFlagSet("config-set")
+	fs.maxArgs = 2
+	fs.StringVarP(&filePath, "file", "f", "", "Path to config file")
+	fs.BoolVar(&edit, "edit", false, "Edit the current content in $EDITOR")
+	fs.BoolVar(&fromClipboard, "from-clipboard", false, "Read the content from the system clipboard and confirm it")
+	fs.BoolVar(&check.Force, "force", false, "Publish content larger than defaults.maxSize")
+	fs.BoolVar(&check.AllowBinary, "allow-binary", false, "Publish content that looks binary without asking")
+	fs.BoolVar(&check.AllowShrink, "allow-shrink", false, "Publish content much smaller than the current content without asking")
+	fs.BoolVar(&noValidate, "no-validate", false, "Publish yaml, json, properties or xml content that does not parse")
+	positional, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	dataID, group, ok := t.configArgs(positional)
+	if !ok {
+		t.printUsage("config-set <data-id> <group> [-f <file> | --edit | --from-clipboard]")
+		t.println("\033[90mWithout -f: enter content in next lines, empty line to finish.\033[0m")
+		return
 	}
 
-	if dataID == "" || group == "" {
-		fmt.Println("\033[31mUsage:\033[0m config-set <data-id> <group> [-f <file>]")
-		fmt.Println("\033[90mWithout -f: enter content in next lines, empty line to finish.\033[0m")
+	if edit {
+		if filePath != "" || fromClipboard {
+			t.errorf("--edit cannot be combined with --file or --from-clipboard")
+			return
+		}
+		t.editConfig(dataID, group, true, noValidate)
 		return
 	}
 
 	var content string
-	if filePath != "" {
-		data, err := os.ReadFile(filePath)
+	if fromClipboard {
+		if filePath != "" {
+			t.errorf("--from-clipboard cannot be combined with --file")
+			return
+		}
+		var err error
+		if content, err = clipboard.Read(); err != nil {
+			t.errorf("%v", err)
+			return
+		}
+	} else if filePath != "" {
+		path, err := t.resolvePath(filePath)
+		if err != nil {
+			t.errorf("resolve path: %v", err)
+			return
+		}
+		data, err := os.ReadFile(path)
 		if err != nil {
-			fmt.Printf("\033[31mError:\033[0m read file %s: %v\n", filePath, err)
+			t.errorf("read file %s: %v", path, err)
 			return
 		}
 		content = string(data)
 	} else {
 		// Read content from terminal: multi-line until empty line or single "."
-		fmt.Println("\033[90mEnter config content. Finish with a blank line or a single dot line.\033[0m")
-		fmt.Println("\033[90m  (Type your content, then press Enter, then press Enter again — or type \".\" and Enter)\033[0m")
+		t.println("\033[90mEnter config content. Finish with a blank line or a single dot line.\033[0m")
+		t.println("\033[90m  (Type your content, then press Enter, then press Enter again — or type \".\" and Enter)\033[0m")
 		var lines []string
+		t.pendingInput = "config-set input"
+		defer func() { t.pendingInput = "" }()
 		for {
-			line, err := t.rl.Readline()
+			line, err := t.input.Readline()
 			if err == readline.ErrInterrupt {
-				fmt.Println("\033[33mCancelled\033[0m")
+				t.println("\033[33mCancelled\033[0m")
 				return
 			}
+			// Ctrl+D asks to quit like at the prompt; at the end of a script it ends the content
+			if err == io.EOF && t.rl != nil {
+				if t.exit(false) {
+					return
+				}
+				continue
+			}
 			if err == io.EOF {
 				break
 			}
 			if err != nil {
-				fmt.Printf("\033[31mError:\033[0m %v\n", err)
+				t.errorf("%v", err)
 				return
 			}
 			trimmed := strings.TrimSpace(line)
@@ -911,50 +1843,268 @@ func (t *Terminal) setConfig(args []string) {
 			}
 			lines = append(lines, line)
 		}
-		content = strings.Join(lines, "\n")
+		// Every line typed ends with Enter, so the content ends with a newline
+		if len(lines) > 0 {
+			content = strings.Join(lines, "\n") + "\n"
+		}
 	}
 
 	if content == "" {
-		fmt.Println("\033[31mError:\033[0m config content is empty (use -f <file> or type content)")
+		t.errorf("config content is empty (use -f <file> or type content)")
+		return
+	}
+	if !noValidate && !t.validateConfigContent(dataID, filePath, content) {
 		return
 	}
+	warnings, ok := t.checkConfigContent(check, dataID, group, content)
+	if !ok {
+		return
+	}
+	if fromClipboard || len(warnings) > 0 {
+		if fromClipboard {
+			t.println("\033[1mClipboard content:\033[0m")
+			for _, line := range clipboard.Preview(content) {
+				t.printf("\033[90m%s\033[0m\n", line)
+			}
+		}
+		for _, warning := range warnings {
+			t.printf("\033[33m%s\033[0m\n", i18n.T("Warning: %s", warning))
+		}
+		ok, err := t.confirm(fmt.Sprintf("Publish this to %s (%s)?", dataID, group), true)
+		if err != nil {
+			t.errorf("%v", err)
+			return
+		}
+		if !ok {
+			t.println("\033[33mCancelled\033[0m")
+			return
+		}
+	}
 
-	fmt.Printf("\033[90mPublishing config: \033[33m%s\033[90m (\033[33m%s\033[90m)...\033[0m\n", dataID, group)
+	t.printf("\033[90mPublishing config: \033[33m%s\033[90m (\033[33m%s\033[90m)...\033[0m\n", dataID, group)
 	if err := t.client.PublishConfig(dataID, group, content); err != nil {
-		fmt.Printf("\033[31mError:\033[0m %v\n", err)
+		t.errorf("%v", err)
+		return
+	}
+	t.configCache.invalidate()
+	t.println("\033[32mConfiguration published successfully\033[0m")
+}
+
+// validateConfigContent prints the problems found in content when it does not
+// parse as the type of dataId, or else of the file it was read from, and
+// reports whether there were none
+func (t *Terminal) validateConfigContent(dataID, filePath, content string) bool {
+	typ := lint.DetectType(dataID, "")
+	if typ == "" {
+		typ = lint.DetectType(filePath, "")
+	}
+	problems := lint.Check(typ, content)
+	if len(problems) == 0 {
+		return true
+	}
+	for _, p := range problems {
+		t.printf("\033[31m%s\033[0m\n", p.In(dataID))
+	}
+	t.errorf("content is not valid %s, nothing published (pass --no-validate to publish it anyway)", typ)
+	return false
+}
+
+// checkConfigContent checks content config-set is about to publish: content
+// over the size limit is refused, and warnings are returned for content that
+// looks binary or is much smaller than the current content
+func (t *Terminal) checkConfigContent(check util.PublishCheck, dataID, group, content string) ([]string, bool) {
+	if _, err := check.Check(content, ""); err != nil {
+		t.errorf("%v", err)
+		return nil, false
+	}
+	var previous string
+	if !check.AllowShrink {
+		var err error
+		previous, err = t.client.GetConfig(dataID, group)
+		if err != nil && !errors.Is(err, client.ErrConfigNotFound) {
+			t.errorf("cannot compare with the current content (pass --allow-shrink to publish without comparing): %v", err)
+			return nil, false
+		}
+	}
+	warnings, err := check.Check(content, previous)
+	if err != nil {
+		t.errorf("%v", err)
+		return nil, false
+	}
+	return warnings, true
+}
+
+// editConfigCommand edits a configuration in $EDITOR (config-edit)
+func (t *Terminal) editConfigCommand(args []string) {
+	var create, noValidate bool
+	fs := newFlagSet("config-edit")
+	fs.maxArgs = 2
+	fs.BoolVar(&create, "create", false, "Start from empty content if the config does not exist")
+	fs.BoolVar(&noValidate, "no-validate", false, "Publish yaml, json, properties or xml content that does not parse")
+	positional, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	dataID, group, ok := t.configArgs(positional)
+	if !ok {
+		t.printUsage("config-edit <data-id> <group> [--create] [--no-validate]")
+		return
+	}
+	t.editConfig(dataID, group, create, noValidate)
+}
+
+// editConfig opens the current content in $EDITOR and publishes the result after
+// showing a diff and asking for confirmation; content that does not parse as
+// its type is refused unless noValidate is set. A missing config is only
+// created with create set. The publish fails if the config changed since it
+// was fetched, and the edit is then saved to a file.
+func (t *Terminal) editConfig(dataID, group string, create, noValidate bool) {
+	var current, md5 string
+	config, err := t.client.GetConfigDetail(dataID, group)
+	switch {
+	case errors.Is(err, client.ErrConfigNotFound):
+		if !create {
+			t.errorf("%v; pass --create to create it", err)
+			return
+		}
+	case err != nil:
+		t.errorf("%v", err)
+		return
+	default:
+		current, md5 = config.Content, config.MD5
+	}
+
+	edited, err := editor.Edit(dataID, []byte(current))
+	if err != nil {
+		t.errorf("%v", err)
+		t.println("\033[33mCancelled\033[0m")
+		return
+	}
+	content := string(edited)
+
+	if content == current {
+		t.println("\033[33mNo changes, nothing to publish\033[0m")
+		return
+	}
+	if strings.TrimSpace(content) == "" {
+		t.errorf("config content is empty, nothing published")
+		return
+	}
+	if !noValidate && !t.validateConfigContent(dataID, "", content) {
+		t.keepEdit(dataID, content)
+		return
+	}
+
+	t.printDiff(util.UnifiedDiff(dataID+" (remote)", dataID+" (edited)", current, content))
+	ok, err := t.confirm("Publish these changes?", true)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	if !ok {
+		t.println("\033[33mCancelled\033[0m")
+		return
+	}
+
+	t.printf("\033[90mPublishing config: \033[33m%s\033[90m (\033[33m%s\033[90m)...\033[0m\n", dataID, group)
+	if err := t.client.PublishConfigCAS(dataID, group, content, md5); err != nil {
+		t.errorf("%v", err)
+		t.keepEdit(dataID, content)
 		return
 	}
-	fmt.Println("\033[32mConfiguration published successfully\033[0m")
+	t.configCache.invalidate()
+	t.println("\033[32mConfiguration published successfully\033[0m")
+}
+
+// keepEdit saves an edit that was not published to a file, so it is not lost
+func (t *Terminal) keepEdit(dataID, content string) {
+	if path, err := editor.Keep(dataID, []byte(content)); err == nil {
+		t.printf("\033[90mYour edit is saved in \033[0m%s\n", path)
+	}
+}
+
+// printDiff prints a unified diff with removed lines in red and added lines in green
+func (t *Terminal) printDiff(diff string) {
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			t.printf("\033[1m%s\033[0m\n", line)
+		case strings.HasPrefix(line, "@@"):
+			t.printf("\033[36m%s\033[0m\n", line)
+		case strings.HasPrefix(line, "+"):
+			t.printf("\033[32m%s\033[0m\n", line)
+		case strings.HasPrefix(line, "-"):
+			t.printf("\033[31m%s\033[0m\n", line)
+		default:
+			t.println(line)
+		}
+	}
 }
 
 // getConfig gets configuration content
 func (t *Terminal) getConfig(args []string) {
-	if len(args) < 2 {
-		fmt.Println("\033[31mUsage:\033[0m config-get <data-id> <group>")
+	var compact, pick bool
+
+	fs := newFlagSet("config-get")
+	fs.maxArgs = 2
+	fs.BoolVar(&compact, "compact", false, "Show JSON content as stored instead of pretty-printed")
+	fs.BoolVar(&pick, "pick", false, "Choose the config from a list; the arguments filter it (* matches any text)")
+	positional, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	var dataID, group string
+	if pick {
+		dataIDPattern, groupPattern := "*", t.defaultGroup
+		if len(positional) > 0 {
+			dataIDPattern = positional[0]
+		}
+		if len(positional) > 1 {
+			groupPattern = positional[1]
+		}
+		if groupPattern == "" {
+			groupPattern = "*"
+		}
+		if dataID, group, ok = t.pickConfig(dataIDPattern, groupPattern); !ok {
+			return
+		}
+	} else if dataID, group, ok = t.configArgs(positional); !ok {
+		t.printUsage("config-get <data-id> <group> | config-get --pick [data-id] [group]")
 		return
 	}
 
-	dataID := args[0]
-	group := args[1]
-
-	fmt.Printf("\033[90mFetching config: \033[33m%s\033[90m (\033[33m%s\033[90m)...\033[0m\n\n", dataID, group)
+	if t.redirected == nil {
+		t.printf("\033[90mFetching config: \033[33m%s\033[90m (\033[33m%s\033[90m)...\033[0m\n\n", dataID, group)
+	}
 
-	content, err := t.client.GetConfig(dataID, group)
+	config, err := t.client.GetConfigDetail(dataID, group)
 	if err != nil {
-		fmt.Printf("\033[31mError:\033[0m %v\n", err)
+		t.errorf("%v", err)
+		t.printNamespaceHint(err)
 		return
 	}
 
+	content := config.Content
 	if content == "" {
-		fmt.Println("\033[33mConfiguration not found\033[0m")
+		t.errorf("configuration not found")
+		return
+	}
+
+	// The file or pipe gets the content as stored, unfiltered
+	if t.redirected != nil {
+		t.redirected.writeRaw([]byte(content))
 		return
 	}
 
-	fmt.Println("\033[36m═══════════════════════════════════════\033[0m")
-	fmt.Printf("\033[33mData ID:\033[0m %s\n", dataID)
-	fmt.Printf("\033[33mGroup:\033[0m %s\n", group)
-	fmt.Println("\033[36m═══════════════════════════════════════\033[0m")
-	fmt.Println(content)
+	if highlight.Enabled(os.Stdout) {
+		content = highlight.Format(content, highlight.DetectType(dataID, config.Type, content), compact)
+	}
+
+	t.println("\033[36m═══════════════════════════════════════\033[0m")
+	t.printf("\033[33mData ID:\033[0m %s\n", dataID)
+	t.printf("\033[33mGroup:\033[0m %s\n", group)
+	t.println("\033[36m═══════════════════════════════════════\033[0m")
+	t.println(content)
 }
 
 // Command help methods
@@ -983,9 +2133,16 @@ func (t *Terminal) showConfigSetHelp() {
 	help.ConfigSet.FormatForTerminal()
 }
 
+func (t *Terminal) showConfigEditHelp() {
+	help.ConfigEdit.FormatForTerminal()
+}
+
+func (t *Terminal) showSkillExportHelp() {
+	help.SkillExport.FormatForTerminal()
+}
+
 func (t *Terminal) showSkillSyncHelp() {
-	fmt.Println("\033[33mskill-sync has been removed.\033[0m")
-	fmt.Println("\033[90mUse 'skill-get' to download skills.\033[0m")
+	help.SkillSync.FormatForTerminal()
 }
 
 // AgentSpec command help methods
@@ -1004,58 +2161,42 @@ func (t *Terminal) showAgentSpecPublishHelp() {
 
 // listAgentSpecs lists all agent specs
 func (t *Terminal) listAgentSpecs(args []string) {
-	// Parse flags
 	var name string
-	var page, size int = 1, 20
-
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		if strings.HasPrefix(arg, "--name=") {
-			name = strings.TrimPrefix(arg, "--name=")
-		} else if arg == "--name" && i+1 < len(args) {
-			i++
-			name = args[i]
-		} else if strings.HasPrefix(arg, "--page=") {
-			value := strings.TrimPrefix(arg, "--page=")
-			if value != "" {
-				fmt.Sscanf(value, "%d", &page)
-			}
-		} else if arg == "--page" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &page)
-		} else if strings.HasPrefix(arg, "--size=") {
-			value := strings.TrimPrefix(arg, "--size=")
-			if value != "" {
-				fmt.Sscanf(value, "%d", &size)
-			}
-		} else if arg == "--size" && i+1 < len(args) {
-			i++
-			fmt.Sscanf(args[i], "%d", &size)
-		}
+	var page, size int
+
+	fs := newFlagSet("agentspec-list")
+	fs.maxArgs = 0
+	fs.StringVar(&name, "name", "", "Filter by agent spec name")
+	fs.IntVar(&page, "page", 1, "Page number")
+	fs.IntVar(&size, "size", t.listSize(), "Page size")
+	if _, ok := t.parseFlags(fs, args); !ok {
+		return
 	}
 
-	fmt.Print("\033[90mFetching agent specs...\033[0m\r")
+	t.print("\033[90mFetching agent specs...\033[0m\r")
 
 	specs, totalCount, err := t.agentSpecService.ListAgentSpecs(name, "", page, size)
 	if err != nil {
-		fmt.Printf("\033[31mError:\033[0m %v\n", err)
+		t.errorf("%v", err)
 		return
 	}
+	t.setRows(len(specs))
 
-	fmt.Print("\033[K") // Clear line
+	t.print("\033[K") // Clear line
 
 	if len(specs) == 0 {
 		totalPages := (totalCount + size - 1) / size
 		if totalPages == 0 {
-			fmt.Println("\033[33mNo agent specs found\033[0m")
+			t.println("\033[33mNo agent specs found\033[0m")
+			t.printNamespaceHint(nil)
 		} else {
-			fmt.Printf("\033[33mPage %d is out of range\033[0m \033[90m(Total: %d items, Total pages: %d)\033[0m\n", page, totalCount, totalPages)
+			t.printf("\033[33mPage %d is out of range\033[0m \033[90m(Total: %d items, Total pages: %d)\033[0m\n", page, totalCount, totalPages)
 		}
 		return
 	}
 
-	fmt.Printf("\n\033[1;36mAgentSpec List\033[0m \033[90m(Page: %d/%d, Total: %d)\033[0m\n", page, (totalCount+size-1)/size, totalCount)
-	fmt.Println("\033[36m═══════════════════════════════════════════════════════════════════════════════\033[0m")
+	t.printf("\n\033[1;36mAgentSpec List\033[0m \033[90m(Page: %d/%d, Total: %d)\033[0m\n", page, (totalCount+size-1)/size, totalCount)
+	t.println("\033[36m═══════════════════════════════════════════════════════════════════════════════\033[0m")
 	for i, spec := range specs {
 		enableStr := "\033[32menabled\033[0m"
 		if !spec.Enable {
@@ -1063,9 +2204,9 @@ func (t *Terminal) listAgentSpecs(args []string) {
 		}
 		if spec.Description != nil && *spec.Description != "" {
 			desc := truncateDesc(*spec.Description, defaultDescLimit)
-			fmt.Printf("\033[90m%3d.\033[0m \033[32m%s\033[0m \033[90m- %s\033[0m [%s, \033[90monline:%d\033[0m]\n", (page-1)*size+i+1, spec.Name, desc, enableStr, spec.OnlineCnt)
+			t.printf("\033[90m%3d.\033[0m \033[32m%s\033[0m \033[90m- %s\033[0m [%s, \033[90monline:%d\033[0m]\n", (page-1)*size+i+1, spec.Name, desc, enableStr, spec.OnlineCnt)
 		} else {
-			fmt.Printf("\033[90m%3d.\033[0m \033[32m%s\033[0m [%s, \033[90monline:%d\033[0m]\n", (page-1)*size+i+1, spec.Name, enableStr, spec.OnlineCnt)
+			t.printf("\033[90m%3d.\033[0m \033[32m%s\033[0m [%s, \033[90monline:%d\033[0m]\n", (page-1)*size+i+1, spec.Name, enableStr, spec.OnlineCnt)
 		}
 	}
 }
@@ -1073,44 +2214,24 @@ func (t *Terminal) listAgentSpecs(args []string) {
 // getAgentSpec downloads one or more agent specs
 func (t *Terminal) getAgentSpec(args []string) {
 	if len(args) == 0 {
-		fmt.Println("\033[31mUsage:\033[0m agentspec-get <name> [name2...]")
+		t.printUsage("agentspec-get <name> [name2...]")
 		return
 	}
 
-	// Parse flags from args
-	var specNames []string
 	var outputDir string
 	var version, label string
 
-	for i := 0; i < len(args); i++ {
-		arg := args[i]
-		
-		if arg == "--version" && i+1 < len(args) {
-			i++
-			version = args[i]
-		} else if strings.HasPrefix(arg, "--version=") {
-			version = strings.TrimPrefix(arg, "--version=")
-		} else if arg == "-o" && i+1 < len(args) {
-			i++
-			outputDir = args[i]
-		} else if strings.HasPrefix(arg, "-o=") {
-			outputDir = strings.TrimPrefix(arg, "-o=")
-		} else if arg == "--label" && i+1 < len(args) {
-			i++
-			label = args[i]
-		} else if strings.HasPrefix(arg, "--label=") {
-			label = strings.TrimPrefix(arg, "--label=")
-		} else if strings.HasPrefix(arg, "-") {
-			// Unknown flag, skip
-			continue
-		} else {
-			// Positional argument (spec name)
-			specNames = append(specNames, arg)
-		}
+	fs := newFlagSet("agentspec-get")
+	fs.StringVarP(&outputDir, "output", "o", "", "Output directory")
+	fs.StringVar(&version, "version", "", "Specific version to download")
+	fs.StringVar(&label, "label", "", "Route label to resolve version")
+	specNames, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
 	}
 
 	if len(specNames) == 0 {
-		fmt.Println("\033[31mError:\033[0m no agent spec names specified")
+		t.errorf("no agent spec names specified")
 		return
 	}
 
@@ -1118,27 +2239,17 @@ func (t *Terminal) getAgentSpec(args []string) {
 	if outputDir == "" {
 		homeDir, homeErr := os.UserHomeDir()
 		if homeErr != nil {
-			fmt.Printf("\033[31mError:\033[0m %v\n", homeErr)
+			t.errorf("%v", homeErr)
 			return
 		}
 		outputDir = filepath.Join(homeDir, ".agentspecs")
 	} else {
-		// Expand ~ to home directory
-		if strings.HasPrefix(outputDir, "~/") {
-			homeDir, homeErr := os.UserHomeDir()
-			if homeErr != nil {
-				fmt.Printf("\033[31mError:\033[0m %v\n", homeErr)
-				return
-			}
-			outputDir = filepath.Join(homeDir, outputDir[2:])
-		} else if outputDir == "~" {
-			homeDir, homeErr := os.UserHomeDir()
-			if homeErr != nil {
-				fmt.Printf("\033[31mError:\033[0m %v\n", homeErr)
-				return
-			}
-			outputDir = homeDir
+		resolved, pathErr := t.resolvePath(outputDir)
+		if pathErr != nil {
+			t.errorf("resolve path: %v", pathErr)
+			return
 		}
+		outputDir = resolved
 	}
 
 	// Track results
@@ -1149,124 +2260,90 @@ func (t *Terminal) getAgentSpec(args []string) {
 	// Process each spec
 	for i, specName := range specNames {
 		if len(specNames) > 1 {
-			fmt.Printf("\n\033[90m[%d/%d] \033[0m", i+1, len(specNames))
+			t.printf("\n\033[90m[%d/%d] \033[0m", i+1, len(specNames))
 		}
-		fmt.Printf("\033[90mDownloading agent spec: \033[33m%s\033[90m...\033[0m\n", specName)
+		t.printf("\033[90mDownloading agent spec: \033[33m%s\033[90m...\033[0m\n", specName)
 
 		err = t.agentSpecService.GetAgentSpec(specName, outputDir, version, label)
 		if err != nil {
-			fmt.Printf("\033[31mError:\033[0m failed to download agent spec '%s': %v\n", specName, err)
+			t.errorf("failed to download agent spec '%s': %v", specName, err)
 			failCount++
 			failedSpecs = append(failedSpecs, specName)
 		} else {
-			fmt.Printf("\033[32mAgent spec downloaded successfully!\033[0m\n")
-			fmt.Printf("  \033[90mLocation:\033[0m %s/%s\n", outputDir, specName)
+			t.printf("\033[32mAgent spec downloaded successfully!\033[0m\n")
+			t.printf("  \033[90mLocation:\033[0m %s/%s\n", outputDir, specName)
 			successCount++
 		}
 	}
 
 	// Summary for multiple specs
 	if len(specNames) > 1 {
-		fmt.Println()
-		fmt.Println("\033[36m========== Summary ==========\033[0m")
-		fmt.Printf("Total: %d | \033[32mSuccess:\033[0m %d | \033[31mFailed:\033[0m %d\n", len(specNames), successCount, failCount)
+		t.println()
+		t.println("\033[36m========== Summary ==========\033[0m")
+		t.printf("Total: %d | \033[32mSuccess:\033[0m %d | \033[31mFailed:\033[0m %d\n", len(specNames), successCount, failCount)
 		if failCount > 0 {
-			fmt.Printf("Failed agent specs: \033[31m%s\033[0m\n", strings.Join(failedSpecs, ", "))
+			t.printf("Failed agent specs: \033[31m%s\033[0m\n", strings.Join(failedSpecs, ", "))
 		}
 	}
 }
 
 // publishAgentSpec publishes an agent spec (mirrors skill-publish).
 func (t *Terminal) publishAgentSpec(args []string) {
-	if len(args) == 0 {
-		fmt.Println("Usage: agentspec-publish <agentSpecPath> or agentspec-publish --all <folder>")
-		return
-	}
+	var all bool
 
-	// Check for --all flag in any position
-	allFlagIndex := -1
-	folderPath := ""
-	for i, arg := range args {
-		if arg == "--all" {
-			allFlagIndex = i
-			if i+1 < len(args) {
-				folderPath = args[i+1]
-			}
-			break
-		}
+	fs := newFlagSet("agentspec-publish")
+	fs.maxArgs = 1
+	fs.BoolVar(&all, "all", false, "Publish all agent specs in the directory")
+	paths, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
 	}
 
-	// If --all found but no folder after it, check if folder is before --all
-	if allFlagIndex >= 0 && folderPath == "" {
-		if allFlagIndex > 0 {
-			folderPath = args[allFlagIndex-1]
+	if len(paths) == 0 {
+		if all {
+			t.errorf("folder path required for --all flag")
 		}
+		t.printUsage("agentspec-publish <agentSpecPath> or agentspec-publish --all <folder>")
+		return
 	}
 
-	if allFlagIndex >= 0 {
-		if folderPath == "" {
-			fmt.Println("Error: folder path required for --all flag")
-			fmt.Println("Usage: agentspec-publish --all <folder> or agentspec-publish <folder> --all")
-			return
-		}
-		t.publishAllAgentSpecs(folderPath)
+	if all {
+		t.publishAllAgentSpecs(paths[0])
 		return
 	}
 
 	// Single agent spec publish
-	specPath := args[0]
+	specPath := paths[0]
 
-	// Expand ~ to home directory
-	if strings.HasPrefix(specPath, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		specPath = filepath.Join(homeDir, specPath[2:])
-	} else if specPath == "~" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		specPath = homeDir
+	specPath, err := t.resolvePath(specPath)
+	if err != nil {
+		t.errorf("resolve path: %v", err)
+		return
 	}
 
-	fmt.Printf("Publishing agent spec: %s...\n", specPath)
+	t.printf("Publishing agent spec: %s...\n", specPath)
 
-	err := t.agentSpecService.UploadAgentSpec(specPath)
+	err = t.agentSpecService.UploadAgentSpec(specPath)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
+		t.errorf("%v", err)
 		return
 	}
 
-	fmt.Printf("Agent spec published successfully!\n")
+	t.printf("Agent spec published successfully!\n")
 }
 
 // publishAllAgentSpecs publishes all agent specs in a directory
 func (t *Terminal) publishAllAgentSpecs(folderPath string) {
-	// Expand ~ to home directory
-	if strings.HasPrefix(folderPath, "~/") {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		folderPath = filepath.Join(homeDir, folderPath[2:])
-	} else if folderPath == "~" {
-		homeDir, err := os.UserHomeDir()
-		if err != nil {
-			fmt.Printf("Error getting home directory: %v\n", err)
-			return
-		}
-		folderPath = homeDir
+	folderPath, err := t.resolvePath(folderPath)
+	if err != nil {
+		t.errorf("resolve path: %v", err)
+		return
 	}
 
 	// List subdirectories
 	entries, err := os.ReadDir(folderPath)
 	if err != nil {
-		fmt.Printf("Error reading directory: %v\n", err)
+		t.errorf("read directory: %v", err)
 		return
 	}
 
@@ -1284,54 +2361,54 @@ func (t *Terminal) publishAllAgentSpecs(folderPath string) {
 	}
 
 	if len(specDirs) == 0 {
-		fmt.Println("No agent specs found (directories with manifest.json)")
+		t.println("No agent specs found (directories with manifest.json)")
 		return
 	}
 
-	fmt.Printf("Found %d agent specs:\n", len(specDirs))
+	t.printf("Found %d agent specs:\n", len(specDirs))
 	for _, name := range specDirs {
-		fmt.Printf("  - %s\n", name)
+		t.printf("  - %s\n", name)
 	}
-	fmt.Println()
+	t.println()
 
 	successCount := 0
 	failedCount := 0
 
 	for i, specName := range specDirs {
-		fmt.Println(strings.Repeat("=", 80))
-		fmt.Printf("[%d/%d] Publishing agent spec: %s\n", i+1, len(specDirs), specName)
-		fmt.Println(strings.Repeat("=", 80))
+		t.println(strings.Repeat("=", 80))
+		t.printf("[%d/%d] Publishing agent spec: %s\n", i+1, len(specDirs), specName)
+		t.println(strings.Repeat("=", 80))
 
 		specPath := filepath.Join(folderPath, specName)
 		err := t.agentSpecService.UploadAgentSpec(specPath)
 		if err != nil {
-			fmt.Printf("Publish failed: %v\n", err)
+			t.printf("Publish failed: %v\n", err)
+			t.fail(err)
 			failedCount++
 		} else {
-			fmt.Printf("Publish successful!\n")
+			t.printf("Publish successful!\n")
 			successCount++
 		}
-		fmt.Println()
+		t.println()
 	}
 
 	// Summary
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Println("Batch Publish Complete")
-	fmt.Println(strings.Repeat("=", 80))
-	fmt.Printf("Success: %d\n", successCount)
+	t.println(strings.Repeat("=", 80))
+	t.println("Batch Publish Complete")
+	t.println(strings.Repeat("=", 80))
+	t.printf("Success: %d\n", successCount)
 	if failedCount > 0 {
-		fmt.Printf("Failed: %d\n", failedCount)
+		t.printf("Failed: %d\n", failedCount)
 	}
-	fmt.Printf("Total: %d\n", len(specDirs))
-	fmt.Println()
-	fmt.Println("Tip: Use 'agentspec-list' to view all published agent specs")
+	t.printf("Total: %d\n", len(specDirs))
+	t.println()
+	t.println("Tip: Use 'agentspec-list' to view all published agent specs")
 }
 
-// truncateDesc truncates description to maxLen and appends ...... if needed
+// truncateDesc cuts description to maxLen terminal columns and appends ...... if needed
 func truncateDesc(desc string, maxLen int) string {
-	runes := []rune(desc)
-	if len(runes) <= maxLen {
+	if util.DisplayWidth(desc) <= maxLen {
 		return desc
 	}
-	return string(runes[:maxLen]) + "......"
+	return util.Truncate(desc, maxLen, "") + "......"
 }
@@ -2,6 +2,8 @@ package terminal
 
 import (
 	"testing"
+
+	"github.com/nacos-group/nacos-cli/internal/client"
 )
 
 func TestParseCommandArgs(t *testing.T) {
@@ -100,7 +102,10 @@ func TestParseCommandArgs(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd, args := parseCommandArgs(tt.input)
+			cmd, args, err := parseCommandArgs(tt.input)
+			if err != nil {
+				t.Fatalf("parseCommandArgs(%q) unexpected error: %v", tt.input, err)
+			}
 			
 			if cmd != tt.expectedCmd {
 				t.Errorf("parseCommandArgs(%q) cmd = %q, want %q\nTest: %s\nDescription: %s", 
@@ -157,7 +162,10 @@ func TestParseCommandArgsEdgeCases(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			cmd, args := parseCommandArgs(tt.input)
+			cmd, args, err := parseCommandArgs(tt.input)
+			if err != nil {
+				t.Fatalf("parseCommandArgs(%q) unexpected error: %v", tt.input, err)
+			}
 			
 			if cmd != tt.expectedCmd {
 				t.Errorf("parseCommandArgs(%q) cmd = %q, want %q", tt.input, cmd, tt.expectedCmd)
@@ -169,3 +177,52 @@ func TestParseCommandArgsEdgeCases(t *testing.T) {
 		})
 	}
 }
+
+func TestUseGroup(t *testing.T) {
+	term := &Terminal{client: &client.NacosClient{Namespace: "prod"}}
+
+	if _, _, ok := term.configArgs([]string{"app.yaml"}); ok {
+		t.Error("configArgs accepted a dataId without a default group")
+	}
+	if got := term.getPrompt(); got != "\033[32mnacos>\033[0m " {
+		t.Errorf("prompt = %q", got)
+	}
+
+	term.use([]string{"group", "DEFAULT_GROUP"})
+	dataID, group, ok := term.configArgs([]string{"app.yaml"})
+	if !ok || dataID != "app.yaml" || group != "DEFAULT_GROUP" {
+		t.Errorf("configArgs = %q, %q, %v; want app.yaml, DEFAULT_GROUP", dataID, group, ok)
+	}
+	if _, group, _ := term.configArgs([]string{"app.yaml", "OTHER"}); group != "OTHER" {
+		t.Errorf("explicit group = %q, want OTHER", group)
+	}
+	if got := term.getPrompt(); got != "\033[32mnacos[prod/DEFAULT_GROUP]>\033[0m " {
+		t.Errorf("prompt = %q", got)
+	}
+
+	term.use([]string{"group", "-"})
+	if term.defaultGroup != "" {
+		t.Errorf("defaultGroup = %q after 'use group -'", term.defaultGroup)
+	}
+}
+
+func TestTruncateDesc(t *testing.T) {
+	tests := []struct {
+		name   string
+		desc   string
+		maxLen int
+		want   string
+	}{
+		{"short", "Deploy helper", 20, "Deploy helper"},
+		{"ascii", "Deploys services to the cluster", 10, "Deploys se......"},
+		{"cjk counts two columns a rune", "部署服务到集群的技能", 10, "部署服务到......"},
+		{"emoji", "🚀 快速部署", 6, "🚀 快......"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := truncateDesc(tt.desc, tt.maxLen); got != tt.want {
+				t.Errorf("truncateDesc(%q, %d) = %q, want %q", tt.desc, tt.maxLen, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package terminal
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/chzyer/readline"
+)
+
+const (
+	// watchDefaultInterval is the time between runs when -n is not given
+	watchDefaultInterval = 2 * time.Second
+	// watchMinInterval protects the server from tight polling loops
+	watchMinInterval = time.Second
+)
+
+// watchable lists the read-only commands watch may re-run. Commands that
+// publish, download or change the session are refused.
+var watchable = map[string]bool{
+	"config-get":     true,
+	"config-list":    true,
+	"skill-list":     true,
+	"agentspec-list": true,
+	"jobs":           true,
+	"logs":           true,
+	"server":         true,
+}
+
+// watch re-runs a command at an interval, highlighting lines that changed
+// since the previous run, until a key is pressed
+func (t *Terminal) watch(args []string) {
+	var seconds float64
+
+	fs := newFlagSet("watch")
+	fs.Float64VarP(&seconds, "interval", "n", watchDefaultInterval.Seconds(), "Seconds between runs (minimum 1)")
+	// Flags after the command name belong to the watched command
+	fs.SetInterspersed(false)
+	words, ok := t.parseFlags(fs, args)
+	if !ok {
+		return
+	}
+	if len(words) == 0 {
+		t.printUsage("watch [-n seconds] <command...>")
+		return
+	}
+
+	words, err := ExpandAliases(words, t.aliases)
+	if err != nil {
+		t.errorf("%v", err)
+		return
+	}
+	if !watchable[words[0]] {
+		t.errorf("watch only re-runs read-only commands: %s", strings.Join(watchableCommands(), ", "))
+		return
+	}
+	if t.rl == nil {
+		t.errorf("watch needs an interactive terminal")
+		return
+	}
+
+	interval := watchInterval(seconds)
+	command := joinWords(words)
+
+	var pressed chan struct{}
+	var previous []string
+	for {
+		output, err := t.captureOutput(func() { t.runCommand(command) })
+		if err != nil {
+			t.errorf("%v", err)
+			if pressed != nil {
+				// The key reader started for an earlier run would otherwise
+				// swallow the first key typed at the prompt
+				t.println("\033[90mPress any key to return\033[0m")
+				<-pressed
+			}
+			return
+		}
+		lines := splitOutputLines(output)
+
+		t.print("\033[H\033[2J")
+		t.printf("\033[90mEvery %s: \033[0m%s\033[90m    %s\033[0m\n\n", interval, command, time.Now().Format("15:04:05"))
+		for _, line := range highlightChanges(previous, lines) {
+			t.println(line)
+		}
+		t.println("\n\033[90mPress any key to stop\033[0m")
+		previous = lines
+		if pressed == nil {
+			// Keys are read once the first run succeeded, so a run that
+			// fails right away leaves no reader behind
+			pressed = make(chan struct{})
+			go t.waitForKey(pressed)
+		}
+
+		select {
+		case <-pressed:
+			t.result = commandResult{rows: -1}
+			return
+		case <-time.After(interval):
+		}
+	}
+}
+
+// watchInterval converts -n seconds to a duration of at least watchMinInterval
+func watchInterval(seconds float64) time.Duration {
+	interval := time.Duration(seconds * float64(time.Second))
+	if interval < watchMinInterval {
+		return watchMinInterval
+	}
+	return interval
+}
+
+func watchableCommands() []string {
+	names := make([]string, 0, len(watchable))
+	for name := range watchable {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// waitForKey closes pressed on the next key press, including Ctrl+C. It reads
+// a masked line whose first key is turned into Enter, so nothing is echoed.
+func (t *Terminal) waitForKey(pressed chan<- struct{}) {
+	cfg := t.rl.GenPasswordConfig()
+	cfg.Prompt = ""
+	cfg.FuncFilterInputRune = func(rune) (rune, bool) {
+		return readline.CharEnter, true
+	}
+	t.rl.ReadPasswordWithConfig(cfg)
+	close(pressed)
+}
+
+// highlightChanges returns current with lines that differ from the same line
+// of previous shown in reverse video. Nothing is highlighted on the first run.
+func highlightChanges(previous, current []string) []string {
+	result := make([]string, len(current))
+	for i, line := range current {
+		if previous == nil || (i < len(previous) && previous[i] == line) {
+			result[i] = line
+		} else {
+			result[i] = "\033[7m" + line + "\033[0m"
+		}
+	}
+	return result
+}
+
+func splitOutputLines(output string) []string {
+	output = strings.TrimRight(output, "\n")
+	if output == "" {
+		return []string{}
+	}
+	return strings.Split(output, "\n")
+}
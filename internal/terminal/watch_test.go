@@ -0,0 +1,53 @@
+package terminal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWatchInterval(t *testing.T) {
+	tests := []struct {
+		seconds float64
+		want    time.Duration
+	}{
+		{2, 2 * time.Second},
+		{0.1, time.Second},
+		{0, time.Second},
+		{-5, time.Second},
+		{1.5, 1500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := watchInterval(tt.seconds); got != tt.want {
+			t.Errorf("watchInterval(%v) = %v, want %v", tt.seconds, got, tt.want)
+		}
+	}
+}
+
+func TestHighlightChanges(t *testing.T) {
+	first := highlightChanges(nil, []string{"a", "b"})
+	if !reflect.DeepEqual(first, []string{"a", "b"}) {
+		t.Errorf("first run = %q, want no highlighting", first)
+	}
+
+	got := highlightChanges([]string{"a", "b"}, []string{"a", "c", "d"})
+	want := []string{"a", "\033[7mc\033[0m", "\033[7md\033[0m"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("highlightChanges() = %q, want %q", got, want)
+	}
+}
+
+func TestWatchRefusesDestructiveCommands(t *testing.T) {
+	for _, args := range [][]string{
+		{"config-set", "app.yaml", "DEFAULT_GROUP"},
+		{"-n", "5", "skill-publish", "./skill"},
+		{"cs", "app.yaml"}, // alias for config-set
+		{"watch", "server"},
+	} {
+		term := &Terminal{aliases: MergeAliases(nil)}
+		term.watch(args)
+		if !term.result.failed() {
+			t.Errorf("watch %q was not refused", args)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Package ui holds the prompts shared by CLI commands and the terminal.
+package ui
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+var (
+	// AssumeYes answers every confirmation with yes (--yes)
+	AssumeYes bool
+	// Interactive allows prompting; when false (--interactive=false) any prompt
+	// is an error so a pipeline can never wait for input
+	Interactive = true
+)
+
+var (
+	// ErrPromptsDisabled is returned instead of prompting with --interactive=false
+	ErrPromptsDisabled = errors.New("input required but prompts are disabled by --interactive=false")
+	// ErrNoTerminal is returned instead of prompting when stdin is not a terminal
+	ErrNoTerminal = errors.New("input required but stdin is not a terminal")
+)
+
+// Confirm asks a yes/no question on stdin. An empty answer is "no" when
+// defaultNo is set and "yes" otherwise. It returns true without asking with
+// --yes, and fails closed with an error when it cannot ask.
+func Confirm(prompt string, defaultNo bool) (bool, error) {
+	canPrompt := term.IsTerminal(int(os.Stdin.Fd()))
+	return ConfirmWith(prompt, defaultNo, canPrompt, func(prompt string) (string, error) {
+		fmt.Fprint(os.Stderr, prompt)
+		return bufio.NewReader(os.Stdin).ReadString('\n')
+	})
+}
+
+// ConfirmWith is Confirm for callers that own the input, such as the terminal's
+// line editor. canPrompt reports whether a user is there to answer; read shows
+// the prompt and returns the answer. A read error counts as "no".
+func ConfirmWith(prompt string, defaultNo, canPrompt bool, read func(prompt string) (string, error)) (bool, error) {
+	if AssumeYes {
+		return true, nil
+	}
+	if err := RequireInteractive(); err != nil {
+		return false, fmt.Errorf("%w; pass --yes to confirm", err)
+	}
+	if !canPrompt {
+		return false, fmt.Errorf("%w; pass --yes to confirm", ErrNoTerminal)
+	}
+
+	suffix := " [Y/n]: "
+	if defaultNo {
+		suffix = " [y/N]: "
+	}
+	answer, err := read(prompt + suffix)
+	if err != nil {
+		return false, nil
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true, nil
+	case "":
+		return !defaultNo, nil
+	}
+	return false, nil
+}
+
+// RequireInteractive returns ErrPromptsDisabled when prompts are turned off
+func RequireInteractive() error {
+	if !Interactive {
+		return ErrPromptsDisabled
+	}
+	return nil
+}
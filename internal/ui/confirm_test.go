@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestConfirmWith(t *testing.T) {
+	tests := []struct {
+		name      string
+		answer    string
+		readErr   error
+		defaultNo bool
+		want      bool
+	}{
+		{"yes", "y\n", nil, true, true},
+		{"full yes", " YES \n", nil, true, true},
+		{"no", "n\n", nil, false, false},
+		{"empty default no", "\n", nil, true, false},
+		{"empty default yes", "\n", nil, false, true},
+		{"other answer", "maybe\n", nil, false, false},
+		{"read error", "", io.EOF, false, false},
+	}
+	for _, tt := range tests {
+		var shown string
+		got, err := ConfirmWith("Delete?", tt.defaultNo, true, func(prompt string) (string, error) {
+			shown = prompt
+			return tt.answer, tt.readErr
+		})
+		if err != nil || got != tt.want {
+			t.Errorf("%s: ConfirmWith() = %v, %v; want %v", tt.name, got, err, tt.want)
+		}
+		wantPrompt := "Delete? [Y/n]: "
+		if tt.defaultNo {
+			wantPrompt = "Delete? [y/N]: "
+		}
+		if shown != wantPrompt {
+			t.Errorf("%s: prompt = %q, want %q", tt.name, shown, wantPrompt)
+		}
+	}
+}
+
+func TestConfirmWithoutPrompting(t *testing.T) {
+	defer func() { AssumeYes, Interactive = false, true }()
+	read := func(string) (string, error) {
+		t.Fatal("read called")
+		return "", nil
+	}
+
+	if _, err := ConfirmWith("Delete?", true, false, read); !errors.Is(err, ErrNoTerminal) {
+		t.Errorf("without a terminal: error = %v, want ErrNoTerminal", err)
+	}
+
+	Interactive = false
+	if _, err := ConfirmWith("Delete?", true, true, read); !errors.Is(err, ErrPromptsDisabled) {
+		t.Errorf("--interactive=false: error = %v, want ErrPromptsDisabled", err)
+	}
+
+	AssumeYes = true
+	if ok, err := ConfirmWith("Delete?", true, false, read); !ok || err != nil {
+		t.Errorf("--yes: ConfirmWith() = %v, %v; want true", ok, err)
+	}
+}
@@ -0,0 +1,38 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// consoleANSI reports whether the console interprets ANSI escape sequences;
+// see SetupConsole
+var consoleANSI = true
+
+// SetupConsole lets the console interpret the ANSI escape sequences printed
+// for colors and cursor movement. Unix terminals always do; a Windows
+// console does once virtual terminal processing is turned on, which needs
+// Windows 10 or later. On an older console, syntax highlighting is turned
+// off as with NO_COLOR and a warning suggests a newer terminal. Call it once,
+// before any output.
+func SetupConsole() {
+	stdout, stderr := enableVirtualTerminal(os.Stdout), enableVirtualTerminal(os.Stderr)
+	if stdout && stderr {
+		return
+	}
+	consoleANSI = false
+	os.Setenv("NO_COLOR", "1")
+	fmt.Fprintln(os.Stderr, "Warning: this console does not support ANSI colors; output may show escape codes. Use Windows Terminal or PowerShell 7 for colored output.")
+}
+
+// ClearScreen clears the terminal, with cls on a console without ANSI support
+func ClearScreen() {
+	if consoleANSI {
+		fmt.Print("\033[H\033[2J")
+		return
+	}
+	cls := exec.Command("cmd", "/c", "cls")
+	cls.Stdout = os.Stdout
+	cls.Run()
+}
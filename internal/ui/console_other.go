@@ -0,0 +1,11 @@
+//go:build !windows
+
+package ui
+
+import "os"
+
+// enableVirtualTerminal reports true: Unix terminals interpret ANSI escape
+// sequences without being asked
+func enableVirtualTerminal(f *os.File) bool {
+	return true
+}
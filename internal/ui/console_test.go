@@ -0,0 +1,28 @@
+package ui
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestEnableVirtualTerminalOutsideConsole(t *testing.T) {
+	// Output redirected to a file or a pipe needs no console mode
+	f, err := os.Create(filepath.Join(t.TempDir(), "out.txt"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	defer w.Close()
+
+	for _, target := range []*os.File{f, w} {
+		if !enableVirtualTerminal(target) {
+			t.Errorf("enableVirtualTerminal(%s) = false, want true", target.Name())
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package ui
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nacos-group/nacos-cli/internal/util"
+	"golang.org/x/term"
+)
+
+// Quiet turns off progress output (--quiet)
+var Quiet bool
+
+const (
+	// progressDelay is how long a step runs before its progress is shown, so
+	// that quick steps print nothing
+	progressDelay = 300 * time.Millisecond
+	// progressInterval is how often the progress line of a terminal is redrawn
+	progressInterval = 100 * time.Millisecond
+	// progressBarWidth is the width of the bar in columns
+	progressBarWidth = 24
+)
+
+// Progress shows on stderr how far an upload, a download or another long
+// step got. On a terminal it redraws one line with a bar, the amount done and
+// the throughput; otherwise it prints a line at every quarter of the total.
+// It shows nothing with --quiet, for a step without a total when stderr is
+// not a terminal, and for steps done within progressDelay. A nil *Progress
+// shows nothing either.
+type Progress struct {
+	mu       sync.Mutex
+	w        io.Writer // nil when disabled
+	tty      bool
+	label    string
+	unit     string // what is counted, e.g. "files"; empty for bytes
+	total    int64  // 0 when unknown
+	done     int64
+	start    time.Time
+	lastDraw time.Time
+	quarter  int64 // last quarter printed without a terminal
+	shown    bool  // something was printed
+	now      func() time.Time
+}
+
+// NewProgress starts the progress of a step labelled label, e.g. "Uploading
+// demo.zip", with total bytes, or total units such as "files" when unit is
+// not empty. A total of 0 means unknown.
+func NewProgress(label string, total int64, unit string) *Progress {
+	return newProgress(os.Stderr, term.IsTerminal(int(os.Stderr.Fd())), label, total, unit)
+}
+
+func newProgress(w io.Writer, tty bool, label string, total int64, unit string) *Progress {
+	p := &Progress{tty: tty, label: label, unit: unit, total: total, now: time.Now}
+	if !Quiet && (tty || total > 0) {
+		p.w = w
+	}
+	p.start = p.now()
+	return p
+}
+
+// Add records n more bytes or units done
+func (p *Progress) Add(n int64) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done += n
+	if p.w == nil || p.now().Sub(p.start) < progressDelay {
+		return
+	}
+	if p.tty {
+		if p.now().Sub(p.lastDraw) >= progressInterval {
+			p.draw()
+		}
+		return
+	}
+	if p.total > 0 {
+		if quarter := min(p.done*4/p.total, 4); quarter > p.quarter {
+			p.quarter = quarter
+			p.printQuarter()
+		}
+	}
+}
+
+// Done finishes the progress line, if one was shown. Later calls do nothing.
+func (p *Progress) Done() {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	w := p.w
+	defer func() { p.w = nil }()
+	if w == nil || !p.shown {
+		return
+	}
+	if p.tty {
+		p.draw()
+		fmt.Fprintln(p.w)
+		return
+	}
+	if p.quarter < 4 && p.total > 0 && p.done >= p.total {
+		p.quarter = 4
+		p.printQuarter()
+	}
+}
+
+// Reader returns a reader that records what is read from r
+func (p *Progress) Reader(r io.Reader) io.Reader {
+	if p == nil {
+		return r
+	}
+	return &progressReader{r: r, p: p}
+}
+
+// draw redraws the progress line of a terminal
+func (p *Progress) draw() {
+	p.lastDraw = p.now()
+	p.shown = true
+	line := p.label + " "
+	if p.total > 0 {
+		filled := int(min(p.done, p.total) * progressBarWidth / p.total)
+		line += "[" + strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled) + "] "
+	}
+	line += p.amount()
+	if p.unit == "" {
+		if elapsed := p.now().Sub(p.start).Seconds(); elapsed > 0 {
+			line += fmt.Sprintf("  %s/s", util.FormatBytes(int64(float64(p.done)/elapsed)))
+		}
+	}
+	fmt.Fprintf(p.w, "\r\033[K%s", line)
+}
+
+// printQuarter prints the line of the quarter reached without a terminal
+func (p *Progress) printQuarter() {
+	p.shown = true
+	fmt.Fprintf(p.w, "%s: %d%% (%s)\n", p.label, p.quarter*25, p.amount())
+}
+
+// amount shows what is done and, when known, of what total
+func (p *Progress) amount() string {
+	if p.unit != "" {
+		if p.total > 0 {
+			return fmt.Sprintf("%d/%d %s", p.done, p.total, p.unit)
+		}
+		return fmt.Sprintf("%d %s", p.done, p.unit)
+	}
+	if p.total > 0 {
+		return fmt.Sprintf("%s / %s", util.FormatBytes(p.done), util.FormatBytes(p.total))
+	}
+	return util.FormatBytes(p.done)
+}
+
+// progressReader records the bytes read through it in a Progress
+type progressReader struct {
+	r io.Reader
+	p *Progress
+}
+
+func (r *progressReader) Read(b []byte) (int, error) {
+	n, err := r.r.Read(b)
+	r.p.Add(int64(n))
+	return n, err
+}
@@ -0,0 +1,126 @@
+package ui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeClock makes a Progress see clock as the time, starting at zero
+func fakeClock(p *Progress, clock *time.Duration) {
+	base := time.Unix(0, 0)
+	p.now = func() time.Time { return base.Add(*clock) }
+	p.start = p.now()
+}
+
+func TestProgressQuarters(t *testing.T) {
+	var buf bytes.Buffer
+	var clock time.Duration
+	p := newProgress(&buf, false, "Downloading demo", 4096, "")
+	fakeClock(p, &clock)
+
+	p.Add(2048) // within progressDelay: nothing yet
+	clock = time.Second
+	p.Add(100)
+	p.Add(100) // still the second quarter
+	p.Add(1848)
+	p.Done()
+	p.Done()
+
+	want := "Downloading demo: 50% (2.1 KB / 4.0 KB)\nDownloading demo: 100% (4.0 KB / 4.0 KB)\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output = %q, want %q", got, want)
+	}
+}
+
+func TestProgressQuickStep(t *testing.T) {
+	var buf bytes.Buffer
+	var clock time.Duration
+	p := newProgress(&buf, true, "Zipping demo", 3, "files")
+	fakeClock(p, &clock)
+	p.Add(3)
+	p.Done()
+	if buf.Len() != 0 {
+		t.Errorf("a step done within the delay printed %q", buf.String())
+	}
+}
+
+func TestProgressTerminal(t *testing.T) {
+	var buf bytes.Buffer
+	var clock time.Duration
+	p := newProgress(&buf, true, "Zipping demo", 4, "files")
+	fakeClock(p, &clock)
+	clock = time.Second
+	p.Add(1)
+	p.Add(1) // within progressInterval: not redrawn
+	clock += progressInterval
+	p.Add(2)
+	p.Done()
+
+	out := buf.String()
+	if n := strings.Count(out, "\r\033[K"); n != 3 {
+		t.Errorf("line drawn %d times, want 3:\n%q", n, out)
+	}
+	if !strings.Contains(out, "[======                  ] 1/4 files") ||
+		!strings.HasSuffix(out, "[========================] 4/4 files\n") {
+		t.Errorf("output = %q", out)
+	}
+
+	buf.Reset()
+	p = newProgress(&buf, true, "Uploading demo.zip", 0, "")
+	fakeClock(p, &clock)
+	clock += 2 * time.Second
+	p.Add(2048)
+	p.Done()
+	if got, want := buf.String(), "\r\033[KUploading demo.zip 2.0 KB  1.0 KB/s\r\033[KUploading demo.zip 2.0 KB  1.0 KB/s\n"; got != want {
+		t.Errorf("output without a total = %q, want %q", got, want)
+	}
+}
+
+func TestProgressDisabled(t *testing.T) {
+	var buf bytes.Buffer
+	var clock time.Duration
+
+	// No total and no terminal: there is nothing useful to print
+	p := newProgress(&buf, false, "Uploading demo.zip", 0, "")
+	fakeClock(p, &clock)
+	clock = time.Second
+	p.Add(4096)
+	p.Done()
+
+	Quiet = true
+	p = newProgress(&buf, true, "Zipping demo", 2, "files")
+	Quiet = false
+	fakeClock(p, &clock)
+	clock = 2 * time.Second
+	p.Add(2)
+	p.Done()
+
+	var nilProgress *Progress
+	nilProgress.Add(1)
+	nilProgress.Done()
+	if r := strings.NewReader("x"); nilProgress.Reader(r) != io.Reader(r) {
+		t.Error("Reader() of a nil Progress wraps the reader")
+	}
+
+	if buf.Len() != 0 {
+		t.Errorf("disabled progress printed %q", buf.String())
+	}
+}
+
+func TestProgressReader(t *testing.T) {
+	var buf bytes.Buffer
+	var clock time.Duration
+	p := newProgress(&buf, false, "Downloading demo", 10, "")
+	fakeClock(p, &clock)
+	clock = time.Second
+	data, err := io.ReadAll(p.Reader(strings.NewReader("0123456789")))
+	if err != nil || string(data) != "0123456789" {
+		t.Fatalf("ReadAll() = %q, %v", data, err)
+	}
+	if p.done != 10 || !strings.Contains(buf.String(), "100% (10 B / 10 B)") {
+		t.Errorf("done = %d, output %q", p.done, buf.String())
+	}
+}
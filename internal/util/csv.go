@@ -0,0 +1,22 @@
+package util
+
+import (
+	"encoding/csv"
+	"io"
+)
+
+// WriteCSV writes a header row followed by rows as RFC 4180 CSV, for
+// spreadsheets to import: lines end in CRLF, and fields holding commas,
+// quotes or line breaks are quoted. Values are written in full, unlike the
+// truncated columns of the tables.
+func WriteCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	cw.UseCRLF = true
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
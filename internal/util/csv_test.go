@@ -0,0 +1,29 @@
+package util
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, []string{"name", "description"}, [][]string{
+		{"plain", "Searches the web"},
+		{"comma", "Reads, writes and deletes"},
+		{"quote", `Says "hello"`},
+		{"multiline", "First line\nsecond line"},
+		{"empty", ""},
+	})
+	if err != nil {
+		t.Fatalf("WriteCSV() error = %v", err)
+	}
+	want := "name,description\r\n" +
+		"plain,Searches the web\r\n" +
+		"comma,\"Reads, writes and deletes\"\r\n" +
+		"quote,\"Says \"\"hello\"\"\"\r\n" +
+		"multiline,\"First line\r\nsecond line\"\r\n" +
+		"empty,\r\n"
+	if got := buf.String(); got != want {
+		t.Errorf("WriteCSV() = %q, want %q", got, want)
+	}
+}
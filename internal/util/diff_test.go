@@ -0,0 +1,46 @@
+package util
+
+import "testing"
+
+func TestUnifiedDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		old      string
+		new      string
+		expected string
+	}{
+		{
+			name:     "equal",
+			old:      "a\nb\n",
+			new:      "a\nb\n",
+			expected: "",
+		},
+		{
+			name:     "changed line",
+			old:      "a\nb\nc\n",
+			new:      "a\nB\nc\n",
+			expected: "--- old\n+++ new\n@@ -1,3 +1,3 @@\n a\n-b\n+B\n c\n",
+		},
+		{
+			name:     "new config",
+			old:      "",
+			new:      "key: value\n",
+			expected: "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+key: value\n",
+		},
+		{
+			name:     "separate hunks",
+			old:      "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			new:      "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\ntwelve\n",
+			expected: "--- old\n+++ new\n@@ -1,4 +1,4 @@\n-1\n+one\n 2\n 3\n 4\n@@ -9,4 +9,4 @@\n 9\n 10\n 11\n-12\n+twelve\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UnifiedDiff("old", "new", tt.old, tt.new)
+			if got != tt.expected {
+				t.Errorf("UnifiedDiff() =\n%s\nwant:\n%s", got, tt.expected)
+			}
+		})
+	}
+}
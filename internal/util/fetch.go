@@ -0,0 +1,74 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// FetchURL downloads the body of rawURL, following redirects.
+// headers are "Name: value" strings added to the request; their values are never
+// included in returned errors. A zero timeout means no timeout.
+// If expectedSHA256 is non-empty, the body's SHA-256 (hex) must match it.
+func FetchURL(rawURL string, headers []string, timeout time.Duration, expectedSHA256 string) ([]byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("unsupported url scheme %q (expected http or https)", u.Scheme)
+	}
+	display := u.Redacted()
+
+	req, err := http.NewRequest("GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("build request for %s: %w", display, err)
+	}
+	for _, h := range headers {
+		name, value, ok := strings.Cut(h, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			// Don't echo the header: without a colon it may be a bare secret
+			return nil, fmt.Errorf("invalid header (expected 'Name: value')")
+		}
+		req.Header.Set(name, strings.TrimSpace(value))
+	}
+
+	httpClient := &http.Client{Timeout: timeout}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		// Unwrap *url.Error so the raw URL (which may carry credentials) is not echoed
+		if ue, ok := err.(*url.Error); ok {
+			err = ue.Err
+		}
+		return nil, fmt.Errorf("fetch %s failed: %w", display, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response from %s: %w", display, err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch %s failed (HTTP %d)", display, resp.StatusCode)
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("fetch %s returned empty content", display)
+	}
+
+	if expectedSHA256 != "" {
+		sum := sha256.Sum256(body)
+		actual := hex.EncodeToString(sum[:])
+		if !strings.EqualFold(actual, strings.TrimSpace(expectedSHA256)) {
+			return nil, fmt.Errorf("sha256 mismatch for %s: expected %s, got %s", display, expectedSHA256, actual)
+		}
+	}
+
+	return body, nil
+}
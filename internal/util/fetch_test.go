@@ -0,0 +1,77 @@
+package util
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFetchURL(t *testing.T) {
+	const body = "key: value\n"
+	sum := sha256.Sum256([]byte(body))
+	checksum := hex.EncodeToString(sum[:])
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/app.yaml", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.Write([]byte(body))
+	})
+	mux.HandleFunc("/moved", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/app.yaml", http.StatusFound)
+	})
+	mux.HandleFunc("/empty", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(200 * time.Millisecond)
+		w.Write([]byte(body))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	auth := []string{"Authorization: Bearer secret-token"}
+
+	tests := []struct {
+		name    string
+		path    string
+		headers []string
+		timeout time.Duration
+		sha     string
+		wantErr bool
+	}{
+		{name: "ok", path: "/app.yaml", headers: auth},
+		{name: "redirect followed", path: "/moved", headers: auth},
+		{name: "checksum match", path: "/app.yaml", headers: auth, sha: strings.ToUpper(checksum)},
+		{name: "checksum mismatch", path: "/app.yaml", headers: auth, sha: "deadbeef", wantErr: true},
+		{name: "unauthorized", path: "/app.yaml", wantErr: true},
+		{name: "empty body", path: "/empty", wantErr: true},
+		{name: "timeout", path: "/slow", timeout: 50 * time.Millisecond, wantErr: true},
+		{name: "bad header", path: "/app.yaml", headers: []string{"no-colon"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FetchURL(server.URL+tt.path, tt.headers, tt.timeout, tt.sha)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchURL() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "secret-token") {
+				t.Errorf("error leaks auth header: %v", err)
+			}
+			if !tt.wantErr && string(got) != body {
+				t.Errorf("FetchURL() = %q, want %q", got, body)
+			}
+		})
+	}
+}
+
+func TestFetchURLRejectsUnsupportedScheme(t *testing.T) {
+	if _, err := FetchURL("file:///etc/passwd", nil, 0, ""); err == nil {
+		t.Error("expected error for file:// url")
+	}
+}
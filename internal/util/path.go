@@ -10,6 +10,7 @@ import (
 // Supported patterns:
 //   - "~"        → Home directory
 //   - "~/path"   → Home directory joined with path
+//   - "~\path"   → The same, on Windows only
 //   - "/path"    → Returned unchanged
 //   - "path"     → Returned unchanged
 //
@@ -23,7 +24,7 @@ func ExpandTilde(path string) (string, error) {
 		return homeDir, nil
 	}
 	
-	if strings.HasPrefix(path, "~/") {
+	if strings.HasPrefix(path, "~/") || (filepath.Separator == '\\' && strings.HasPrefix(path, `~\`)) {
 		homeDir, err := os.UserHomeDir()
 		if err != nil {
 			return path, err
@@ -35,6 +36,19 @@ func ExpandTilde(path string) (string, error) {
 	return path, nil
 }
 
+// ResolvePath expands environment variables ($VAR or ${VAR}) and then ~ like
+// ExpandTilde, and makes a relative path absolute, relative to the current
+// directory, so a path reads the same in messages and state files wherever
+// it came from. It is for paths the shell did not expand: one attached with
+// --flag=~/path, read from a config file, or typed in the terminal.
+func ResolvePath(path string) (string, error) {
+	expanded, err := ExpandTilde(os.ExpandEnv(path))
+	if err != nil {
+		return path, err
+	}
+	return filepath.Abs(expanded)
+}
+
 // SeparatorLine returns a horizontal separator line.
 // Uses Unicode double line characters by default.
 // Pass ascii=true for environments that don't support Unicode.
@@ -3,6 +3,7 @@ package util
 import (
 	"os"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -11,6 +12,11 @@ func TestExpandTilde(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Failed to get home directory: %v", err)
 	}
+	// Only Windows takes a backslash after ~ as a separator
+	backslashPath := `~\test\path`
+	if runtime.GOOS == "windows" {
+		backslashPath = filepath.Join(homeDir, `test\path`)
+	}
 
 	tests := []struct {
 		name     string
@@ -30,6 +36,12 @@ func TestExpandTilde(t *testing.T) {
 			expected: filepath.Join(homeDir, "test/path"),
 			wantErr:  false,
 		},
+		{
+			name:     "tilde backslash path",
+			input:    `~\test\path`,
+			expected: backslashPath,
+			wantErr:  false,
+		},
 		{
 			name:     "absolute path unchanged",
 			input:    "/absolute/path",
@@ -64,6 +76,43 @@ func TestExpandTilde(t *testing.T) {
 	}
 }
 
+func TestResolvePath(t *testing.T) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		t.Skip("no home directory")
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("NACOS_TEST_DIR", "/srv/nacos")
+	t.Setenv("NACOS_TEST_HOME", "~")
+	// On Windows a rooted path gets the drive of the working directory
+	abs := func(path string) string {
+		path, _ = filepath.Abs(filepath.FromSlash(path))
+		return path
+	}
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"~/.skills", filepath.Join(home, ".skills")},
+		{"$NACOS_TEST_DIR/app.yaml", abs("/srv/nacos/app.yaml")},
+		{"${NACOS_TEST_DIR}/skills", abs("/srv/nacos/skills")},
+		{"$NACOS_TEST_HOME/configs", filepath.Join(home, "configs")},
+		{"$NACOS_TEST_UNSET/skills", abs("/skills")},
+		{"skills", filepath.Join(wd, "skills")},
+		{"./a/../skills", filepath.Join(wd, "skills")},
+		{"/opt/skills/", abs("/opt/skills")},
+	}
+	for _, tt := range tests {
+		got, err := ResolvePath(tt.input)
+		if err != nil || got != tt.want {
+			t.Errorf("ResolvePath(%q) = %q, %v; want %q", tt.input, got, err, tt.want)
+		}
+	}
+}
+
 func TestSeparatorLine(t *testing.T) {
 	tests := []struct {
 		name     string
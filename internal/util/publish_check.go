@@ -0,0 +1,53 @@
+package util
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DefaultMaxConfigSize is the size config-set refuses to publish above
+// without --force. Nacos itself accepts up to about 10 MB.
+const DefaultMaxConfigSize = 1 << 20
+
+// shrinkRatio is how much smaller than the content it replaces new content
+// has to be for PublishCheck to warn; shrinkMinimum is the smallest previous
+// content it compares with, so that small configs can be rewritten freely
+const (
+	shrinkRatio   = 10
+	shrinkMinimum = 1024
+)
+
+// PublishCheck is what config-set checks content against before publishing
+// it, so that a wrong -f argument does not end up in Nacos
+type PublishCheck struct {
+	MaxSize     int64 // larger content is refused unless Force is set; 0 means no limit
+	Force       bool
+	AllowBinary bool // do not warn about content that looks binary
+	AllowShrink bool // do not warn about content much smaller than the content it replaces
+}
+
+// Check returns an error for content over MaxSize, and warnings for content
+// that looks binary and for content under a tenth of previous, the content
+// it replaces ("" for a new config), which usually means a truncated file
+func (p PublishCheck) Check(content, previous string) ([]string, error) {
+	if p.MaxSize > 0 && int64(len(content)) > p.MaxSize && !p.Force {
+		return nil, fmt.Errorf("content is %s, over the limit of %s: check the file given, or pass --force to publish it anyway (the limit is --max-size or defaults.maxSize)",
+			FormatBytes(int64(len(content))), FormatBytes(p.MaxSize))
+	}
+	var warnings []string
+	if !p.AllowBinary && LooksBinary(content) {
+		warnings = append(warnings, "the content looks binary (NUL bytes or invalid UTF-8); pass --allow-binary if that is intended")
+	}
+	if !p.AllowShrink && len(previous) >= shrinkMinimum && len(content)*shrinkRatio < len(previous) {
+		warnings = append(warnings, fmt.Sprintf("the content is %s, less than a tenth of the %s it replaces, so the file may be truncated; pass --allow-shrink if that is intended",
+			FormatBytes(int64(len(content))), FormatBytes(int64(len(previous)))))
+	}
+	return warnings, nil
+}
+
+// LooksBinary reports whether content has NUL bytes or is not valid UTF-8,
+// as an image, archive or log in another encoding would
+func LooksBinary(content string) bool {
+	return strings.IndexByte(content, 0) >= 0 || !utf8.ValidString(content)
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPublishCheck(t *testing.T) {
+	large := strings.Repeat("a: 1\n", 300) // 1500 bytes
+	tests := []struct {
+		name         string
+		check        PublishCheck
+		content      string
+		previous     string
+		wantErr      string
+		wantWarnings []string
+	}{
+		{"ordinary update", PublishCheck{MaxSize: 1024}, "a: 2\n", "a: 1\n", "", nil},
+		{"over the limit", PublishCheck{MaxSize: 1024}, large, "", "content is 1.5 KB, over the limit of 1.0 KB", nil},
+		{"over the limit with force", PublishCheck{MaxSize: 1024, Force: true}, large, "", "", nil},
+		{"no limit", PublishCheck{}, large, "", "", nil},
+		{"NUL bytes", PublishCheck{}, "PK\x03\x04\x00\x00", "", "", []string{"looks binary"}},
+		{"invalid UTF-8", PublishCheck{}, "caf\xe9", "", "", []string{"looks binary"}},
+		{"binary allowed", PublishCheck{AllowBinary: true}, "caf\xe9", "", "", nil},
+		{"truncated file", PublishCheck{}, "a: 1\n", large, "", []string{"5 B, less than a tenth of the 1.5 KB"}},
+		{"shrink allowed", PublishCheck{AllowShrink: true}, "a: 1\n", large, "", nil},
+		{"small previous content", PublishCheck{}, "a", strings.Repeat("x", 500), "", nil},
+		{"both warnings", PublishCheck{}, "\x00", large, "", []string{"looks binary", "less than a tenth"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings, err := tt.check.Check(tt.content, tt.previous)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("Check() error = %v, want it to mention %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("Check() warnings = %q, want %d", warnings, len(tt.wantWarnings))
+			}
+			for i, want := range tt.wantWarnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warning %q does not mention %q", warnings[i], want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,81 @@
+package util
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// maxLimitedRead caps a single read through a Limiter, so that one read
+// does not claim a large share of the limit at once
+const maxLimitedRead = 32 * 1024
+
+// Limiter caps the bandwidth of every reader it wraps together: all of them
+// combined read at most its rate, however many run at once. A nil Limiter
+// does not limit.
+type Limiter struct {
+	rate float64 // bytes per second
+
+	mu    sync.Mutex
+	next  time.Time // when the bytes read so far are paid for
+	now   func() time.Time
+	sleep func(time.Duration)
+}
+
+// NewLimiter returns a Limiter of bytesPerSecond, or nil for 0, which
+// does not limit
+func NewLimiter(bytesPerSecond int64) *Limiter {
+	if bytesPerSecond <= 0 {
+		return nil
+	}
+	return &Limiter{rate: float64(bytesPerSecond), now: time.Now, sleep: time.Sleep}
+}
+
+// Rate returns the limit in bytes per second, 0 for none
+func (l *Limiter) Rate() int64 {
+	if l == nil {
+		return 0
+	}
+	return int64(l.rate)
+}
+
+// Wait blocks until n more bytes fit in the limit. It is for data read
+// without Reader, e.g. a response read whole by an HTTP client: the bytes
+// are paid for after the fact, which delays the next read instead.
+func (l *Limiter) Wait(n int) {
+	if l == nil || n <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := l.now()
+	// Time not used in the past is not saved up for a burst
+	if l.next.Before(now) {
+		l.next = now
+	}
+	l.next = l.next.Add(time.Duration(float64(n) / l.rate * float64(time.Second)))
+	delay := l.next.Sub(now)
+	l.mu.Unlock()
+	l.sleep(delay)
+}
+
+// Reader returns r read within the limit, or r itself for a nil Limiter
+func (l *Limiter) Reader(r io.Reader) io.Reader {
+	if l == nil {
+		return r
+	}
+	return &limitedReader{r: r, l: l}
+}
+
+type limitedReader struct {
+	r io.Reader
+	l *Limiter
+}
+
+func (r *limitedReader) Read(p []byte) (int, error) {
+	if len(p) > maxLimitedRead {
+		p = p[:maxLimitedRead]
+	}
+	n, err := r.r.Read(p)
+	r.l.Wait(n)
+	return n, err
+}
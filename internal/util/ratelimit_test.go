@@ -0,0 +1,88 @@
+package util
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock advances only when the limiter sleeps
+type fakeClock struct {
+	mu    sync.Mutex
+	now   time.Time
+	slept time.Duration
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.slept += d
+		c.now = c.now.Add(d)
+	}
+}
+
+func TestLimiterSharedByReaders(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLimiter(100 << 10)
+	l.now, l.sleep = clock.Now, clock.Sleep
+
+	// Four readers of 100KB each at 100KB/s take about 4s together
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			n, err := io.Copy(io.Discard, l.Reader(bytes.NewReader(make([]byte, 100<<10))))
+			if n != 100<<10 || err != nil {
+				t.Errorf("io.Copy() = %d, %v", n, err)
+			}
+		}()
+	}
+	wg.Wait()
+	if clock.slept < 3*time.Second || clock.slept > 5*time.Second {
+		t.Errorf("readers slept %s, want about 4s", clock.slept)
+	}
+}
+
+func TestLimiterWait(t *testing.T) {
+	clock := &fakeClock{now: time.Unix(0, 0)}
+	l := NewLimiter(1000)
+	l.now, l.sleep = clock.Now, clock.Sleep
+	l.Wait(500)
+	l.Wait(500)
+	if clock.slept != time.Second {
+		t.Errorf("Wait() slept %s, want 1s", clock.slept)
+	}
+
+	// Time left unused is not saved up for a burst
+	clock.now = clock.now.Add(time.Minute)
+	clock.slept = 0
+	l.Wait(2000)
+	if clock.slept != 2*time.Second {
+		t.Errorf("Wait() after an idle minute slept %s, want 2s", clock.slept)
+	}
+}
+
+func TestNilLimiter(t *testing.T) {
+	l := NewLimiter(0)
+	if l != nil {
+		t.Fatalf("NewLimiter(0) = %v, want nil", l)
+	}
+	if l.Rate() != 0 {
+		t.Errorf("Rate() = %d, want 0", l.Rate())
+	}
+	l.Wait(1 << 20)
+	r := bytes.NewReader([]byte("data"))
+	if l.Reader(r) != r {
+		t.Error("Reader() of a nil Limiter wrapped the reader")
+	}
+}
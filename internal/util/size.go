@@ -0,0 +1,44 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sizeUnits are the units of FormatBytes and ParseSize, in powers of 1024
+var sizeUnits = []string{"B", "KB", "MB", "GB"}
+
+// FormatBytes shows a size in B, KB, MB or GB (powers of 1024)
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	value, suffix := float64(n)/unit, "KB"
+	for _, next := range sizeUnits[2:] {
+		if value < unit {
+			break
+		}
+		value, suffix = value/unit, next
+	}
+	return fmt.Sprintf("%.1f %s", value, suffix)
+}
+
+// ParseSize parses a size such as 512KB, 1MB or 1048576 (bytes). Units are
+// powers of 1024 and their case does not matter.
+func ParseSize(s string) (int64, error) {
+	upper := strings.ToUpper(strings.TrimSpace(s))
+	number, multiplier := upper, int64(1)
+	for i := len(sizeUnits) - 1; i >= 0; i-- {
+		if strings.HasSuffix(upper, sizeUnits[i]) {
+			number, multiplier = strings.TrimSpace(strings.TrimSuffix(upper, sizeUnits[i])), int64(1)<<(10*i)
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(number, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q: use a number of bytes or a size such as 512KB or 5MB", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
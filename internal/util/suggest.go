@@ -0,0 +1,43 @@
+package util
+
+// Closest returns the candidate nearest to word by edit distance, or "" if
+// none is close enough to be a likely typo
+func Closest(word string, candidates []string) string {
+	best, bestDistance := "", -1
+	for _, candidate := range candidates {
+		d := Levenshtein(word, candidate)
+		if bestDistance < 0 || d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	// Allow two edits (a transposition such as nmae/name counts as two), or a
+	// third of the word for long names, but never replace most of the word
+	limit := max(2, len(word)/3)
+	if best == "" || bestDistance > limit || bestDistance >= len(word) {
+		return ""
+	}
+	return best
+}
+
+// Levenshtein returns the number of single-character insertions, deletions
+// and substitutions needed to turn a into b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(min(prev[j]+1, cur[j-1]+1), prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
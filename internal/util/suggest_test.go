@@ -0,0 +1,42 @@
+package util
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"name", "name", 0},
+		{"nmae", "name", 2},
+		{"nam", "name", 1},
+		{"page", "size", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := Levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("Levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestClosest(t *testing.T) {
+	tests := []struct {
+		word string
+		want string
+	}{
+		{"nmae", "name"},
+		{"sise", "size"},
+		{"cofig-get", "config-get"},
+		{"skil-lsit", "skill-list"},
+		{"xyz", ""},
+		{"ls", ""},
+	}
+	candidates := []string{"name", "page", "size", "config-get", "config-list", "skill-list"}
+	for _, tt := range tests {
+		if got := Closest(tt.word, candidates); got != tt.want {
+			t.Errorf("Closest(%q) = %q, want %q", tt.word, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package util
+
+import (
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDisplayWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  int
+	}{
+		{"ascii", "app.yaml", 8},
+		{"cjk", "数据库配置", 10},
+		{"mixed", "db-配置.yaml", 12},
+		{"fullwidth", "ＡＢ", 4},
+		{"hangul", "설정", 4},
+		{"emoji", "🚀deploy", 8},
+		{"combining mark", "café", 4},
+		{"zero width joiner", "a‍b", 2},
+		{"empty", "", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DisplayWidth(tt.input); got != tt.want {
+				t.Errorf("DisplayWidth(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTruncate(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		width int
+		want  string
+	}{
+		{"fits", "app.yaml", 10, "app.yaml"},
+		{"exact fit", "数据库", 6, "数据库"},
+		{"ascii", "application-production.yaml", 12, "applicati..."},
+		{"cjk", "数据库连接池配置", 9, "数据库..."},
+		// A wide rune that would straddle the limit is left out entirely
+		{"cjk odd width", "数据库连接池配置", 10, "数据库..."},
+		{"mixed", "db-数据库配置.yaml", 10, "db-数据..."},
+		{"emoji", "🚀🚀🚀🚀", 7, "🚀🚀..."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Truncate(tt.input, tt.width, "...")
+			if got != tt.want {
+				t.Errorf("Truncate(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+			}
+			if !utf8.ValidString(got) || DisplayWidth(got) > tt.width {
+				t.Errorf("Truncate(%q, %d) = %q is %d columns or invalid UTF-8", tt.input, tt.width, got, DisplayWidth(got))
+			}
+		})
+	}
+}
+
+func TestPadRight(t *testing.T) {
+	tests := []struct {
+		input string
+		width int
+		want  string
+	}{
+		{"app", 6, "app   "},
+		{"配置", 6, "配置  "},
+		{"🚀x", 6, "🚀x   "},
+		{"too long", 4, "too long"},
+	}
+	for _, tt := range tests {
+		if got := PadRight(tt.input, tt.width); got != tt.want {
+			t.Errorf("PadRight(%q, %d) = %q, want %q", tt.input, tt.width, got, tt.want)
+		}
+	}
+}